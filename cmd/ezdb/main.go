@@ -6,21 +6,44 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/nhath/ezdb/internal/audit"
 	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/connect"
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/demo"
 	"github.com/nhath/ezdb/internal/history"
+	"github.com/nhath/ezdb/internal/session"
 	"github.com/nhath/ezdb/internal/ui"
-	"github.com/nhath/ezdb/internal/ui/components/table"
 	"github.com/nhath/ezdb/internal/ui/styles"
 )
 
 func main() {
 	// Parse flags
 	debug := flag.Bool("debug", false, "Enable debug logging to debug.log")
+	check := flag.Bool("check", false, "Ping every profile concurrently and report reachability, then exit")
+	checkTimeout := flag.Duration("check-timeout", 10*time.Second, "Per-profile timeout for -check")
+	profileStartup := flag.Bool("profile-startup", false, "Report time spent in each startup phase on exit")
+	auditExport := flag.String("audit-export", "", "Export the audit log to a .csv or .jsonl file (- for JSON lines on stdout), then exit")
+	auditProfile := flag.String("audit-profile", "", "Only export audit records of this profile")
+	historyExport := flag.String("history-export", "", "Export query history to a .jsonl or .sql file (- for JSON lines on stdout), then exit")
+	historyImport := flag.String("history-import", "", "Import query history from a .jsonl or .sql export, skipping entries already present, then exit")
+	historyProfile := flag.String("history-profile", "", "Only export history of this profile")
+	historySince := flag.String("history-since", "", "Only export history executed on or after this date (YYYY-MM-DD)")
+	historyMatch := flag.String("history-match", "", "Only export queries containing this text")
+	demoMode := flag.Bool("demo", false, "Explore a temporary sample SQLite database; it and its history are removed on exit")
 	flag.Parse()
 
+	var startup *startupProfile
+	if *profileStartup {
+		startup = newStartupProfile()
+	}
+
 	// Setup logging if debug enabled
 	if *debug {
 		f, err := tea.LogToFile("debug.log", "debug")
@@ -31,6 +54,7 @@ func main() {
 		defer f.Close()
 		log.SetOutput(f) // Redirect standard log to the same file
 	}
+	startup.mark("flags and logging")
 
 	// Load configuration
 	cfg, err := config.Load()
@@ -38,25 +62,106 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
 		os.Exit(1)
 	}
+	startup.mark("config load")
+
+	// Health check mode: no TUI
+	if *check {
+		os.Exit(runHealthCheck(cfg, *checkTimeout))
+	}
+
+	if *auditExport != "" {
+		os.Exit(runAuditExport(cfg, *auditExport, *auditProfile))
+	}
+
+	if *historyExport != "" || *historyImport != "" {
+		filter := history.ExportFilter{ProfileName: *historyProfile, Contains: *historyMatch}
+		if *historySince != "" {
+			since, err := time.ParseInLocation("2006-01-02", *historySince, time.Local)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid -history-since %q, want YYYY-MM-DD\n", *historySince)
+				os.Exit(1)
+			}
+			filter.Since = since
+		}
+		os.Exit(runHistoryTransfer(*historyExport, *historyImport, filter))
+	}
+
+	// Follow the terminal background in auto theme mode; the UI applies the theme
+	if cfg.ThemeMode == "auto" {
+		cfg.ApplyAutoTheme(styles.DetectBackground())
+		startup.mark("background detect")
+	}
 
-	// Initialize UI styles
-	styles.Init(cfg.Theme)
-	table.Init(cfg.Theme, cfg.Keys)
+	// Demo mode keeps its database and history in a directory removed on exit
+	var demoDir string
+	if *demoMode {
+		demoDir, err = os.MkdirTemp("", "ezdb-demo-")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create demo directory: %v\n", err)
+			os.Exit(1)
+		}
+		defer os.RemoveAll(demoDir)
+	}
 
 	// Initialize history store
-	historyStore, err := history.NewStore()
+	var historyStore *history.Store
+	if *demoMode {
+		historyStore, err = history.OpenStore(filepath.Join(demoDir, "history.db"))
+	} else {
+		historyStore, err = history.NewStore()
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize history: %v\n", err)
 		os.Exit(1)
 	}
 	defer historyStore.Close()
+	startup.mark("history open")
+
+	var auditLog audit.Log
+	if cfg.Audit.Target != "" {
+		auditLog, err = audit.Open(cfg.Audit.Target, cfg.Audit.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open audit log: %v\n", err)
+			os.Exit(1)
+		}
+		defer auditLog.Close()
+	}
 
 	// Create TUI with profile selector (no pre-connection)
 	// The TUI will handle profile selection and connection
-	model := ui.NewModel(cfg, nil, nil, historyStore)
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	var model ui.Model
+	if *demoMode {
+		profile, driver, err := openDemo(demoDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open demo database: %v\n", err)
+			os.RemoveAll(demoDir)
+			os.Exit(1)
+		}
+		defer driver.Close()
+		model = ui.NewModel(cfg, profile, driver, historyStore)
+	} else {
+		model = ui.NewModel(cfg, nil, nil, historyStore)
+		// A missing or unreadable session only loses the restore offer
+		if path, err := session.DefaultPath(); err == nil {
+			prev, _ := session.Load(path)
+			model = model.WithSession(path, prev)
+		}
+	}
+	if auditLog != nil {
+		model = model.WithAuditLog(auditLog)
+	}
+	startup.mark("model build")
+	if startup != nil {
+		model = model.WithFirstRenderHook(func() { startup.mark("first render") })
+	}
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if !cfg.DisableMouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(model, opts...)
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
 		os.Exit(1)
 	}
@@ -64,4 +169,155 @@ func main() {
 	// Clear any leftover output from pagers (pspg, less, etc.)
 	// by printing a clear screen sequence
 	fmt.Print("\033[H\033[2J")
+
+	// Results exported to "-" go to stdout once the TUI is gone
+	if m, ok := final.(ui.Model); ok {
+		fmt.Print(m.StdoutExport())
+	}
+
+	if startup != nil {
+		startup.report(os.Stderr)
+		if *debug {
+			startup.report(log.Writer())
+		}
+	}
+}
+
+// openDemo creates the sample database in dir and connects to it. The demo
+// profile is not added to the config, so it is never saved.
+func openDemo(dir string) (*config.Profile, db.Driver, error) {
+	path, err := demo.Create(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	profile := &config.Profile{Name: "demo", Type: "sqlite", Database: path, Label: "DEMO"}
+	driver, err := connect.Open(profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return profile, driver, nil
+}
+
+// runHealthCheck checks all profiles and returns the process exit code
+func runHealthCheck(cfg *config.Config, timeout time.Duration) int {
+	if len(cfg.Profiles) == 0 {
+		fmt.Fprintln(os.Stderr, "No profiles configured")
+		return 1
+	}
+
+	results := connect.CheckAll(cfg.Profiles, timeout)
+	if err := connect.WriteHealthTable(os.Stdout, results); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write results: %v\n", err)
+		return 1
+	}
+
+	for _, r := range results {
+		if !r.OK() {
+			return 1
+		}
+	}
+	return 0
+}
+
+// runAuditExport writes the configured audit log to path and returns the process exit code
+func runAuditExport(cfg *config.Config, path, profile string) int {
+	if cfg.Audit.Target == "" {
+		fmt.Fprintln(os.Stderr, "No audit log configured (set [audit] target)")
+		return 1
+	}
+	auditLog, err := audit.Open(cfg.Audit.Target, cfg.Audit.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open audit log: %v\n", err)
+		return 1
+	}
+	defer auditLog.Close()
+	records, err := auditLog.Records(profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read audit log: %v\n", err)
+		return 1
+	}
+
+	out := os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create %s: %v\n", path, err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+	write := audit.WriteJSONLines
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		write = audit.WriteCSV
+	}
+	if err := write(out, records); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to export audit log: %v\n", err)
+		return 1
+	}
+	if path != "-" {
+		fmt.Printf("Exported %d audit records to %s\n", len(records), path)
+	}
+	return 0
+}
+
+// runHistoryTransfer exports history matching filter to exportPath and/or
+// imports the export at importPath, returning the process exit code
+func runHistoryTransfer(exportPath, importPath string, filter history.ExportFilter) int {
+	store, err := history.NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open history: %v\n", err)
+		return 1
+	}
+	defer store.Close()
+
+	if importPath != "" {
+		f, err := os.Open(importPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open %s: %v\n", importPath, err)
+			return 1
+		}
+		defer f.Close()
+		load := store.ImportJSONLines
+		if strings.EqualFold(filepath.Ext(importPath), ".sql") {
+			load = store.ImportSQL
+		}
+		added, err := load(f)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to import %s: %v\n", importPath, err)
+			return 1
+		}
+		fmt.Printf("Imported %d history entries from %s\n", added, importPath)
+	}
+
+	if exportPath == "" {
+		return 0
+	}
+	entries, err := store.Export(filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read history: %v\n", err)
+		return 1
+	}
+	out := os.Stdout
+	if exportPath != "-" {
+		f, err := os.Create(exportPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create %s: %v\n", exportPath, err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+	write := history.WriteJSONLines
+	if strings.EqualFold(filepath.Ext(exportPath), ".sql") {
+		write = history.WriteSQL
+	}
+	if err := write(out, entries); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to export history: %v\n", err)
+		return 1
+	}
+	if exportPath != "-" {
+		fmt.Printf("Exported %d history entries to %s\n", len(entries), exportPath)
+	}
+	return 0
 }
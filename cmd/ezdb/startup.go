@@ -0,0 +1,57 @@
+// cmd/ezdb/startup.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// startupProfile records how long each startup phase takes.
+// A nil profile ignores marks, so call sites need no flag checks.
+type startupProfile struct {
+	mu     sync.Mutex
+	start  time.Time
+	last   time.Time
+	phases []startupPhase
+}
+
+type startupPhase struct {
+	name string
+	took time.Duration
+}
+
+func newStartupProfile() *startupProfile {
+	now := time.Now()
+	return &startupProfile{start: now, last: now}
+}
+
+// mark closes the current phase under name
+func (p *startupProfile) mark(name string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	p.phases = append(p.phases, startupPhase{name: name, took: now.Sub(p.last)})
+	p.last = now
+}
+
+// report prints each phase and the total time to the last mark
+func (p *startupProfile) report(w io.Writer) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PHASE\tTIME")
+	for _, ph := range p.phases {
+		fmt.Fprintf(tw, "%s\t%s\n", ph.name, ph.took.Round(time.Microsecond))
+	}
+	fmt.Fprintf(tw, "total\t%s\n", p.last.Sub(p.start).Round(time.Microsecond))
+	tw.Flush()
+}
@@ -0,0 +1,216 @@
+// internal/assistant/assistant.go
+// Package assistant asks a language model to write SQL for a plain-language request or to fix a failed query, sending it the schema but never result rows.
+package assistant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// maxSchemaBytes caps the schema description sent with a request; tables
+// past it are left out
+const maxSchemaBytes = 48 << 10
+
+// Client talks to an OpenAI-compatible or Ollama chat endpoint
+type Client struct {
+	Provider string
+	Endpoint string
+	Model    string
+	APIKey   string
+	HTTP     *http.Client
+}
+
+// New returns a client for the configured assistant, filling in the
+// provider's default endpoint, model and API key variable
+func New(cfg config.Assistant) Client {
+	c := Client{Provider: cfg.Provider, Endpoint: cfg.Endpoint, Model: cfg.Model, HTTP: http.DefaultClient}
+	if c.Provider == "" {
+		c.Provider = config.AssistantOpenAI
+	}
+	keyEnv := cfg.APIKeyEnv
+	if c.Provider == config.AssistantOllama {
+		c.Endpoint = orDefault(c.Endpoint, "http://localhost:11434")
+		c.Model = orDefault(c.Model, "llama3.1")
+	} else {
+		c.Endpoint = orDefault(c.Endpoint, "https://api.openai.com/v1")
+		c.Model = orDefault(c.Model, "gpt-4o-mini")
+		keyEnv = orDefault(keyEnv, "OPENAI_API_KEY")
+	}
+	if keyEnv != "" {
+		c.APIKey = os.Getenv(keyEnv)
+	}
+	c.Endpoint = strings.TrimRight(c.Endpoint, "/")
+	return c
+}
+
+// orDefault returns s, or def when s is empty
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// Schema is what the model is told about the database: table and column
+// names, types and keys
+type Schema struct {
+	Dialect string // Driver type, e.g. "postgres"
+	Tables  []string
+	Columns map[string][]db.Column
+}
+
+// Describe lays the schema out one table per line, e.g.
+// "orders(id integer PK, customer_id integer, total numeric)"
+func (s Schema) Describe() string {
+	tables := append([]string(nil), s.Tables...)
+	sort.Strings(tables)
+	var b strings.Builder
+	for i, table := range tables {
+		cols := make([]string, len(s.Columns[table]))
+		for j, c := range s.Columns[table] {
+			cols[j] = strings.TrimSpace(c.Name + " " + c.Type)
+			if c.Key == "PRI" {
+				cols[j] += " PK"
+			}
+		}
+		line := table + "(" + strings.Join(cols, ", ") + ")\n"
+		if b.Len()+len(line) > maxSchemaBytes {
+			fmt.Fprintf(&b, "(%d more tables not listed)\n", len(tables)-i)
+			break
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// systemPrompt tells the model what to write and about which database
+func systemPrompt(schema Schema) string {
+	return fmt.Sprintf("You write SQL for a %s database. Answer with one SQL statement only, "+
+		"no explanation and no markdown. Use only these tables and columns:\n\n%s", schema.Dialect, schema.Describe())
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// fixPrompt tells the model to explain a failed query and correct it
+func fixPrompt(schema Schema) string {
+	return fmt.Sprintf("You help fix failed SQL on a %s database. In at most three sentences, say why the query failed, "+
+		"then give the corrected statement in one ```sql code block. Use only these tables and columns:\n\n%s", schema.Dialect, schema.Describe())
+}
+
+// Generate asks the model for SQL answering request against schema
+func (c Client) Generate(ctx context.Context, request string, schema Schema) (string, error) {
+	content, err := c.chat(ctx, systemPrompt(schema), request)
+	if err != nil {
+		return "", err
+	}
+	sql := ExtractSQL(content)
+	if sql == "" {
+		return "", fmt.Errorf("assistant: the reply held no SQL")
+	}
+	return sql, nil
+}
+
+// SuggestFix asks the model why query failed with errMsg and how to fix
+// it. The reply is prose, usually with the corrected SQL in a code block.
+func (c Client) SuggestFix(ctx context.Context, query, errMsg string, schema Schema) (string, error) {
+	content, err := c.chat(ctx, fixPrompt(schema), "Query:\n"+query+"\n\nError:\n"+errMsg)
+	if err != nil {
+		return "", err
+	}
+	if content = strings.TrimSpace(content); content == "" {
+		return "", fmt.Errorf("assistant: the reply was empty")
+	}
+	return content, nil
+}
+
+// chat sends one system and one user message and returns the reply
+func (c Client) chat(ctx context.Context, system, user string) (string, error) {
+	messages := []message{{Role: "system", Content: system}, {Role: "user", Content: user}}
+
+	url := c.Endpoint + "/chat/completions"
+	payload := map[string]any{"model": c.Model, "messages": messages, "temperature": 0}
+	if c.Provider == config.AssistantOllama {
+		url = c.Endpoint + "/api/chat"
+		payload = map[string]any{"model": c.Model, "messages": messages, "stream": false, "options": map[string]any{"temperature": 0}}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("assistant: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("assistant: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// OpenAI answers in choices with errors as objects, Ollama in message
+	// with errors as strings
+	var reply struct {
+		Choices []struct {
+			Message message `json:"message"`
+		} `json:"choices"`
+		Message message         `json:"message"`
+		Error   json.RawMessage `json:"error"`
+	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&reply)
+	if resp.StatusCode != http.StatusOK || (len(reply.Error) > 0 && string(reply.Error) != "null") {
+		return "", fmt.Errorf("assistant: %s %s", resp.Status, errorText(reply.Error))
+	}
+	if decodeErr != nil {
+		return "", fmt.Errorf("assistant: %w", decodeErr)
+	}
+	if len(reply.Choices) > 0 {
+		return reply.Choices[0].Message.Content, nil
+	}
+	return reply.Message.Content, nil
+}
+
+// errorText returns the message of an error field, an object with a
+// message or a plain string
+func errorText(raw json.RawMessage) string {
+	var obj struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(raw, &obj) == nil && obj.Message != "" {
+		return obj.Message
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return ""
+}
+
+// ExtractSQL returns the SQL of a reply: the first fenced code block when
+// the model wrote one anyway, else the whole reply
+func ExtractSQL(reply string) string {
+	if _, rest, ok := strings.Cut(reply, "```"); ok {
+		// Drop a language tag such as ```sql
+		if nl := strings.IndexByte(rest, '\n'); nl >= 0 && !strings.ContainsAny(rest[:nl], " \t") {
+			rest = rest[nl+1:]
+		}
+		block, _, _ := strings.Cut(rest, "```")
+		return strings.TrimSpace(block)
+	}
+	return strings.TrimSpace(reply)
+}
@@ -0,0 +1,81 @@
+// internal/assistant/assistant_test.go
+package assistant
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/db"
+)
+
+func TestGenerate(t *testing.T) {
+	var sent struct {
+		Model    string    `json:"model"`
+		Messages []message `json:"messages"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		switch r.URL.Path {
+		case "/v1/chat/completions":
+			if r.Header.Get("Authorization") != "Bearer key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":{"message":"bad key"}}`))
+				return
+			}
+			w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"SELECT * FROM orders"}}]}`))
+		case "/api/chat":
+			w.Write([]byte(`{"message":{"role":"assistant","content":"` + "```sql\\nSELECT id FROM orders;\\n```" + `"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	schema := Schema{Dialect: "postgres", Tables: []string{"orders"},
+		Columns: map[string][]db.Column{"orders": {{Name: "id", Type: "integer", Key: "PRI"}, {Name: "total", Type: "numeric"}}}}
+
+	t.Setenv("TEST_ASSISTANT_KEY", "key")
+	openai := New(config.Assistant{Endpoint: srv.URL + "/v1/", APIKeyEnv: "TEST_ASSISTANT_KEY"})
+	if got, err := openai.Generate(context.Background(), "all orders", schema); err != nil || got != "SELECT * FROM orders" {
+		t.Errorf("openai: %q, %v", got, err)
+	}
+	if sent.Model != "gpt-4o-mini" || len(sent.Messages) != 2 || sent.Messages[1].Content != "all orders" ||
+		!strings.Contains(sent.Messages[0].Content, "orders(id integer PK, total numeric)") {
+		t.Errorf("openai request: %+v", sent)
+	}
+
+	ollama := New(config.Assistant{Provider: config.AssistantOllama, Endpoint: srv.URL, Model: "sqlcoder"})
+	if got, err := ollama.Generate(context.Background(), "order ids", schema); err != nil || got != "SELECT id FROM orders;" {
+		t.Errorf("ollama: %q, %v", got, err)
+	}
+
+	openai.APIKey = "wrong"
+	if _, err := openai.Generate(context.Background(), "all orders", schema); err == nil || !strings.Contains(err.Error(), "bad key") {
+		t.Errorf("rejected key gave %v", err)
+	}
+}
+
+func TestSuggestFix(t *testing.T) {
+	var sent struct {
+		Messages []message `json:"messages"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Write([]byte(`{"message":{"content":"The table is orders.\n` + "```sql\\nSELECT * FROM orders\\n```" + `"}}`))
+	}))
+	defer srv.Close()
+
+	c := New(config.Assistant{Provider: config.AssistantOllama, Endpoint: srv.URL})
+	got, err := c.SuggestFix(context.Background(), "SELECT * FROM order", "no such table: order", Schema{Dialect: "sqlite", Tables: []string{"orders"}})
+	if err != nil || ExtractSQL(got) != "SELECT * FROM orders" || !strings.HasPrefix(got, "The table is orders.") {
+		t.Errorf("SuggestFix = %q, %v", got, err)
+	}
+	if user := sent.Messages[1].Content; !strings.Contains(user, "SELECT * FROM order") || !strings.Contains(user, "no such table: order") {
+		t.Errorf("user message = %q", user)
+	}
+}
@@ -0,0 +1,73 @@
+// internal/audit/audit.go
+package audit
+
+import (
+	"fmt"
+	"os/user"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+// Audit log targets
+const (
+	TargetFile   = "file"   // JSON lines appended to a file
+	TargetSQLite = "sqlite" // Rows in a SQLite table that refuses updates and deletes
+)
+
+// Record is one executed statement
+type Record struct {
+	Time       time.Time `json:"time"`
+	Profile    string    `json:"profile"`
+	OSUser     string    `json:"os_user"`
+	DBUser     string    `json:"db_user,omitempty"`
+	Database   string    `json:"database,omitempty"`
+	Statement  string    `json:"statement"`
+	DurationMs int64     `json:"duration_ms"`
+	Rows       int64     `json:"rows"`
+	Status     string    `json:"status"` // "success", "error"
+	Error      string    `json:"error,omitempty"`
+}
+
+// Log is an append-only store of executed statements, safe for concurrent use
+type Log interface {
+	Append(r Record) error
+	// Records returns the entries of a profile, or of all profiles when
+	// profile is empty, oldest first
+	Records(profile string) ([]Record, error)
+	Close() error
+}
+
+// Open opens the audit log for target at path, or at the default path under
+// the data directory when path is empty
+func Open(target, path string) (Log, error) {
+	var err error
+	switch target {
+	case TargetFile:
+		if path == "" {
+			path, err = xdg.DataFile("ezdb/audit.log")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return openFile(path)
+	case TargetSQLite:
+		if path == "" {
+			path, err = xdg.DataFile("ezdb/audit.db")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return openSQLite(path)
+	}
+	return nil, fmt.Errorf("unknown audit target %q (want %q or %q)", target, TargetFile, TargetSQLite)
+}
+
+// CurrentUser is the operating system user recorded with each statement
+func CurrentUser() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
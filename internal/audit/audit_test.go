@@ -0,0 +1,71 @@
+// internal/audit/audit_test.go
+package audit
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogTargets(t *testing.T) {
+	for _, target := range []string{TargetFile, TargetSQLite} {
+		t.Run(target, func(t *testing.T) {
+			log, err := Open(target, filepath.Join(t.TempDir(), "audit"))
+			if err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			defer log.Close()
+
+			at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			records := []Record{
+				{Time: at, Profile: "prod", OSUser: "ann", DBUser: "app", Statement: "DELETE FROM t WHERE id = 1", DurationMs: 3, Rows: 1, Status: "success"},
+				{Time: at, Profile: "dev", OSUser: "ann", Statement: "SELECT x", Status: "error", Error: "no such column: x"},
+			}
+			for _, r := range records {
+				if err := log.Append(r); err != nil {
+					t.Fatalf("append: %v", err)
+				}
+			}
+
+			all, err := log.Records("")
+			if err != nil || len(all) != 2 {
+				t.Fatalf("all records = %v, %v", all, err)
+			}
+			prod, err := log.Records("prod")
+			if err != nil || len(prod) != 1 || prod[0].Statement != records[0].Statement || prod[0].Rows != 1 || !prod[0].Time.Equal(at) {
+				t.Fatalf("prod records = %+v, %v", prod, err)
+			}
+		})
+	}
+}
+
+func TestSQLiteLogIsAppendOnly(t *testing.T) {
+	log, err := openSQLite(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer log.Close()
+	if err := log.Append(Record{Time: time.Now(), Profile: "prod", Statement: "SELECT 1", Status: "success"}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if _, err := log.db.Exec("DELETE FROM audit_log"); err == nil {
+		t.Error("delete succeeded, want the trigger to abort it")
+	}
+	if _, err := log.db.Exec("UPDATE audit_log SET statement = ''"); err == nil {
+		t.Error("update succeeded, want the trigger to abort it")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	r := Record{Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Profile: "prod", Statement: "SELECT 'a,b'", Rows: 2, Status: "success"}
+	if err := WriteCSV(&buf, []Record{r}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || lines[1] != `2026-01-02T03:04:05Z,prod,,,,"SELECT 'a,b'",0,2,success,` {
+		t.Errorf("csv = %q", buf.String())
+	}
+}
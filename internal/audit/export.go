@@ -0,0 +1,40 @@
+// internal/audit/export.go
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteCSV writes records as CSV with a header row
+func WriteCSV(w io.Writer, records []Record) error {
+	cw := csv.NewWriter(w)
+	header := []string{"time", "profile", "os_user", "db_user", "database", "statement", "duration_ms", "rows", "status", "error"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := cw.Write([]string{
+			r.Time.Format(time.RFC3339Nano), r.Profile, r.OSUser, r.DBUser, r.Database, r.Statement,
+			strconv.FormatInt(r.DurationMs, 10), strconv.FormatInt(r.Rows, 10), r.Status, r.Error,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSONLines writes one JSON object per record
+func WriteJSONLines(w io.Writer, records []Record) error {
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
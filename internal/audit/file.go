@@ -0,0 +1,64 @@
+// internal/audit/file.go
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// fileLog appends records as JSON lines
+type fileLog struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+}
+
+func openFile(path string) (*fileLog, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &fileLog{path: path, f: f}, nil
+}
+
+// Append writes the record as one line and syncs it to disk
+func (l *fileLog) Append(r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return l.f.Sync()
+}
+
+func (l *fileLog) Records(profile string) ([]Record, error) {
+	f, err := os.Open(l.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, err
+		}
+		if profile == "" || r.Profile == profile {
+			records = append(records, r)
+		}
+	}
+	return records, scanner.Err()
+}
+
+func (l *fileLog) Close() error {
+	return l.f.Close()
+}
@@ -0,0 +1,87 @@
+// internal/audit/sqlite.go
+package audit
+
+import (
+	"database/sql"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteLog stores records in a table whose triggers reject updates and deletes
+type sqliteLog struct {
+	db *sql.DB
+}
+
+func openSQLite(path string) (*sqliteLog, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec("PRAGMA busy_timeout = 5000"); err != nil {
+		db.Close()
+		return nil, err
+	}
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			executed_at TIMESTAMP NOT NULL,
+			profile_name TEXT NOT NULL,
+			os_user TEXT NOT NULL,
+			db_user TEXT,
+			database_name TEXT,
+			statement TEXT NOT NULL,
+			duration_ms INTEGER NOT NULL,
+			row_count INTEGER NOT NULL,
+			status TEXT NOT NULL,
+			error_message TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_audit_profile ON audit_log(profile_name);
+		CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+		BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END;
+		CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+		BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END;
+	`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqliteLog{db: db}, nil
+}
+
+func (l *sqliteLog) Append(r Record) error {
+	_, err := l.db.Exec(`
+		INSERT INTO audit_log (executed_at, profile_name, os_user, db_user, database_name, statement, duration_ms, row_count, status, error_message)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, r.Time, r.Profile, r.OSUser, r.DBUser, r.Database, r.Statement, r.DurationMs, r.Rows, r.Status, r.Error)
+	return err
+}
+
+func (l *sqliteLog) Records(profile string) ([]Record, error) {
+	rows, err := l.db.Query(`
+		SELECT executed_at, profile_name, os_user, db_user, database_name, statement, duration_ms, row_count, status, error_message
+		FROM audit_log
+		WHERE ? = '' OR profile_name = ?
+		ORDER BY id
+	`, profile, profile)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var r Record
+		var dbUser, database, errMsg sql.NullString
+		if err := rows.Scan(&r.Time, &r.Profile, &r.OSUser, &dbUser, &database, &r.Statement,
+			&r.DurationMs, &r.Rows, &r.Status, &errMsg); err != nil {
+			return nil, err
+		}
+		r.DBUser, r.Database, r.Error = dbUser.String, database.String, errMsg.String
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+func (l *sqliteLog) Close() error {
+	return l.db.Close()
+}
@@ -4,15 +4,37 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/adrg/xdg"
 )
 
-// QueryTemplate defines a predefined query with <table> placeholder
+// QueryTemplate defines a predefined query with <table> placeholder.
+// ${1:text} tab-stops are filled in with tab and shift+tab once inserted.
 type QueryTemplate struct {
-	Name  string `toml:"name"`
-	Query string `toml:"query"`
+	Name     string            `toml:"name"`
+	Query    string            `toml:"query"`
+	Dialects map[string]string `toml:"dialects,omitempty"` // Driver type -> query used instead of Query
+}
+
+// For returns the template as run on a driver type, its dialect query
+// replacing Query when it has one
+func (t QueryTemplate) For(driverType string) QueryTemplate {
+	if q, ok := t.Dialects[driverType]; ok {
+		t.Query = q
+	}
+	return t
+}
+
+// describeTemplate is the default DESCRIBE, which only MySQL understands as written
+func describeTemplate() QueryTemplate {
+	return QueryTemplate{Name: "DESCRIBE", Query: "DESCRIBE <table>", Dialects: map[string]string{
+		"postgres": "SELECT column_name, data_type, is_nullable, column_default FROM information_schema.columns " +
+			"WHERE table_schema || '.' || table_name = '<table>' ORDER BY ordinal_position",
+		"sqlite": "SELECT * FROM pragma_table_info('<table>')",
+	}}
 }
 
 // Config represents the application configuration
@@ -26,6 +48,204 @@ type Config struct {
 	Theme              Theme           `toml:"theme_colors"`
 	Keys               KeyMap          `toml:"keys"`
 	QueryTemplates     []QueryTemplate `toml:"query_templates"`
+	// PagerFormat is "aligned" (default, psql-style) or "csv" for the file handed to the pager
+	PagerFormat string `toml:"pager_format,omitempty"`
+	// PagerDelimiter separates csv pager fields, "," by default
+	PagerDelimiter string `toml:"pager_delimiter,omitempty"`
+	// DisableMouse turns off mouse capture, keeping the terminal's own text selection
+	DisableMouse bool `toml:"disable_mouse,omitempty"`
+	// Clipboard is "auto" (default: pbcopy, wl-copy, xclip, xsel or clip.exe,
+	// OSC 52 over SSH or without one), "local" or "osc52"
+	Clipboard string `toml:"clipboard,omitempty"`
+	// SchemaLayout is "overlay" (default) or "sidebar" to dock the schema browser
+	SchemaLayout string `toml:"schema_layout,omitempty"`
+	// SidebarRatio is the docked sidebar's share of the screen width
+	SidebarRatio float64 `toml:"sidebar_ratio,omitempty"`
+	// StatusFormat lays out the status bar from {segment} placeholders and
+	// literal text, e.g. "{mode}{profile}{db}{tx}{rows}{clock}"; empty uses
+	// the default layout
+	StatusFormat string `toml:"status_format,omitempty"`
+	// ResultsLayout is "popup" (default) or "dock" to show SELECT results below the editor
+	ResultsLayout string `toml:"results_layout,omitempty"`
+	// ThemeMode is "manual" (default, use theme_name) or "auto" to follow the terminal background
+	ThemeMode string `toml:"theme_mode,omitempty"`
+	// LightTheme and DarkTheme are the pair picked from when theme_mode is "auto"
+	LightTheme string `toml:"light_theme,omitempty"`
+	DarkTheme  string `toml:"dark_theme,omitempty"`
+	// UndoDepth caps the editor's undo snapshots per profile, 0 for unlimited
+	UndoDepth int `toml:"undo_depth,omitempty"`
+	// DisableDryRun stops checking the editor's SQL by preparing it on the server while typing
+	DisableDryRun bool `toml:"disable_dry_run,omitempty"`
+	// HistoryResults keeps whole result sets with history entries; profiles may override it
+	HistoryResults HistoryResults `toml:"history_results,omitempty"`
+	// ResultMemoryMB caps the rows of a query result held in memory; rows past
+	// it spill to a temporary file. 0 uses the default, negative never spills.
+	ResultMemoryMB int `toml:"result_memory_mb,omitempty"`
+	// PlanTiming plans each PostgreSQL SELECT again with EXPLAIN (SUMMARY) to
+	// show its planning time with the other phases in the results header
+	PlanTiming bool `toml:"plan_timing,omitempty"`
+	// Audit appends every executed statement to a log kept apart from history
+	Audit AuditLog `toml:"audit,omitempty"`
+	// RecentFiles are the SQL files last opened or saved, newest first
+	RecentFiles []string `toml:"recent_files,omitempty"`
+	// ImportNulls are the CSV values imported as NULL, "", "NULL" and \N by default
+	ImportNulls []string `toml:"import_nulls,omitempty"`
+	// ProtectProduction connects profiles labeled PROD or PRODUCTION in
+	// strict mode, which cannot be turned off, and read-only
+	ProtectProduction bool `toml:"protect_production,omitempty"`
+	// Timestamps lays out the times of history entries
+	Timestamps Timestamps `toml:"timestamps,omitempty"`
+	// NumberFormat groups the thousands of numeric result cells
+	NumberFormat NumberFormat `toml:"number_format,omitempty"`
+	// FrozenColumns keeps the first columns of results in view while
+	// scrolling sideways. 0 freezes the primary key columns a result starts
+	// with, or its first column; -1 freezes none.
+	FrozenColumns int `toml:"frozen_columns,omitempty"`
+	// Assistant writes SQL from plain-language requests; off unless enabled
+	Assistant Assistant `toml:"assistant,omitempty"`
+}
+
+// defaultImportNulls apply when import_nulls is not set
+var defaultImportNulls = []string{"", "NULL", `\N`}
+
+// NullSentinels returns the CSV values imported as NULL
+func (c *Config) NullSentinels() []string {
+	if len(c.ImportNulls) > 0 {
+		return c.ImportNulls
+	}
+	return defaultImportNulls
+}
+
+// HistoryResults caps the result sets stored with history entries
+type HistoryResults struct {
+	// MaxKB skips results larger than this once encoded, 0 stores none
+	MaxKB int `toml:"max_kb,omitempty"`
+	// BudgetMB caps a profile's stored results, evicting the oldest past it
+	BudgetMB int `toml:"budget_mb,omitempty"`
+}
+
+// defaultResultBudgetMB applies when results are stored without a budget
+const defaultResultBudgetMB = 50
+
+// ResultLimits returns the per-result and per-profile byte caps of stored
+// history results for profile p; maxBytes is 0 when results are not stored
+func (c *Config) ResultLimits(p *Profile) (maxBytes, budgetBytes int64) {
+	limits := c.HistoryResults
+	if p != nil && p.HistoryResults != nil {
+		limits = *p.HistoryResults
+	}
+	if limits.MaxKB <= 0 {
+		return 0, 0
+	}
+	budget := limits.BudgetMB
+	if budget <= 0 {
+		budget = defaultResultBudgetMB
+	}
+	return int64(limits.MaxKB) << 10, int64(budget) << 20
+}
+
+// Timestamps lays out the times history entries ran at
+type Timestamps struct {
+	// Format is the Go layout of times from today, "15:04:05" by default
+	Format string `toml:"format,omitempty"`
+	// DateFormat is the layout of older times, "2006-01-02 15:04" by default
+	DateFormat string `toml:"date_format,omitempty"`
+	// Absolute shows DateFormat for the past week too, instead of
+	// "yesterday 14:03" and "Mon 14:03"
+	Absolute bool `toml:"absolute,omitempty"`
+	// Timezone is an IANA name such as "UTC" or "Europe/Berlin", "session"
+	// for the connected database session's, or empty for the local one
+	Timezone string `toml:"timezone,omitempty"`
+}
+
+// NumberFormat lays out numeric cells in the results view; exports, copies
+// and row actions keep the values as the database returned them
+type NumberFormat struct {
+	// Enabled formats numbers when results open; the results popup toggles it
+	Enabled bool `toml:"enabled,omitempty"`
+	// Decimals fixes the digits after the decimal point of fractional
+	// values, 0 keeps each value's own
+	Decimals int `toml:"decimals,omitempty"`
+	// Locale picks the separators, e.g. "de-DE"; empty reads LC_ALL,
+	// LC_NUMERIC or LANG
+	Locale string `toml:"locale,omitempty"`
+}
+
+// Assistant providers
+const (
+	AssistantOpenAI = "openai" // Any OpenAI-compatible chat completions API
+	AssistantOllama = "ollama"
+)
+
+// Assistant is a language model that turns a request such as "orders of
+// the last week" into SQL, or suggests a fix for a failed query. It is sent
+// the request, or the failed query and its error message, and the cached
+// schema's table and column names and types, never rows.
+type Assistant struct {
+	// Enabled opts in to the assistant
+	Enabled bool `toml:"enabled,omitempty"`
+	// Provider is "openai" (default) or "ollama"
+	Provider string `toml:"provider,omitempty"`
+	// Endpoint is the API base URL, by default https://api.openai.com/v1
+	// or http://localhost:11434 for ollama
+	Endpoint string `toml:"endpoint,omitempty"`
+	// Model names the model, e.g. "gpt-4o-mini" or "llama3.1"
+	Model string `toml:"model,omitempty"`
+	// APIKeyEnv names the environment variable holding the API key,
+	// OPENAI_API_KEY by default for openai
+	APIKeyEnv string `toml:"api_key_env,omitempty"`
+}
+
+// AssistantEnabled reports whether the assistant may be used with profile p
+func (c *Config) AssistantEnabled(p *Profile) bool {
+	return c.Assistant.Enabled && (p == nil || !p.DisableAssistant)
+}
+
+// SessionTimezone is the Timezone following the database session
+const SessionTimezone = "session"
+
+// Layouts returns the time layouts for today and for older times
+func (t Timestamps) Layouts() (today, older string) {
+	today, older = t.Format, t.DateFormat
+	if today == "" {
+		today = "15:04:05"
+	}
+	if older == "" {
+		older = "2006-01-02 15:04"
+	}
+	return today, older
+}
+
+// Location returns the configured timezone, the local one when Timezone is
+// empty or "session"
+func (t Timestamps) Location() (*time.Location, error) {
+	if t.Timezone == "" || strings.EqualFold(t.Timezone, SessionTimezone) {
+		return time.Local, nil
+	}
+	return time.LoadLocation(t.Timezone)
+}
+
+// defaultResultMemoryMB applies when result_memory_mb is not set
+const defaultResultMemoryMB = 256
+
+// ResultMemory returns the bytes of result rows kept in memory before the
+// rest spill to disk, or 0 when results never spill
+func (c *Config) ResultMemory() int64 {
+	switch {
+	case c.ResultMemoryMB < 0:
+		return 0
+	case c.ResultMemoryMB == 0:
+		return defaultResultMemoryMB << 20
+	}
+	return int64(c.ResultMemoryMB) << 20
+}
+
+// AuditLog configures the append-only log of executed statements
+type AuditLog struct {
+	// Target is "file" (JSON lines), "sqlite" or empty to disable the log
+	Target string `toml:"target,omitempty"`
+	// Path overrides the default audit.log or audit.db in the data directory
+	Path string `toml:"path,omitempty"`
 }
 
 // Theme defines the color palette
@@ -46,48 +266,75 @@ type Theme struct {
 	SelectedBg    string `toml:"selected_bg"`
 }
 
-// KeyMap defines key bindings
+// KeyMap defines key bindings.
+// The UI's action registry is generated from the struct tags: `help` is the
+// description, `hint` the label on the bottom help line, `group` the help
+// section it is listed under and `ctx` the comma-separated UI contexts
+// (visual, insert, popup, schema, global) where the binding applies.
 type KeyMap struct {
 	// Existing keys
-	Execute     []string `toml:"execute"`
-	Exit        []string `toml:"exit"`
-	Filter      []string `toml:"filter"`
-	NextPage    []string `toml:"next_page"`
-	PrevPage    []string `toml:"prev_page"`
-	ScrollLeft  []string `toml:"scroll_left"`
-	ScrollRight []string `toml:"scroll_right"`
-	RowAction   []string `toml:"row_action"`
-	Export      []string `toml:"export"`
-	Sort        []string `toml:"sort"`
-	ToggleTheme []string `toml:"toggle_theme"`
+	Execute     []string `toml:"execute" help:"Execute query" hint:"Run" group:"Query" ctx:"insert"`
+	Exit        []string `toml:"exit" help:"Back / close popup" hint:"Visual" group:"General" ctx:"insert,popup,schema"`
+	Filter      []string `toml:"filter" help:"Filter results / search history" hint:"Filter" group:"Actions" ctx:"visual,popup"`
+	NextPage    []string `toml:"next_page" help:"Next page" group:"Navigation" ctx:"popup"`
+	PrevPage    []string `toml:"prev_page" help:"Previous page" group:"Navigation" ctx:"popup"`
+	ScrollLeft  []string `toml:"scroll_left" help:"Scroll columns left" group:"Navigation" ctx:"visual,popup"`
+	ScrollRight []string `toml:"scroll_right" help:"Scroll columns right" group:"Navigation" ctx:"visual,popup"`
+	RowAction   []string `toml:"row_action" help:"Row actions" hint:"Actions" group:"Actions" ctx:"popup"`
+	Export      []string `toml:"export" help:"Export to file" hint:"Export" group:"Actions" ctx:"popup,schema"`
+	Sort        []string `toml:"sort" help:"Sort by column" group:"Actions" ctx:"popup"`
+	ToggleTheme []string `toml:"toggle_theme" help:"Theme selector" hint:"Theme" group:"Panels" ctx:"visual"`
 	// Navigation keys
-	InsertMode   []string `toml:"insert_mode"`
-	MoveUp       []string `toml:"move_up"`
-	MoveDown     []string `toml:"move_down"`
-	GoTop        []string `toml:"go_top"`
-	GoBottom     []string `toml:"go_bottom"`
-	ToggleExpand []string `toml:"toggle_expand"`
+	InsertMode   []string `toml:"insert_mode" help:"Enter Insert mode" hint:"Insert" group:"Actions" ctx:"visual"`
+	MoveUp       []string `toml:"move_up" help:"Move up" hint:"Nav" group:"Navigation" ctx:"visual,popup,schema"`
+	MoveDown     []string `toml:"move_down" help:"Move down" group:"Navigation" ctx:"visual,popup,schema"`
+	GoTop        []string `toml:"go_top" help:"Jump to top" group:"Navigation" ctx:"visual"`
+	GoBottom     []string `toml:"go_bottom" help:"Jump to bottom" group:"Navigation" ctx:"visual"`
+	ToggleExpand []string `toml:"toggle_expand" help:"Expand / view details" hint:"Expand" group:"Navigation" ctx:"visual,schema"`
 	// Action keys
-	Rerun        []string `toml:"rerun"`
-	Edit         []string `toml:"edit"`
-	Delete       []string `toml:"delete"`
-	Copy         []string `toml:"copy"`
-	ToggleStrict []string `toml:"toggle_strict"`
-	ToggleSchema []string `toml:"toggle_schema"`
-	ShowProfiles []string `toml:"show_profiles"`
-	Help         []string `toml:"help"`
-	Explain      []string `toml:"explain"`
+	Rerun        []string `toml:"rerun" help:"Rerun query" hint:"Rerun" group:"Actions" ctx:"visual"`
+	Edit         []string `toml:"edit" help:"Edit query" hint:"Edit" group:"Actions" ctx:"visual"`
+	Delete       []string `toml:"delete" help:"Delete entry" group:"Actions" ctx:"visual"`
+	Copy         []string `toml:"copy" help:"Copy query" group:"Actions" ctx:"visual"`
+	ToggleStrict []string `toml:"toggle_strict" help:"Toggle strict mode" group:"Panels" ctx:"visual"`
+	ToggleSchema []string `toml:"toggle_schema" help:"Schema browser" hint:"Schema" group:"Panels" ctx:"visual,schema"`
+	ShowProfiles []string `toml:"show_profiles" help:"Switch profile" group:"Panels" ctx:"visual"`
+	Attach       []string `toml:"attach" help:"Attach SQLite databases" hint:"Attach" group:"Panels" ctx:"visual"`
+	Help         []string `toml:"help" help:"Toggle this help" hint:"Help" group:"General" ctx:"global"`
+	Explain      []string `toml:"explain" help:"Explain query" hint:"Explain" group:"Query" ctx:"insert"`
 	// Modifier keys
-	Autocomplete []string `toml:"autocomplete"`
-	Undo         []string `toml:"undo"`
-	Redo         []string `toml:"redo"`
-	Quit         []string `toml:"quit"`
+	Autocomplete []string `toml:"autocomplete" help:"Autocomplete" hint:"Complete" group:"Query" ctx:"insert"`
+	Undo         []string `toml:"undo" help:"Undo" group:"Edit" ctx:"insert"`
+	Redo         []string `toml:"redo" help:"Redo" group:"Edit" ctx:"insert"`
+	Quit         []string `toml:"quit" help:"Quit" hint:"Quit" group:"General" ctx:"global"`
+	// Docked sidebar keys
+	SidebarGrow   []string `toml:"sidebar_grow" help:"Widen schema sidebar" group:"Panels" ctx:"visual,schema"`
+	SidebarShrink []string `toml:"sidebar_shrink" help:"Narrow schema sidebar" group:"Panels" ctx:"visual,schema"`
+	ExpandDock    []string `toml:"expand_dock" help:"Expand results dock" hint:"Expand" group:"Panels" ctx:"visual,insert"`
+	Notifications []string `toml:"notifications" help:"Notification center" group:"Panels" ctx:"visual"`
+	ServerFilter  []string `toml:"server_filter" help:"Filter in the database (WHERE)" group:"Actions" ctx:"popup"`
+	OpenFile      []string `toml:"open_file" help:"Open SQL file" group:"Query" ctx:"visual,insert"`
+	SaveFile      []string `toml:"save_file" help:"Save editor to SQL file" group:"Query" ctx:"visual,insert"`
+	SwitchSchema  []string `toml:"switch_schema" help:"Switch schema / database" hint:"Schema" group:"Panels" ctx:"visual"`
+	Activity      []string `toml:"activity" help:"Active sessions" hint:"Activity" group:"Panels" ctx:"visual"`
+	Dashboard     []string `toml:"dashboard" help:"Server dashboard" hint:"Dashboard" group:"Panels" ctx:"visual"`
+	Snapshots     []string `toml:"snapshots" help:"Schema snapshots" hint:"Snapshots" group:"Panels" ctx:"visual"`
+	Analytics     []string `toml:"analytics" help:"Query analytics" hint:"Analytics" group:"Panels" ctx:"visual"`
+	OpenPager     []string `toml:"open_pager" help:"Open results in the pager" hint:"Pager" group:"Actions" ctx:"popup"`
+	FormatNumbers []string `toml:"format_numbers" help:"Toggle number formatting" group:"Actions" ctx:"popup"`
+	FullWidth     []string `toml:"full_width" help:"Toggle full column width" group:"Actions" ctx:"popup"`
+	ColumnActions []string `toml:"column_actions" help:"Column aggregates" group:"Actions" ctx:"popup"`
+	Transcript    []string `toml:"transcript" help:"Session transcript" hint:"Transcript" group:"Actions" ctx:"visual"`
+	RunOn         []string `toml:"run_on" help:"Rerun on another profile" hint:"Run on" group:"Actions" ctx:"visual"`
+	QueryBuilder  []string `toml:"query_builder" help:"Query builder" hint:"Builder" group:"Query" ctx:"visual"`
+	Assistant     []string `toml:"assistant" help:"Ask the assistant for SQL" hint:"Ask" group:"Query" ctx:"visual,insert"`
+	ExplainError  []string `toml:"explain_error" help:"Explain a failed query" hint:"Why?" group:"Actions" ctx:"visual"`
 }
 
 // Profile represents a database connection profile
 type Profile struct {
 	Name     string `toml:"name"`
-	Type     string `toml:"type"` // postgres, mysql, sqlite
+	Type     string `toml:"type"` // postgres, mysql, sqlite, cassandra, bigquery
 	Host     string `toml:"host"`
 	Port     int    `toml:"port"`
 	User     string `toml:"user"`
@@ -96,6 +343,12 @@ type Profile struct {
 	Password string `toml:"-"`
 	// EncryptedPassword is the one persisted in the config file
 	EncryptedPassword string `toml:"password"`
+	// PasswordCmd is a shell command printing the password at connect time,
+	// e.g. "op read op://Private/prod-db/password"
+	PasswordCmd string `toml:"password_cmd,omitempty"`
+	// VaultPath reads the password from HashiCorp Vault at connect time,
+	// e.g. "secret/data/prod-db#password"
+	VaultPath string `toml:"vault_path,omitempty"`
 
 	// SSH Tunnel Configuration
 	SSHHost     string `toml:"ssh_host,omitempty"`
@@ -106,6 +359,69 @@ type Profile struct {
 
 	// EncryptedSSHPassword persisted in config
 	EncryptedSSHPassword string `toml:"ssh_password,omitempty"`
+
+	// Color is an accent (hex or ANSI) applied to the editor border, status bar
+	// and history selection while connected, e.g. red for production.
+	Color string `toml:"color,omitempty"`
+
+	// Label is an environment badge such as "PROD" or "STAGING" shown in
+	// the status bar and the profile selector
+	Label string `toml:"label,omitempty"`
+
+	// ReadOnly refuses statements that modify data or schema
+	ReadOnly bool `toml:"read_only,omitempty"`
+
+	// LastUsed is when the profile last connected; the selector lists recent profiles first
+	LastUsed time.Time `toml:"last_used,omitempty"`
+
+	// Group is the folder the profile is listed under in the selector;
+	// slashes nest folders, e.g. "clients/acme"
+	Group string `toml:"group,omitempty"`
+
+	// CredentialsFile is a service account JSON key for bigquery.
+	// Host holds the GCP project and Database the default dataset.
+	CredentialsFile string `toml:"credentials_file,omitempty"`
+
+	// SessionInit statements run on every new connection, including
+	// reconnects, e.g. SET search_path TO app
+	SessionInit []string `toml:"session_init,omitempty"`
+
+	// Params are extra DSN parameters for postgres and mysql as key=value
+	// pairs separated by &, e.g. "application_name=ezdb&sslmode=disable"
+	Params string `toml:"params,omitempty"`
+
+	// ApplicationName tags the profile's connections, "ezdb <version>" by
+	// default; "-" sends nothing
+	ApplicationName string `toml:"application_name,omitempty"`
+
+	// Guards flag dangerous statements before they run, independent of strict mode
+	Guards QueryGuards `toml:"guards,omitempty"`
+
+	// Pager overrides the global pager for this profile's results
+	Pager string `toml:"pager,omitempty"`
+
+	// HistoryResults overrides the global limits on stored result sets
+	HistoryResults *HistoryResults `toml:"history_results,omitempty"`
+
+	// DisableAssistant keeps this profile's schema from the assistant even
+	// when it is enabled
+	DisableAssistant bool `toml:"disable_assistant,omitempty"`
+}
+
+// Query guard policies
+const (
+	GuardOff   = ""
+	GuardWarn  = "warn"  // Ask for confirmation
+	GuardBlock = "block" // Refuse to run the statement
+)
+
+// QueryGuards holds a policy per lint rule for dangerous statements
+type QueryGuards struct {
+	UnfilteredWrite string `toml:"unfiltered_write,omitempty"` // UPDATE/DELETE without WHERE, TRUNCATE
+	Drop            string `toml:"drop,omitempty"`             // DROP of non-temporary objects
+	CrossJoin       string `toml:"cross_join,omitempty"`       // Joins without a join condition
+	LargeWrite      string `toml:"large_write,omitempty"`      // UPDATE/DELETE estimated by EXPLAIN to touch more than MaxRows rows
+	MaxRows         int64  `toml:"max_rows,omitempty"`
 }
 
 const defaultHistoryFile = "history.txt"
@@ -164,6 +480,7 @@ func DefaultConfig() *Config {
 			ToggleStrict: []string{"m"},
 			ToggleSchema: []string{"tab"},
 			ShowProfiles: []string{"P"},
+			Attach:       []string{"A"},
 			Help:         []string{"?"},
 			Explain:      []string{"X"},
 			// Modifier keys
@@ -171,12 +488,35 @@ func DefaultConfig() *Config {
 			Undo:         []string{"ctrl+z"},
 			Redo:         []string{"ctrl+y"},
 			Quit:         []string{"ctrl+c"},
+			// Docked sidebar keys
+			SidebarGrow:   []string{">"},
+			SidebarShrink: []string{"<"},
+			ExpandDock:    []string{"ctrl+o"},
+			Notifications: []string{"N"},
+			ServerFilter:  []string{"W"},
+			OpenFile:      []string{"ctrl+r"},
+			SaveFile:      []string{"ctrl+s"},
+			SwitchSchema:  []string{"S"},
+			Activity:      []string{"ctrl+a"},
+			Dashboard:     []string{"D"},
+			Snapshots:     []string{"H"},
+			Analytics:     []string{"I"},
+			Transcript:    []string{"T"},
+			RunOn:         []string{"R"},
+			OpenPager:     []string{"p"},
+			FormatNumbers: []string{"#"},
+			FullWidth:     []string{"w"},
+			ColumnActions: []string{"A"},
+			QueryBuilder:  []string{"B"},
+			Assistant:     []string{"ctrl+g"},
+			ExplainError:  []string{"E"},
 		},
 		QueryTemplates: []QueryTemplate{
 			{Name: "SELECT 10", Query: "SELECT * FROM <table> LIMIT 10"},
 			{Name: "SELECT 100", Query: "SELECT * FROM <table> LIMIT 100"},
 			{Name: "COUNT", Query: "SELECT COUNT(*) FROM <table>"},
-			{Name: "DESCRIBE", Query: "DESCRIBE <table>"},
+			describeTemplate(),
+			{Name: "INSERT", Query: "INSERT INTO <table> (${1:columns}) VALUES (${2:values})"},
 		},
 	}
 }
@@ -321,6 +661,94 @@ func Load() (*Config, error) {
 		cfg.Keys.ShowProfiles = defaults.Keys.ShowProfiles
 		updated = true
 	}
+	if len(cfg.Keys.Attach) == 0 {
+		cfg.Keys.Attach = defaults.Keys.Attach
+		updated = true
+	}
+	if len(cfg.Keys.SidebarGrow) == 0 {
+		cfg.Keys.SidebarGrow = defaults.Keys.SidebarGrow
+		updated = true
+	}
+	if len(cfg.Keys.SidebarShrink) == 0 {
+		cfg.Keys.SidebarShrink = defaults.Keys.SidebarShrink
+		updated = true
+	}
+	if len(cfg.Keys.ExpandDock) == 0 {
+		cfg.Keys.ExpandDock = defaults.Keys.ExpandDock
+		updated = true
+	}
+	if len(cfg.Keys.Notifications) == 0 {
+		cfg.Keys.Notifications = defaults.Keys.Notifications
+		updated = true
+	}
+	if len(cfg.Keys.ServerFilter) == 0 {
+		cfg.Keys.ServerFilter = defaults.Keys.ServerFilter
+		updated = true
+	}
+	if len(cfg.Keys.OpenFile) == 0 {
+		cfg.Keys.OpenFile = defaults.Keys.OpenFile
+		updated = true
+	}
+	if len(cfg.Keys.SaveFile) == 0 {
+		cfg.Keys.SaveFile = defaults.Keys.SaveFile
+		updated = true
+	}
+	if len(cfg.Keys.SwitchSchema) == 0 {
+		cfg.Keys.SwitchSchema = defaults.Keys.SwitchSchema
+		updated = true
+	}
+	if len(cfg.Keys.Activity) == 0 {
+		cfg.Keys.Activity = defaults.Keys.Activity
+		updated = true
+	}
+	if len(cfg.Keys.Dashboard) == 0 {
+		cfg.Keys.Dashboard = defaults.Keys.Dashboard
+		updated = true
+	}
+	if len(cfg.Keys.Snapshots) == 0 {
+		cfg.Keys.Snapshots = defaults.Keys.Snapshots
+		updated = true
+	}
+	if len(cfg.Keys.Analytics) == 0 {
+		cfg.Keys.Analytics = defaults.Keys.Analytics
+		updated = true
+	}
+	if len(cfg.Keys.Transcript) == 0 {
+		cfg.Keys.Transcript = defaults.Keys.Transcript
+		updated = true
+	}
+	if len(cfg.Keys.RunOn) == 0 {
+		cfg.Keys.RunOn = defaults.Keys.RunOn
+		updated = true
+	}
+	if len(cfg.Keys.OpenPager) == 0 {
+		cfg.Keys.OpenPager = defaults.Keys.OpenPager
+		updated = true
+	}
+	if len(cfg.Keys.FormatNumbers) == 0 {
+		cfg.Keys.FormatNumbers = defaults.Keys.FormatNumbers
+		updated = true
+	}
+	if len(cfg.Keys.FullWidth) == 0 {
+		cfg.Keys.FullWidth = defaults.Keys.FullWidth
+		updated = true
+	}
+	if len(cfg.Keys.ColumnActions) == 0 {
+		cfg.Keys.ColumnActions = defaults.Keys.ColumnActions
+		updated = true
+	}
+	if len(cfg.Keys.QueryBuilder) == 0 {
+		cfg.Keys.QueryBuilder = defaults.Keys.QueryBuilder
+		updated = true
+	}
+	if len(cfg.Keys.Assistant) == 0 {
+		cfg.Keys.Assistant = defaults.Keys.Assistant
+		updated = true
+	}
+	if len(cfg.Keys.ExplainError) == 0 {
+		cfg.Keys.ExplainError = defaults.Keys.ExplainError
+		updated = true
+	}
 	if len(cfg.Keys.Autocomplete) == 0 {
 		cfg.Keys.Autocomplete = defaults.Keys.Autocomplete
 		updated = true
@@ -346,12 +774,21 @@ func Load() (*Config, error) {
 		updated = true
 	}
 
+	// Configs saved before templates had dialects carry the MySQL-only DESCRIBE
+	for i, t := range cfg.QueryTemplates {
+		if t.Query == "DESCRIBE <table>" && t.Dialects == nil {
+			cfg.QueryTemplates[i].Dialects = describeTemplate().Dialects
+			updated = true
+		}
+	}
+
 	if len(cfg.QueryTemplates) == 0 {
 		cfg.QueryTemplates = []QueryTemplate{
 			{Name: "SELECT 100", Query: "SELECT * FROM <table> LIMIT 100"},
 			{Name: "COUNT", Query: "SELECT COUNT(*) FROM <table>"},
-			{Name: "DESCRIBE", Query: "DESCRIBE <table>"},
+			describeTemplate(),
 			{Name: "INSERT DEFAULT", Query: "INSERT INTO <table> DEFAULT VALUES"},
+			{Name: "INSERT", Query: "INSERT INTO <table> (${1:columns}) VALUES (${2:values})"},
 		}
 		updated = true
 	}
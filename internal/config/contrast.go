@@ -0,0 +1,117 @@
+// internal/config/contrast.go
+// Contrast helpers so badges, the status bar and selections stay readable on any theme.
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Minimum contrast ratios (WCAG): body text and large/bold badge text
+const (
+	minTextContrast  = 4.5
+	minBadgeContrast = 3.0
+)
+
+// parseHex parses "#RRGGBB"; other formats (ANSI numbers, names) are not adjusted
+func parseHex(color string) (r, g, b float64, ok bool) {
+	color = strings.TrimPrefix(color, "#")
+	if len(color) != 6 {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(color, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return float64(v>>16&0xFF) / 255, float64(v>>8&0xFF) / 255, float64(v&0xFF) / 255, true
+}
+
+func formatHex(r, g, b float64) string {
+	c := func(v float64) int { return int(math.Round(math.Max(0, math.Min(1, v)) * 255)) }
+	return fmt.Sprintf("#%02X%02X%02X", c(r), c(g), c(b))
+}
+
+// luminance returns the WCAG relative luminance of a hex color
+func luminance(color string) (float64, bool) {
+	r, g, b, ok := parseHex(color)
+	if !ok {
+		return 0, false
+	}
+	lin := func(c float64) float64 {
+		if c <= 0.03928 {
+			return c / 12.92
+		}
+		return math.Pow((c+0.055)/1.055, 2.4)
+	}
+	return 0.2126*lin(r) + 0.7152*lin(g) + 0.0722*lin(b), true
+}
+
+// ContrastRatio returns the WCAG contrast ratio of two hex colors (1 to 21), or 0 if unknown
+func ContrastRatio(a, b string) float64 {
+	la, okA := luminance(a)
+	lb, okB := luminance(b)
+	if !okA || !okB {
+		return 0
+	}
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// IsLight reports whether the theme has a light background
+func (t Theme) IsLight() bool {
+	l, ok := luminance(t.BgPrimary)
+	return ok && l > 0.5
+}
+
+// ReadableOn returns the first candidate with enough contrast on bg,
+// falling back to black or white, whichever reads better
+func ReadableOn(bg string, candidates ...string) string {
+	if _, ok := luminance(bg); !ok {
+		if len(candidates) > 0 {
+			return candidates[0]
+		}
+		return ""
+	}
+	for _, c := range candidates {
+		if ContrastRatio(c, bg) >= minBadgeContrast {
+			return c
+		}
+	}
+	if ContrastRatio("#000000", bg) >= ContrastRatio("#FFFFFF", bg) {
+		return "#000000"
+	}
+	return "#FFFFFF"
+}
+
+// separateFrom mixes bg toward black or white (away from fg) until fg reads on it
+func separateFrom(bg, fg string, min float64) string {
+	r, g, b, ok := parseHex(bg)
+	lf, okF := luminance(fg)
+	if !ok || !okF || ContrastRatio(fg, bg) >= min {
+		return bg
+	}
+	target := 0.0 // Light text: darken the background
+	if lf < 0.5 {
+		target = 1.0 // Dark text: lighten the background
+	}
+	for step := 0.1; step <= 1.0; step += 0.1 {
+		adjusted := formatHex(r+(target-r)*step, g+(target-g)*step, b+(target-b)*step)
+		if ContrastRatio(fg, adjusted) >= min {
+			return adjusted
+		}
+	}
+	return formatHex(target, target, target)
+}
+
+// Readable returns a copy of the theme whose status bar, card and selection
+// backgrounds keep primary text legible
+func (t Theme) Readable() Theme {
+	t.BgSecondary = separateFrom(t.BgSecondary, t.TextPrimary, minTextContrast)
+	t.CardBg = separateFrom(t.CardBg, t.TextPrimary, minBadgeContrast)
+	t.SelectedBg = separateFrom(t.SelectedBg, t.TextPrimary, minTextContrast)
+	return t
+}
@@ -1,7 +1,50 @@
 package config
 
-// GetThemes returns the list of available themes
+// Default light/dark pair for theme_mode = "auto"
+const (
+	defaultLightTheme = "Solarized Light"
+	defaultDarkTheme  = "JetBrains Darcula"
+)
+
+// AutoThemeName returns the configured theme for a light or dark terminal
+func (c *Config) AutoThemeName(dark bool) string {
+	name, fallback := c.LightTheme, defaultLightTheme
+	if dark {
+		name, fallback = c.DarkTheme, defaultDarkTheme
+	}
+	if _, ok := GetThemes()[name]; !ok {
+		return fallback
+	}
+	return name
+}
+
+// ApplyAutoTheme switches to the light or dark theme when theme_mode is "auto".
+// It reports whether the theme changed.
+func (c *Config) ApplyAutoTheme(dark bool) bool {
+	if c.ThemeMode != "auto" {
+		return false
+	}
+	name := c.AutoThemeName(dark)
+	if name == c.ThemeName {
+		return false
+	}
+	c.ThemeName = name
+	c.Theme = GetThemes()[name]
+	return true
+}
+
+// GetThemes returns the built-in themes plus user themes from ThemesDir.
+// A user theme with a built-in name replaces it.
 func GetThemes() map[string]Theme {
+	themes := builtinThemes()
+	for name, t := range cachedUserThemes() {
+		themes[name] = t
+	}
+	return themes
+}
+
+// builtinThemes returns the themes shipped with ezdb
+func builtinThemes() map[string]Theme {
 	return map[string]Theme{
 		"JetBrains Darcula": {
 			TextPrimary:   "#A9B7C6",
@@ -67,6 +110,118 @@ func GetThemes() map[string]Theme {
 			BorderColor:   "#75715E",
 			SelectedBg:    "#49483E",
 		},
+		"Dracula": {
+			TextPrimary:   "#F8F8F2",
+			TextSecondary: "#8BE9FD",
+			TextFaint:     "#6272A4",
+			Accent:        "#FF79C6",
+			Success:       "#50FA7B",
+			Error:         "#FF5555",
+			Highlight:     "#BD93F9",
+			Warning:       "#F1FA8C",
+			BgPrimary:     "#282A36",
+			BgSecondary:   "#343746",
+			CardBg:        "#44475A",
+			PopupBg:       "#21222C",
+			BorderColor:   "#6272A4",
+			SelectedBg:    "#44475A",
+		},
+		"Gruvbox Light": {
+			TextPrimary:   "#3C3836",
+			TextSecondary: "#076678",
+			TextFaint:     "#928374",
+			Accent:        "#AF3A03",
+			Success:       "#79740E",
+			Error:         "#9D0006",
+			Highlight:     "#8F3F71",
+			Warning:       "#B57614",
+			BgPrimary:     "#FBF1C7",
+			BgSecondary:   "#EBDBB2",
+			CardBg:        "#F2E5BC",
+			PopupBg:       "#F9F5D7",
+			BorderColor:   "#BDAE93",
+			SelectedBg:    "#D5C4A1",
+		},
+		"Solarized Dark": {
+			TextPrimary:   "#93A1A1",
+			TextSecondary: "#268BD2",
+			TextFaint:     "#586E75",
+			Accent:        "#CB4B16",
+			Success:       "#859900",
+			Error:         "#DC322F",
+			Highlight:     "#6C71C4",
+			Warning:       "#B58900",
+			BgPrimary:     "#002B36",
+			BgSecondary:   "#073642",
+			CardBg:        "#0A4050",
+			PopupBg:       "#00212B",
+			BorderColor:   "#586E75",
+			SelectedBg:    "#094352",
+		},
+		"Solarized Light": {
+			TextPrimary:   "#586E75",
+			TextSecondary: "#268BD2",
+			TextFaint:     "#93A1A1",
+			Accent:        "#CB4B16",
+			Success:       "#859900",
+			Error:         "#DC322F",
+			Highlight:     "#6C71C4",
+			Warning:       "#B58900",
+			BgPrimary:     "#FDF6E3",
+			BgSecondary:   "#EEE8D5",
+			CardBg:        "#EEE8D5",
+			PopupBg:       "#FFFBF0",
+			BorderColor:   "#93A1A1",
+			SelectedBg:    "#E4DDC8",
+		},
+		"Catppuccin Mocha": {
+			TextPrimary:   "#CDD6F4",
+			TextSecondary: "#89B4FA",
+			TextFaint:     "#6C7086",
+			Accent:        "#FAB387",
+			Success:       "#A6E3A1",
+			Error:         "#F38BA8",
+			Highlight:     "#CBA6F7",
+			Warning:       "#F9E2AF",
+			BgPrimary:     "#1E1E2E",
+			BgSecondary:   "#313244",
+			CardBg:        "#45475A",
+			PopupBg:       "#181825",
+			BorderColor:   "#585B70",
+			SelectedBg:    "#45475A",
+		},
+		"Catppuccin Macchiato": {
+			TextPrimary:   "#CAD3F5",
+			TextSecondary: "#8AADF4",
+			TextFaint:     "#6E738D",
+			Accent:        "#F5A97F",
+			Success:       "#A6DA95",
+			Error:         "#ED8796",
+			Highlight:     "#C6A0F6",
+			Warning:       "#EED49F",
+			BgPrimary:     "#24273A",
+			BgSecondary:   "#363A4F",
+			CardBg:        "#494D64",
+			PopupBg:       "#1E2030",
+			BorderColor:   "#5B6078",
+			SelectedBg:    "#494D64",
+		},
+		"Catppuccin Latte": {
+			TextPrimary:   "#4C4F69",
+			TextSecondary: "#1E66F5",
+			TextFaint:     "#9CA0B0",
+			Accent:        "#FE640B",
+			Success:       "#40A02B",
+			Error:         "#D20F39",
+			Highlight:     "#8839EF",
+			Warning:       "#DF8E1D",
+			BgPrimary:     "#EFF1F5",
+			BgSecondary:   "#E6E9EF",
+			CardBg:        "#DCE0E8",
+			PopupBg:       "#F5F6FA",
+			BorderColor:   "#ACB0BE",
+			SelectedBg:    "#CCD0DA",
+		},
 		"Tokyo Night": {
 			TextPrimary:   "#C0CAF5",
 			TextSecondary: "#7AA2F7",
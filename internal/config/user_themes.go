@@ -0,0 +1,143 @@
+// internal/config/user_themes.go
+// User themes: one TOML file per theme in ~/.config/ezdb/themes/.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/adrg/xdg"
+)
+
+// userThemeFile is the on-disk format: the [theme_colors] keys at the top
+// level, plus an optional display name (defaults to the file name)
+type userThemeFile struct {
+	Name string `toml:"name"`
+	Theme
+}
+
+var (
+	userThemesMu     sync.RWMutex
+	userThemes       map[string]Theme
+	userThemesLoaded bool
+)
+
+// ThemesDir returns the directory scanned for user theme files
+func ThemesDir() string {
+	return filepath.Join(xdg.ConfigHome, "ezdb", "themes")
+}
+
+// ReloadUserThemes rescans ThemesDir. Files that fail to parse are skipped
+// and reported in the returned error; the rest are still loaded.
+func ReloadUserThemes() error {
+	themes, err := loadUserThemes(ThemesDir())
+	userThemesMu.Lock()
+	userThemes = themes
+	userThemesLoaded = true
+	userThemesMu.Unlock()
+	return err
+}
+
+// UserThemeNames returns the names of loaded user themes, sorted
+func UserThemeNames() []string {
+	themes := cachedUserThemes()
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsUserTheme reports whether name comes from a theme file
+func IsUserTheme(name string) bool {
+	_, ok := cachedUserThemes()[name]
+	return ok
+}
+
+// cachedUserThemes loads user themes on first use
+func cachedUserThemes() map[string]Theme {
+	userThemesMu.RLock()
+	loaded := userThemesLoaded
+	themes := userThemes
+	userThemesMu.RUnlock()
+	if !loaded {
+		_ = ReloadUserThemes()
+		userThemesMu.RLock()
+		themes = userThemes
+		userThemesMu.RUnlock()
+	}
+	return themes
+}
+
+// loadUserThemes parses every *.toml file in dir
+func loadUserThemes(dir string) (map[string]Theme, error) {
+	themes := make(map[string]Theme)
+	files, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil {
+		return themes, err
+	}
+
+	var failed []string
+	for _, path := range files {
+		var f userThemeFile
+		if _, err := toml.DecodeFile(path, &f); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", filepath.Base(path), err))
+			continue
+		}
+		name := strings.TrimSpace(f.Name)
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		themes[name] = f.Theme.withDefaults()
+	}
+	if len(failed) > 0 {
+		return themes, fmt.Errorf("invalid theme files: %s", strings.Join(failed, "; "))
+	}
+	return themes, nil
+}
+
+// withDefaults fills colors a theme file leaves out from the default theme
+func (t Theme) withDefaults() Theme {
+	d := DefaultConfig().Theme
+	fill := func(v *string, def string) {
+		if *v == "" {
+			*v = def
+		}
+	}
+	fill(&t.TextPrimary, d.TextPrimary)
+	fill(&t.TextSecondary, d.TextSecondary)
+	fill(&t.TextFaint, d.TextFaint)
+	fill(&t.Accent, d.Accent)
+	fill(&t.Success, d.Success)
+	fill(&t.Error, d.Error)
+	fill(&t.Highlight, d.Highlight)
+	fill(&t.Warning, d.Warning)
+	fill(&t.BgPrimary, d.BgPrimary)
+	fill(&t.BgSecondary, d.BgSecondary)
+	fill(&t.CardBg, d.CardBg)
+	fill(&t.PopupBg, d.PopupBg)
+	fill(&t.BorderColor, d.BorderColor)
+	fill(&t.SelectedBg, d.SelectedBg)
+	return t
+}
+
+// UserThemesStamp summarizes theme file names, sizes and mtimes so callers
+// can poll for changes cheaply
+func UserThemesStamp() string {
+	files, _ := filepath.Glob(filepath.Join(ThemesDir(), "*.toml"))
+	var b strings.Builder
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", filepath.Base(path), info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String()
+}
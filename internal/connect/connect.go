@@ -0,0 +1,115 @@
+// internal/connect/connect.go
+// Package connect opens database drivers from configured profiles.
+package connect
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/secrets"
+)
+
+// secretTimeout bounds fetching a password from password_cmd or Vault
+const secretTimeout = 30 * time.Second
+
+// DriverType maps a profile type to its driver type
+func DriverType(profileType string) (db.DriverType, error) {
+	switch profileType {
+	case "postgres":
+		return db.Postgres, nil
+	case "mysql":
+		return db.MySQL, nil
+	case "sqlite":
+		return db.SQLite, nil
+	case "cassandra":
+		return db.Cassandra, nil
+	case "bigquery":
+		return db.BigQuery, nil
+	}
+	return "", fmt.Errorf("unsupported database type: %s", profileType)
+}
+
+// applicationName is what the profile's connections are tagged with for
+// pg_stat_activity and MySQL's session attributes
+func applicationName(profile *config.Profile) string {
+	switch profile.ApplicationName {
+	case "-":
+		return ""
+	case "":
+		if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			return "ezdb " + info.Main.Version
+		}
+		return "ezdb"
+	}
+	return profile.ApplicationName
+}
+
+// Params builds connection parameters for a profile, including its SSH tunnel
+func Params(profile *config.Profile) db.ConnectParams {
+	// Use password from profile
+	password := profile.Password
+	if password == "" && secrets.ForProfile(profile) == nil && profile.Type != "sqlite" && profile.Type != "bigquery" {
+		// Fallback to keyring for existing profiles not yet migrated to config
+		keyringStore, err := config.NewKeyringStore()
+		if err == nil {
+			password, _ = keyringStore.GetPassword(profile.Name)
+		}
+	}
+
+	params := db.ConnectParams{
+		Host:     profile.Host,
+		Port:     profile.Port,
+		User:     profile.User,
+		Password: password,
+		Database: profile.Database,
+
+		CredentialsFile:   profile.CredentialsFile,
+		SessionStatements: profile.SessionInit,
+		Params:            profile.Params,
+		ApplicationName:   applicationName(profile),
+		ReadOnly:          profile.ReadOnly,
+	}
+
+	if profile.SSHHost != "" {
+		params.SSHConfig = &db.SSHConfig{
+			Host:     profile.SSHHost,
+			Port:     profile.SSHPort,
+			User:     profile.SSHUser,
+			Password: profile.SSHPassword,
+			KeyPath:  profile.SSHKeyPath,
+		}
+	}
+	return params
+}
+
+// Open creates a driver for the profile and connects it
+func Open(profile *config.Profile) (db.Driver, error) {
+	driverType, err := DriverType(profile.Type)
+	if err != nil {
+		return nil, db.WrapConnectionError(err)
+	}
+
+	driver, err := db.NewDriver(driverType)
+	if err != nil {
+		return nil, err
+	}
+
+	params := Params(profile)
+	if provider := secrets.ForProfile(profile); provider != nil {
+		// Fetched for each connection and never stored
+		ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+		defer cancel()
+		if params.Password, err = provider.Password(ctx); err != nil {
+			return nil, db.WrapConnectionError(fmt.Errorf("fetching password: %w", err))
+		}
+	}
+
+	if err := driver.Connect(params); err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
@@ -0,0 +1,103 @@
+// internal/connect/health.go
+package connect
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nhath/ezdb/internal/config"
+)
+
+// HealthResult is the outcome of checking a single profile
+type HealthResult struct {
+	Profile string
+	Type    string
+	Via     string        // "direct" or "ssh <host>"
+	Connect time.Duration // Time to open the connection (including tunnel)
+	Ping    time.Duration // Round trip of a ping on the open connection
+	Err     error
+}
+
+// OK reports whether the profile was reachable
+func (r HealthResult) OK() bool {
+	return r.Err == nil
+}
+
+// CheckAll connects to and pings every profile concurrently.
+// Each check is bounded by timeout; results keep the order of profiles.
+func CheckAll(profiles []config.Profile, timeout time.Duration) []HealthResult {
+	results := make([]HealthResult, len(profiles))
+	var wg sync.WaitGroup
+	for i := range profiles {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = Check(&profiles[i], timeout)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// Check connects to a single profile, pings it and closes the connection
+func Check(profile *config.Profile, timeout time.Duration) HealthResult {
+	result := HealthResult{Profile: profile.Name, Type: profile.Type, Via: "direct"}
+	if profile.SSHHost != "" {
+		result.Via = "ssh " + profile.SSHHost
+	}
+
+	// Connect takes no context, so bound it here; a hung dial is abandoned.
+	done := make(chan HealthResult, 1)
+	go func() {
+		r := result
+		start := time.Now()
+		driver, err := Open(profile)
+		r.Connect = time.Since(start)
+		if err != nil {
+			r.Err = err
+			done <- r
+			return
+		}
+		defer driver.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		start = time.Now()
+		r.Err = driver.Ping(ctx)
+		r.Ping = time.Since(start)
+		done <- r
+	}()
+
+	select {
+	case r := <-done:
+		return r
+	case <-time.After(timeout):
+		result.Connect = timeout
+		result.Err = fmt.Errorf("timed out after %s", timeout)
+		return result
+	}
+}
+
+// WriteHealthTable prints results as an aligned table
+func WriteHealthTable(w io.Writer, results []HealthResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROFILE\tTYPE\tVIA\tSTATUS\tCONNECT\tPING\tERROR")
+	for _, r := range results {
+		status, ping, errMsg := "ok", formatLatency(r.Ping), ""
+		if !r.OK() {
+			status, ping, errMsg = "FAIL", "-", r.Err.Error()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Profile, r.Type, r.Via, status, formatLatency(r.Connect), ping, errMsg)
+	}
+	return tw.Flush()
+}
+
+// formatLatency renders a duration in milliseconds
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
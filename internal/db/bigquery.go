@@ -0,0 +1,370 @@
+// internal/db/bigquery.go
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// onDemandPricePerTiB is the BigQuery on-demand analysis price in USD
+const onDemandPricePerTiB = 6.25
+
+// BigQueryDriver implements Driver for Google BigQuery (standard SQL).
+// ConnectParams.Host is the GCP project and Database the default dataset.
+type BigQueryDriver struct {
+	client  *bigquery.Client
+	project string
+	dataset string
+}
+
+// Connect creates a BigQuery client using a service account key or ADC
+func (d *BigQueryDriver) Connect(params ConnectParams) error {
+	if params.Host == "" {
+		return WrapConnectionError(fmt.Errorf("bigquery project is required"))
+	}
+
+	var opts []option.ClientOption
+	if params.CredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(params.CredentialsFile))
+	}
+
+	client, err := bigquery.NewClient(context.Background(), params.Host, opts...)
+	if err != nil {
+		return WrapConnectionError(err)
+	}
+
+	d.client = client
+	d.project = params.Host
+	d.dataset = params.Database
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := d.Ping(ctx); err != nil {
+		client.Close()
+		return err
+	}
+	return nil
+}
+
+// Close closes the BigQuery client
+func (d *BigQueryDriver) Close() error {
+	if d.client != nil {
+		return d.client.Close()
+	}
+	return nil
+}
+
+// query builds a standard SQL query with the profile's default dataset
+func (d *BigQueryDriver) query(sql string) *bigquery.Query {
+	q := d.client.Query(sql)
+	q.DefaultProjectID = d.project
+	q.DefaultDatasetID = d.dataset
+	return q
+}
+
+// Execute runs a query job and returns results
+func (d *BigQueryDriver) Execute(ctx context.Context, query string) (*QueryResult, error) {
+	start := time.Now()
+
+	job, err := d.query(query).Run(ctx)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	if err := status.Err(); err != nil {
+		return nil, WrapQueryError(err)
+	}
+
+	// DML and DDL report affected rows instead of a result set.
+	// Scripts are read like SELECTs: the iterator yields the final statement's rows.
+	stats := queryStatistics(status)
+	isScript := stats != nil && stats.StatementType == "SCRIPT"
+	if stats != nil && stats.StatementType != "" && stats.StatementType != "SELECT" && !isScript {
+		return &QueryResult{
+			ExecTime:     time.Since(start),
+			IsSelect:     false,
+			AffectedRows: stats.NumDMLAffectedRows,
+		}, nil
+	}
+
+	fetchStart := time.Now()
+	it, err := job.Read(ctx)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+
+	collected := newRowCollector(ctx)
+	for {
+		var values []bigquery.Value
+		err := it.Next(&values)
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			collected.discard()
+			return nil, WrapQueryError(err)
+		}
+		row := make([]string, len(values))
+		for i, v := range values {
+			row[i] = formatValue(v)
+		}
+		if err := collected.add(row); err != nil {
+			collected.discard()
+			return nil, err
+		}
+	}
+
+	// A script ending in DML/DDL has no final result set
+	if isScript && len(it.Schema) == 0 {
+		collected.discard()
+		return &QueryResult{
+			ExecTime:     time.Since(start),
+			IsSelect:     false,
+			AffectedRows: stats.NumDMLAffectedRows,
+		}, nil
+	}
+
+	// Schema is only populated once the iterator has fetched a page
+	columns := make([]string, len(it.Schema))
+	for i, f := range it.Schema {
+		columns[i] = f.Name
+	}
+
+	res, err := collected.result(columns, start)
+	if err != nil {
+		return nil, err
+	}
+	res.Timing = Timing{Execute: fetchStart.Sub(start), Fetch: time.Since(fetchStart)}
+	// The job's own clock splits the wait into queueing and running
+	if st := status.Statistics; st != nil && !st.StartTime.IsZero() && !st.EndTime.IsZero() {
+		res.Timing.Queue = st.StartTime.Sub(st.CreationTime)
+		res.Timing.Execute = st.EndTime.Sub(st.StartTime)
+	}
+	return res, nil
+}
+
+// EstimateCost dry-runs the query and reports bytes processed and on-demand price
+func (d *BigQueryDriver) EstimateCost(ctx context.Context, query string) (string, error) {
+	q := d.query(query)
+	q.DryRun = true
+	job, err := q.Run(ctx)
+	if err != nil {
+		return "", WrapQueryError(err)
+	}
+	status := job.LastStatus()
+	if err := status.Err(); err != nil {
+		return "", WrapQueryError(err)
+	}
+	if status.Statistics == nil {
+		return "", WrapQueryError(fmt.Errorf("dry run returned no statistics"))
+	}
+
+	bytes := status.Statistics.TotalBytesProcessed
+	cost := float64(bytes) / (1 << 40) * onDemandPricePerTiB
+	return fmt.Sprintf("Will process %s (~$%.4f on-demand)", formatBytes(bytes), cost), nil
+}
+
+// Ping checks credentials and reachability with a free dry run
+func (d *BigQueryDriver) Ping(ctx context.Context) error {
+	if d.client == nil {
+		return WrapConnectionError(fmt.Errorf("not connected"))
+	}
+	q := d.query("SELECT 1")
+	q.DryRun = true
+	if _, err := q.Run(ctx); err != nil {
+		return WrapConnectionError(err)
+	}
+	return nil
+}
+
+// Type returns the driver type
+func (d *BigQueryDriver) Type() DriverType {
+	return BigQuery
+}
+
+// GetTables returns dataset-qualified tables of every dataset in the project
+func (d *BigQueryDriver) GetTables(ctx context.Context) ([]string, error) {
+	var tables []string
+	datasets := d.client.Datasets(ctx)
+	for {
+		ds, err := datasets.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, WrapQueryError(err)
+		}
+
+		it := ds.Tables(ctx)
+		for {
+			t, err := it.Next()
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			if err != nil {
+				return nil, WrapQueryError(err)
+			}
+			tables = append(tables, ds.DatasetID+"."+t.TableID)
+		}
+	}
+
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// GetColumns returns top-level column metadata for a table.
+// Primary key columns are reported as PRI and clustering columns as CLU.
+func (d *BigQueryDriver) GetColumns(ctx context.Context, tableName string) ([]Column, error) {
+	meta, err := d.tableMetadata(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]string)
+	if meta.Clustering != nil {
+		for _, f := range meta.Clustering.Fields {
+			keys[f] = "CLU"
+		}
+	}
+	if meta.TableConstraints != nil && meta.TableConstraints.PrimaryKey != nil {
+		for _, c := range meta.TableConstraints.PrimaryKey.Columns {
+			keys[c] = "PRI"
+		}
+	}
+
+	columns := make([]Column, 0, len(meta.Schema))
+	for _, f := range meta.Schema {
+		columns = append(columns, Column{
+			Name:     f.Name,
+			Type:     fieldTypeName(f),
+			Nullable: !f.Required,
+			Default:  f.DefaultValueExpression,
+			Key:      keys[f.Name],
+		})
+	}
+	return columns, nil
+}
+
+// GetConstraints returns key constraints, partitioning and clustering for a table
+func (d *BigQueryDriver) GetConstraints(ctx context.Context, tableName string) ([]Constraint, error) {
+	meta, err := d.tableMetadata(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	_, table := d.splitTableName(tableName)
+
+	var constraints []Constraint
+	if tc := meta.TableConstraints; tc != nil {
+		if tc.PrimaryKey != nil && len(tc.PrimaryKey.Columns) > 0 {
+			constraints = append(constraints, Constraint{
+				Name:       table + "_pkey",
+				Type:       "PRIMARY KEY",
+				Definition: "PRIMARY KEY (" + strings.Join(tc.PrimaryKey.Columns, ", ") + ") NOT ENFORCED",
+			})
+		}
+		for _, fk := range tc.ForeignKeys {
+			var from, to []string
+			for _, ref := range fk.ColumnReferences {
+				from = append(from, ref.ReferencingColumn)
+				to = append(to, ref.ReferencedColumn)
+			}
+			refTable := ""
+			if fk.ReferencedTable != nil {
+				refTable = fk.ReferencedTable.DatasetID + "." + fk.ReferencedTable.TableID
+			}
+			constraints = append(constraints, Constraint{
+				Name:       fk.Name,
+				Type:       "FOREIGN KEY",
+				Definition: fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s) NOT ENFORCED", strings.Join(from, ", "), refTable, strings.Join(to, ", ")),
+				Columns:    from,
+				RefTable:   refTable,
+				RefColumns: to,
+			})
+		}
+	}
+
+	if tp := meta.TimePartitioning; tp != nil {
+		field := tp.Field
+		if field == "" {
+			field = "_PARTITIONTIME"
+		}
+		constraints = append(constraints, Constraint{
+			Name:       table + "_partition",
+			Type:       "PARTITION",
+			Definition: fmt.Sprintf("PARTITION BY %s (%s)", field, tp.Type),
+		})
+	}
+	if meta.Clustering != nil && len(meta.Clustering.Fields) > 0 {
+		constraints = append(constraints, Constraint{
+			Name:       table + "_cluster",
+			Type:       "CLUSTER",
+			Definition: "CLUSTER BY " + strings.Join(meta.Clustering.Fields, ", "),
+		})
+	}
+	return constraints, nil
+}
+
+// tableMetadata fetches metadata for "dataset.table" or a table in the default dataset
+func (d *BigQueryDriver) tableMetadata(ctx context.Context, tableName string) (*bigquery.TableMetadata, error) {
+	dataset, table := d.splitTableName(tableName)
+	meta, err := d.client.Dataset(dataset).Table(table).Metadata(ctx)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	return meta, nil
+}
+
+// splitTableName splits "dataset.table", falling back to the default dataset
+func (d *BigQueryDriver) splitTableName(tableName string) (string, string) {
+	tableName = strings.Trim(tableName, "`")
+	if parts := strings.SplitN(tableName, ".", 2); len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return d.dataset, tableName
+}
+
+// queryStatistics returns query job statistics if available
+func queryStatistics(status *bigquery.JobStatus) *bigquery.QueryStatistics {
+	if status == nil || status.Statistics == nil {
+		return nil
+	}
+	stats, _ := status.Statistics.Details.(*bigquery.QueryStatistics)
+	return stats
+}
+
+// fieldTypeName renders a field type as it appears in DDL (ARRAY<...>, STRUCT)
+func fieldTypeName(f *bigquery.FieldSchema) string {
+	name := string(f.Type)
+	if f.Type == bigquery.RecordFieldType {
+		name = "STRUCT"
+	}
+	if f.Repeated {
+		return "ARRAY<" + name + ">"
+	}
+	return name
+}
+
+// formatBytes renders a byte count with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
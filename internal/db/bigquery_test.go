@@ -0,0 +1,42 @@
+// internal/db/bigquery_test.go
+package db
+
+import (
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:                    "0 B",
+		1023:                 "1023 B",
+		1024:                 "1.0 KiB",
+		1536:                 "1.5 KiB",
+		10 * 1024 * 1024:     "10.0 MiB",
+		3 << 30:              "3.0 GiB",
+		5 * (int64(1) << 40): "5.0 TiB",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestFieldTypeName(t *testing.T) {
+	tests := []struct {
+		field *bigquery.FieldSchema
+		want  string
+	}{
+		{&bigquery.FieldSchema{Type: bigquery.StringFieldType}, "STRING"},
+		{&bigquery.FieldSchema{Type: bigquery.IntegerFieldType, Repeated: true}, "ARRAY<INTEGER>"},
+		{&bigquery.FieldSchema{Type: bigquery.RecordFieldType}, "STRUCT"},
+		{&bigquery.FieldSchema{Type: bigquery.RecordFieldType, Repeated: true}, "ARRAY<STRUCT>"},
+	}
+	for _, tt := range tests {
+		if got := fieldTypeName(tt.field); got != tt.want {
+			t.Errorf("fieldTypeName(%v) = %s, want %s", tt.field.Type, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+// internal/db/cassandra.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// systemKeyspaces are hidden from the schema browser
+var systemKeyspaces = map[string]bool{
+	"system":                true,
+	"system_auth":           true,
+	"system_schema":         true,
+	"system_distributed":    true,
+	"system_traces":         true,
+	"system_views":          true,
+	"system_virtual_schema": true,
+}
+
+// CassandraDriver implements Driver for Apache Cassandra and ScyllaDB (CQL)
+type CassandraDriver struct {
+	session  *gocql.Session
+	tunnel   *SSHTunnel
+	keyspace string
+}
+
+// Connect establishes a CQL session
+func (d *CassandraDriver) Connect(params ConnectParams) error {
+	cluster := gocql.NewCluster(params.Host)
+	if params.Port != 0 {
+		cluster.Port = params.Port
+	}
+	cluster.Keyspace = params.Database
+	cluster.Consistency = gocql.LocalQuorum
+	cluster.Timeout = 30 * time.Second
+	cluster.ConnectTimeout = 15 * time.Second
+	cluster.NumConns = 2
+
+	if params.User != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{
+			Username: params.User,
+			Password: params.Password,
+		}
+	}
+
+	// Setup SSH tunnel if configured
+	if params.SSHConfig != nil && params.SSHConfig.Host != "" {
+		tunnel, err := NewSSHTunnel(params.SSHConfig)
+		if err != nil {
+			return WrapConnectionError(fmt.Errorf("failed to create SSH tunnel: %w", err))
+		}
+		d.tunnel = tunnel
+
+		// Peers advertise addresses that are only reachable from the SSH host,
+		// so stick to the contact point and dial everything through the tunnel.
+		cluster.DisableInitialHostLookup = true
+		cluster.Dialer = tunnel
+	}
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		if d.tunnel != nil {
+			d.tunnel.Close()
+		}
+		return WrapConnectionError(err)
+	}
+
+	d.session = session
+	d.keyspace = params.Database
+	return nil
+}
+
+// Close closes the CQL session and SSH tunnel
+func (d *CassandraDriver) Close() error {
+	if d.session != nil {
+		d.session.Close()
+	}
+	if d.tunnel != nil {
+		return d.tunnel.Close()
+	}
+	return nil
+}
+
+// Execute runs a CQL statement and returns results
+func (d *CassandraDriver) Execute(ctx context.Context, query string) (*QueryResult, error) {
+	start := time.Now()
+	iter := d.session.Query(query).WithContext(ctx).Iter()
+	fetchStart := time.Now() // Iter has fetched the first page
+
+	var columns []string
+	for _, c := range iter.Columns() {
+		columns = append(columns, c.Name)
+	}
+
+	collected := newRowCollector(ctx)
+	for {
+		values := make(map[string]interface{}, len(columns))
+		if !iter.MapScan(values) {
+			break
+		}
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = formatValue(values[c])
+		}
+		if err := collected.add(row); err != nil {
+			iter.Close()
+			collected.discard()
+			return nil, err
+		}
+	}
+
+	if err := iter.Close(); err != nil {
+		collected.discard()
+		return nil, WrapQueryError(err)
+	}
+
+	// Statements without a result set (INSERT, UPDATE, DDL) report no columns
+	if len(columns) == 0 {
+		return &QueryResult{
+			ExecTime: time.Since(start),
+			IsSelect: false,
+		}, nil
+	}
+
+	res, err := collected.result(columns, start)
+	if err != nil {
+		return nil, err
+	}
+	res.Timing = Timing{Execute: fetchStart.Sub(start), Fetch: time.Since(fetchStart)}
+	return res, nil
+}
+
+// Ping checks if the cluster is reachable
+func (d *CassandraDriver) Ping(ctx context.Context) error {
+	if d.session == nil || d.session.Closed() {
+		return WrapConnectionError(fmt.Errorf("not connected"))
+	}
+	var version string
+	if err := d.session.Query("SELECT release_version FROM system.local").WithContext(ctx).Scan(&version); err != nil {
+		return WrapConnectionError(err)
+	}
+	return nil
+}
+
+// Type returns the driver type
+func (d *CassandraDriver) Type() DriverType {
+	return Cassandra
+}
+
+// GetTables returns keyspace-qualified tables in all non-system keyspaces
+func (d *CassandraDriver) GetTables(ctx context.Context) ([]string, error) {
+	iter := d.session.Query("SELECT keyspace_name, table_name FROM system_schema.tables").WithContext(ctx).Iter()
+
+	var tables []string
+	var keyspace, table string
+	for iter.Scan(&keyspace, &table) {
+		if systemKeyspaces[keyspace] {
+			continue
+		}
+		tables = append(tables, keyspace+"."+table)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, WrapQueryError(err)
+	}
+
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// GetColumns returns column metadata for a table.
+// Partition key columns are reported as PRI, clustering columns as CLU with their order.
+func (d *CassandraDriver) GetColumns(ctx context.Context, tableName string) ([]Column, error) {
+	cols, err := d.schemaColumns(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]Column, 0, len(cols))
+	for _, c := range cols {
+		col := Column{
+			Name:     c.name,
+			Type:     c.dataType,
+			Nullable: c.kind == "regular" || c.kind == "static",
+		}
+		switch c.kind {
+		case "partition_key":
+			col.Key = "PRI"
+		case "clustering":
+			col.Key = "CLU " + strings.ToUpper(c.order)
+		case "static":
+			col.Key = "STATIC"
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// GetConstraints returns the primary key definition and secondary indexes for a table
+func (d *CassandraDriver) GetConstraints(ctx context.Context, tableName string) ([]Constraint, error) {
+	keyspace, table := d.splitTableName(tableName)
+
+	cols, err := d.schemaColumns(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var constraints []Constraint
+	if def := primaryKeyDefinition(cols); def != "" {
+		constraints = append(constraints, Constraint{
+			Name:       table + "_pkey",
+			Type:       "PRIMARY KEY",
+			Definition: def,
+		})
+	}
+
+	iter := d.session.Query(
+		"SELECT index_name, options FROM system_schema.indexes WHERE keyspace_name = ? AND table_name = ?",
+		keyspace, table,
+	).WithContext(ctx).Iter()
+
+	var name string
+	var options map[string]string
+	for iter.Scan(&name, &options) {
+		constraints = append(constraints, Constraint{
+			Name:       name,
+			Type:       "INDEX",
+			Definition: options["target"],
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, WrapQueryError(err)
+	}
+	return constraints, nil
+}
+
+// cqlColumn is a row of system_schema.columns
+type cqlColumn struct {
+	name     string
+	dataType string
+	kind     string // partition_key, clustering, static, regular
+	position int
+	order    string // asc, desc, none
+}
+
+// schemaColumns reads column metadata ordered as the table's primary key declares it
+func (d *CassandraDriver) schemaColumns(ctx context.Context, tableName string) ([]cqlColumn, error) {
+	keyspace, table := d.splitTableName(tableName)
+	iter := d.session.Query(
+		`SELECT column_name, type, kind, position, clustering_order
+		FROM system_schema.columns WHERE keyspace_name = ? AND table_name = ?`,
+		keyspace, table,
+	).WithContext(ctx).Iter()
+
+	var cols []cqlColumn
+	var c cqlColumn
+	for iter.Scan(&c.name, &c.dataType, &c.kind, &c.position, &c.order) {
+		cols = append(cols, c)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, WrapQueryError(err)
+	}
+
+	sortCQLColumns(cols)
+	return cols, nil
+}
+
+// splitTableName splits "keyspace.table", falling back to the session keyspace
+func (d *CassandraDriver) splitTableName(tableName string) (string, string) {
+	if parts := strings.SplitN(tableName, ".", 2); len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return d.keyspace, tableName
+}
+
+// sortCQLColumns orders partition keys, then clustering columns, then the rest by name
+func sortCQLColumns(cols []cqlColumn) {
+	rank := map[string]int{"partition_key": 0, "clustering": 1, "static": 2, "regular": 3}
+	sort.SliceStable(cols, func(i, j int) bool {
+		if rank[cols[i].kind] != rank[cols[j].kind] {
+			return rank[cols[i].kind] < rank[cols[j].kind]
+		}
+		if cols[i].kind == "partition_key" || cols[i].kind == "clustering" {
+			return cols[i].position < cols[j].position
+		}
+		return cols[i].name < cols[j].name
+	})
+}
+
+// primaryKeyDefinition renders PRIMARY KEY ((pk1, pk2), ck1, ck2) from sorted columns
+func primaryKeyDefinition(cols []cqlColumn) string {
+	var partition, clustering []string
+	for _, c := range cols {
+		switch c.kind {
+		case "partition_key":
+			partition = append(partition, c.name)
+		case "clustering":
+			clustering = append(clustering, c.name)
+		}
+	}
+	if len(partition) == 0 {
+		return ""
+	}
+
+	keys := []string{"(" + strings.Join(partition, ", ") + ")"}
+	keys = append(keys, clustering...)
+	return "PRIMARY KEY (" + strings.Join(keys, ", ") + ")"
+}
@@ -0,0 +1,27 @@
+// internal/db/cassandra_test.go
+package db
+
+import "testing"
+
+func TestPrimaryKeyDefinition(t *testing.T) {
+	cols := []cqlColumn{
+		{name: "payload", kind: "regular", position: -1},
+		{name: "ts", kind: "clustering", position: 1, order: "desc"},
+		{name: "bucket", kind: "partition_key", position: 1},
+		{name: "seq", kind: "clustering", position: 0, order: "asc"},
+		{name: "tenant", kind: "partition_key", position: 0},
+	}
+	sortCQLColumns(cols)
+
+	want := []string{"tenant", "bucket", "seq", "ts", "payload"}
+	for i, name := range want {
+		if cols[i].name != name {
+			t.Fatalf("column %d: got %s, want %s", i, cols[i].name, name)
+		}
+	}
+
+	got := primaryKeyDefinition(cols)
+	if got != "PRIMARY KEY ((tenant, bucket), seq, ts)" {
+		t.Fatalf("unexpected primary key definition: %s", got)
+	}
+}
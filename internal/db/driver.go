@@ -4,7 +4,10 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -13,9 +16,11 @@ import (
 type DriverType string
 
 const (
-	Postgres DriverType = "postgres"
-	MySQL    DriverType = "mysql"
-	SQLite   DriverType = "sqlite"
+	Postgres  DriverType = "postgres"
+	MySQL     DriverType = "mysql"
+	SQLite    DriverType = "sqlite"
+	Cassandra DriverType = "cassandra"
+	BigQuery  DriverType = "bigquery"
 )
 
 // Column represents table column metadata
@@ -24,7 +29,7 @@ type Column struct {
 	Type     string
 	Nullable bool
 	Default  string
-	Key      string // PRI, UNI, MUL
+	Key      string // PRI, UNI, MUL (CLU for CQL/BigQuery clustering columns)
 }
 
 // Constraint represents table constraint metadata
@@ -32,6 +37,26 @@ type Constraint struct {
 	Name       string
 	Type       string // PRIMARY KEY, FOREIGN KEY, UNIQUE, etc.
 	Definition string
+
+	// Set for foreign keys: Columns of this table reference RefColumns of RefTable
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
+
+// Function is a user-defined function available to queries
+type Function struct {
+	Name      string // Schema-qualified outside the default schema
+	Arguments string // e.g. "a integer, b text"
+	Returns   string
+}
+
+// splitNames splits a separated list of names, returning nil for an empty list
+func splitNames(list, sep string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, sep)
 }
 
 // ConnectParams holds database connection details
@@ -42,6 +67,16 @@ type ConnectParams struct {
 	Password  string
 	Database  string
 	SSHConfig *SSHConfig // Optional SSH tunnel config
+
+	CredentialsFile string // Service account key (bigquery); empty uses ADC
+
+	SessionStatements []string // Run on every new connection, e.g. SET search_path
+
+	Params string // Extra DSN parameters, key=value pairs separated by &
+
+	ApplicationName string // application_name (postgres) or program_name attribute (mysql)
+
+	ReadOnly bool // Open read-only sessions where the database supports them
 }
 
 // Driver defines the interface for database operations
@@ -56,6 +91,141 @@ type Driver interface {
 	GetConstraints(ctx context.Context, tableName string) ([]Constraint, error)
 }
 
+// Attacher is implemented by drivers that can attach extra database files (SQLite)
+type Attacher interface {
+	Attach(ctx context.Context, path, schema string) (string, error)
+	Detach(ctx context.Context, schema string) error
+	Attachments(ctx context.Context) ([]Attachment, error)
+}
+
+// Transactor is implemented by drivers that can run statements in one
+// transaction. Transaction commits when fn returns nil and rolls back otherwise.
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(exec func(query string) error) error) error
+}
+
+// Copier is implemented by drivers that export tables with the server's
+// bulk copy protocol, which is far faster than reading a SELECT row by row
+type Copier interface {
+	// CopyOut streams the rows of a SELECT query to fn as text, its column
+	// names first; NULL is passed as "NULL"
+	CopyOut(ctx context.Context, query string, fn func(row []string) error) error
+}
+
+// BulkLoader is implemented by drivers that load rows through the server's
+// bulk path (COPY FROM, LOAD DATA) instead of INSERT statements
+type BulkLoader interface {
+	// BulkLoad inserts rows into columns of table in one statement, every
+	// row or none. Values are nil for NULL or text the server parses for the
+	// column type. It returns ErrBulkLoadUnavailable when the server does not
+	// allow it and a *BulkLoadRejectedError when the server skipped rows or
+	// changed values that INSERTs would have failed on.
+	BulkLoad(ctx context.Context, table string, columns []string, rows [][]any) (int64, error)
+}
+
+// ErrBulkLoadUnavailable means the server refused a bulk load; INSERTs still work
+var ErrBulkLoadUnavailable = errors.New("bulk load is not enabled on the server")
+
+// BulkLoadRejectedError is a bulk load rolled back because the server
+// skipped rows, e.g. duplicate keys, or coerced values that did not fit
+type BulkLoadRejectedError struct {
+	Skipped  int64  // Rows the server left out
+	Warnings int64  // Warnings beyond those of the skipped rows, one per coerced value
+	First    string // The first warning, naming its row
+}
+
+func (e *BulkLoadRejectedError) Error() string {
+	msg := fmt.Sprintf("the server skipped %d rows and coerced %d values", e.Skipped, e.Warnings)
+	if e.First != "" {
+		msg += " (" + e.First + ")"
+	}
+	return msg
+}
+
+// FlavorReporter is implemented by drivers that detect a server flavor (e.g. MariaDB)
+type FlavorReporter interface {
+	Flavor() string
+}
+
+// Explainer is implemented by drivers whose EXPLAIN syntax depends on the server
+type Explainer interface {
+	ExplainQuery(query string) string
+}
+
+// CostEstimator is implemented by drivers that can price a query before running it
+type CostEstimator interface {
+	EstimateCost(ctx context.Context, query string) (string, error)
+}
+
+// SchemaSwitcher is implemented by drivers that can change the schema
+// (PostgreSQL search_path) or database (MySQL) of the session
+type SchemaSwitcher interface {
+	Schemas(ctx context.Context) ([]string, error)
+	CurrentSchema(ctx context.Context) (string, error)
+	SwitchSchema(ctx context.Context, name string) error
+}
+
+// Session is a client connection to the server
+type Session struct {
+	ID       string
+	User     string
+	Database string
+	State    string        // e.g. active, idle in transaction, Query, Sleep
+	Duration time.Duration // Time in the current state
+	Query    string        // Running or last query
+}
+
+// ActivityMonitor is implemented by drivers that can list the server's
+// sessions and stop them
+type ActivityMonitor interface {
+	Sessions(ctx context.Context) ([]Session, error)
+	CancelSession(ctx context.Context, id string) error    // Stops the running query
+	TerminateSession(ctx context.Context, id string) error // Closes the connection
+}
+
+// ServerMetrics is a snapshot of server health for the dashboard
+type ServerMetrics struct {
+	Connections    int     // Client connections
+	Active         int     // Connections running a query
+	MaxConnections int     // 0 if unknown
+	CacheHitRatio  float64 // Buffer cache hits per read, -1 if unknown
+
+	LongestQuery         string
+	LongestQueryDuration time.Duration
+
+	Replicating    bool          // A standby, or a primary with replicas
+	ReplicationLag time.Duration // Standby delay, or the largest replica's
+
+	DatabaseSizes []DatabaseSize // Largest first
+}
+
+// DatabaseSize is the on-disk size of a database (MySQL: schema)
+type DatabaseSize struct {
+	Name  string
+	Bytes int64
+}
+
+// MetricsReporter is implemented by drivers that can report server health
+type MetricsReporter interface {
+	Metrics(ctx context.Context) (*ServerMetrics, error)
+}
+
+// FunctionLister is implemented by drivers that can list user-defined functions
+type FunctionLister interface {
+	GetFunctions(ctx context.Context) ([]Function, error)
+}
+
+// Validator is implemented by drivers that can check a query without running it
+type Validator interface {
+	Validate(ctx context.Context, query string) error
+}
+
+// RowEstimator is implemented by drivers whose EXPLAIN estimates how many
+// rows a statement touches
+type RowEstimator interface {
+	EstimateRows(ctx context.Context, query string) (int64, error)
+}
+
 // QueryResult contains query execution results
 type QueryResult struct {
 	Columns      []string
@@ -64,6 +234,10 @@ type QueryResult struct {
 	RowCount     int
 	IsSelect     bool
 	AffectedRows int64
+	Timing       Timing // ExecTime split into phases, where the driver can tell
+	// Spill holds the rows past Rows when the result outgrew the memory cap
+	// of its query; RowCount counts both
+	Spill *Spill
 }
 
 // NewDriver creates a new driver instance by type
@@ -75,11 +249,104 @@ func NewDriver(driverType DriverType) (Driver, error) {
 		return &MySQLDriver{}, nil
 	case SQLite:
 		return &SQLiteDriver{}, nil
+	case Cassandra:
+		return &CassandraDriver{}, nil
+	case BigQuery:
+		return &BigQueryDriver{}, nil
 	default:
 		return nil, fmt.Errorf("unknown driver type: %s", driverType)
 	}
 }
 
+// ServerVersion returns the server version string, or "" if it cannot be determined
+func ServerVersion(ctx context.Context, d Driver) string {
+	var query string
+	switch d.Type() {
+	case Postgres:
+		query = "SELECT version()"
+	case MySQL:
+		query = "SELECT VERSION()"
+	case SQLite:
+		query = "SELECT sqlite_version()"
+	case Cassandra:
+		query = "SELECT release_version FROM system.local"
+	default:
+		return ""
+	}
+
+	result, err := d.Execute(ctx, query)
+	if err != nil || len(result.Rows) == 0 || len(result.Rows[0]) == 0 {
+		return ""
+	}
+	return result.Rows[0][0]
+}
+
+// prepareOnly has the server parse and plan query without running it
+func prepareOnly(ctx context.Context, db *sql.DB, query string) error {
+	if db == nil {
+		return WrapConnectionError(fmt.Errorf("not connected"))
+	}
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	return stmt.Close()
+}
+
+// planRowsPattern finds row estimates in text plans: PostgreSQL's "rows=N"
+// and CockroachDB's "estimated row count: N"
+var planRowsPattern = regexp.MustCompile(`(?:rows=|estimated row count: )([\d,]+)`)
+
+// explainRows runs EXPLAIN, which does not execute the statement, and returns
+// the largest row estimate in the plan
+func explainRows(ctx context.Context, db *sql.DB, query string) (int64, error) {
+	result, err := executeQuery(ctx, db, "EXPLAIN "+query)
+	if err != nil {
+		return 0, err
+	}
+	var most int64 = -1
+	for _, row := range result.Rows {
+		for i, cell := range row {
+			// MySQL's rows and TiDB's estRows columns
+			if col := strings.ToLower(result.Columns[i]); col == "rows" || col == "estrows" {
+				if n, err := strconv.ParseFloat(cell, 64); err == nil {
+					most = max(most, int64(n))
+				}
+				continue
+			}
+			for _, m := range planRowsPattern.FindAllStringSubmatch(cell, -1) {
+				if n, err := strconv.ParseInt(strings.ReplaceAll(m[1], ",", ""), 10, 64); err == nil {
+					most = max(most, n)
+				}
+			}
+		}
+	}
+	if most < 0 {
+		return 0, fmt.Errorf("EXPLAIN reported no row estimate")
+	}
+	return most, nil
+}
+
+// transaction runs fn against a transaction of db
+func transaction(ctx context.Context, db *sql.DB, fn func(exec func(query string) error) error) error {
+	if db == nil {
+		return WrapConnectionError(fmt.Errorf("not connected"))
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = fn(func(query string) error {
+		_, err := tx.ExecContext(ctx, query)
+		return err
+	})
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
 // executeQuery executes a query and returns results
 func executeQuery(ctx context.Context, db *sql.DB, query string) (*QueryResult, error) {
 	start := time.Now()
@@ -101,9 +368,10 @@ func executeSelect(ctx context.Context, db *sql.DB, query string, start time.Tim
 		return nil, WrapQueryError(err)
 	}
 	defer rows.Close()
+	fetchStart := time.Now()
 
 	columns, _ := rows.Columns()
-	var results [][]string
+	collected := newRowCollector(ctx)
 
 	for rows.Next() {
 		values := make([]interface{}, len(columns))
@@ -112,6 +380,7 @@ func executeSelect(ctx context.Context, db *sql.DB, query string, start time.Tim
 			valuePtrs[i] = &values[i]
 		}
 		if err := rows.Scan(valuePtrs...); err != nil {
+			collected.discard()
 			return nil, WrapQueryError(err)
 		}
 
@@ -119,20 +388,23 @@ func executeSelect(ctx context.Context, db *sql.DB, query string, start time.Tim
 		for i, v := range values {
 			row[i] = formatValue(v)
 		}
-		results = append(results, row)
+		if err := collected.add(row); err != nil {
+			collected.discard()
+			return nil, err
+		}
 	}
 
 	if err := rows.Err(); err != nil {
+		collected.discard()
 		return nil, WrapQueryError(err)
 	}
 
-	return &QueryResult{
-		Columns:  columns,
-		Rows:     results,
-		ExecTime: time.Since(start),
-		RowCount: len(results),
-		IsSelect: true,
-	}, nil
+	res, err := collected.result(columns, start)
+	if err != nil {
+		return nil, err
+	}
+	res.Timing = Timing{Execute: fetchStart.Sub(start), Fetch: time.Since(fetchStart)}
+	return res, nil
 }
 
 // executeDML executes INSERT/UPDATE/DELETE queries
@@ -0,0 +1,30 @@
+// internal/db/dsn.go
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// isSocket reports whether host is the path of a unix socket (MySQL) or of
+// the directory holding one (PostgreSQL)
+func isSocket(host string) bool {
+	return strings.HasPrefix(host, "/")
+}
+
+// splitParams parses extra DSN parameters written as key=value pairs
+// separated by &, e.g. "application_name=ezdb&sslmode=disable"
+func splitParams(s string) ([][2]string, error) {
+	var pairs [][2]string
+	for _, p := range strings.Split(s, "&") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(p, "=")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			return nil, fmt.Errorf("invalid connection parameter %q, want key=value", p)
+		}
+		pairs = append(pairs, [2]string{key, strings.TrimSpace(value)})
+	}
+	return pairs, nil
+}
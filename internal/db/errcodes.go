@@ -0,0 +1,177 @@
+// internal/db/errcodes.go
+// Bundled documentation hints for common error codes of each dialect, looked up from a failed query's error message.
+package db
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ErrorHint documents an error code of a dialect
+type ErrorHint struct {
+	Code   string // e.g. "42P01", "1146"; empty for SQLite, which has no codes in messages
+	Name   string // Condition name, e.g. "undefined_table"
+	Hint   string // What usually causes it
+	DocURL string
+}
+
+const (
+	postgresErrorDocs = "https://www.postgresql.org/docs/current/errcodes-appendix.html"
+	mysqlErrorDocs    = "https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html"
+	sqliteErrorDocs   = "https://www.sqlite.org/rescode.html"
+)
+
+var (
+	sqlstatePattern   = regexp.MustCompile(`\(SQLSTATE ([0-9A-Z]{5})\)`)
+	mysqlErrorPattern = regexp.MustCompile(`Error (\d{4,5})\b`)
+)
+
+type codeHint struct{ name, hint string }
+
+// postgresCodes are the SQLSTATEs most queries run into
+var postgresCodes = map[string]codeHint{
+	"08006": {"connection_failure", "The connection dropped; check the server is up and reconnect."},
+	"0A000": {"feature_not_supported", "The server does not support this statement or option in this form."},
+	"21000": {"cardinality_violation", "A subquery used as a value returned more than one row; add a WHERE, LIMIT 1 or an aggregate."},
+	"22001": {"string_data_right_truncation", "A value is longer than its column allows; shorten it or widen the column."},
+	"22003": {"numeric_value_out_of_range", "A number does not fit its column type; use a wider type or check the value."},
+	"22007": {"invalid_datetime_format", "A date or time literal is not in a format the server reads; use ISO 8601, e.g. '2024-01-31 12:00'."},
+	"22008": {"datetime_field_overflow", "A date or time field is out of range, e.g. month 13 or the wrong DateStyle."},
+	"22012": {"division_by_zero", "A divisor is zero; guard it with NULLIF(divisor, 0)."},
+	"22P02": {"invalid_text_representation", "A literal cannot be read as the column's type, e.g. 'abc' for an integer or a malformed UUID."},
+	"23502": {"not_null_violation", "A NOT NULL column got no value; supply one or give the column a default."},
+	"23503": {"foreign_key_violation", "The referenced row does not exist, or other rows still reference the one being deleted."},
+	"23505": {"unique_violation", "A row with this key already exists; update it instead or use INSERT ... ON CONFLICT."},
+	"23514": {"check_violation", "A value fails a CHECK constraint of the table."},
+	"25P02": {"in_failed_sql_transaction", "An earlier statement of the transaction failed; ROLLBACK before running more."},
+	"28P01": {"invalid_password", "The password of the profile is wrong."},
+	"3D000": {"invalid_catalog_name", "The database does not exist; check the profile's database name."},
+	"3F000": {"invalid_schema_name", "The schema does not exist; check its name or the search_path."},
+	"40001": {"serialization_failure", "A concurrent transaction conflicted with this one; run it again."},
+	"40P01": {"deadlock_detected", "Two transactions waited on each other; run it again and lock rows in a consistent order."},
+	"42501": {"insufficient_privilege", "The role lacks a privilege on the object; ask for a GRANT."},
+	"42601": {"syntax_error", "The statement does not parse; look near the quoted token for a typo, missing comma or reserved word."},
+	"42702": {"ambiguous_column", "A column name exists in several joined tables; qualify it with the table or alias."},
+	"42703": {"undefined_column", "The column does not exist; check its spelling, its table alias, and quote mixed-case names."},
+	"42704": {"undefined_object", "A type, index or other object named in the statement does not exist."},
+	"42710": {"duplicate_object", "An object with this name already exists."},
+	"42723": {"duplicate_function", "A function with this name and argument types already exists; use CREATE OR REPLACE."},
+	"42803": {"grouping_error", "A selected column is neither in GROUP BY nor inside an aggregate."},
+	"42804": {"datatype_mismatch", "An expression has the wrong type for where it is used; add a cast."},
+	"42830": {"invalid_foreign_key", "The referenced columns have no unique constraint or primary key."},
+	"42846": {"cannot_coerce", "There is no cast between these two types."},
+	"42883": {"undefined_function", "No function or operator matches these argument types; add explicit casts."},
+	"42P01": {"undefined_table", "The table does not exist; check its spelling, the schema and the search_path, and quote mixed-case names."},
+	"42P07": {"duplicate_table", "A table with this name already exists; use CREATE TABLE IF NOT EXISTS."},
+	"53300": {"too_many_connections", "The server has no free connections; close idle sessions or raise max_connections."},
+	"55P03": {"lock_not_available", "A lock could not be taken in time; another session holds it."},
+	"57014": {"query_canceled", "The statement was cancelled, by the user or by statement_timeout."},
+	"57P01": {"admin_shutdown", "The server is shutting down or ended the session; reconnect."},
+}
+
+// postgresClasses names the SQLSTATE classes, the first two characters of a
+// code, for codes not listed in postgresCodes
+var postgresClasses = map[string]codeHint{
+	"08": {"connection_exception", "The connection to the server failed."},
+	"22": {"data_exception", "A value is invalid for its type or operation."},
+	"23": {"integrity_constraint_violation", "The change breaks a constraint of the table."},
+	"25": {"invalid_transaction_state", "The statement is not allowed in the current transaction state."},
+	"28": {"invalid_authorization_specification", "The server rejected the credentials of the profile."},
+	"40": {"transaction_rollback", "The transaction was rolled back; run it again."},
+	"42": {"syntax_error_or_access_rule_violation", "The statement is invalid or refers to something that does not exist or is not allowed."},
+	"53": {"insufficient_resources", "The server ran out of a resource such as disk, memory or connections."},
+	"54": {"program_limit_exceeded", "The statement exceeds a server limit, e.g. too many columns or too deep a nesting."},
+	"55": {"object_not_in_prerequisite_state", "The object is in use or not in a state the statement needs."},
+	"57": {"operator_intervention", "The statement was cancelled or the server is shutting down."},
+	"XX": {"internal_error", "The server hit an internal error; check its log."},
+}
+
+// mysqlCodes are the MySQL and MariaDB server errors most queries run into
+var mysqlCodes = map[string]codeHint{
+	"1005": {"ER_CANT_CREATE_TABLE", "The table could not be created, often because of an invalid foreign key."},
+	"1044": {"ER_DBACCESS_DENIED_ERROR", "The user has no access to this database."},
+	"1045": {"ER_ACCESS_DENIED_ERROR", "The user or password of the profile is wrong."},
+	"1046": {"ER_NO_DB_ERROR", "No database is selected; set one in the profile or qualify the table with it."},
+	"1048": {"ER_BAD_NULL_ERROR", "A NOT NULL column got NULL; supply a value."},
+	"1049": {"ER_BAD_DB_ERROR", "The database does not exist."},
+	"1050": {"ER_TABLE_EXISTS_ERROR", "A table with this name already exists; use CREATE TABLE IF NOT EXISTS."},
+	"1052": {"ER_NON_UNIQ_ERROR", "A column name exists in several joined tables; qualify it with the table or alias."},
+	"1054": {"ER_BAD_FIELD_ERROR", "The column does not exist; check its spelling and table alias."},
+	"1055": {"ER_WRONG_FIELD_WITH_GROUP", "A selected column is neither in GROUP BY nor inside an aggregate (ONLY_FULL_GROUP_BY)."},
+	"1060": {"ER_DUP_FIELDNAME", "The column name is used twice."},
+	"1062": {"ER_DUP_ENTRY", "A row with this key already exists; update it instead or use INSERT ... ON DUPLICATE KEY UPDATE."},
+	"1064": {"ER_PARSE_ERROR", "The statement does not parse; look at the text after \"near\" for a typo, missing comma or reserved word."},
+	"1136": {"ER_WRONG_VALUE_COUNT_ON_ROW", "The number of values does not match the number of columns."},
+	"1142": {"ER_TABLEACCESS_DENIED_ERROR", "The user lacks a privilege on the table; ask for a GRANT."},
+	"1146": {"ER_NO_SUCH_TABLE", "The table does not exist; check its spelling and the selected database."},
+	"1205": {"ER_LOCK_WAIT_TIMEOUT", "A row lock was not released in time; another session holds it."},
+	"1213": {"ER_LOCK_DEADLOCK", "Two transactions waited on each other; run it again."},
+	"1216": {"ER_NO_REFERENCED_ROW", "The referenced row does not exist."},
+	"1242": {"ER_SUBQUERY_NO_1_ROW", "A subquery used as a value returned more than one row; add a WHERE, LIMIT 1 or an aggregate."},
+	"1264": {"ER_WARN_DATA_OUT_OF_RANGE", "A number does not fit its column type."},
+	"1292": {"ER_TRUNCATED_WRONG_VALUE", "A value cannot be read as the column's type, e.g. a malformed date."},
+	"1364": {"ER_NO_DEFAULT_FOR_FIELD", "A column without a default got no value; supply one."},
+	"1366": {"ER_TRUNCATED_WRONG_VALUE_FOR_FIELD", "A value is invalid for the column, often a character set mismatch."},
+	"1406": {"ER_DATA_TOO_LONG", "A value is longer than its column allows."},
+	"1451": {"ER_ROW_IS_REFERENCED_2", "Other rows still reference the one being deleted or changed."},
+	"1452": {"ER_NO_REFERENCED_ROW_2", "The referenced row does not exist."},
+	"3024": {"ER_QUERY_TIMEOUT", "The statement exceeded max_execution_time."},
+}
+
+// sqliteMessages map text of SQLite error messages, which carry no codes,
+// to hints
+var sqliteMessages = []struct {
+	text string
+	codeHint
+}{
+	{"no such table", codeHint{"SQLITE_ERROR", "The table does not exist; check its spelling and which database is attached."}},
+	{"no such column", codeHint{"SQLITE_ERROR", "The column does not exist; check its spelling and table alias."}},
+	{"no such function", codeHint{"SQLITE_ERROR", "SQLite has no function of this name; it may be specific to another dialect."}},
+	{"ambiguous column name", codeHint{"SQLITE_ERROR", "A column name exists in several joined tables; qualify it with the table or alias."}},
+	{`near "`, codeHint{"SQLITE_ERROR", "The statement does not parse; look at the quoted token for a typo, missing comma or reserved word."}},
+	{"already exists", codeHint{"SQLITE_ERROR", "A table or index with this name already exists; use IF NOT EXISTS."}},
+	{"UNIQUE constraint failed", codeHint{"SQLITE_CONSTRAINT_UNIQUE", "A row with this key already exists; use INSERT OR REPLACE or ON CONFLICT."}},
+	{"NOT NULL constraint failed", codeHint{"SQLITE_CONSTRAINT_NOTNULL", "A NOT NULL column got no value; supply one."}},
+	{"FOREIGN KEY constraint failed", codeHint{"SQLITE_CONSTRAINT_FOREIGNKEY", "The referenced row does not exist, or other rows still reference the one being deleted."}},
+	{"CHECK constraint failed", codeHint{"SQLITE_CONSTRAINT_CHECK", "A value fails a CHECK constraint of the table."}},
+	{"datatype mismatch", codeHint{"SQLITE_MISMATCH", "A value has the wrong type, e.g. a non-integer for an INTEGER PRIMARY KEY."}},
+	{"database is locked", codeHint{"SQLITE_BUSY", "Another connection is writing the file; retry once it is done."}},
+	{"attempt to write a readonly database", codeHint{"SQLITE_READONLY", "The file or its directory is not writable, or it was opened read-only."}},
+	{"unable to open database file", codeHint{"SQLITE_CANTOPEN", "The file or its directory does not exist or is not readable."}},
+}
+
+// ExplainError looks up the documentation hint for an error message of
+// dialect dt
+func ExplainError(dt DriverType, message string) (ErrorHint, bool) {
+	switch dt {
+	case Postgres:
+		m := sqlstatePattern.FindStringSubmatch(message)
+		if m == nil {
+			return ErrorHint{}, false
+		}
+		h, ok := postgresCodes[m[1]]
+		if !ok {
+			if h, ok = postgresClasses[m[1][:2]]; !ok {
+				return ErrorHint{}, false
+			}
+		}
+		return ErrorHint{Code: m[1], Name: h.name, Hint: h.hint, DocURL: postgresErrorDocs}, true
+	case MySQL:
+		m := mysqlErrorPattern.FindStringSubmatch(message)
+		if m == nil {
+			return ErrorHint{}, false
+		}
+		h, ok := mysqlCodes[m[1]]
+		if !ok {
+			return ErrorHint{}, false
+		}
+		return ErrorHint{Code: m[1], Name: h.name, Hint: h.hint, DocURL: mysqlErrorDocs}, true
+	case SQLite:
+		for _, s := range sqliteMessages {
+			if strings.Contains(message, s.text) {
+				return ErrorHint{Name: s.name, Hint: s.hint, DocURL: sqliteErrorDocs}, true
+			}
+		}
+	}
+	return ErrorHint{}, false
+}
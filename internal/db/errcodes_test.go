@@ -0,0 +1,31 @@
+// internal/db/errcodes_test.go
+package db
+
+import "testing"
+
+func TestExplainError(t *testing.T) {
+	tests := []struct {
+		dt      DriverType
+		message string
+		code    string
+		name    string
+	}{
+		{Postgres, `query failed: ERROR: relation "itemz" does not exist (SQLSTATE 42P01)`, "42P01", "undefined_table"},
+		{Postgres, `ERROR: invalid escape string (SQLSTATE 22025)`, "22025", "data_exception"},
+		{MySQL, `query failed: Error 1146 (42S02): Table 'shop.itemz' doesn't exist`, "1146", "ER_NO_SUCH_TABLE"},
+		{SQLite, `query failed: no such column: nme`, "", "SQLITE_ERROR"},
+		{SQLite, `UNIQUE constraint failed: items.id`, "", "SQLITE_CONSTRAINT_UNIQUE"},
+	}
+	for _, tt := range tests {
+		h, ok := ExplainError(tt.dt, tt.message)
+		if !ok || h.Code != tt.code || h.Name != tt.name || h.Hint == "" || h.DocURL == "" {
+			t.Errorf("ExplainError(%s, %q) = %+v, %v; want %s %s", tt.dt, tt.message, h, ok, tt.code, tt.name)
+		}
+	}
+
+	for _, msg := range []string{"pq: relation does not exist", "ERROR: odd (SQLSTATE ZZ999)"} {
+		if h, ok := ExplainError(Postgres, msg); ok {
+			t.Errorf("ExplainError(postgres, %q) = %+v, want no hint", msg, h)
+		}
+	}
+}
@@ -1,7 +1,17 @@
 // internal/db/errors.go
 package db
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
 
 // ConnectionError wraps database connection failures
 type ConnectionError struct {
@@ -21,6 +31,10 @@ func (e *QueryError) Error() string {
 	return fmt.Sprintf("query failed: %v", e.Underlying)
 }
 
+func (e *QueryError) Unwrap() error {
+	return e.Underlying
+}
+
 // WrapConnectionError creates a ConnectionError from underlying error
 func WrapConnectionError(err error) error {
 	return &ConnectionError{Underlying: err}
@@ -30,3 +44,71 @@ func WrapConnectionError(err error) error {
 func WrapQueryError(err error) error {
 	return &QueryError{Underlying: err}
 }
+
+// SyntaxError is a problem found in a query before running it
+type SyntaxError struct {
+	Offset  int // Byte offset in the query, -1 when unknown
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return e.Message
+}
+
+// ErrorDetail is a query error broken into the parts drivers report
+type ErrorDetail struct {
+	Code     string // SQLSTATE or MySQL error number, "" when the driver has none
+	Detail   string
+	Hint     string
+	Position int // 1-based character position in the query, 0 when unknown
+}
+
+// DescribeError breaks err, returned by running query, into parts. Postgres
+// reports all of them; for MySQL and SQLite the position is known only when
+// the message quotes the text the parser failed near.
+func DescribeError(query string, err error) ErrorDetail {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return ErrorDetail{Code: pgErr.Code, Detail: pgErr.Detail, Hint: pgErr.Hint, Position: int(pgErr.Position)}
+	}
+	var d ErrorDetail
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		d.Code = strconv.Itoa(int(myErr.Number))
+	}
+	if offset := nearOffset(query, err.Error()); offset >= 0 {
+		d.Position = utf8.RuneCountInString(query[:offset]) + 1
+	}
+	return d
+}
+
+var (
+	sqliteNearPattern = regexp.MustCompile(`near "([^"]*)"`)
+	mysqlNearPattern  = regexp.MustCompile(`(?s)near '(.*)' at line \d+$`)
+)
+
+// nearOffset locates the text an error message quotes after "near" in query,
+// or returns -1
+func nearOffset(query, message string) int {
+	m := sqliteNearPattern.FindStringSubmatch(message)
+	if m == nil {
+		m = mysqlNearPattern.FindStringSubmatch(message)
+	}
+	if m == nil {
+		return -1
+	}
+	if m[1] == "" {
+		return len(query)
+	}
+	return strings.Index(query, m[1])
+}
+
+// runeOffset converts a character offset in s to a byte offset
+func runeOffset(s string, chars int) int {
+	offset := 0
+	for i := 0; i < chars && offset < len(s); i++ {
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		offset += size
+	}
+	return offset
+}
@@ -0,0 +1,32 @@
+// internal/db/errors_test.go
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestDescribeError(t *testing.T) {
+	pg := WrapQueryError(&pgconn.PgError{Severity: "ERROR", Code: "42703", Message: `column "nme" does not exist`,
+		Hint: `Perhaps you meant to reference the column "items.name".`, Position: 8})
+	if got := DescribeError("SELECT nme FROM items", pg); got.Code != "42703" || got.Position != 8 || got.Hint == "" {
+		t.Errorf("postgres: %+v", got)
+	}
+
+	my := WrapQueryError(&mysql.MySQLError{Number: 1064, Message: "You have an error in your SQL syntax; check the manual " +
+		"that corresponds to your MySQL server version for the right syntax to use near 'FORM items' at line 1"})
+	if got := DescribeError("SELECT é FORM items", my); got.Code != "1064" || got.Position != 10 {
+		t.Errorf("mysql: %+v", got)
+	}
+
+	sqlite := WrapQueryError(errors.New(`near "SELEC": syntax error`))
+	if got := DescribeError("SELEC 1", sqlite); got != (ErrorDetail{Position: 1}) {
+		t.Errorf("sqlite: %+v", got)
+	}
+	if got := DescribeError("SELECT 1", errors.New("connection reset")); got != (ErrorDetail{}) {
+		t.Errorf("plain error: %+v", got)
+	}
+}
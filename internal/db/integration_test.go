@@ -0,0 +1,281 @@
+//go:build integration
+
+// internal/db/integration_test.go
+// Driver tests against real PostgreSQL and MySQL servers started with Docker.
+// Run with: go test -tags integration ./internal/db/
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readyTimeout bounds how long a fresh container may take to accept connections
+const readyTimeout = 2 * time.Minute
+
+// docker runs the docker CLI and returns its trimmed output
+func docker(t *testing.T, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("docker", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("docker %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// requireDocker skips the test when no Docker daemon is reachable
+func requireDocker(t *testing.T) {
+	t.Helper()
+	if err := exec.Command("docker", "info").Run(); err != nil {
+		t.Skipf("docker is not available: %v", err)
+	}
+}
+
+// dockerNetwork creates a network removed when the test ends
+func dockerNetwork(t *testing.T) string {
+	name := fmt.Sprintf("ezdb-it-%d", time.Now().UnixNano())
+	docker(t, "network", "create", name)
+	t.Cleanup(func() { exec.Command("docker", "network", "rm", name).Run() })
+	return name
+}
+
+// runContainer starts a container with its exposed ports published and
+// removes it when the test ends. args go before the image, e.g. -e FOO=1.
+func runContainer(t *testing.T, image string, args ...string) string {
+	run := append([]string{"run", "-d", "--rm", "-P"}, args...)
+	id := docker(t, append(run, image)...)
+	t.Cleanup(func() { exec.Command("docker", "rm", "-f", id).Run() })
+	return id
+}
+
+// hostPort returns the local port a container port is published on
+func hostPort(t *testing.T, id string, port int) int {
+	out := docker(t, "port", id, fmt.Sprintf("%d/tcp", port))
+	// One line per address, e.g. "0.0.0.0:32768"
+	line := strings.SplitN(out, "\n", 2)[0]
+	n, err := strconv.Atoi(line[strings.LastIndex(line, ":")+1:])
+	if err != nil {
+		t.Fatalf("port of %s: %q", id, out)
+	}
+	return n
+}
+
+// connectWhenReady connects a new driver of driverType with params,
+// retrying while the server starts up
+func connectWhenReady(t *testing.T, driverType DriverType, params ConnectParams) Driver {
+	t.Helper()
+	deadline := time.Now().Add(readyTimeout)
+	for {
+		d, err := NewDriver(driverType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = d.Connect(params); err == nil {
+			t.Cleanup(func() { d.Close() })
+			return d
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s never accepted connections: %v", driverType, err)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// startPostgres starts a PostgreSQL server, on network when it is not ""
+func startPostgres(t *testing.T, network, alias string) string {
+	args := []string{"-e", "POSTGRES_PASSWORD=secret", "-e", "POSTGRES_DB=ezdb"}
+	if network != "" {
+		args = append(args, "--network", network, "--network-alias", alias)
+	}
+	return runContainer(t, "postgres:16-alpine", args...)
+}
+
+// exerciseDriver creates a small schema and checks the driver reads it back.
+// prefix qualifies table names the way the driver lists them.
+func exerciseDriver(t *testing.T, d Driver, prefix string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	for _, stmt := range []string{
+		"CREATE TABLE customers (id INT PRIMARY KEY, email VARCHAR(100) NOT NULL UNIQUE)",
+		"CREATE TABLE orders (id INT PRIMARY KEY, customer_id INT NOT NULL, total DECIMAL(10,2), FOREIGN KEY (customer_id) REFERENCES customers (id))",
+	} {
+		if _, err := d.Execute(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	res, err := d.Execute(ctx, "INSERT INTO customers (id, email) VALUES (1, 'a@example.com'), (2, 'b@example.com')")
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if res.IsSelect || res.AffectedRows != 2 {
+		t.Errorf("insert result = %+v, want 2 affected rows", res)
+	}
+	if _, err := d.Execute(ctx, "INSERT INTO orders (id, customer_id, total) VALUES (10, 1, 9.50)"); err != nil {
+		t.Fatalf("insert order: %v", err)
+	}
+	if _, err := d.Execute(ctx, "INSERT INTO orders (id, customer_id, total) VALUES (11, 99, 1)"); err == nil {
+		t.Error("insert violating the foreign key succeeded")
+	}
+
+	res, err = d.Execute(ctx, "SELECT id, email FROM customers ORDER BY id")
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if !res.IsSelect || res.RowCount != 2 || !slices.Equal(res.Columns, []string{"id", "email"}) || res.Rows[1][1] != "b@example.com" {
+		t.Errorf("select result = %+v", res)
+	}
+
+	tables, err := d.GetTables(ctx)
+	if err != nil {
+		t.Fatalf("GetTables: %v", err)
+	}
+	for _, name := range []string{"customers", "orders"} {
+		if !slices.Contains(tables, prefix+name) {
+			t.Errorf("GetTables = %v, missing %s", tables, prefix+name)
+		}
+	}
+
+	columns, err := d.GetColumns(ctx, prefix+"orders")
+	if err != nil {
+		t.Fatalf("GetColumns: %v", err)
+	}
+	var names []string
+	for _, c := range columns {
+		names = append(names, c.Name)
+	}
+	if !slices.Equal(names, []string{"id", "customer_id", "total"}) {
+		t.Errorf("columns = %v", names)
+	}
+	if len(columns) == 3 && (columns[0].Key != "PRI" || columns[1].Nullable || !columns[2].Nullable) {
+		t.Errorf("column details = %+v", columns)
+	}
+
+	constraints, err := d.GetConstraints(ctx, prefix+"orders")
+	if err != nil {
+		t.Fatalf("GetConstraints: %v", err)
+	}
+	var fk *Constraint
+	for i, c := range constraints {
+		if c.Type == "FOREIGN KEY" {
+			fk = &constraints[i]
+		}
+	}
+	if fk == nil {
+		t.Fatalf("constraints = %+v, want a foreign key", constraints)
+	}
+	if fk.RefTable != prefix+"customers" || !slices.Equal(fk.Columns, []string{"customer_id"}) || !slices.Equal(fk.RefColumns, []string{"id"}) {
+		t.Errorf("foreign key = %+v", fk)
+	}
+
+	if err := d.Ping(ctx); err != nil {
+		t.Errorf("ping: %v", err)
+	}
+}
+
+func TestPostgresIntegration(t *testing.T) {
+	requireDocker(t)
+	id := startPostgres(t, "", "")
+	d := connectWhenReady(t, Postgres, ConnectParams{
+		Host: "127.0.0.1", Port: hostPort(t, id, 5432), User: "postgres", Password: "secret", Database: "ezdb",
+	})
+	exerciseDriver(t, d, "public.")
+	exerciseCopier(t, d.(Copier), d.(BulkLoader))
+}
+
+// exerciseCopier loads awkward values into a table and copies them back out
+func exerciseCopier(t *testing.T, c Copier, l BulkLoader) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	rows := [][]any{
+		{"1", "plain"},
+		{"2", nil},
+		{"3", "comma, \"quote\"\nand newline"},
+		{"4", ""},
+	}
+	if _, err := c.(Driver).Execute(ctx, "CREATE TABLE notes (id INT PRIMARY KEY, body TEXT)"); err != nil {
+		t.Fatalf("create notes: %v", err)
+	}
+	if n, err := l.BulkLoad(ctx, "notes", []string{"id", "body"}, rows); err != nil || n != 4 {
+		t.Fatalf("BulkLoad = %d, %v, want 4 rows", n, err)
+	}
+
+	var out [][]string
+	err := c.CopyOut(ctx, "SELECT * FROM notes ORDER BY id", func(row []string) error {
+		out = append(out, row)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("CopyOut: %v", err)
+	}
+	want := [][]string{{"id", "body"}, {"1", "plain"}, {"2", "NULL"}, {"3", "comma, \"quote\"\nand newline"}, {"4", ""}}
+	if !slices.EqualFunc(out, want, slices.Equal[[]string]) {
+		t.Errorf("CopyOut rows = %q, want %q", out, want)
+	}
+}
+
+func TestMySQLIntegration(t *testing.T) {
+	requireDocker(t)
+	id := runContainer(t, "mysql:8.4", "-e", "MYSQL_ROOT_PASSWORD=secret", "-e", "MYSQL_DATABASE=ezdb")
+	d := connectWhenReady(t, MySQL, ConnectParams{
+		Host: "127.0.0.1", Port: hostPort(t, id, 3306), User: "root", Password: "secret", Database: "ezdb",
+	})
+	exerciseDriver(t, d, "")
+
+	// MySQL 8 ships with local_infile off, so imports fall back to INSERTs
+	rows := [][]any{{"3", "c@example.com"}}
+	if _, err := d.(BulkLoader).BulkLoad(context.Background(), "customers", []string{"id", "email"}, rows); !errors.Is(err, ErrBulkLoadUnavailable) {
+		t.Errorf("BulkLoad with local_infile off = %v, want ErrBulkLoadUnavailable", err)
+	}
+
+	// LOAD DATA skips duplicates and truncates long values where INSERT
+	// fails, so such loads are rolled back
+	ctx := context.Background()
+	if _, err := d.Execute(ctx, "SET GLOBAL local_infile = 1"); err != nil {
+		t.Fatalf("enable local_infile: %v", err)
+	}
+	for name, rows := range map[string][][]any{
+		"duplicate key": {{"3", "c@example.com"}, {"1", "dup@example.com"}},
+		"long value":    {{"3", strings.Repeat("x", 101)}},
+	} {
+		var rejected *BulkLoadRejectedError
+		if _, err := d.(BulkLoader).BulkLoad(ctx, "customers", []string{"id", "email"}, rows); !errors.As(err, &rejected) {
+			t.Errorf("BulkLoad with a %s = %v, want a BulkLoadRejectedError", name, err)
+		}
+	}
+	if res, err := d.Execute(ctx, "SELECT COUNT(*) FROM customers"); err != nil || res.Rows[0][0] != "2" {
+		t.Errorf("customers after rejected loads = %v, %v, want 2 rows", res, err)
+	}
+	if n, err := d.(BulkLoader).BulkLoad(ctx, "customers", []string{"id", "email"}, [][]any{{"3", "c@example.com"}}); err != nil || n != 1 {
+		t.Errorf("BulkLoad = %d, %v, want 1 row", n, err)
+	}
+}
+
+// TestSSHTunnelIntegration reaches a PostgreSQL server that is only on a
+// Docker network through an SSH server on the same network
+func TestSSHTunnelIntegration(t *testing.T) {
+	requireDocker(t)
+	t.Setenv("SSH_AUTH_SOCK", "") // Authenticate with the password only
+
+	network := dockerNetwork(t)
+	startPostgres(t, network, "pg")
+	bastion := runContainer(t, "linuxserver/openssh-server:latest",
+		"--network", network,
+		"-e", "USER_NAME=ezdb", "-e", "USER_PASSWORD=secret", "-e", "PASSWORD_ACCESS=true",
+		"-e", "DOCKER_MODS=linuxserver/mods:openssh-server-ssh-tunnel")
+
+	d := connectWhenReady(t, Postgres, ConnectParams{
+		Host: "pg", Port: 5432, User: "postgres", Password: "secret", Database: "ezdb",
+		SSHConfig: &SSHConfig{Host: "127.0.0.1", Port: hostPort(t, bastion, 2222), User: "ezdb", Password: "secret"},
+	})
+	exerciseDriver(t, d, "public.")
+}
@@ -4,24 +4,93 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
 )
 
-// MySQLDriver implements Driver for MySQL
+// MySQL-compatible server flavors, detected from VERSION()
+const (
+	FlavorMySQL   = "MySQL"
+	FlavorMariaDB = "MariaDB"
+	FlavorTiDB    = "TiDB"
+)
+
+// MySQLDriver implements Driver for MySQL and compatible servers (MariaDB, TiDB)
 type MySQLDriver struct {
 	db      *sql.DB
 	tunnel  *SSHTunnel
 	netName string // Registered network name for SSH
+	version string // Server VERSION()
+	flavor  string // FlavorMySQL, FlavorMariaDB or FlavorTiDB
+	session *sessionInit
+}
+
+// detectMySQLFlavor derives the server flavor from a VERSION() string,
+// e.g. "10.11.6-MariaDB-1" or "8.0.11-TiDB-v7.5.0"
+func detectMySQLFlavor(version string) string {
+	v := strings.ToLower(version)
+	switch {
+	case strings.Contains(v, "tidb"):
+		return FlavorTiDB
+	case strings.Contains(v, "mariadb"):
+		return FlavorMariaDB
+	default:
+		return FlavorMySQL
+	}
+}
+
+// mysqlDSN builds the DSN, tagging the connection with a program_name
+// attribute unless the extra parameters set connection attributes
+func mysqlDSN(params ConnectParams, protocol, address string, extra [][2]string) string {
+	// Build DSN: user:password@protocol(address)/dbname?param=value
+	dsn := fmt.Sprintf("%s:%s@%s(%s)/%s",
+		params.User,
+		params.Password,
+		protocol,
+		address,
+		params.Database,
+	)
+	var query []string
+	attributes := params.ApplicationName != ""
+	for _, kv := range extra {
+		query = append(query, kv[0]+"="+kv[1])
+		if kv[0] == "connectionAttributes" {
+			attributes = false
+		}
+	}
+	if attributes {
+		// Attribute values end at the next , or :
+		name := strings.NewReplacer(",", " ", ":", " ").Replace(params.ApplicationName)
+		query = append(query, "connectionAttributes="+url.QueryEscape("program_name:"+name))
+	}
+	if len(query) > 0 {
+		dsn += "?" + strings.Join(query, "&")
+	}
+	return dsn
 }
 
 // Connect establishes connection to MySQL
 func (d *MySQLDriver) Connect(params ConnectParams) error {
+	extra, err := splitParams(params.Params)
+	if err != nil {
+		return WrapConnectionError(err)
+	}
+
 	protocol := "tcp"
 	address := fmt.Sprintf("%s:%d", params.Host, params.Port)
+	if isSocket(params.Host) {
+		protocol, address = "unix", params.Host
+	}
+	network := protocol
 
 	// Setup SSH tunnel if configured
 	if params.SSHConfig != nil && params.SSHConfig.Host != "" {
@@ -36,21 +105,19 @@ func (d *MySQLDriver) Connect(params ConnectParams) error {
 		d.netName = fmt.Sprintf("mysql+ssh+%d", time.Now().UnixNano())
 		mysql.RegisterDialContext(d.netName, func(ctx context.Context, addr string) (net.Conn, error) {
 			// addr here is what was in dsn: host:port, which is usually params.Host:params.Port
-			return tunnel.DialContext(ctx, "tcp", addr)
+			return tunnel.DialContext(ctx, network, addr)
 		})
 		protocol = d.netName
 	}
 
-	// Build DSN: user:password@protocol(address)/dbname?param=value
-	dsn := fmt.Sprintf("%s:%s@%s(%s)/%s",
-		params.User,
-		params.Password,
-		protocol,
-		address,
-		params.Database,
-	)
+	dsn := mysqlDSN(params, protocol, address, extra)
 
-	db, err := sql.Open("mysql", dsn)
+	statements := params.SessionStatements
+	if params.ReadOnly {
+		statements = append([]string{"SET SESSION TRANSACTION READ ONLY"}, statements...)
+	}
+	d.session = newSessionInit(statements)
+	db, err := openDB(mysql.MySQLDriver{}, dsn, d.session)
 	if err != nil {
 		d.Close() // Cleanup tunnel if open failed
 		return WrapConnectionError(err)
@@ -58,7 +125,7 @@ func (d *MySQLDriver) Connect(params ConnectParams) error {
 
 	// Configure connection pooling
 	db.SetMaxOpenConns(5)
-	db.SetMaxIdleConns(2)
+	db.SetMaxIdleConns(maxIdleConns)
 	db.SetConnMaxLifetime(5 * time.Minute)
 
 	// Verify connection immediately (sql.Open is lazy)
@@ -69,9 +136,61 @@ func (d *MySQLDriver) Connect(params ConnectParams) error {
 	}
 
 	d.db = db
+
+	// Detect flavor; a failure here just falls back to plain MySQL behaviour
+	if err := db.QueryRow("SELECT VERSION()").Scan(&d.version); err == nil {
+		d.flavor = detectMySQLFlavor(d.version)
+	} else {
+		d.flavor = FlavorMySQL
+	}
 	return nil
 }
 
+// Flavor returns the detected server flavor
+func (d *MySQLDriver) Flavor() string {
+	return d.flavor
+}
+
+// ExplainQuery returns the flavor's most readable EXPLAIN form
+func (d *MySQLDriver) ExplainQuery(query string) string {
+	switch d.flavor {
+	case FlavorTiDB:
+		return "EXPLAIN FORMAT='brief' " + query
+	case FlavorMariaDB:
+		return "EXPLAIN " + query
+	}
+	if supportsExplainTree(d.version) {
+		return "EXPLAIN FORMAT=TREE " + query
+	}
+	return "EXPLAIN " + query
+}
+
+// supportsExplainTree reports whether a MySQL version string is 8.0.16 or newer,
+// the first release with EXPLAIN FORMAT=TREE
+func supportsExplainTree(version string) bool {
+	var v [3]int
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return false
+	}
+	for i, p := range parts {
+		// Trim suffixes such as "-log" or "-0ubuntu0.22.04.1"
+		end := strings.IndexFunc(p, func(r rune) bool { return r < '0' || r > '9' })
+		if end >= 0 {
+			p = p[:end]
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return false
+		}
+		v[i] = n
+	}
+	if v[0] != 8 {
+		return v[0] > 8
+	}
+	return v[1] > 0 || v[2] >= 16
+}
+
 // Close closes the database connection and SSH tunnel
 func (d *MySQLDriver) Close() error {
 	var dbErr error
@@ -96,6 +215,105 @@ func (d *MySQLDriver) Execute(ctx context.Context, query string) (*QueryResult,
 	return executeQuery(ctx, d.db, query)
 }
 
+// Transaction runs statements in one transaction
+func (d *MySQLDriver) Transaction(ctx context.Context, fn func(exec func(query string) error) error) error {
+	return transaction(ctx, d.db, fn)
+}
+
+// loadDataSeq numbers the reader handlers of concurrent LOAD DATA statements
+var loadDataSeq atomic.Int64
+
+// loadDataEscaper escapes a value enclosed in double quotes for LOAD DATA
+var loadDataEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\x00", `\0`)
+
+// BulkLoad loads rows with LOAD DATA LOCAL INFILE from an in-memory reader.
+// LOAD DATA skips duplicate keys and coerces values that do not fit with a
+// warning, like INSERT IGNORE, so it runs in a transaction that is rolled
+// back with a *BulkLoadRejectedError when the server warned. Servers with
+// local_infile off refuse it with ErrBulkLoadUnavailable.
+func (d *MySQLDriver) BulkLoad(ctx context.Context, table string, columns []string, rows [][]any) (int64, error) {
+	if d.db == nil {
+		return 0, WrapConnectionError(fmt.Errorf("not connected"))
+	}
+	var data strings.Builder
+	for _, row := range rows {
+		for i, v := range row {
+			if i > 0 {
+				data.WriteByte(',')
+			}
+			if v == nil {
+				data.WriteString(`\N`)
+			} else {
+				data.WriteString(`"` + loadDataEscaper.Replace(fmt.Sprint(v)) + `"`)
+			}
+		}
+		data.WriteByte('\n')
+	}
+
+	name := fmt.Sprintf("ezdb-%d", loadDataSeq.Add(1))
+	mysql.RegisterReaderHandler(name, func() io.Reader { return strings.NewReader(data.String()) })
+	defer mysql.DeregisterReaderHandler(name)
+
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = quoteIdentFor(MySQL, c)
+	}
+	stmt := fmt.Sprintf("LOAD DATA LOCAL INFILE 'Reader::%s' INTO TABLE %s CHARACTER SET utf8mb4 "+
+		`FIELDS TERMINATED BY ',' ENCLOSED BY '"' ESCAPED BY '\\' LINES TERMINATED BY '\n' (%s)`,
+		name, table, strings.Join(names, ", "))
+
+	// Warnings belong to the session, so they are read inside the transaction
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, WrapQueryError(err)
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, stmt)
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) && (myErr.Number == 1148 || myErr.Number == 3948 || myErr.Number == 3950) {
+		// Loading local data is disabled on the server
+		return 0, ErrBulkLoadUnavailable
+	}
+	if err != nil {
+		return 0, WrapQueryError(err)
+	}
+	loaded, _ := res.RowsAffected()
+	var warnings int64
+	if err := tx.QueryRowContext(ctx, "SHOW COUNT(*) WARNINGS").Scan(&warnings); err != nil {
+		return 0, WrapQueryError(err)
+	}
+	if skipped := int64(len(rows)) - loaded; skipped > 0 || warnings > 0 {
+		rejected := &BulkLoadRejectedError{Skipped: skipped, Warnings: max(warnings-skipped, 0)}
+		var level string
+		var code int
+		_ = tx.QueryRowContext(ctx, "SHOW WARNINGS LIMIT 1").Scan(&level, &code, &rejected.First)
+		return 0, rejected
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, WrapQueryError(err)
+	}
+	return loaded, nil
+}
+
+// EstimateRows returns the optimizer's estimate of the rows a statement touches
+func (d *MySQLDriver) EstimateRows(ctx context.Context, query string) (int64, error) {
+	return explainRows(ctx, d.db, query)
+}
+
+// Validate prepares a single statement without running it. Statements the
+// prepared statement protocol does not support pass unchecked.
+func (d *MySQLDriver) Validate(ctx context.Context, query string) error {
+	err := prepareOnly(ctx, d.db, query)
+	var myErr *mysql.MySQLError
+	if !errors.As(err, &myErr) {
+		return err
+	}
+	if myErr.Number == 1295 { // ER_UNSUPPORTED_PS
+		return nil
+	}
+	return &SyntaxError{Offset: nearOffset(query, myErr.Message), Message: myErr.Message}
+}
+
 // Ping checks if database is reachable
 func (d *MySQLDriver) Ping(ctx context.Context) error {
 	if d.db == nil {
@@ -129,6 +347,171 @@ func (d *MySQLDriver) GetTables(ctx context.Context) ([]string, error) {
 	return tables, rows.Err()
 }
 
+// Schemas lists the databases on the server, without the system ones
+func (d *MySQLDriver) Schemas(ctx context.Context) ([]string, error) {
+	rows, err := d.db.QueryContext(ctx, "SHOW DATABASES")
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, WrapQueryError(err)
+		}
+		switch strings.ToLower(name) {
+		case "information_schema", "mysql", "performance_schema", "sys", "metrics_schema":
+			continue
+		}
+		schemas = append(schemas, name)
+	}
+	return schemas, rows.Err()
+}
+
+// CurrentSchema returns the session's database
+func (d *MySQLDriver) CurrentSchema(ctx context.Context) (string, error) {
+	var schema string
+	if err := d.db.QueryRowContext(ctx, "SELECT COALESCE(DATABASE(), '')").Scan(&schema); err != nil {
+		return "", WrapQueryError(err)
+	}
+	return schema, nil
+}
+
+// SwitchSchema makes database the session's default database
+func (d *MySQLDriver) SwitchSchema(ctx context.Context, database string) error {
+	return switchSchema(ctx, d.db, d.session, "USE `"+strings.ReplaceAll(database, "`", "``")+"`")
+}
+
+// Sessions lists the other connections, longest running first
+func (d *MySQLDriver) Sessions(ctx context.Context) ([]Session, error) {
+	query := `
+		SELECT ID, COALESCE(USER, ''), COALESCE(DB, ''), COALESCE(COMMAND, ''), COALESCE(TIME, 0), COALESCE(INFO, '')
+		FROM information_schema.PROCESSLIST
+		WHERE ID <> CONNECTION_ID()
+		ORDER BY COMMAND = 'Sleep', TIME DESC`
+	rows, err := d.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	defer rows.Close()
+
+	var sessions []Session
+	for rows.Next() {
+		var s Session
+		var seconds int64
+		if err := rows.Scan(&s.ID, &s.User, &s.Database, &s.State, &seconds, &s.Query); err != nil {
+			return nil, WrapQueryError(err)
+		}
+		s.Duration = time.Duration(seconds) * time.Second
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+// Metrics reports connections, buffer pool hits, the longest query,
+// replication lag and the largest schemas
+func (d *MySQLDriver) Metrics(ctx context.Context) (*ServerMetrics, error) {
+	m := &ServerMetrics{CacheHitRatio: -1}
+	rows, err := d.db.QueryContext(ctx, `SHOW GLOBAL STATUS WHERE Variable_name IN
+		('Threads_connected', 'Threads_running', 'Innodb_buffer_pool_read_requests', 'Innodb_buffer_pool_reads')`)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	status := make(map[string]int64)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			rows.Close()
+			return nil, WrapQueryError(err)
+		}
+		status[name], _ = strconv.ParseInt(value, 10, 64)
+	}
+	rows.Close()
+	m.Connections = int(status["Threads_connected"])
+	m.Active = int(status["Threads_running"])
+	if requests := status["Innodb_buffer_pool_read_requests"]; requests > 0 {
+		m.CacheHitRatio = 1 - float64(status["Innodb_buffer_pool_reads"])/float64(requests)
+	}
+	// TiDB has no max_connections limit by default
+	_ = d.db.QueryRowContext(ctx, "SELECT @@max_connections").Scan(&m.MaxConnections)
+
+	var seconds int64
+	err = d.db.QueryRowContext(ctx, `
+		SELECT TIME, INFO FROM information_schema.PROCESSLIST
+		WHERE COMMAND <> 'Sleep' AND INFO IS NOT NULL AND ID <> CONNECTION_ID()
+		ORDER BY TIME DESC
+		LIMIT 1`).Scan(&seconds, &m.LongestQuery)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, WrapQueryError(err)
+	}
+	m.LongestQueryDuration = time.Duration(seconds) * time.Second
+
+	m.Replicating, m.ReplicationLag = d.replicaLag(ctx)
+
+	rows, err = d.db.QueryContext(ctx, `
+		SELECT table_schema, COALESCE(SUM(data_length + index_length), 0)
+		FROM information_schema.TABLES
+		GROUP BY table_schema
+		ORDER BY 2 DESC
+		LIMIT 10`)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var size DatabaseSize
+		if err := rows.Scan(&size.Name, &size.Bytes); err != nil {
+			return nil, WrapQueryError(err)
+		}
+		m.DatabaseSizes = append(m.DatabaseSizes, size)
+	}
+	return m, rows.Err()
+}
+
+// replicaLag reads Seconds_Behind_Source from the replica status. Servers
+// before MySQL 8.0.22 and MariaDB only know SHOW SLAVE STATUS.
+func (d *MySQLDriver) replicaLag(ctx context.Context) (bool, time.Duration) {
+	result, err := executeQuery(ctx, d.db, "SHOW REPLICA STATUS")
+	if err != nil {
+		if result, err = executeQuery(ctx, d.db, "SHOW SLAVE STATUS"); err != nil {
+			return false, 0
+		}
+	}
+	if len(result.Rows) == 0 {
+		return false, 0
+	}
+	for i, col := range result.Columns {
+		if col == "Seconds_Behind_Source" || col == "Seconds_Behind_Master" {
+			seconds, _ := strconv.ParseInt(result.Rows[0][i], 10, 64)
+			return true, time.Duration(seconds) * time.Second
+		}
+	}
+	return true, 0
+}
+
+// CancelSession kills the connection's running statement
+func (d *MySQLDriver) CancelSession(ctx context.Context, id string) error {
+	return d.kill(ctx, "KILL QUERY ", id)
+}
+
+// TerminateSession kills the connection
+func (d *MySQLDriver) TerminateSession(ctx context.Context, id string) error {
+	return d.kill(ctx, "KILL ", id)
+}
+
+func (d *MySQLDriver) kill(ctx context.Context, statement, id string) error {
+	// KILL takes no placeholders, so only a numeric ID goes into the statement
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return fmt.Errorf("invalid connection id %q", id)
+	}
+	if _, err := d.db.ExecContext(ctx, statement+id); err != nil {
+		return WrapQueryError(err)
+	}
+	return nil
+}
+
 // GetColumns returns detailed column metadata for a table
 func (d *MySQLDriver) GetColumns(ctx context.Context, tableName string) ([]Column, error) {
 	query := `
@@ -154,6 +537,10 @@ func (d *MySQLDriver) GetColumns(ctx context.Context, tableName string) ([]Colum
 		if err := rows.Scan(&col.Name, &col.Type, &col.Nullable, &col.Default, &col.Key); err != nil {
 			return nil, WrapQueryError(err)
 		}
+		// MariaDB reports a missing default as the literal NULL
+		if d.flavor == FlavorMariaDB && col.Default == "NULL" {
+			col.Default = ""
+		}
 		columns = append(columns, col)
 	}
 	return columns, rows.Err()
@@ -162,12 +549,20 @@ func (d *MySQLDriver) GetColumns(ctx context.Context, tableName string) ([]Colum
 // GetConstraints returns detailed constraint metadata for a table
 func (d *MySQLDriver) GetConstraints(ctx context.Context, tableName string) ([]Constraint, error) {
 	query := `
-		SELECT 
-			CONSTRAINT_NAME, 
-			CONSTRAINT_TYPE, 
-			'' as DEFINITION
-		FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS
-		WHERE TABLE_NAME = ? AND TABLE_SCHEMA = DATABASE()`
+		SELECT
+			tc.CONSTRAINT_NAME,
+			tc.CONSTRAINT_TYPE,
+			IFNULL(GROUP_CONCAT(k.COLUMN_NAME ORDER BY k.ORDINAL_POSITION SEPARATOR ', '), ''),
+			IFNULL(MAX(k.REFERENCED_TABLE_NAME), ''),
+			IFNULL(GROUP_CONCAT(k.REFERENCED_COLUMN_NAME ORDER BY k.ORDINAL_POSITION SEPARATOR ', '), '')
+		FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+		LEFT JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE k
+			ON k.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA
+			AND k.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+			AND k.TABLE_NAME = tc.TABLE_NAME
+		WHERE tc.TABLE_NAME = ? AND tc.TABLE_SCHEMA = DATABASE()
+		GROUP BY tc.CONSTRAINT_NAME, tc.CONSTRAINT_TYPE
+		ORDER BY tc.CONSTRAINT_NAME`
 
 	rows, err := d.db.QueryContext(ctx, query, tableName)
 	if err != nil {
@@ -175,13 +570,107 @@ func (d *MySQLDriver) GetConstraints(ctx context.Context, tableName string) ([]C
 	}
 	defer rows.Close()
 
+	checks := d.checkClauses(ctx, tableName)
+	pkType := d.tidbPKType(ctx, tableName)
+
 	var constraints []Constraint
 	for rows.Next() {
 		var cons Constraint
-		if err := rows.Scan(&cons.Name, &cons.Type, &cons.Definition); err != nil {
+		var cols, refTable, refCols string
+		if err := rows.Scan(&cons.Name, &cons.Type, &cols, &refTable, &refCols); err != nil {
 			return nil, WrapQueryError(err)
 		}
+		switch cons.Type {
+		case "PRIMARY KEY":
+			cons.Definition = strings.TrimSpace(fmt.Sprintf("PRIMARY KEY (%s) %s", cols, pkType))
+		case "UNIQUE":
+			cons.Definition = fmt.Sprintf("UNIQUE (%s)", cols)
+		case "FOREIGN KEY":
+			cons.Definition = fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)", cols, refTable, refCols)
+			cons.Columns, cons.RefTable, cons.RefColumns = splitNames(cols, ", "), refTable, splitNames(refCols, ", ")
+		case "CHECK":
+			cons.Definition = checks[cons.Name]
+		}
 		constraints = append(constraints, cons)
 	}
 	return constraints, rows.Err()
 }
+
+// GetFunctions returns the stored functions of the current database
+func (d *MySQLDriver) GetFunctions(ctx context.Context) ([]Function, error) {
+	query := `
+		SELECT
+			r.ROUTINE_NAME,
+			IFNULL(GROUP_CONCAT(CONCAT(p.PARAMETER_NAME, ' ', p.DTD_IDENTIFIER) ORDER BY p.ORDINAL_POSITION SEPARATOR ', '), ''),
+			IFNULL(r.DTD_IDENTIFIER, '')
+		FROM INFORMATION_SCHEMA.ROUTINES r
+		LEFT JOIN INFORMATION_SCHEMA.PARAMETERS p
+			ON p.SPECIFIC_SCHEMA = r.ROUTINE_SCHEMA
+			AND p.SPECIFIC_NAME = r.SPECIFIC_NAME
+			AND p.ORDINAL_POSITION > 0
+		WHERE r.ROUTINE_SCHEMA = DATABASE() AND r.ROUTINE_TYPE = 'FUNCTION'
+		GROUP BY r.ROUTINE_NAME, r.DTD_IDENTIFIER
+		ORDER BY r.ROUTINE_NAME`
+
+	rows, err := d.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	defer rows.Close()
+
+	var functions []Function
+	for rows.Next() {
+		var fn Function
+		if err := rows.Scan(&fn.Name, &fn.Arguments, &fn.Returns); err != nil {
+			return nil, WrapQueryError(err)
+		}
+		functions = append(functions, fn)
+	}
+	return functions, rows.Err()
+}
+
+// checkClauses returns CHECK expressions by constraint name.
+// MariaDB exposes TABLE_NAME on CHECK_CONSTRAINTS; MySQL and TiDB need a join.
+// Servers without CHECK_CONSTRAINTS (MySQL 5.7, older TiDB) yield no clauses.
+func (d *MySQLDriver) checkClauses(ctx context.Context, tableName string) map[string]string {
+	query := `
+		SELECT cc.CONSTRAINT_NAME, cc.CHECK_CLAUSE
+		FROM INFORMATION_SCHEMA.CHECK_CONSTRAINTS cc
+		JOIN INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+			ON tc.CONSTRAINT_SCHEMA = cc.CONSTRAINT_SCHEMA
+			AND tc.CONSTRAINT_NAME = cc.CONSTRAINT_NAME
+		WHERE tc.TABLE_SCHEMA = DATABASE() AND tc.TABLE_NAME = ? AND tc.CONSTRAINT_TYPE = 'CHECK'`
+	if d.flavor == FlavorMariaDB {
+		query = `
+			SELECT CONSTRAINT_NAME, CHECK_CLAUSE
+			FROM INFORMATION_SCHEMA.CHECK_CONSTRAINTS
+			WHERE CONSTRAINT_SCHEMA = DATABASE() AND TABLE_NAME = ?`
+	}
+
+	checks := make(map[string]string)
+	rows, err := d.db.QueryContext(ctx, query, tableName)
+	if err != nil {
+		return checks
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, clause string
+		if err := rows.Scan(&name, &clause); err == nil {
+			checks[name] = "CHECK (" + clause + ")"
+		}
+	}
+	return checks
+}
+
+// tidbPKType returns CLUSTERED or NONCLUSTERED for TiDB tables, "" otherwise
+func (d *MySQLDriver) tidbPKType(ctx context.Context, tableName string) string {
+	if d.flavor != FlavorTiDB {
+		return ""
+	}
+	var pkType sql.NullString
+	query := "SELECT TIDB_PK_TYPE FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?"
+	if err := d.db.QueryRowContext(ctx, query, tableName).Scan(&pkType); err != nil {
+		return ""
+	}
+	return pkType.String
+}
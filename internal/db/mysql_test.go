@@ -0,0 +1,55 @@
+// internal/db/mysql_test.go
+package db
+
+import "testing"
+
+func TestDetectMySQLFlavor(t *testing.T) {
+	tests := map[string]string{
+		"8.0.36":                              FlavorMySQL,
+		"5.7.44-log":                          FlavorMySQL,
+		"10.11.6-MariaDB-1:10.11.6+maria~ubu": FlavorMariaDB,
+		"5.5.5-10.6.16-MariaDB":               FlavorMariaDB,
+		"8.0.11-TiDB-v7.5.0":                  FlavorTiDB,
+	}
+	for version, want := range tests {
+		if got := detectMySQLFlavor(version); got != want {
+			t.Errorf("detectMySQLFlavor(%q) = %s, want %s", version, got, want)
+		}
+	}
+}
+
+func TestSupportsExplainTree(t *testing.T) {
+	tests := map[string]bool{
+		"5.7.44-log":              false,
+		"8.0.0":                   false,
+		"8.0.15":                  false,
+		"8.0.16":                  true,
+		"8.0.36-0ubuntu0.22.04.1": true,
+		"8.4.0":                   true,
+		"9.1.0":                   true,
+		"8":                       false,
+		"":                        false,
+	}
+	for version, want := range tests {
+		if got := supportsExplainTree(version); got != want {
+			t.Errorf("supportsExplainTree(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestMySQLDSN(t *testing.T) {
+	params := ConnectParams{User: "root", Password: "pw", Database: "app", ApplicationName: "ezdb v1.2.0"}
+	tests := []struct {
+		extra [][2]string
+		want  string
+	}{
+		{nil, "root:pw@unix(/tmp/mysql.sock)/app?connectionAttributes=program_name%3Aezdb+v1.2.0"},
+		{[][2]string{{"charset", "utf8mb4"}}, "root:pw@unix(/tmp/mysql.sock)/app?charset=utf8mb4&connectionAttributes=program_name%3Aezdb+v1.2.0"},
+		{[][2]string{{"connectionAttributes", "team:data"}}, "root:pw@unix(/tmp/mysql.sock)/app?connectionAttributes=team:data"},
+	}
+	for _, tt := range tests {
+		if got := mysqlDSN(params, "unix", "/tmp/mysql.sock", tt.extra); got != tt.want {
+			t.Errorf("mysqlDSN(%v) = %q, want %q", tt.extra, got, tt.want)
+		}
+	}
+}
@@ -2,26 +2,51 @@
 package db
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"net"
 	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/stdlib"
 )
 
-// PostgresDriver implements Driver for PostgreSQL
+// Postgres-compatible server flavors, detected from version()
+const (
+	FlavorPostgres    = "PostgreSQL"
+	FlavorCockroachDB = "CockroachDB"
+)
+
+// PostgresDriver implements Driver for PostgreSQL and CockroachDB
 type PostgresDriver struct {
-	db     *sql.DB
-	tunnel *SSHTunnel
+	db      *sql.DB
+	tunnel  *SSHTunnel
+	flavor  string // FlavorPostgres or FlavorCockroachDB
+	session *sessionInit
 }
 
-// Connect establishes connection to PostgreSQL
-func (d *PostgresDriver) Connect(params ConnectParams) error {
+// detectPostgresFlavor derives the server flavor from a version() string,
+// e.g. "CockroachDB CCL v23.2.4 (x86_64-pc-linux-gnu, ...)"
+func detectPostgresFlavor(version string) string {
+	if strings.HasPrefix(version, "CockroachDB") {
+		return FlavorCockroachDB
+	}
+	return FlavorPostgres
+}
+
+// postgresDSN builds the connection URL; a host starting with "/" is the
+// directory of the server's unix socket
+func postgresDSN(params ConnectParams) (string, error) {
 	// Build connection string safely with url.URL
 	u := &url.URL{
 		Scheme: "postgres",
@@ -29,7 +54,38 @@ func (d *PostgresDriver) Connect(params ConnectParams) error {
 		Host:   fmt.Sprintf("%s:%d", params.Host, params.Port),
 		Path:   "/" + params.Database,
 	}
-	dsn := u.String()
+	query := url.Values{}
+	if params.ApplicationName != "" {
+		query.Set("application_name", params.ApplicationName)
+	}
+	if isSocket(params.Host) {
+		u.Host = ""
+		query.Set("host", params.Host)
+		if params.Port != 0 {
+			query.Set("port", strconv.Itoa(params.Port))
+		}
+	}
+	extra, err := splitParams(params.Params)
+	if err != nil {
+		return "", err
+	}
+	for _, kv := range extra {
+		query.Set(kv[0], kv[1])
+	}
+	if params.ReadOnly {
+		// Sent at startup, after the extra parameters so they cannot undo it
+		query.Set("default_transaction_read_only", "on")
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Connect establishes connection to PostgreSQL
+func (d *PostgresDriver) Connect(params ConnectParams) error {
+	dsn, err := postgresDSN(params)
+	if err != nil {
+		return WrapConnectionError(err)
+	}
 
 	// Parse config
 	connConfig, err := pgx.ParseConfig(dsn)
@@ -53,6 +109,10 @@ func (d *PostgresDriver) Connect(params ConnectParams) error {
 
 		// Override DialFunc to use DialContext and hostname
 		connConfig.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if network == "unix" {
+				// Socket path on the SSH server
+				return tunnel.DialContext(ctx, network, addr)
+			}
 			remoteAddr := fmt.Sprintf("%s:%d", params.Host, params.Port)
 			return tunnel.DialContext(ctx, network, remoteAddr)
 		}
@@ -60,7 +120,8 @@ func (d *PostgresDriver) Connect(params ConnectParams) error {
 
 	// Register the driver configuration with stdlib
 	dbStr := stdlib.RegisterConnConfig(connConfig)
-	db, err := sql.Open("pgx", dbStr)
+	d.session = newSessionInit(params.SessionStatements)
+	db, err := openDB(stdlib.GetDefaultDriver(), dbStr, d.session)
 	if err != nil {
 		if d.tunnel != nil {
 			d.tunnel.Close()
@@ -70,7 +131,7 @@ func (d *PostgresDriver) Connect(params ConnectParams) error {
 
 	// Configure connection pooling
 	db.SetMaxOpenConns(5)
-	db.SetMaxIdleConns(2)
+	db.SetMaxIdleConns(maxIdleConns)
 	db.SetConnMaxLifetime(5 * time.Minute)
 
 	// Verify connection
@@ -85,9 +146,34 @@ func (d *PostgresDriver) Connect(params ConnectParams) error {
 	}
 
 	d.db = db
+
+	var version string
+	if err := db.QueryRow("SELECT version()").Scan(&version); err == nil {
+		d.flavor = detectPostgresFlavor(version)
+	} else {
+		d.flavor = FlavorPostgres
+	}
 	return nil
 }
 
+// Flavor returns the detected server flavor
+func (d *PostgresDriver) Flavor() string {
+	return d.flavor
+}
+
+// ExplainQuery returns the flavor's EXPLAIN form.
+// On CockroachDB reads get EXPLAIN ANALYZE (DISTSQL), which executes the
+// statement; anything else only gets the distributed plan.
+func (d *PostgresDriver) ExplainQuery(query string) string {
+	if d.flavor != FlavorCockroachDB {
+		return "EXPLAIN " + query
+	}
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(query)), "SELECT") {
+		return "EXPLAIN ANALYZE (DISTSQL) " + query
+	}
+	return "EXPLAIN (DISTSQL) " + query
+}
+
 // Close closes the database connection and SSH tunnel
 func (d *PostgresDriver) Close() error {
 	var dbErr error
@@ -108,7 +194,141 @@ func (d *PostgresDriver) Close() error {
 
 // Execute runs a query and returns results
 func (d *PostgresDriver) Execute(ctx context.Context, query string) (*QueryResult, error) {
-	return executeQuery(ctx, d.db, query)
+	res, err := executeQuery(ctx, d.db, query)
+	if err == nil && res.IsSelect && planTiming(ctx) {
+		res.Timing.Plan = d.planningTime(ctx, query)
+	}
+	return res, err
+}
+
+// planningTime plans a SELECT again with EXPLAIN (SUMMARY), without running
+// it, and returns the planning time the server reports, or 0
+func (d *PostgresDriver) planningTime(ctx context.Context, query string) time.Duration {
+	trimmed := strings.ToUpper(strings.TrimSpace(query))
+	if !strings.HasPrefix(trimmed, "SELECT") && !strings.HasPrefix(trimmed, "WITH") {
+		return 0
+	}
+	rows, err := d.db.QueryContext(ctx, "EXPLAIN (SUMMARY) "+query)
+	if err != nil {
+		return 0
+	}
+	defer rows.Close()
+	var lines []string
+	for rows.Next() {
+		var line string
+		if rows.Scan(&line) == nil {
+			lines = append(lines, line)
+		}
+	}
+	return parsePlanningTime(lines)
+}
+
+// Transaction runs statements in one transaction
+func (d *PostgresDriver) Transaction(ctx context.Context, fn func(exec func(query string) error) error) error {
+	return transaction(ctx, d.db, fn)
+}
+
+// rawConn runs fn on the pgx connection under one of the pool's connections
+func (d *PostgresDriver) rawConn(ctx context.Context, fn func(pg *pgconn.PgConn) error) error {
+	if d.db == nil {
+		return WrapConnectionError(fmt.Errorf("not connected"))
+	}
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return WrapConnectionError(err)
+	}
+	defer conn.Close()
+	return conn.Raw(func(dc any) error {
+		return fn(dc.(*stdlib.Conn).Conn().PgConn())
+	})
+}
+
+// CopyOut streams query with COPY TO STDOUT in CSV, the column names first
+func (d *PostgresDriver) CopyOut(ctx context.Context, query string, fn func(row []string) error) error {
+	stmt := fmt.Sprintf("COPY (%s) TO STDOUT WITH (FORMAT csv, HEADER, NULL 'NULL')", query)
+	return d.rawConn(ctx, func(pg *pgconn.PgConn) error {
+		pr, pw := io.Pipe()
+		copied := make(chan error, 1)
+		go func() {
+			_, err := pg.CopyTo(ctx, pw, stmt)
+			pw.CloseWithError(err)
+			copied <- err
+		}()
+
+		r := csv.NewReader(pr)
+		var readErr, fnErr error
+		for readErr == nil && fnErr == nil {
+			var row []string
+			if row, readErr = r.Read(); readErr == nil {
+				fnErr = fn(row)
+			}
+		}
+		// Closing the read end stops a copy cut short; its connection is then dropped
+		pr.Close()
+		copyErr := <-copied
+		switch {
+		case fnErr != nil:
+			return fnErr
+		case copyErr != nil:
+			return WrapQueryError(copyErr)
+		case readErr != io.EOF:
+			return readErr
+		}
+		return nil
+	})
+}
+
+// BulkLoad loads rows with COPY FROM STDIN in CSV, every row or none. Every
+// value is quoted, so only NULLs are left as the bare empty fields COPY reads as NULL.
+func (d *PostgresDriver) BulkLoad(ctx context.Context, table string, columns []string, rows [][]any) (int64, error) {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = quoteIdentFor(Postgres, c)
+	}
+	stmt := fmt.Sprintf("COPY %s (%s) FROM STDIN WITH (FORMAT csv)", table, strings.Join(names, ", "))
+
+	var data bytes.Buffer
+	for _, row := range rows {
+		for i, v := range row {
+			if i > 0 {
+				data.WriteByte(',')
+			}
+			if v != nil {
+				data.WriteString(`"` + strings.ReplaceAll(fmt.Sprint(v), `"`, `""`) + `"`)
+			}
+		}
+		data.WriteByte('\n')
+	}
+
+	var copied int64
+	err := d.rawConn(ctx, func(pg *pgconn.PgConn) error {
+		tag, err := pg.CopyFrom(ctx, &data, stmt)
+		copied = tag.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, WrapQueryError(err)
+	}
+	return copied, nil
+}
+
+// EstimateRows returns the planner's estimate of the rows a statement touches
+func (d *PostgresDriver) EstimateRows(ctx context.Context, query string) (int64, error) {
+	return explainRows(ctx, d.db, query)
+}
+
+// Validate prepares a single statement without running it
+func (d *PostgresDriver) Validate(ctx context.Context, query string) error {
+	err := prepareOnly(ctx, d.db, query)
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	offset := -1
+	if pgErr.Position > 0 {
+		offset = runeOffset(query, int(pgErr.Position)-1)
+	}
+	return &SyntaxError{Offset: offset, Message: pgErr.Message}
 }
 
 // Ping checks if database is reachable
@@ -130,7 +350,7 @@ func (d *PostgresDriver) GetTables(ctx context.Context) ([]string, error) {
 		SELECT n.nspname || '.' || c.relname
 		FROM pg_class c
 		JOIN pg_namespace n ON n.oid = c.relnamespace
-		WHERE n.nspname NOT IN ('information_schema', 'pg_catalog', 'pg_toast')
+		WHERE n.nspname NOT IN ('information_schema', 'pg_catalog', 'pg_toast', 'crdb_internal', 'pg_extension')
 		AND c.relkind IN ('r', 'v', 'm', 'f', 'p')
 		ORDER BY 1`
 	rows, err := d.db.QueryContext(ctx, query)
@@ -150,8 +370,171 @@ func (d *PostgresDriver) GetTables(ctx context.Context) ([]string, error) {
 	return tables, rows.Err()
 }
 
+// Schemas lists the non-system schemas
+func (d *PostgresDriver) Schemas(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT nspname FROM pg_namespace
+		WHERE nspname NOT IN ('information_schema', 'crdb_internal', 'pg_extension')
+			AND nspname NOT LIKE 'pg\_%'
+		ORDER BY 1`
+	rows, err := d.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, WrapQueryError(err)
+		}
+		schemas = append(schemas, name)
+	}
+	return schemas, rows.Err()
+}
+
+// CurrentSchema returns the first existing schema on the search_path
+func (d *PostgresDriver) CurrentSchema(ctx context.Context) (string, error) {
+	var schema string
+	if err := d.db.QueryRowContext(ctx, "SELECT COALESCE(current_schema(), '')").Scan(&schema); err != nil {
+		return "", WrapQueryError(err)
+	}
+	return schema, nil
+}
+
+// SwitchSchema puts schema first on the search_path, ahead of public
+func (d *PostgresDriver) SwitchSchema(ctx context.Context, schema string) error {
+	path := `"` + strings.ReplaceAll(schema, `"`, `""`) + `"`
+	if schema != "public" {
+		path += ", public"
+	}
+	return switchSchema(ctx, d.db, d.session, "SET search_path TO "+path)
+}
+
+// Sessions lists the other client backends, active ones first
+func (d *PostgresDriver) Sessions(ctx context.Context) ([]Session, error) {
+	if d.flavor == FlavorCockroachDB {
+		return nil, fmt.Errorf("session activity is not available on CockroachDB, use SHOW CLUSTER SESSIONS")
+	}
+	query := `
+		SELECT pid::text, COALESCE(usename, ''), COALESCE(datname, ''), COALESCE(state, ''),
+			COALESCE(EXTRACT(EPOCH FROM now() - CASE WHEN state = 'active' THEN query_start ELSE state_change END), 0)::float8,
+			COALESCE(query, '')
+		FROM pg_stat_activity
+		WHERE pid <> pg_backend_pid() AND backend_type = 'client backend'
+		ORDER BY state = 'active' DESC, 5 DESC`
+	rows, err := d.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	defer rows.Close()
+
+	var sessions []Session
+	for rows.Next() {
+		var s Session
+		var seconds float64
+		if err := rows.Scan(&s.ID, &s.User, &s.Database, &s.State, &seconds, &s.Query); err != nil {
+			return nil, WrapQueryError(err)
+		}
+		s.Duration = time.Duration(seconds * float64(time.Second))
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+// Metrics reports connections, cache hits, the longest query, replication lag
+// and the largest databases
+func (d *PostgresDriver) Metrics(ctx context.Context) (*ServerMetrics, error) {
+	if d.flavor == FlavorCockroachDB {
+		return nil, fmt.Errorf("server metrics are not available on CockroachDB, see its DB Console")
+	}
+	m := &ServerMetrics{}
+	err := d.db.QueryRowContext(ctx, `
+		SELECT count(*), count(*) FILTER (WHERE state = 'active'), current_setting('max_connections')::int,
+			(SELECT COALESCE(sum(blks_hit)::float8 / NULLIF(sum(blks_hit) + sum(blks_read), 0), -1) FROM pg_stat_database)
+		FROM pg_stat_activity
+		WHERE backend_type = 'client backend'`).Scan(&m.Connections, &m.Active, &m.MaxConnections, &m.CacheHitRatio)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+
+	var seconds float64
+	err = d.db.QueryRowContext(ctx, `
+		SELECT EXTRACT(EPOCH FROM now() - query_start)::float8, query
+		FROM pg_stat_activity
+		WHERE state = 'active' AND pid <> pg_backend_pid() AND backend_type = 'client backend'
+		ORDER BY query_start
+		LIMIT 1`).Scan(&seconds, &m.LongestQuery)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, WrapQueryError(err)
+	}
+	m.LongestQueryDuration = time.Duration(seconds * float64(time.Second))
+
+	// A standby reports its replay delay, a primary its slowest replica's
+	var lag sql.NullFloat64
+	err = d.db.QueryRowContext(ctx, `
+		SELECT CASE WHEN pg_is_in_recovery()
+			THEN COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+			ELSE (SELECT EXTRACT(EPOCH FROM max(replay_lag)) FROM pg_stat_replication)
+		END::float8`).Scan(&lag)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	m.Replicating = lag.Valid
+	m.ReplicationLag = time.Duration(lag.Float64 * float64(time.Second))
+
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT datname, pg_database_size(datname)
+		FROM pg_database
+		WHERE NOT datistemplate AND has_database_privilege(datname, 'CONNECT')
+		ORDER BY 2 DESC
+		LIMIT 10`)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var size DatabaseSize
+		if err := rows.Scan(&size.Name, &size.Bytes); err != nil {
+			return nil, WrapQueryError(err)
+		}
+		m.DatabaseSizes = append(m.DatabaseSizes, size)
+	}
+	return m, rows.Err()
+}
+
+// CancelSession cancels the backend's running query
+func (d *PostgresDriver) CancelSession(ctx context.Context, id string) error {
+	return d.signalBackend(ctx, "pg_cancel_backend", id)
+}
+
+// TerminateSession closes the backend's connection
+func (d *PostgresDriver) TerminateSession(ctx context.Context, id string) error {
+	return d.signalBackend(ctx, "pg_terminate_backend", id)
+}
+
+func (d *PostgresDriver) signalBackend(ctx context.Context, function, id string) error {
+	pid, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid backend pid %q", id)
+	}
+	var ok bool
+	if err := d.db.QueryRowContext(ctx, "SELECT "+function+"($1)", pid).Scan(&ok); err != nil {
+		return WrapQueryError(err)
+	}
+	if !ok {
+		return fmt.Errorf("backend %d not found", pid)
+	}
+	return nil
+}
+
 // GetColumns returns detailed column metadata for a table
 func (d *PostgresDriver) GetColumns(ctx context.Context, tableName string) ([]Column, error) {
+	if d.flavor == FlavorCockroachDB {
+		return d.getCockroachColumns(ctx, tableName)
+	}
+
 	query := `
 		SELECT
 			a.attname AS column_name,
@@ -200,7 +583,13 @@ func (d *PostgresDriver) GetConstraints(ctx context.Context, tableName string) (
 				WHEN contype = 'c' THEN 'CHECK'
 				ELSE contype::text
 			END as type, 
-			pg_get_constraintdef(c.oid) as definition
+			pg_get_constraintdef(c.oid) as definition,
+			COALESCE((SELECT string_agg(a.attname, ',' ORDER BY array_position(c.conkey, a.attnum))
+				FROM pg_attribute a WHERE a.attrelid = c.conrelid AND a.attnum = ANY(c.conkey)), ''),
+			COALESCE((SELECT rn.nspname || '.' || r.relname
+				FROM pg_class r JOIN pg_namespace rn ON rn.oid = r.relnamespace WHERE r.oid = c.confrelid), ''),
+			COALESCE((SELECT string_agg(a.attname, ',' ORDER BY array_position(c.confkey, a.attnum))
+				FROM pg_attribute a WHERE a.attrelid = c.confrelid AND a.attnum = ANY(c.confkey)), '')
 		FROM pg_constraint c
 		JOIN pg_class cl ON cl.oid = c.conrelid
 		JOIN pg_namespace n ON n.oid = cl.relnamespace
@@ -216,10 +605,90 @@ func (d *PostgresDriver) GetConstraints(ctx context.Context, tableName string) (
 	var constraints []Constraint
 	for rows.Next() {
 		var cons Constraint
-		if err := rows.Scan(&cons.Name, &cons.Type, &cons.Definition); err != nil {
+		var cols, refCols string
+		if err := rows.Scan(&cons.Name, &cons.Type, &cons.Definition, &cols, &cons.RefTable, &refCols); err != nil {
 			return nil, WrapQueryError(err)
 		}
+		if cons.Type == "FOREIGN KEY" {
+			cons.Columns, cons.RefColumns = splitNames(cols, ","), splitNames(refCols, ",")
+		} else {
+			cons.RefTable = ""
+		}
 		constraints = append(constraints, cons)
 	}
 	return constraints, rows.Err()
 }
+
+// GetFunctions returns the functions defined outside the system schemas
+func (d *PostgresDriver) GetFunctions(ctx context.Context) ([]Function, error) {
+	query := `
+		SELECT
+			CASE WHEN n.nspname = ANY(current_schemas(false)) THEN p.proname ELSE n.nspname || '.' || p.proname END,
+			pg_get_function_arguments(p.oid),
+			COALESCE(pg_get_function_result(p.oid), '')
+		FROM pg_proc p
+		JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')
+			AND n.nspname NOT LIKE 'pg\_%'
+			AND n.nspname NOT LIKE 'crdb\_%'
+		ORDER BY 1, 2`
+
+	rows, err := d.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	defer rows.Close()
+
+	var functions []Function
+	for rows.Next() {
+		var fn Function
+		if err := rows.Scan(&fn.Name, &fn.Arguments, &fn.Returns); err != nil {
+			return nil, WrapQueryError(err)
+		}
+		functions = append(functions, fn)
+	}
+	return functions, rows.Err()
+}
+
+// getCockroachColumns reads columns from information_schema, which on
+// CockroachDB hides the implicit rowid column and reports crdb_sql_type;
+// pg_index.indkey does not cast to int2[] there.
+func (d *PostgresDriver) getCockroachColumns(ctx context.Context, tableName string) ([]Column, error) {
+	query := `
+		SELECT
+			c.column_name,
+			c.crdb_sql_type,
+			c.is_nullable = 'YES',
+			COALESCE(c.column_default, ''),
+			COALESCE((
+				SELECT CASE MIN(CASE tc.constraint_type
+						WHEN 'PRIMARY KEY' THEN 1 WHEN 'UNIQUE' THEN 2 WHEN 'FOREIGN KEY' THEN 3 END)
+					WHEN 1 THEN 'PRI' WHEN 2 THEN 'UNI' WHEN 3 THEN 'MUL' END
+				FROM information_schema.key_column_usage k
+				JOIN information_schema.table_constraints tc
+					ON tc.constraint_schema = k.constraint_schema
+					AND tc.constraint_name = k.constraint_name
+					AND tc.table_name = k.table_name
+				WHERE k.table_schema = c.table_schema AND k.table_name = c.table_name
+				AND k.column_name = c.column_name
+			), '')
+		FROM information_schema.columns c
+		WHERE c.table_schema || '.' || c.table_name = $1 AND c.is_hidden = 'NO'
+		ORDER BY c.ordinal_position`
+
+	rows, err := d.db.QueryContext(ctx, query, tableName)
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var col Column
+		if err := rows.Scan(&col.Name, &col.Type, &col.Nullable, &col.Default, &col.Key); err != nil {
+			return nil, WrapQueryError(err)
+		}
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
@@ -0,0 +1,38 @@
+// internal/db/postgres_test.go
+package db
+
+import "testing"
+
+func TestDetectPostgresFlavor(t *testing.T) {
+	if got := detectPostgresFlavor("PostgreSQL 16.2 on x86_64-pc-linux-gnu"); got != FlavorPostgres {
+		t.Errorf("got %s, want %s", got, FlavorPostgres)
+	}
+	if got := detectPostgresFlavor("CockroachDB CCL v23.2.4 (x86_64-pc-linux-gnu, built 2024/04/05)"); got != FlavorCockroachDB {
+		t.Errorf("got %s, want %s", got, FlavorCockroachDB)
+	}
+}
+
+func TestPostgresDSN(t *testing.T) {
+	tests := []struct {
+		params ConnectParams
+		want   string
+	}{
+		{ConnectParams{Host: "db", Port: 5432, User: "app", Database: "shop"}, "postgres://app:@db:5432/shop"},
+		{ConnectParams{Host: "/var/run/postgresql", Port: 5433, User: "app", Database: "shop"}, "postgres://app:@/shop?host=%2Fvar%2Frun%2Fpostgresql&port=5433"},
+		{ConnectParams{Host: "db", Port: 5432, User: "app", Database: "shop", Params: "application_name=ezdb & options=-c statement_timeout=5s"},
+			"postgres://app:@db:5432/shop?application_name=ezdb&options=-c+statement_timeout%3D5s"},
+		{ConnectParams{Host: "db", Port: 5432, User: "app", Database: "shop", ApplicationName: "ezdb v1.2.0"}, "postgres://app:@db:5432/shop?application_name=ezdb+v1.2.0"},
+		{ConnectParams{Host: "db", Port: 5432, User: "app", Database: "shop", ApplicationName: "ezdb", Params: "application_name=etl"}, "postgres://app:@db:5432/shop?application_name=etl"},
+		{ConnectParams{Host: "db", Port: 5432, User: "app", Database: "shop", ReadOnly: true, Params: "default_transaction_read_only=off"},
+			"postgres://app:@db:5432/shop?default_transaction_read_only=on"},
+	}
+	for _, tt := range tests {
+		got, err := postgresDSN(tt.params)
+		if err != nil || got != tt.want {
+			t.Errorf("postgresDSN(%+v) = %q, %v, want %q", tt.params, got, err, tt.want)
+		}
+	}
+	if _, err := postgresDSN(ConnectParams{Params: "sslmode"}); err == nil {
+		t.Error("postgresDSN accepted a parameter without a value")
+	}
+}
@@ -0,0 +1,164 @@
+// internal/db/schemadiff.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Schema is a database's tables with their columns and constraints
+type Schema struct {
+	Columns     map[string][]Column     `json:"columns"`
+	Constraints map[string][]Constraint `json:"constraints"`
+}
+
+// SchemaChange is one difference between two schemas
+type SchemaChange struct {
+	Op     string // "+" added, "-" removed, "~" changed
+	Table  string
+	Object string // Column or constraint; empty for the table itself
+	Detail string
+}
+
+func (c SchemaChange) String() string {
+	s := c.Op + " " + c.Table
+	if c.Object != "" {
+		s += "." + c.Object
+	}
+	if c.Detail != "" {
+		s += ": " + c.Detail
+	}
+	return s
+}
+
+// LoadSchema reads every table's columns and constraints. Unlike the schema
+// browser it fails on any error, so a snapshot never misses a table.
+func LoadSchema(ctx context.Context, driver Driver) (Schema, error) {
+	tables, err := driver.GetTables(ctx)
+	if err != nil {
+		return Schema{}, err
+	}
+	schema := Schema{Columns: map[string][]Column{}, Constraints: map[string][]Constraint{}}
+	var mu sync.Mutex
+	var firstErr error
+	sem := make(chan struct{}, 20)
+	var wg sync.WaitGroup
+	for _, table := range tables {
+		wg.Add(1)
+		go func(t string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			cols, err := driver.GetColumns(ctx, t)
+			if err == nil {
+				var cons []Constraint
+				cons, err = driver.GetConstraints(ctx, t)
+				mu.Lock()
+				schema.Columns[t], schema.Constraints[t] = cols, cons
+				mu.Unlock()
+			}
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("%s: %w", t, err)
+				}
+				mu.Unlock()
+			}
+		}(table)
+	}
+	wg.Wait()
+	return schema, firstErr
+}
+
+// DiffSchemas lists what changed from old to current, table by table
+func DiffSchemas(old, current Schema) []SchemaChange {
+	tables := map[string]bool{}
+	for t := range old.Columns {
+		tables[t] = true
+	}
+	for t := range current.Columns {
+		tables[t] = true
+	}
+	names := make([]string, 0, len(tables))
+	for t := range tables {
+		names = append(names, t)
+	}
+	sort.Strings(names)
+
+	var changes []SchemaChange
+	for _, t := range names {
+		oldCols, inOld := old.Columns[t]
+		newCols, inNew := current.Columns[t]
+		switch {
+		case !inOld:
+			changes = append(changes, SchemaChange{Op: "+", Table: t, Detail: fmt.Sprintf("table, %d columns", len(newCols))})
+			continue
+		case !inNew:
+			changes = append(changes, SchemaChange{Op: "-", Table: t, Detail: "table"})
+			continue
+		}
+		changes = append(changes, diffColumns(t, oldCols, newCols)...)
+		changes = append(changes, diffConstraints(t, old.Constraints[t], current.Constraints[t])...)
+	}
+	return changes
+}
+
+func diffColumns(table string, old, current []Column) []SchemaChange {
+	var changes []SchemaChange
+	for _, c := range current {
+		i := slices.IndexFunc(old, func(o Column) bool { return o.Name == c.Name })
+		if i < 0 {
+			changes = append(changes, SchemaChange{Op: "+", Table: table, Object: c.Name, Detail: describeColumn(c)})
+			continue
+		}
+		if was, now := describeColumn(old[i]), describeColumn(c); was != now {
+			changes = append(changes, SchemaChange{Op: "~", Table: table, Object: c.Name, Detail: was + " → " + now})
+		}
+	}
+	for _, o := range old {
+		if !slices.ContainsFunc(current, func(c Column) bool { return c.Name == o.Name }) {
+			changes = append(changes, SchemaChange{Op: "-", Table: table, Object: o.Name, Detail: describeColumn(o)})
+		}
+	}
+	return changes
+}
+
+// describeColumn words a column's type and modifiers: "varchar(40) NOT NULL DEFAULT 'x' PRI"
+func describeColumn(c Column) string {
+	parts := []string{c.Type}
+	if !c.Nullable {
+		parts = append(parts, "NOT NULL")
+	}
+	if c.Default != "" && c.Default != "<nil>" {
+		parts = append(parts, "DEFAULT "+c.Default)
+	}
+	if c.Key != "" {
+		parts = append(parts, c.Key)
+	}
+	return strings.Join(parts, " ")
+}
+
+func diffConstraints(table string, old, current []Constraint) []SchemaChange {
+	var changes []SchemaChange
+	for _, c := range current {
+		i := slices.IndexFunc(old, func(o Constraint) bool { return o.Name == c.Name })
+		if i < 0 {
+			changes = append(changes, SchemaChange{Op: "+", Table: table, Object: c.Name, Detail: c.Definition})
+			continue
+		}
+		if old[i].Type != c.Type || old[i].Definition != c.Definition {
+			changes = append(changes, SchemaChange{Op: "~", Table: table, Object: c.Name, Detail: old[i].Definition + " → " + c.Definition})
+		}
+	}
+	for _, o := range old {
+		if !slices.ContainsFunc(current, func(c Constraint) bool { return c.Name == o.Name }) {
+			changes = append(changes, SchemaChange{Op: "-", Table: table, Object: o.Name, Detail: o.Definition})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,72 @@
+// internal/db/schemadiff_test.go
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	old := Schema{
+		Columns: map[string][]Column{
+			"users":  {{Name: "id", Type: "integer", Key: "PRI"}, {Name: "email", Type: "varchar(40)", Nullable: true}, {Name: "age", Type: "integer", Nullable: true}},
+			"legacy": {{Name: "id", Type: "integer"}},
+		},
+		Constraints: map[string][]Constraint{
+			"users": {{Name: "users_pkey", Type: "PRIMARY KEY", Definition: "PRIMARY KEY (id)"}},
+		},
+	}
+	current := Schema{
+		Columns: map[string][]Column{
+			"users":  {{Name: "id", Type: "integer", Key: "PRI"}, {Name: "email", Type: "varchar(80)"}, {Name: "name", Type: "text", Nullable: true}},
+			"orders": {{Name: "id", Type: "integer"}, {Name: "user_id", Type: "integer"}},
+		},
+		Constraints: map[string][]Constraint{
+			"users": {
+				{Name: "users_pkey", Type: "PRIMARY KEY", Definition: "PRIMARY KEY (id)"},
+				{Name: "users_email_key", Type: "UNIQUE", Definition: "UNIQUE (email)"},
+			},
+		},
+	}
+
+	var got []string
+	for _, c := range DiffSchemas(old, current) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"- legacy: table",
+		"+ orders: table, 2 columns",
+		"~ users.email: varchar(40) → varchar(80) NOT NULL",
+		"+ users.name: text",
+		"- users.age: integer",
+		"+ users.users_email_key: UNIQUE (email)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("DiffSchemas =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if changes := DiffSchemas(current, current); len(changes) != 0 {
+		t.Errorf("DiffSchemas of a schema with itself = %v, want none", changes)
+	}
+}
+
+func TestLoadSchema(t *testing.T) {
+	d := &SQLiteDriver{}
+	if err := d.Connect(ConnectParams{Database: filepath.Join(t.TempDir(), "schema.db")}); err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	ctx := context.Background()
+	if _, err := d.Execute(ctx, "CREATE TABLE t (id INTEGER PRIMARY KEY, name TEXT NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := LoadSchema(ctx, d)
+	if err != nil {
+		t.Fatalf("LoadSchema: %v", err)
+	}
+	if cols := schema.Columns["t"]; len(cols) != 2 || cols[1].Name != "name" || cols[1].Nullable {
+		t.Errorf("columns of t = %+v", cols)
+	}
+}
@@ -0,0 +1,130 @@
+// internal/db/session.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+)
+
+// maxIdleConns is the idle connections a server driver's pool keeps
+const maxIdleConns = 2
+
+// sessionInit holds the statements run on each new connection
+type sessionInit struct {
+	mu         sync.Mutex
+	statements []string
+	schema     string // Selects the schema or database, set by switchSchema
+}
+
+func newSessionInit(statements []string) *sessionInit {
+	return &sessionInit{statements: statements}
+}
+
+// all returns the statements to run on a new connection
+func (s *sessionInit) all() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.schema == "" {
+		return s.statements
+	}
+	return append(append([]string(nil), s.statements...), s.schema)
+}
+
+func (s *sessionInit) setSchema(stmt string) (prev string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, s.schema = s.schema, stmt
+	return prev
+}
+
+// openDB opens a connection pool on drv. Session statements (SET search_path,
+// SET time_zone...) run on every connection the pool opens, so they survive
+// reconnects as well as the first connect.
+func openDB(drv driver.Driver, dsn string, session *sessionInit) (*sql.DB, error) {
+	var connector driver.Connector = dsnConnector{drv: drv, dsn: dsn}
+	if dc, ok := drv.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+		connector = c
+	}
+	return sql.OpenDB(sessionConnector{Connector: connector, session: session}), nil
+}
+
+// switchSchema adds stmt (SET search_path, USE) to every connection's
+// session. Idle connections are dropped so the next query opens one that
+// ran it; if that fails the previous schema is restored.
+func switchSchema(ctx context.Context, db *sql.DB, session *sessionInit, stmt string) error {
+	if db == nil {
+		return WrapConnectionError(fmt.Errorf("not connected"))
+	}
+	prev := session.setSchema(stmt)
+	db.SetMaxIdleConns(0)
+	db.SetMaxIdleConns(maxIdleConns)
+	if err := db.PingContext(ctx); err != nil {
+		session.setSchema(prev)
+		db.SetMaxIdleConns(0)
+		db.SetMaxIdleConns(maxIdleConns)
+		return err
+	}
+	return nil
+}
+
+// dsnConnector adapts a driver without its own connector
+type dsnConnector struct {
+	drv driver.Driver
+	dsn string
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.drv.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.drv
+}
+
+// sessionConnector runs the session statements on each new connection
+type sessionConnector struct {
+	driver.Connector
+	session *sessionInit
+}
+
+func (c sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, stmt := range c.session.all() {
+		if err := execSession(ctx, conn, stmt); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("session statement %q: %w", stmt, err)
+		}
+	}
+	return conn, nil
+}
+
+// execSession runs stmt on a raw driver connection
+func execSession(ctx context.Context, conn driver.Conn, stmt string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, stmt, nil)
+		if err != driver.ErrSkip {
+			return err
+		}
+	}
+	prepared, err := conn.Prepare(stmt)
+	if err != nil {
+		return err
+	}
+	defer prepared.Close()
+	if execer, ok := prepared.(driver.StmtExecContext); ok {
+		_, err = execer.ExecContext(ctx, nil)
+		return err
+	}
+	_, err = prepared.Exec(nil)
+	return err
+}
@@ -0,0 +1,230 @@
+// internal/db/spill.go
+package db
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// resultMemoryKey is the context key of the cap on result rows kept in memory
+type resultMemoryKey struct{}
+
+// WithResultMemory caps the bytes of result rows Execute keeps in memory for
+// queries run with the returned context; rows past the cap spill to a
+// temporary file. A cap of 0 keeps every row in memory.
+func WithResultMemory(ctx context.Context, limit int64) context.Context {
+	return context.WithValue(ctx, resultMemoryKey{}, limit)
+}
+
+// spillBlock is the number of spilled rows between indexed file offsets
+const spillBlock = 256
+
+// Spill holds result rows on disk, one JSON array per line, and reads them
+// back a page at a time
+type Spill struct {
+	f      *os.File
+	w      *bufio.Writer
+	size   int64   // Bytes written
+	blocks []int64 // Offset of every spillBlock-th row
+	rows   int
+}
+
+// newSpill creates an empty spill file. It is unlinked right away where the
+// OS allows, so the file goes with the process even if Close is never called.
+func newSpill() (*Spill, error) {
+	f, err := os.CreateTemp("", "ezdb-result-*.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(f.Name())
+	return &Spill{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// append writes a row to the end of the spill
+func (s *Spill) append(row []string) error {
+	line, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	if s.rows%spillBlock == 0 {
+		s.blocks = append(s.blocks, s.size)
+	}
+	line = append(line, '\n')
+	if _, err := s.w.Write(line); err != nil {
+		return err
+	}
+	s.size += int64(len(line))
+	s.rows++
+	return nil
+}
+
+// Len returns the number of spilled rows
+func (s *Spill) Len() int {
+	return s.rows
+}
+
+// Rows reads up to n spilled rows starting at row start
+func (s *Spill) Rows(start, n int) ([][]string, error) {
+	if start < 0 || start >= s.rows || n <= 0 {
+		return nil, nil
+	}
+	off := s.blocks[start/spillBlock]
+	r := bufio.NewReader(io.NewSectionReader(s.f, off, s.size-off))
+	for skip := start % spillBlock; skip > 0; skip-- {
+		if _, err := r.ReadBytes('\n'); err != nil {
+			return nil, err
+		}
+	}
+	if start+n > s.rows {
+		n = s.rows - start
+	}
+	rows := make([][]string, 0, n)
+	for len(rows) < n {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+		var row []string
+		if err := json.Unmarshal(line, &row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Close deletes the spill file
+func (s *Spill) Close() error {
+	if s == nil || s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	os.Remove(s.f.Name())
+	s.f = nil
+	return err
+}
+
+// RowsAt returns up to n rows of the whole result, in memory or spilled,
+// starting at row start
+func (r *QueryResult) RowsAt(start, n int) ([][]string, error) {
+	var rows [][]string
+	if start < len(r.Rows) {
+		end := min(start+n, len(r.Rows))
+		rows = r.Rows[start:end:end]
+	}
+	if r.Spill == nil || len(rows) == n {
+		return rows, nil
+	}
+	spilled, err := r.Spill.Rows(max(start-len(r.Rows), 0), n-len(rows))
+	if err != nil {
+		return nil, fmt.Errorf("reading spilled rows: %w", err)
+	}
+	return append(rows, spilled...), nil
+}
+
+// EachRow calls fn with every row of the result in order, stopping at the first error
+func (r *QueryResult) EachRow(fn func(row []string) error) error {
+	for _, row := range r.Rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if r.Spill == nil {
+		return nil
+	}
+	for start := 0; start < r.Spill.Len(); start += spillBlock {
+		rows, err := r.Spill.Rows(start, spillBlock)
+		if err != nil {
+			return fmt.Errorf("reading spilled rows: %w", err)
+		}
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Close deletes the result's spill file, if it has one
+func (r *QueryResult) Close() error {
+	if r == nil {
+		return nil
+	}
+	return r.Spill.Close()
+}
+
+// rowCollector gathers the rows of a result, spilling them once they pass
+// the memory cap of the query's context
+type rowCollector struct {
+	limit int64
+	size  int64
+	rows  [][]string
+	spill *Spill
+}
+
+// newRowCollector returns a collector for a query run with ctx
+func newRowCollector(ctx context.Context) *rowCollector {
+	limit, _ := ctx.Value(resultMemoryKey{}).(int64)
+	return &rowCollector{limit: limit}
+}
+
+// add collects a row
+func (c *rowCollector) add(row []string) error {
+	if c.spill == nil {
+		c.size += rowSize(row)
+		if c.limit <= 0 || c.size <= c.limit {
+			c.rows = append(c.rows, row)
+			return nil
+		}
+		spill, err := newSpill()
+		if err != nil {
+			return fmt.Errorf("spilling result rows: %w", err)
+		}
+		c.spill = spill
+	}
+	if err := c.spill.append(row); err != nil {
+		return fmt.Errorf("spilling result rows: %w", err)
+	}
+	return nil
+}
+
+// discard deletes the spill of a result that is not returned
+func (c *rowCollector) discard() {
+	c.spill.Close()
+}
+
+// result returns the SELECT result of the collected rows. The spill is
+// flushed first; from then on it is only read, so readers may share it.
+func (c *rowCollector) result(columns []string, start time.Time) (*QueryResult, error) {
+	res := &QueryResult{
+		Columns:  columns,
+		Rows:     c.rows,
+		ExecTime: time.Since(start),
+		RowCount: len(c.rows),
+		IsSelect: true,
+	}
+	if c.spill != nil {
+		if err := c.spill.w.Flush(); err != nil {
+			c.discard()
+			return nil, fmt.Errorf("spilling result rows: %w", err)
+		}
+		res.Spill = c.spill
+		res.RowCount += c.spill.Len()
+	}
+	return res, nil
+}
+
+// rowSize estimates the memory a row of strings takes
+func rowSize(row []string) int64 {
+	size := int64(24) // Slice header
+	for _, v := range row {
+		size += int64(16 + len(v)) // String header and bytes
+	}
+	return size
+}
@@ -0,0 +1,61 @@
+// internal/db/spill_test.go
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestResultSpillsPastMemoryCap(t *testing.T) {
+	d := &SQLiteDriver{}
+	if err := d.Connect(ConnectParams{Database: filepath.Join(t.TempDir(), "spill.db")}); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer d.Close()
+	ctx := context.Background()
+	setup := "CREATE TABLE nums AS WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 1000) SELECT i, 'row ' || i AS label FROM n"
+	if _, err := d.Execute(ctx, setup); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	res, err := d.Execute(WithResultMemory(ctx, 4096), "SELECT i, label FROM nums ORDER BY i")
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	defer res.Close()
+	if res.Spill == nil || len(res.Rows) >= 100 || res.RowCount != 1000 {
+		t.Fatalf("kept %d rows in memory of %d, spilled %v", len(res.Rows), res.RowCount, res.Spill != nil)
+	}
+
+	// A page straddling memory and disk, and one deep in the spill
+	for _, start := range []int{len(res.Rows) - 3, 700} {
+		rows, err := res.RowsAt(start, 10)
+		if err != nil {
+			t.Fatalf("rows at %d: %v", start, err)
+		}
+		if len(rows) != 10 || rows[0][0] != strconv.Itoa(start+1) || rows[9][1] != "row "+strconv.Itoa(start+10) {
+			t.Errorf("rows at %d = %v", start, rows)
+		}
+	}
+	if rows, _ := res.RowsAt(995, 10); len(rows) != 5 {
+		t.Errorf("rows at 995 = %d rows, want the last 5", len(rows))
+	}
+
+	next := 1
+	err = res.EachRow(func(row []string) error {
+		if row[0] != strconv.Itoa(next) {
+			t.Fatalf("row %d = %v", next, row)
+		}
+		next++
+		return nil
+	})
+	if err != nil || next != 1001 {
+		t.Errorf("EachRow visited %d rows, err %v", next-1, err)
+	}
+
+	if res, _ := d.Execute(ctx, "SELECT i FROM nums"); res.Spill != nil || len(res.Rows) != 1000 {
+		t.Errorf("uncapped query spilled or lost rows: %d in memory", len(res.Rows))
+	}
+}
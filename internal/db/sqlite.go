@@ -4,9 +4,12 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
 // SQLiteDriver implements Driver for SQLite
@@ -22,8 +25,11 @@ func (d *SQLiteDriver) Connect(params ConnectParams) error {
 	if len(dsn) > 9 && dsn[:9] == "sqlite://" {
 		dsn = dsn[9:]
 	}
+	if params.ReadOnly {
+		dsn = readOnlyDSN(dsn)
+	}
 
-	db, err := sql.Open("sqlite3", dsn)
+	db, err := openDB(&sqlite3.SQLiteDriver{}, dsn, newSessionInit(params.SessionStatements))
 	if err != nil {
 		return WrapConnectionError(err)
 	}
@@ -36,10 +42,75 @@ func (d *SQLiteDriver) Connect(params ConnectParams) error {
 		return WrapConnectionError(fmt.Errorf("pragma busy_timeout: %w", err))
 	}
 
+	// ATTACH is per connection, so keep a single one for the whole session
+	db.SetMaxOpenConns(1)
+
 	d.db = db
 	return nil
 }
 
+// Attachment is an extra database file attached to the session under a schema name
+type Attachment struct {
+	Schema string
+	Path   string
+}
+
+// Attach attaches a database file; an empty schema defaults to the file's base name
+func (d *SQLiteDriver) Attach(ctx context.Context, path, schema string) (string, error) {
+	if schema == "" {
+		schema = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if _, err := d.db.ExecContext(ctx, "ATTACH DATABASE ? AS "+quoteSQLiteIdent(schema), path); err != nil {
+		return "", WrapQueryError(err)
+	}
+	return schema, nil
+}
+
+// Detach detaches a previously attached schema
+func (d *SQLiteDriver) Detach(ctx context.Context, schema string) error {
+	if _, err := d.db.ExecContext(ctx, "DETACH DATABASE "+quoteSQLiteIdent(schema)); err != nil {
+		return WrapQueryError(err)
+	}
+	return nil
+}
+
+// Attachments lists attached databases, excluding main and temp
+func (d *SQLiteDriver) Attachments(ctx context.Context) ([]Attachment, error) {
+	rows, err := d.db.QueryContext(ctx, "PRAGMA database_list")
+	if err != nil {
+		return nil, WrapQueryError(err)
+	}
+	defer rows.Close()
+
+	var attachments []Attachment
+	for rows.Next() {
+		var seq int
+		var name, file string
+		if err := rows.Scan(&seq, &name, &file); err != nil {
+			return nil, WrapQueryError(err)
+		}
+		if name == "main" || name == "temp" {
+			continue
+		}
+		attachments = append(attachments, Attachment{Schema: name, Path: file})
+	}
+	return attachments, rows.Err()
+}
+
+// quoteSQLiteIdent quotes an identifier for use in SQL text
+func quoteSQLiteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// splitSQLiteTable splits "schema.table" into a quoted schema prefix and table.
+// Tables in main are unqualified, so their prefix is empty.
+func splitSQLiteTable(name string) (prefix, table string) {
+	if schema, table, ok := strings.Cut(name, "."); ok {
+		return quoteSQLiteIdent(schema) + ".", table
+	}
+	return "", name
+}
+
 // Close closes the database connection
 func (d *SQLiteDriver) Close() error {
 	if d.db != nil {
@@ -53,6 +124,35 @@ func (d *SQLiteDriver) Execute(ctx context.Context, query string) (*QueryResult,
 	return executeQuery(ctx, d.db, query)
 }
 
+// readOnlyDSN turns a file name or file: URI into a URI opening it read-only
+func readOnlyDSN(dsn string) string {
+	switch {
+	case dsn == ":memory:" || strings.Contains(dsn, "mode=memory"):
+		return dsn
+	case !strings.HasPrefix(dsn, "file:"):
+		dsn = "file:" + dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&mode=ro"
+	}
+	return dsn + "?mode=ro"
+}
+
+// Transaction runs statements in one transaction
+func (d *SQLiteDriver) Transaction(ctx context.Context, fn func(exec func(query string) error) error) error {
+	return transaction(ctx, d.db, fn)
+}
+
+// Validate compiles a single statement without running it
+func (d *SQLiteDriver) Validate(ctx context.Context, query string) error {
+	err := prepareOnly(ctx, d.db, query)
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+	return &SyntaxError{Offset: nearOffset(query, err.Error()), Message: err.Error()}
+}
+
 // Ping checks if database is reachable
 func (d *SQLiteDriver) Ping(ctx context.Context) error {
 	if d.db == nil {
@@ -66,9 +166,31 @@ func (d *SQLiteDriver) Type() DriverType {
 	return SQLite
 }
 
-// GetTables returns a list of tables
+// GetTables returns a list of tables; tables of attached databases are
+// prefixed with their schema name
 func (d *SQLiteDriver) GetTables(ctx context.Context) ([]string, error) {
-	query := "SELECT name FROM sqlite_master WHERE type='table'"
+	tables, err := d.schemaTables(ctx, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := d.Attachments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range attachments {
+		attached, err := d.schemaTables(ctx, quoteSQLiteIdent(a.Schema)+".", a.Schema+".")
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, attached...)
+	}
+	return tables, nil
+}
+
+// schemaTables lists tables of one schema, reading from prefix.sqlite_master
+func (d *SQLiteDriver) schemaTables(ctx context.Context, prefix, namePrefix string) ([]string, error) {
+	query := "SELECT name FROM " + prefix + "sqlite_master WHERE type='table'"
 	rows, err := d.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, WrapQueryError(err)
@@ -81,14 +203,15 @@ func (d *SQLiteDriver) GetTables(ctx context.Context) ([]string, error) {
 		if err := rows.Scan(&name); err != nil {
 			return nil, WrapQueryError(err)
 		}
-		tables = append(tables, name)
+		tables = append(tables, namePrefix+name)
 	}
 	return tables, rows.Err()
 }
 
 // GetColumns returns detailed column metadata for a table
 func (d *SQLiteDriver) GetColumns(ctx context.Context, tableName string) ([]Column, error) {
-	query := fmt.Sprintf("PRAGMA table_info(%s)", tableName)
+	prefix, table := splitSQLiteTable(tableName)
+	query := fmt.Sprintf("PRAGMA %stable_info(%s)", prefix, quoteSQLiteIdent(table))
 	rows, err := d.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, WrapQueryError(err)
@@ -127,20 +250,37 @@ func (d *SQLiteDriver) GetColumns(ctx context.Context, tableName string) ([]Colu
 func (d *SQLiteDriver) GetConstraints(ctx context.Context, tableName string) ([]Constraint, error) {
 	var constraints []Constraint
 
-	// Foreign keys
-	rows, err := d.db.QueryContext(ctx, fmt.Sprintf("PRAGMA foreign_key_list(%s)", tableName))
+	// Foreign keys; a composite key has one row per column sharing an id
+	prefix, table := splitSQLiteTable(tableName)
+	rows, err := d.db.QueryContext(ctx, fmt.Sprintf("PRAGMA %sforeign_key_list(%s)", prefix, quoteSQLiteIdent(table)))
 	if err == nil {
 		defer rows.Close()
+		byID := make(map[int]int) // id -> index in constraints
 		for rows.Next() {
 			var id, seq int
-			var table, from, to, onUpdate, onDelete, match string
-			if err := rows.Scan(&id, &seq, &table, &from, &to, &onUpdate, &onDelete, &match); err == nil {
+			var refTable, from, to, onUpdate, onDelete, match string
+			if err := rows.Scan(&id, &seq, &refTable, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+				continue
+			}
+			i, ok := byID[id]
+			if !ok {
+				i = len(constraints)
+				byID[id] = i
+				qualified := refTable
+				if schema, _, ok := strings.Cut(tableName, "."); ok {
+					// Foreign keys reference tables in the same database
+					qualified = schema + "." + refTable
+				}
 				constraints = append(constraints, Constraint{
-					Name:       fmt.Sprintf("fk_%s_%d", tableName, id),
-					Type:       "FOREIGN KEY",
-					Definition: fmt.Sprintf("REFERENCES %s(%s) ON UPDATE %s ON DELETE %s", table, to, onUpdate, onDelete),
+					Name:     fmt.Sprintf("fk_%s_%d", tableName, id),
+					Type:     "FOREIGN KEY",
+					RefTable: qualified,
 				})
 			}
+			cons := &constraints[i]
+			cons.Columns = append(cons.Columns, from)
+			cons.RefColumns = append(cons.RefColumns, to)
+			cons.Definition = fmt.Sprintf("REFERENCES %s(%s) ON UPDATE %s ON DELETE %s", refTable, strings.Join(cons.RefColumns, ", "), onUpdate, onDelete)
 		}
 	}
 
@@ -2,10 +2,12 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"path/filepath"
 	"testing"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
 func TestSQLiteDriver(t *testing.T) {
@@ -19,3 +21,220 @@ func TestSQLiteDriver(t *testing.T) {
 		t.Fatalf("ping failed: %v", err)
 	}
 }
+
+func TestSQLiteAttach(t *testing.T) {
+	dir := t.TempDir()
+	other := filepath.Join(dir, "other.db")
+	seed, err := sql.Open("sqlite3", other)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := seed.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	seed.Close()
+
+	d := &SQLiteDriver{}
+	if err := d.Connect(ConnectParams{Database: filepath.Join(dir, "main.db")}); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer d.Close()
+
+	ctx := context.Background()
+	schema, err := d.Attach(ctx, other, "")
+	if err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	if schema != "other" {
+		t.Fatalf("schema = %q, want other", schema)
+	}
+
+	tables, err := d.GetTables(ctx)
+	if err != nil {
+		t.Fatalf("tables: %v", err)
+	}
+	if len(tables) != 1 || tables[0] != "other.items" {
+		t.Fatalf("tables = %v, want [other.items]", tables)
+	}
+
+	cols, err := d.GetColumns(ctx, "other.items")
+	if err != nil || len(cols) != 2 || cols[0].Key != "PRI" {
+		t.Fatalf("columns = %v, err = %v", cols, err)
+	}
+
+	if err := d.Detach(ctx, "other"); err != nil {
+		t.Fatalf("detach: %v", err)
+	}
+	attachments, err := d.Attachments(ctx)
+	if err != nil || len(attachments) != 0 {
+		t.Fatalf("attachments after detach = %v, err = %v", attachments, err)
+	}
+}
+
+func TestSQLiteForeignKeys(t *testing.T) {
+	d := &SQLiteDriver{}
+	if err := d.Connect(ConnectParams{Database: filepath.Join(t.TempDir(), "fk.db")}); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer d.Close()
+
+	ctx := context.Background()
+	for _, stmt := range []string{
+		"CREATE TABLE orders (id INTEGER, region TEXT, PRIMARY KEY (id, region))",
+		"CREATE TABLE items (id INTEGER PRIMARY KEY, order_id INTEGER, region TEXT, FOREIGN KEY (order_id, region) REFERENCES orders(id, region))",
+	} {
+		if _, err := d.Execute(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	cons, err := d.GetConstraints(ctx, "items")
+	if err != nil || len(cons) != 1 {
+		t.Fatalf("constraints = %v, err = %v", cons, err)
+	}
+	fk := cons[0]
+	if fk.RefTable != "orders" || len(fk.Columns) != 2 || fk.Columns[1] != "region" || fk.RefColumns[0] != "id" {
+		t.Fatalf("foreign key = %+v", fk)
+	}
+}
+
+func TestSQLiteValidate(t *testing.T) {
+	d := &SQLiteDriver{}
+	if err := d.Connect(ConnectParams{Database: filepath.Join(t.TempDir(), "main.db")}); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer d.Close()
+	ctx := context.Background()
+	if _, err := d.Execute(ctx, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := d.Execute(ctx, "INSERT INTO users (name) VALUES ('ann')"); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	if err := d.Validate(ctx, "DELETE FROM users"); err != nil {
+		t.Fatalf("valid statement: %v", err)
+	}
+	if res, _ := d.Execute(ctx, "SELECT COUNT(*) FROM users"); res.Rows[0][0] != "1" {
+		t.Fatalf("validate ran the statement: %v", res.Rows)
+	}
+
+	query := "SELECT name FORM users"
+	err := d.Validate(ctx, query)
+	syntaxErr, ok := err.(*SyntaxError)
+	if !ok {
+		t.Fatalf("err = %v, want a SyntaxError", err)
+	}
+	if syntaxErr.Offset != 17 {
+		t.Errorf("offset = %d, want 17 (users)", syntaxErr.Offset)
+	}
+}
+
+func TestSQLiteSessionStatements(t *testing.T) {
+	d := &SQLiteDriver{}
+	params := ConnectParams{
+		Database:          filepath.Join(t.TempDir(), "main.db"),
+		SessionStatements: []string{"PRAGMA case_sensitive_like = ON", "CREATE TEMP TABLE session_marker (id INTEGER)"},
+	}
+	if err := d.Connect(params); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer d.Close()
+
+	// Temp tables belong to the connection, so this sees the one the session made
+	if _, err := d.Execute(context.Background(), "SELECT * FROM session_marker"); err != nil {
+		t.Fatalf("session statement did not run: %v", err)
+	}
+
+	bad := &SQLiteDriver{}
+	params.SessionStatements = []string{"SET nonsense"}
+	if err := bad.Connect(params); err == nil {
+		bad.Close()
+		t.Fatal("connect with a failing session statement succeeded")
+	}
+}
+
+func TestSwitchSchemaRecyclesConnections(t *testing.T) {
+	session := newSessionInit(nil)
+	db, err := openDB(&sqlite3.SQLiteDriver{}, ":memory:", session)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	// Each connection to :memory: is a new database, so the table only
+	// exists if the statement ran on the connection the query gets
+	if err := switchSchema(ctx, db, session, "CREATE TABLE switched (id INTEGER)"); err != nil {
+		t.Fatalf("switch: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "SELECT * FROM switched"); err != nil {
+		t.Fatalf("switch statement did not run on the next connection: %v", err)
+	}
+
+	if err := switchSchema(ctx, db, session, "USE nowhere"); err == nil {
+		t.Fatal("switch with a failing statement succeeded")
+	}
+	if _, err := db.ExecContext(ctx, "SELECT * FROM switched"); err != nil {
+		t.Fatalf("previous schema not restored: %v", err)
+	}
+}
+
+func TestSQLiteTransaction(t *testing.T) {
+	d := &SQLiteDriver{}
+	if err := d.Connect(ConnectParams{Database: filepath.Join(t.TempDir(), "main.db")}); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer d.Close()
+	ctx := context.Background()
+	if _, err := d.Execute(ctx, "CREATE TABLE users (id INTEGER PRIMARY KEY)"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	err := d.Transaction(ctx, func(exec func(string) error) error {
+		if err := exec("INSERT INTO users (id) VALUES (1)"); err != nil {
+			return err
+		}
+		return exec("INSERT INTO users (id) VALUES (1)")
+	})
+	if err == nil {
+		t.Fatal("duplicate key did not fail the transaction")
+	}
+	if res, _ := d.Execute(ctx, "SELECT COUNT(*) FROM users"); res.Rows[0][0] != "0" {
+		t.Fatalf("failed transaction kept rows: %v", res.Rows)
+	}
+
+	if err := d.Transaction(ctx, func(exec func(string) error) error {
+		return exec("INSERT INTO users (id) VALUES (1), (2)")
+	}); err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+	if res, _ := d.Execute(ctx, "SELECT COUNT(*) FROM users"); res.Rows[0][0] != "2" {
+		t.Fatalf("committed rows = %v, want 2", res.Rows)
+	}
+}
+
+func TestSQLiteReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ro.db")
+	seed, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := seed.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY)"); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	seed.Close()
+
+	d := &SQLiteDriver{}
+	if err := d.Connect(ConnectParams{Database: path, ReadOnly: true}); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer d.Close()
+	ctx := context.Background()
+	if _, err := d.Execute(ctx, "SELECT COUNT(*) FROM items"); err != nil {
+		t.Errorf("read failed: %v", err)
+	}
+	if _, err := d.Execute(ctx, "INSERT INTO items VALUES (1)"); err == nil {
+		t.Error("insert succeeded on a read-only connection")
+	}
+}
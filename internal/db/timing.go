@@ -0,0 +1,67 @@
+// internal/db/timing.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timing splits the time a statement took into phases. Phases a driver
+// cannot measure are zero.
+type Timing struct {
+	Queue   time.Duration // Waiting for the server to start the job (bigquery)
+	Plan    time.Duration // Planning, as reported by the server
+	Execute time.Duration // From sending the statement until its first rows arrive
+	Fetch   time.Duration // Reading the rows, measured client-side
+}
+
+// String lists the measured phases, e.g. "plan 0.4ms · execute 12.0ms · fetch 3.1ms"
+func (t Timing) String() string {
+	var parts []string
+	for _, p := range []struct {
+		name string
+		d    time.Duration
+	}{{"queue", t.Queue}, {"plan", t.Plan}, {"execute", t.Execute}, {"fetch", t.Fetch}} {
+		if p.d > 0 {
+			parts = append(parts, fmt.Sprintf("%s %.1fms", p.name, float64(p.d)/float64(time.Millisecond)))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// planTimingKey is the context key asking drivers for server-side planning time
+type planTimingKey struct{}
+
+// WithPlanTiming asks drivers that can report planning time to do so for
+// queries run with the returned context. PostgreSQL plans each SELECT once
+// more with EXPLAIN (SUMMARY), which does not run it.
+func WithPlanTiming(ctx context.Context) context.Context {
+	return context.WithValue(ctx, planTimingKey{}, true)
+}
+
+// planTiming reports whether ctx asks for planning time
+func planTiming(ctx context.Context) bool {
+	on, _ := ctx.Value(planTimingKey{}).(bool)
+	return on
+}
+
+// planningTimeLine matches the summary line of a PostgreSQL EXPLAIN
+var planningTimeLine = regexp.MustCompile(`^\s*Planning Time: ([0-9.]+) ms`)
+
+// parsePlanningTime finds the planning time in EXPLAIN (SUMMARY) output
+func parsePlanningTime(lines []string) time.Duration {
+	for _, line := range lines {
+		if m := planningTimeLine.FindStringSubmatch(line); m != nil {
+			ms, err := strconv.ParseFloat(m[1], 64)
+			if err != nil {
+				return 0
+			}
+			return time.Duration(ms * float64(time.Millisecond))
+		}
+	}
+	return 0
+}
@@ -0,0 +1,27 @@
+// internal/db/timing_test.go
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePlanningTime(t *testing.T) {
+	lines := []string{
+		"Seq Scan on users  (cost=0.00..22.70 rows=1270 width=36)",
+		"Planning Time: 0.250 ms",
+	}
+	if got := parsePlanningTime(lines); got != 250*time.Microsecond {
+		t.Errorf("got %v, want 250µs", got)
+	}
+	if got := parsePlanningTime(lines[:1]); got != 0 {
+		t.Errorf("got %v without a summary, want 0", got)
+	}
+}
+
+func TestTimingString(t *testing.T) {
+	tm := Timing{Plan: 400 * time.Microsecond, Execute: 12 * time.Millisecond, Fetch: 3100 * time.Microsecond}
+	if got, want := tm.String(), "plan 0.4ms · execute 12.0ms · fetch 3.1ms"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
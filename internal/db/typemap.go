@@ -0,0 +1,371 @@
+// internal/db/typemap.go
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// typeFamily is a column type every SQL dialect can represent
+type typeFamily int
+
+const (
+	familyText typeFamily = iota
+	familyBool
+	familySmallInt
+	familyInt
+	familyBigInt
+	familyDecimal
+	familyFloat
+	familyDouble
+	familyVarchar
+	familyChar
+	familyDate
+	familyTime
+	familyTimestamp
+	familyTimestampTZ
+	familyJSON
+	familyUUID
+	familyBinary
+)
+
+// typeArgs matches the arguments of varchar(20) or numeric(10, 2)
+var typeArgs = regexp.MustCompile(`\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\)`)
+
+// goTimeLayout is how time.Time values appear in query results
+const goTimeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// classifyType returns the family of a column type of any dialect and its
+// length or precision arguments, e.g. "(10,2)"
+func classifyType(colType string) (typeFamily, string) {
+	t := strings.ToLower(strings.TrimSpace(colType))
+	args := ""
+	if m := typeArgs.FindStringSubmatch(t); m != nil {
+		args = "(" + m[1]
+		if m[2] != "" {
+			args += "," + m[2]
+		}
+		args += ")"
+	}
+	base := t
+	if i := strings.IndexAny(base, "(["); i >= 0 {
+		base = strings.TrimSpace(base[:i])
+	}
+	base = strings.TrimSpace(strings.TrimSuffix(base, " unsigned"))
+
+	switch {
+	case strings.HasSuffix(t, "[]") || base == "array" || strings.HasPrefix(base, "enum") || strings.HasPrefix(base, "set"):
+		return familyText, ""
+	case base == "bool" || base == "boolean" || t == "tinyint(1)" || base == "bit" && (args == "" || args == "(1)"):
+		return familyBool, ""
+	case base == "smallint" || base == "int2" || base == "tinyint" || base == "smallserial":
+		return familySmallInt, ""
+	case base == "bigint" || base == "int8" || base == "bigserial" || base == "serial8":
+		return familyBigInt, ""
+	case base == "int" || base == "integer" || base == "int4" || base == "mediumint" || base == "serial" || base == "serial4":
+		return familyInt, ""
+	case base == "numeric" || base == "decimal" || base == "money":
+		return familyDecimal, args
+	case base == "real" || base == "float4" || base == "float":
+		return familyFloat, ""
+	case base == "double" || base == "double precision" || base == "float8":
+		return familyDouble, ""
+	case base == "varchar" || base == "character varying" || base == "nvarchar" || base == "varchar2":
+		return familyVarchar, args
+	case base == "char" || base == "character" || base == "bpchar" || base == "nchar":
+		return familyChar, args
+	case base == "date":
+		return familyDate, ""
+	case base == "time" || strings.HasPrefix(base, "time without") || base == "timetz" || strings.HasPrefix(base, "time with"):
+		return familyTime, ""
+	case base == "timestamptz" || strings.HasPrefix(base, "timestamp with time zone"):
+		return familyTimestampTZ, ""
+	case strings.HasPrefix(base, "timestamp") || base == "datetime":
+		return familyTimestamp, ""
+	case base == "json" || base == "jsonb":
+		return familyJSON, ""
+	case base == "uuid":
+		return familyUUID, ""
+	case base == "bytea" || strings.HasSuffix(base, "blob") || base == "binary" || base == "varbinary":
+		return familyBinary, ""
+	}
+	return familyText, ""
+}
+
+// MapColumnType translates a column type of one dialect to another.
+// Types without a counterpart become text; key is set for key columns,
+// which MySQL cannot index as TEXT.
+func MapColumnType(from, to DriverType, colType string, key bool) string {
+	if from == to {
+		return colType
+	}
+	family, args := classifyType(colType)
+	switch to {
+	case SQLite:
+		switch family {
+		case familyBool, familySmallInt, familyInt, familyBigInt:
+			return "INTEGER"
+		case familyDecimal:
+			return "NUMERIC"
+		case familyFloat, familyDouble:
+			return "REAL"
+		case familyBinary:
+			return "BLOB"
+		}
+		return "TEXT"
+	case MySQL:
+		switch family {
+		case familyBool:
+			return "tinyint(1)"
+		case familySmallInt:
+			return "smallint"
+		case familyInt:
+			return "int"
+		case familyBigInt:
+			return "bigint"
+		case familyDecimal:
+			if args == "" {
+				args = "(38,10)"
+			}
+			return "decimal" + args
+		case familyFloat:
+			return "float"
+		case familyDouble:
+			return "double"
+		case familyVarchar:
+			if args == "" {
+				args = "(255)"
+			}
+			return "varchar" + args
+		case familyChar:
+			return "char" + args
+		case familyDate:
+			return "date"
+		case familyTime:
+			return "time(6)"
+		case familyTimestamp, familyTimestampTZ:
+			return "datetime(6)"
+		case familyJSON:
+			return "json"
+		case familyUUID:
+			return "char(36)"
+		case familyBinary:
+			return "longblob"
+		}
+		if key {
+			return "varchar(255)"
+		}
+		return "longtext"
+	case Postgres:
+		switch family {
+		case familyBool:
+			return "boolean"
+		case familySmallInt:
+			return "smallint"
+		case familyInt:
+			return "integer"
+		case familyBigInt:
+			return "bigint"
+		case familyDecimal:
+			return "numeric" + args
+		case familyFloat:
+			return "real"
+		case familyDouble:
+			return "double precision"
+		case familyVarchar:
+			return "varchar" + args
+		case familyChar:
+			return "char" + args
+		case familyDate:
+			return "date"
+		case familyTime:
+			return "time"
+		case familyTimestamp:
+			return "timestamp"
+		case familyTimestampTZ:
+			return "timestamptz"
+		case familyJSON:
+			return "jsonb"
+		case familyUUID:
+			return "uuid"
+		case familyBinary:
+			return "bytea"
+		}
+		return "text"
+	}
+	return colType
+}
+
+// CreateTableStatement returns a CREATE TABLE for to with the columns of a
+// from table, keeping NOT NULL and the primary key. Defaults are dialect
+// specific and are not copied.
+func CreateTableStatement(from, to DriverType, table string, columns []Column) string {
+	var defs, keys []string
+	for _, c := range columns {
+		key := c.Key == "PRI"
+		def := quoteIdentFor(to, c.Name) + " " + MapColumnType(from, to, c.Type, key)
+		if !c.Nullable {
+			def += " NOT NULL"
+		}
+		defs = append(defs, def)
+		if key {
+			keys = append(keys, quoteIdentFor(to, c.Name))
+		}
+	}
+	if len(keys) > 0 {
+		defs = append(defs, "PRIMARY KEY ("+strings.Join(keys, ", ")+")")
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", table, strings.Join(defs, ",\n  "))
+}
+
+// ConvertValue rewrites a query result value for a column of colType in to:
+// booleans become what the dialect stores and Go-formatted times become SQL
+// timestamps. NULL and other values pass through.
+func ConvertValue(to DriverType, colType, value string) string {
+	if value == "NULL" {
+		return value
+	}
+	family, _ := classifyType(colType)
+	switch family {
+	case familyBool:
+		b := value == "1" || strings.EqualFold(value, "true") || strings.EqualFold(value, "t")
+		if to == Postgres {
+			return fmt.Sprint(b)
+		}
+		if b {
+			return "1"
+		}
+		return "0"
+	case familyDate, familyTime, familyTimestamp, familyTimestampTZ:
+		ts, err := time.Parse(goTimeLayout, value)
+		if err != nil {
+			return value
+		}
+		return formatTime(family, ts)
+	}
+	return value
+}
+
+// formatTime renders ts as a SQL literal of a date or time family
+func formatTime(family typeFamily, ts time.Time) string {
+	switch family {
+	case familyDate:
+		return ts.Format("2006-01-02")
+	case familyTime:
+		return ts.Format("15:04:05.999999")
+	case familyTimestampTZ:
+		return ts.Format("2006-01-02 15:04:05.999999-07:00")
+	}
+	return ts.Format("2006-01-02 15:04:05.999999")
+}
+
+// importLayouts are the layouts CoerceValue accepts for dates and timestamps
+var importLayouts = []string{
+	goTimeLayout,
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// clockLayouts are the layouts CoerceValue accepts for times of day
+var clockLayouts = []string{"15:04:05.999999999", "15:04"}
+
+// CoerceValue checks an imported value against a column of colType in to and
+// converts it like ConvertValue: integers, numbers and booleans must parse
+// and dates and times are accepted in common layouts. Other values pass through.
+func CoerceValue(to DriverType, colType, value string) (string, error) {
+	family, _ := classifyType(colType)
+	v := strings.TrimSpace(value)
+	switch family {
+	case familySmallInt, familyInt, familyBigInt:
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			if _, err := strconv.ParseUint(v, 10, 64); err != nil {
+				return "", fmt.Errorf("%q is not an integer", value)
+			}
+		}
+		return v, nil
+	case familyDecimal, familyFloat, familyDouble:
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return "", fmt.Errorf("%q is not a number", value)
+		}
+		return v, nil
+	case familyBool:
+		switch strings.ToLower(v) {
+		case "1", "t", "true", "y", "yes":
+			return ConvertValue(to, colType, "true"), nil
+		case "0", "f", "false", "n", "no":
+			return ConvertValue(to, colType, "false"), nil
+		}
+		return "", fmt.Errorf("%q is not a boolean", value)
+	case familyDate, familyTime, familyTimestamp, familyTimestampTZ:
+		layouts := importLayouts
+		if family == familyTime {
+			layouts = append(clockLayouts, layouts...)
+		}
+		for _, layout := range layouts {
+			if ts, err := time.Parse(layout, v); err == nil {
+				return formatTime(family, ts), nil
+			}
+		}
+		return "", fmt.Errorf("%q is not a valid %s", value, strings.ToLower(colType))
+	}
+	return value, nil
+}
+
+// ColumnKind is how export formats that carry their own types store a column
+type ColumnKind int
+
+const (
+	KindText ColumnKind = iota
+	KindBool
+	KindInt
+	KindBigInt
+	KindDecimal
+	KindDouble
+	KindDate
+	KindTimestamp
+	KindTimestampTZ
+)
+
+// KindOf returns the ColumnKind of a column type of any dialect, with the
+// precision and scale of decimals. Decimals without a precision are text.
+func KindOf(colType string) (kind ColumnKind, precision, scale int) {
+	family, args := classifyType(colType)
+	switch family {
+	case familyBool:
+		return KindBool, 0, 0
+	case familySmallInt, familyInt:
+		return KindInt, 0, 0
+	case familyBigInt:
+		return KindBigInt, 0, 0
+	case familyDecimal:
+		if m := typeArgs.FindStringSubmatch(args); m != nil {
+			precision, _ = strconv.Atoi(m[1])
+			scale, _ = strconv.Atoi(m[2])
+			return KindDecimal, precision, scale
+		}
+	case familyFloat, familyDouble:
+		return KindDouble, 0, 0
+	case familyDate:
+		return KindDate, 0, 0
+	case familyTimestamp:
+		return KindTimestamp, 0, 0
+	case familyTimestampTZ:
+		return KindTimestampTZ, 0, 0
+	}
+	return KindText, 0, 0
+}
+
+// quoteIdentFor quotes an identifier for the driver's dialect
+func quoteIdentFor(dt DriverType, name string) string {
+	if dt == MySQL || dt == BigQuery {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
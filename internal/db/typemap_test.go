@@ -0,0 +1,122 @@
+// internal/db/typemap_test.go
+package db
+
+import "testing"
+
+func TestMapColumnType(t *testing.T) {
+	tests := []struct {
+		from, to DriverType
+		colType  string
+		key      bool
+		want     string
+	}{
+		{Postgres, MySQL, "character varying(40)", false, "varchar(40)"},
+		{Postgres, MySQL, "text", false, "longtext"},
+		{Postgres, MySQL, "text", true, "varchar(255)"},
+		{Postgres, MySQL, "timestamp with time zone", false, "datetime(6)"},
+		{Postgres, MySQL, "boolean", false, "tinyint(1)"},
+		{Postgres, SQLite, "numeric(10,2)", false, "NUMERIC"},
+		{Postgres, SQLite, "integer[]", false, "TEXT"},
+		{MySQL, Postgres, "tinyint(1)", false, "boolean"},
+		{MySQL, Postgres, "int(11) unsigned", false, "integer"},
+		{MySQL, Postgres, "decimal(12, 4)", false, "numeric(12,4)"},
+		{MySQL, Postgres, "enum('a','b')", false, "text"},
+		{MySQL, Postgres, "datetime", false, "timestamp"},
+		{MySQL, Postgres, "longblob", false, "bytea"},
+		{SQLite, Postgres, "VARCHAR(20)", false, "varchar(20)"},
+		{SQLite, MySQL, "REAL", false, "float"},
+		{SQLite, SQLite, "anything", false, "anything"},
+	}
+	for _, tt := range tests {
+		if got := MapColumnType(tt.from, tt.to, tt.colType, tt.key); got != tt.want {
+			t.Errorf("MapColumnType(%s, %s, %q) = %q, want %q", tt.from, tt.to, tt.colType, got, tt.want)
+		}
+	}
+}
+
+func TestCreateTableStatement(t *testing.T) {
+	cols := []Column{
+		{Name: "id", Type: "bigint", Key: "PRI"},
+		{Name: "name", Type: "text", Nullable: true},
+		{Name: "active", Type: "boolean"},
+	}
+	want := "CREATE TABLE users (\n  `id` bigint NOT NULL,\n  `name` longtext,\n  `active` tinyint(1) NOT NULL,\n  PRIMARY KEY (`id`)\n)"
+	if got := CreateTableStatement(Postgres, MySQL, "users", cols); got != want {
+		t.Errorf("CreateTableStatement =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	tests := []struct {
+		to      DriverType
+		colType string
+		value   string
+		want    string
+	}{
+		{MySQL, "tinyint(1)", "true", "1"},
+		{Postgres, "boolean", "0", "false"},
+		{MySQL, "datetime(6)", "2024-03-01 10:20:30.5 +0000 UTC", "2024-03-01 10:20:30.5"},
+		{Postgres, "timestamptz", "2024-03-01 10:20:30 +0200 EET", "2024-03-01 10:20:30+02:00"},
+		{SQLite, "DATE", "2024-03-01 00:00:00 +0000 UTC", "2024-03-01"},
+		{MySQL, "datetime", "2024-03-01 10:20:30", "2024-03-01 10:20:30"},
+		{Postgres, "boolean", "NULL", "NULL"},
+	}
+	for _, tt := range tests {
+		if got := ConvertValue(tt.to, tt.colType, tt.value); got != tt.want {
+			t.Errorf("ConvertValue(%s, %q, %q) = %q, want %q", tt.to, tt.colType, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		colType          string
+		kind             ColumnKind
+		precision, scale int
+	}{
+		{"numeric(10, 2)", KindDecimal, 10, 2},
+		{"decimal(12)", KindDecimal, 12, 0},
+		{"numeric", KindText, 0, 0},
+		{"int unsigned", KindInt, 0, 0},
+		{"timestamp with time zone", KindTimestampTZ, 0, 0},
+		{"tinyint(1)", KindBool, 0, 0},
+		{"jsonb", KindText, 0, 0},
+	}
+	for _, tt := range tests {
+		kind, precision, scale := KindOf(tt.colType)
+		if kind != tt.kind || precision != tt.precision || scale != tt.scale {
+			t.Errorf("KindOf(%q) = %v, %d, %d; want %v, %d, %d", tt.colType, kind, precision, scale, tt.kind, tt.precision, tt.scale)
+		}
+	}
+}
+
+func TestCoerceValue(t *testing.T) {
+	tests := []struct {
+		to      DriverType
+		colType string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{Postgres, "integer", " 42 ", "42", false},
+		{Postgres, "bigint unsigned", "18446744073709551615", "18446744073709551615", false},
+		{Postgres, "integer", "4.2", "", true},
+		{MySQL, "decimal(10,2)", "1e3", "1e3", false},
+		{MySQL, "double", "abc", "", true},
+		{MySQL, "tinyint(1)", "yes", "1", false},
+		{Postgres, "boolean", "F", "false", false},
+		{Postgres, "boolean", "maybe", "", true},
+		{Postgres, "timestamp", "2024-03-01T10:20:30Z", "2024-03-01 10:20:30", false},
+		{Postgres, "timestamptz", "2024-03-01T10:20:30+02:00", "2024-03-01 10:20:30+02:00", false},
+		{SQLite, "DATE", "2024-03-01", "2024-03-01", false},
+		{Postgres, "time", "10:20", "10:20:00", false},
+		{Postgres, "date", "03/01/2024", "", true},
+		{Postgres, "text", " padded ", " padded ", false},
+	}
+	for _, tt := range tests {
+		got, err := CoerceValue(tt.to, tt.colType, tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("CoerceValue(%s, %q, %q) = %q, %v; want %q, error %v", tt.to, tt.colType, tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
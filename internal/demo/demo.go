@@ -0,0 +1,32 @@
+// internal/demo/demo.go
+// Package demo builds the sample SQLite database behind ezdb -demo.
+package demo
+
+import (
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"path/filepath"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+//go:embed demo.sql
+var schema string
+
+// FileName is the name of the database Create writes
+const FileName = "demo.db"
+
+// Create writes the sample shop database into dir and returns its path
+func Create(dir string) (string, error) {
+	path := filepath.Join(dir, FileName)
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+	if _, err := db.Exec(schema); err != nil {
+		return "", fmt.Errorf("create demo database: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,102 @@
+-- Sample shop database for ezdb -demo. Data is generated deterministically
+-- so screenshots and CI demos look the same on every run.
+
+CREATE TABLE customers (
+    id         INTEGER PRIMARY KEY,
+    name       TEXT NOT NULL,
+    email      TEXT NOT NULL UNIQUE,
+    country    TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE products (
+    id       INTEGER PRIMARY KEY,
+    name     TEXT NOT NULL,
+    category TEXT NOT NULL,
+    price    NUMERIC(10, 2) NOT NULL CHECK (price > 0),
+    stock    INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE orders (
+    id          INTEGER PRIMARY KEY,
+    customer_id INTEGER NOT NULL REFERENCES customers (id),
+    status      TEXT NOT NULL CHECK (status IN ('pending', 'paid', 'shipped', 'cancelled')),
+    ordered_at  TIMESTAMP NOT NULL
+);
+
+CREATE TABLE order_items (
+    order_id   INTEGER NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products (id),
+    quantity   INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price NUMERIC(10, 2) NOT NULL,
+    PRIMARY KEY (order_id, product_id)
+);
+
+CREATE INDEX idx_orders_customer ON orders (customer_id);
+CREATE INDEX idx_orders_ordered_at ON orders (ordered_at);
+
+INSERT INTO products (id, name, category, price, stock) VALUES
+    (1, 'Espresso Beans 1kg', 'Coffee', 24.90, 120),
+    (2, 'Filter Roast 500g', 'Coffee', 12.50, 200),
+    (3, 'Decaf 250g', 'Coffee', 8.75, 45),
+    (4, 'Green Tea 100g', 'Tea', 6.20, 80),
+    (5, 'Earl Grey 100g', 'Tea', 5.90, 95),
+    (6, 'Chai Blend 200g', 'Tea', 9.40, 0),
+    (7, 'Pour-over Dripper', 'Equipment', 29.00, 35),
+    (8, 'Burr Grinder', 'Equipment', 149.00, 12),
+    (9, 'Gooseneck Kettle', 'Equipment', 59.50, 20),
+    (10, 'Milk Frother', 'Equipment', 39.99, 18),
+    (11, 'Ceramic Mug', 'Accessories', 14.00, 150),
+    (12, 'Travel Tumbler', 'Accessories', 22.00, 60),
+    (13, 'Paper Filters (100)', 'Accessories', 4.50, 300),
+    (14, 'Cleaning Tablets', 'Accessories', 11.25, 75),
+    (15, 'Gift Card', 'Gifts', 50.00, 999);
+
+WITH RECURSIVE
+    first_names (i, name) AS (VALUES (0, 'Ada'), (1, 'Linus'), (2, 'Grace'), (3, 'Ken'), (4, 'Margaret'),
+                                     (5, 'Dennis'), (6, 'Barbara'), (7, 'Edsger'), (8, 'Radia'), (9, 'Alan')),
+    last_names (i, name) AS (VALUES (0, 'Lovelace'), (1, 'Torvalds'), (2, 'Hopper'), (3, 'Thompson'), (4, 'Hamilton'),
+                                    (5, 'Ritchie'), (6, 'Liskov'), (7, 'Dijkstra')),
+    countries (i, name) AS (VALUES (0, 'US'), (1, 'DE'), (2, 'VN'), (3, 'GB'), (4, 'FR'), (5, 'JP'), (6, 'BR')),
+    n (i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 80)
+INSERT INTO customers (id, name, email, country, created_at)
+SELECT n.i,
+       f.name || ' ' || l.name,
+       lower(f.name) || '.' || lower(l.name) || n.i || '@example.com',
+       c.name,
+       datetime('2023-01-01', '+' || (n.i * 97 % 540) || ' days', '+' || (n.i * 13 % 24) || ' hours')
+FROM n
+JOIN first_names f ON f.i = n.i % 10
+JOIN last_names l ON l.i = n.i * 3 % 8
+JOIN countries c ON c.i = n.i * 5 % 7;
+
+WITH RECURSIVE n (i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 600)
+INSERT INTO orders (id, customer_id, status, ordered_at)
+SELECT i,
+       1 + i * 37 % 80,
+       CASE WHEN i % 17 = 0 THEN 'cancelled' WHEN i > 570 THEN 'pending' WHEN i > 520 THEN 'paid' ELSE 'shipped' END,
+       datetime('2024-01-01', '+' || (i * 365 / 600) || ' days', '+' || (i * 7 % 24) || ' hours', '+' || (i * 11 % 60) || ' minutes')
+FROM n;
+
+-- One to three lines per order
+WITH RECURSIVE line (k) AS (VALUES (0), (1), (2))
+INSERT INTO order_items (order_id, product_id, quantity, unit_price)
+SELECT o.id, p.id, 1 + (o.id + line.k) % 3, p.price
+FROM orders o
+JOIN line ON line.k <= o.id % 3
+JOIN products p ON p.id = 1 + (o.id * 7 + line.k * 5) % 15;
+
+CREATE VIEW order_totals AS
+SELECT o.id AS order_id, c.name AS customer, o.status, o.ordered_at,
+       round(sum(i.quantity * i.unit_price), 2) AS total
+FROM orders o
+JOIN customers c ON c.id = o.customer_id
+JOIN order_items i ON i.order_id = o.id
+GROUP BY o.id;
+
+CREATE VIEW monthly_revenue AS
+SELECT strftime('%Y-%m', ordered_at) AS month, count(*) AS orders, round(sum(total), 2) AS revenue
+FROM order_totals
+WHERE status <> 'cancelled'
+GROUP BY month
+ORDER BY month;
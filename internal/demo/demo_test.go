@@ -0,0 +1,33 @@
+// internal/demo/demo_test.go
+package demo
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestCreate(t *testing.T) {
+	path, err := Create(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for table, want := range map[string]int{"customers": 80, "products": 15, "orders": 600, "order_items": 1200} {
+		var got int
+		if err := db.QueryRow("SELECT count(*) FROM " + table).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s has %d rows, want %d", table, got, want)
+		}
+	}
+	var months int
+	if err := db.QueryRow("SELECT count(*) FROM monthly_revenue").Scan(&months); err != nil || months != 12 {
+		t.Errorf("monthly_revenue has %d months (%v), want 12", months, err)
+	}
+}
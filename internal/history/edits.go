@@ -0,0 +1,112 @@
+// internal/history/edits.go
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// EditHistory is the undo/redo timeline of the editor. Each snapshot is the
+// full editor text at an idle boundary; Pos is the snapshot currently shown.
+type EditHistory struct {
+	Snapshots []string
+	Pos       int
+	// Limit caps the number of snapshots kept, 0 for unlimited
+	Limit int
+}
+
+// NewEditHistory returns a timeline starting from text
+func NewEditHistory(text string, limit int) *EditHistory {
+	return &EditHistory{Snapshots: []string{text}, Limit: limit}
+}
+
+// Current returns the snapshot at the cursor
+func (h *EditHistory) Current() string {
+	return h.Snapshots[h.Pos]
+}
+
+// Record adds text as a new snapshot after the cursor, discarding redo states.
+// It reports whether a snapshot was added.
+func (h *EditHistory) Record(text string) bool {
+	if text == h.Current() {
+		return false
+	}
+	h.Snapshots = append(h.Snapshots[:h.Pos+1], text)
+	h.Pos++
+	if h.Limit > 0 && len(h.Snapshots) > h.Limit {
+		drop := len(h.Snapshots) - h.Limit
+		h.Snapshots = h.Snapshots[drop:]
+		h.Pos -= drop
+	}
+	return true
+}
+
+// Undo moves back one snapshot. Unrecorded edits in current are recorded
+// first so redo can return to them.
+func (h *EditHistory) Undo(current string) (string, bool) {
+	h.Record(current)
+	if h.Pos == 0 {
+		return "", false
+	}
+	h.Pos--
+	return h.Current(), true
+}
+
+// Redo moves forward one snapshot
+func (h *EditHistory) Redo(current string) (string, bool) {
+	if current != h.Current() || h.Pos == len(h.Snapshots)-1 {
+		// Typing after an undo starts a new branch; there is nothing to redo
+		return "", false
+	}
+	h.Pos++
+	return h.Current(), true
+}
+
+// SaveEdits persists a profile's edit history; its current snapshot is the scratchpad
+func (s *Store) SaveEdits(profileName string, h *EditHistory) error {
+	snapshots, err := json.Marshal(h.Snapshots)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO edit_history (profile_name, snapshots, position, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(profile_name) DO UPDATE SET
+			snapshots = excluded.snapshots,
+			position = excluded.position,
+			updated_at = excluded.updated_at
+	`, profileName, string(snapshots), h.Pos)
+	return err
+}
+
+// LoadEdits returns a profile's saved edit history, or nil if none was saved
+func (s *Store) LoadEdits(profileName string, limit int) (*EditHistory, error) {
+	var snapshots string
+	var pos int
+	err := s.db.QueryRow(`
+		SELECT snapshots, position FROM edit_history WHERE profile_name = ?
+	`, profileName).Scan(&snapshots, &pos)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	h := &EditHistory{Limit: limit}
+	if err := json.Unmarshal([]byte(snapshots), &h.Snapshots); err != nil {
+		return nil, err
+	}
+	if len(h.Snapshots) == 0 {
+		return nil, nil
+	}
+	h.Pos = min(max(pos, 0), len(h.Snapshots)-1)
+	// Apply a depth lowered since the history was saved
+	if limit > 0 && len(h.Snapshots) > limit {
+		drop := min(len(h.Snapshots)-limit, h.Pos)
+		h.Snapshots = h.Snapshots[drop:]
+		h.Pos -= drop
+		h.Snapshots = h.Snapshots[:min(len(h.Snapshots), limit)]
+	}
+	return h, nil
+}
@@ -0,0 +1,51 @@
+// internal/history/edits_test.go
+package history
+
+import "testing"
+
+func TestEditHistoryUndoRedo(t *testing.T) {
+	h := NewEditHistory("", 0)
+	h.Record("SELECT")
+	h.Record("SELECT 1")
+
+	// Unrecorded typing is kept so redo can come back to it
+	got, ok := h.Undo("SELECT 1 FROM t")
+	if !ok || got != "SELECT 1" {
+		t.Fatalf("Undo = %q, %v; want %q", got, ok, "SELECT 1")
+	}
+	if got, _ := h.Undo(got); got != "SELECT" {
+		t.Fatalf("second Undo = %q, want %q", got, "SELECT")
+	}
+	if got, _ := h.Redo("SELECT"); got != "SELECT 1" {
+		t.Fatalf("Redo = %q, want %q", got, "SELECT 1")
+	}
+	if got, _ := h.Redo("SELECT 1"); got != "SELECT 1 FROM t" {
+		t.Fatalf("second Redo = %q, want %q", got, "SELECT 1 FROM t")
+	}
+	if _, ok := h.Redo("SELECT 1 FROM t"); ok {
+		t.Fatal("Redo past the newest snapshot succeeded")
+	}
+}
+
+func TestEditHistoryBranchDropsRedo(t *testing.T) {
+	h := NewEditHistory("a", 0)
+	h.Record("ab")
+	h.Undo("ab")
+	h.Record("ax")
+	if _, ok := h.Redo("ax"); ok {
+		t.Fatal("Redo after a new edit should have nothing to redo")
+	}
+	if got, _ := h.Undo("ax"); got != "a" {
+		t.Fatalf("Undo = %q, want %q", got, "a")
+	}
+}
+
+func TestEditHistoryLimit(t *testing.T) {
+	h := NewEditHistory("0", 3)
+	for _, s := range []string{"1", "2", "3", "4"} {
+		h.Record(s)
+	}
+	if len(h.Snapshots) != 3 || h.Snapshots[0] != "2" || h.Current() != "4" {
+		t.Fatalf("Snapshots = %v (pos %d), want [2 3 4] at 4", h.Snapshots, h.Pos)
+	}
+}
@@ -1,26 +1,33 @@
 // internal/history/entry.go
 package history
 
-import "time"
+import (
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
 
 // HistoryEntry represents a single query execution in history
 type HistoryEntry struct {
-	ID           int64
-	ProfileName  string
-	Query        string
-	ExecutedAt   time.Time
-	DurationMs   int64
-	RowCount     int
-	Status       string `json:"status"` // "success", "error"
-	ErrorMessage string `json:"error_message,omitempty"`
-	Preview      string `json:"preview,omitempty"` // First 3 rows
+	ID           int64     `json:"-"`
+	ProfileName  string    `json:"profile"`
+	Query        string    `json:"query"`
+	ExecutedAt   time.Time `json:"executed_at"`
+	DurationMs   int64     `json:"duration_ms"`
+	RowCount     int       `json:"rows"`
+	Status       string    `json:"status"` // "success", "error"
+	ErrorMessage string    `json:"error_message,omitempty"`
+	Preview      string    `json:"preview,omitempty"` // First 3 rows
+	RanOn        string    `json:"ran_on,omitempty"`  // Profile the query ran on when not ProfileName
+
+	// Parts of the error the driver reported besides its message
+	ErrorCode     string `json:"error_code,omitempty"` // SQLSTATE or driver error number
+	ErrorDetail   string `json:"error_detail,omitempty"`
+	ErrorHint     string `json:"error_hint,omitempty"`
+	ErrorPosition int    `json:"error_position,omitempty"` // 1-based character in Query, 0 when unknown
 }
 
-// QueryPreview returns a truncated version of the query
+// QueryPreview returns the query cut to maxLen terminal cells
 func (e *HistoryEntry) QueryPreview(maxLen int) string {
-	q := e.Query
-	if len(q) > maxLen {
-		return q[:maxLen-3] + "..."
-	}
-	return q
+	return ansi.Truncate(e.Query, maxLen, "...")
 }
@@ -0,0 +1,137 @@
+// internal/history/portable.go
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timestampFormat is how the sqlite driver stores executed_at
+const timestampFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// importInsert adds an entry unless one of the same profile, query and time exists
+const importInsert = `INSERT INTO history (profile_name, query, executed_at, duration_ms, row_count, status, error_message, preview, ran_on,
+	error_code, error_detail, error_hint, error_position)
+SELECT %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s
+WHERE NOT EXISTS (SELECT 1 FROM history WHERE profile_name = %[1]s AND query = %[2]s AND executed_at = %[3]s);
+`
+
+// ExportFilter selects the entries to export; zero fields match everything
+type ExportFilter struct {
+	ProfileName string
+	Since       time.Time
+	Contains    string
+}
+
+// Export returns the entries matching filter, oldest first
+func (s *Store) Export(filter ExportFilter) ([]HistoryEntry, error) {
+	rows, err := s.db.Query(`
+		SELECT `+entryColumns+`
+		FROM history
+		WHERE (? = '' OR profile_name = ?) AND executed_at >= ? AND query LIKE ?
+		ORDER BY executed_at, id
+	`, filter.ProfileName, filter.ProfileName, filter.Since, "%"+filter.Contains+"%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEntries(rows)
+}
+
+// WriteJSONLines writes one JSON object per entry
+func WriteJSONLines(w io.Writer, entries []HistoryEntry) error {
+	enc := json.NewEncoder(w)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteSQL writes entries as INSERT statements for the history table that
+// skip entries already present, so the script can be replayed safely
+func WriteSQL(w io.Writer, entries []HistoryEntry) error {
+	bw := bufio.NewWriter(w)
+	for _, e := range entries {
+		fmt.Fprintf(bw, importInsert,
+			sqlString(e.ProfileName), sqlString(e.Query), sqlString(e.ExecutedAt.Format(timestampFormat)),
+			strconv.FormatInt(e.DurationMs, 10), strconv.Itoa(e.RowCount),
+			sqlString(e.Status), sqlString(e.ErrorMessage), sqlString(e.Preview), sqlString(e.RanOn),
+			sqlString(e.ErrorCode), sqlString(e.ErrorDetail), sqlString(e.ErrorHint), strconv.Itoa(e.ErrorPosition))
+	}
+	return bw.Flush()
+}
+
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// ImportJSONLines adds the entries of a JSON lines export, skipping ones
+// already in the history, and returns how many were added
+func (s *Store) ImportJSONLines(r io.Reader) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	insert := fmt.Sprintf(importInsert, "?", "?", "?", "?", "?", "?", "?", "?", "?", "?", "?", "?", "?")
+	dec := json.NewDecoder(r)
+	added := 0
+	for line := 1; ; line++ {
+		var e HistoryEntry
+		if err := dec.Decode(&e); err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, fmt.Errorf("entry %d: %w", line, err)
+		}
+		if e.ProfileName == "" || e.Query == "" || e.ExecutedAt.IsZero() {
+			return 0, fmt.Errorf("entry %d: profile, query and executed_at are required", line)
+		}
+		at := e.ExecutedAt.Format(timestampFormat)
+		res, err := tx.Exec(insert, e.ProfileName, e.Query, at,
+			e.DurationMs, e.RowCount, e.Status, e.ErrorMessage, e.Preview, e.RanOn,
+			e.ErrorCode, e.ErrorDetail, e.ErrorHint, e.ErrorPosition,
+			e.ProfileName, e.Query, at)
+		if err != nil {
+			return 0, fmt.Errorf("entry %d: %w", line, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+	return added, tx.Commit()
+}
+
+// ImportSQL runs a script written by WriteSQL and returns how many entries
+// it added
+func (s *Store) ImportSQL(r io.Reader) (int, error) {
+	script, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var before, after int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM history").Scan(&before); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(string(script)); err != nil {
+		return 0, err
+	}
+	if err := tx.QueryRow("SELECT COUNT(*) FROM history").Scan(&after); err != nil {
+		return 0, err
+	}
+	return after - before, tx.Commit()
+}
@@ -0,0 +1,101 @@
+// internal/history/portable_test.go
+package history
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+// newTestStore opens a store in a fresh data directory
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	t.Cleanup(xdg.Reload) // runs after XDG_DATA_HOME is restored
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	xdg.Reload()
+	s, err := NewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := newTestStore(t)
+
+	at := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	for _, e := range []HistoryEntry{
+		{ProfileName: "prod", Query: "SELECT 'it''s'", ExecutedAt: at, DurationMs: 12, RowCount: 1, Status: "success", Preview: "it's"},
+		{ProfileName: "prod", Query: "SELECT nope", ExecutedAt: at.Add(time.Second), Status: "error", ErrorMessage: "no such column",
+			ErrorCode: "42703", ErrorHint: "Check the spelling", ErrorPosition: 8},
+		{ProfileName: "dev", Query: "SELECT 1", ExecutedAt: at, Status: "success"},
+	} {
+		if err := src.Add(&e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := src.Export(ExportFilter{ProfileName: "prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Query != "SELECT 'it''s'" {
+		t.Fatalf("Export = %+v, want the two prod entries oldest first", entries)
+	}
+
+	var jsonl, script bytes.Buffer
+	if err := WriteJSONLines(&jsonl, entries); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteSQL(&script, entries); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newTestStore(t)
+	if n, err := dst.ImportJSONLines(bytes.NewReader(jsonl.Bytes())); err != nil || n != 2 {
+		t.Fatalf("ImportJSONLines = %d, %v; want 2", n, err)
+	}
+	// Importing the same entries again, in either format, adds nothing
+	if n, err := dst.ImportSQL(bytes.NewReader(script.Bytes())); err != nil || n != 0 {
+		t.Fatalf("ImportSQL of duplicates = %d, %v; want 0", n, err)
+	}
+	if n, err := dst.ImportJSONLines(bytes.NewReader(jsonl.Bytes())); err != nil || n != 0 {
+		t.Fatalf("second ImportJSONLines = %d, %v; want 0", n, err)
+	}
+
+	got, err := dst.Export(ExportFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("imported %d entries, want 2", len(got))
+	}
+	e := got[1]
+	if !e.ExecutedAt.Equal(at.Add(time.Second)) || e.Status != "error" || e.ErrorMessage != "no such column" {
+		t.Errorf("imported entry = %+v, want timestamp and status preserved", e)
+	}
+	if e.ErrorCode != "42703" || e.ErrorHint != "Check the spelling" || e.ErrorPosition != 8 {
+		t.Errorf("imported entry = %+v, want the error code, hint and position preserved", e)
+	}
+	if got[0].Preview != "it's" || got[0].DurationMs != 12 || got[0].RowCount != 1 {
+		t.Errorf("imported entry = %+v, want preview, duration and rows preserved", got[0])
+	}
+}
+
+func TestImportSQLScript(t *testing.T) {
+	s := newTestStore(t)
+
+	var script bytes.Buffer
+	entries := []HistoryEntry{{ProfileName: "prod", Query: "SELECT 1", ExecutedAt: time.Now(), Status: "success"}}
+	if err := WriteSQL(&script, entries); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.ImportSQL(bytes.NewReader(script.Bytes())); err != nil || n != 1 {
+		t.Fatalf("ImportSQL = %d, %v; want 1", n, err)
+	}
+	if n, _ := s.Count("prod"); n != 1 {
+		t.Errorf("Count = %d, want 1", n)
+	}
+}
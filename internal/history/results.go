@@ -0,0 +1,58 @@
+// internal/history/results.go
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// StoredResult is the full result set kept with a history entry
+type StoredResult struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+}
+
+// SaveResult keeps the result of history entry id unless it encodes to more
+// than maxBytes, then evicts the profile's oldest results until they fit in
+// budgetBytes. It reports whether the result was kept.
+func (s *Store) SaveResult(id int64, profileName string, result StoredResult, maxBytes, budgetBytes int64) (bool, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return false, err
+	}
+	if int64(len(data)) > maxBytes {
+		return false, nil
+	}
+	if _, err := s.db.Exec(`
+		INSERT OR REPLACE INTO history_results (history_id, profile_name, result, size)
+		VALUES (?, ?, ?, ?)
+	`, id, profileName, string(data), len(data)); err != nil {
+		return false, err
+	}
+	_, err = s.db.Exec(`
+		DELETE FROM history_results WHERE history_id IN (
+			SELECT history_id FROM (
+				SELECT history_id, SUM(size) OVER (ORDER BY history_id DESC) AS kept
+				FROM history_results WHERE profile_name = ?
+			) WHERE kept > ?
+		)
+	`, profileName, budgetBytes)
+	return true, err
+}
+
+// Result returns the stored result of history entry id, nil if none was kept
+func (s *Store) Result(id int64) (*StoredResult, error) {
+	var data string
+	err := s.db.QueryRow("SELECT result FROM history_results WHERE history_id = ?", id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var result StoredResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
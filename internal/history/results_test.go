@@ -0,0 +1,47 @@
+// internal/history/results_test.go
+package history
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveResultEvictsOldest(t *testing.T) {
+	s := newTestStore(t)
+
+	result := StoredResult{Columns: []string{"v"}, Rows: [][]string{{strings.Repeat("x", 100)}}}
+	var ids []int64
+	for i := 0; i < 3; i++ {
+		e := &HistoryEntry{ProfileName: "prod", Query: "SELECT v", ExecutedAt: time.Now(), Status: "success"}
+		if err := s.Add(e); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, e.ID)
+	}
+
+	if kept, err := s.SaveResult(ids[0], "prod", result, 50, 1000); err != nil || kept {
+		t.Fatalf("SaveResult over max = %v, %v; want not kept", kept, err)
+	}
+	// Each result is a little over 100 bytes, so a 250 byte budget holds two
+	for _, id := range ids {
+		if kept, err := s.SaveResult(id, "prod", result, 1000, 250); err != nil || !kept {
+			t.Fatalf("SaveResult(%d) = %v, %v; want kept", id, kept, err)
+		}
+	}
+
+	if got, err := s.Result(ids[0]); err != nil || got != nil {
+		t.Errorf("Result of the oldest entry = %v, %v; want evicted", got, err)
+	}
+	got, err := s.Result(ids[2])
+	if err != nil || got == nil || got.Rows[0][0] != result.Rows[0][0] {
+		t.Fatalf("Result of the newest entry = %v, %v; want the saved rows", got, err)
+	}
+
+	if err := s.Delete(ids[2]); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.Result(ids[2]); got != nil {
+		t.Error("Result survived deleting its history entry")
+	}
+}
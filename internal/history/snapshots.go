@@ -0,0 +1,61 @@
+// internal/history/snapshots.go
+package history
+
+import "time"
+
+// SchemaSnapshot is a named copy of a profile's schema
+type SchemaSnapshot struct {
+	ID          int64
+	ProfileName string
+	Name        string
+	CreatedAt   time.Time
+}
+
+// SaveSnapshot stores a schema, encoded by the caller, under name
+func (s *Store) SaveSnapshot(profileName, name, schema string) (int64, error) {
+	res, err := s.db.Exec(`
+		INSERT INTO schema_snapshots (profile_name, name, created_at, schema)
+		VALUES (?, ?, ?, ?)
+	`, profileName, name, time.Now(), schema)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// Snapshots lists a profile's snapshots, or every profile's when
+// profileName is empty, newest first
+func (s *Store) Snapshots(profileName string) ([]SchemaSnapshot, error) {
+	rows, err := s.db.Query(`
+		SELECT id, profile_name, name, created_at FROM schema_snapshots
+		WHERE ? = '' OR profile_name = ?
+		ORDER BY id DESC
+	`, profileName, profileName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []SchemaSnapshot
+	for rows.Next() {
+		var snap SchemaSnapshot
+		if err := rows.Scan(&snap.ID, &snap.ProfileName, &snap.Name, &snap.CreatedAt); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots, rows.Err()
+}
+
+// SnapshotSchema returns the encoded schema of a snapshot
+func (s *Store) SnapshotSchema(id int64) (string, error) {
+	var schema string
+	err := s.db.QueryRow("SELECT schema FROM schema_snapshots WHERE id = ?", id).Scan(&schema)
+	return schema, err
+}
+
+// DeleteSnapshot removes a snapshot
+func (s *Store) DeleteSnapshot(id int64) error {
+	_, err := s.db.Exec("DELETE FROM schema_snapshots WHERE id = ?", id)
+	return err
+}
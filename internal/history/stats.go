@@ -0,0 +1,98 @@
+// internal/history/stats.go
+package history
+
+// QueryTiming is a distinct successful query with its run times
+type QueryTiming struct {
+	Query string
+	Count int
+	MaxMs int64
+	AvgMs float64
+}
+
+// DayStats counts a day's executions and how many failed
+type DayStats struct {
+	Day    string // YYYY-MM-DD in the local time of execution
+	Total  int
+	Errors int
+}
+
+// Slowest returns a profile's successful queries by their longest run on the
+// profile itself, slowest first
+func (s *Store) Slowest(profileName string, limit int) ([]QueryTiming, error) {
+	rows, err := s.db.Query(`
+		SELECT query, COUNT(*), MAX(duration_ms), AVG(duration_ms) FROM history
+		WHERE profile_name = ? AND status = 'success' AND COALESCE(ran_on, '') = ''
+		GROUP BY query
+		ORDER BY MAX(duration_ms) DESC
+		LIMIT ?
+	`, profileName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var timings []QueryTiming
+	for rows.Next() {
+		var t QueryTiming
+		if err := rows.Scan(&t.Query, &t.Count, &t.MaxMs, &t.AvgMs); err != nil {
+			return nil, err
+		}
+		timings = append(timings, t)
+	}
+	return timings, rows.Err()
+}
+
+// MostRun returns a profile's distinct successful queries, most often run first
+func (s *Store) MostRun(profileName string, limit int) ([]QueryUsage, error) {
+	rows, err := s.db.Query(`
+		SELECT query, COUNT(*) FROM history
+		WHERE profile_name = ? AND status = 'success'
+		GROUP BY query
+		ORDER BY COUNT(*) DESC, MAX(id) DESC
+		LIMIT ?
+	`, profileName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usage []QueryUsage
+	for rows.Next() {
+		var u QueryUsage
+		if err := rows.Scan(&u.Query, &u.Count); err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+// Daily returns a profile's executions and errors of its latest days with
+// any, oldest first
+func (s *Store) Daily(profileName string, days int) ([]DayStats, error) {
+	rows, err := s.db.Query(`
+		SELECT day, total, errors FROM (
+			SELECT substr(executed_at, 1, 10) AS day, COUNT(*) AS total,
+				SUM(CASE WHEN status = 'error' THEN 1 ELSE 0 END) AS errors
+			FROM history
+			WHERE profile_name = ?
+			GROUP BY day
+			ORDER BY day DESC
+			LIMIT ?
+		) ORDER BY day
+	`, profileName, days)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []DayStats
+	for rows.Next() {
+		var d DayStats
+		if err := rows.Scan(&d.Day, &d.Total, &d.Errors); err != nil {
+			return nil, err
+		}
+		stats = append(stats, d)
+	}
+	return stats, rows.Err()
+}
@@ -19,7 +19,11 @@ func NewStore() (*Store, error) {
 	if err != nil {
 		return nil, err
 	}
+	return OpenStore(dbPath)
+}
 
+// OpenStore opens the history store kept in the SQLite file at dbPath
+func OpenStore(dbPath string) (*Store, error) {
 	db, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return nil, err
@@ -44,10 +48,36 @@ func NewStore() (*Store, error) {
 			row_count INTEGER NOT NULL,
 			status TEXT NOT NULL,
 			error_message TEXT,
-			preview TEXT
+			preview TEXT,
+			ran_on TEXT,
+			error_code TEXT,
+			error_detail TEXT,
+			error_hint TEXT,
+			error_position INTEGER
 		);
 		CREATE INDEX IF NOT EXISTS idx_history_profile ON history(profile_name);
 		CREATE INDEX IF NOT EXISTS idx_history_executed_at ON history(executed_at);
+		CREATE TABLE IF NOT EXISTS edit_history (
+			profile_name TEXT PRIMARY KEY,
+			snapshots TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS schema_snapshots (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			profile_name TEXT NOT NULL,
+			name TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			schema TEXT NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_schema_snapshots_profile ON schema_snapshots(profile_name);
+		CREATE TABLE IF NOT EXISTS history_results (
+			history_id INTEGER PRIMARY KEY,
+			profile_name TEXT NOT NULL,
+			result TEXT NOT NULL,
+			size INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_history_results_profile ON history_results(profile_name);
 	`)
 	if err != nil {
 		return nil, err
@@ -57,6 +87,10 @@ func NewStore() (*Store, error) {
 	// This will fail silently if the column already exists or if there's another issue,
 	// which is acceptable for a simple development migration.
 	_, _ = db.Exec("ALTER TABLE history ADD COLUMN preview TEXT")
+	_, _ = db.Exec("ALTER TABLE history ADD COLUMN ran_on TEXT")
+	for _, col := range []string{"error_code TEXT", "error_detail TEXT", "error_hint TEXT", "error_position INTEGER"} {
+		_, _ = db.Exec("ALTER TABLE history ADD COLUMN " + col)
+	}
 
 	store := &Store{db: db}
 	// Run cleanup on initialization
@@ -75,8 +109,9 @@ func (s *Store) Close() error {
 // Add inserts a new execution into history
 func (s *Store) Add(entry *HistoryEntry) error {
 	query := `
-		INSERT INTO history (profile_name, query, executed_at, duration_ms, row_count, status, error_message, preview)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO history (profile_name, query, executed_at, duration_ms, row_count, status, error_message, preview, ran_on,
+			error_code, error_detail, error_hint, error_position)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	res, err := s.db.Exec(query,
 		entry.ProfileName,
@@ -87,6 +122,11 @@ func (s *Store) Add(entry *HistoryEntry) error {
 		entry.Status,
 		entry.ErrorMessage,
 		entry.Preview,
+		entry.RanOn,
+		entry.ErrorCode,
+		entry.ErrorDetail,
+		entry.ErrorHint,
+		entry.ErrorPosition,
 	)
 	if err != nil {
 		return err
@@ -122,7 +162,7 @@ func (s *Store) enforceLimit(profileName string, limit int) error {
 // List returns paginated history entries for a profile
 func (s *Store) List(profileName string, limit, offset int) ([]HistoryEntry, error) {
 	rows, err := s.db.Query(`
-		SELECT id, profile_name, query, executed_at, duration_ms, row_count, status, error_message, preview
+		SELECT `+entryColumns+`
 		FROM history
 		WHERE profile_name = ?
 		ORDER BY executed_at DESC
@@ -139,7 +179,7 @@ func (s *Store) List(profileName string, limit, offset int) ([]HistoryEntry, err
 // Search finds history entries by query substring
 func (s *Store) Search(profileName, querySubstr string, limit int) ([]HistoryEntry, error) {
 	rows, err := s.db.Query(`
-		SELECT id, profile_name, query, executed_at, duration_ms, row_count, status, error_message, preview
+		SELECT `+entryColumns+`
 		FROM history
 		WHERE profile_name = ? AND query LIKE ?
 		ORDER BY executed_at DESC
@@ -153,17 +193,28 @@ func (s *Store) Search(profileName, querySubstr string, limit int) ([]HistoryEnt
 	return scanEntries(rows)
 }
 
+// entryColumns are the history columns scanEntry reads, in order
+const entryColumns = `id, profile_name, query, executed_at, duration_ms, row_count, status, error_message, preview, ran_on,
+		error_code, error_detail, error_hint, error_position`
+
+// scanEntry scans one row of entryColumns
+func scanEntry(row interface{ Scan(...any) error }) (HistoryEntry, error) {
+	var e HistoryEntry
+	var preview, ranOn, errCode, errDetail, errHint sql.NullString
+	var errPosition sql.NullInt64
+	err := row.Scan(&e.ID, &e.ProfileName, &e.Query, &e.ExecutedAt,
+		&e.DurationMs, &e.RowCount, &e.Status, &e.ErrorMessage, &preview, &ranOn,
+		&errCode, &errDetail, &errHint, &errPosition)
+	e.Preview, e.RanOn = preview.String, ranOn.String
+	e.ErrorCode, e.ErrorDetail, e.ErrorHint, e.ErrorPosition = errCode.String, errDetail.String, errHint.String, int(errPosition.Int64)
+	return e, err
+}
+
 // scanEntries scans rows into HistoryEntry slice
 func scanEntries(rows *sql.Rows) ([]HistoryEntry, error) {
 	var entries []HistoryEntry
 	for rows.Next() {
-		var e HistoryEntry
-		var preview sql.NullString
-		err := rows.Scan(&e.ID, &e.ProfileName, &e.Query, &e.ExecutedAt,
-			&e.DurationMs, &e.RowCount, &e.Status, &e.ErrorMessage, &preview)
-		if preview.Valid {
-			e.Preview = preview.String
-		}
+		e, err := scanEntry(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -175,17 +226,11 @@ func scanEntries(rows *sql.Rows) ([]HistoryEntry, error) {
 // GetByID retrieves a single history entry by ID
 func (s *Store) GetByID(id int64) (*HistoryEntry, error) {
 	row := s.db.QueryRow(`
-		SELECT id, profile_name, query, executed_at, duration_ms, row_count, status, error_message, preview
+		SELECT `+entryColumns+`
 		FROM history WHERE id = ?
 	`, id)
 
-	var e HistoryEntry
-	var preview sql.NullString
-	err := row.Scan(&e.ID, &e.ProfileName, &e.Query, &e.ExecutedAt,
-		&e.DurationMs, &e.RowCount, &e.Status, &e.ErrorMessage, &preview)
-	if preview.Valid {
-		e.Preview = preview.String
-	}
+	e, err := scanEntry(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -194,15 +239,20 @@ func (s *Store) GetByID(id int64) (*HistoryEntry, error) {
 
 // Delete removes a history entry by ID
 func (s *Store) Delete(id int64) error {
-	_, err := s.db.Exec("DELETE FROM history WHERE id = ?", id)
+	if _, err := s.db.Exec("DELETE FROM history WHERE id = ?", id); err != nil {
+		return err
+	}
+	_, err := s.db.Exec("DELETE FROM history_results WHERE history_id = ?", id)
 	return err
 }
 
-// cleanup removes history entries older than 90 days
+// cleanup removes history entries older than 90 days and their results
 func (s *Store) cleanup() error {
 	_, err := s.db.Exec(`
 		DELETE FROM history
-		WHERE executed_at < datetime('now', '-90 days')
+		WHERE executed_at < datetime('now', '-90 days');
+		DELETE FROM history_results
+		WHERE history_id NOT IN (SELECT id FROM history)
 	`)
 	return err
 }
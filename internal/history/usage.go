@@ -0,0 +1,33 @@
+// internal/history/usage.go
+package history
+
+// QueryUsage is a distinct successful query and how often it ran
+type QueryUsage struct {
+	Query string
+	Count int
+}
+
+// Usage returns a profile's distinct successful queries, most recently run first
+func (s *Store) Usage(profileName string, limit int) ([]QueryUsage, error) {
+	rows, err := s.db.Query(`
+		SELECT query, COUNT(*) FROM history
+		WHERE profile_name = ? AND status = 'success'
+		GROUP BY query
+		ORDER BY MAX(id) DESC
+		LIMIT ?
+	`, profileName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usage []QueryUsage
+	for rows.Next() {
+		var u QueryUsage
+		if err := rows.Scan(&u.Query, &u.Count); err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
@@ -0,0 +1,581 @@
+// internal/parquet/parquet.go
+package parquet
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is how a column is stored
+type Type int
+
+const (
+	Auto        Type = iota // Inferred from every value of the column
+	String                  // UTF-8 text
+	Boolean                 // true/false, t/f or 1/0
+	Int32                   // 32-bit integer
+	Int64                   // 64-bit integer
+	Double                  // 64-bit float
+	Decimal                 // Exact decimal of at most 18 digits
+	Date                    // Days since the epoch
+	Timestamp               // Microseconds, local time
+	TimestampTZ             // Microseconds, adjusted to UTC
+)
+
+// Column describes a column; Precision and Scale are for decimals
+type Column struct {
+	Name      string
+	Type      Type
+	Precision int
+	Scale     int
+}
+
+// RowGroupRows is how many rows are buffered per row group
+const RowGroupRows = 1 << 16
+
+// magic starts and ends every parquet file
+const magic = "PAR1"
+
+// Physical types, encodings and codecs of the parquet format
+const (
+	physBoolean   = 0
+	physInt32     = 1
+	physInt64     = 2
+	physDouble    = 5
+	physByteArray = 6
+
+	encPlain = 0
+	encRLE   = 3
+
+	codecGzip = 2
+)
+
+// timeLayouts are the formats dates and timestamps are parsed in; the first
+// is how drivers format time.Time values
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+var (
+	integer = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	float   = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+	date    = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+)
+
+// chunk is the metadata of a written column chunk
+type chunk struct {
+	offset       int64
+	values       int64
+	uncompressed int64
+	compressed   int64
+}
+
+type rowGroup struct {
+	rows   int64
+	chunks []chunk
+}
+
+// Writer writes rows of string values, "NULL" for nulls, as a parquet file.
+// Rows are buffered and written a row group at a time; Close writes the footer.
+// With Auto columns every row is first spilled to a temporary file, so the
+// inferred types fit the last row too, and Close writes all row groups.
+type Writer struct {
+	w       io.Writer
+	n       int64
+	columns []Column
+	rows    [][]string
+	groups  []rowGroup
+	total   int64
+	err     error
+
+	infer  []*inference // Per column, nil unless Auto
+	spill  *os.File
+	spillW *bufio.Writer
+}
+
+// NewWriter writes the header of a parquet file of columns to w. Column names
+// are made unique and decimals too wide for 64 bits are stored as strings.
+func NewWriter(w io.Writer, columns []Column) (*Writer, error) {
+	pw := &Writer{w: w, columns: append([]Column(nil), columns...)}
+	seen := map[string]bool{}
+	for i := range pw.columns {
+		c := &pw.columns[i]
+		name := c.Name
+		for n := 2; seen[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s_%d", c.Name, n)
+		}
+		seen[strings.ToLower(name)] = true
+		c.Name = name
+		if c.Type == Decimal && (c.Precision < 1 || c.Precision > 18 || c.Scale > c.Precision) {
+			c.Type = String
+		}
+	}
+	pw.infer = make([]*inference, len(pw.columns))
+	for i, c := range pw.columns {
+		if c.Type != Auto {
+			continue
+		}
+		pw.infer[i] = newInference()
+		if pw.spill == nil {
+			// Unlinked right away where the OS allows, so it goes with the process
+			f, err := os.CreateTemp("", "ezdb-parquet-*.jsonl")
+			if err != nil {
+				return nil, err
+			}
+			os.Remove(f.Name())
+			pw.spill, pw.spillW = f, bufio.NewWriter(f)
+		}
+	}
+	if err := pw.write([]byte(magic)); err != nil {
+		pw.closeSpill()
+		return nil, err
+	}
+	return pw, nil
+}
+
+func (pw *Writer) write(b []byte) error {
+	if pw.err != nil {
+		return pw.err
+	}
+	n, err := pw.w.Write(b)
+	pw.n += int64(n)
+	pw.err = err
+	return err
+}
+
+// WriteRow buffers a row, writing a row group once RowGroupRows are buffered.
+// With Auto columns the row is spilled until Close instead.
+func (pw *Writer) WriteRow(row []string) error {
+	if len(row) != len(pw.columns) {
+		return fmt.Errorf("parquet: row has %d values, want %d", len(row), len(pw.columns))
+	}
+	if pw.spill != nil {
+		for i, in := range pw.infer {
+			if in != nil {
+				in.add(row[i])
+			}
+		}
+		line, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		_, err = pw.spillW.Write(append(line, '\n'))
+		return err
+	}
+	pw.rows = append(pw.rows, append([]string(nil), row...))
+	if len(pw.rows) == RowGroupRows {
+		return pw.flush()
+	}
+	return pw.err
+}
+
+// Close writes the buffered rows and the footer
+func (pw *Writer) Close() error {
+	if pw.spill != nil {
+		err := pw.unspill()
+		pw.closeSpill()
+		if err != nil {
+			return err
+		}
+	}
+	if len(pw.rows) > 0 {
+		if err := pw.flush(); err != nil {
+			return err
+		}
+	}
+	footer := pw.footer()
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(len(footer)))
+	return pw.write(append(footer, magic...))
+}
+
+// unspill resolves the Auto column types from every spilled value, then
+// writes the spilled rows a row group at a time
+func (pw *Writer) unspill() error {
+	for i, in := range pw.infer {
+		if in != nil {
+			pw.columns[i].Type = in.result()
+		}
+	}
+	if err := pw.spillW.Flush(); err != nil {
+		return err
+	}
+	if _, err := pw.spill.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	r := bufio.NewReader(pw.spill)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		var row []string
+		if err := json.Unmarshal(line, &row); err != nil {
+			return err
+		}
+		pw.rows = append(pw.rows, row)
+		if len(pw.rows) == RowGroupRows {
+			if err := pw.flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// closeSpill closes and forgets the spill file
+func (pw *Writer) closeSpill() {
+	pw.spill.Close()
+	pw.spill, pw.spillW = nil, nil
+}
+
+// candidates are the types Auto columns are inferred as, narrowest first
+var candidates = []struct {
+	t  Type
+	ok func(string) bool
+}{
+	// Numbers must also be in range, which the patterns do not check
+	{Int64, func(v string) bool {
+		if !integer.MatchString(v) {
+			return false
+		}
+		_, err := strconv.ParseInt(v, 10, 64)
+		return err == nil
+	}},
+	{Double, func(v string) bool {
+		if !float.MatchString(v) {
+			return false
+		}
+		_, err := strconv.ParseFloat(v, 64)
+		return err == nil
+	}},
+	{Boolean, func(v string) bool { return strings.EqualFold(v, "true") || strings.EqualFold(v, "false") }},
+	{Date, date.MatchString},
+	{TimestampTZ, func(v string) bool { _, zoned, err := parseTime(v); return err == nil && zoned }},
+	{Timestamp, func(v string) bool { _, zoned, err := parseTime(v); return err == nil && !zoned }},
+}
+
+// inference narrows the candidate types of a column value by value
+type inference struct {
+	fits []bool // Per candidate, whether every value so far parses as it
+	seen bool   // A non-NULL value was added
+}
+
+func newInference() *inference {
+	in := &inference{fits: make([]bool, len(candidates))}
+	for i := range in.fits {
+		in.fits[i] = true
+	}
+	return in
+}
+
+func (in *inference) add(v string) {
+	if v == "NULL" {
+		return
+	}
+	in.seen = true
+	for i, c := range candidates {
+		if in.fits[i] && !c.ok(v) {
+			in.fits[i] = false
+		}
+	}
+}
+
+// result returns the narrowest candidate every value fits, else String
+func (in *inference) result() Type {
+	for i, c := range candidates {
+		if in.seen && in.fits[i] {
+			return c.t
+		}
+	}
+	return String
+}
+
+// Infer returns the narrowest type holding every non-NULL value
+func Infer(values []string) Type {
+	in := newInference()
+	for _, v := range values {
+		in.add(v)
+	}
+	return in.result()
+}
+
+// parseTime parses v in one of timeLayouts; zoned is set for layouts with
+// a zone
+func parseTime(v string) (t time.Time, zoned bool, err error) {
+	for i, layout := range timeLayouts {
+		if t, err = time.Parse(layout, v); err == nil {
+			return t, i < 3, nil
+		}
+	}
+	return t, false, fmt.Errorf("invalid time %q", v)
+}
+
+// parseDecimal returns v scaled by 10^scale as an integer
+func parseDecimal(v string, scale int) (int64, error) {
+	whole, frac, _ := strings.Cut(v, ".")
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > scale {
+		return 0, fmt.Errorf("%q has more than %d decimals", v, scale)
+	}
+	return strconv.ParseInt(whole+frac+strings.Repeat("0", scale-len(frac)), 10, 64)
+}
+
+// physical returns the physical type a column type is stored as
+func physical(t Type) int32 {
+	switch t {
+	case Boolean:
+		return physBoolean
+	case Int32, Date:
+		return physInt32
+	case Int64, Decimal, Timestamp, TimestampTZ:
+		return physInt64
+	case Double:
+		return physDouble
+	}
+	return physByteArray
+}
+
+// encode returns the plain encoding of the non-NULL values of column i and
+// whether each row has one
+func (pw *Writer) encode(i int) (values []byte, present []bool, err error) {
+	col := pw.columns[i]
+	var bits, nbits int
+	present = make([]bool, len(pw.rows))
+	for r, row := range pw.rows {
+		v := row[i]
+		if v == "NULL" {
+			continue
+		}
+		present[r] = true
+		switch col.Type {
+		case Boolean:
+			if v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "t") {
+				bits |= 1 << nbits
+			} else if v != "0" && !strings.EqualFold(v, "false") && !strings.EqualFold(v, "f") {
+				err = fmt.Errorf("invalid boolean %q", v)
+			}
+			if nbits++; nbits == 8 {
+				values = append(values, byte(bits))
+				bits, nbits = 0, 0
+			}
+		case Int32:
+			var n int64
+			if n, err = strconv.ParseInt(v, 10, 32); err == nil {
+				values = binary.LittleEndian.AppendUint32(values, uint32(n))
+			}
+		case Int64, Decimal:
+			var n int64
+			if col.Type == Decimal {
+				n, err = parseDecimal(v, col.Scale)
+			} else {
+				n, err = strconv.ParseInt(v, 10, 64)
+			}
+			values = binary.LittleEndian.AppendUint64(values, uint64(n))
+		case Double:
+			var f float64
+			f, err = strconv.ParseFloat(v, 64)
+			values = binary.LittleEndian.AppendUint64(values, math.Float64bits(f))
+		case Date, Timestamp, TimestampTZ:
+			var t time.Time
+			if t, _, err = parseTime(v); err != nil {
+				break
+			}
+			if col.Type != TimestampTZ {
+				// Keep the wall clock of the value
+				y, mo, d := t.Date()
+				t = time.Date(y, mo, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+			}
+			if col.Type == Date {
+				values = binary.LittleEndian.AppendUint32(values, uint32(int32(t.Unix()/86400)))
+			} else {
+				values = binary.LittleEndian.AppendUint64(values, uint64(t.UnixMicro()))
+			}
+		default:
+			values = binary.LittleEndian.AppendUint32(values, uint32(len(v)))
+			values = append(values, v...)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("parquet: column %s, row %d: %w", col.Name, pw.total+int64(r)+1, err)
+		}
+	}
+	if nbits > 0 {
+		values = append(values, byte(bits))
+	}
+	return values, present, nil
+}
+
+// definitionLevels encodes which rows have a value as runs of the RLE
+// hybrid encoding, prefixed by their length
+func definitionLevels(present []bool) []byte {
+	var runs []byte
+	for i := 0; i < len(present); {
+		j := i
+		for j < len(present) && present[j] == present[i] {
+			j++
+		}
+		runs = binary.AppendUvarint(runs, uint64(j-i)<<1)
+		if present[i] {
+			runs = append(runs, 1)
+		} else {
+			runs = append(runs, 0)
+		}
+		i = j
+	}
+	return append(binary.LittleEndian.AppendUint32(nil, uint32(len(runs))), runs...)
+}
+
+// flush writes the buffered rows as a row group of one gzip page per column
+func (pw *Writer) flush() error {
+	group := rowGroup{rows: int64(len(pw.rows))}
+	for i := range pw.columns {
+		values, present, err := pw.encode(i)
+		if err != nil {
+			return err
+		}
+		page := append(definitionLevels(present), values...)
+
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		gz.Write(page)
+		if err := gz.Close(); err != nil {
+			return err
+		}
+
+		var h thrift
+		h.beginStruct(0)
+		h.i32(1, 0) // DATA_PAGE
+		h.i32(2, int32(len(page)))
+		h.i32(3, int32(compressed.Len()))
+		h.beginStruct(5)
+		h.i32(1, int32(len(pw.rows)))
+		h.i32(2, encPlain)
+		h.i32(3, encRLE)
+		h.i32(4, encRLE)
+		h.endStruct()
+		h.endStruct()
+
+		c := chunk{
+			offset:       pw.n,
+			values:       int64(len(pw.rows)),
+			uncompressed: int64(len(h.b) + len(page)),
+			compressed:   int64(len(h.b) + compressed.Len()),
+		}
+		if err := pw.write(h.b); err != nil {
+			return err
+		}
+		if err := pw.write(compressed.Bytes()); err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, c)
+	}
+	pw.groups = append(pw.groups, group)
+	pw.total += group.rows
+	pw.rows = pw.rows[:0]
+	return nil
+}
+
+// footer encodes the file metadata: the schema and where each chunk is
+func (pw *Writer) footer() []byte {
+	var t thrift
+	t.beginStruct(0)
+	t.i32(1, 1) // version
+	t.list(2, tStruct, len(pw.columns)+1)
+	t.beginStruct(0)
+	t.str(4, "schema")
+	t.i32(5, int32(len(pw.columns)))
+	t.endStruct()
+	for _, c := range pw.columns {
+		t.beginStruct(0)
+		t.i32(1, physical(c.Type))
+		t.i32(3, 1) // OPTIONAL
+		t.str(4, c.Name)
+		switch c.Type {
+		case String:
+			t.i32(6, 0) // UTF8
+			t.beginStruct(10)
+			t.beginStruct(1)
+			t.endStruct()
+			t.endStruct()
+		case Decimal:
+			t.i32(6, 5) // DECIMAL
+			t.i32(7, int32(c.Scale))
+			t.i32(8, int32(c.Precision))
+			t.beginStruct(10)
+			t.beginStruct(5)
+			t.i32(1, int32(c.Scale))
+			t.i32(2, int32(c.Precision))
+			t.endStruct()
+			t.endStruct()
+		case Date:
+			t.i32(6, 6) // DATE
+			t.beginStruct(10)
+			t.beginStruct(6)
+			t.endStruct()
+			t.endStruct()
+		case Timestamp, TimestampTZ:
+			if c.Type == TimestampTZ {
+				t.i32(6, 10) // TIMESTAMP_MICROS
+			}
+			t.beginStruct(10)
+			t.beginStruct(8)
+			t.bool(1, c.Type == TimestampTZ)
+			t.beginStruct(2)
+			t.beginStruct(2) // MICROS
+			t.endStruct()
+			t.endStruct()
+			t.endStruct()
+			t.endStruct()
+		}
+		t.endStruct()
+	}
+	t.i64(3, pw.total)
+	t.list(4, tStruct, len(pw.groups))
+	for _, g := range pw.groups {
+		var size int64
+		t.beginStruct(0)
+		t.list(1, tStruct, len(g.chunks))
+		for i, c := range g.chunks {
+			size += c.uncompressed
+			t.beginStruct(0)
+			t.i64(2, c.offset)
+			t.beginStruct(3)
+			t.i32(1, physical(pw.columns[i].Type))
+			t.list(2, tI32, 2)
+			t.zigzag(encPlain)
+			t.zigzag(encRLE)
+			t.list(3, tBinary, 1)
+			t.varint(uint64(len(pw.columns[i].Name)))
+			t.b = append(t.b, pw.columns[i].Name...)
+			t.i32(4, codecGzip)
+			t.i64(5, c.values)
+			t.i64(6, c.uncompressed)
+			t.i64(7, c.compressed)
+			t.i64(9, c.offset)
+			t.endStruct()
+			t.endStruct()
+		}
+		t.i64(2, size)
+		t.i64(3, g.rows)
+		t.endStruct()
+	}
+	t.str(6, "ezdb")
+	t.endStruct()
+	return t.b
+}
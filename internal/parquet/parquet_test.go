@@ -0,0 +1,178 @@
+// internal/parquet/parquet_test.go
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"strconv"
+	"testing"
+)
+
+// readStruct decodes a Thrift compact struct into its fields by id; lists
+// become []any and nested structs map[int16]any
+func readStruct(t *testing.T, b *bytes.Reader) map[int16]any {
+	fields := map[int16]any{}
+	var last int16
+	for {
+		h, err := b.ReadByte()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h == 0 {
+			return fields
+		}
+		id := last + int16(h>>4)
+		if h>>4 == 0 {
+			z, _ := binary.ReadUvarint(b)
+			id = int16(int64(z>>1) ^ -int64(z&1))
+		}
+		last = id
+		fields[id] = readValue(t, b, h&0x0f)
+	}
+}
+
+func readValue(t *testing.T, b *bytes.Reader, typ byte) any {
+	switch typ {
+	case tTrue:
+		return true
+	case tFalse:
+		return false
+	case tI32, tI64:
+		z, _ := binary.ReadUvarint(b)
+		return int64(z>>1) ^ -int64(z&1)
+	case tBinary:
+		n, _ := binary.ReadUvarint(b)
+		s := make([]byte, n)
+		io.ReadFull(b, s)
+		return string(s)
+	case tList:
+		h, _ := b.ReadByte()
+		n := int(h >> 4)
+		if n == 15 {
+			u, _ := binary.ReadUvarint(b)
+			n = int(u)
+		}
+		var list []any
+		for i := 0; i < n; i++ {
+			list = append(list, readValue(t, b, h&0x0f))
+		}
+		return list
+	case tStruct:
+		return readStruct(t, b)
+	}
+	t.Fatalf("unexpected thrift type %d", typ)
+	return nil
+}
+
+func TestWriterFile(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, []Column{
+		{Name: "id", Type: Auto},
+		{Name: "price", Type: Decimal, Precision: 10, Scale: 2},
+		{Name: "id", Type: Auto},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range [][]string{{"1", "9.5", "a"}, {"2", "NULL", "b"}, {"3", "10", "NULL"}} {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	if string(data[:4]) != magic || string(data[len(data)-4:]) != magic {
+		t.Fatal("missing PAR1 magic")
+	}
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	meta := readStruct(t, bytes.NewReader(data[len(data)-8-size:len(data)-8]))
+	if meta[3] != int64(3) {
+		t.Errorf("num_rows = %v, want 3", meta[3])
+	}
+
+	schema := meta[2].([]any)
+	wantSchema := []struct {
+		name string
+		phys int64
+	}{{"id", physInt64}, {"price", physInt64}, {"id_2", physByteArray}}
+	for i, want := range wantSchema {
+		el := schema[i+1].(map[int16]any)
+		if el[4] != want.name || el[1] != want.phys {
+			t.Errorf("column %d = %v %v, want %s %d", i, el[4], el[1], want.name, want.phys)
+		}
+	}
+
+	// The price chunk holds 950 and 1000 after the definition levels
+	chunk := meta[4].([]any)[0].(map[int16]any)[1].([]any)[1].(map[int16]any)[3].(map[int16]any)
+	page := bytes.NewReader(data[chunk[9].(int64):])
+	header := readStruct(t, page)
+	gz, err := gzip.NewReader(io.LimitReader(page, header[3].(int64)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, _ := io.ReadAll(gz)
+	levels := int(binary.LittleEndian.Uint32(values))
+	values = values[4+levels:]
+	if len(values) != 16 || binary.LittleEndian.Uint64(values) != 950 || binary.LittleEndian.Uint64(values[8:]) != 1000 {
+		t.Errorf("price values = %v, want 950 and 1000", values)
+	}
+}
+
+// TestWriterLateValue writes a value past the first row group that does not
+// fit the type its values so far suggest
+func TestWriterLateValue(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, []Column{{Name: "code", Type: Auto}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < RowGroupRows; i++ {
+		if err := w.WriteRow([]string{strconv.Itoa(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteRow([]string{"n/a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data := buf.Bytes()
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	meta := readStruct(t, bytes.NewReader(data[len(data)-8-size:len(data)-8]))
+	if meta[3] != int64(RowGroupRows+1) || len(meta[4].([]any)) != 2 {
+		t.Errorf("num_rows = %v in %d row groups, want %d in 2", meta[3], len(meta[4].([]any)), RowGroupRows+1)
+	}
+	if el := meta[2].([]any)[1].(map[int16]any); el[1] != int64(physByteArray) {
+		t.Errorf("code stored as physical type %v, want byte array", el[1])
+	}
+}
+
+func TestInfer(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   Type
+	}{
+		{[]string{"1", "NULL", "-20"}, Int64},
+		{[]string{"1", "2.5", "1e3"}, Double},
+		{[]string{"007"}, String},
+		{[]string{"1", "9223372036854775808"}, Double},
+		{[]string{"1e400"}, String},
+		{[]string{"true", "FALSE"}, Boolean},
+		{[]string{"2024-03-01"}, Date},
+		{[]string{"2024-03-01 10:20:30 +0000 UTC"}, TimestampTZ},
+		{[]string{"2024-03-01 10:20:30"}, Timestamp},
+		{[]string{"NULL"}, String},
+	}
+	for _, tt := range tests {
+		if got := Infer(tt.values); got != tt.want {
+			t.Errorf("Infer(%q) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
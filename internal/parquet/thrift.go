@@ -0,0 +1,91 @@
+// internal/parquet/thrift.go
+package parquet
+
+import "encoding/binary"
+
+// Thrift compact protocol types
+const (
+	tTrue   = 1
+	tFalse  = 2
+	tI32    = 5
+	tI64    = 6
+	tBinary = 8
+	tList   = 9
+	tStruct = 12
+)
+
+// thrift encodes the file metadata and page headers in the Thrift compact
+// protocol; structs nest through beginStruct/endStruct
+type thrift struct {
+	b     []byte
+	last  int16
+	stack []int16
+}
+
+func (t *thrift) varint(v uint64) {
+	t.b = binary.AppendUvarint(t.b, v)
+}
+
+func (t *thrift) zigzag(v int64) {
+	t.varint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (t *thrift) field(id int16, typ byte) {
+	if d := id - t.last; d > 0 && d <= 15 {
+		t.b = append(t.b, byte(d)<<4|typ)
+	} else {
+		t.b = append(t.b, typ)
+		t.zigzag(int64(id))
+	}
+	t.last = id
+}
+
+func (t *thrift) i32(id int16, v int32) {
+	t.field(id, tI32)
+	t.zigzag(int64(v))
+}
+
+func (t *thrift) i64(id int16, v int64) {
+	t.field(id, tI64)
+	t.zigzag(v)
+}
+
+func (t *thrift) str(id int16, s string) {
+	t.field(id, tBinary)
+	t.varint(uint64(len(s)))
+	t.b = append(t.b, s...)
+}
+
+func (t *thrift) bool(id int16, v bool) {
+	if v {
+		t.field(id, tTrue)
+	} else {
+		t.field(id, tFalse)
+	}
+}
+
+// list starts a list field of n elements of typ
+func (t *thrift) list(id int16, typ byte, n int) {
+	t.field(id, tList)
+	if n < 15 {
+		t.b = append(t.b, byte(n)<<4|typ)
+	} else {
+		t.b = append(t.b, 0xf0|typ)
+		t.varint(uint64(n))
+	}
+}
+
+// beginStruct starts a struct field; id 0 starts a list element
+func (t *thrift) beginStruct(id int16) {
+	if id != 0 {
+		t.field(id, tStruct)
+	}
+	t.stack = append(t.stack, t.last)
+	t.last = 0
+}
+
+func (t *thrift) endStruct() {
+	t.b = append(t.b, 0)
+	t.last = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+}
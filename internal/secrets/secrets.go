@@ -0,0 +1,143 @@
+// internal/secrets/secrets.go
+// Package secrets fetches profile passwords from external providers at connect time.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/nhath/ezdb/internal/config"
+)
+
+// Provider resolves a password when a profile connects
+type Provider interface {
+	Password(ctx context.Context) (string, error)
+}
+
+// ForProfile returns the provider configured on the profile, or nil if
+// its password is stored in the config or keyring
+func ForProfile(p *config.Profile) Provider {
+	switch {
+	case p.PasswordCmd != "":
+		return Command(p.PasswordCmd)
+	case p.VaultPath != "":
+		return Vault{Path: p.VaultPath}
+	}
+	return nil
+}
+
+// Command is a shell command printing the password, e.g.
+// "op read op://Private/prod-db/password"
+type Command string
+
+// Password runs the command and returns its output without the trailing newline
+func (c Command) Password(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", string(c))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("password_cmd: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("password_cmd: %w", err)
+	}
+	password := strings.TrimRight(string(out), "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("password_cmd printed nothing")
+	}
+	return password, nil
+}
+
+// Vault reads a password from HashiCorp Vault. Path is the secret's API
+// path with an optional field, e.g. "secret/data/prod-db#password" for KV
+// version 2; the field defaults to "password". VAULT_ADDR, VAULT_TOKEN (or
+// ~/.vault-token) and VAULT_NAMESPACE locate the server and authenticate.
+type Vault struct {
+	Path string
+
+	// Addr and Token override the environment
+	Addr  string
+	Token string
+}
+
+// Password fetches the secret and returns its field
+func (v Vault) Password(ctx context.Context) (string, error) {
+	path, field, _ := strings.Cut(v.Path, "#")
+	if field == "" {
+		field = "password"
+	}
+	addr := v.Addr
+	if addr == "" {
+		addr = os.Getenv("VAULT_ADDR")
+	}
+	if addr == "" {
+		return "", fmt.Errorf("vault: VAULT_ADDR is not set")
+	}
+	token, err := v.token()
+	if err != nil {
+		return "", err
+	}
+
+	url := strings.TrimRight(addr, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Data   map[string]any `json:"data"`
+		Errors []string       `json:"errors"`
+	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault %s: %s %s", path, resp.Status, strings.Join(body.Errors, "; "))
+	}
+	if decodeErr != nil {
+		return "", fmt.Errorf("vault %s: %w", path, decodeErr)
+	}
+
+	data := body.Data
+	if inner, ok := data["data"].(map[string]any); ok {
+		data = inner // KV version 2 nests the secret
+	}
+	password, ok := data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("vault %s: no field %q", path, field)
+	}
+	return password, nil
+}
+
+func (v Vault) token() (string, error) {
+	if v.Token != "" {
+		return v.Token, nil
+	}
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		return token, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("vault: VAULT_TOKEN is not set")
+	}
+	token, err := os.ReadFile(filepath.Join(home, ".vault-token"))
+	if err != nil {
+		return "", fmt.Errorf("vault: VAULT_TOKEN is not set and ~/.vault-token is unreadable")
+	}
+	return strings.TrimSpace(string(token)), nil
+}
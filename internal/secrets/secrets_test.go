@@ -0,0 +1,56 @@
+// internal/secrets/secrets_test.go
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	got, err := Command("printf 's3cret\n'").Password(context.Background())
+	if err != nil || got != "s3cret" {
+		t.Errorf("Password() = %q, %v, want s3cret", got, err)
+	}
+	if _, err := Command("echo denied >&2; exit 1").Password(context.Background()); err == nil {
+		t.Error("failing command returned no error")
+	}
+}
+
+func TestVault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "tok" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/prod-db":
+			w.Write([]byte(`{"data":{"data":{"password":"v2pass","user":"app"}}}`))
+		case "/v1/kv/prod-db":
+			w.Write([]byte(`{"data":{"pw":"v1pass"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		path, token, want string
+		wantErr           bool
+	}{
+		{"secret/data/prod-db", "tok", "v2pass", false},
+		{"kv/prod-db#pw", "tok", "v1pass", false},
+		{"secret/data/prod-db#missing", "tok", "", true},
+		{"secret/data/other", "tok", "", true},
+		{"secret/data/prod-db", "bad", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Vault{Path: tt.path, Addr: srv.URL, Token: tt.token}.Password(context.Background())
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("Vault{%s}.Password() = %q, %v, want %q", tt.path, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// internal/session/session.go
+// Package session keeps the UI state of the last run so the next start can restore it.
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+// State is what the next start offers to restore
+type State struct {
+	Profile    string    `json:"profile"`
+	Editor     string    `json:"editor,omitempty"`
+	HistoryID  int64     `json:"history_id,omitempty"` // Selected history entry
+	SchemaOpen bool      `json:"schema_open,omitempty"`
+	Table      string    `json:"table,omitempty"` // Highlighted in the schema browser
+	SavedAt    time.Time `json:"saved_at"`
+}
+
+// DefaultPath returns the session file under the XDG state directory
+func DefaultPath() (string, error) {
+	return xdg.StateFile("ezdb/session.json")
+}
+
+// Load reads the session saved at path, or nil if there is none
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if s.Profile == "" {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+// Save replaces the session at path, writing a temporary file first so a
+// crash never leaves half a session behind
+func Save(path string, s State) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
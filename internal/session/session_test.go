@@ -0,0 +1,32 @@
+// internal/session/session_test.go
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "session.json")
+	if s, err := Load(path); s != nil || err != nil {
+		t.Fatalf("Load() without a file = %v, %v", s, err)
+	}
+
+	want := State{Profile: "prod", Editor: "SELECT 1", HistoryID: 42, SchemaOpen: true, Table: "orders", SavedAt: time.Unix(1700000000, 0).UTC()}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil || got == nil || *got != want {
+		t.Fatalf("Load() = %+v, %v, want %+v", got, err, want)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() accepted a broken file")
+	}
+}
@@ -0,0 +1,130 @@
+// internal/ui/actions.go
+// Action registry: every key action with its description, hint, help section and contexts, shared by dispatch, help, hints and the keybindings editor.
+package ui
+
+import (
+	"reflect"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/ui/components/schemabrowser"
+)
+
+// action is one thing a key does
+type action struct {
+	Name  string   // KeyMap field, or a name of its own for fixed keys
+	Desc  string   // Help popup description
+	Hint  string   // Bottom help line label, "" when never hinted
+	Group string   // Help popup section
+	Ctx   []string // Contexts it is active in; none keeps it out of the help
+	Fixed []string // Keys of actions that cannot be rebound
+}
+
+// fixedActions are keys hardcoded in components, so outside the KeyMap
+var fixedActions = []action{
+	{Name: "SchemaTabs", Desc: "Switch tabs", Group: "Navigation", Ctx: []string{"schema"}, Fixed: []string{schemabrowser.KeyPrevTab, schemabrowser.KeyNextTab}},
+	{Name: "SchemaBrowse", Desc: "Browse table data", Group: "Actions", Ctx: []string{"schema"}, Fixed: []string{schemabrowser.KeyBrowse}},
+	{Name: "SchemaTemplates", Desc: "Query templates", Group: "Actions", Ctx: []string{"schema"}, Fixed: []string{schemabrowser.KeyTemplates}},
+	{Name: "SchemaExport", Desc: "Export table", Hint: "Export", Group: "Actions", Ctx: []string{"schema"}, Fixed: []string{schemabrowser.KeyExport}},
+	{Name: "SchemaImport", Desc: "Import", Hint: "Import", Group: "Actions", Ctx: []string{"schema"}, Fixed: []string{schemabrowser.KeyImport}},
+	{Name: "SchemaGenerate", Desc: "Generate rows", Hint: "Generate rows", Group: "Actions", Ctx: []string{"schema"}, Fixed: []string{schemabrowser.KeyGenerate}},
+	{Name: "SchemaCopy", Desc: "Copy table to a profile", Hint: "Copy to profile", Group: "Actions", Ctx: []string{"schema"}, Fixed: []string{schemabrowser.KeyCopy}},
+	{Name: "SchemaTruncate", Desc: "Truncate table", Hint: "Truncate", Group: "Actions", Ctx: []string{"schema"}, Fixed: []string{schemabrowser.KeyTruncate}},
+	{Name: "SchemaDrop", Desc: "Drop table", Hint: "Drop table", Group: "Actions", Ctx: []string{"schema"}, Fixed: []string{schemabrowser.KeyDrop}},
+	{Name: "SchemaRunFile", Desc: "Run a .sql file", Group: "Query", Ctx: []string{"schema"}, Fixed: []string{schemabrowser.KeyRunFile}},
+	{Name: "HelpEditKeys", Desc: "Edit keybindings", Fixed: []string{"e"}},
+	{Name: "TemplateManage", Desc: "Manage query templates", Fixed: []string{"m"}},
+
+	// Typed into the editor rather than pressed
+	{Name: "Commit", Desc: "Commit the open transaction", Hint: "Commit tx", Group: "Query", Fixed: []string{"COMMIT"}},
+	{Name: "Rollback", Desc: "Roll back the open transaction", Hint: "Rollback tx", Group: "Query", Fixed: []string{"ROLLBACK"}},
+}
+
+// actionRegistry holds every action: the KeyMap fields with a help tag in struct
+// order, then fixedActions
+var actionRegistry = registerActions()
+
+func registerActions() []action {
+	var list []action
+	t := reflect.TypeOf(config.KeyMap{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		desc := field.Tag.Get("help")
+		if desc == "" {
+			continue
+		}
+		list = append(list, action{
+			Name:  field.Name,
+			Desc:  desc,
+			Hint:  field.Tag.Get("hint"),
+			Group: field.Tag.Get("group"),
+			Ctx:   strings.Split(field.Tag.Get("ctx"), ","),
+		})
+	}
+	return append(list, fixedActions...)
+}
+
+// actionNamed returns the registered action called name
+func actionNamed(name string) (action, bool) {
+	for _, a := range actionRegistry {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return action{}, false
+}
+
+// rebindable reports whether the action's keys live in the KeyMap
+func (a action) rebindable() bool {
+	return a.Fixed == nil
+}
+
+// keys returns the keys bound to the action
+func (a action) keys(keys *config.KeyMap) []string {
+	if !a.rebindable() {
+		return a.Fixed
+	}
+	return keysOf(keys, a.Name)
+}
+
+// appliesTo reports whether the action is active in the given context
+func (a action) appliesTo(ctx HelpContext) bool {
+	for _, c := range a.Ctx {
+		if c == "global" || c == ctx.tag() {
+			return true
+		}
+	}
+	return false
+}
+
+// sharesContext reports whether two actions can be triggered in the same UI context
+func (a action) sharesContext(b action) bool {
+	for _, x := range a.Ctx {
+		for _, y := range b.Ctx {
+			if x == "global" || y == "global" || x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pressed reports whether msg triggers the named action. KeyMap actions
+// can also be matched on their field directly with matchKey.
+func (m Model) pressed(msg tea.KeyMsg, name string) bool {
+	a, ok := actionNamed(name)
+	return ok && matchKey(msg, a.keys(&m.config.Keys))
+}
+
+// keysOf returns the keys bound to a KeyMap field
+func keysOf(keys *config.KeyMap, field string) []string {
+	bound, _ := reflect.ValueOf(keys).Elem().FieldByName(field).Interface().([]string)
+	return bound
+}
+
+// setKeys replaces the keys bound to a KeyMap field
+func setKeys(keys *config.KeyMap, field string, bound []string) {
+	reflect.ValueOf(keys).Elem().FieldByName(field).Set(reflect.ValueOf(bound))
+}
@@ -0,0 +1,226 @@
+// internal/ui/activity.go
+// Activity view: the server's sessions (pg_stat_activity, PROCESSLIST) with cancel and terminate.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// activityRefresh is how often the open activity view lists sessions again
+const activityRefresh = 3 * time.Second
+
+// openActivityPopup opens the activity view.
+func (m *Model) openActivityPopup() {
+	if m.showActivityPopup {
+		return
+	}
+	m.showActivityPopup = true
+	m.autocompleting = false
+	m.activityIdx = 0
+	m.activityConfirm = ""
+	m.popupStack.Push("activity", func(m *Model) {
+		m.showActivityPopup = false
+		m.activityConfirm = ""
+	})
+}
+
+// listSessionsCmd lists the sessions; only the latest listing keeps refreshing
+func (m *Model) listSessionsCmd(monitor db.ActivityMonitor) tea.Cmd {
+	m.activitySeq++
+	seq := m.activitySeq
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		sessions, err := monitor.Sessions(ctx)
+		return ActivityMsg{Seq: seq, Sessions: sessions, Err: err}
+	}
+}
+
+// handleActivityKeys handles keys while the activity view is open. Cancel
+// and terminate wait for y.
+func (m Model) handleActivityKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	monitor, ok := m.driver.(db.ActivityMonitor)
+	if !ok {
+		m.closeTopPopup()
+		return m, nil, true
+	}
+
+	if m.activityConfirm != "" {
+		action := m.activityConfirm
+		m.activityConfirm = ""
+		if msg.String() == "y" || msg.String() == "Y" {
+			return m, m.stopSessionCmd(monitor, action, m.activityTarget), true
+		}
+		return m, nil, true
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.activityIdx > 0 {
+			m.activityIdx--
+		}
+	case "down", "j":
+		if m.activityIdx < len(m.sessions)-1 {
+			m.activityIdx++
+		}
+	case "r":
+		return m, m.listSessionsCmd(monitor), true
+	case "c":
+		if m.profile != nil && m.config.ReadOnly(m.profile) {
+			m.errorMsg = errReadOnly.Error()
+		} else if m.activityIdx < len(m.sessions) {
+			m.activityConfirm, m.activityTarget = "cancel", m.sessions[m.activityIdx].ID
+		}
+	case "x":
+		if m.profile != nil && m.config.ReadOnly(m.profile) {
+			m.errorMsg = errReadOnly.Error()
+		} else if m.activityIdx < len(m.sessions) {
+			m.activityConfirm, m.activityTarget = "terminate", m.sessions[m.activityIdx].ID
+		}
+	default:
+		if matchKey(msg, m.config.Keys.Activity) {
+			m.closeTopPopup()
+		}
+	}
+	return m, nil, true
+}
+
+// handleActivity shows a session listing, keeping the selected session
+// selected, and schedules the next refresh
+func (m Model) handleActivity(msg ActivityMsg) (Model, tea.Cmd) {
+	if !m.showActivityPopup || msg.Seq != m.activitySeq {
+		return m, nil
+	}
+	if msg.Err != nil {
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	}
+	selected := ""
+	if m.activityIdx < len(m.sessions) {
+		selected = m.sessions[m.activityIdx].ID
+	}
+	m.sessions = msg.Sessions
+	m.activityIdx = min(m.activityIdx, max(len(m.sessions)-1, 0))
+	for i, s := range m.sessions {
+		if s.ID == selected {
+			m.activityIdx = i
+		}
+	}
+	return m, tea.Tick(activityRefresh, func(time.Time) tea.Msg {
+		return ActivityTickMsg{Seq: msg.Seq}
+	})
+}
+
+// handleActivityTick lists the sessions again if the view is still open
+func (m Model) handleActivityTick(msg ActivityTickMsg) (Model, tea.Cmd) {
+	monitor, ok := m.driver.(db.ActivityMonitor)
+	if !ok || !m.showActivityPopup || msg.Seq != m.activitySeq {
+		return m, nil
+	}
+	return m, m.listSessionsCmd(monitor)
+}
+
+// handleSessionStopped reports a cancel or terminate and refreshes the list
+func (m Model) handleSessionStopped(msg SessionStoppedMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = msg.Err.Error()
+	} else {
+		m.statusMsg = msg.Status
+	}
+	monitor, ok := m.driver.(db.ActivityMonitor)
+	if !ok || !m.showActivityPopup {
+		return m, nil
+	}
+	return m, m.listSessionsCmd(monitor)
+}
+
+// stopSessionCmd cancels or terminates a session, refused on read-only
+// profiles and audited like an executed statement
+func (m Model) stopSessionCmd(monitor db.ActivityMonitor, action, id string) tea.Cmd {
+	profile := m.profile
+	if profile != nil && m.config.ReadOnly(profile) {
+		return func() tea.Msg { return SessionStoppedMsg{Err: errReadOnly} }
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		start := time.Now()
+		stop, status := monitor.CancelSession, "Cancelled query of session "+id
+		if action == "terminate" {
+			stop, status = monitor.TerminateSession, "Terminated session "+id
+		}
+		err := stop(ctx, id)
+		m.audit(profile, action+" session "+id, start, &db.QueryResult{}, err)
+		if err != nil {
+			return SessionStoppedMsg{Err: err}
+		}
+		return SessionStoppedMsg{Status: status}
+	}
+}
+
+func (m Model) renderActivityPopup(main string) string {
+	var content strings.Builder
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).
+		Render(fmt.Sprintf("Activity (%d sessions)", len(m.sessions)))
+	content.WriteString(title)
+	content.WriteString("\n\n")
+
+	popupWidth := min(110, m.width-10)
+	faint := lipgloss.NewStyle().Faint(true)
+	content.WriteString(faint.Render(fmt.Sprintf("  %-8s %-12s %-20s %8s  %s", "ID", "USER", "STATE", "TIME", "QUERY")))
+	content.WriteString("\n")
+	if len(m.sessions) == 0 {
+		content.WriteString(faint.Render("  (none)"))
+		content.WriteString("\n")
+	}
+
+	visible := max(m.height-14, 5)
+	start := 0
+	if m.activityIdx >= visible {
+		start = m.activityIdx - visible + 1
+	}
+	end := min(start+visible, len(m.sessions))
+	for i := start; i < end; i++ {
+		s := m.sessions[i]
+		prefix := "  "
+		style := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
+		if i == m.activityIdx {
+			prefix = "> "
+			style = lipgloss.NewStyle().Foreground(m.theme.TextPrimary()).Bold(true)
+		}
+		// The query fills the rest of the row, cut at its end
+		query := truncateEnd(strings.Join(strings.Fields(s.Query), " "), max(popupWidth-8-53, 10))
+		line := fmt.Sprintf("%s %s %s %8s  %s", padRight(limitString(s.ID, 8), 8), padRight(limitString(s.User, 12), 12),
+			padRight(limitString(s.State, 20), 20), s.Duration.Round(time.Second), query)
+		content.WriteString(prefix + style.Render(line) + "\n")
+	}
+
+	content.WriteString("\n")
+	if m.activityConfirm != "" {
+		prompt := fmt.Sprintf("Cancel the running query of session %s? (y/n)", m.activityTarget)
+		if m.activityConfirm == "terminate" {
+			prompt = fmt.Sprintf("Terminate session %s? (y/n)", m.activityTarget)
+		}
+		content.WriteString(lipgloss.NewStyle().Foreground(m.theme.WarningColor()).Bold(true).Render(prompt))
+	} else {
+		content.WriteString(m.renderHintLine("↑/↓: select • c: cancel query • x: terminate • r: refresh • Esc: close", m.popupInnerWidth(popupWidth)))
+	}
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
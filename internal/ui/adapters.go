@@ -1,18 +1,21 @@
 package ui
 
 import (
+	"time"
+
 	"github.com/nhath/ezdb/internal/history"
 	"github.com/nhath/ezdb/internal/ui/components/historylist"
 )
 
 // HistoryItemAdapter wraps HistoryEntry to implement historylist.Item
 type HistoryItemAdapter struct {
-	entry history.HistoryEntry
+	entry      history.HistoryEntry
+	executedAt string
 }
 
-// NewHistoryItemAdapter creates a new adapter
-func NewHistoryItemAdapter(entry history.HistoryEntry) HistoryItemAdapter {
-	return HistoryItemAdapter{entry: entry}
+// NewHistoryItemAdapter creates a new adapter showing the entry's time as executedAt
+func NewHistoryItemAdapter(entry history.HistoryEntry, executedAt string) HistoryItemAdapter {
+	return HistoryItemAdapter{entry: entry, executedAt: executedAt}
 }
 
 // Implement historylist.Item interface
@@ -24,18 +27,27 @@ func (a HistoryItemAdapter) ErrorMessage() string           { return a.entry.Err
 func (a HistoryItemAdapter) Preview() string                { return a.entry.Preview }
 func (a HistoryItemAdapter) DurationMs() int64              { return a.entry.DurationMs }
 func (a HistoryItemAdapter) RowCount() int                  { return a.entry.RowCount }
-func (a HistoryItemAdapter) ExecutedAtFormatted() string {
-	return a.entry.ExecutedAt.Format("15:04:05")
+func (a HistoryItemAdapter) ExecutedAtFormatted() string    { return a.executedAt }
+func (a HistoryItemAdapter) Origin() string {
+	if a.entry.RanOn == "" {
+		return ""
+	}
+	return "on " + a.entry.RanOn + " from " + a.entry.ProfileName
+}
+func (a HistoryItemAdapter) ErrorInfo() historylist.ErrorInfo {
+	e := a.entry
+	return historylist.ErrorInfo{Code: e.ErrorCode, Detail: e.ErrorDetail, Hint: e.ErrorHint, Position: e.ErrorPosition}
 }
 
 // Entry returns the underlying HistoryEntry
 func (a HistoryItemAdapter) Entry() history.HistoryEntry { return a.entry }
 
-// ConvertToItems converts a slice of HistoryEntry to historylist.Item slice
-func ConvertToItems(entries []history.HistoryEntry) []historylist.Item {
+// ConvertToItems converts a slice of HistoryEntry to historylist.Item slice,
+// laying out their times with stamp
+func ConvertToItems(entries []history.HistoryEntry, stamp func(time.Time) string) []historylist.Item {
 	items := make([]historylist.Item, len(entries))
 	for i, e := range entries {
-		items[i] = NewHistoryItemAdapter(e)
+		items[i] = NewHistoryItemAdapter(e, stamp(e.ExecutedAt))
 	}
 	return items
 }
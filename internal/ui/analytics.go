@@ -0,0 +1,249 @@
+// internal/ui/analytics.go
+// Query analytics: slowest and most-run queries, daily error rate and per-table activity from the history store.
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/history"
+)
+
+const (
+	analyticsRows    = 10   // Entries per chart
+	analyticsDays    = 14   // Days of the error rate chart
+	analyticsQueries = 2000 // Recent queries parsed for table activity
+)
+
+// analyticsTabs names the charts of the analytics popup in order
+var analyticsTabs = []string{"Slowest", "Most run", "Errors", "Tables"}
+
+// tableRef matches the table after FROM, JOIN, UPDATE, INTO or TABLE
+var tableRef = regexp.MustCompile("(?i)\\b(?:FROM|JOIN|UPDATE|INTO|TABLE)\\s+(\"[^\"]+\"|`[^`]+`|[A-Za-z_][\\w$]*(?:\\.[A-Za-z_][\\w$]*)?)")
+
+// notTables are words that can follow those keywords without naming a table
+var notTables = map[string]bool{"if": true, "only": true, "lateral": true, "unnest": true, "select": true}
+
+// queryAnalytics holds the charts of the analytics popup
+type queryAnalytics struct {
+	Slowest []history.QueryTiming
+	MostRun []history.QueryUsage
+	Days    []history.DayStats
+	Tables  []tableCount
+}
+
+// tableCount is how often a table appears in the history
+type tableCount struct {
+	Table string
+	Count int
+}
+
+// queryTables returns the tables a query reads or writes, each once
+func queryTables(query string) []string {
+	var tables []string
+	seen := map[string]bool{}
+	for _, m := range tableRef.FindAllStringSubmatch(query, -1) {
+		name := strings.Trim(m[1], "\"`")
+		key := strings.ToLower(name)
+		if notTables[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		tables = append(tables, key)
+	}
+	return tables
+}
+
+// tableActivity counts the queries touching each table, busiest first
+func tableActivity(entries []history.HistoryEntry) []tableCount {
+	counts := map[string]int{}
+	for _, e := range entries {
+		for _, t := range queryTables(e.Query) {
+			counts[t]++
+		}
+	}
+	activity := make([]tableCount, 0, len(counts))
+	for t, n := range counts {
+		activity = append(activity, tableCount{Table: t, Count: n})
+	}
+	sort.Slice(activity, func(i, j int) bool {
+		if activity[i].Count != activity[j].Count {
+			return activity[i].Count > activity[j].Count
+		}
+		return activity[i].Table < activity[j].Table
+	})
+	return activity
+}
+
+// bar draws value as a bar of at most width cells, scaled to most
+func bar(value, most float64, width int) string {
+	if most <= 0 || value <= 0 {
+		return ""
+	}
+	eighths := int(value / most * float64(width*8))
+	blocks := []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+	s := strings.Repeat("█", eighths/8) + blocks[eighths%8]
+	if s == "" {
+		s = "▏"
+	}
+	return s
+}
+
+// openAnalyticsPopup opens the analytics popup on its first chart.
+func (m *Model) openAnalyticsPopup() {
+	if m.showAnalytics {
+		return
+	}
+	m.showAnalytics = true
+	m.autocompleting = false
+	m.analyticsTab = 0
+	m.analytics = nil
+	m.popupStack.Push("analytics", func(m *Model) {
+		m.showAnalytics = false
+	})
+}
+
+// loadAnalyticsCmd gathers every chart of a profile's history
+func loadAnalyticsCmd(store *history.Store, profile string) tea.Cmd {
+	return func() tea.Msg {
+		var a queryAnalytics
+		var err error
+		if a.Slowest, err = store.Slowest(profile, analyticsRows); err != nil {
+			return AnalyticsMsg{Err: err}
+		}
+		if a.MostRun, err = store.MostRun(profile, analyticsRows); err != nil {
+			return AnalyticsMsg{Err: err}
+		}
+		if a.Days, err = store.Daily(profile, analyticsDays); err != nil {
+			return AnalyticsMsg{Err: err}
+		}
+		entries, err := store.List(profile, analyticsQueries, 0)
+		if err != nil {
+			return AnalyticsMsg{Err: err}
+		}
+		a.Tables = tableActivity(entries)
+		return AnalyticsMsg{Analytics: a}
+	}
+}
+
+// handleAnalyticsKeys switches charts while the analytics popup is open
+func (m Model) handleAnalyticsKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "tab", "right", "l":
+		m.analyticsTab = (m.analyticsTab + 1) % len(analyticsTabs)
+	case "shift+tab", "left", "h":
+		m.analyticsTab = (m.analyticsTab + len(analyticsTabs) - 1) % len(analyticsTabs)
+	case "1", "2", "3", "4":
+		m.analyticsTab = int(msg.String()[0] - '1')
+	case "r":
+		if m.historyStore != nil && m.profile != nil {
+			return m, loadAnalyticsCmd(m.historyStore, m.profile.Name), true
+		}
+	default:
+		if matchKey(msg, m.config.Keys.Analytics) {
+			m.closeTopPopup()
+		}
+	}
+	return m, nil, true
+}
+
+// handleAnalytics shows freshly gathered charts
+func (m Model) handleAnalytics(msg AnalyticsMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	}
+	m.analytics = &msg.Analytics
+	return m, nil
+}
+
+func (m Model) renderAnalyticsPopup(main string) string {
+	var content strings.Builder
+	popupWidth := min(100, m.width-10)
+	faint := lipgloss.NewStyle().Faint(true)
+	barStyle := lipgloss.NewStyle().Foreground(m.theme.AccentColor())
+
+	title := "Query Analytics"
+	if m.profile != nil {
+		title += " of " + m.profile.Name
+	}
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render(title))
+	content.WriteString("\n\n")
+	for i, name := range analyticsTabs {
+		label := fmt.Sprintf(" %d %s ", i+1, name)
+		if i == m.analyticsTab {
+			content.WriteString(lipgloss.NewStyle().Bold(true).Reverse(true).Render(label))
+		} else {
+			content.WriteString(faint.Render(label))
+		}
+		content.WriteString(" ")
+	}
+	content.WriteString("\n\n")
+
+	// Each row is a label, a bar and a value
+	const labelWidth, valueWidth = 40, 14
+	barWidth := max(popupWidth-labelWidth-valueWidth-8, 10)
+	row := func(label string, value, most float64, shown string) {
+		label = limitString(strings.Join(strings.Fields(label), " "), labelWidth)
+		content.WriteString(padRight(label, labelWidth) + " ")
+		b := bar(value, most, barWidth)
+		content.WriteString(barStyle.Render(b))
+		content.WriteString(strings.Repeat(" ", max(barWidth-lipgloss.Width(b), 0)))
+		content.WriteString(fmt.Sprintf(" %*s\n", valueWidth, shown))
+	}
+
+	a := m.analytics
+	switch {
+	case a == nil:
+		content.WriteString(faint.Render("  Loading..."))
+		content.WriteString("\n")
+	case m.analyticsTab == 0:
+		if len(a.Slowest) == 0 {
+			content.WriteString(faint.Render("  No successful queries yet\n"))
+		}
+		for _, q := range a.Slowest {
+			most := float64(a.Slowest[0].MaxMs)
+			row(q.Query, float64(q.MaxMs), most, fmt.Sprintf("%s max", time.Duration(q.MaxMs)*time.Millisecond))
+		}
+	case m.analyticsTab == 1:
+		if len(a.MostRun) == 0 {
+			content.WriteString(faint.Render("  No successful queries yet\n"))
+		}
+		for _, q := range a.MostRun {
+			row(q.Query, float64(q.Count), float64(a.MostRun[0].Count), fmt.Sprintf("%d runs", q.Count))
+		}
+	case m.analyticsTab == 2:
+		if len(a.Days) == 0 {
+			content.WriteString(faint.Render("  No queries yet\n"))
+		}
+		for _, d := range a.Days {
+			rate := float64(d.Errors) / float64(max(d.Total, 1)) * 100
+			row(d.Day, rate, 100, fmt.Sprintf("%.0f%% of %d", rate, d.Total))
+		}
+	default:
+		if len(a.Tables) == 0 {
+			content.WriteString(faint.Render("  No table references in recent queries\n"))
+		}
+		for _, t := range a.Tables[:min(len(a.Tables), analyticsRows)] {
+			row(t.Table, float64(t.Count), float64(a.Tables[0].Count), fmt.Sprintf("%d queries", t.Count))
+		}
+	}
+
+	content.WriteString("\n")
+	content.WriteString(m.renderHintLine("Tab/1-4: chart • r: refresh • Esc: close", m.popupInnerWidth(popupWidth)))
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
@@ -0,0 +1,41 @@
+// internal/ui/analytics_test.go
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nhath/ezdb/internal/history"
+)
+
+func TestTableActivity(t *testing.T) {
+	got := queryTables("SELECT * FROM Users u JOIN \"orders\" o ON o.user_id = u.id JOIN users x ON true")
+	if want := []string{"users", "orders"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("queryTables = %v, want %v", got, want)
+	}
+	if got := queryTables("CREATE TABLE IF NOT EXISTS logs (id int)"); len(got) != 0 {
+		t.Errorf("queryTables of CREATE TABLE IF = %v, want none", got)
+	}
+
+	entries := []history.HistoryEntry{
+		{Query: "UPDATE app.orders SET paid = true"},
+		{Query: "INSERT INTO `orders` VALUES (1)"},
+		{Query: "SELECT 1 FROM app.orders"},
+	}
+	want := []tableCount{{"app.orders", 2}, {"orders", 1}}
+	if got := tableActivity(entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("tableActivity = %v, want %v", got, want)
+	}
+}
+
+func TestBar(t *testing.T) {
+	if got := bar(5, 10, 4); got != "██" {
+		t.Errorf("bar(5, 10, 4) = %q", got)
+	}
+	if got := bar(1, 1000, 4); got != "▏" {
+		t.Errorf("bar of a tiny value = %q, want a sliver", got)
+	}
+	if got := bar(0, 10, 4); got != "" {
+		t.Errorf("bar(0) = %q, want empty", got)
+	}
+}
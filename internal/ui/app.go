@@ -1,13 +1,15 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/nhath/ezdb/internal/db"
 	"github.com/nhath/ezdb/internal/history"
 	"github.com/nhath/ezdb/internal/ui/components/profileselector"
 	"github.com/nhath/ezdb/internal/ui/components/schemabrowser"
@@ -16,280 +18,298 @@ import (
 )
 
 // Update handles messages and updates the model.
+// Status and error messages set along the way are recorded as notifications.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmds []tea.Cmd
+	prevStatus, prevError := m.statusMsg, m.errorMsg
+	prevText := m.editor.Value()
+	model, cmd := m.update(msg)
+	next, ok := model.(Model)
+	if !ok {
+		return model, cmd
+	}
+	next, notifyCmd := next.recordNotifications(prevStatus, prevError)
+	var editCmd tea.Cmd
+	if next.editor.Value() != prevText {
+		next, editCmd = next.scheduleEditSnapshot()
+	}
+	return next, tea.Batch(cmd, notifyCmd, editCmd)
+}
 
-	// --- Non-key messages (structural / async results) ---
+// update dispatches a single message. Keys go through the key controllers
+// (see controllers.go); every other message goes to its handler.
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.handleChordKey(msg)
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		m.editor.SetWidth(msg.Width - 4)
-		m.profileSelector = m.profileSelector.SetSize(msg.Width, msg.Height)
-		m.schemaBrowser = m.schemaBrowser.SetSize(msg.Width, msg.Height)
-		if m.expandedID != 0 {
-			m.expandedTable = m.expandedTable.WithMaxTotalWidth(msg.Width - 14)
-		}
-		m.updatePopupTable()
-		m = m.updateHistoryViewport()
-		return m, nil
+		return m.handleWindowSize(msg)
 
+	// Profiles and connections
 	case profileselector.SelectedMsg:
 		return m.handleProfileSelected(msg)
-
 	case profileselector.ProfileSavedMsg:
 		return m.handleProfileSaved(msg)
-
 	case profileselector.ManagementMsg:
 		return m.handleProfileManagement(msg)
-
+	case profileselector.TestConnectionMsg:
+		return m.handleTestConnection(msg)
+	case ConnectionTestedMsg:
+		return m.handleConnectionTested(msg)
 	case ProfileConnectedMsg:
 		return m.handleProfileConnected(msg)
 
-	case ClipboardCopiedMsg:
-		if msg.Err != nil {
-			m.errorMsg = fmt.Sprintf("Clipboard error: %v", msg.Err)
-			m.statusMsg = ""
-		} else {
-			m.errorMsg = ""
-			m.statusMsg = "Copied to clipboard"
-		}
-		return m, nil
-
-	case schemabrowser.SchemaLoadedMsg:
-		if msg.Err == nil {
-			m.schemaBrowser = m.schemaBrowser.SetSchema(msg.Tables, msg.Columns, msg.Constraints)
-			m.tables = msg.Tables
-			m.columns = msg.Columns
-			m.statusMsg = fmt.Sprintf("Loaded %d tables", len(msg.Tables))
-		} else {
-			m.errorMsg = fmt.Sprintf("Schema load failed: %v", msg.Err)
-		}
-		m.loadingTables = false
-		if m.autocompleting {
-			m = m.updateSuggestions()
-		}
-		return m, nil
-
-	case schemabrowser.TableSelectedMsg:
-		m.openTemplatePopup(msg.TableName)
-		return m, nil
-
-	case schemabrowser.ExportTableMsg:
-		m.exportTable = msg.TableName
-		m.openExportPopup(msg.TableName + ".csv")
-		return m, nil
-
-	case schemabrowser.ImportTableMsg:
-		m.openImportPopup(msg.TableName)
-		return m, nil
-
-	case ThemeSelectedMsg:
-		return m.handleThemeSelected(msg)
-
-	case ExportTableCompleteMsg:
-		m.loading = false
-		if msg.Err != nil {
-			m.errorMsg = fmt.Sprintf("Export failed: %v", msg.Err)
-		} else {
-			m.statusMsg = fmt.Sprintf("Exported %d rows to %s", msg.Rows, msg.Filename)
-		}
-		m.exportTable = ""
-		return m, nil
-
-	case ImportTableCompleteMsg:
-		m.loading = false
-		if msg.Err != nil {
-			m.errorMsg = fmt.Sprintf("Import failed: %v", msg.Err)
-		} else {
-			m.statusMsg = fmt.Sprintf("Imported %d rows", msg.Rows)
-		}
-		m.importTable = ""
-		return m, nil
-
-	case DebounceMsg:
-		if msg.ID == m.debounceID {
-			m = m.updateSuggestions()
-			if len(m.suggestions) > 0 {
-				m.autocompleting = true
-			} else {
-				m.autocompleting = false
-			}
-		}
-		return m, nil
-
-	case PagerFinishedMsg:
-		if msg.Err != nil {
-			m.errorMsg = fmt.Sprintf("Pager error: %v", msg.Err)
-		}
-		return m, nil
-
+	// Queries and history
 	case QueryResultMsg:
 		return m.handleQueryResult(msg)
-
+	case RerunResultMsg:
+		return m.handleRerunResult(msg)
+	case RunOnResultMsg:
+		return m.handleRunOnResult(msg)
+	case StoredResultMsg:
+		return m.handleStoredResult(msg)
 	case HistoryLoadedMsg:
 		return m.handleHistoryLoaded(msg)
-
-	case RerunResultMsg:
-		m.loading = false
-		if msg.Err == nil {
-			m.popupTable = eztable.FromQueryResult(msg.Result, 0).Focused(true)
-			m.updatePopupTable()
-			m.openResultsPopup(msg.Entry, msg.Result)
-		} else {
-			m.errorMsg = msg.Err.Error()
-		}
-		return m, nil
-
+	case HistoryIndexLoadedMsg:
+		return m.handleHistoryIndexLoaded(msg)
+	case CostEstimateMsg:
+		return m.handleCostEstimate(msg)
+	case RowEstimateMsg:
+		return m.handleRowEstimate(msg)
+	case ExportEstimateMsg:
+		return m.handleExportEstimate(msg)
+	case BrowsePageMsg:
+		return m.handleBrowsePage(msg)
+	case SQLFileMsg:
+		return m.handleSQLFile(msg)
+	case ScriptLoadedMsg:
+		return m.handleScriptLoaded(msg)
+	case ScriptRunMsg:
+		return m.handleScriptRun(msg)
+
+	// Schema
+	case schemabrowser.SchemaLoadedMsg:
+		return m.handleSchemaLoaded(msg)
+	case FunctionsLoadedMsg:
+		return m.handleFunctionsLoaded(msg)
+	case SessionTimezoneMsg:
+		return m.handleSessionTimezone(msg)
+	case AssistantMsg:
+		return m.handleAssistant(msg)
+	case ErrorFixMsg:
+		return m.handleErrorFix(msg)
+	case schemabrowser.TableSelectedMsg, schemabrowser.ExportTableMsg, schemabrowser.ImportTableMsg,
+		schemabrowser.GenerateDataMsg, schemabrowser.TruncateTableMsg, schemabrowser.DropTableMsg,
+		schemabrowser.CopyTableMsg, schemabrowser.BrowseTableMsg, schemabrowser.RunFileMsg:
+		return m.handleSchemaAction(msg)
+	case TableActionMsg:
+		return m.handleTableAction(msg)
+	case CopyTableCompleteMsg:
+		return m.handleCopyTableComplete(msg)
+	case SchemasMsg:
+		return m.handleSchemas(msg)
+	case AttachmentsMsg:
+		return m.handleAttachments(msg)
+	case SnapshotsMsg:
+		return m.handleSnapshots(msg)
+	case SnapshotDiffMsg:
+		return m.handleSnapshotDiff(msg)
+
+	// Imports and exports
+	case TransferProgressMsg:
+		return m.handleTransferProgress(msg)
+	case ExportTableCompleteMsg:
+		return m.handleExportTableComplete(msg)
+	case ImportTableCompleteMsg:
+		return m.handleImportTableComplete(msg)
+	case GenerateDataCompleteMsg:
+		return m.handleGenerateDataComplete(msg)
 	case ExportCompleteMsg:
-		if msg.Err != nil {
-			m.errorMsg = fmt.Sprintf("Export failed: %v", msg.Err)
-		} else {
-			m.statusMsg = fmt.Sprintf("Exported to: %s", msg.Path)
-		}
-		return m, nil
-	}
-
-	// Forward non-KeyMsg to schema browser (spinner ticks, etc.)
-	if _, ok := msg.(tea.KeyMsg); !ok {
-		var sbCmd tea.Cmd
-		m.schemaBrowser, sbCmd = m.schemaBrowser.Update(msg)
-		cmds = append(cmds, sbCmd)
-	}
-
-	// --- Key messages ---
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		m.statusMsg = "" // clear status on any key
-
-		// Profile-selection state: delegate immediately
-		if m.appState == StateSelectingProfile {
-			if matchKey(msg, m.config.Keys.Help) {
-				m.openHelpPopup()
-				return m, nil
-			}
-			var cmd tea.Cmd
-			m.profileSelector, cmd = m.profileSelector.Update(msg)
-			return m, cmd
-		}
-
-		// Toggle theme (only outside insert mode and when schema/theme not visible)
-		if m.mode != InsertMode && !m.schemaBrowser.IsVisible() && !m.themeSelector.Visible() && matchKey(msg, m.config.Keys.ToggleTheme) {
-			m.openThemeSelector()
-			return m, nil
-		}
-
-		// Let popup layer handle its keys first
-		if m2, cmd, handled := m.handlePopupKeys(msg); handled {
-			return m2, cmd
-		}
-
-		// Global quit
-		if matchKey(msg, m.config.Keys.Quit) {
-			return m, tea.Quit
-		}
-
-		// Tab toggles schema browser (visual mode only, outside schema browser)
-		if matchKey(msg, m.config.Keys.ToggleSchema) && m.mode == VisualMode {
-			m.schemaBrowser = m.schemaBrowser.Toggle()
-			if m.schemaBrowser.IsVisible() && m.driver != nil {
-				return m, schemabrowser.LoadSchemaCmd(m.driver)
-			}
-			return m, nil
-		}
-
-		// Help shortcut (when no popup open)
-		if matchKey(msg, m.config.Keys.Help) && !m.hasOpenPopup() {
-			m.openHelpPopup()
-			return m, nil
-		}
-
-		// P – reconnect / show profile selector
-		if matchKey(msg, m.config.Keys.ShowProfiles) && m.mode == VisualMode {
-			if m.driver != nil {
-				m.driver.Close()
-				m.driver = nil
-			}
-			m.appState = StateSelectingProfile
-			m.reloadProfiles()
-			return m, nil
-		}
-
-		// Schema browser consumes keys when visible
-		if m.schemaBrowser.IsVisible() {
-			var cmd tea.Cmd
-			m.schemaBrowser, cmd = m.schemaBrowser.Update(msg)
-			return m, cmd
-		}
+		return m.handleExportComplete(msg)
+	case ClipboardCopiedMsg:
+		return m.handleClipboardCopied(msg)
+	case TranscriptSavedMsg:
+		return m.handleTranscriptSaved(msg)
+	case PagerFinishedMsg:
+		return m.handlePagerFinished(msg)
 
-		// Mode dispatch
-		if m.mode == InsertMode {
-			var updatedCmds []tea.Cmd
-			m, updatedCmds = m.handleInsertMode(msg, cmds)
-			return m, tea.Batch(updatedCmds...)
-		}
-		return m.handleVisualMode(msg)
+	// Editor
+	case DebounceMsg:
+		return m.handleDebounce(msg)
+	case LintResultMsg:
+		return m.handleLintResult(msg)
+	case EditIdleMsg:
+		return m.handleEditIdle(msg)
+	case EditsLoadedMsg:
+		return m.handleEditsLoaded(msg)
+	case ChordTimeoutMsg:
+		return m.handleChordTimeout(msg)
+
+	// Server views
+	case AnalyticsMsg:
+		return m.handleAnalytics(msg)
+	case ActivityMsg:
+		return m.handleActivity(msg)
+	case ActivityTickMsg:
+		return m.handleActivityTick(msg)
+	case SessionStoppedMsg:
+		return m.handleSessionStopped(msg)
+	case MetricsMsg:
+		return m.handleMetrics(msg)
+	case MetricsTickMsg:
+		return m.handleMetricsTick(msg)
+
+	// Appearance and timers
+	case ThemeSelectedMsg:
+		return m.handleThemeSelected(msg)
+	case UserThemesChangedMsg:
+		return m.handleUserThemesChanged(msg)
+	case themeWatchTickMsg:
+		return m, watchThemesCmd(msg.Stamp)
+	case ToastExpiredMsg:
+		return m.handleToastExpired(msg)
+	case ClockTickMsg:
+		return m, clockTickCmd()
+	case SidebarSaveMsg:
+		return m.handleSidebarSave(msg)
+	case SidebarSavedMsg:
+		return m.handleSidebarSaved(msg)
 	}
 
-	// Fallback: forward to editor if in insert mode
+	// Anything else (spinner ticks, cursor blinks) goes to the schema
+	// browser, and to the editor in insert mode
+	var cmds []tea.Cmd
+	var cmd tea.Cmd
+	m.schemaBrowser, cmd = m.schemaBrowser.Update(msg)
+	cmds = append(cmds, cmd)
 	if m.mode == InsertMode {
-		var cmd tea.Cmd
 		m.editor, cmd = m.editor.Update(msg)
 		cmds = append(cmds, cmd)
 	}
-
 	return m, tea.Batch(cmds...)
 }
 
+// handleWindowSize lays everything out for the new terminal size
+func (m Model) handleWindowSize(msg tea.WindowSizeMsg) (Model, tea.Cmd) {
+	m.width = msg.Width
+	m.height = msg.Height
+	m.editor.SetWidth(msg.Width - 4)
+	m.profileSelector = m.profileSelector.SetSize(msg.Width, msg.Height)
+	m = m.layoutSchemaBrowser()
+	if m.expandedID != 0 {
+		m.expandedTable = m.expandedTable.WithMaxTotalWidth(msg.Width - 14)
+	}
+	m.updatePopupTable()
+	m = m.updateHistoryViewport()
+	return m, nil
+}
+
 // --- Result / history message handlers ---
 
 // handleQueryResult processes a completed query execution.
 func (m Model) handleQueryResult(msg QueryResultMsg) (Model, tea.Cmd) {
 	m.loading = false
+	m.cancelQuery = nil
+	for _, entry := range msg.AllEntries {
+		m.inTransaction = transactionState(entry.Query, m.inTransaction)
+		if m.historyIndex != nil && entry.Status == "success" {
+			m.historyIndex.Add(entry.Query)
+		}
+	}
 	if msg.Err != nil {
 		m.errorMsg = msg.Err.Error()
+		if errors.Is(msg.Err, context.Canceled) {
+			m.errorMsg = "Query cancelled"
+		}
+		m.lastRun = nil
 		if msg.Entry != nil {
+			m.lastRun = &runSummary{failed: true, duration: time.Duration(msg.Entry.DurationMs) * time.Millisecond}
 			m.history = append(m.history, *msg.Entry)
 			m.selected = len(m.history) - 1
 			m.expandedID = msg.Entry.ID
-			if strings.Contains(msg.Entry.Preview, " | ") {
-				m.expandedTable = eztable.FromPreview(msg.Entry.Preview).
-					WithMaxTotalWidth(m.width - 14).
-					WithHorizontalFreezeColumnCount(1)
-			}
+			m.expandPreview(msg.Entry.Preview)
 		}
 	} else {
+		m.keepResult(msg.Result)
 		m.results = msg.Result
 		m.page = 0
+		m.lastRun = &runSummary{rows: int64(msg.Result.RowCount), duration: msg.Result.ExecTime}
+		if !msg.Result.IsSelect {
+			m.lastRun.rows, m.lastRun.affected = msg.Result.AffectedRows, true
+		}
 		if msg.Entry != nil {
 			m.history = append(m.history, *msg.Entry)
 			m.selected = len(m.history) - 1
 
 			if msg.Result.IsSelect {
-				if m.config.Pager != "" {
+				if m.pagerCommand() != "" {
 					return m, m.openPager(msg.Result)
 				}
-				m.popupTable = eztable.FromQueryResult(msg.Result, 0).Focused(true)
-				m.updatePopupTable()
-				m.openResultsPopup(msg.Entry, msg.Result)
-				m.expandedID = msg.Entry.ID
+				if m.resultsDocked() {
+					m.setDockResult(msg.Entry, msg.Result)
+					m.expandedID = 0
+				} else {
+					m.showResultsPopup(msg.Entry, msg.Result)
+					m.expandedID = msg.Entry.ID
+				}
 			} else {
 				m.expandedID = msg.Entry.ID
-				if strings.Contains(msg.Entry.Preview, " | ") {
-					m.expandedTable = eztable.FromPreview(msg.Entry.Preview).
-						WithMaxTotalWidth(m.width - 14).
-						WithHorizontalFreezeColumnCount(1)
-				}
+				m.expandPreview(msg.Entry.Preview)
 			}
 		}
 		m.errorMsg = ""
 	}
 	m = m.updateHistoryViewport()
-	m.viewport.GotoBottom()
+	m.historyList = m.historyList.GotoBottom()
 	m = m.ensureSelectionVisible()
 	return m, nil
 }
 
+// handleRerunResult shows the result of re-running a history entry
+func (m Model) handleRerunResult(msg RerunResultMsg) (Model, tea.Cmd) {
+	m.loading = false
+	if msg.Err == nil {
+		m.keepResult(msg.Result)
+	}
+	if msg.Err == nil && msg.Result.IsSelect && m.resultsDocked() {
+		m.setDockResult(msg.Entry, msg.Result)
+		m = m.updateHistoryViewport()
+	} else if msg.Err == nil {
+		m.showResultsPopup(msg.Entry, msg.Result)
+	} else {
+		m.errorMsg = msg.Err.Error()
+	}
+	return m, nil
+}
+
+// handleStoredResult shows the full result of an expanded history entry
+// the way a fresh query result is shown
+func (m Model) handleStoredResult(msg StoredResultMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = "Loading stored result failed: " + msg.Err.Error()
+		return m, nil
+	}
+	if msg.Result == nil || m.expandedID != msg.Entry.ID || m.hasOpenPopup() {
+		return m, nil
+	}
+	entry := msg.Entry
+	result := &db.QueryResult{
+		Columns:  msg.Result.Columns,
+		Rows:     msg.Result.Rows,
+		ExecTime: time.Duration(entry.DurationMs) * time.Millisecond,
+		RowCount: len(msg.Result.Rows),
+		IsSelect: true,
+	}
+	if m.resultsDocked() {
+		m.setDockResult(&entry, result)
+	} else {
+		m.showResultsPopup(&entry, result)
+	}
+	m.statusMsg = fmt.Sprintf("Stored result from %s", entry.ExecutedAt.In(m.timestampLocation()).Format("2006-01-02 15:04:05"))
+	return m, nil
+}
+
 // handleHistoryLoaded processes loaded history entries.
 func (m Model) handleHistoryLoaded(msg HistoryLoadedMsg) (Model, tea.Cmd) {
 	if msg.Err == nil {
@@ -302,14 +322,24 @@ func (m Model) handleHistoryLoaded(msg HistoryLoadedMsg) (Model, tea.Cmd) {
 
 		if len(m.history) > 0 {
 			m.selected = len(m.history) - 1
-			m.expandedID = m.history[m.selected].ID
-			if strings.Contains(m.history[m.selected].Preview, " | ") {
-				m.expandedTable = eztable.FromPreview(m.history[m.selected].Preview).
-					WithMaxTotalWidth(m.width - 14).
-					WithHorizontalFreezeColumnCount(1)
+			restored := false
+			if m.restoring != nil && m.restoring.HistoryID != 0 {
+				for i, e := range m.history {
+					if e.ID == m.restoring.HistoryID {
+						m.selected, restored = i, true
+						break
+					}
+				}
+				m.restoring.HistoryID = 0
 			}
+			m.expandedID = m.history[m.selected].ID
+			m.expandPreview(m.history[m.selected].Preview)
 			m = m.updateHistoryViewport()
-			m.viewport.GotoBottom()
+			if restored {
+				m = m.ensureSelectionVisible()
+			} else {
+				m.historyList = m.historyList.GotoBottom()
+			}
 		}
 	}
 	return m, nil
@@ -319,24 +349,35 @@ func (m Model) handleHistoryLoaded(msg HistoryLoadedMsg) (Model, tea.Cmd) {
 func (m Model) handleThemeSelected(msg ThemeSelectedMsg) (Model, tea.Cmd) {
 	m.config.Theme = msg.Theme
 	m.config.ThemeName = msg.ThemeName
-	styles.Init(m.config.Theme)
+	m.config.ThemeMode = ""
+	if msg.Auto {
+		m.config.ThemeMode = "auto"
+	}
+	m = m.applyTheme()
+
+	m.config.Save()
+	if m.popupStack.TopName() == "theme" {
+		m.closeTopPopup()
+	}
+	return m, tea.ClearScreen
+}
+
+// applyTheme re-initializes every themed component from m.config.Theme
+func (m Model) applyTheme() Model {
+	m.theme = styles.New(m.config.Theme)
 
 	m.profileSelector = m.profileSelector.SetStyles(profileselector.DefaultStyles(m.config.Theme))
 	m.themeSelector = m.themeSelector.UpdateTheme(m.config.Theme)
-	eztable.Init(m.config.Theme, m.config.Keys)
+	m.tableBuilder = eztable.NewBuilder(m.config.Theme, m.config.Keys)
+	m.schemaBrowser = m.schemaBrowser.SetTableBuilder(m.tableBuilder)
 
 	// Recreate expanded table with new theme
 	if m.expandedID != 0 && m.selected >= 0 && m.selected < len(m.history) {
 		entry := m.history[m.selected]
-		if strings.Contains(entry.Preview, " | ") {
-			m.expandedTable = eztable.FromPreview(entry.Preview).
-				WithMaxTotalWidth(m.width - 14).
-				WithHorizontalFreezeColumnCount(1)
-		}
+		m.expandPreview(entry.Preview)
 	}
 	if m.popupResult != nil {
-		m.popupTable = eztable.FromQueryResult(m.popupResult, 0).Focused(true)
-		m.updatePopupTable()
+		m.setPopupWindow(m.popupResult, m.popupOffset)
 	}
 
 	// Re-init schema browser styles
@@ -354,12 +395,7 @@ func (m Model) handleThemeSelected(msg ThemeSelectedMsg) (Model, tea.Cmd) {
 		TabActive:     lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.Success)).Bold(true).Border(lipgloss.NormalBorder(), false, false, true, false).BorderForeground(lipgloss.Color(m.config.Theme.Success)).Padding(0, 1),
 		TabInactive:   lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Theme.TextFaint)).Padding(0, 1),
 	})
-
-	m.config.Save()
-	if m.popupStack.TopName() == "theme" {
-		m.popupStack.Pop()
-	}
-	return m, tea.ClearScreen
+	return m
 }
 
 // addSystemMessage appends an informational entry to the visible history.
@@ -372,7 +408,7 @@ func (m Model) addSystemMessage(msg string) Model {
 	}
 	m.history = append(m.history, entry)
 	m.selected = len(m.history) - 1
-	m.viewport.SetContent(m.renderHistoryContent(m.viewport.Height))
-	m.viewport.GotoBottom()
+	m = m.refreshHistoryList()
+	m.historyList = m.historyList.GotoBottom()
 	return m
 }
@@ -0,0 +1,120 @@
+// internal/ui/assistant.go
+// SQL assistant prompt: a plain-language request and the cached schema go to the configured model, the SQL it writes lands in the editor for review.
+package ui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/assistant"
+)
+
+// assistantTimeout bounds one request to the model
+const assistantTimeout = 90 * time.Second
+
+// openAssistantPopup opens the assistant prompt when the config opts in and
+// the profile does not opt out
+func (m *Model) openAssistantPopup() tea.Cmd {
+	switch {
+	case !m.config.Assistant.Enabled:
+		m.errorMsg = "The assistant is off; set enabled = true under [assistant] in the config"
+		return nil
+	case !m.config.AssistantEnabled(m.profile):
+		m.errorMsg = "The assistant is disabled for this profile"
+		return nil
+	case m.assistantBusy:
+		m.statusMsg = "The assistant is still writing"
+		return nil
+	case m.showAssistant:
+		return nil
+	}
+	m.ensureInput(lazyAssistant, &m.assistantInput, newAssistantInput)
+	m.showAssistant = true
+	m.autocompleting = false
+	m.popupStack.Push("assistant", func(m *Model) {
+		m.showAssistant = false
+		m.assistantInput.Blur()
+	})
+	m.assistantInput.CursorEnd()
+	return m.assistantInput.Focus()
+}
+
+// handleAssistantKeys edits the request; Enter sends it
+func (m Model) handleAssistantKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc":
+		m.closeTopPopup()
+		return m, nil, true
+	case "enter":
+		request := strings.TrimSpace(m.assistantInput.Value())
+		if request == "" {
+			return m, nil, true
+		}
+		m.closeTopPopup()
+		m.assistantBusy = true
+		m.statusMsg = "Asking the assistant..."
+		return m, m.assistantCmd(request), true
+	}
+	var cmd tea.Cmd
+	m.assistantInput, cmd = m.assistantInput.Update(msg)
+	return m, cmd, true
+}
+
+// assistantCmd sends request with the cached schema, table and column
+// names and types only, to the configured model
+func (m Model) assistantCmd(request string) tea.Cmd {
+	client := assistant.New(m.config.Assistant)
+	schema := assistant.Schema{Tables: m.tables, Columns: m.columns}
+	if m.driver != nil {
+		schema.Dialect = string(m.driver.Type())
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), assistantTimeout)
+		defer cancel()
+		sql, err := client.Generate(ctx, request, schema)
+		return AssistantMsg{Request: request, SQL: sql, Err: err}
+	}
+}
+
+// handleAssistant puts the generated SQL into the editor without running it
+func (m Model) handleAssistant(msg AssistantMsg) (Model, tea.Cmd) {
+	m.assistantBusy = false
+	if msg.Err != nil {
+		m.statusMsg = ""
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	}
+	m.setEditorValue(msg.SQL)
+	m.mode = InsertMode
+	m.editor.Focus()
+	m.errorMsg = ""
+	m.statusMsg = "Assistant SQL in the editor; review it before running"
+	return m, textinput.Blink
+}
+
+func (m Model) renderAssistantPopup(main string) string {
+	popupWidth := min(72, m.width-10)
+	var content strings.Builder
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Ask for SQL"))
+	content.WriteString("\n\n")
+	m.assistantInput.Width = max(m.popupInnerWidth(popupWidth)-len(m.assistantInput.Prompt)-1, 10)
+	content.WriteString(m.assistantInput.View())
+	content.WriteString("\n\n")
+	faint := lipgloss.NewStyle().Faint(true)
+	content.WriteString(faint.Width(m.popupInnerWidth(popupWidth)).Render("Sends this request and the names and types of " +
+		"the cached tables, never rows, to " + assistant.New(m.config.Assistant).Endpoint))
+	content.WriteString("\n\n")
+	content.WriteString(m.renderHintLine("Enter: ask • Esc: cancel", m.popupInnerWidth(popupWidth)))
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
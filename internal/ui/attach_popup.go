@@ -0,0 +1,188 @@
+// internal/ui/attach_popup.go
+// SQLite ATTACH manager: attach extra database files and detach them again.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/ui/components/schemabrowser"
+)
+
+// openAttachPopup opens the attach manager popup.
+func (m *Model) openAttachPopup() {
+	if m.showAttachPopup {
+		return
+	}
+	m.showAttachPopup = true
+	m.autocompleting = false
+	m.ensureInput(lazyAttach, &m.attachInput, newAttachInput)
+	m.attachInput.SetValue("")
+	m.attachInput.Focus()
+	m.popupStack.Push("attach", func(m *Model) {
+		m.showAttachPopup = false
+		m.attachInput.Blur()
+	})
+}
+
+// handleAttachKeys handles keys while the attach manager is open
+func (m Model) handleAttachKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	attacher, ok := m.driver.(db.Attacher)
+	if !ok {
+		m.closeTopPopup()
+		return m, nil, true
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.closeTopPopup()
+		return m, nil, true
+	case "up":
+		if m.attachIdx > 0 {
+			m.attachIdx--
+		}
+		return m, nil, true
+	case "down":
+		if m.attachIdx < len(m.attachments)-1 {
+			m.attachIdx++
+		}
+		return m, nil, true
+	case "ctrl+x":
+		if m.attachIdx < len(m.attachments) {
+			return m, detachCmd(attacher, m.attachments[m.attachIdx].Schema), true
+		}
+		return m, nil, true
+	case "enter":
+		path, schema := parseAttachInput(m.attachInput.Value())
+		if path == "" {
+			return m, nil, true
+		}
+		m.attachInput.SetValue("")
+		return m, attachCmd(attacher, path, schema), true
+	}
+
+	var cmd tea.Cmd
+	m.attachInput, cmd = m.attachInput.Update(msg)
+	return m, cmd, true
+}
+
+// handleAttachments applies an attach/detach/list result and reloads the schema if it changed
+func (m Model) handleAttachments(msg AttachmentsMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	}
+	m.errorMsg = ""
+	m.attachments = msg.Attachments
+	if m.attachIdx >= len(m.attachments) {
+		m.attachIdx = max(len(m.attachments)-1, 0)
+	}
+	if msg.Status != "" {
+		m.statusMsg = msg.Status
+	}
+	if msg.Changed && m.driver != nil {
+		m.loadingTables = true
+		return m, schemabrowser.LoadSchemaCmd(m.driver)
+	}
+	return m, nil
+}
+
+// parseAttachInput splits "path [AS name]"
+func parseAttachInput(input string) (path, schema string) {
+	input = strings.TrimSpace(input)
+	if i := strings.LastIndex(strings.ToUpper(input), " AS "); i >= 0 {
+		return strings.TrimSpace(input[:i]), strings.TrimSpace(input[i+4:])
+	}
+	return input, ""
+}
+
+func listAttachmentsCmd(attacher db.Attacher) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		attachments, err := attacher.Attachments(ctx)
+		return AttachmentsMsg{Attachments: attachments, Err: err}
+	}
+}
+
+func attachCmd(attacher db.Attacher, path, schema string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		schema, err := attacher.Attach(ctx, path, schema)
+		if err != nil {
+			return AttachmentsMsg{Err: err}
+		}
+		attachments, err := attacher.Attachments(ctx)
+		return AttachmentsMsg{
+			Attachments: attachments,
+			Status:      fmt.Sprintf("Attached %s as %s", path, schema),
+			Changed:     true,
+			Err:         err,
+		}
+	}
+}
+
+func detachCmd(attacher db.Attacher, schema string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := attacher.Detach(ctx, schema); err != nil {
+			return AttachmentsMsg{Err: err}
+		}
+		attachments, err := attacher.Attachments(ctx)
+		return AttachmentsMsg{
+			Attachments: attachments,
+			Status:      "Detached " + schema,
+			Changed:     true,
+			Err:         err,
+		}
+	}
+}
+
+func (m Model) renderAttachPopup(main string) string {
+	var content strings.Builder
+	popupWidth := 64
+	if popupWidth > m.width-10 {
+		popupWidth = m.width - 10
+	}
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Attached Databases")
+	content.WriteString(title)
+	content.WriteString("\n\n")
+
+	if len(m.attachments) == 0 {
+		content.WriteString(lipgloss.NewStyle().Faint(true).Render("  (none)"))
+		content.WriteString("\n")
+	}
+	for i, a := range m.attachments {
+		style := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
+		prefix := "  "
+		if i == m.attachIdx {
+			style = lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Bold(true)
+			prefix = "> "
+		}
+		content.WriteString(prefix + style.Render(a.Schema) + "  " +
+			lipgloss.NewStyle().Faint(true).Render(limitString(a.Path, 40)) + "\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(m.attachInput.View())
+	content.WriteString("\n\n")
+	content.WriteString(m.renderHintLine("Enter: attach • ↑/↓: select • Ctrl+X: detach • Esc: close", m.popupInnerWidth(popupWidth)))
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
@@ -0,0 +1,77 @@
+// internal/ui/audit.go
+// Audit trail: every statement the UI executes is appended to the configured audit log.
+package ui
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/nhath/ezdb/internal/audit"
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// errReadOnly refuses a write on a read-only profile
+var errReadOnly = errors.New("profile is read-only")
+
+// writable returns errReadOnly for statements that would modify a read-only profile
+func (m Model) writable(profile *config.Profile, stmt string) error {
+	if profile != nil && m.config.ReadOnly(profile) && isModifyingQuery(stmt) {
+		return errReadOnly
+	}
+	return nil
+}
+
+// execute runs a statement on the connected database and audits it
+func (m Model) execute(ctx context.Context, stmt string) (*db.QueryResult, error) {
+	if err := m.writable(m.profile, stmt); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	result, err := m.driver.Execute(ctx, stmt)
+	m.audit(m.profile, stmt, start, result, err)
+	return result, err
+}
+
+// executeOn runs a statement on another profile's connection and audits it
+// under that profile
+func (m Model) executeOn(ctx context.Context, driver db.Driver, profile *config.Profile, stmt string) (*db.QueryResult, error) {
+	if err := m.writable(profile, stmt); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	result, err := driver.Execute(ctx, stmt)
+	m.audit(profile, stmt, start, result, err)
+	return result, err
+}
+
+// audit appends the outcome of a statement on profile to the audit log, if one is open.
+// A failed write does not undo the statement, so it is only logged.
+func (m Model) audit(profile *config.Profile, stmt string, start time.Time, result *db.QueryResult, err error) {
+	if m.auditLog == nil {
+		return
+	}
+	r := audit.Record{
+		Time:       start,
+		OSUser:     m.osUser,
+		Statement:  stmt,
+		DurationMs: time.Since(start).Milliseconds(),
+		Status:     "success",
+	}
+	if profile != nil {
+		r.Profile, r.DBUser, r.Database = profile.Name, profile.User, profile.Database
+	}
+	switch {
+	case err != nil:
+		r.Status, r.Error = "error", err.Error()
+	case result.IsSelect:
+		r.Rows = int64(result.RowCount)
+	default:
+		r.Rows = result.AffectedRows
+	}
+	if err := m.auditLog.Append(r); err != nil {
+		log.Printf("audit log: %v", err)
+	}
+}
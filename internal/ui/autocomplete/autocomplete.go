@@ -1,6 +1,8 @@
 package autocomplete
 
 import (
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -13,6 +15,7 @@ type Suggestion struct {
 	Type     SuggestionType
 	Detail   string // e.g., column type, function signature
 	Priority int    // Lower is higher priority
+	Snippet  string // Inserted instead of Text when set; may hold ${1:placeholder} tab-stops
 }
 
 // SuggestionType indicates what kind of completion to show
@@ -24,19 +27,34 @@ const (
 	SuggestColumn
 	SuggestFunction
 	SuggestAlias
+	SuggestSnippet // A full past query from history
+	SuggestRecent  // An identifier seen in past queries
+	SuggestJoin    // A JOIN clause or ON condition following a foreign key
 )
 
 // SQL keywords organized by context
 var (
-	// Keywords that start statements
+	// Keywords that start statements; the rest are per database in dialectCatalogs
 	statementKeywords = []string{
 		"SELECT", "INSERT", "UPDATE", "DELETE", "CREATE", "DROP", "ALTER",
-		"TRUNCATE", "EXPLAIN", "DESCRIBE", "SHOW", "USE", "BEGIN", "COMMIT", "ROLLBACK",
+		"BEGIN", "COMMIT", "ROLLBACK",
+	}
+
+	// Extra statement keywords per server flavor (see db.FlavorReporter)
+	dialectStatementKeywords = map[string][]string{
+		db.FlavorMariaDB:     {"ANALYZE", "CREATE SEQUENCE", "CREATE OR REPLACE", "RETURNING", "HANDLER", "OPTIMIZE", "FLUSH"},
+		db.FlavorCockroachDB: {"SHOW RANGES FROM TABLE", "SHOW JOBS", "SHOW REGIONS", "SHOW ZONE CONFIGURATIONS", "SHOW STATISTICS FOR TABLE", "UPSERT"},
+		db.FlavorTiDB:        {"ADMIN", "TRACE", "SPLIT TABLE", "FLASHBACK TABLE", "RECOVER TABLE", "BATCH", "ANALYZE TABLE", "SHOW STATS_META"},
+	}
+
+	// Extra clauses after FROM per server flavor
+	dialectFromKeywords = map[string][]string{
+		db.FlavorCockroachDB: {"AS OF SYSTEM TIME '-10s'", "AS OF SYSTEM TIME follower_read_timestamp()"},
 	}
 
 	// Keywords after SELECT
 	selectKeywords = []string{
-		"DISTINCT", "ALL", "TOP", "AS", "FROM",
+		"DISTINCT", "ALL", "AS", "FROM",
 	}
 
 	// Keywords after FROM/JOIN
@@ -47,7 +65,7 @@ var (
 
 	// Keywords after WHERE/AND/OR
 	whereKeywords = []string{
-		"AND", "OR", "NOT", "IN", "BETWEEN", "LIKE", "ILIKE", "IS", "NULL",
+		"AND", "OR", "NOT", "IN", "BETWEEN", "LIKE", "IS", "NULL",
 		"TRUE", "FALSE", "EXISTS", "ANY", "ALL", "SOME",
 	}
 
@@ -63,29 +81,29 @@ var (
 
 	// Aggregate functions
 	aggregateFunctions = []string{
-		"COUNT", "SUM", "AVG", "MIN", "MAX", "GROUP_CONCAT", "STRING_AGG",
+		"COUNT", "SUM", "AVG", "MIN", "MAX",
 	}
 
-	// Common SQL functions
+	// Common SQL functions, valid on every SQL database
 	commonFunctions = []string{
-		"COALESCE", "NULLIF", "CAST", "CONVERT", "CONCAT", "SUBSTRING", "LENGTH",
-		"UPPER", "LOWER", "TRIM", "LTRIM", "RTRIM", "REPLACE", "NOW", "CURRENT_DATE",
-		"CURRENT_TIME", "CURRENT_TIMESTAMP", "DATE", "TIME", "DATETIME", "YEAR", "MONTH", "DAY",
-		"ROUND", "FLOOR", "CEIL", "ABS", "MOD", "IFNULL", "NVL", "CASE", "WHEN", "THEN", "ELSE", "END",
+		"COALESCE", "NULLIF", "CAST", "LENGTH", "UPPER", "LOWER", "TRIM", "LTRIM", "RTRIM", "REPLACE",
+		"CURRENT_DATE", "CURRENT_TIME", "CURRENT_TIMESTAMP", "ROUND", "ABS",
+		"CASE", "WHEN", "THEN", "ELSE", "END",
 	}
 
 	// All keywords combined for legacy compatibility
-	SQLKeywords = append(append(append(append(append(append(
+	SQLKeywords = append(append(append(append(append(append(append(
 		statementKeywords, selectKeywords...), fromKeywords...), whereKeywords...),
-		groupKeywords...), orderKeywords...), aggregateFunctions...)
+		groupKeywords...), orderKeywords...), aggregateFunctions...), catalogKeywords()...)
 )
 
 // SQLContext represents the parsed context of a SQL statement
 type SQLContext struct {
-	StatementType string            // SELECT, INSERT, UPDATE, DELETE, etc.
-	LastKeyword   string            // Most recent keyword before cursor
-	Tables        []string          // Tables referenced in the query (with aliases)
-	TableAliases  map[string]string // alias -> table name
+	StatementType string              // SELECT, INSERT, UPDATE, DELETE, etc.
+	LastKeyword   string              // Most recent keyword before cursor
+	Tables        []string            // Tables referenced in the query (with aliases)
+	TableAliases  map[string]string   // alias -> table name
+	Relations     map[string]Relation // CTEs and derived tables, by name
 	InSelect      bool
 	InFrom        bool
 	InWhere       bool
@@ -96,8 +114,17 @@ type SQLContext struct {
 	InInsert      bool
 	InUpdate      bool
 	InSet         bool
-	AfterDot      bool   // After a "." for qualified names
-	Qualifier     string // Table/alias before the dot
+	AfterJoin     bool          // Where the table of a JOIN goes
+	JoinTable     string        // Table just joined, when the cursor is right after its ON
+	InsertTable   string        // Target of INSERT INTO
+	InsertColumns []string      // Columns listed after the INSERT target
+	InInsertList  bool          // Inside the INSERT column list
+	AfterValues   bool          // Right after VALUES, where a row goes
+	ValuesParen   bool          // The row's opening parenthesis is already typed
+	AfterDot      bool          // After a "." for qualified names
+	Qualifier     string        // Table/alias before the dot
+	Driver        db.DriverType // Selects the keyword and function catalog; empty for generic SQL
+	Dialect       string        // Server flavor (e.g. "MariaDB", "TiDB"); empty for generic SQL
 }
 
 // ParseSQLContext analyzes SQL text up to cursor position to determine context
@@ -172,7 +199,12 @@ func ParseSQLContext(sql string, cursorPos int) SQLContext {
 	}
 
 	// Extract tables and aliases from the query
-	ctx.Tables, ctx.TableAliases = extractTables(sql[:cursorPos])
+	relations, outer := extractRelations(sql[:cursorPos])
+	ctx.Tables, ctx.TableAliases = extractTables(outer)
+	ctx.Relations = relations
+	addRelations(&ctx)
+	ctx.AfterJoin, ctx.JoinTable = joinPosition(sql, cursorPos)
+	parseInsert(&ctx, sql, cursorPos)
 
 	// Check if we're after a dot (qualified name)
 	_, start, _ := GetWordAtCursor(sql, cursorPos)
@@ -293,11 +325,44 @@ func findTableColumns(tableName string, columns map[string][]db.Column) ([]db.Co
 	return nil, false
 }
 
+// InSchema adds the tables of the current schema under their unqualified
+// names, the way the search_path resolves them, ahead of the other tables
+func InSchema(schema string, tables []string, columns map[string][]db.Column) ([]string, map[string][]db.Column) {
+	if schema == "" {
+		return tables, columns
+	}
+	prefix := schema + "."
+	var local []string
+	for _, table := range tables {
+		if name, ok := strings.CutPrefix(table, prefix); ok && !slices.Contains(tables, name) {
+			local = append(local, name)
+		}
+	}
+	if len(local) == 0 {
+		return tables, columns
+	}
+	scoped := make(map[string][]db.Column, len(columns)+len(local))
+	for table, cols := range columns {
+		scoped[table] = cols
+	}
+	for _, name := range local {
+		if cols, ok := columns[prefix+name]; ok {
+			scoped[name] = cols
+		}
+	}
+	return append(local, tables...), scoped
+}
+
 // GetSuggestions returns context-aware suggestions
-func GetSuggestions(ctx SQLContext, tables []string, columns map[string][]db.Column, input string) []Suggestion {
+func GetSuggestions(ctx SQLContext, tables []string, columns map[string][]db.Column, constraints map[string][]db.Constraint, functions []db.Function, input string) []Suggestion {
 	var suggestions []Suggestion
 	inputUpper := strings.ToUpper(input)
 
+	// CTEs and derived tables complete like tables of the schema
+	columns = withRelations(ctx.Relations, columns)
+	tables = append(cteNames(ctx.Relations), tables...)
+	dialect := dialectCatalogs[ctx.Driver]
+
 	// After a dot - suggest columns for the qualified table/alias
 	if ctx.AfterDot {
 		tableName := ctx.Qualifier
@@ -328,6 +393,20 @@ func GetSuggestions(ctx SQLContext, tables []string, columns map[string][]db.Col
 		for _, kw := range statementKeywords {
 			suggestions = append(suggestions, Suggestion{Text: kw, Type: SuggestKeyword, Priority: 1})
 		}
+		for _, kw := range dialect.statements {
+			suggestions = append(suggestions, Suggestion{Text: kw, Type: SuggestKeyword, Priority: 1})
+		}
+		for _, kw := range dialectStatementKeywords[ctx.Dialect] {
+			suggestions = append(suggestions, Suggestion{Text: kw, Type: SuggestKeyword, Detail: ctx.Dialect, Priority: 2})
+		}
+
+	case ctx.InInsertList:
+		// Inside INSERT INTO t ( ... ) - suggest the columns not listed yet
+		suggestions = append(suggestions, insertColumnSuggestions(ctx, columns)...)
+
+	case ctx.AfterValues:
+		// After VALUES - suggest a row with a tab-stop per column
+		suggestions = append(suggestions, valuesSuggestions(ctx, columns)...)
 
 	case ctx.InSelect:
 		// After SELECT - suggest columns, functions, tables (for table.*)
@@ -348,10 +427,17 @@ func GetSuggestions(ctx SQLContext, tables []string, columns map[string][]db.Col
 		for _, fn := range aggregateFunctions {
 			suggestions = append(suggestions, Suggestion{Text: fn + "(", Type: SuggestFunction, Priority: 3})
 		}
+		for _, fn := range dialect.aggregates {
+			suggestions = append(suggestions, Suggestion{Text: fn + "(", Type: SuggestFunction, Priority: 3})
+		}
 		// Add common functions
 		for _, fn := range commonFunctions {
 			suggestions = append(suggestions, Suggestion{Text: fn + "(", Type: SuggestFunction, Priority: 4})
 		}
+		for _, fn := range dialect.functions {
+			suggestions = append(suggestions, Suggestion{Text: fn + "(", Type: SuggestFunction, Priority: 4})
+		}
+		suggestions = append(suggestions, functionSuggestions(functions, 4)...)
 		// Add tables for qualified references
 		for _, tbl := range tables {
 			suggestions = append(suggestions, Suggestion{Text: tbl, Type: SuggestTable, Priority: 5})
@@ -362,14 +448,23 @@ func GetSuggestions(ctx SQLContext, tables []string, columns map[string][]db.Col
 		}
 
 	case ctx.InFrom || ctx.InJoin:
+		// Whole joins along foreign keys come before bare tables
+		suggestions = append(suggestions, joinSuggestions(ctx, tables, constraints, input)...)
 		// After FROM/JOIN - suggest tables
 		for _, tbl := range tables {
 			suggestions = append(suggestions, Suggestion{Text: tbl, Type: SuggestTable, Priority: 1})
 		}
 		// Add FROM/JOIN keywords
-		for _, kw := range fromKeywords {
+		from := fromKeywords
+		if dialect.from != nil {
+			from = dialect.from
+		}
+		for _, kw := range from {
 			suggestions = append(suggestions, Suggestion{Text: kw, Type: SuggestKeyword, Priority: 3})
 		}
+		for _, kw := range dialectFromKeywords[ctx.Dialect] {
+			suggestions = append(suggestions, Suggestion{Text: kw, Type: SuggestKeyword, Detail: ctx.Dialect, Priority: 4})
+		}
 
 	case ctx.InWhere || ctx.InHaving:
 		// After WHERE - suggest columns, operators, functions
@@ -402,10 +497,17 @@ func GetSuggestions(ctx SQLContext, tables []string, columns map[string][]db.Col
 		for _, fn := range commonFunctions {
 			suggestions = append(suggestions, Suggestion{Text: fn + "(", Type: SuggestFunction, Priority: 3})
 		}
+		for _, fn := range dialect.functions {
+			suggestions = append(suggestions, Suggestion{Text: fn + "(", Type: SuggestFunction, Priority: 3})
+		}
+		suggestions = append(suggestions, functionSuggestions(functions, 3)...)
 		// Add WHERE keywords
 		for _, kw := range whereKeywords {
 			suggestions = append(suggestions, Suggestion{Text: kw, Type: SuggestKeyword, Priority: 4})
 		}
+		for _, kw := range dialect.operators {
+			suggestions = append(suggestions, Suggestion{Text: kw, Type: SuggestKeyword, Priority: 4})
+		}
 
 	case ctx.InGroupBy:
 		// After GROUP BY - suggest columns
@@ -463,6 +565,9 @@ func GetSuggestions(ctx SQLContext, tables []string, columns map[string][]db.Col
 		for _, kw := range statementKeywords {
 			suggestions = append(suggestions, Suggestion{Text: kw, Type: SuggestKeyword, Priority: 5})
 		}
+		for _, kw := range dialect.statements {
+			suggestions = append(suggestions, Suggestion{Text: kw, Type: SuggestKeyword, Priority: 5})
+		}
 		for _, tbl := range tables {
 			suggestions = append(suggestions, Suggestion{Text: tbl, Type: SuggestTable, Priority: 3})
 		}
@@ -483,6 +588,38 @@ func GetSuggestions(ctx SQLContext, tables []string, columns map[string][]db.Col
 	return filtered
 }
 
+// functionSuggestions offers user-defined functions with their signatures as detail
+func functionSuggestions(functions []db.Function, priority int) []Suggestion {
+	suggestions := make([]Suggestion, 0, len(functions))
+	for _, fn := range functions {
+		detail := "(" + fn.Arguments + ")"
+		if fn.Returns != "" {
+			detail += " → " + fn.Returns
+		}
+		suggestions = append(suggestions, Suggestion{
+			Text:     fn.Name + "(",
+			Type:     SuggestFunction,
+			Detail:   detail,
+			Priority: priority,
+			Snippet:  functionSnippet(fn),
+		})
+	}
+	return suggestions
+}
+
+// functionSnippet calls fn with a tab-stop per argument, e.g.
+// "f(${1:a integer}, ${2:b text})${0}"
+func functionSnippet(fn db.Function) string {
+	if fn.Arguments == "" {
+		return fn.Name + "()"
+	}
+	args := strings.Split(fn.Arguments, ", ")
+	for i, arg := range args {
+		args[i] = "${" + strconv.Itoa(i+1) + ":" + strings.ReplaceAll(arg, "}", "") + "}"
+	}
+	return fn.Name + "(" + strings.Join(args, ", ") + ")${0}"
+}
+
 // filterSuggestionsTyped filters suggestions by prefix
 func filterSuggestionsTyped(suggestions []Suggestion, input string) []Suggestion {
 	if input == "" {
@@ -0,0 +1,37 @@
+package autocomplete
+
+import (
+	"testing"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+func TestUserFunctionSuggestions(t *testing.T) {
+	functions := []db.Function{{Name: "order_total", Arguments: "order_id integer", Returns: "numeric"}}
+	sql := "SELECT * FROM orders WHERE order_"
+	ctx := ParseSQLContext(sql, len(sql))
+
+	got := GetSuggestions(ctx, nil, nil, nil, functions, "order_")
+	if len(got) != 1 || got[0].Text != "order_total(" || got[0].Detail != "(order_id integer) → numeric" {
+		t.Fatalf("suggestions = %+v", got)
+	}
+}
+
+func TestInSchema(t *testing.T) {
+	tables := []string{"app.users", "public.users", "public.orders"}
+	columns := map[string][]db.Column{
+		"app.users":    {{Name: "handle"}},
+		"public.users": {{Name: "email"}},
+	}
+
+	scoped, cols := InSchema("app", tables, columns)
+	if len(scoped) != 4 || scoped[0] != "users" {
+		t.Fatalf("tables = %v, want users first", scoped)
+	}
+	if got, _ := findTableColumns("users", cols); len(got) != 1 || got[0].Name != "handle" {
+		t.Fatalf("users resolved to %v, want app.users", got)
+	}
+	if _, ok := columns["users"]; ok {
+		t.Fatal("InSchema modified the schema's columns")
+	}
+}
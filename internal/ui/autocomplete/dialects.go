@@ -0,0 +1,69 @@
+package autocomplete
+
+import "github.com/nhath/ezdb/internal/db"
+
+// catalog holds the keywords and functions valid for one database type, on
+// top of the portable ones
+type catalog struct {
+	statements []string // Extra statement keywords
+	from       []string // Replaces fromKeywords when set
+	operators  []string // Extra keywords after WHERE
+	aggregates []string
+	functions  []string
+}
+
+// dialectCatalogs are selected by the connected driver's type
+var dialectCatalogs = map[db.DriverType]catalog{
+	db.Postgres: {
+		statements: []string{"EXPLAIN", "TRUNCATE", "SHOW", "WITH", "COPY", "VACUUM", "ANALYZE"},
+		operators:  []string{"ILIKE", "SIMILAR TO", "IS DISTINCT FROM"},
+		aggregates: []string{"STRING_AGG", "ARRAY_AGG", "JSON_AGG", "BOOL_AND", "BOOL_OR"},
+		functions: []string{
+			"NOW", "CONCAT", "SUBSTRING", "DATE_TRUNC", "EXTRACT", "TO_CHAR", "AGE", "GENERATE_SERIES",
+			"GREATEST", "LEAST", "FLOOR", "CEIL", "MOD", "SPLIT_PART", "REGEXP_REPLACE", "JSONB_BUILD_OBJECT",
+		},
+	},
+	db.MySQL: {
+		statements: []string{"EXPLAIN", "TRUNCATE", "SHOW", "DESCRIBE", "USE", "WITH", "REPLACE"},
+		operators:  []string{"REGEXP", "RLIKE"},
+		aggregates: []string{"GROUP_CONCAT", "JSON_ARRAYAGG"},
+		functions: []string{
+			"NOW", "IFNULL", "IF", "CONVERT", "CONCAT", "CONCAT_WS", "SUBSTRING", "DATE", "TIME", "YEAR", "MONTH", "DAY",
+			"DATE_FORMAT", "DATE_ADD", "DATE_SUB", "DATEDIFF", "UNIX_TIMESTAMP", "GREATEST", "LEAST", "FLOOR", "CEIL", "MOD",
+			"JSON_EXTRACT",
+		},
+	},
+	db.SQLite: {
+		statements: []string{"EXPLAIN", "WITH", "PRAGMA", "VACUUM", "ATTACH", "DETACH", "REINDEX"},
+		operators:  []string{"GLOB", "REGEXP"},
+		aggregates: []string{"GROUP_CONCAT", "TOTAL"},
+		functions: []string{
+			"IFNULL", "IIF", "SUBSTR", "INSTR", "DATE", "TIME", "DATETIME", "STRFTIME", "JULIANDAY", "UNIXEPOCH",
+			"PRINTF", "TYPEOF", "RANDOM", "JSON_EXTRACT",
+		},
+	},
+	db.Cassandra: {
+		statements: []string{"TRUNCATE", "DESCRIBE", "USE", "BEGIN BATCH", "APPLY BATCH"},
+		from:       []string{"WHERE", "ORDER", "LIMIT", "PER PARTITION LIMIT", "ALLOW FILTERING"},
+		operators:  []string{"CONTAINS", "CONTAINS KEY", "ALLOW FILTERING"},
+		functions:  []string{"NOW", "UUID", "TOKEN", "TTL", "WRITETIME", "TOTIMESTAMP", "TODATE", "MINTIMEUUID", "MAXTIMEUUID"},
+	},
+	db.BigQuery: {
+		statements: []string{"WITH", "TRUNCATE TABLE", "MERGE"},
+		aggregates: []string{"STRING_AGG", "ARRAY_AGG", "COUNTIF", "APPROX_COUNT_DISTINCT", "ANY_VALUE"},
+		functions: []string{
+			"IFNULL", "IF", "CONCAT", "SUBSTR", "SAFE_CAST", "SAFE_DIVIDE", "DATE", "TIMESTAMP", "DATE_TRUNC",
+			"TIMESTAMP_TRUNC", "EXTRACT", "FORMAT_DATE", "PARSE_DATE", "GENERATE_ARRAY", "UNNEST", "GREATEST", "LEAST",
+			"FLOOR", "CEIL", "MOD",
+		},
+	},
+}
+
+// catalogKeywords lists every dialect's keywords, so none is mistaken for an identifier
+func catalogKeywords() []string {
+	var keywords []string
+	for _, c := range dialectCatalogs {
+		keywords = append(append(append(append(keywords, c.statements...), c.from...), c.operators...), c.aggregates...)
+	}
+	return keywords
+}
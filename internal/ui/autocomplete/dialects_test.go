@@ -0,0 +1,36 @@
+package autocomplete
+
+import (
+	"testing"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+func dialectSuggestions(driver db.DriverType, sql string) map[string]bool {
+	ctx := ParseSQLContext(sql, len(sql))
+	ctx.Driver = driver
+	word, _, _ := GetWordAtCursor(sql, len(sql))
+	texts := make(map[string]bool)
+	for _, s := range GetSuggestions(ctx, []string{"users"}, nil, nil, nil, word) {
+		texts[s.Text] = true
+	}
+	return texts
+}
+
+func TestDialectKeywords(t *testing.T) {
+	if !dialectSuggestions(db.Postgres, "SELECT * FROM users WHERE name I")["ILIKE"] {
+		t.Error("ILIKE missing for PostgreSQL")
+	}
+	if dialectSuggestions(db.MySQL, "SELECT * FROM users WHERE name I")["ILIKE"] {
+		t.Error("ILIKE suggested for MySQL")
+	}
+	if !dialectSuggestions(db.MySQL, "SELECT GROUP")["GROUP_CONCAT("] {
+		t.Error("GROUP_CONCAT missing for MySQL")
+	}
+	if dialectSuggestions(db.Postgres, "SELECT GROUP")["GROUP_CONCAT("] {
+		t.Error("GROUP_CONCAT suggested for PostgreSQL")
+	}
+	if !dialectSuggestions(db.SQLite, "PRA")["PRAGMA"] {
+		t.Error("PRAGMA missing for SQLite")
+	}
+}
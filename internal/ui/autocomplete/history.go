@@ -0,0 +1,199 @@
+package autocomplete
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxSnippetSuggestions caps the past queries offered at once
+	maxSnippetSuggestions = 5
+	// minSnippetPrefix is how much of a query must be typed before past queries are offered
+	minSnippetPrefix = 3
+)
+
+// HistoryQuery is a distinct past query and how often it ran
+type HistoryQuery struct {
+	Query string
+	Count int
+}
+
+// HistoryIndex ranks past queries and the identifiers in them by frequency,
+// decayed by recency
+type HistoryIndex struct {
+	snippets    []scored
+	identifiers map[string]*scored // Lowercased name -> original spelling and score
+}
+
+// clauseWords are keywords that appear inside statements but are never
+// suggested on their own, so are missing from SQLKeywords
+var clauseWords = map[string]bool{
+	"SET": true, "INTO": true, "VALUES": true, "BY": true, "TABLE": true, "VIEW": true,
+	"INDEX": true, "KEY": true, "PRIMARY": true, "DEFAULT": true, "IF": true, "WITH": true,
+	"RETURNING": true, "REFERENCES": true, "CONSTRAINT": true,
+}
+
+type scored struct {
+	text  string
+	count int
+	score float64
+}
+
+// NewHistoryIndex builds an index from distinct queries, most recently run first
+func NewHistoryIndex(queries []HistoryQuery) *HistoryIndex {
+	idx := &HistoryIndex{identifiers: make(map[string]*scored)}
+	for rank, q := range queries {
+		// A query loses half its weight every 20 more recent queries
+		score := float64(q.Count) / (1 + float64(rank)/20)
+		idx.addScored(q.Query, q.Count, score)
+	}
+	idx.sort()
+	return idx
+}
+
+// Add records a query that just ran, ranking it as the most recent
+func (h *HistoryIndex) Add(query string) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return
+	}
+	top := 1.0
+	if len(h.snippets) > 0 {
+		top = max(top, h.snippets[0].score)
+	}
+	for i, s := range h.snippets {
+		if s.text == query {
+			h.snippets = append(h.snippets[:i], h.snippets[i+1:]...)
+			h.addScored(query, s.count+1, top+1)
+			h.sort()
+			return
+		}
+	}
+	h.addScored(query, 1, top)
+	h.sort()
+}
+
+func (h *HistoryIndex) addScored(query string, count int, score float64) {
+	h.snippets = append(h.snippets, scored{text: query, count: count, score: score})
+	for _, ident := range historyIdentifiers(query) {
+		key := strings.ToLower(ident)
+		if s, ok := h.identifiers[key]; ok {
+			s.count += count
+			s.score += score
+		} else {
+			h.identifiers[key] = &scored{text: ident, count: count, score: score}
+		}
+	}
+}
+
+func (h *HistoryIndex) sort() {
+	sort.SliceStable(h.snippets, func(i, j int) bool { return h.snippets[i].score > h.snippets[j].score })
+}
+
+// historyIdentifiers returns the table and column names in a query: words
+// that are not keywords, functions, numbers or inside string literals
+func historyIdentifiers(query string) []string {
+	var b strings.Builder
+	inQuote := false
+	for _, r := range query {
+		if r == '\'' {
+			inQuote = !inQuote
+			b.WriteRune(' ')
+			continue
+		}
+		if !inQuote {
+			b.WriteRune(r)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var idents []string
+	for _, token := range tokenizeSQL(b.String()) {
+		for _, part := range strings.Split(token, ".") {
+			upper := strings.ToUpper(part)
+			if len(part) < 2 || unicode.IsDigit(rune(part[0])) || isKeyword(upper) || clauseWords[upper] || isFunction(upper) || seen[upper] {
+				continue
+			}
+			seen[upper] = true
+			idents = append(idents, part)
+		}
+	}
+	return idents
+}
+
+// isFunction checks if a token is a known SQL function of any dialect
+func isFunction(s string) bool {
+	for _, fn := range commonFunctions {
+		if s == fn {
+			return true
+		}
+	}
+	for _, c := range dialectCatalogs {
+		for _, fn := range c.functions {
+			if s == fn {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WithHistory merges history into suggestions: past queries starting with
+// the text typed so far come first, identifiers from past queries that the
+// schema did not suggest are added, and suggestions of equal priority are
+// ordered by how often and how recently they were used.
+func WithHistory(suggestions []Suggestion, h *HistoryIndex, text, word string) []Suggestion {
+	if h == nil {
+		return suggestions
+	}
+
+	var snippets []Suggestion
+	typed := strings.TrimSpace(text)
+	if len(typed) >= minSnippetPrefix {
+		lower := strings.ToLower(typed)
+		for _, s := range h.snippets {
+			if len(snippets) == maxSnippetSuggestions {
+				break
+			}
+			if s.text != typed && strings.HasPrefix(strings.ToLower(s.text), lower) {
+				snippets = append(snippets, Suggestion{Text: s.text, Type: SuggestSnippet, Detail: usedDetail(s.count)})
+			}
+		}
+	}
+
+	if word != "" {
+		present := make(map[string]bool, len(suggestions))
+		for _, s := range suggestions {
+			present[strings.ToLower(s.Text)] = true
+		}
+		lowerWord := strings.ToLower(word)
+		for key, s := range h.identifiers {
+			if !present[key] && key != lowerWord && strings.HasPrefix(key, lowerWord) {
+				suggestions = append(suggestions, Suggestion{Text: s.text, Type: SuggestRecent, Detail: usedDetail(s.count), Priority: 2})
+			}
+		}
+	}
+
+	score := func(s Suggestion) float64 {
+		if id, ok := h.identifiers[strings.ToLower(s.Text)]; ok {
+			return id.score
+		}
+		return 0
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Priority != suggestions[j].Priority {
+			return suggestions[i].Priority < suggestions[j].Priority
+		}
+		return score(suggestions[i]) > score(suggestions[j])
+	})
+	return append(snippets, suggestions...)
+}
+
+func usedDetail(count int) string {
+	if count == 1 {
+		return "used once"
+	}
+	return "used " + strconv.Itoa(count) + "×"
+}
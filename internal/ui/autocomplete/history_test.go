@@ -0,0 +1,55 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHistoryIdentifiers(t *testing.T) {
+	got := historyIdentifiers("SELECT u.name, COUNT(*) FROM users u WHERE u.note = 'from orders' AND id > 10")
+	want := []string{"name", "users", "note", "id"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("historyIdentifiers = %v, want %v", got, want)
+	}
+}
+
+func TestWithHistorySnippets(t *testing.T) {
+	h := NewHistoryIndex([]HistoryQuery{
+		{Query: "SELECT * FROM orders", Count: 1},
+		{Query: "SELECT * FROM users", Count: 5},
+		{Query: "UPDATE users SET name = 'x'", Count: 9},
+	})
+
+	got := WithHistory(nil, h, "sel", "sel")
+	if len(got) != 2 || got[0].Text != "SELECT * FROM users" || got[1].Text != "SELECT * FROM orders" {
+		t.Fatalf("snippets = %v, want users then orders", got)
+	}
+	if got := WithHistory(nil, h, "se", ""); len(got) != 0 {
+		t.Fatalf("snippets offered for a short prefix: %v", got)
+	}
+
+	// A query run now outranks more frequent older ones
+	h.Add("SELECT * FROM orders")
+	if got := WithHistory(nil, h, "sel", "sel"); got[0].Text != "SELECT * FROM orders" {
+		t.Fatalf("after Add, first snippet = %q", got[0].Text)
+	}
+}
+
+func TestWithHistoryIdentifiers(t *testing.T) {
+	h := NewHistoryIndex([]HistoryQuery{
+		{Query: "SELECT * FROM orders", Count: 1},
+		{Query: "SELECT * FROM order_items", Count: 8},
+	})
+	schema := []Suggestion{{Text: "orders", Type: SuggestTable, Priority: 2}}
+
+	got := WithHistory(schema, h, "ord", "ord")
+	if len(got) != 2 {
+		t.Fatalf("suggestions = %v, want orders and order_items", got)
+	}
+	if got[0].Text != "order_items" || got[0].Type != SuggestRecent {
+		t.Fatalf("first = %+v, want the more used order_items from history", got[0])
+	}
+	if got[1].Type != SuggestTable {
+		t.Fatalf("schema suggestion was replaced: %+v", got[1])
+	}
+}
@@ -0,0 +1,96 @@
+package autocomplete
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// insertPattern matches an INSERT statement up to the cursor: its target, an
+// optional column list (closed or still being typed) and an optional VALUES
+var insertPattern = regexp.MustCompile("(?is)\\bINSERT\\s+(?:IGNORE\\s+)?INTO\\s+([\\w.\"`]+)\\s*(?:\\(([^()]*)(\\))?)?\\s*(VALUES\\s*(\\()?\\s*)?$")
+
+// parseInsert fills in the INSERT context: the cursor inside the column list
+// of INSERT INTO t (...) or where the row after VALUES goes
+func parseInsert(ctx *SQLContext, sql string, cursorPos int) {
+	before := sql[:cursorPos]
+	if word, start, _ := GetWordAtCursor(sql, cursorPos); word != "" {
+		before = sql[:start]
+	}
+	m := insertPattern.FindStringSubmatchIndex(before)
+	if m == nil {
+		return
+	}
+	group := func(i int) string {
+		if m[2*i] < 0 {
+			return ""
+		}
+		return before[m[2*i]:m[2*i+1]]
+	}
+
+	ctx.InsertTable = strings.Trim(group(1), "\"`")
+	ctx.InsertColumns = splitColumnList(group(2))
+	listOpen := m[4] >= 0 && m[6] < 0
+	switch {
+	case m[8] >= 0:
+		// VALUES needs a closed column list, or none at all
+		ctx.AfterValues = !listOpen
+		ctx.ValuesParen = m[10] >= 0
+	case listOpen:
+		ctx.InInsertList = true
+	}
+}
+
+// insertColumnSuggestions offers the INSERT target's columns not listed yet
+func insertColumnSuggestions(ctx SQLContext, columns map[string][]db.Column) []Suggestion {
+	cols, ok := findTableColumns(ctx.InsertTable, columns)
+	if !ok {
+		return nil
+	}
+	listed := make(map[string]bool, len(ctx.InsertColumns))
+	for _, col := range ctx.InsertColumns {
+		listed[strings.ToLower(col)] = true
+	}
+
+	var suggestions []Suggestion
+	for _, col := range cols {
+		if !listed[strings.ToLower(col.Name)] {
+			suggestions = append(suggestions, Suggestion{Text: col.Name, Type: SuggestColumn, Detail: col.Type, Priority: 1})
+		}
+	}
+	return suggestions
+}
+
+// valuesSuggestions offers a row of tab-stops, one per listed column or, with
+// no column list, per column of the table
+func valuesSuggestions(ctx SQLContext, columns map[string][]db.Column) []Suggestion {
+	names := ctx.InsertColumns
+	if len(names) == 0 {
+		cols, _ := findTableColumns(ctx.InsertTable, columns)
+		for _, col := range cols {
+			names = append(names, col.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	stops := make([]string, len(names))
+	for i, name := range names {
+		stops[i] = "${" + strconv.Itoa(i+1) + ":" + name + "}"
+	}
+	text := strings.Join(names, ", ") + ")"
+	snippet := strings.Join(stops, ", ") + ")${0}"
+	if !ctx.ValuesParen {
+		text, snippet = "("+text, "("+snippet
+	}
+	return []Suggestion{{
+		Text:     text,
+		Type:     SuggestColumn,
+		Detail:   strconv.Itoa(len(names)) + " values",
+		Priority: 1,
+		Snippet:  snippet,
+	}}
+}
@@ -0,0 +1,45 @@
+package autocomplete
+
+import (
+	"testing"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+var insertSchema = map[string][]db.Column{
+	"users": {{Name: "id", Type: "integer"}, {Name: "name", Type: "text"}, {Name: "email", Type: "text"}},
+}
+
+func insertSuggestions(sql string) []Suggestion {
+	ctx := ParseSQLContext(sql, len(sql))
+	word, _, _ := GetWordAtCursor(sql, len(sql))
+	return GetSuggestions(ctx, []string{"users"}, insertSchema, nil, nil, word)
+}
+
+func TestInsertColumnList(t *testing.T) {
+	got := insertSuggestions("INSERT INTO users (id, ")
+	if len(got) != 2 || got[0].Text != "name" || got[1].Text != "email" {
+		t.Fatalf("column list = %+v, want name and email", got)
+	}
+	got = insertSuggestions("INSERT INTO users (id, na")
+	if len(got) != 1 || got[0].Text != "name" {
+		t.Fatalf("typed prefix = %+v, want name", got)
+	}
+}
+
+func TestInsertValuesRow(t *testing.T) {
+	tests := []struct {
+		sql, text, snippet string
+	}{
+		{"INSERT INTO users (name, email) VALUES ", "(name, email)", "(${1:name}, ${2:email})${0}"},
+		{"INSERT INTO users VALUES ", "(id, name, email)", "(${1:id}, ${2:name}, ${3:email})${0}"},
+		{"insert into users (id) values (", "id)", "${1:id})${0}"},
+		{"INSERT INTO users VALUES (", "id, name, email)", "${1:id}, ${2:name}, ${3:email})${0}"},
+	}
+	for _, tt := range tests {
+		got := insertSuggestions(tt.sql)
+		if len(got) != 1 || got[0].Text != tt.text || got[0].Snippet != tt.snippet {
+			t.Errorf("%q: suggestions = %+v, want %q", tt.sql, got, tt.snippet)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package autocomplete
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// foreignKey is a foreign key of table, flattened from its constraint
+type foreignKey struct {
+	name     string
+	table    string
+	cols     []string
+	refTable string
+	refCols  []string
+}
+
+// joinPosition reports whether the cursor is where a JOIN's table goes, or
+// else returns the joined table when the cursor is right after its ON
+func joinPosition(sql string, cursorPos int) (bool, string) {
+	before := sql[:cursorPos]
+	if word, start, _ := GetWordAtCursor(sql, cursorPos); word != "" {
+		before = sql[:start]
+	}
+	tokens := tokenizeSQL(before)
+	upper := tokenizeSQL(strings.ToUpper(before))
+	if len(upper) == 0 {
+		return false, ""
+	}
+
+	switch upper[len(upper)-1] {
+	case "JOIN":
+		return true, ""
+	case "ON":
+		for i := len(upper) - 2; i >= 0; i-- {
+			if upper[i] == "JOIN" {
+				if i+1 < len(tokens) {
+					return false, tokens[i+1]
+				}
+				break
+			}
+		}
+	}
+	return false, ""
+}
+
+// joinSuggestions returns whole JOIN clauses after JOIN, or ON conditions
+// right after ON, that follow foreign keys to the tables already in the query
+func joinSuggestions(ctx SQLContext, tables []string, constraints map[string][]db.Constraint, input string) []Suggestion {
+	if !ctx.AfterJoin && ctx.JoinTable == "" {
+		return nil
+	}
+	fks := foreignKeys(constraints)
+	if len(fks) == 0 {
+		return nil
+	}
+	if ctx.AfterJoin {
+		return joinClauses(ctx, tables, fks, input)
+	}
+	return joinConditions(ctx, fks)
+}
+
+// joinClauses suggests "orders o ON o.user_id = u.id" for each table related
+// to one already in the query
+func joinClauses(ctx SQLContext, tables []string, fks []foreignKey, input string) []Suggestion {
+	existing := ctx.Tables
+	if n := len(existing); n > 0 && input != "" && existing[n-1] == input {
+		// The word being typed after JOIN is not a table yet
+		existing = existing[:n-1]
+	}
+
+	taken := make(map[string]bool)
+	for alias := range ctx.TableAliases {
+		taken[strings.ToLower(alias)] = true
+	}
+	for _, t := range existing {
+		taken[strings.ToLower(unqualified(t))] = true
+	}
+
+	var suggestions []Suggestion
+	seen := make(map[string]bool)
+	for _, t := range existing {
+		ref := refName(ctx, t)
+		for _, fk := range fks {
+			var other string
+			var otherCols, cols []string
+			switch {
+			case sameTable(fk.table, t):
+				other, otherCols, cols = fk.refTable, fk.refCols, fk.cols
+			case sameTable(fk.refTable, t):
+				other, otherCols, cols = fk.table, fk.cols, fk.refCols
+			default:
+				continue
+			}
+			if !sameTable(fk.table, fk.refTable) && inQuery(existing, other) {
+				continue
+			}
+
+			name := schemaName(tables, other)
+			alias := newAlias(name, taken)
+			text := name + " " + alias + " ON " + joinCondition(alias, otherCols, ref, cols)
+			if seen[text] {
+				continue
+			}
+			seen[text] = true
+			suggestions = append(suggestions, Suggestion{Text: text, Type: SuggestJoin, Detail: fk.name})
+		}
+	}
+	return suggestions
+}
+
+// joinConditions suggests "o.user_id = u.id" between the table just joined
+// and the tables before it
+func joinConditions(ctx SQLContext, fks []foreignKey) []Suggestion {
+	joined := ctx.JoinTable
+	jref := refName(ctx, joined)
+
+	var suggestions []Suggestion
+	seen := make(map[string]bool)
+	for _, t := range ctx.Tables {
+		ref := refName(ctx, t)
+		if ref == jref {
+			continue
+		}
+		for _, fk := range fks {
+			var text string
+			switch {
+			case sameTable(fk.table, joined) && sameTable(fk.refTable, t):
+				text = joinCondition(jref, fk.cols, ref, fk.refCols)
+			case sameTable(fk.refTable, joined) && sameTable(fk.table, t):
+				text = joinCondition(jref, fk.refCols, ref, fk.cols)
+			default:
+				continue
+			}
+			if seen[text] {
+				continue
+			}
+			seen[text] = true
+			suggestions = append(suggestions, Suggestion{Text: text, Type: SuggestJoin, Detail: fk.name})
+		}
+	}
+	return suggestions
+}
+
+// foreignKeys collects complete foreign keys, ordered by table for stable suggestions
+func foreignKeys(constraints map[string][]db.Constraint) []foreignKey {
+	names := make([]string, 0, len(constraints))
+	for name := range constraints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fks []foreignKey
+	for _, table := range names {
+		for _, c := range constraints[table] {
+			if c.Type != "FOREIGN KEY" || c.RefTable == "" || len(c.Columns) == 0 || len(c.Columns) != len(c.RefColumns) {
+				continue
+			}
+			fks = append(fks, foreignKey{name: c.Name, table: table, cols: c.Columns, refTable: c.RefTable, refCols: c.RefColumns})
+		}
+	}
+	return fks
+}
+
+// joinCondition equates the paired columns of two tables
+func joinCondition(left string, leftCols []string, right string, rightCols []string) string {
+	parts := make([]string, len(leftCols))
+	for i := range leftCols {
+		parts[i] = left + "." + leftCols[i] + " = " + right + "." + rightCols[i]
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// refName returns how the query refers to a table: its alias if it has one
+func refName(ctx SQLContext, table string) string {
+	var aliases []string
+	for alias, t := range ctx.TableAliases {
+		if t == table {
+			aliases = append(aliases, alias)
+		}
+	}
+	if len(aliases) == 0 {
+		return table
+	}
+	sort.Strings(aliases)
+	return aliases[0]
+}
+
+// newAlias derives an alias from a table's initials (order_items -> oi) that
+// is not a keyword or already used in the query
+func newAlias(table string, taken map[string]bool) string {
+	var b strings.Builder
+	for _, part := range strings.Split(unqualified(table), "_") {
+		if part != "" {
+			b.WriteString(strings.ToLower(part[:1]))
+		}
+	}
+	base := b.String()
+	if base == "" {
+		base = "t"
+	}
+	alias := base
+	for n := 2; taken[alias] || isKeyword(strings.ToUpper(alias)); n++ {
+		alias = base + strconv.Itoa(n)
+	}
+	return alias
+}
+
+// schemaName returns the schema's spelling of a table, which may be schema-qualified
+func schemaName(tables []string, table string) string {
+	for _, t := range tables {
+		if strings.EqualFold(t, table) {
+			return t
+		}
+	}
+	for _, t := range tables {
+		if sameTable(t, table) {
+			return t
+		}
+	}
+	return table
+}
+
+// sameTable matches table names case-insensitively, with or without a schema prefix
+func sameTable(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	return a == b || strings.HasSuffix(a, "."+b) || strings.HasSuffix(b, "."+a)
+}
+
+func inQuery(tables []string, table string) bool {
+	for _, t := range tables {
+		if sameTable(t, table) {
+			return true
+		}
+	}
+	return false
+}
+
+func unqualified(table string) string {
+	return table[strings.LastIndex(table, ".")+1:]
+}
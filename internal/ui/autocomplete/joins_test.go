@@ -0,0 +1,58 @@
+package autocomplete
+
+import (
+	"testing"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+var joinSchema = map[string][]db.Constraint{
+	"orders":      {{Name: "orders_user_fk", Type: "FOREIGN KEY", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}}},
+	"order_items": {{Name: "items_order_fk", Type: "FOREIGN KEY", Columns: []string{"order_id"}, RefTable: "orders", RefColumns: []string{"id"}}},
+	"users":       {{Name: "users_pkey", Type: "PRIMARY KEY"}},
+}
+
+func joinTexts(sql string) []string {
+	ctx := ParseSQLContext(sql, len(sql))
+	word, _, _ := GetWordAtCursor(sql, len(sql))
+	var texts []string
+	for _, s := range GetSuggestions(ctx, []string{"users", "orders", "order_items"}, nil, joinSchema, nil, word) {
+		if s.Type == SuggestJoin {
+			texts = append(texts, s.Text)
+		}
+	}
+	return texts
+}
+
+func TestJoinClauseSuggestions(t *testing.T) {
+	got := joinTexts("SELECT * FROM users u JOIN ")
+	if len(got) != 1 || got[0] != "orders o ON o.user_id = u.id" {
+		t.Fatalf("after JOIN = %v", got)
+	}
+
+	// Both directions, skipping tables already joined, filtered by the typed word
+	got = joinTexts("SELECT * FROM users u JOIN orders o ON o.user_id = u.id JOIN ord")
+	if len(got) != 1 || got[0] != "order_items oi ON oi.order_id = o.id" {
+		t.Fatalf("second JOIN = %v", got)
+	}
+}
+
+func TestJoinConditionSuggestions(t *testing.T) {
+	got := joinTexts("SELECT * FROM orders o JOIN users u ON ")
+	if len(got) != 1 || got[0] != "u.id = o.user_id" {
+		t.Fatalf("after ON = %v", got)
+	}
+	if got := joinTexts("SELECT * FROM orders o JOIN users u ON u.id = o.user_id WHERE "); len(got) != 0 {
+		t.Fatalf("join suggestions outside the join: %v", got)
+	}
+}
+
+func TestNewAlias(t *testing.T) {
+	taken := map[string]bool{"o": true}
+	if got := newAlias("public.orders", taken); got != "o2" {
+		t.Fatalf("newAlias(orders) = %q, want o2", got)
+	}
+	if got := newAlias("order_notes", nil); got != "on2" {
+		t.Fatalf("newAlias(order_notes) = %q, want on2 since ON is a keyword", got)
+	}
+}
@@ -0,0 +1,197 @@
+package autocomplete
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// statementStarts are the words a statement can begin with in any dialect,
+// besides statementKeywords and the catalogs' statements
+var statementStarts = map[string]bool{
+	"WITH": true, "VALUES": true, "TABLE": true, "SET": true, "SHOW": true, "USE": true,
+	"EXPLAIN": true, "ANALYZE": true, "DESCRIBE": true, "DESC": true, "TRUNCATE": true,
+	"START": true, "END": true, "ABORT": true, "SAVEPOINT": true, "RELEASE": true,
+	"GRANT": true, "REVOKE": true, "CALL": true, "DO": true, "MERGE": true, "REPLACE": true,
+	"LOCK": true, "UNLOCK": true, "PREPARE": true, "EXECUTE": true, "DEALLOCATE": true,
+	"DECLARE": true, "FETCH": true, "CLOSE": true, "MOVE": true, "COMMENT": true,
+	"REFRESH": true, "CLUSTER": true, "CHECKPOINT": true, "RESET": true, "DISCARD": true,
+	"LISTEN": true, "NOTIFY": true, "UNLISTEN": true, "REINDEX": true, "COPY": true,
+	"VACUUM": true, "PRAGMA": true, "ATTACH": true, "DETACH": true, "RENAME": true,
+	"OPTIMIZE": true, "REPAIR": true, "CHECK": true, "FLUSH": true, "KILL": true,
+	"HANDLER": true, "LOAD": true, "INSTALL": true, "UNINSTALL": true, "BATCH": true,
+	"APPLY": true, "LIST": true, "ASSERT": true, "EXPORT": true, "IMPORT": true,
+}
+
+// afterCommaKeywords cannot directly follow a comma
+var afterCommaKeywords = map[string]bool{
+	"FROM": true, "WHERE": true, "GROUP": true, "ORDER": true, "HAVING": true,
+	"LIMIT": true, "OFFSET": true, "UNION": true, "JOIN": true, "ON": true, "SET": true,
+	"VALUES": true, "RETURNING": true,
+}
+
+// dollarTagPattern matches a PostgreSQL dollar quote opener: $$ or $tag$
+var dollarTagPattern = regexp.MustCompile(`^\$(?:[A-Za-z_]\w*)?\$`)
+
+// Lint finds the first problem that makes sql fail to parse: an unterminated
+// literal or comment, unbalanced parentheses, a stray comma or an unknown
+// statement. It returns nil when sql looks well-formed.
+func Lint(sql string, driver db.DriverType) *db.SyntaxError {
+	backslash := driver == db.MySQL || driver == db.BigQuery
+	end := len(strings.TrimRight(sql, " \t\r\n"))
+	var parens []int
+	stmtStart := true
+	prev := "" // Previous token, upper-cased words and punctuation as is
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+			continue
+		case strings.HasPrefix(sql[i:], "--") || (c == '#' && driver == db.MySQL):
+			if nl := strings.IndexByte(sql[i:], '\n'); nl >= 0 {
+				i += nl
+			} else {
+				i = len(sql)
+			}
+			continue
+		case strings.HasPrefix(sql[i:], "/*"):
+			closing := strings.Index(sql[i+2:], "*/")
+			if closing < 0 {
+				return lintError(i, "unterminated comment")
+			}
+			i += closing + 4
+			continue
+		}
+
+		token := string(c)
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			closing := closingQuote(sql, i, backslash && c != '`')
+			if closing < 0 {
+				return lintError(i, "unterminated "+quoteKind(c, driver))
+			}
+			i = closing + 1
+			token = "literal"
+		case (c == 'E' || c == 'e') && driver == db.Postgres && strings.HasPrefix(sql[i+1:], "'"):
+			// E'...' strings take backslash escapes
+			closing := closingQuote(sql, i+1, true)
+			if closing < 0 {
+				return lintError(i, "unterminated string")
+			}
+			i = closing + 1
+			token = "literal"
+		case c == '$' && driver == db.Postgres && dollarTagPattern.MatchString(sql[i:]):
+			tag := dollarTagPattern.FindString(sql[i:])
+			closing := strings.Index(sql[i+len(tag):], tag)
+			if closing < 0 {
+				return lintError(i, "unterminated dollar-quoted string")
+			}
+			i += len(tag) + closing + len(tag)
+			token = "literal"
+		case c == '(':
+			parens = append(parens, i)
+			i++
+		case c == ')':
+			if len(parens) == 0 {
+				return lintError(i, "unexpected )")
+			}
+			if prev == "," {
+				return lintError(i, "unexpected ) after ,")
+			}
+			parens = parens[:len(parens)-1]
+			i++
+		case c == ',':
+			if prev == "," || prev == "(" || stmtStart {
+				return lintError(i, "unexpected ,")
+			}
+			i++
+		case c == ';':
+			if len(parens) > 0 {
+				return lintError(parens[len(parens)-1], "unclosed (")
+			}
+			if prev == "," {
+				return lintError(i, "unexpected ; after ,")
+			}
+			stmtStart, prev = true, ""
+			i++
+			continue
+		case isValidIdentifierChar(rune(c)):
+			j := i
+			for j < len(sql) && isValidIdentifierChar(rune(sql[j])) {
+				j++
+			}
+			word := strings.ToUpper(sql[i:j])
+			// A last word may still be being typed
+			if stmtStart && j < end && !(c >= '0' && c <= '9') && !isStatementStart(word) {
+				return lintError(i, "unknown statement "+sql[i:j])
+			}
+			if prev == "," && afterCommaKeywords[word] {
+				return lintError(i, "unexpected "+word+" after ,")
+			}
+			i = j
+			token = word
+		default:
+			i++
+		}
+		stmtStart, prev = false, token
+	}
+
+	if len(parens) > 0 {
+		return lintError(parens[len(parens)-1], "unclosed (")
+	}
+	return nil
+}
+
+func lintError(offset int, message string) *db.SyntaxError {
+	return &db.SyntaxError{Offset: offset, Message: message}
+}
+
+// closingQuote returns the index of the quote closing the one at open, or -1.
+// A doubled quote is an escaped one.
+func closingQuote(sql string, open int, backslash bool) int {
+	quote := sql[open]
+	for i := open + 1; i < len(sql); i++ {
+		switch {
+		case backslash && sql[i] == '\\':
+			i++
+		case sql[i] == quote:
+			if i+1 < len(sql) && sql[i+1] == quote {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// quoteKind names what a quote character delimits in a dialect
+func quoteKind(quote byte, driver db.DriverType) string {
+	if quote == '\'' || (quote == '"' && (driver == db.MySQL || driver == db.BigQuery)) {
+		return "string"
+	}
+	return "quoted identifier"
+}
+
+// isStatementStart reports whether word can begin a statement
+func isStatementStart(word string) bool {
+	if statementStarts[word] {
+		return true
+	}
+	for _, kw := range statementKeywords {
+		if kw == word {
+			return true
+		}
+	}
+	for _, c := range dialectCatalogs {
+		for _, kw := range c.statements {
+			if kw == word {
+				return true
+			}
+		}
+	}
+	return false
+}
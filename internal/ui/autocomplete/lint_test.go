@@ -0,0 +1,45 @@
+package autocomplete
+
+import (
+	"testing"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		sql    string
+		driver db.DriverType
+		offset int // -1 for no error
+	}{
+		{"SELECT * FROM users WHERE name = 'ann'", db.Postgres, -1},
+		{"SELECT * FROM users WHERE name = 'it''s'", db.Postgres, -1},
+		{"SELECT 'a;b' -- it's fine\nFROM t", db.SQLite, -1},
+		{"SELECT $$it's$$, E'it\\'s', $1", db.Postgres, -1},
+		{"SELECT 'it\\'s'", db.MySQL, -1},
+		{"SELECT COUNT(*) FROM t; INSERT INTO t (a, b) VALUES (1, 2)", db.SQLite, -1},
+		{"SELEC", db.Postgres, -1}, // Still being typed
+		{"SELECT * FROM users WHERE name = 'ann", db.Postgres, 33},
+		{"SELECT 'it\\'s'", db.Postgres, 13},
+		{"SELECT \"name FROM t", db.Postgres, 7},
+		{"SELECT /* note", db.Postgres, 7},
+		{"SELECT COUNT(* FROM t", db.Postgres, 12},
+		{"SELECT (1)) FROM t", db.Postgres, 10},
+		{"SELECT a, FROM t", db.Postgres, 10},
+		{"SELECT a,, b FROM t", db.Postgres, 9},
+		{"INSERT INTO t (a, b,) VALUES (1, 2)", db.Postgres, 20},
+		{"SELEC * FROM t", db.Postgres, 0},
+		{"SELECT 1;\nUPDTE t SET a = 1", db.MySQL, 10},
+	}
+	for _, tt := range tests {
+		err := Lint(tt.sql, tt.driver)
+		switch {
+		case tt.offset < 0 && err != nil:
+			t.Errorf("Lint(%q) = %q at %d, want no error", tt.sql, err.Message, err.Offset)
+		case tt.offset >= 0 && err == nil:
+			t.Errorf("Lint(%q) = nil, want an error at %d", tt.sql, tt.offset)
+		case tt.offset >= 0 && err.Offset != tt.offset:
+			t.Errorf("Lint(%q) = %q at %d, want offset %d", tt.sql, err.Message, err.Offset, tt.offset)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package autocomplete
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// Relation is a CTE or derived table defined inside the query
+type Relation struct {
+	Columns []string // Projected column names that could be determined
+	Star    []string // Tables whose columns a * in the projection brings in
+	CTE     bool     // Defined in a WITH clause, so usable as a table name
+}
+
+var (
+	withPattern      = regexp.MustCompile(`(?is)\bWITH\s+(?:RECURSIVE\s+)?`)
+	ctePattern       = regexp.MustCompile(`(?is)^\s*([A-Za-z_]\w*)\s*(?:\(([^()]*)\))?\s+AS\s+(?:NOT\s+)?(?:MATERIALIZED\s+)?\(`)
+	aliasPattern     = regexp.MustCompile(`(?is)^\s*(?:AS\s+)?([A-Za-z_]\w*)`)
+	asAliasPattern   = regexp.MustCompile(`(?is)\s+AS\s+["` + "`" + `]?(\w+)["` + "`" + `]?$`)
+	implicitPattern  = regexp.MustCompile(`[\w)]\s+(\w+)$`)
+	columnRefPattern = regexp.MustCompile(`^[\w."` + "`" + `*]+$`)
+)
+
+// extractRelations finds the CTEs and the aliased subqueries after FROM or
+// JOIN in sql, keyed by name. It also returns sql with the bodies of those
+// that are complete blanked out, leaving the tables of the outer query.
+func extractRelations(sql string) (map[string]Relation, string) {
+	masked := maskLiterals(sql)
+	relations := make(map[string]Relation)
+	outer := []byte(sql)
+	blank := func(open, end int) {
+		if end < len(outer) {
+			for i := open + 1; i < end; i++ {
+				outer[i] = ' '
+			}
+		}
+	}
+
+	// WITH a AS (...), b (x, y) AS (...)
+	for _, loc := range withPattern.FindAllStringIndex(masked, -1) {
+		pos := loc[1]
+		for {
+			m := ctePattern.FindStringSubmatchIndex(masked[pos:])
+			if m == nil {
+				break
+			}
+			name := masked[pos+m[2] : pos+m[3]]
+			open := pos + m[1] - 1
+			end := closingParen(masked, open)
+			rel := projection(masked[open+1 : end])
+			rel.CTE = true
+			if m[4] >= 0 {
+				rel.Columns, rel.Star = splitColumnList(masked[pos+m[4]:pos+m[5]]), nil
+			}
+			relations[name] = rel
+			blank(open, end)
+			if end >= len(masked) {
+				break
+			}
+			rest := strings.TrimLeft(masked[end+1:], " \t\r\n")
+			if !strings.HasPrefix(rest, ",") {
+				break
+			}
+			pos = len(masked) - len(rest) + 1
+		}
+	}
+
+	// FROM (SELECT ...) AS alias
+	tokens := []string{}
+	for i := 0; i < len(masked); i++ {
+		c := masked[i]
+		if isValidIdentifierChar(rune(c)) {
+			j := i
+			for j < len(masked) && isValidIdentifierChar(rune(masked[j])) {
+				j++
+			}
+			tokens = append(tokens, strings.ToUpper(masked[i:j]))
+			i = j - 1
+			continue
+		}
+		if c != '(' || len(tokens) == 0 || (tokens[len(tokens)-1] != "FROM" && tokens[len(tokens)-1] != "JOIN") {
+			continue
+		}
+		end := closingParen(masked, i)
+		body := masked[i+1 : end]
+		if !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(body)), "SELECT") || end >= len(masked) {
+			continue
+		}
+		if m := aliasPattern.FindStringSubmatch(masked[end+1:]); m != nil && !isKeyword(strings.ToUpper(m[1])) {
+			relations[m[1]] = projection(body)
+			blank(i, end)
+		}
+	}
+	return relations, string(outer)
+}
+
+// projection works out the column names a SELECT produces
+func projection(body string) Relation {
+	var rel Relation
+	trimmed := strings.TrimSpace(body)
+	upper := strings.ToUpper(trimmed)
+	if !strings.HasPrefix(upper, "SELECT") {
+		return rel
+	}
+	list := trimmed[len("SELECT"):]
+	if end := topLevelKeyword(list, "FROM"); end >= 0 {
+		list = list[:end]
+	}
+	list = strings.TrimSpace(list)
+	if strings.HasPrefix(strings.ToUpper(list), "DISTINCT ") {
+		list = list[len("DISTINCT "):]
+	}
+
+	tables, aliases := extractTables(trimmed)
+	for _, item := range splitTopLevel(list) {
+		item = strings.TrimSpace(item)
+		switch {
+		case asAliasPattern.MatchString(item):
+			rel.Columns = append(rel.Columns, asAliasPattern.FindStringSubmatch(item)[1])
+		case columnRefPattern.MatchString(item):
+			qualifier, name := "", item
+			if dot := strings.LastIndex(item, "."); dot >= 0 {
+				qualifier, name = item[:dot], item[dot+1:]
+			}
+			name = strings.Trim(name, "\"`")
+			if name != "*" {
+				rel.Columns = append(rel.Columns, name)
+			} else if qualifier == "" {
+				rel.Star = append(rel.Star, tables...)
+			} else if table, ok := aliases[qualifier]; ok {
+				rel.Star = append(rel.Star, table)
+			} else {
+				rel.Star = append(rel.Star, qualifier)
+			}
+		case implicitPattern.MatchString(item):
+			if alias := implicitPattern.FindStringSubmatch(item)[1]; !isKeyword(strings.ToUpper(alias)) && !clauseWords[strings.ToUpper(alias)] {
+				rel.Columns = append(rel.Columns, alias)
+			}
+		}
+	}
+	return rel
+}
+
+// withRelations adds the query's CTEs and derived tables to the schema's
+// columns, expanding * projections from the tables they select from
+func withRelations(relations map[string]Relation, columns map[string][]db.Column) map[string][]db.Column {
+	if len(relations) == 0 {
+		return columns
+	}
+	merged := make(map[string][]db.Column, len(columns)+len(relations))
+	for table, cols := range columns {
+		merged[table] = cols
+	}
+	for name, rel := range relations {
+		var cols []db.Column
+		for _, col := range rel.Columns {
+			cols = append(cols, db.Column{Name: col})
+		}
+		for _, table := range rel.Star {
+			if starCols, ok := findTableColumns(table, columns); ok {
+				cols = append(cols, starCols...)
+			} else if inner, ok := relations[table]; ok && table != name {
+				for _, col := range inner.Columns {
+					cols = append(cols, db.Column{Name: col})
+				}
+			}
+		}
+		merged[name] = cols
+	}
+	return merged
+}
+
+// addRelations registers derived tables in ctx.Tables; extractTables only
+// sees their alias after a blanked-out subquery
+func addRelations(ctx *SQLContext) {
+	names := make([]string, 0, len(ctx.Relations))
+	for name := range ctx.Relations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ctx.Relations[name].CTE {
+			continue
+		}
+		if !inQuery(ctx.Tables, name) {
+			ctx.Tables = append(ctx.Tables, name)
+		}
+	}
+}
+
+// cteNames returns the query's CTE names, which can be selected from like tables
+func cteNames(relations map[string]Relation) []string {
+	var names []string
+	for name, rel := range relations {
+		if rel.CTE {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// maskLiterals blanks out quoted strings so their contents are not parsed
+func maskLiterals(sql string) string {
+	b := []byte(sql)
+	inQuote := false
+	for i, c := range b {
+		if c == '\'' {
+			inQuote = !inQuote
+		} else if inQuote {
+			b[i] = ' '
+		}
+	}
+	return string(b)
+}
+
+// closingParen returns the index of the parenthesis closing the one at open,
+// or len(s) if it is not closed yet
+func closingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+// topLevelKeyword returns the index of keyword outside parentheses in s, or -1
+func topLevelKeyword(s, keyword string) int {
+	upper := strings.ToUpper(s)
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		default:
+			if depth == 0 && strings.HasPrefix(upper[i:], keyword) &&
+				(i == 0 || !isValidIdentifierChar(rune(s[i-1]))) &&
+				(i+len(keyword) == len(s) || !isValidIdentifierChar(rune(s[i+len(keyword)]))) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits s on commas outside parentheses
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// splitColumnList parses "a, b" from a CTE's explicit column list
+func splitColumnList(list string) []string {
+	var cols []string
+	for _, col := range strings.Split(list, ",") {
+		if col = strings.Trim(strings.TrimSpace(col), "\"`"); col != "" {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
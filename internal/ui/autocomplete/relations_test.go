@@ -0,0 +1,59 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+var relationSchema = map[string][]db.Column{
+	"users":  {{Name: "id"}, {Name: "name"}},
+	"orders": {{Name: "id"}, {Name: "user_id"}, {Name: "total"}},
+}
+
+func columnTexts(sql string) []string {
+	ctx := ParseSQLContext(sql, len(sql))
+	word, _, _ := GetWordAtCursor(sql, len(sql))
+	var texts []string
+	for _, s := range GetSuggestions(ctx, []string{"users", "orders"}, relationSchema, nil, nil, word) {
+		if s.Type == SuggestColumn || s.Type == SuggestTable {
+			texts = append(texts, s.Text)
+		}
+	}
+	return texts
+}
+
+func TestProjection(t *testing.T) {
+	rel := projection("SELECT u.id, name AS who, COUNT(*) n, SUM(o.total) + 1, o.* FROM users u JOIN orders o ON o.user_id = u.id")
+	if want := []string{"id", "who", "n"}; !reflect.DeepEqual(rel.Columns, want) {
+		t.Fatalf("columns = %v, want %v", rel.Columns, want)
+	}
+	if want := []string{"orders"}; !reflect.DeepEqual(rel.Star, want) {
+		t.Fatalf("star = %v, want %v", rel.Star, want)
+	}
+}
+
+func TestCTEColumns(t *testing.T) {
+	sql := "WITH big AS (SELECT user_id, SUM(total) AS spent FROM orders GROUP BY user_id), " +
+		"named (uid, label) AS (SELECT id, name FROM users) SELECT * FROM big b WHERE b."
+	if got, want := columnTexts(sql), []string{"user_id", "spent"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("big columns = %v, want %v", got, want)
+	}
+
+	sql = "WITH big AS (SELECT user_id FROM orders), named (uid, label) AS (SELECT id, name FROM users) SELECT * FROM na"
+	if got := columnTexts(sql); len(got) == 0 || got[0] != "named" {
+		t.Fatalf("FROM suggestions = %v, want the CTE first", got)
+	}
+	sql = "WITH named (uid, label) AS (SELECT id, name FROM users) SELECT * FROM named WHERE "
+	if got := columnTexts(sql); !reflect.DeepEqual(got[:2], []string{"uid", "label"}) {
+		t.Fatalf("WHERE columns = %v", got)
+	}
+}
+
+func TestDerivedTableColumns(t *testing.T) {
+	sql := "SELECT s.* FROM (SELECT o.*, 'a)b' AS tag FROM orders o) AS s WHERE s."
+	if got, want := columnTexts(sql), []string{"tag", "id", "user_id", "total"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("derived columns = %v, want %v", got, want)
+	}
+}
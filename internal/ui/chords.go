@@ -0,0 +1,185 @@
+// internal/ui/chords.go
+// Multi-key chord bindings ("g g", "space e") resolved before normal key dispatch.
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/config"
+)
+
+// chordTimeout is how long a partial chord waits for its next key
+const chordTimeout = 800 * time.Millisecond
+
+// keyToken names a key press the way chords are written in the config
+func keyToken(msg tea.KeyMsg) string {
+	if msg.Type == tea.KeySpace {
+		return "space"
+	}
+	return msg.String()
+}
+
+// keyTokens names a sequence of key presses
+func keyTokens(msgs []tea.KeyMsg) []string {
+	tokens := make([]string, len(msgs))
+	for i, msg := range msgs {
+		tokens[i] = keyToken(msg)
+	}
+	return tokens
+}
+
+// normalizeChord collapses the whitespace between chord keys
+func normalizeChord(k string) string {
+	return strings.Join(strings.Fields(k), " ")
+}
+
+// chordMsg is the synthetic key dispatched once a chord completes; its
+// String() is the normalized chord so matchKey compares it like any key
+func chordMsg(seq []string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(strings.Join(seq, " "))}
+}
+
+// chordBindings returns every bound key sequence with more than one key
+func chordBindings(keys *config.KeyMap) [][]string {
+	var chords [][]string
+	for _, a := range keybindActions() {
+		for _, k := range a.keys(keys) {
+			if seq := strings.Fields(k); len(seq) > 1 {
+				chords = append(chords, seq)
+			}
+		}
+	}
+	return chords
+}
+
+// chordStatus reports whether seq is a proper prefix of a chord and whether it is a whole chord
+func chordStatus(keys *config.KeyMap, seq []string) (prefix, complete bool) {
+	for _, chord := range chordBindings(keys) {
+		if len(seq) > len(chord) {
+			continue
+		}
+		same := true
+		for i := range seq {
+			if seq[i] != chord[i] {
+				same = false
+				break
+			}
+		}
+		if !same {
+			continue
+		}
+		if len(seq) == len(chord) {
+			complete = true
+		} else {
+			prefix = true
+		}
+	}
+	return prefix, complete
+}
+
+// chordBoundHere reports whether a completed chord is bound to an action of the current context
+func (m Model) chordBoundHere(seq string) bool {
+	ctx := m.getHelpContext()
+	for _, b := range allHelpBindings(m.config.Keys) {
+		if !b.appliesTo(ctx) {
+			continue
+		}
+		for _, k := range b.Keys {
+			if normalizeChord(k) == seq {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// textInputPopups are the popups whose keys go to a text input or form
+var textInputPopups = map[string]bool{
+	"attach": true, "schema": true, "file": true, "export": true, "import": true, "generate": true,
+	"table action": true, "copy table": true, "query builder": true, "assistant": true,
+	"keybind": true, "templateEditor": true,
+}
+
+// chordsEnabled reports whether msg may start a chord. Text inputs keep every
+// key, and insert mode keeps printable keys so typing is never delayed.
+func (m Model) chordsEnabled(msg tea.KeyMsg) bool {
+	if m.appState == StateSelectingProfile || textInputPopups[m.popupStack.TopName()] ||
+		m.searching || m.tableFilterActive || m.helpFilterActive || m.browseFilterActive || m.snapshotNaming {
+		return false
+	}
+	if m.mode == InsertMode && !m.hasOpenPopup() && (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) {
+		return false
+	}
+	return true
+}
+
+// dispatchChord runs a completed chord, or reports one bound only in other contexts
+func (m Model) dispatchChord(seq []string) (Model, tea.Cmd) {
+	if chord := strings.Join(seq, " "); len(seq) > 1 && !m.chordBoundHere(chord) {
+		m.errorMsg = chord + " is not bound here"
+		return m, nil
+	}
+	return m.handleKey(chordMsg(seq))
+}
+
+// handleChordKey collects chord prefixes and dispatches completed chords.
+// Keys that cannot continue a chord flush the pending keys one by one first.
+func (m Model) handleChordKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if len(m.pendingKeys) == 0 && !m.chordsEnabled(msg) {
+		return m.handleKey(msg)
+	}
+
+	pending := append(append([]tea.KeyMsg{}, m.pendingKeys...), msg)
+	seq := keyTokens(pending)
+	prefix, complete := chordStatus(&m.config.Keys, seq)
+	switch {
+	case prefix:
+		// Wait for the next key, even if seq is also a shorter complete chord
+		m.pendingKeys = pending
+		m.chordID++
+		id := m.chordID
+		return m, tea.Tick(chordTimeout, func(time.Time) tea.Msg {
+			return ChordTimeoutMsg{ID: id}
+		})
+	case complete:
+		m.pendingKeys = nil
+		return m.dispatchChord(seq)
+	}
+
+	if len(m.pendingKeys) == 0 {
+		return m.handleKey(msg)
+	}
+	m, flushed := m.flushPendingKeys()
+	// The breaking key may itself start a new chord
+	m, cmd := m.handleChordKey(msg)
+	return m, tea.Batch(flushed, cmd)
+}
+
+// handleChordTimeout flushes a partial chord that received no further key
+func (m Model) handleChordTimeout(msg ChordTimeoutMsg) (Model, tea.Cmd) {
+	if msg.ID != m.chordID || len(m.pendingKeys) == 0 {
+		return m, nil
+	}
+	seq := keyTokens(m.pendingKeys)
+	if _, complete := chordStatus(&m.config.Keys, seq); complete && len(seq) > 1 {
+		m.pendingKeys = nil
+		return m.dispatchChord(seq)
+	}
+	return m.flushPendingKeys()
+}
+
+// flushPendingKeys dispatches pending chord keys as ordinary single keys
+func (m Model) flushPendingKeys() (Model, tea.Cmd) {
+	pending := m.pendingKeys
+	m.pendingKeys = nil
+	var cmds []tea.Cmd
+	for _, k := range pending {
+		var cmd tea.Cmd
+		m, cmd = m.handleKey(k)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
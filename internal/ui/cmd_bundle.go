@@ -0,0 +1,162 @@
+// internal/ui/cmd_bundle.go
+// Exports query + results + provenance metadata as a directory or zip bundle.
+package ui
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// bundleMetadata is written to metadata.json
+type bundleMetadata struct {
+	Profile       string    `json:"profile"`
+	Driver        string    `json:"driver"`
+	Database      string    `json:"database"`
+	ServerVersion string    `json:"server_version,omitempty"`
+	ExecutedAt    time.Time `json:"executed_at"`
+	ExportedAt    time.Time `json:"exported_at"`
+	DurationMs    int64     `json:"duration_ms"`
+	RowCount      int       `json:"row_count"`
+}
+
+// isBundlePath reports whether an export target asks for a bundle:
+// a .zip file or a directory (trailing slash)
+func isBundlePath(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".zip") ||
+		strings.HasSuffix(path, "/") || strings.HasSuffix(path, string(filepath.Separator))
+}
+
+// exportBundleCmd writes query.sql, results.csv and metadata.json for the results popup
+func (m Model) exportBundleCmd(target string) tea.Cmd {
+	if m.popupResult == nil || m.popupEntry == nil {
+		return func() tea.Msg {
+			return ExportCompleteMsg{Err: errors.New("no result to bundle")}
+		}
+	}
+
+	entry := *m.popupEntry
+	result := m.popupResult
+	driver := m.driver
+	meta := bundleMetadata{
+		ExecutedAt: entry.ExecutedAt,
+		DurationMs: entry.DurationMs,
+		RowCount:   result.RowCount,
+	}
+	if m.profile != nil {
+		meta.Profile = m.profile.Name
+		meta.Driver = m.profile.Type
+		meta.Database = m.profile.Database
+	}
+
+	return func() tea.Msg {
+		if driver != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			meta.ServerVersion = db.ServerVersion(ctx, driver)
+			cancel()
+		}
+		meta.ExportedAt = time.Now()
+
+		files, err := bundleFiles(entry.Query, result, meta)
+		if err != nil {
+			return ExportCompleteMsg{Err: err}
+		}
+
+		exportPath := target
+		if !filepath.IsAbs(exportPath) {
+			cwd, err := os.Getwd()
+			if err != nil {
+				cwd = "."
+			}
+			exportPath = filepath.Join(cwd, target)
+		}
+
+		if strings.HasSuffix(strings.ToLower(exportPath), ".zip") {
+			err = writeZipBundle(exportPath, files)
+		} else {
+			err = writeDirBundle(exportPath, files)
+		}
+		if err != nil {
+			return ExportCompleteMsg{Err: err}
+		}
+		return ExportCompleteMsg{Path: exportPath}
+	}
+}
+
+// bundleFile is a named file inside a bundle
+type bundleFile struct {
+	Name string
+	Data []byte
+}
+
+// bundleFiles renders the bundle contents in a stable order
+func bundleFiles(query string, result *db.QueryResult, meta bundleMetadata) ([]bundleFile, error) {
+	var csvBuf bytes.Buffer
+	w := csv.NewWriter(&csvBuf)
+	if err := w.Write(result.Columns); err != nil {
+		return nil, err
+	}
+	if err := result.EachRow(w.Write); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	metaJSON, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []bundleFile{
+		{Name: "query.sql", Data: []byte(strings.TrimSpace(query) + "\n")},
+		{Name: "results.csv", Data: csvBuf.Bytes()},
+		{Name: "metadata.json", Data: append(metaJSON, '\n')},
+	}, nil
+}
+
+// writeDirBundle writes bundle files into a directory, creating it if needed
+func writeDirBundle(dir string, files []bundleFile) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f.Name), f.Data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeZipBundle writes bundle files into a zip archive
+func writeZipBundle(path string, files []bundleFile) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	for _, f := range files {
+		w, err := zw.Create(f.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(f.Data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
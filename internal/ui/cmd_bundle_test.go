@@ -0,0 +1,59 @@
+// internal/ui/cmd_bundle_test.go
+package ui
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+func TestBundleFiles(t *testing.T) {
+	result := &db.QueryResult{
+		Columns:  []string{"id", "name"},
+		Rows:     [][]string{{"1", "alice"}, {"2", "bob, jr"}},
+		RowCount: 2,
+	}
+	meta := bundleMetadata{
+		Profile:    "local",
+		Driver:     "postgres",
+		Database:   "app",
+		ExecutedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		DurationMs: 42,
+		RowCount:   2,
+	}
+
+	files, err := bundleFiles("  SELECT * FROM users \n", result, meta)
+	if err != nil {
+		t.Fatalf("bundleFiles: %v", err)
+	}
+
+	names := []string{"query.sql", "results.csv", "metadata.json"}
+	if len(files) != len(names) {
+		t.Fatalf("got %d files, want %d", len(files), len(names))
+	}
+	for i, name := range names {
+		if files[i].Name != name {
+			t.Errorf("files[%d].Name = %s, want %s", i, files[i].Name, name)
+		}
+	}
+
+	if got := string(files[0].Data); got != "SELECT * FROM users\n" {
+		t.Errorf("query.sql = %q", got)
+	}
+	if got, want := string(files[1].Data), "id,name\n1,alice\n2,\"bob, jr\"\n"; got != want {
+		t.Errorf("results.csv = %q, want %q", got, want)
+	}
+
+	var decoded bundleMetadata
+	if err := json.Unmarshal(files[2].Data, &decoded); err != nil {
+		t.Fatalf("metadata.json: %v", err)
+	}
+	if decoded.Profile != "local" || decoded.Driver != "postgres" || decoded.RowCount != 2 || decoded.DurationMs != 42 {
+		t.Errorf("metadata = %+v", decoded)
+	}
+	if !decoded.ExecutedAt.Equal(meta.ExecutedAt) {
+		t.Errorf("executed_at = %v, want %v", decoded.ExecutedAt, meta.ExecutedAt)
+	}
+}
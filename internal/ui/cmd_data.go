@@ -3,115 +3,369 @@ package ui
 import (
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/db"
 )
 
-func (m Model) exportTableCmd(tableName, filename string) tea.Cmd {
-	return func() tea.Msg {
+func (m Model) exportTableCmd(tableName string, filter exportFilter, filename string) tea.Cmd {
+	return runTransfer("Export", func(ctx context.Context, report transferReporter) tea.Msg {
 		if m.driver == nil {
 			return ExportTableCompleteMsg{Err: fmt.Errorf("no database connection")}
 		}
 
-		ctx := context.Background()
-		// Query all data from the table
-		query := fmt.Sprintf("SELECT * FROM %s", tableName)
-		result, err := m.driver.Execute(ctx, query)
+		// Query the rows of the table the filter keeps
+		query := exportQuery(tableName, filter)
+
+		// Drivers with a bulk copy stream CSV exports without a SELECT of every row
+		if copier, ok := m.driver.(db.Copier); ok {
+			if ext, _ := exportFormat(filename); ext != ".xlsx" && ext != ".parquet" {
+				return m.copyOutTable(ctx, copier, tableName, query, filename, report)
+			}
+		}
+
+		result, err := m.execute(ctx, query)
 		if err != nil {
 			return ExportTableCompleteMsg{Err: err, Filename: filename}
 		}
 
-		// Create CSV file
+		// Parquet files carry the column types of the table
+		var types map[string]string
+		if ext, _ := exportFormat(filename); ext == ".parquet" {
+			if cols, err := m.driver.GetColumns(ctx, tableName); err == nil {
+				types = map[string]string{}
+				for _, c := range cols {
+					types[c.Name] = c.Type
+				}
+			}
+		}
+
+		// Create the file; its extension picks CSV, .xlsx or .parquet, and .gz compresses
 		file, err := os.Create(filename)
 		if err != nil {
 			return ExportTableCompleteMsg{Err: err, Filename: filename}
 		}
 		defer file.Close()
 
-		writer := csv.NewWriter(file)
-		defer writer.Flush()
-
-		// Write header
-		if err := writer.Write(result.Columns); err != nil {
+		counter := &countingWriter{w: file}
+		writer, err := newRowWriter(counter, filename, ',', tableName, result.Columns, types)
+		if err != nil {
 			return ExportTableCompleteMsg{Err: err, Filename: filename}
 		}
 
 		// Write rows - result.Rows is [][]string
-		for _, row := range result.Rows {
+		for i, row := range result.Rows {
+			if err := ctx.Err(); err != nil {
+				return ExportTableCompleteMsg{Err: err, Filename: filename}
+			}
 			if err := writer.Write(row); err != nil {
 				return ExportTableCompleteMsg{Err: err, Filename: filename}
 			}
+			report(i+1, len(result.Rows), counter.n)
+		}
+		if err := writer.Close(); err != nil {
+			return ExportTableCompleteMsg{Err: err, Filename: filename}
 		}
 
 		return ExportTableCompleteMsg{Filename: filename, Rows: len(result.Rows)}
+	})
+}
+
+// bulkLoadStatement describes a bulk load for the audit log
+func bulkLoadStatement(dt db.DriverType, table string, columns []string) string {
+	if dt == db.MySQL {
+		return fmt.Sprintf("LOAD DATA LOCAL INFILE INTO TABLE %s (%s)", table, strings.Join(columns, ", "))
 	}
+	return fmt.Sprintf("COPY %s (%s) FROM STDIN", table, strings.Join(columns, ", "))
 }
 
-func (m Model) importTableCmd(tableName, filename string) tea.Cmd {
-	return func() tea.Msg {
+// copyOutTable exports the rows query selects from tableName to a CSV file
+// with the driver's bulk copy
+func (m Model) copyOutTable(ctx context.Context, copier db.Copier, tableName, query, filename string, report transferReporter) tea.Msg {
+	file, err := os.Create(filename)
+	if err != nil {
+		return ExportTableCompleteMsg{Err: err, Filename: filename}
+	}
+	defer file.Close()
+
+	counter := &countingWriter{w: file}
+	var writer rowWriter
+	rows := 0
+	start := time.Now()
+	err = copier.CopyOut(ctx, query, func(row []string) error {
+		if writer == nil { // The first row names the columns
+			var err error
+			writer, err = newRowWriter(counter, filename, ',', tableName, row, nil)
+			return err
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+		rows++
+		report(rows, 0, counter.n)
+		return nil
+	})
+	m.audit(m.profile, fmt.Sprintf("COPY (%s) TO STDOUT", query), start, nil, err)
+	if err == nil && writer != nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		return ExportTableCompleteMsg{Err: err, Filename: filename}
+	}
+	return ExportTableCompleteMsg{Filename: filename, Rows: rows}
+}
+
+// importErrorsShown caps the skipped rows listed after an import
+const importErrorsShown = 20
+
+// importBatchSizes are the rows per INSERT the import prompt cycles through
+var importBatchSizes = []int{1, 100, 500, 1000}
+
+// importOptions are the choices of the import prompt
+type importOptions struct {
+	DryRun      bool // Check every row but insert nothing
+	BatchSize   int  // Rows per INSERT
+	Transaction bool // One transaction for the whole file instead of committing per batch
+	ResumeRow   int  // First data row to import, 1-based; 0 imports every row
+}
+
+// importResume is where a failed import stopped
+type importResume struct {
+	Table string
+	File  string
+	Row   int
+}
+
+// importTableCmd inserts the rows of a CSV file whose header names columns
+// of tableName. Values are checked against the column types and the
+// configured NULL sentinels become NULL; rows that fail to convert are
+// skipped and reported by line. A failed batch stops the import, leaving
+// earlier batches committed unless the whole file runs in one transaction.
+// A dry run checks every row but inserts nothing. Drivers with a bulk load
+// (COPY, LOAD DATA) take the rows in one statement instead of batches when
+// the server allows it.
+func (m Model) importTableCmd(tableName, filename string, opts importOptions) tea.Cmd {
+	nulls := map[string]bool{}
+	for _, s := range m.config.NullSentinels() {
+		nulls[s] = true
+	}
+	op := "Import"
+	if opts.DryRun {
+		op = "Validate"
+	}
+	return runTransfer(op, func(ctx context.Context, report transferReporter) tea.Msg {
+		done := ImportTableCompleteMsg{Table: tableName, File: filename, DryRun: opts.DryRun}
+		fail := func(err error) tea.Msg {
+			done.Err = err
+			return done
+		}
 		if m.driver == nil {
-			return ImportTableCompleteMsg{Err: fmt.Errorf("no database connection")}
+			return fail(fmt.Errorf("no database connection"))
 		}
 
 		// Read CSV file
 		file, err := os.Open(filename)
 		if err != nil {
-			return ImportTableCompleteMsg{Err: err}
+			return fail(err)
 		}
 		defer file.Close()
 
 		reader := csv.NewReader(file)
-		records, err := reader.ReadAll()
-		if err != nil {
-			return ImportTableCompleteMsg{Err: err}
+		reader.FieldsPerRecord = -1
+		header, err := reader.Read()
+		if err == io.EOF {
+			return fail(fmt.Errorf("CSV file is empty or has no data rows"))
+		} else if err != nil {
+			return fail(err)
 		}
-
-		if len(records) < 2 {
-			return ImportTableCompleteMsg{Err: fmt.Errorf("CSV file is empty or has no data rows")}
+		var records [][]string
+		var lines []int
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return fail(err)
+			}
+			line, _ := reader.FieldPos(0)
+			records = append(records, record)
+			lines = append(lines, line)
+		}
+		first := max(opts.ResumeRow, 1) - 1
+		if first >= len(records) {
+			return fail(fmt.Errorf("CSV file is empty or has no data rows"))
 		}
 
-		// First row is header
-		columns := records[0]
-		dataRows := records[1:]
-
-		// Build INSERT statements
-		ctx := context.Background()
-		insertedRows := 0
+		// Match the header to the table's columns; without metadata values go in as text
+		dt := m.driver.Type()
+		tableCols, _ := m.driver.GetColumns(ctx, tableName)
+		columns := make([]db.Column, len(header))
+		names := make([]string, len(header))
+		for i, name := range header {
+			columns[i] = db.Column{Name: strings.TrimSpace(name)}
+			if len(tableCols) > 0 {
+				col, ok := findColumn(tableCols, columns[i].Name)
+				if !ok {
+					return fail(fmt.Errorf("%s has no column %s", tableName, columns[i].Name))
+				}
+				columns[i] = col
+			}
+			names[i] = columns[i].Name
+		}
 
-		for _, row := range dataRows {
-			// Build column list and values
-			placeholders := make([]string, len(columns))
-			for i := range columns {
-				placeholders[i] = "?"
+		// Convert every row first, keeping the data row number of each
+		type importRow struct {
+			n       int
+			literal []string
+			values  []any // nil for NULL, for a bulk copy
+		}
+		var rows []importRow
+		for i := first; i < len(records); i++ {
+			record := records[i]
+			var rowErr error
+			literal := make([]string, len(columns))
+			values := make([]any, len(columns))
+			if len(record) != len(columns) {
+				rowErr = fmt.Errorf("%d values for %d columns", len(record), len(columns))
+			}
+			for j := 0; rowErr == nil && j < len(columns); j++ {
+				value := "NULL"
+				if !nulls[record[j]] {
+					if value, rowErr = db.CoerceValue(dt, columns[j].Type, record[j]); rowErr != nil {
+						rowErr = fmt.Errorf("column %s: %w", columns[j].Name, rowErr)
+					}
+				}
+				literal[j] = sqlValue(dt, value, columns[j])
+				if value != "NULL" {
+					values[j] = value
+				}
 			}
+			if rowErr != nil {
+				done.Errors = append(done.Errors, fmt.Sprintf("line %d: %v", lines[i], rowErr))
+				continue
+			}
+			rows = append(rows, importRow{n: i + 1, literal: literal, values: values})
+		}
+		if opts.DryRun {
+			done.Rows = len(rows)
+			return done
+		}
+		if opts.Transaction && len(done.Errors) > 0 {
+			return fail(fmt.Errorf("%d rows failed to convert, nothing was imported", len(done.Errors)))
+		}
+		// Every path below writes like an INSERT, including those that bypass execute
+		if err := m.writable(m.profile, "INSERT INTO "+tableName); err != nil {
+			return fail(err)
+		}
 
-			query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-				tableName,
-				strings.Join(columns, ", "),
-				strings.Join(placeholders, ", "))
+		// A bulk load takes every row in one statement; servers that refuse it
+		// get the batched INSERTs below
+		if loader, ok := m.driver.(db.BulkLoader); ok {
+			values := make([][]any, len(rows))
+			for i, r := range rows {
+				values[i] = r.values
+			}
+			report(0, len(values), 0)
+			start := time.Now()
+			loaded, err := loader.BulkLoad(ctx, tableName, names, values)
+			if !errors.Is(err, db.ErrBulkLoadUnavailable) {
+				m.audit(m.profile, bulkLoadStatement(dt, tableName, names), start, nil, err)
+				if err != nil {
+					if first > 0 {
+						done.ResumeRow = first + 1
+					}
+					return fail(fmt.Errorf("bulk load rolled back: %w", err))
+				}
+				done.Rows = int(loaded)
+				return done
+			}
+		}
 
-			// Convert row to interface slice
-			values := make([]interface{}, len(row))
-			for i, v := range row {
-				if v == "" {
-					values[i] = nil
-				} else {
-					values[i] = v
+		size := max(opts.BatchSize, 1)
+		batches := make([][]importRow, 0, len(rows)/size+1)
+		for len(rows) > 0 {
+			n := min(size, len(rows))
+			batches = append(batches, rows[:n])
+			rows = rows[n:]
+		}
+		// insert runs one batch through exec, naming its rows when it fails
+		insert := func(batch []importRow, exec func(string) error) error {
+			tuples := make([][]string, len(batch))
+			for i, r := range batch {
+				tuples[i] = r.literal
+			}
+			if err := exec(batchInsert(dt, tableName, names, tuples)); err != nil {
+				if len(batch) == 1 {
+					return fmt.Errorf("row %d (line %d): %w", batch[0].n, lines[batch[0].n-1], err)
 				}
+				last := batch[len(batch)-1].n
+				return fmt.Errorf("rows %d-%d (lines %d-%d): %w", batch[0].n, last, lines[batch[0].n-1], lines[last-1], err)
 			}
+			return nil
+		}
 
-			// Execute insert (note: this is a simplified approach, proper implementation would use prepared statements)
-			_, err := m.driver.Execute(ctx, query)
+		if opts.Transaction {
+			tx, ok := m.driver.(db.Transactor)
+			if !ok {
+				return fail(fmt.Errorf("%s does not support transactions, commit per batch instead", dt))
+			}
+			err := tx.Transaction(ctx, func(exec func(string) error) error {
+				audited := func(stmt string) error {
+					if err := m.writable(m.profile, stmt); err != nil {
+						return err
+					}
+					start := time.Now()
+					err := exec(stmt)
+					m.audit(m.profile, stmt, start, nil, err)
+					return err
+				}
+				for i, batch := range batches {
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+					report(i*size, len(records)-first, 0)
+					if err := insert(batch, audited); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 			if err != nil {
-				// Continue with other rows
-				continue
+				// Nothing was committed, so a retry starts where this run did
+				if first > 0 {
+					done.ResumeRow = first + 1
+				}
+				return fail(fmt.Errorf("rolled back: %w", err))
 			}
-			insertedRows++
+			for _, batch := range batches {
+				done.Rows += len(batch)
+			}
+			return done
 		}
 
-		return ImportTableCompleteMsg{Rows: insertedRows}
-	}
+		exec := func(stmt string) error {
+			_, err := m.execute(ctx, stmt)
+			return err
+		}
+		for i, batch := range batches {
+			if err := ctx.Err(); err != nil {
+				done.ResumeRow = batch[0].n
+				return fail(err)
+			}
+			report(i*size, len(records)-first, 0)
+			if err := insert(batch, exec); err != nil {
+				done.ResumeRow = batch[0].n
+				return fail(err)
+			}
+			done.Rows += len(batch)
+		}
+		return done
+	})
 }
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
@@ -9,8 +10,37 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// Export targets besides file paths
+const (
+	exportClipboard = "clipboard:"
+	exportStdout    = "-" // Printed once the program exits
 )
 
+// resultCSV renders a result as comma separated values with a header row
+func resultCSV(result *db.QueryResult) (string, error) {
+	if result == nil {
+		return "", fmt.Errorf("no results to export")
+	}
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Write(result.Columns)
+	if err := result.EachRow(w.Write); err != nil {
+		return "", err
+	}
+	w.Flush()
+	return b.String(), w.Error()
+}
+
+// StdoutExport returns the results exported to "-", for printing after the
+// program exits
+func (m Model) StdoutExport() string {
+	return m.stdoutExport
+}
+
 // exportTableToPath exports all query results to a specified path
 func (m Model) exportTableToPath(filename string) tea.Cmd {
 	if m.popupResult == nil {
@@ -18,10 +48,9 @@ func (m Model) exportTableToPath(filename string) tea.Cmd {
 	}
 
 	// Capture result data for the closure
-	columns := m.popupResult.Columns
-	rows := m.popupResult.Rows
+	result := m.popupResult
 
-	return func() tea.Msg {
+	return runTransfer("Export", func(ctx context.Context, report transferReporter) tea.Msg {
 		// Expand path
 		exportPath := filename
 		if !filepath.IsAbs(exportPath) {
@@ -32,8 +61,8 @@ func (m Model) exportTableToPath(filename string) tea.Cmd {
 			exportPath = filepath.Join(cwd, filename)
 		}
 
-		// Ensure .csv extension
-		if !strings.HasSuffix(strings.ToLower(exportPath), ".csv") {
+		// Default to CSV unless the path names another format
+		if !hasExportExt(exportPath) {
 			exportPath += ".csv"
 		}
 
@@ -44,25 +73,35 @@ func (m Model) exportTableToPath(filename string) tea.Cmd {
 		}
 		defer f.Close()
 
-		// Write CSV with | separator
-		w := csv.NewWriter(f)
-		w.Comma = '|'
-		defer w.Flush()
-
-		// Write header
-		if err := w.Write(columns); err != nil {
+		// Write CSV with | separator, or an .xlsx workbook
+		counter := &countingWriter{w: f}
+		w, err := newRowWriter(counter, exportPath, '|', "Results", result.Columns, nil)
+		if err != nil {
 			return ExportCompleteMsg{Err: err}
 		}
 
-		// Write ALL rows
-		for _, row := range rows {
+		// Write ALL rows, spilled ones included
+		written := 0
+		err = result.EachRow(func(row []string) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			if err := w.Write(row); err != nil {
-				return ExportCompleteMsg{Err: err}
+				return err
 			}
+			written++
+			report(written, result.RowCount, counter.n)
+			return nil
+		})
+		if err != nil {
+			return ExportCompleteMsg{Err: err}
+		}
+		if err := w.Close(); err != nil {
+			return ExportCompleteMsg{Err: err}
 		}
 
 		return ExportCompleteMsg{Path: exportPath}
-	}
+	})
 }
 
 // copyRowAsJSON copies the currently highlighted row as JSON
@@ -80,7 +119,7 @@ func (m Model) copyRowAsJSON() tea.Cmd {
 		// Convert row data to map
 		rowMap := make(map[string]interface{})
 		for key, value := range highlightedRow.Data {
-			rowMap[key] = value
+			rowMap[key] = cellText(value)
 		}
 
 		// Convert to JSON
@@ -113,7 +152,7 @@ func (m Model) copyRowAsCSV() tea.Cmd {
 		row := make([]string, len(m.popupResult.Columns))
 		for i, col := range m.popupResult.Columns {
 			if val, ok := highlightedRow.Data[col]; ok {
-				row[i] = fmt.Sprintf("%v", val)
+				row[i] = cellText(val)
 			}
 		}
 
@@ -2,6 +2,8 @@ package ui
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/ui/autocomplete"
 )
 
 // loadHistoryCmd loads query history from SQLite
@@ -11,3 +13,31 @@ func (m Model) loadHistoryCmd() tea.Cmd {
 		return HistoryLoadedMsg{Entries: entries, Err: err}
 	}
 }
+
+// loadHistoryIndexCmd loads past queries and their run counts for autocomplete
+func (m Model) loadHistoryIndexCmd() tea.Cmd {
+	if m.historyStore == nil || m.profile == nil {
+		return nil
+	}
+	store, name := m.historyStore, m.profile.Name
+	return func() tea.Msg {
+		usage, err := store.Usage(name, 500)
+		if err != nil {
+			return HistoryIndexLoadedMsg{Err: err}
+		}
+		queries := make([]autocomplete.HistoryQuery, len(usage))
+		for i, u := range usage {
+			queries[i] = autocomplete.HistoryQuery{Query: u.Query, Count: u.Count}
+		}
+		return HistoryIndexLoadedMsg{Index: autocomplete.NewHistoryIndex(queries)}
+	}
+}
+
+// handleHistoryIndexLoaded stores past queries for autocomplete
+func (m Model) handleHistoryIndexLoaded(msg HistoryIndexLoadedMsg) (Model, tea.Cmd) {
+	// Autocomplete works without history, so a failed load is not reported
+	if msg.Err == nil {
+		m.historyIndex = msg.Index
+	}
+	return m, nil
+}
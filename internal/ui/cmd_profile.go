@@ -4,61 +4,37 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/connect"
 	"github.com/nhath/ezdb/internal/db"
 )
 
+// openProfile connects to profile, asking the server for read-only sessions
+// when the profile refuses writes so statements the UI cannot classify are
+// refused too
+func (m Model) openProfile(profile config.Profile) (db.Driver, error) {
+	profile.ReadOnly = m.config.ReadOnly(&profile)
+	return connect.Open(&profile)
+}
+
 // connectToProfileCmd connects to the selected profile
 func (m Model) connectToProfileCmd(profile *config.Profile) tea.Cmd {
 	return func() tea.Msg {
-		var driverType db.DriverType
-		switch profile.Type {
-		case "postgres":
-			driverType = db.Postgres
-		case "mysql":
-			driverType = db.MySQL
-		case "sqlite":
-			driverType = db.SQLite
-		default:
-			return ProfileConnectedMsg{Err: db.WrapConnectionError(nil)}
-		}
-
-		driver, err := db.NewDriver(driverType)
+		driver, err := m.openProfile(*profile)
 		if err != nil {
 			return ProfileConnectedMsg{Err: err}
 		}
 
-		// Use password from profile
-		password := profile.Password
-		if password == "" && profile.Type != "sqlite" {
-			// Fallback to keyring for existing profiles not yet migrated to config
-			keyringStore, err := config.NewKeyringStore()
-			if err == nil {
-				password, _ = keyringStore.GetPassword(profile.Name)
-			}
-		}
-
-		params := db.ConnectParams{
-			Host:     profile.Host,
-			Port:     profile.Port,
-			User:     profile.User,
-			Password: password,
-			Database: profile.Database,
-		}
-
-		if profile.SSHHost != "" {
-			params.SSHConfig = &db.SSHConfig{
-				Host:     profile.SSHHost,
-				Port:     profile.SSHPort,
-				User:     profile.SSHUser,
-				Password: profile.SSHPassword,
-				KeyPath:  profile.SSHKeyPath,
-			}
-		}
+		return ProfileConnectedMsg{Driver: driver}
+	}
+}
 
-		if err := driver.Connect(params); err != nil {
-			return ProfileConnectedMsg{Err: err}
+// testConnectionCmd connects to profile, SSH tunnel included, and disconnects again
+func testConnectionCmd(profile config.Profile) tea.Cmd {
+	return func() tea.Msg {
+		driver, err := connect.Open(&profile)
+		if err == nil {
+			driver.Close()
 		}
-
-		return ProfileConnectedMsg{Driver: driver}
+		return ConnectionTestedMsg{Name: profile.Name, Err: err}
 	}
 }
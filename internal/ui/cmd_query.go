@@ -7,15 +7,109 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/nhath/ezdb/internal/config"
 	"github.com/nhath/ezdb/internal/db"
 	"github.com/nhath/ezdb/internal/history"
 )
 
-// executeQueryCmd executes a query (or multiple queries split by ;) asynchronously
-func (m Model) executeQueryCmd(query string) tea.Cmd {
+// runQuery marks the model as loading and starts query with a context
+// that can be cancelled from the UI while it runs.
+func (m *Model) runQuery(query string) tea.Cmd {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	m.loading = true
+	m.lastRun = nil
+	m.cancelQuery = cancel
+	return m.executeQueryCmd(m.queryContext(ctx), cancel, query)
+}
+
+// queryContext hands the configured result memory cap and timing options to Execute
+func (m Model) queryContext(ctx context.Context) context.Context {
+	ctx = db.WithResultMemory(ctx, m.config.ResultMemory())
+	if m.config.PlanTiming {
+		ctx = db.WithPlanTiming(ctx)
+	}
+	return ctx
+}
+
+// confirmOrRun asks for confirmation of writes in strict mode, otherwise starts the query.
+// Drivers that can price queries confirm every query with a dry-run estimate.
+// The profile's query guards block queries or ask about them in any mode.
+func (m *Model) confirmOrRun(query string) tea.Cmd {
+	if m.writable(m.profile, query) != nil {
+		m.blockQuery(query, "the profile is read-only")
+		return nil
+	}
+	var guards config.QueryGuards
+	if m.profile != nil {
+		guards = m.profile.Guards
+	}
+	var warnings []string
+	for _, v := range checkGuards(query, guards) {
+		if v.policy == config.GuardBlock {
+			m.blockQuery(query, v.reason)
+			return nil
+		}
+		warnings = append(warnings, v.reason)
+	}
+
+	var writes []string
+	rowEstimator, canEstimateRows := m.driver.(db.RowEstimator)
+	if (guards.LargeWrite == config.GuardWarn || guards.LargeWrite == config.GuardBlock) && canEstimateRows {
+		writes = estimatedWrites(query)
+	}
+
+	estimator, canEstimate := m.driver.(db.CostEstimator)
+	strict := m.strictMode && (isModifyingQuery(query) || canEstimate)
+	if !strict && len(warnings) == 0 && len(writes) == 0 {
+		return m.runQuery(query)
+	}
+	m.confirming = true
+	m.pendingQuery = query
+	m.pendingCost = ""
+	m.pendingWarnings = warnings
+	m.pendingStrict = strict
+	m.estimatingRows = len(writes) > 0
+	if m.estimatingRows {
+		return estimateRowsCmd(rowEstimator, query, writes)
+	}
+	if strict && canEstimate {
+		m.pendingCost = "Estimating cost..."
+		return estimateCostCmd(estimator, query)
+	}
+	return nil
+}
+
+// estimateCostCmd dry-runs a query to estimate its cost
+func estimateCostCmd(estimator db.CostEstimator, query string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
+		summary, err := estimator.EstimateCost(ctx, query)
+		return CostEstimateMsg{Query: query, Summary: summary, Err: err}
+	}
+}
+
+// handleCostEstimate shows a cost estimate on the confirmation prompt it
+// was made for, or in the status bar
+func (m Model) handleCostEstimate(msg CostEstimateMsg) (Model, tea.Cmd) {
+	summary := msg.Summary
+	if msg.Err != nil {
+		summary = "Cost estimate failed: " + msg.Err.Error()
+	}
+	if m.confirming && msg.Query == m.pendingQuery {
+		m.pendingCost = summary
+	} else if msg.Err != nil {
+		m.errorMsg = summary
+	} else {
+		m.statusMsg = summary
+	}
+	return m, nil
+}
+
+// executeQueryCmd executes a query (or multiple queries split by ;) asynchronously
+func (m Model) executeQueryCmd(ctx context.Context, cancel context.CancelFunc, query string) tea.Cmd {
+	return func() tea.Msg {
+		defer cancel()
 
 		// Split by semicolon for multi-statement execution
 		statements := splitStatements(query)
@@ -34,50 +128,14 @@ func (m Model) executeQueryCmd(query string) tea.Cmd {
 			}
 
 			start := time.Now()
-			result, err := m.driver.Execute(ctx, stmt)
+			result, err := m.execute(ctx, stmt)
+			entry := m.recordHistory(stmt, start, result, err)
 			if err != nil {
-				// Save error to history
-				entry := &history.HistoryEntry{
-					ProfileName:  m.profile.Name,
-					Query:        stmt,
-					ExecutedAt:   time.Now(),
-					DurationMs:   time.Since(start).Milliseconds(),
-					RowCount:     0,
-					Status:       "error",
-					ErrorMessage: err.Error(),
-				}
-				m.historyStore.Add(entry)
-				return QueryResultMsg{Err: err, Entry: entry}
-			}
-
-			var previewBuilder strings.Builder
-			if len(result.Rows) > 0 {
-				previewBuilder.WriteString(strings.Join(result.Columns, " | "))
-				previewBuilder.WriteString("\n")
-				limit := m.config.HistoryPreviewRows
-				if len(result.Rows) < limit {
-					limit = len(result.Rows)
-				}
-				for i := 0; i < limit; i++ {
-					previewBuilder.WriteString(strings.Join(result.Rows[i], " | "))
-					previewBuilder.WriteString("\n")
-				}
-				if len(result.Rows) > m.config.HistoryPreviewRows {
-					previewBuilder.WriteString("...")
-				}
+				lastResult.Close() // Not shown, so its spill is never closed elsewhere
+				return QueryResultMsg{Err: err, Entry: entry, AllEntries: allEntries}
 			}
-
-			entry := &history.HistoryEntry{
-				ProfileName: m.profile.Name,
-				Query:       stmt,
-				ExecutedAt:  time.Now(),
-				DurationMs:  result.ExecTime.Milliseconds(),
-				RowCount:    result.RowCount,
-				Status:      "success",
-				Preview:     strings.TrimSpace(previewBuilder.String()),
-			}
-			m.historyStore.Add(entry)
 			allEntries = append(allEntries, entry)
+			lastResult.Close() // Only the last result is shown
 			lastResult = result
 			lastEntry = entry
 		}
@@ -87,16 +145,118 @@ func (m Model) executeQueryCmd(query string) tea.Cmd {
 	}
 }
 
-// splitStatements splits a query string by semicolons, respecting quotes
+// recordHistory saves the outcome of one executed statement to the history store
+func (m Model) recordHistory(stmt string, start time.Time, result *db.QueryResult, err error) *history.HistoryEntry {
+	return m.recordHistoryOn("", stmt, start, result, err)
+}
+
+// recordHistoryOn records a statement that ran on the profile named ranOn
+// instead of the connected one, which is "" for the connected one
+func (m Model) recordHistoryOn(ranOn, stmt string, start time.Time, result *db.QueryResult, err error) *history.HistoryEntry {
+	if err != nil {
+		detail := db.DescribeError(stmt, err)
+		entry := &history.HistoryEntry{
+			ProfileName:   m.profile.Name,
+			RanOn:         ranOn,
+			Query:         stmt,
+			ExecutedAt:    time.Now(),
+			DurationMs:    time.Since(start).Milliseconds(),
+			RowCount:      0,
+			Status:        "error",
+			ErrorMessage:  err.Error(),
+			ErrorCode:     detail.Code,
+			ErrorDetail:   detail.Detail,
+			ErrorHint:     detail.Hint,
+			ErrorPosition: detail.Position,
+		}
+		m.historyStore.Add(entry)
+		return entry
+	}
+
+	var previewBuilder strings.Builder
+	if len(result.Rows) > 0 {
+		previewBuilder.WriteString(strings.Join(result.Columns, " | "))
+		previewBuilder.WriteString("\n")
+		limit := m.config.HistoryPreviewRows
+		if len(result.Rows) < limit {
+			limit = len(result.Rows)
+		}
+		for i := 0; i < limit; i++ {
+			previewBuilder.WriteString(strings.Join(result.Rows[i], " | "))
+			previewBuilder.WriteString("\n")
+		}
+		if len(result.Rows) > m.config.HistoryPreviewRows {
+			previewBuilder.WriteString("...")
+		}
+	}
+
+	entry := &history.HistoryEntry{
+		ProfileName: m.profile.Name,
+		RanOn:       ranOn,
+		Query:       stmt,
+		ExecutedAt:  time.Now(),
+		DurationMs:  result.ExecTime.Milliseconds(),
+		RowCount:    result.RowCount,
+		Status:      "success",
+		Preview:     strings.TrimSpace(previewBuilder.String()),
+	}
+	// A spilled result is too big to keep and only partly in memory
+	if err := m.historyStore.Add(entry); err == nil && len(result.Rows) > m.config.HistoryPreviewRows && result.Spill == nil {
+		if maxBytes, budget := m.config.ResultLimits(m.profile); maxBytes > 0 {
+			stored := history.StoredResult{Columns: result.Columns, Rows: result.Rows}
+			m.historyStore.SaveResult(entry.ID, m.profile.Name, stored, maxBytes, budget)
+		}
+	}
+	return entry
+}
+
+// loadStoredResultCmd reads the full result kept with a history entry
+func (m Model) loadStoredResultCmd(entry history.HistoryEntry) tea.Cmd {
+	if m.historyStore == nil || entry.RowCount <= m.config.HistoryPreviewRows {
+		return nil
+	}
+	if maxBytes, _ := m.config.ResultLimits(m.profile); maxBytes == 0 {
+		return nil
+	}
+	store := m.historyStore
+	return func() tea.Msg {
+		stored, err := store.Result(entry.ID)
+		return StoredResultMsg{Entry: entry, Result: stored, Err: err}
+	}
+}
+
+// splitStatements splits a query string by semicolons, respecting quotes,
+// comments and Postgres dollar-quoted bodies. Leading comments are dropped
+// from each statement and comment-only statements are skipped.
 func splitStatements(query string) []string {
 	var statements []string
 	var current strings.Builder
 	inSingleQuote := false
 	inDoubleQuote := false
+	dollarTag := "" // Closing tag while inside $tag$...$tag$
+
+	flush := func() {
+		stmt := trimLeadingComments(current.String())
+		if stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
 
 	for i := 0; i < len(query); i++ {
 		c := query[i]
 
+		if dollarTag != "" {
+			if strings.HasPrefix(query[i:], dollarTag) {
+				current.WriteString(dollarTag)
+				i += len(dollarTag) - 1
+				dollarTag = ""
+				continue
+			}
+			current.WriteByte(c)
+			continue
+		}
+
 		// Handle escape sequences
 		if (inSingleQuote || inDoubleQuote) && c == '\\' && i+1 < len(query) {
 			current.WriteByte(c)
@@ -105,6 +265,38 @@ func splitStatements(query string) []string {
 			continue
 		}
 
+		if !inSingleQuote && !inDoubleQuote {
+			// Comments run to the end of the line or the closing */
+			if strings.HasPrefix(query[i:], "--") {
+				end := strings.IndexByte(query[i:], '\n')
+				if end < 0 {
+					end = len(query) - i
+				}
+				current.WriteString(query[i : i+end])
+				i += end - 1
+				continue
+			}
+			if strings.HasPrefix(query[i:], "/*") {
+				end := strings.Index(query[i+2:], "*/")
+				if end < 0 {
+					end = len(query) - i
+				} else {
+					end += 4
+				}
+				current.WriteString(query[i : i+end])
+				i += end - 1
+				continue
+			}
+			if c == '$' {
+				if tag := dollarQuoteTag(query[i:]); tag != "" {
+					current.WriteString(tag)
+					i += len(tag) - 1
+					dollarTag = tag
+					continue
+				}
+			}
+		}
+
 		// Toggle quote state
 		if c == '\'' && !inDoubleQuote {
 			inSingleQuote = !inSingleQuote
@@ -114,11 +306,7 @@ func splitStatements(query string) []string {
 
 		// Split on semicolon outside quotes
 		if c == ';' && !inSingleQuote && !inDoubleQuote {
-			stmt := strings.TrimSpace(current.String())
-			if stmt != "" {
-				statements = append(statements, stmt)
-			}
-			current.Reset()
+			flush()
 			continue
 		}
 
@@ -126,21 +314,90 @@ func splitStatements(query string) []string {
 	}
 
 	// Don't forget the last statement
-	stmt := strings.TrimSpace(current.String())
-	if stmt != "" {
-		statements = append(statements, stmt)
-	}
+	flush()
 
 	return statements
 }
 
+// dollarQuoteTag returns the $tag$ opening s, or "" if s does not start one
+func dollarQuoteTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == '$' {
+			return s[:i+1]
+		}
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 1 && c >= '0' && c <= '9') {
+			return ""
+		}
+	}
+	return ""
+}
+
+// trimLeadingComments strips whitespace and leading comments so a statement
+// starts with its keyword
+func trimLeadingComments(stmt string) string {
+	for {
+		stmt = strings.TrimSpace(stmt)
+		switch {
+		case strings.HasPrefix(stmt, "--"):
+			end := strings.IndexByte(stmt, '\n')
+			if end < 0 {
+				return ""
+			}
+			stmt = stmt[end+1:]
+		case strings.HasPrefix(stmt, "/*"):
+			end := strings.Index(stmt, "*/")
+			if end < 0 {
+				return ""
+			}
+			stmt = stmt[end+2:]
+		default:
+			return stmt
+		}
+	}
+}
+
+// trimTrailingComments strips trailing comments, whitespace and semicolons
+// so text appended to a statement is not swallowed by a line comment
+func trimTrailingComments(stmt string) string {
+	end := 0
+	for i := 0; i < len(stmt); i++ {
+		c := stmt[i]
+		switch {
+		case strings.HasPrefix(stmt[i:], "--"):
+			if nl := strings.IndexByte(stmt[i:], '\n'); nl >= 0 {
+				i += nl
+			} else {
+				i = len(stmt)
+			}
+		case strings.HasPrefix(stmt[i:], "/*"):
+			if e := strings.Index(stmt[i+2:], "*/"); e >= 0 {
+				i += e + 3
+			} else {
+				i = len(stmt)
+			}
+		case c == '\'' || c == '"' || c == '`':
+			for i++; i < len(stmt) && stmt[i] != c; i++ {
+				if stmt[i] == '\\' {
+					i++
+				}
+			}
+			end = min(i+1, len(stmt))
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == ';':
+		default:
+			end = i + 1
+		}
+	}
+	return stmt[:end]
+}
+
 // rerunQueryCmd re-runs a query from history
 func (m Model) rerunQueryCmd(entry *history.HistoryEntry) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		result, err := m.driver.Execute(ctx, entry.Query)
+		result, err := m.execute(m.queryContext(ctx), entry.Query)
 		if err != nil {
 			return RerunResultMsg{Err: err, Entry: entry}
 		}
@@ -0,0 +1,27 @@
+// internal/ui/cmd_query_test.go
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- create users; it's the first migration
+CREATE TABLE users (id INT, name TEXT DEFAULT 'a;b');
+/* seed; data */
+INSERT INTO users VALUES (1, 'O''Hara');
+CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END $body$ LANGUAGE plpgsql;
+SELECT $1; -- trailing comment
+-- only a comment;
+`
+	want := []string{
+		"CREATE TABLE users (id INT, name TEXT DEFAULT 'a;b')",
+		"INSERT INTO users VALUES (1, 'O''Hara')",
+		"CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END $body$ LANGUAGE plpgsql",
+		"SELECT $1",
+	}
+	if got := splitStatements(script); !reflect.DeepEqual(got, want) {
+		t.Errorf("splitStatements =\n%q\nwant\n%q", got, want)
+	}
+}
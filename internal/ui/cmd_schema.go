@@ -0,0 +1,24 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// loadFunctionsCmd lists the database's user-defined functions for autocomplete
+func (m Model) loadFunctionsCmd() tea.Cmd {
+	lister, ok := m.driver.(db.FunctionLister)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		functions, err := lister.GetFunctions(ctx)
+		return FunctionsLoadedMsg{Functions: functions, Err: err}
+	}
+}
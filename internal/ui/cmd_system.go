@@ -1,20 +1,83 @@
 package ui
 
 import (
-	"encoding/csv"
+	"encoding/base64"
+	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
-
-	"github.com/nhath/ezdb/internal/db"
 )
 
-// copyToClipboardCmd copies text to clipboard using pbcopy (macOS)
+// clipboardTools are the local clipboard commands tried in order
+var clipboardTools = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// localClipboard returns the first clipboard command installed, or nil
+func localClipboard() []string {
+	for _, tool := range clipboardTools {
+		if tool[0] == "wl-copy" && os.Getenv("WAYLAND_DISPLAY") == "" {
+			continue
+		}
+		if _, err := exec.LookPath(tool[0]); err == nil {
+			return tool
+		}
+	}
+	return nil
+}
+
+// overSSH reports whether ezdb runs in an SSH session, where local tools
+// would fill the remote host's clipboard
+func overSSH() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+// osc52 returns the escape sequence asking the terminal to set its
+// clipboard to text, wrapped for tmux or screen to pass it through
+func osc52(text string) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07"
+	switch {
+	case os.Getenv("TMUX") != "":
+		return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		return "\x1bP" + seq + "\x1b\\"
+	}
+	return seq
+}
+
+// copyToClipboardCmd copies text to the clipboard with a local tool such as
+// pbcopy or xclip, or through the terminal with OSC 52. The clipboard setting
+// "osc52" always uses the terminal and "local" never does; by default OSC 52
+// is used over SSH and when no tool is installed.
 func (m Model) copyToClipboardCmd(text string) tea.Cmd {
 	return func() tea.Msg {
-		cmd := exec.Command("pbcopy")
+		tool := localClipboard()
+		switch m.config.Clipboard {
+		case "osc52":
+			tool = nil
+		case "local":
+			if tool == nil {
+				tool = clipboardTools[0] // Fails with the tool's own error
+			}
+		default:
+			if overSSH() {
+				tool = nil
+			}
+		}
+		if tool == nil {
+			if _, err := os.Stdout.WriteString(osc52(text)); err != nil {
+				return ClipboardCopiedMsg{Err: err}
+			}
+			return ClipboardCopiedMsg{Text: text}
+		}
+
+		cmd := exec.Command(tool[0], tool[1:]...)
 		stdin, err := cmd.StdinPipe()
 		if err != nil {
 			return ClipboardCopiedMsg{Err: err}
@@ -35,41 +98,14 @@ func (m Model) copyToClipboardCmd(text string) tea.Cmd {
 	}
 }
 
-// openPager opens the result in an external pager
-func (m Model) openPager(result *db.QueryResult) tea.Cmd {
-	if m.config.Pager == "" || result == nil || len(result.Rows) == 0 {
-		return nil
-	}
-
-	// Create temp file
-	f, err := os.CreateTemp("", "ezdb-*.csv")
-	if err != nil {
-		return func() tea.Msg { return PagerFinishedMsg{Err: err} }
-	}
-	defer f.Close()
-
-	// Write CSV
-	w := csv.NewWriter(f)
-	w.Comma = ','
-
-	if err := w.Write(result.Columns); err != nil {
-		return func() tea.Msg { return PagerFinishedMsg{Err: err} }
-	}
-	for _, row := range result.Rows {
-		if err := w.Write(row); err != nil {
-			return func() tea.Msg { return PagerFinishedMsg{Err: err} }
-		}
+// handleClipboardCopied reports how a copy to the clipboard went
+func (m Model) handleClipboardCopied(msg ClipboardCopiedMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = fmt.Sprintf("Clipboard error: %v", msg.Err)
+		m.statusMsg = ""
+	} else {
+		m.errorMsg = ""
+		m.statusMsg = "Copied to clipboard"
 	}
-	w.Flush()
-
-	// Command
-	parts := strings.Fields(m.config.Pager)
-	cmdName := parts[0]
-	args := append(parts[1:], f.Name())
-
-	c := exec.Command(cmdName, args...)
-	return tea.ExecProcess(c, func(err error) tea.Msg {
-		os.Remove(f.Name()) // Cleanup
-		return PagerFinishedMsg{Err: err}
-	})
+	return m, nil
 }
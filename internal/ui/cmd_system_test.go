@@ -0,0 +1,15 @@
+package ui
+
+import "testing"
+
+func TestOSC52(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+	if got := osc52("hi"); got != "\x1b]52;c;aGk=\x07" {
+		t.Errorf("osc52 = %q", got)
+	}
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	if got := osc52("hi"); got != "\x1bPtmux;\x1b\x1b]52;c;aGk=\x07\x1b\\" {
+		t.Errorf("osc52 in tmux = %q", got)
+	}
+}
@@ -0,0 +1,118 @@
+// internal/ui/column_actions.go
+// Column aggregates: distinct counts, value counts, min/max and NULL counts of a result column, run against its source table.
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// columnAggregates are the entries of the column aggregates menu, in the
+// order of their number keys
+var columnAggregates = []string{"Count distinct", "Group by with counts", "Min / Max", "NULL count"}
+
+// columnAggregateQuery builds the SQL of aggregate action (an index into
+// columnAggregates) over column of tableName
+func columnAggregateQuery(dt db.DriverType, tableName, column string, action int) string {
+	col := quoteIdent(dt, column)
+	switch action {
+	case 0:
+		return fmt.Sprintf("SELECT COUNT(DISTINCT %s) AS distinct_count FROM %s", col, tableName)
+	case 1:
+		return fmt.Sprintf("SELECT %s, COUNT(*) AS row_count FROM %s GROUP BY %s ORDER BY row_count DESC", col, tableName, col)
+	case 2:
+		return fmt.Sprintf("SELECT MIN(%s) AS min_value, MAX(%s) AS max_value FROM %s", col, col, tableName)
+	}
+	return fmt.Sprintf("SELECT COUNT(*) - COUNT(%s) AS null_count, COUNT(*) AS row_count FROM %s", col, tableName)
+}
+
+// openColumnActions opens the column aggregates menu on the column at the
+// horizontal scroll position
+func (m *Model) openColumnActions() {
+	if m.showColumnActions || m.popupResult == nil || len(m.popupResult.Columns) == 0 {
+		return
+	}
+	m.showColumnActions = true
+	m.columnActionCol = m.cellColumn()
+	m.popupStack.Push("columnActions", func(m *Model) {
+		m.showColumnActions = false
+	})
+}
+
+// handleColumnActionKeys picks the column with left/right and runs the
+// aggregate of a number key
+func (m Model) handleColumnActionKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch key := msg.String(); key {
+	case "esc", "q":
+		m.closeTopPopup()
+	case "left", "h":
+		if m.columnActionCol > 0 {
+			m.columnActionCol--
+		}
+	case "right", "l":
+		if m.columnActionCol < len(m.popupResult.Columns)-1 {
+			m.columnActionCol++
+		}
+	default:
+		if len(key) == 1 && key[0] >= '1' && int(key[0]-'1') < len(columnAggregates) {
+			model, cmd := m.runColumnAggregate(int(key[0] - '1'))
+			return model, cmd, true
+		}
+	}
+	return m, nil, true
+}
+
+// runColumnAggregate runs an aggregate over the picked column of the table
+// the results were read from
+func (m Model) runColumnAggregate(action int) (Model, tea.Cmd) {
+	if m.driver == nil || m.columnActionCol >= len(m.popupResult.Columns) {
+		return m, nil
+	}
+	column := m.popupResult.Columns[m.columnActionCol]
+	tableName := entryTable(m.popupEntry)
+	if tableName == "" {
+		m.errorMsg = "Could not determine table name from query"
+		return m, nil
+	}
+	tableName, schema, ok := m.tableColumns(tableName)
+	if ok {
+		col, found := findColumn(schema, column)
+		if !found {
+			m.errorMsg = fmt.Sprintf("%s is not a column of %s", column, tableName)
+			return m, nil
+		}
+		column = col.Name
+	}
+	// The aggregate opens in a fresh results popup
+	m.closePopupsThrough("results")
+	return m, m.runQuery(columnAggregateQuery(m.driver.Type(), tableName, column, action))
+}
+
+// renderColumnActions draws the column aggregates menu over main
+func (m Model) renderColumnActions(main string) string {
+	width := min(max(m.width-8, 20), 35)
+	var content strings.Builder
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Column Aggregates") + "\n\n")
+	column := m.popupResult.Columns[m.columnActionCol]
+	content.WriteString("Column: " + lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Render(truncateEnd(column, width-10)) + "\n\n")
+	for i, label := range columnAggregates {
+		content.WriteString(fmt.Sprintf("%d - %s\n", i+1, label))
+	}
+	content.WriteString(fmt.Sprintf("\nPress 1-%d, ←/→ column, q to close", len(columnAggregates)))
+
+	popupBox := lipgloss.NewStyle().
+		Width(width).
+		Background(m.theme.PopupBg()).
+		Foreground(m.theme.TextPrimary()).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.HighlightColor()).
+		Padding(1).
+		Render(content.String())
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
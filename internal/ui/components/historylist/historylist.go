@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/nhath/ezdb/internal/ui/icons"
 )
 
@@ -19,56 +20,59 @@ type Item interface {
 	QueryPreview(maxLen int) string
 	Status() string
 	ErrorMessage() string
+	ErrorInfo() ErrorInfo
 	Preview() string
 	DurationMs() int64
 	RowCount() int
 	ExecutedAtFormatted() string
+	// Origin names where the query ran when that is not the current
+	// connection, e.g. "on staging from prod", or ""
+	Origin() string
+}
+
+// ErrorInfo is what the driver reported about an error besides its message
+type ErrorInfo struct {
+	Code     string // SQLSTATE or driver error number
+	Detail   string
+	Hint     string
+	Position int // 1-based character position in the query, 0 when unknown
 }
 
 // Styles for the list
 type Styles struct {
-	Item          lipgloss.Style
-	Selected      lipgloss.Style
-	Prompt        lipgloss.Style
-	Meta          lipgloss.Style
-	Error         lipgloss.Style
-	SystemMessage lipgloss.Style
-	SuccessIcon   lipgloss.Style
-	ErrorIcon     lipgloss.Style
-	InfoIcon      lipgloss.Style
-	Faint         lipgloss.Style
+	Header   lipgloss.Style // Query and meta lines of an entry
+	Accent   lipgloss.Color // Left border of the selected entry
+	Error    lipgloss.Style // Error message of the selected entry
+	ErrorDim lipgloss.Style // Error message of other entries
+	Preview  lipgloss.Style // Text preview of the expanded entry
 }
 
 // DefaultStyles returns default styling
 func DefaultStyles() Styles {
-	textFaint := lipgloss.Color("#6272A4")
-	successColor := lipgloss.Color("#50FA7B")
 	errorColor := lipgloss.Color("#FF5555")
-	highlightColor := lipgloss.Color("#8BE9FD")
-
 	return Styles{
-		Item:          lipgloss.NewStyle().PaddingLeft(1),
-		Selected:      lipgloss.NewStyle().PaddingLeft(1).Background(lipgloss.Color("#44475A")),
-		Prompt:        lipgloss.NewStyle().Foreground(lipgloss.Color("#50FA7B")).Bold(true),
-		Meta:          lipgloss.NewStyle().Foreground(textFaint),
-		Error:         lipgloss.NewStyle().Foreground(errorColor),
-		SystemMessage: lipgloss.NewStyle().Foreground(highlightColor).Italic(true),
-		SuccessIcon:   lipgloss.NewStyle().Foreground(successColor),
-		ErrorIcon:     lipgloss.NewStyle().Foreground(errorColor),
-		InfoIcon:      lipgloss.NewStyle().Foreground(highlightColor),
-		Faint:         lipgloss.NewStyle().Foreground(textFaint),
+		Header:   lipgloss.NewStyle().Background(lipgloss.Color("#44475A")).Foreground(lipgloss.Color("#F8F8F2")),
+		Accent:   lipgloss.Color("#8BE9FD"),
+		Error:    lipgloss.NewStyle().Foreground(errorColor),
+		ErrorDim: lipgloss.NewStyle().Foreground(errorColor).Faint(true),
+		Preview:  lipgloss.NewStyle().Foreground(lipgloss.Color("#6272A4")),
 	}
 }
 
 // Model represents the list state
 type Model struct {
-	items    []Item
-	selected int
-	expanded map[int64]bool
-	width    int
-	height   int
-	viewport viewport.Model
-	styles   Styles
+	items      []Item
+	selected   int
+	focused    bool // Whether the selection is shown
+	expandedID int64
+	table      string // Rendered result table of the expanded entry
+	width      int
+	viewport   viewport.Model
+	styles     Styles
+
+	// Layout of the last Refresh: where the first entry starts and each entry's height
+	top     int
+	heights []int
 
 	// Callbacks
 	highlightFunc func(string) string
@@ -76,13 +80,10 @@ type Model struct {
 
 // New creates a new list model
 func New() Model {
-	vp := viewport.New(80, 10)
 	return Model{
-		items:    []Item{},
-		selected: 0,
-		expanded: make(map[int64]bool),
-		viewport: vp,
+		viewport: viewport.New(80, 10),
 		styles:   DefaultStyles(),
+		focused:  true,
 	}
 }
 
@@ -92,20 +93,22 @@ func (m Model) SetItems(items []Item) Model {
 	if m.selected >= len(items) && len(items) > 0 {
 		m.selected = len(items) - 1
 	}
-	m.updateViewport()
 	return m
 }
 
-// SetSize sets the component dimensions
+// SetSize sets the component dimensions; Refresh lays the items out again
 func (m Model) SetSize(width, height int) Model {
 	m.width = width
-	m.height = height
 	m.viewport.Width = width
 	m.viewport.Height = height
-	m.updateViewport()
 	return m
 }
 
+// Height returns the visible height
+func (m Model) Height() int {
+	return m.viewport.Height
+}
+
 // SetStyles sets custom styles
 func (m Model) SetStyles(s Styles) Model {
 	m.styles = s
@@ -118,6 +121,18 @@ func (m Model) SetHighlightFunc(fn func(string) string) Model {
 	return m
 }
 
+// SetSelected selects the item at index i
+func (m Model) SetSelected(i int) Model {
+	m.selected = i
+	return m
+}
+
+// SetFocused sets whether the selected item is marked
+func (m Model) SetFocused(focused bool) Model {
+	m.focused = focused
+	return m
+}
+
 // Selected returns the currently selected index
 func (m Model) Selected() int {
 	return m.selected
@@ -131,54 +146,88 @@ func (m Model) SelectedItem() Item {
 	return nil
 }
 
+// SetExpanded expands the item with id, or none when id is 0. table is the
+// rendered result table shown under an expanded item that returned rows.
+func (m Model) SetExpanded(id int64, table string) Model {
+	m.expandedID = id
+	m.table = table
+	return m
+}
+
 // IsExpanded returns whether an item is expanded
 func (m Model) IsExpanded(id int64) bool {
-	return m.expanded[id]
+	return id != 0 && m.expandedID == id
 }
 
-// ToggleExpanded toggles expansion for the selected item
-func (m Model) ToggleExpanded() Model {
-	if item := m.SelectedItem(); item != nil {
-		m.expanded[item.ID()] = !m.expanded[item.ID()]
-		m.updateViewport()
-		m = m.ensureVisible()
+// Refresh renders the items into the viewport, anchored to the bottom when
+// they do not fill it
+func (m Model) Refresh() Model {
+	m.heights = m.heights[:0]
+	if len(m.items) == 0 {
+		m.top = 0
+		m.viewport.SetContent("")
+		return m
 	}
-	return m
-}
 
-// SetExpanded sets the expansion state for an item
-func (m Model) SetExpanded(id int64, expanded bool) Model {
-	m.expanded[id] = expanded
-	m.updateViewport()
+	sections := make([]string, len(m.items))
+	for i := range m.items {
+		sections[i] = strings.TrimRight(m.renderItem(i), "\n")
+		m.heights = append(m.heights, lipgloss.Height(sections[i]))
+	}
+	// A blank line between cards and one above the first
+	content := lipgloss.NewStyle().MarginTop(1).Render(strings.Join(sections, "\n\n"))
+	m.top = 1
+
+	if h := lipgloss.Height(content); h < m.viewport.Height {
+		content = strings.Repeat("\n", m.viewport.Height-h) + content
+		m.top += m.viewport.Height - h
+	}
+	m.viewport.SetContent(content)
 	return m
 }
 
-// MoveUp moves selection up
-func (m Model) MoveUp() Model {
-	if m.selected > 0 {
-		m.selected--
-		m = m.ensureVisible()
+// itemTop returns the content line item i starts on, as of the last Refresh
+func (m Model) itemTop(i int) int {
+	top := m.top
+	for _, h := range m.heights[:i] {
+		top += h + 1
 	}
-	return m
+	return top
 }
 
-// MoveDown moves selection down
-func (m Model) MoveDown() Model {
-	if m.selected < len(m.items)-1 {
-		m.selected++
-		m = m.ensureVisible()
+// EnsureVisible scrolls the selected item into view
+func (m Model) EnsureVisible() Model {
+	if m.selected < 0 || m.selected >= len(m.heights) {
+		return m
+	}
+	top := m.itemTop(m.selected)
+	bottom := top + m.heights[m.selected]
+
+	vTop := m.viewport.YOffset
+	vBottom := vTop + m.viewport.Height
+	if top < vTop {
+		m.viewport.SetYOffset(top)
+	} else if bottom > vBottom {
+		m.viewport.SetYOffset(bottom - m.viewport.Height)
 	}
 	return m
 }
 
-// SelectLast selects the last item
-func (m Model) SelectLast() Model {
-	if len(m.items) > 0 {
-		m.selected = len(m.items) - 1
-		m.updateViewport()
-		m.viewport.GotoBottom()
+// ItemAt returns the index of the item shown on line y of the view, or -1
+// when y falls between items
+func (m Model) ItemAt(y int) int {
+	if y < 0 || y >= m.viewport.Height {
+		return -1
 	}
-	return m
+	line := y + m.viewport.YOffset
+	top := m.top
+	for i, h := range m.heights {
+		if line >= top && line < top+h {
+			return i
+		}
+		top += h + 1
+	}
+	return -1
 }
 
 // GotoBottom scrolls viewport to bottom
@@ -187,6 +236,18 @@ func (m Model) GotoBottom() Model {
 	return m
 }
 
+// ScrollUp scrolls the view up by n lines
+func (m Model) ScrollUp(n int) Model {
+	m.viewport.LineUp(n)
+	return m
+}
+
+// ScrollDown scrolls the view down by n lines
+func (m Model) ScrollDown(n int) Model {
+	m.viewport.LineDown(n)
+	return m
+}
+
 // Update handles messages
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -199,137 +260,167 @@ func (m Model) View() string {
 	return m.viewport.View()
 }
 
-// updateViewport refreshes the viewport content
-func (m *Model) updateViewport() {
-	var sections []string
-	for i := range m.items {
-		sections = append(sections, m.renderItem(i))
-	}
-	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
-
-	// Bottom anchor: pad top if content is shorter
-	h := lipgloss.Height(content)
-	if h < m.height {
-		padding := strings.Repeat("\n", m.height-h)
-		content = padding + content
-	}
-
-	m.viewport.SetContent(content)
-}
-
-// renderItem renders a single item
-func (m *Model) renderItem(i int) string {
-	if i < 0 || i >= len(m.items) {
-		return ""
-	}
+// renderItem renders a single item as a card: a header with the query and
+// its outcome, then any error and, when expanded, the result
+func (m Model) renderItem(i int) string {
 	item := m.items[i]
-	isSelected := i == m.selected
-	isExpanded := m.expanded[item.ID()]
-
-	// Select style
-	style := m.styles.Item
-	if isSelected {
-		style = m.styles.Selected
-	}
-	style = style.Width(m.width - 2)
+	isSelected := m.focused && i == m.selected
+	isExpanded := m.IsExpanded(item.ID())
+	info := item.Status() == "info"
 
-	var content strings.Builder
+	var header strings.Builder
 
 	// Query Line
-	if item.Status() != "info" {
-		content.WriteString(m.styles.Prompt.Render(icons.IconSelect))
+	if !info {
+		indicator := " " + icons.IconCollapsed + " "
+		if isExpanded {
+			indicator = " " + icons.IconExpanded + " "
+		}
+		header.WriteString(indicator)
 	}
-
-	queryText := item.QueryPreview(m.width - 10)
+	queryText := item.QueryPreview(m.width - 14) // Adjusted for margins
 	if isExpanded {
 		queryText = item.Query()
 	}
-
-	if item.Status() == "info" {
-		content.WriteString(m.styles.SystemMessage.Render(queryText))
-	} else {
-		// Apply highlighting if available
-		if m.highlightFunc != nil {
-			queryText = m.highlightFunc(queryText)
-		}
-		content.WriteString(lipgloss.NewStyle().Bold(true).Render(queryText))
+	if !info && m.highlightFunc != nil {
+		queryText = m.highlightFunc(queryText)
 	}
-	content.WriteString("\n")
+	header.WriteString(queryText)
+	if isExpanded {
+		header.WriteString(" [EXPANDED]")
+	}
+	header.WriteString("\n")
 
 	// Meta Line
-	var statusIcon string
-	var iconStyle lipgloss.Style
+	statusIcon := icons.IconSuccess
 	switch item.Status() {
 	case "error":
 		statusIcon = icons.IconError
-		iconStyle = m.styles.ErrorIcon
 	case "info":
 		statusIcon = icons.IconInfo
-		iconStyle = m.styles.InfoIcon
-	default:
-		statusIcon = icons.IconSuccess
-		iconStyle = m.styles.SuccessIcon
 	}
-
-	var metaInfo string
-	if item.Status() == "info" {
-		metaInfo = " " + item.ExecutedAtFormatted()
+	if info {
+		header.WriteString(fmt.Sprintf("  %s %s", statusIcon, item.ExecutedAtFormatted()))
 	} else {
-		metaInfo = fmt.Sprintf(" %dms | %d rows | %s",
-			item.DurationMs(), item.RowCount(), item.ExecutedAtFormatted())
+		header.WriteString(fmt.Sprintf("  %s %dms | %d rows | %s", statusIcon, item.DurationMs(), item.RowCount(), item.ExecutedAtFormatted()))
+		if origin := item.Origin(); origin != "" {
+			header.WriteString(" | " + origin)
+		}
+	}
+
+	headerStyle := m.styles.Header.Width(m.width).Padding(1, 1)
+	if isSelected {
+		headerStyle = m.selectedBorder(headerStyle).PaddingLeft(1)
 	}
-	content.WriteString(iconStyle.Render("  "+statusIcon) + m.styles.Meta.Render(metaInfo))
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render(header.String()))
 	content.WriteString("\n")
 
-	// Error message
+	// Error: its first line, or the whole error block when expanded
 	if item.ErrorMessage() != "" {
-		content.WriteString(m.styles.Error.Render("  " + item.ErrorMessage()))
+		errStyle := m.styles.ErrorDim
+		if isSelected {
+			errStyle = m.styles.Error
+		}
+		if isExpanded {
+			blockStyle := lipgloss.NewStyle().Padding(1, 4)
+			if isSelected {
+				blockStyle = m.selectedBorder(blockStyle).PaddingLeft(3)
+			}
+			content.WriteString(blockStyle.Render(m.renderErrorBlock(item, errStyle, m.width-8)))
+		} else {
+			message, _, _ := strings.Cut(item.ErrorMessage(), "\n")
+			if code := item.ErrorInfo().Code; code != "" {
+				message = "[" + code + "] " + message
+			}
+			content.WriteString(errStyle.Render("  " + ansi.Truncate(message, m.width-2, "…")))
+		}
 		content.WriteString("\n")
 	}
 
-	// Preview
-	if item.Preview() != "" {
-		previewLines := strings.Split(item.Preview(), "\n")
-		var previewBody strings.Builder
-		for _, line := range previewLines {
-			previewBody.WriteString(line + "\n")
+	// Expanded result: the result table, or the preview text when there are no rows
+	if isExpanded && (item.RowCount() > 0 || item.Preview() != "") {
+		body := m.table
+		previewStyle := lipgloss.NewStyle().Padding(1, 4)
+		if item.RowCount() == 0 {
+			body = item.Preview() + "\n"
+			previewStyle = m.styles.Preview.Padding(1, 4)
 		}
-
-		styledPreview := lipgloss.NewStyle().
-			Foreground(m.styles.Faint.GetForeground()).
-			Padding(0, 4). // Left/Right padding for the preview text
-			Render(previewBody.String())
-
-		content.WriteString(styledPreview)
+		if isSelected {
+			previewStyle = m.selectedBorder(previewStyle).PaddingLeft(3) // 4 less the border
+		}
+		content.WriteString(previewStyle.Render(body))
 		content.WriteString("\n")
 	}
 
-	return style.Render(content.String())
+	return content.String()
 }
 
-// ensureVisible keeps the selected item in view
-func (m Model) ensureVisible() Model {
-	if len(m.items) == 0 {
-		return m
+// renderErrorBlock renders the whole error of an expanded item: its message,
+// detail and hint, and the query line it points at with the position marked
+func (m Model) renderErrorBlock(item Item, errStyle lipgloss.Style, width int) string {
+	info := item.ErrorInfo()
+	width = max(width, 20)
+	message := item.ErrorMessage()
+	if info.Code != "" {
+		message = "[" + info.Code + "] " + message
 	}
-
-	// Calculate top Y of selected item
-	top := 0
-	for i := 0; i < m.selected; i++ {
-		top += lipgloss.Height(m.renderItem(i))
+	lines := []string{errStyle.Width(width).Render(message)}
+	if info.Detail != "" {
+		lines = append(lines, m.styles.Preview.Width(width).Render("Detail: "+info.Detail))
 	}
+	if info.Hint != "" {
+		lines = append(lines, m.styles.Preview.Width(width).Render("Hint: "+info.Hint))
+	}
+	if info.Position > 0 {
+		lines = append(lines, "", errorSnippet(item.Query(), info.Position, width, errStyle, m.styles.Preview))
+	}
+	return strings.Join(lines, "\n")
+}
 
-	itemHeight := lipgloss.Height(m.renderItem(m.selected))
-	bottom := top + itemHeight
-
-	vTop := m.viewport.YOffset
-	vBottom := vTop + m.viewport.Height
-
-	if top < vTop {
-		m.viewport.SetYOffset(top)
-	} else if bottom > vBottom {
-		m.viewport.SetYOffset(bottom - m.viewport.Height)
+// errorSnippet renders the line of query holding the 1-based character
+// position, with that character marked and a caret under it, scrolled
+// sideways to fit width
+func errorSnippet(query string, position, width int, mark, gutter lipgloss.Style) string {
+	runes := []rune(strings.ReplaceAll(query, "\t", " "))
+	pos := min(position-1, len(runes))
+	start, lineNo := 0, 1
+	for i, r := range runes[:pos] {
+		if r == '\n' {
+			start, lineNo = i+1, lineNo+1
+		}
 	}
+	end := start
+	for end < len(runes) && runes[end] != '\n' {
+		end++
+	}
+	line, col := runes[start:end], pos-start
+
+	prefix := fmt.Sprintf("%d | ", lineNo)
+	room := max(width-len(prefix), 10)
+	from := 0
+	if len(line) > room {
+		from = max(0, min(col-room/2, len(line)-room))
+		line = line[from:min(from+room, len(line))]
+	}
+	col -= from
+	at := " "
+	if col < len(line) {
+		at = string(line[col])
+	}
+	after := ""
+	if col+1 < len(line) {
+		after = string(line[col+1:])
+	}
+	marked := string(line[:col]) + mark.Reverse(true).Render(at) + after
+	caret := strings.Repeat(" ", len(prefix)+col) + mark.Render("^")
+	return gutter.Render(prefix) + marked + "\n" + caret
+}
 
-	return m
+// selectedBorder adds the accent border marking the selected item
+func (m Model) selectedBorder(s lipgloss.Style) lipgloss.Style {
+	return s.BorderLeft(true).
+		BorderStyle(lipgloss.ThickBorder()).
+		BorderForeground(m.styles.Accent)
 }
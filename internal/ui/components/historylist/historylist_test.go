@@ -0,0 +1,95 @@
+package historylist
+
+import (
+	"strings"
+	"testing"
+)
+
+type fakeItem struct {
+	id      int64
+	query   string
+	preview string
+	rows    int
+	err     string
+	info    ErrorInfo
+}
+
+func (f fakeItem) ID() int64                      { return f.id }
+func (f fakeItem) Query() string                  { return f.query }
+func (f fakeItem) QueryPreview(maxLen int) string { return f.query }
+func (f fakeItem) Status() string                 { return "success" }
+func (f fakeItem) ErrorMessage() string           { return f.err }
+func (f fakeItem) ErrorInfo() ErrorInfo           { return f.info }
+func (f fakeItem) Preview() string                { return f.preview }
+func (f fakeItem) DurationMs() int64              { return 1 }
+func (f fakeItem) RowCount() int                  { return f.rows }
+func (f fakeItem) ExecutedAtFormatted() string    { return "12:00:00" }
+func (f fakeItem) Origin() string                 { return "" }
+
+func items(n int) []Item {
+	list := make([]Item, n)
+	for i := range list {
+		list[i] = fakeItem{id: int64(i + 1), query: "SELECT " + strings.Repeat("x", i+1)}
+	}
+	return list
+}
+
+func TestItemAtBottomAnchored(t *testing.T) {
+	m := New().SetSize(60, 40).SetItems(items(2)).Refresh()
+
+	// Two cards of 4 lines, a blank line between and one above: 10 lines
+	// padded to the bottom of 40, so the cards start on lines 31 and 36
+	for y, want := range map[int]int{30: -1, 31: 0, 34: 0, 35: -1, 36: 1, 39: 1} {
+		if got := m.ItemAt(y); got != want {
+			t.Errorf("ItemAt(%d) = %d, want %d", y, got, want)
+		}
+	}
+}
+
+func TestEnsureVisibleScrolls(t *testing.T) {
+	m := New().SetSize(60, 6).SetItems(items(5)).SetSelected(0).Refresh().EnsureVisible()
+	if m.ItemAt(1) != 0 {
+		t.Fatalf("first item not shown after selecting it, ItemAt(1) = %d", m.ItemAt(1))
+	}
+	m = m.SetSelected(4).Refresh().EnsureVisible()
+	if m.ItemAt(5) != 4 {
+		t.Errorf("last item not shown at the bottom after selecting it, ItemAt(5) = %d", m.ItemAt(5))
+	}
+}
+
+func TestExpandedShowsTable(t *testing.T) {
+	list := []Item{
+		fakeItem{id: 1, query: "SELECT 1", rows: 1},
+		fakeItem{id: 2, query: "DELETE FROM t", preview: "3 rows affected"},
+	}
+	m := New().SetSize(60, 30).SetItems(list).SetExpanded(1, "TABLE VIEW").Refresh()
+	if view := m.View(); !strings.Contains(view, "TABLE VIEW") || strings.Contains(view, "rows affected") {
+		t.Errorf("expanding the query with rows should show only its table:\n%s", view)
+	}
+	m = m.SetExpanded(2, "").Refresh()
+	if view := m.View(); !strings.Contains(view, "3 rows affected") || strings.Contains(view, "TABLE VIEW") {
+		t.Errorf("expanding the statement should show its preview:\n%s", view)
+	}
+}
+
+func TestExpandedErrorMarksPosition(t *testing.T) {
+	item := fakeItem{id: 1, query: "SELECT id,\n  nme FROM items", err: `column "nme" does not exist`,
+		info: ErrorInfo{Code: "42703", Hint: `Perhaps you meant "name".`, Position: 14}}
+	m := New().SetSize(60, 30).SetItems([]Item{item}).Refresh()
+	if view := m.View(); !strings.Contains(view, `[42703] column "nme" does not exist`) || strings.Contains(view, "Hint:") {
+		t.Errorf("collapsed error should be one line with its code:\n%s", view)
+	}
+
+	view := m.SetExpanded(1, "").Refresh().View()
+	if !strings.Contains(view, `Hint: Perhaps you meant "name".`) || !strings.Contains(view, "2 |   nme FROM items") {
+		t.Fatalf("expanded error should show the hint and the failing line:\n%s", view)
+	}
+	lines := strings.Split(view, "\n")
+	for i, line := range lines[:len(lines)-1] {
+		if strings.Contains(line, "2 |   nme") {
+			if caret := strings.Index(lines[i+1], "^"); caret != strings.Index(line, "nme") {
+				t.Errorf("caret at column %d, want under nme:\n%s\n%s", caret, line, lines[i+1])
+			}
+		}
+	}
+}
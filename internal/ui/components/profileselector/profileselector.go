@@ -4,10 +4,12 @@ package profileselector
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/nhath/ezdb/internal/config"
 	"github.com/nhath/ezdb/internal/ui/icons"
 )
@@ -21,18 +23,43 @@ const (
 	StateManagementMenu
 	StateAddingProfile
 	StateEditingProfile
+	StateWizard // First-run setup
 )
 
 // Profile represents a selectable profile
 type Profile struct {
 	Name     string
-	Type     string // postgres, mysql, sqlite
+	Type     string // postgres, mysql, sqlite, cassandra, bigquery
 	Host     string
 	Port     int
 	User     string
 	Database string
 	Password string
 
+	// Group is the folder the profile is listed under, e.g. "clients/acme"
+	Group string
+
+	// CredentialsFile is a service account JSON key (bigquery)
+	CredentialsFile string
+
+	// Color is the accent used while connected
+	Color string
+
+	// Label is an environment badge, e.g. "PROD"
+	Label string
+
+	// LastUsed is when the profile last connected, zero if never
+	LastUsed time.Time
+
+	// SessionInit statements run on every new connection
+	SessionInit []string
+
+	// Params are extra DSN parameters, key=value pairs separated by &
+	Params string
+
+	// ExternalPassword is set when password_cmd or vault_path supplies the password
+	ExternalPassword bool
+
 	// SSH tunneling
 	SSHHost     string
 	SSHPort     int
@@ -59,6 +86,13 @@ func (p *Profile) BuildDSN(password string) string {
 		return fmt.Sprintf("mysql://%s@%s:%d/%s", p.User, p.Host, p.Port, p.Database)
 	case "sqlite":
 		return fmt.Sprintf("sqlite://%s", p.Database)
+	case "cassandra":
+		if password != "" {
+			return fmt.Sprintf("cassandra://%s:%s@%s:%d/%s", p.User, password, p.Host, p.Port, p.Database)
+		}
+		return fmt.Sprintf("cassandra://%s@%s:%d/%s", p.User, p.Host, p.Port, p.Database)
+	case "bigquery":
+		return fmt.Sprintf("bigquery://%s/%s", p.Host, p.Database)
 	default:
 		return p.Database // Return as-is for unknown types
 	}
@@ -96,6 +130,7 @@ type Styles struct {
 
 // DefaultStyles returns the default styling
 func DefaultStyles(theme config.Theme) Styles {
+	theme = theme.Readable()
 	return Styles{
 		Box: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
@@ -109,7 +144,7 @@ func DefaultStyles(theme config.Theme) Styles {
 			MarginBottom(2),
 		Title: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color(theme.TextPrimary)).
+			Foreground(lipgloss.Color(config.ReadableOn(theme.Highlight, theme.TextPrimary, theme.BgPrimary))).
 			Background(lipgloss.Color(theme.Highlight)).
 			Padding(0, 1).
 			MarginBottom(1),
@@ -216,10 +251,16 @@ type StatusMsg struct {
 	Message string
 }
 
+// TestConnectionMsg asks the app to try connecting with a profile from the form
+type TestConnectionMsg struct {
+	Profile Profile
+}
+
 // ProfileSavedMsg is sent when a profile is added or updated
 type ProfileSavedMsg struct {
 	Profile Profile
 	IsNew   bool // true for add, false for edit
+	Connect bool // Connect once saved, from the setup wizard
 }
 
 // Model represents the selector state
@@ -238,6 +279,12 @@ type Model struct {
 	userInput         textinput.Model
 	databaseInput     textinput.Model // serves as path for sqlite
 	passwordFormInput textinput.Model // For saving password
+	credentialsInput  textinput.Model // Service account key file (bigquery)
+	colorInput        textinput.Model // Accent color while connected
+	sessionInput      textinput.Model // Session statements, separated by ;
+	groupInput        textinput.Model // Folder path, e.g. clients/acme
+	labelInput        textinput.Model // Environment badge, e.g. PROD
+	paramsInput       textinput.Model // Extra DSN parameters
 
 	// SSH Form inputs
 	sshHostInput     textinput.Model
@@ -246,6 +293,19 @@ type Model struct {
 	sshKeyInput      textinput.Model
 	sshPasswordInput textinput.Model
 
+	// Profile list
+	collapsed   map[string]bool // Folded folders by path
+	cursor      int             // Index into the visible rows
+	searching   bool
+	searchInput textinput.Model // Fuzzy search across all profiles
+	byName      bool            // Alphabetical instead of most recently used first
+
+	// Setup wizard
+	wizardStep    wizardStep
+	wizardType    int   // Index into wizardTypes
+	wizardTesting bool  // Waiting for the connection test
+	wizardTestErr error // Outcome of the last test
+
 	formFocused    int      // Index of focused field
 	editingProfile *Profile // Profile being edited (nil for add)
 	width          int
@@ -281,19 +341,30 @@ func New(profiles []Profile, theme config.Theme) Model {
 		return t
 	}
 
-	return Model{
+	search := textinput.New()
+	search.Placeholder = "Search profiles..."
+	search.Prompt = "/ "
+	search.Width = 50
+
+	m := Model{
 		profiles:      profiles,
 		selected:      0,
 		state:         StateSelectingProfile,
 		passwordInput: ti,
 
 		nameInput:         newInput("Profile Name", 50),
-		typeInput:         newInput("Type (postgres, mysql, sqlite)", 30),
-		hostInput:         newInput("Host (localhost)", 40),
+		typeInput:         newInput("Type (postgres, mysql, sqlite, cassandra, bigquery)", 50),
+		hostInput:         newInput("Host (localhost), socket path / GCP Project", 40),
 		portInput:         newInput("Port (5432)", 10),
 		userInput:         newInput("User", 30),
-		databaseInput:     newInput("Database / Path", 40),
+		databaseInput:     newInput("Database / Path / Keyspace / Dataset", 40),
 		passwordFormInput: newPasswordInput("Password (optional)", 30),
+		credentialsInput:  newInput("Service account JSON (optional, default ADC)", 50),
+		colorInput:        newInput("Accent color (#BF616A for production)", 40),
+		sessionInput:      newInput("SET statements run on connect, separated by ;", 50),
+		groupInput:        newInput("Folder (prod, clients/acme)", 40),
+		labelInput:        newInput("Environment badge (PROD, STAGING)", 40),
+		paramsInput:       newInput("application_name=ezdb&sslmode=disable", 50),
 
 		sshHostInput:     newInput("SSH Host", 40),
 		sshPortInput:     newInput("SSH Port (22)", 10),
@@ -301,9 +372,25 @@ func New(profiles []Profile, theme config.Theme) Model {
 		sshKeyInput:      newInput("SSH Key Path (~/.ssh/id_rsa)", 50),
 		sshPasswordInput: newPasswordInput("SSH Password (optional)", 30),
 
+		collapsed:   map[string]bool{},
+		searchInput: search,
+
 		formFocused: 0,
 		styles:      DefaultStyles(theme),
 	}
+	// Nothing to select yet: walk through creating the first profile
+	if len(profiles) == 0 {
+		m.startWizard()
+	}
+	// Start on the first profile listed, the most recently used
+	rows := m.rows()
+	for i, r := range rows {
+		if !r.isFolder() {
+			m.moveCursor(rows, i)
+			break
+		}
+	}
+	return m
 }
 
 // SetProfiles updates the profile list
@@ -312,6 +399,7 @@ func (m Model) SetProfiles(profiles []Profile) Model {
 	if m.selected >= len(profiles) {
 		m.selected = 0
 	}
+	m.syncCursor()
 	return m
 }
 
@@ -349,6 +437,15 @@ func (m Model) Selected() int {
 	return m.selected
 }
 
+// Select moves the selection to the profile at index i
+func (m Model) Select(i int) Model {
+	if i >= 0 && i < len(m.profiles) {
+		m.selected = i
+		m.syncCursor()
+	}
+	return m
+}
+
 // SelectedProfile returns the selected profile
 func (m Model) SelectedProfile() *Profile {
 	if m.selected >= 0 && m.selected < len(m.profiles) {
@@ -360,20 +457,91 @@ func (m Model) SelectedProfile() *Profile {
 // NeedsPassword returns true if the selected profile needs a password
 func (m Model) NeedsPassword() bool {
 	p := m.SelectedProfile()
-	return p != nil && p.Type != "sqlite" && p.Password == ""
+	return p != nil && p.Type != "sqlite" && p.Password == "" && !p.ExternalPassword
+}
+
+// Connect selects the profile under the cursor, asking for its password first if needed
+func (m Model) Connect() (Model, tea.Cmd) {
+	if m.NeedsPassword() {
+		// Show password input
+		m.state = StateEnteringPassword
+		m.passwordInput.Focus()
+		return m, textinput.Blink
+	}
+	// SQLite doesn't need password
+	return m, func() tea.Msg {
+		return SelectedMsg{Index: m.selected, Password: ""}
+	}
+}
+
+// formValues builds a profile from the form as entered
+func (m Model) formValues() Profile {
+	var session []string
+	for _, stmt := range strings.Split(m.sessionInput.Value(), ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			session = append(session, stmt)
+		}
+	}
+
+	port := 0
+	if portStr := strings.TrimSpace(m.portInput.Value()); portStr != "" {
+		fmt.Sscanf(portStr, "%d", &port)
+	}
+	sshPort := 22
+	if sshPortStr := strings.TrimSpace(m.sshPortInput.Value()); sshPortStr != "" {
+		fmt.Sscanf(sshPortStr, "%d", &sshPort)
+	}
+
+	return Profile{
+		Name:        strings.TrimSpace(m.nameInput.Value()),
+		Type:        strings.TrimSpace(m.typeInput.Value()),
+		Host:        strings.TrimSpace(m.hostInput.Value()),
+		Port:        port,
+		User:        strings.TrimSpace(m.userInput.Value()),
+		Database:    strings.TrimSpace(m.databaseInput.Value()),
+		Password:    strings.TrimSpace(m.passwordFormInput.Value()),
+		SSHHost:     strings.TrimSpace(m.sshHostInput.Value()),
+		SSHPort:     sshPort,
+		SSHUser:     strings.TrimSpace(m.sshUserInput.Value()),
+		SSHKeyPath:  strings.TrimSpace(m.sshKeyInput.Value()),
+		SSHPassword: strings.TrimSpace(m.sshPasswordInput.Value()),
+
+		CredentialsFile: strings.TrimSpace(m.credentialsInput.Value()),
+		Color:           strings.TrimSpace(m.colorInput.Value()),
+		SessionInit:     session,
+		Group:           strings.Join(groupPath(m.groupInput.Value()), "/"),
+		Label:           strings.TrimSpace(m.labelInput.Value()),
+		Params:          strings.TrimSpace(m.paramsInput.Value()),
+	}
+}
+
+// formProfile builds a profile from the form, or returns why the form is invalid
+func (m Model) formProfile() (Profile, string) {
+	p := m.formValues()
+	// Basic validtion
+	if p.Name == "" {
+		return Profile{}, "Profile name is required"
+	}
+	if p.Type != "sqlite" && p.Host == "" {
+		return Profile{}, "Host is required for non-sqlite"
+	}
+	return p, ""
 }
 
 // Update handles input
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.state == StateWizard {
+			return m.updateWizard(msg)
+		}
 		// Handle form input states (Add/Edit profile)
 		if m.state == StateAddingProfile || m.state == StateEditingProfile {
 			switch msg.String() {
 			case "tab":
 				// Cycle next
 				m.blurField(m.formFocused)
-				m.formFocused = (m.formFocused + 1) % 12 // 12 inputs
+				m.formFocused = (m.formFocused + 1) % 18 // 18 inputs
 				m.focusField(m.formFocused)
 				return m, nil
 			case "shift+tab":
@@ -381,65 +549,36 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				m.blurField(m.formFocused)
 				m.formFocused--
 				if m.formFocused < 0 {
-					m.formFocused = 11
+					m.formFocused = 17
 				}
 				m.focusField(m.formFocused)
 				return m, nil
 			case "enter":
 				// Submit form
-				name := strings.TrimSpace(m.nameInput.Value())
-				dbType := strings.TrimSpace(m.typeInput.Value())
-				host := strings.TrimSpace(m.hostInput.Value())
-				portStr := strings.TrimSpace(m.portInput.Value())
-				user := strings.TrimSpace(m.userInput.Value())
-				database := strings.TrimSpace(m.databaseInput.Value())
-				password := strings.TrimSpace(m.passwordFormInput.Value())
-
-				sshHost := strings.TrimSpace(m.sshHostInput.Value())
-				sshPortStr := strings.TrimSpace(m.sshPortInput.Value())
-				sshUser := strings.TrimSpace(m.sshUserInput.Value())
-				sshKey := strings.TrimSpace(m.sshKeyInput.Value())
-				sshPass := strings.TrimSpace(m.sshPasswordInput.Value())
-
-				// Basic validtion
-				if name == "" {
-					m.statusMessage = "Profile name is required"
-					return m, nil
-				}
-				if dbType != "sqlite" && host == "" {
-					m.statusMessage = "Host is required for non-sqlite"
+				profile, invalid := m.formProfile()
+				if invalid != "" {
+					m.statusMessage = invalid
 					return m, nil
 				}
 
-				port := 0
-				if portStr != "" {
-					fmt.Sscanf(portStr, "%d", &port)
-				}
-				sshPort := 22
-				if sshPortStr != "" {
-					fmt.Sscanf(sshPortStr, "%d", &sshPort)
-				}
-
 				// Return ProfileSavedMsg to app for processing
 				return m, func() tea.Msg {
 					return ProfileSavedMsg{
-						Profile: Profile{
-							Name:        name,
-							Type:        dbType,
-							Host:        host,
-							Port:        port,
-							User:        user,
-							Database:    database,
-							Password:    password,
-							SSHHost:     sshHost,
-							SSHPort:     sshPort,
-							SSHUser:     sshUser,
-							SSHKeyPath:  sshKey,
-							SSHPassword: sshPass,
-						},
-						IsNew: m.state == StateAddingProfile,
+						Profile: profile,
+						IsNew:   m.state == StateAddingProfile,
 					}
 				}
+			case "ctrl+t":
+				// Try the connection without saving
+				profile, invalid := m.formProfile()
+				if invalid != "" {
+					m.statusMessage = invalid
+					return m, nil
+				}
+				m.statusMessage = "Testing connection..."
+				return m, func() tea.Msg {
+					return TestConnectionMsg{Profile: profile}
+				}
 			case "esc":
 				// Cancel form
 				m.state = StateManagementMenu
@@ -451,33 +590,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				return m, tea.Quit
 			default:
 				// Update the focused input
-				var cmd tea.Cmd
-				switch m.formFocused {
-				case 0:
-					m.nameInput, cmd = m.nameInput.Update(msg)
-				case 1:
-					m.typeInput, cmd = m.typeInput.Update(msg)
-				case 2:
-					m.hostInput, cmd = m.hostInput.Update(msg)
-				case 3:
-					m.portInput, cmd = m.portInput.Update(msg)
-				case 4:
-					m.userInput, cmd = m.userInput.Update(msg)
-				case 5:
-					m.databaseInput, cmd = m.databaseInput.Update(msg)
-				case 6:
-					m.passwordFormInput, cmd = m.passwordFormInput.Update(msg)
-				case 7:
-					m.sshHostInput, cmd = m.sshHostInput.Update(msg)
-				case 8:
-					m.sshPortInput, cmd = m.sshPortInput.Update(msg)
-				case 9:
-					m.sshUserInput, cmd = m.sshUserInput.Update(msg)
-				case 10:
-					m.sshKeyInput, cmd = m.sshKeyInput.Update(msg)
-				case 11:
-					m.sshPasswordInput, cmd = m.sshPasswordInput.Update(msg)
-				}
+				cmd := m.updateField(m.formFocused, msg)
 				return m, cmd
 			}
 		}
@@ -557,26 +670,37 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 
 		// Profile selection mode
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+		rows := m.rows()
 		switch msg.String() {
 		case "up", "k":
-			if m.selected > 0 {
-				m.selected--
-			}
+			m.moveCursor(rows, m.cursor-1)
 		case "down", "j":
-			if m.selected < len(m.profiles)-1 {
-				m.selected++
-			}
+			m.moveCursor(rows, m.cursor+1)
+		case "left", "h":
+			m.fold(rows)
+		case "right", "l":
+			m.unfold(rows)
+		case "/":
+			m.searching = true
+			m.searchInput.Focus()
+			return m, textinput.Blink
+		case "esc":
+			m.clearSearch()
+		case "s":
+			m.byName = !m.byName
+			m.syncCursor()
 		case "enter":
-			if m.NeedsPassword() {
-				// Show password input
-				m.state = StateEnteringPassword
-				m.passwordInput.Focus()
-				return m, textinput.Blink
+			if m.cursor >= len(rows) {
+				return m, nil
 			}
-			// SQLite doesn't need password
-			return m, func() tea.Msg {
-				return SelectedMsg{Index: m.selected, Password: ""}
+			if r := rows[m.cursor]; r.isFolder() {
+				m.collapsed[r.folder] = !m.collapsed[r.folder]
+				return m, nil
 			}
+			return m.Connect()
 		case "m", "M":
 			// Open management menu
 			m.state = StateManagementMenu
@@ -596,6 +720,10 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.passwordInput, cmd = m.passwordInput.Update(msg)
 		cmds = append(cmds, cmd)
 	}
+	if m.searching {
+		m.searchInput, cmd = m.searchInput.Update(msg)
+		cmds = append(cmds, cmd)
+	}
 
 	// For form inputs:
 	// If it's a key message, it was already handled in the focused field switch above.
@@ -626,6 +754,18 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 		m.sshPasswordInput, cmd = m.sshPasswordInput.Update(msg)
 		cmds = append(cmds, cmd)
+		m.credentialsInput, cmd = m.credentialsInput.Update(msg)
+		cmds = append(cmds, cmd)
+		m.colorInput, cmd = m.colorInput.Update(msg)
+		cmds = append(cmds, cmd)
+		m.sessionInput, cmd = m.sessionInput.Update(msg)
+		cmds = append(cmds, cmd)
+		m.groupInput, cmd = m.groupInput.Update(msg)
+		cmds = append(cmds, cmd)
+		m.labelInput, cmd = m.labelInput.Update(msg)
+		cmds = append(cmds, cmd)
+		m.paramsInput, cmd = m.paramsInput.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	return m, tea.Batch(cmds...)
@@ -647,13 +787,25 @@ func (m Model) View() string {
 	itemWidth := m.styles.Box.GetWidth() - 6
 
 	if m.state == StateSelectingProfile {
+		// Lines left for the list; the logo goes when it would crowd it out
+		budget, showLogo := -1, true
+		if m.height > 0 {
+			budget = m.height - listChrome
+			if budget < minListLines {
+				budget += logoLines
+				showLogo = false
+			}
+		}
+
 		// Center the logo
-		centeredLogo := lipgloss.NewStyle().
-			Width(m.styles.Box.GetWidth() - 4).
-			Align(lipgloss.Center).
-			Render(m.styles.Logo.Render(logo))
-		b.WriteString(centeredLogo)
-		b.WriteString("\n\n")
+		if showLogo {
+			centeredLogo := lipgloss.NewStyle().
+				Width(m.styles.Box.GetWidth() - 4).
+				Align(lipgloss.Center).
+				Render(m.styles.Logo.Render(logo))
+			b.WriteString(centeredLogo)
+			b.WriteString("\n\n")
+		}
 
 		// Center the title
 		centeredTitle := lipgloss.NewStyle().
@@ -661,18 +813,63 @@ func (m Model) View() string {
 			Align(lipgloss.Center).
 			Render(m.styles.Title.Render(" SELECT PROFILE "))
 		b.WriteString(centeredTitle)
+		b.WriteString("\n")
+		order := "Most recent first " + m.styles.HintKey.Render("s") + " " + m.styles.Hint.Render("A-Z")
+		if m.byName {
+			order = "A-Z " + m.styles.HintKey.Render("s") + " " + m.styles.Hint.Render("Most recent first")
+		}
+		b.WriteString(lipgloss.NewStyle().
+			Width(m.styles.Box.GetWidth() - 4).
+			Align(lipgloss.Center).
+			Render(m.styles.Hint.Render(order)))
 		b.WriteString("\n\n")
 
-		for i, p := range m.profiles {
+		searching := m.searching || m.searchInput.Value() != ""
+		if searching {
+			b.WriteString(m.searchInput.View() + "\n\n")
+		}
+
+		rows := m.rows()
+		if len(rows) == 0 && searching {
+			b.WriteString(m.styles.Hint.Render("  No matching profiles") + "\n")
+		}
+		first, last := listWindow(rows, m.cursor, budget)
+		if first > 0 {
+			b.WriteString(m.styles.Hint.Render(fmt.Sprintf("  ↑ %d more", first)) + "\n")
+		}
+		for i := first; i < last; i++ {
+			r := rows[i]
+			indent := strings.Repeat("  ", r.depth)
+			if r.isFolder() {
+				style := m.styles.Item.Copy().Width(itemWidth)
+				prefix := "   "
+				if i == m.cursor {
+					style = m.styles.Selected.Copy().Width(itemWidth)
+					prefix = " " + icons.IconSelect + " "
+				}
+				arrow := "▾"
+				if m.collapsed[r.folder] {
+					arrow = "▸"
+				}
+				folderRow := prefix + indent + m.styles.ProfileIcon.Render(arrow) + " " + m.styles.ItemType.Render(r.name) +
+					" " + m.styles.ItemHost.Render(fmt.Sprintf("(%d)", r.count))
+				b.WriteString(style.Render(folderRow) + "\n")
+				continue
+			}
+
+			p := m.profiles[r.profile]
 			style := m.styles.Item.Copy().Width(itemWidth)
 			nameStyle := m.styles.ItemName
 			hostStyle := m.styles.ItemHost
 
 			// Selection indicator and icon
 			icon := icons.GetDatabaseIcon(p.Type)
+			if p.Color != "" {
+				icon = lipgloss.NewStyle().Foreground(lipgloss.Color(p.Color)).Render(icon)
+			}
 
 			prefix := "   "
-			if i == m.selected {
+			if i == m.cursor {
 				style = m.styles.Selected.Copy().Width(itemWidth)
 				nameStyle = m.styles.SelectedName
 				hostStyle = m.styles.SelectedHost
@@ -680,7 +877,13 @@ func (m Model) View() string {
 			}
 
 			// First row: icon + name
-			nameRow := prefix + icon + " " + nameStyle.Render(p.Name)
+			nameRow := prefix + indent + icon + " " + nameStyle.Render(p.Name)
+			if p.Label != "" {
+				nameRow += " " + m.styles.ItemSSH.Bold(true).Render(strings.ToUpper(p.Label))
+			}
+			if searching && p.Group != "" {
+				nameRow += " " + hostStyle.Render(p.Group)
+			}
 
 			// Second row: faint connection info
 			hostStr := ""
@@ -706,20 +909,32 @@ func (m Model) View() string {
 			}
 
 			// Indent the second row to align with name (prefix width + icon width)
-			indent := "      "
-			hostRow := indent + hostStyle.Render(hostStr)
+			hostRow := "      " + indent + hostStyle.Render(hostStr)
+			if !p.LastUsed.IsZero() {
+				hostRow += hostStyle.Render(icons.IconSeparator + ago(p.LastUsed, time.Now()))
+			}
 
 			b.WriteString(style.Render(nameRow+"\n"+hostRow) + "\n")
 		}
+		if last < len(rows) {
+			b.WriteString(m.styles.Hint.Render(fmt.Sprintf("  ↓ %d more", len(rows)-last)) + "\n")
+		}
 
 		b.WriteString("\n")
 		// Footer hints - inline format
 		hints := []struct{ key, desc string }{
-			{"↑↓", "Navigate"},
+			{"↑↓←→", "Browse"},
 			{"Enter", "Select"},
+			{"/", "Search"},
 			{"m", "Manage"},
 			{"q", "Quit"},
-			{"?", "Help"},
+		}
+		if searching {
+			hints = []struct{ key, desc string }{
+				{"↑↓", "Navigate"},
+				{"Enter", "Connect"},
+				{"Esc", "Clear"},
+			}
 		}
 		var hintParts []string
 		for _, h := range hints {
@@ -823,6 +1038,9 @@ func (m Model) View() string {
 			Render(footerRow)
 		b.WriteString(footer)
 
+	} else if m.state == StateWizard {
+		b.WriteString(m.viewWizard(itemWidth))
+
 	} else if m.state == StateAddingProfile || m.state == StateEditingProfile {
 		// Profile form view (Add or Edit)
 		title := " NEW PROFILE "
@@ -850,12 +1068,17 @@ func (m Model) View() string {
 
 		renderField("Name", m.nameInput, 0)
 		renderField("Type", m.typeInput, 1)
+		renderField("Group", m.groupInput, 15)
 		b.WriteString(m.styles.Divider.Render("──────────────────────────────────────────────────") + "\n")
 		renderField("Host", m.hostInput, 2)
 		renderField("Port", m.portInput, 3)
 		renderField("User", m.userInput, 4)
 		renderField("Database", m.databaseInput, 5)
 		renderField("Password", m.passwordFormInput, 6)
+		renderField("Color", m.colorInput, 13)
+		renderField("Label", m.labelInput, 16)
+		renderField("Session", m.sessionInput, 14)
+		renderField("Params", m.paramsInput, 17)
 
 		b.WriteString("\n" + m.styles.SectionTitle.Render(" SSH Tunnel (Optional) ") + "\n")
 
@@ -865,11 +1088,17 @@ func (m Model) View() string {
 		renderField("SSH Key", m.sshKeyInput, 10)
 		renderField("SSH Password", m.sshPasswordInput, 11)
 
+		b.WriteString("\n" + m.styles.SectionTitle.Render(" BigQuery (Optional) ") + "\n")
+
+		renderField("Credentials", m.credentialsInput, 12)
+
 		b.WriteString("\n")
 		footerRow := m.styles.HintKey.Render("Tab") + " " + m.styles.Hint.Copy().Margin(0).Render("Next") +
 			icons.IconSeparator +
 			m.styles.HintKey.Render("Enter") + " " + m.styles.Hint.Copy().Margin(0).Render("Save") +
 			icons.IconSeparator +
+			m.styles.HintKey.Render("Ctrl+T") + " " + m.styles.Hint.Copy().Margin(0).Render("Test") +
+			icons.IconSeparator +
 			m.styles.HintKey.Render("Esc") + " " + m.styles.Hint.Copy().Margin(0).Render("Cancel")
 
 		footer := m.styles.Footer.Copy().
@@ -897,6 +1126,50 @@ func (m Model) View() string {
 
 // Helpers
 
+// updateField passes msg to the form input at idx
+func (m *Model) updateField(idx int, msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	switch idx {
+	case 0:
+		m.nameInput, cmd = m.nameInput.Update(msg)
+	case 1:
+		m.typeInput, cmd = m.typeInput.Update(msg)
+	case 2:
+		m.hostInput, cmd = m.hostInput.Update(msg)
+	case 3:
+		m.portInput, cmd = m.portInput.Update(msg)
+	case 4:
+		m.userInput, cmd = m.userInput.Update(msg)
+	case 5:
+		m.databaseInput, cmd = m.databaseInput.Update(msg)
+	case 6:
+		m.passwordFormInput, cmd = m.passwordFormInput.Update(msg)
+	case 7:
+		m.sshHostInput, cmd = m.sshHostInput.Update(msg)
+	case 8:
+		m.sshPortInput, cmd = m.sshPortInput.Update(msg)
+	case 9:
+		m.sshUserInput, cmd = m.sshUserInput.Update(msg)
+	case 10:
+		m.sshKeyInput, cmd = m.sshKeyInput.Update(msg)
+	case 11:
+		m.sshPasswordInput, cmd = m.sshPasswordInput.Update(msg)
+	case 12:
+		m.credentialsInput, cmd = m.credentialsInput.Update(msg)
+	case 13:
+		m.colorInput, cmd = m.colorInput.Update(msg)
+	case 14:
+		m.sessionInput, cmd = m.sessionInput.Update(msg)
+	case 15:
+		m.groupInput, cmd = m.groupInput.Update(msg)
+	case 16:
+		m.labelInput, cmd = m.labelInput.Update(msg)
+	case 17:
+		m.paramsInput, cmd = m.paramsInput.Update(msg)
+	}
+	return cmd
+}
+
 func (m *Model) focusField(idx int) {
 	switch idx {
 	case 0:
@@ -923,6 +1196,18 @@ func (m *Model) focusField(idx int) {
 		m.sshKeyInput.Focus()
 	case 11:
 		m.sshPasswordInput.Focus()
+	case 12:
+		m.credentialsInput.Focus()
+	case 13:
+		m.colorInput.Focus()
+	case 14:
+		m.sessionInput.Focus()
+	case 15:
+		m.groupInput.Focus()
+	case 16:
+		m.labelInput.Focus()
+	case 17:
+		m.paramsInput.Focus()
 	}
 }
 
@@ -952,6 +1237,18 @@ func (m *Model) blurField(idx int) {
 		m.sshKeyInput.Blur()
 	case 11:
 		m.sshPasswordInput.Blur()
+	case 12:
+		m.credentialsInput.Blur()
+	case 13:
+		m.colorInput.Blur()
+	case 14:
+		m.sessionInput.Blur()
+	case 15:
+		m.groupInput.Blur()
+	case 16:
+		m.labelInput.Blur()
+	case 17:
+		m.paramsInput.Blur()
 	}
 }
 
@@ -968,6 +1265,12 @@ func (m *Model) clearInputs() {
 	m.sshUserInput.SetValue("")
 	m.sshKeyInput.SetValue("")
 	m.sshPasswordInput.SetValue("")
+	m.credentialsInput.SetValue("")
+	m.colorInput.SetValue("")
+	m.sessionInput.SetValue("")
+	m.groupInput.SetValue("")
+	m.labelInput.SetValue("")
+	m.paramsInput.SetValue("")
 }
 
 func (m *Model) populateInputs(p *Profile) {
@@ -983,13 +1286,20 @@ func (m *Model) populateInputs(p *Profile) {
 	m.sshUserInput.SetValue(p.SSHUser)
 	m.sshKeyInput.SetValue(p.SSHKeyPath)
 	m.sshPasswordInput.SetValue(p.SSHPassword)
+	m.credentialsInput.SetValue(p.CredentialsFile)
+	m.colorInput.SetValue(p.Color)
+	m.sessionInput.SetValue(strings.Join(p.SessionInit, "; "))
+	m.groupInput.SetValue(p.Group)
+	m.labelInput.SetValue(p.Label)
+	m.paramsInput.SetValue(p.Params)
 }
 
 func limitString(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	width := ansi.StringWidth(s)
+	if width <= maxLen {
 		return s
 	}
 	// relace middle with ...
 	half := (maxLen - 3) / 2
-	return s[:half] + "..." + s[len(s)-half:]
+	return ansi.Truncate(s, half, "") + "..." + ansi.TruncateLeft(s, width-half, "")
 }
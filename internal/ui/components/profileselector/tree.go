@@ -0,0 +1,328 @@
+package profileselector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// row is a line of the profile list: a folder or a profile
+type row struct {
+	profile int    // Index into the profiles, -1 for folders
+	folder  string // Full path of a folder, e.g. "clients/acme"
+	name    string // Last element of a folder's path
+	depth   int
+	count   int // Profiles inside a folder, nested ones included
+}
+
+// isFolder reports whether the row is a folder
+func (r row) isFolder() bool {
+	return r.profile < 0
+}
+
+// groupPath splits a profile group such as "clients/acme" into its folders
+func groupPath(group string) []string {
+	var path []string
+	for _, f := range strings.Split(group, "/") {
+		if f = strings.TrimSpace(f); f != "" {
+			path = append(path, f)
+		}
+	}
+	return path
+}
+
+// sortOrder returns the indexes of profiles, most recently used first or
+// by name; profiles never used keep their configured order at the end
+func sortOrder(profiles []Profile, byName bool) []int {
+	order := make([]int, len(profiles))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := profiles[order[i]], profiles[order[j]]
+		if byName {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+		return a.LastUsed.After(b.LastUsed)
+	})
+	return order
+}
+
+// buildRows lays the profiles out in order as a tree of their groups.
+// Folders sit where their first profile would; collapsed folders hide
+// their contents.
+func buildRows(profiles []Profile, order []int, collapsed map[string]bool) []row {
+	type node struct {
+		row
+		children []*node
+	}
+	root := &node{}
+	for _, i := range order {
+		p := profiles[i]
+		n := root
+		path := ""
+		for depth, name := range groupPath(p.Group) {
+			path = strings.TrimPrefix(path+"/"+name, "/")
+			var child *node
+			for _, c := range n.children {
+				if c.isFolder() && c.name == name {
+					child = c
+					break
+				}
+			}
+			if child == nil {
+				child = &node{row: row{profile: -1, folder: path, name: name, depth: depth}}
+				n.children = append(n.children, child)
+			}
+			child.count++
+			n = child
+		}
+		n.children = append(n.children, &node{row: row{profile: i, depth: len(groupPath(p.Group))}})
+	}
+
+	var rows []row
+	var walk func(n *node)
+	walk = func(n *node) {
+		for _, c := range n.children {
+			rows = append(rows, c.row)
+			if c.isFolder() && !collapsed[c.folder] {
+				walk(c)
+			}
+		}
+	}
+	walk(root)
+	return rows
+}
+
+// searchRows returns the profiles whose group path and name match query,
+// best match first
+func searchRows(profiles []Profile, query string) []row {
+	type match struct {
+		index, score int
+	}
+	var matches []match
+	for i, p := range profiles {
+		score := fuzzyScore(query, p.Name)
+		if full := fuzzyScore(query, strings.Join(append(groupPath(p.Group), p.Name), "/")); full > score {
+			score = full
+		}
+		if score >= 0 {
+			matches = append(matches, match{i, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	rows := make([]row, len(matches))
+	for i, m := range matches {
+		rows[i] = row{profile: m.index}
+	}
+	return rows
+}
+
+// fuzzyScore returns how well query matches s as a case-insensitive
+// subsequence, or -1 when it does not. Consecutive characters and
+// characters starting a word score higher.
+func fuzzyScore(query, s string) int {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0
+	}
+	target := []rune(s)
+	score, qi, run := 0, 0, 0
+	for i, r := range target {
+		if qi == len(q) {
+			break
+		}
+		if unicode.ToLower(r) != q[qi] {
+			run = 0
+			continue
+		}
+		qi++
+		run++
+		score += run
+		if i == 0 || !unicode.IsLetter(target[i-1]) && !unicode.IsDigit(target[i-1]) || unicode.IsUpper(r) && unicode.IsLower(target[i-1]) {
+			score += 3
+		}
+	}
+	if qi < len(q) {
+		return -1
+	}
+	// Prefer shorter names among equal matches
+	return score*100 - len(target)
+}
+
+// Lines of the selection view around the list, and of the logo within them
+const (
+	listChrome   = 26
+	logoLines    = 10
+	minListLines = 12
+)
+
+// listWindow returns the range of rows that fits in budget lines while
+// keeping the cursor row in view; profiles take two lines, folders one.
+// A negative budget fits every row.
+func listWindow(rows []row, cursor, budget int) (first, last int) {
+	height := func(r row) int {
+		if r.isFolder() {
+			return 1
+		}
+		return 2
+	}
+	total := 0
+	for _, r := range rows {
+		total += height(r)
+	}
+	if budget < 0 || total <= budget {
+		return 0, len(rows)
+	}
+
+	budget -= 2 // "more" markers above and below
+	used := 0
+	for i := 0; i <= cursor && i < len(rows); i++ {
+		used += height(rows[i])
+		for used > budget && first < i {
+			used -= height(rows[first])
+			first++
+		}
+	}
+	last = first
+	for used = 0; last < len(rows) && used+height(rows[last]) <= budget; last++ {
+		used += height(rows[last])
+	}
+	if last <= cursor && cursor < len(rows) {
+		last = cursor + 1
+	}
+	return first, last
+}
+
+// rows returns the visible lines of the profile list: the matches of the
+// search query while there is one, otherwise the folder tree
+func (m Model) rows() []row {
+	if query := strings.TrimSpace(m.searchInput.Value()); query != "" {
+		return searchRows(m.profiles, query)
+	}
+	return buildRows(m.profiles, sortOrder(m.profiles, m.byName), m.collapsed)
+}
+
+// moveCursor puts the cursor on rows[i], selecting the profile there
+func (m *Model) moveCursor(rows []row, i int) {
+	if i >= len(rows) {
+		i = len(rows) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	m.cursor = i
+	if i < len(rows) && !rows[i].isFolder() {
+		m.selected = rows[i].profile
+	}
+}
+
+// syncCursor puts the cursor on the selected profile, or on the folder
+// it is collapsed into
+func (m *Model) syncCursor() {
+	rows := m.rows()
+	target := -1
+	for i, r := range rows {
+		if r.profile == m.selected {
+			m.moveCursor(rows, i)
+			return
+		}
+		if r.isFolder() && m.selected < len(m.profiles) {
+			group := strings.Join(groupPath(m.profiles[m.selected].Group), "/")
+			if group == r.folder || strings.HasPrefix(group, r.folder+"/") {
+				target = i
+			}
+		}
+	}
+	m.moveCursor(rows, target)
+}
+
+// fold collapses the folder under the cursor, or moves to the folder
+// holding the row
+func (m *Model) fold(rows []row) {
+	if m.cursor >= len(rows) || m.searchInput.Value() != "" {
+		return
+	}
+	r := rows[m.cursor]
+	if r.isFolder() && !m.collapsed[r.folder] {
+		m.collapsed[r.folder] = true
+		return
+	}
+	parent := strings.Join(groupPath(m.profiles[m.selected].Group), "/")
+	if r.isFolder() {
+		parent = ""
+		if i := strings.LastIndex(r.folder, "/"); i >= 0 {
+			parent = r.folder[:i]
+		}
+	}
+	for i, f := range rows {
+		if f.isFolder() && f.folder == parent {
+			m.moveCursor(rows, i)
+			return
+		}
+	}
+}
+
+// unfold expands the folder under the cursor
+func (m *Model) unfold(rows []row) {
+	if m.cursor < len(rows) && rows[m.cursor].isFolder() {
+		delete(m.collapsed, rows[m.cursor].folder)
+	}
+}
+
+// clearSearch leaves search, showing the whole tree again
+func (m *Model) clearSearch() {
+	m.searching = false
+	m.searchInput.Blur()
+	m.searchInput.SetValue("")
+	m.syncCursor()
+}
+
+// updateSearch handles keys while typing a search query
+func (m Model) updateSearch(msg tea.KeyMsg) (Model, tea.Cmd) {
+	rows := m.rows()
+	switch msg.String() {
+	case "esc":
+		m.clearSearch()
+		return m, nil
+	case "up", "ctrl+p":
+		m.moveCursor(rows, m.cursor-1)
+		return m, nil
+	case "down", "ctrl+n":
+		m.moveCursor(rows, m.cursor+1)
+		return m, nil
+	case "enter":
+		if len(rows) == 0 {
+			return m, nil
+		}
+		m.searching = false
+		m.searchInput.Blur()
+		return m.Connect()
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	m.moveCursor(m.rows(), 0)
+	return m, cmd
+}
+
+// ago describes how long before now t was, e.g. "2h ago"
+func ago(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	}
+	return t.Format("2006-01-02")
+}
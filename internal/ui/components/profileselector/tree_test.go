@@ -0,0 +1,103 @@
+package profileselector
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBuildRows(t *testing.T) {
+	profiles := []Profile{
+		{Name: "local"},
+		{Name: "acme-prod", Group: "clients/acme"},
+		{Name: "orders", Group: "prod"},
+		{Name: "acme-staging", Group: "/clients/acme/"},
+	}
+
+	order := []int{0, 1, 2, 3}
+	rows := buildRows(profiles, order, map[string]bool{})
+	want := []struct {
+		folder  string
+		profile int
+		depth   int
+	}{
+		{"", 0, 0},
+		{"clients", -1, 0},
+		{"clients/acme", -1, 1},
+		{"", 1, 2},
+		{"", 3, 2},
+		{"prod", -1, 0},
+		{"", 2, 1},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(rows), len(want), rows)
+	}
+	for i, w := range want {
+		if r := rows[i]; r.folder != w.folder || r.profile != w.profile || r.depth != w.depth {
+			t.Errorf("row %d = %+v, want %+v", i, r, w)
+		}
+	}
+	if rows[1].count != 2 {
+		t.Errorf("clients count = %d, want 2", rows[1].count)
+	}
+
+	rows = buildRows(profiles, order, map[string]bool{"clients": true})
+	if len(rows) != 4 || rows[1].folder != "clients" || rows[2].folder != "prod" {
+		t.Errorf("collapsed rows = %+v", rows)
+	}
+}
+
+func TestSortOrder(t *testing.T) {
+	now := time.Now()
+	profiles := []Profile{
+		{Name: "beta"},
+		{Name: "Alpha", LastUsed: now.Add(-time.Hour)},
+		{Name: "gamma"},
+		{Name: "delta", LastUsed: now},
+	}
+	if got := sortOrder(profiles, false); fmt.Sprint(got) != "[3 1 0 2]" {
+		t.Errorf("by recency = %v, want [3 1 0 2]", got)
+	}
+	if got := sortOrder(profiles, true); fmt.Sprint(got) != "[1 0 3 2]" {
+		t.Errorf("by name = %v, want [1 0 3 2]", got)
+	}
+	if got := ago(now.Add(-2*time.Hour-time.Minute), now); got != "2h ago" {
+		t.Errorf("ago = %q, want 2h ago", got)
+	}
+}
+
+func TestSearchRows(t *testing.T) {
+	profiles := []Profile{
+		{Name: "analytics"},
+		{Name: "acme-prod", Group: "clients/acme"},
+		{Name: "orders", Group: "prod"},
+	}
+
+	rows := searchRows(profiles, "acpr")
+	if len(rows) != 1 || rows[0].profile != 1 {
+		t.Errorf("acpr matched %+v, want acme-prod", rows)
+	}
+	rows = searchRows(profiles, "prod")
+	if len(rows) != 2 || rows[0].profile != 1 {
+		t.Errorf("prod matched %+v, want acme-prod first", rows)
+	}
+	if rows := searchRows(profiles, "xyz"); len(rows) != 0 {
+		t.Errorf("xyz matched %+v", rows)
+	}
+}
+
+func TestListWindow(t *testing.T) {
+	rows := make([]row, 10) // Ten profiles, two lines each
+	for i := range rows {
+		rows[i].profile = i
+	}
+	if first, last := listWindow(rows, 9, -1); first != 0 || last != 10 {
+		t.Errorf("unlimited = %d..%d, want 0..10", first, last)
+	}
+	if first, last := listWindow(rows, 0, 10); first != 0 || last != 4 {
+		t.Errorf("top = %d..%d, want 0..4", first, last)
+	}
+	if first, last := listWindow(rows, 9, 10); first != 6 || last != 10 {
+		t.Errorf("bottom = %d..%d, want 6..10", first, last)
+	}
+}
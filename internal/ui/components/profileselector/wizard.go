@@ -0,0 +1,359 @@
+package profileselector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/nhath/ezdb/internal/ui/icons"
+)
+
+// wizardStep is a page of the first-run setup wizard
+type wizardStep int
+
+const (
+	stepType wizardStep = iota
+	stepConnection
+	stepTest
+	stepSave
+)
+
+// wizardTypes are the database types offered by the wizard, in order
+var wizardTypes = []string{"postgres", "mysql", "sqlite", "cassandra", "bigquery"}
+
+// wizardField is a form input asked for on the connection step
+type wizardField struct {
+	idx   int // Form field index, as in focusField
+	label string
+}
+
+// wizardFields returns the connection inputs that matter for dbType
+func wizardFields(dbType string) []wizardField {
+	switch dbType {
+	case "sqlite":
+		return []wizardField{{5, "Path"}}
+	case "bigquery":
+		return []wizardField{{2, "Project"}, {5, "Dataset"}, {12, "Credentials"}}
+	}
+	return []wizardField{{2, "Host"}, {3, "Port"}, {4, "User"}, {5, "Database"}, {6, "Password"}}
+}
+
+// connectionProblem returns what is wrong with the connection settings of
+// p, or "" when they are complete. port is the port as typed.
+func connectionProblem(p Profile, port string) string {
+	switch p.Type {
+	case "sqlite":
+		if p.Database == "" {
+			return "Path to the database file is required"
+		}
+	case "bigquery":
+		if p.Host == "" {
+			return "GCP project is required"
+		}
+	default:
+		if p.Host == "" {
+			return "Host is required"
+		}
+		if port != "" {
+			if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+				return "Port must be a number from 1 to 65535"
+			}
+		}
+	}
+	return ""
+}
+
+// nameProblem returns why name cannot name a new profile, or ""
+func nameProblem(name string, profiles []Profile) string {
+	if name == "" {
+		return "Profile name is required"
+	}
+	for _, p := range profiles {
+		if p.Name == name {
+			return fmt.Sprintf("A profile named %q already exists", name)
+		}
+	}
+	return ""
+}
+
+// suggestedName is the name the save step starts with, e.g. "postgres-localhost"
+func suggestedName(p Profile) string {
+	switch {
+	case p.Type == "sqlite":
+		base := p.Database[strings.LastIndexAny(p.Database, `/\`)+1:]
+		if i := strings.LastIndex(base, "."); i > 0 {
+			base = base[:i]
+		}
+		return base
+	case p.Host != "" && !strings.HasPrefix(p.Host, "/"):
+		return p.Type + "-" + p.Host
+	}
+	return p.Type
+}
+
+// startWizard opens the setup wizard on its first step
+func (m *Model) startWizard() {
+	m.state = StateWizard
+	m.wizardStep = stepType
+	m.wizardType = 0
+	m.clearInputs()
+	m.statusMessage = ""
+}
+
+// Tested records the outcome of a connection test started from the form or the wizard
+func (m Model) Tested(err error) Model {
+	if m.state == StateWizard && m.wizardStep == stepTest {
+		// The test step shows the outcome itself
+		m.wizardTesting = false
+		m.wizardTestErr = err
+		m.statusMessage = ""
+	}
+	return m
+}
+
+// wizardProfile is the profile entered so far
+func (m Model) wizardProfile() Profile {
+	p := m.formValues()
+	p.Type = wizardTypes[m.wizardType]
+	return p
+}
+
+// focusWizardField focuses the i-th connection input of the wizard
+func (m *Model) focusWizardField(i int) {
+	fields := wizardFields(wizardTypes[m.wizardType])
+	m.blurField(m.formFocused)
+	m.formFocused = fields[(i+len(fields))%len(fields)].idx
+	m.focusField(m.formFocused)
+}
+
+// wizardFieldPos returns the position of the focused input among the connection inputs
+func (m Model) wizardFieldPos() int {
+	for i, f := range wizardFields(wizardTypes[m.wizardType]) {
+		if f.idx == m.formFocused {
+			return i
+		}
+	}
+	return 0
+}
+
+// testWizard starts a connection test of the profile entered so far
+func (m Model) testWizard() (Model, tea.Cmd) {
+	m.wizardStep = stepTest
+	m.wizardTesting = true
+	m.wizardTestErr = nil
+	m.statusMessage = ""
+	p := m.wizardProfile()
+	if p.Name == "" {
+		p.Name = suggestedName(p)
+	}
+	return m, func() tea.Msg {
+		return TestConnectionMsg{Profile: p}
+	}
+}
+
+// updateWizard handles keys in the setup wizard
+func (m Model) updateWizard(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch m.wizardStep {
+	case stepType:
+		switch msg.String() {
+		case "up", "k":
+			if m.wizardType > 0 {
+				m.wizardType--
+			}
+		case "down", "j":
+			if m.wizardType < len(wizardTypes)-1 {
+				m.wizardType++
+			}
+		case "enter":
+			m.typeInput.SetValue(wizardTypes[m.wizardType])
+			m.wizardStep = stepConnection
+			m.focusWizardField(0)
+			return m, textinput.Blink
+		case "esc", "q":
+			// Skip setup for the empty profile list
+			m.state = StateSelectingProfile
+		}
+		return m, nil
+
+	case stepConnection:
+		m.statusMessage = ""
+		switch msg.String() {
+		case "tab", "down":
+			m.focusWizardField(m.wizardFieldPos() + 1)
+			return m, nil
+		case "shift+tab", "up":
+			m.focusWizardField(m.wizardFieldPos() - 1)
+			return m, nil
+		case "enter":
+			if problem := connectionProblem(m.wizardProfile(), strings.TrimSpace(m.portInput.Value())); problem != "" {
+				m.statusMessage = "Error: " + problem
+				return m, nil
+			}
+			m.blurField(m.formFocused)
+			return m.testWizard()
+		case "esc":
+			m.blurField(m.formFocused)
+			m.wizardStep = stepType
+			return m, nil
+		}
+		return m, m.updateField(m.formFocused, msg)
+
+	case stepTest:
+		if m.wizardTesting {
+			return m, nil
+		}
+		switch msg.String() {
+		case "enter", "s":
+			if m.wizardTestErr != nil && msg.String() == "enter" {
+				// Fix the settings
+				m.wizardStep = stepConnection
+				m.focusWizardField(0)
+				return m, textinput.Blink
+			}
+			m.wizardStep = stepSave
+			if m.nameInput.Value() == "" {
+				m.nameInput.SetValue(suggestedName(m.wizardProfile()))
+			}
+			m.blurField(m.formFocused)
+			m.formFocused = 0
+			m.nameInput.Focus()
+			m.nameInput.CursorEnd()
+			return m, textinput.Blink
+		case "r":
+			return m.testWizard()
+		case "esc":
+			m.wizardStep = stepConnection
+			m.focusWizardField(0)
+			return m, textinput.Blink
+		}
+		return m, nil
+
+	case stepSave:
+		switch msg.String() {
+		case "enter":
+			p := m.wizardProfile()
+			if problem := nameProblem(p.Name, m.profiles); problem != "" {
+				m.statusMessage = "Error: " + problem
+				return m, nil
+			}
+			m.statusMessage = "Saving..."
+			return m, func() tea.Msg {
+				return ProfileSavedMsg{Profile: p, IsNew: true, Connect: true}
+			}
+		case "esc":
+			m.nameInput.Blur()
+			m.wizardStep = stepTest
+			return m, nil
+		}
+		m.statusMessage = ""
+		var cmd tea.Cmd
+		m.nameInput, cmd = m.nameInput.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// viewWizard renders the setup wizard
+func (m Model) viewWizard(itemWidth int) string {
+	var b strings.Builder
+	center := lipgloss.NewStyle().Width(m.styles.Box.GetWidth() - 4).Align(lipgloss.Center)
+
+	b.WriteString(center.Render(m.styles.Title.Render(" WELCOME TO EZDB ")))
+	b.WriteString("\n")
+	steps := []string{"Type", "Connection", "Test", "Save"}
+	for i, s := range steps {
+		if wizardStep(i) == m.wizardStep {
+			steps[i] = m.styles.HintKey.Render(s)
+		} else {
+			steps[i] = m.styles.Hint.Copy().Margin(0).Render(s)
+		}
+	}
+	b.WriteString(center.Render(strings.Join(steps, m.styles.Hint.Copy().Margin(0).Render(" → "))))
+	b.WriteString("\n\n")
+
+	var hints [][2]string
+	switch m.wizardStep {
+	case stepType:
+		b.WriteString(m.styles.PasswordLabel.Render("No profiles yet. What do you want to connect to?") + "\n\n")
+		for i, t := range wizardTypes {
+			style := m.styles.Item.Copy().Width(itemWidth)
+			prefix := "   "
+			if i == m.wizardType {
+				style = m.styles.Selected.Copy().Width(itemWidth)
+				prefix = " " + icons.IconSelect + " "
+			}
+			b.WriteString(style.Render(prefix+icons.GetDatabaseIcon(t)+" "+t) + "\n")
+		}
+		hints = [][2]string{{"↑↓", "Choose"}, {"Enter", "Next"}, {"Esc", "Skip"}}
+
+	case stepConnection:
+		b.WriteString(m.styles.PasswordLabel.Render("Connection for "+wizardTypes[m.wizardType]) + "\n\n")
+		for _, f := range wizardFields(wizardTypes[m.wizardType]) {
+			prefix := "  "
+			label := m.styles.FieldLabel
+			if f.idx == m.formFocused {
+				prefix = icons.IconPointer
+				label = m.styles.FieldLabelAct
+			}
+			b.WriteString(fmt.Sprintf("%2s %s %s\n", prefix, label.Render(f.label), m.fieldView(f.idx)))
+		}
+		hints = [][2]string{{"Tab", "Next field"}, {"Enter", "Test"}, {"Esc", "Back"}}
+
+	case stepTest:
+		p := m.wizardProfile()
+		switch {
+		case m.wizardTesting:
+			b.WriteString(m.styles.PasswordLabel.Render("Testing connection to "+p.Type+"...") + "\n")
+			hints = [][2]string{{"Ctrl+C", "Quit"}}
+		case m.wizardTestErr != nil:
+			b.WriteString(m.styles.StatusError.Render("Could not connect: "+m.wizardTestErr.Error()) + "\n")
+			hints = [][2]string{{"Enter", "Fix"}, {"r", "Retry"}, {"s", "Save anyway"}}
+		default:
+			b.WriteString(m.styles.StatusSuccess.Render(icons.IconSuccess+" Connection works") + "\n")
+			hints = [][2]string{{"Enter", "Next"}, {"Esc", "Back"}}
+		}
+
+	case stepSave:
+		b.WriteString(m.styles.PasswordLabel.Render("Name the profile") + "\n\n")
+		b.WriteString(fmt.Sprintf("%2s %s %s\n", icons.IconPointer, m.styles.FieldLabelAct.Render("Name"), m.nameInput.View()))
+		hints = [][2]string{{"Enter", "Save and connect"}, {"Esc", "Back"}}
+	}
+
+	var parts []string
+	for _, h := range hints {
+		parts = append(parts, m.styles.HintKey.Copy().Margin(0).Render(h[0])+" "+m.styles.Hint.Copy().Margin(0).Render(h[1]))
+	}
+	b.WriteString("\n")
+	b.WriteString(m.styles.Footer.Copy().
+		Width(itemWidth + 2).
+		MarginTop(1).
+		Render(strings.Join(parts, icons.IconSeparator)))
+	return b.String()
+}
+
+// fieldView renders the form input at idx
+func (m Model) fieldView(idx int) string {
+	switch idx {
+	case 2:
+		return m.hostInput.View()
+	case 3:
+		return m.portInput.View()
+	case 4:
+		return m.userInput.View()
+	case 5:
+		return m.databaseInput.View()
+	case 6:
+		return m.passwordFormInput.View()
+	case 12:
+		return m.credentialsInput.View()
+	}
+	return m.nameInput.View()
+}
@@ -0,0 +1,46 @@
+package profileselector
+
+import "testing"
+
+func TestConnectionProblem(t *testing.T) {
+	tests := []struct {
+		p       Profile
+		port    string
+		problem bool
+	}{
+		{Profile{Type: "postgres", Host: "localhost"}, "", false},
+		{Profile{Type: "postgres", Host: "localhost"}, "5432", false},
+		{Profile{Type: "postgres"}, "", true},
+		{Profile{Type: "mysql", Host: "db"}, "33o6", true},
+		{Profile{Type: "mysql", Host: "db"}, "70000", true},
+		{Profile{Type: "sqlite"}, "", true},
+		{Profile{Type: "sqlite", Database: "app.db"}, "", false},
+		{Profile{Type: "bigquery", Database: "dataset"}, "", true},
+	}
+	for _, tt := range tests {
+		if got := connectionProblem(tt.p, tt.port); (got != "") != tt.problem {
+			t.Errorf("connectionProblem(%+v, %q) = %q", tt.p, tt.port, got)
+		}
+	}
+}
+
+func TestWizardNames(t *testing.T) {
+	profiles := []Profile{{Name: "local"}}
+	if nameProblem("local", profiles) == "" || nameProblem("", profiles) == "" || nameProblem("prod", profiles) != "" {
+		t.Error("nameProblem must reject empty and taken names only")
+	}
+
+	tests := []struct {
+		p    Profile
+		want string
+	}{
+		{Profile{Type: "postgres", Host: "localhost"}, "postgres-localhost"},
+		{Profile{Type: "postgres", Host: "/var/run/postgresql"}, "postgres"},
+		{Profile{Type: "sqlite", Database: "/home/me/data/app.db"}, "app"},
+	}
+	for _, tt := range tests {
+		if got := suggestedName(tt.p); got != tt.want {
+			t.Errorf("suggestedName(%+v) = %q, want %q", tt.p, got, tt.want)
+		}
+	}
+}
@@ -32,6 +32,24 @@ const (
 	TabConstraints
 )
 
+// Keys handled by the browser; the UI's help and hints list them from here
+const (
+	KeyTemplates = "t"
+	KeyExport    = "e"
+	KeyImport    = "o"
+	KeyGenerate  = "g"
+	KeyCopy      = "c"
+	KeyTruncate  = "T"
+	KeyDrop      = "X"
+	KeyRunFile   = "r"
+	KeyBrowse    = "b"
+	KeyPrevTab   = "h"
+	KeyNextTab   = "l"
+)
+
+// tabLabels are the tab captions, indexed by DetailTab
+var tabLabels = []string{" Columns", " Constraints"}
+
 // SchemaLoadedMsg is sent when schema is loaded
 type SchemaLoadedMsg struct {
 	Tables      []string
@@ -55,6 +73,34 @@ type ImportTableMsg struct {
 	TableName string
 }
 
+// GenerateDataMsg is sent when generating rows for a table is requested
+type GenerateDataMsg struct {
+	TableName string
+}
+
+// TruncateTableMsg is sent when truncating a table is requested
+type TruncateTableMsg struct {
+	TableName string
+}
+
+// DropTableMsg is sent when dropping a table is requested
+type DropTableMsg struct {
+	TableName string
+}
+
+// CopyTableMsg is sent when copying a table to another profile is requested
+type CopyTableMsg struct {
+	TableName string
+}
+
+// RunFileMsg is sent when running a .sql file is requested
+type RunFileMsg struct{}
+
+// BrowseTableMsg is sent when browsing a table's data is requested
+type BrowseTableMsg struct {
+	TableName string
+}
+
 // Styles for the browser
 type Styles struct {
 	Container     lipgloss.Style
@@ -137,11 +183,13 @@ type Model struct {
 	height           int
 	activeTab        DetailTab
 	styles           Styles
+	tableBuilder     eztable.Builder
 	viewport         viewport.Model
 	spinner          spinner.Model
 	columnsTable     table.Model
 	constraintsTable table.Model
 	loading          bool
+	docked           bool // Fill the given size as a sidebar instead of a centered popup
 }
 
 // New creates a new schema browser
@@ -168,16 +216,14 @@ func (m Model) SetSize(w, h int) Model {
 	return m.updateViewportDimensions()
 }
 
+// SetDocked switches between the centered popup and the docked sidebar layout
+func (m Model) SetDocked(docked bool) Model {
+	m.docked = docked
+	return m.updateViewportDimensions()
+}
+
 func (m Model) updateViewportDimensions() Model {
-	// Calculate popup size
-	popupWidth := int(float64(m.width) * 0.9)
-	if popupWidth > 100 {
-		popupWidth = 100
-	}
-	popupHeight := int(float64(m.height) * 0.8)
-	if popupHeight > 35 {
-		popupHeight = 35
-	}
+	popupWidth, popupHeight := m.getPopupSize()
 
 	m.viewport.Width = popupWidth - 6
 	if m.state == StateColumns {
@@ -203,6 +249,37 @@ func (m Model) IsVisible() bool {
 	return m.visible
 }
 
+// IsLoading reports whether the schema is being loaded
+func (m Model) IsLoading() bool {
+	return m.loading
+}
+
+// CurrentTable returns the highlighted or opened table, or "" if none
+func (m Model) CurrentTable() string {
+	if m.state == StateTables && len(m.tables) > 0 {
+		return m.tables[m.selectedIdx]
+	}
+	if m.state == StateColumns {
+		return m.selectedTable
+	}
+	return ""
+}
+
+// SelectTable highlights table in the table list, if it is there
+func (m Model) SelectTable(table string) Model {
+	for i, t := range m.tables {
+		if t == table {
+			m.state = StateTables
+			m.selectedIdx = i
+			m = m.updateViewportDimensions()
+			m = m.ensureSelectionVisible()
+			m.viewport.SetContent(m.renderContent())
+			break
+		}
+	}
+	return m
+}
+
 // StartLoading begins loading state
 func (m Model) StartLoading() (Model, tea.Cmd) {
 	m.loading = true
@@ -280,6 +357,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, cmd
 		}
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "up", "k":
@@ -304,19 +384,15 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				m.viewport.LineDown(1)
 				return m, nil
 			}
-		case "left", "h":
-			if m.state == StateColumns {
-				m.activeTab = TabColumns
-				m.viewport.YOffset = 0
-				m.viewport.SetContent(m.renderContent())
+		case "left", KeyPrevTab:
+			if m.state == StateColumns && m.activeTab > TabColumns {
+				m = m.selectTab(m.activeTab - 1)
 			}
-		case "right", "l":
-			if m.state == StateColumns {
-				m.activeTab = TabConstraints
-				m.viewport.YOffset = 0
-				m.viewport.SetContent(m.renderContent())
+		case "right", KeyNextTab:
+			if m.state == StateColumns && int(m.activeTab) < len(tabLabels)-1 {
+				m = m.selectTab(m.activeTab + 1)
 			}
-		case "t": // Template quick query
+		case KeyTemplates:
 			var tableName string
 			if m.state == StateTables && len(m.tables) > 0 {
 				tableName = m.tables[m.selectedIdx]
@@ -330,13 +406,8 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					return TableSelectedMsg{TableName: tableName}
 				}
 			}
-		case "e": // Export table
-			var tableName string
-			if m.state == StateTables && len(m.tables) > 0 {
-				tableName = m.tables[m.selectedIdx]
-			} else if m.state == StateColumns {
-				tableName = m.selectedTable
-			}
+		case KeyExport:
+			tableName := m.CurrentTable()
 
 			if tableName != "" {
 				m.visible = false
@@ -344,13 +415,8 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					return ExportTableMsg{TableName: tableName}
 				}
 			}
-		case "o": // Import (open) data into table
-			var tableName string
-			if m.state == StateTables && len(m.tables) > 0 {
-				tableName = m.tables[m.selectedIdx]
-			} else if m.state == StateColumns {
-				tableName = m.selectedTable
-			}
+		case KeyImport:
+			tableName := m.CurrentTable()
 
 			if tableName != "" {
 				m.visible = false
@@ -358,6 +424,56 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					return ImportTableMsg{TableName: tableName}
 				}
 			}
+		case KeyGenerate:
+			tableName := m.CurrentTable()
+
+			if tableName != "" {
+				m.visible = false
+				return m, func() tea.Msg {
+					return GenerateDataMsg{TableName: tableName}
+				}
+			}
+		case KeyCopy:
+			tableName := m.CurrentTable()
+
+			if tableName != "" {
+				m.visible = false
+				return m, func() tea.Msg {
+					return CopyTableMsg{TableName: tableName}
+				}
+			}
+		case KeyTruncate:
+			tableName := m.CurrentTable()
+
+			if tableName != "" {
+				m.visible = false
+				return m, func() tea.Msg {
+					return TruncateTableMsg{TableName: tableName}
+				}
+			}
+		case KeyDrop:
+			tableName := m.CurrentTable()
+
+			if tableName != "" {
+				m.visible = false
+				return m, func() tea.Msg {
+					return DropTableMsg{TableName: tableName}
+				}
+			}
+		case KeyRunFile:
+			m.visible = false
+			return m, func() tea.Msg {
+				return RunFileMsg{}
+			}
+		case KeyBrowse:
+			tableName := m.CurrentTable()
+
+			if tableName != "" {
+				m.visible = false
+				return m, func() tea.Msg {
+					return BrowseTableMsg{TableName: tableName}
+				}
+			}
 		case "enter":
 			if m.state == StateTables && len(m.tables) > 0 {
 				m.selectedTable = m.tables[m.selectedIdx]
@@ -366,8 +482,8 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				m.activeTab = TabColumns
 				m.viewport.YOffset = 0
 				// Initialize rich tables - non-paginated and unfocused for viewport scrolling
-				m.columnsTable = eztable.FromSchemaColumns(m.columns[m.selectedTable]).WithNoPagination().Focused(false)
-				m.constraintsTable = eztable.FromConstraints(m.constraints[m.selectedTable]).WithNoPagination().Focused(false)
+				m.columnsTable = m.tableBuilder.FromSchemaColumns(m.columns[m.selectedTable]).WithNoPagination().Focused(false)
+				m.constraintsTable = m.tableBuilder.FromConstraints(m.constraints[m.selectedTable]).WithNoPagination().Focused(false)
 
 				// Synchronize viewport dimensions and content immediately
 				m = m.updateViewportDimensions()
@@ -408,6 +524,75 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// handleMouse maps wheel scrolling and clicks on the browser to
+// the equivalent key actions: click a table to select it, click it again
+// to open it, click a tab to switch to it.
+func (m Model) handleMouse(msg tea.MouseMsg) (Model, tea.Cmd) {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	case tea.MouseButtonWheelDown:
+		return m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	if msg.Button != tea.MouseButtonLeft || msg.Action != tea.MouseActionPress || m.loading {
+		return m, nil
+	}
+
+	// Docked sidebars sit at the top-left corner, popups are centered
+	var x0, y0 int
+	if !m.docked {
+		view := m.View()
+		x0 = (m.width - lipgloss.Width(view)) / 2
+		y0 = (m.height - lipgloss.Height(view)) / 2
+	}
+	x := msg.X - x0 -
+		m.styles.Container.GetBorderLeftSize() - m.styles.Container.GetPaddingLeft()
+	y := msg.Y - y0 -
+		m.styles.Container.GetBorderTopSize() - m.styles.Container.GetPaddingTop() -
+		lipgloss.Height(m.styles.Title.Render(" Tables"))
+
+	if m.state == StateColumns {
+		if y < 0 || y >= lipgloss.Height(m.tabStyle(TabColumns).Render(tabLabels[TabColumns])) {
+			return m, nil
+		}
+		left := 0
+		for i, label := range tabLabels {
+			w := lipgloss.Width(m.tabStyle(DetailTab(i)).Render(label))
+			if x >= left && x < left+w {
+				return m.selectTab(DetailTab(i)), nil
+			}
+			left += w
+		}
+		return m, nil
+	}
+
+	row := m.viewport.YOffset + y
+	if y < 0 || y >= m.viewport.Height || row >= len(m.tables) {
+		return m, nil
+	}
+	if row == m.selectedIdx {
+		return m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	m.selectedIdx = row
+	return m, nil
+}
+
+// selectTab switches the detail view to tab and scrolls to its top
+func (m Model) selectTab(tab DetailTab) Model {
+	m.activeTab = tab
+	m.viewport.YOffset = 0
+	m.viewport.SetContent(m.renderContent())
+	return m
+}
+
+// tabStyle returns the style a tab is rendered with
+func (m Model) tabStyle(tab DetailTab) lipgloss.Style {
+	if m.activeTab == tab {
+		return m.styles.TabActive
+	}
+	return m.styles.TabInactive
+}
+
 func (m Model) ensureSelectionVisible() Model {
 	if m.viewport.Height <= 0 {
 		return m
@@ -427,6 +612,12 @@ func (m Model) SetStyles(s Styles) Model {
 	return m
 }
 
+// SetTableBuilder sets how the column and constraint tables are built
+func (m Model) SetTableBuilder(b eztable.Builder) Model {
+	m.tableBuilder = b
+	return m
+}
+
 // View renders the browser popup
 func (m Model) View() string {
 	if !m.visible && !m.loading {
@@ -436,9 +627,13 @@ func (m Model) View() string {
 	var view strings.Builder
 
 	if m.loading {
+		width, height := 40, 5
+		if m.docked {
+			width, height = m.getPopupSize()
+		}
 		return m.styles.Container.
-			Width(40).
-			Height(5).
+			Width(width).
+			Height(height).
 			Render(fmt.Sprintf("\n  %s Loading Schema...", m.spinner.View()))
 	}
 
@@ -454,17 +649,10 @@ func (m Model) View() string {
 	m = m.updateViewportDimensions()
 	if m.state == StateColumns {
 		// Render tabs
-		tabs := []string{}
-		colStyle := m.styles.TabInactive
-		if m.activeTab == TabColumns {
-			colStyle = m.styles.TabActive
+		tabs := make([]string, len(tabLabels))
+		for i, label := range tabLabels {
+			tabs[i] = m.tabStyle(DetailTab(i)).Render(label)
 		}
-		tabs = append(tabs, colStyle.Render(" Columns"))
-		conStyle := m.styles.TabInactive
-		if m.activeTab == TabConstraints {
-			conStyle = m.styles.TabActive
-		}
-		tabs = append(tabs, conStyle.Render(" Constraints"))
 
 		view.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
 		view.WriteString("\n\n")
@@ -476,7 +664,9 @@ func (m Model) View() string {
 
 	// Help footer
 	view.WriteString("\n")
-	view.WriteString(lipgloss.NewStyle().Faint(true).Render("enter: details • t: template • e: export • o: import • ?: help"))
+	footer := []string{"enter: details", KeyBrowse + ": browse", KeyTemplates + ": template", KeyExport + ": export", KeyImport + ": import",
+		KeyGenerate + ": generate", KeyCopy + ": copy", KeyTruncate + ": truncate", KeyDrop + ": drop", KeyRunFile + ": run file", "?: help"}
+	view.WriteString(lipgloss.NewStyle().Faint(true).Render(strings.Join(footer, " • ")))
 	if m.state == StateColumns {
 		view.WriteString(lipgloss.NewStyle().Faint(true).Render(" • l/h: tabs • esc: back"))
 	} else {
@@ -490,6 +680,10 @@ func (m Model) View() string {
 }
 
 func (m Model) getPopupSize() (int, int) {
+	if m.docked {
+		// Fill the sidebar; the container border adds one cell on each side
+		return m.width - 2, m.height - 2
+	}
 	popupWidth := int(float64(m.width) * 0.9)
 	if popupWidth > 100 {
 		popupWidth = 100
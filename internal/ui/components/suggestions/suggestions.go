@@ -27,7 +27,7 @@ func DefaultStyles(theme config.Theme) Styles {
 		Item: lipgloss.NewStyle().
 			Foreground(lipgloss.Color(theme.TextPrimary)),
 		Selected: lipgloss.NewStyle().
-			Foreground(lipgloss.Color(theme.BgPrimary)).
+			Foreground(lipgloss.Color(config.ReadableOn(theme.Highlight, theme.BgPrimary, theme.TextPrimary))).
 			Background(lipgloss.Color(theme.Highlight)),
 		Loading: lipgloss.NewStyle().
 			Foreground(lipgloss.Color(theme.TextFaint)).
@@ -0,0 +1,82 @@
+package table
+
+import (
+	"math/big"
+	"strings"
+)
+
+// NumberFormat lays out numeric cells with grouped thousands and a fixed
+// number of decimals
+type NumberFormat struct {
+	Thousands string // Between groups of three integer digits, e.g. "," or "."
+	Decimal   string // Decimal separator, e.g. "." or ","
+	Decimals  int    // Digits after the separator of fractional values, 0 keeps each value's own
+}
+
+// isNumber reports whether val is a plain decimal number such as "-1234.5".
+// Values with a leading zero, like "0042", are codes rather than amounts.
+func isNumber(val string) bool {
+	digits := strings.TrimPrefix(val, "-")
+	intPart, frac, hasFrac := strings.Cut(digits, ".")
+	if intPart == "" || (hasFrac && frac == "") || (len(intPart) > 1 && intPart[0] == '0') {
+		return false
+	}
+	for _, part := range []string{intPart, frac} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Format returns val laid out with f, or val itself when it is not a number
+func (f NumberFormat) Format(val string) string {
+	if !isNumber(val) {
+		return val
+	}
+	if f.Decimals > 0 && strings.Contains(val, ".") {
+		// Rounded exactly, since NUMERIC values can outgrow a float64
+		if r, ok := new(big.Rat).SetString(val); ok {
+			val = r.FloatString(f.Decimals)
+		}
+	}
+	sign := ""
+	if strings.HasPrefix(val, "-") {
+		sign, val = "-", val[1:]
+	}
+	intPart, frac, hasFrac := strings.Cut(val, ".")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(f.Thousands)
+		}
+		b.WriteRune(r)
+	}
+	if hasFrac {
+		b.WriteString(f.Decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// numericColumns reports which columns hold numbers in every non-NULL value
+func numericColumns(columns []string, rows [][]string) []bool {
+	numeric := make([]bool, len(columns))
+	for i := range columns {
+		for _, row := range rows {
+			if i >= len(row) || row[i] == "" || row[i] == "NULL" {
+				continue
+			}
+			if !isNumber(row[i]) {
+				numeric[i] = false
+				break
+			}
+			numeric[i] = true
+		}
+	}
+	return numeric
+}
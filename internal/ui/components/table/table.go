@@ -5,33 +5,68 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	bbtable "github.com/evertras/bubble-table/table"
 	"github.com/nhath/ezdb/internal/config"
 	"github.com/nhath/ezdb/internal/db"
 )
 
-var (
-	currentTheme config.Theme
-	currentKeys  config.KeyMap
-)
+// Builder builds tables colored by one theme and navigated with one keymap
+type Builder struct {
+	theme     config.Theme
+	keys      config.KeyMap
+	numbers   *NumberFormat // Layout of numeric result cells, nil to show them raw
+	fullWidth string        // Result column shown at its natural width rather than capped
+	maxWidth  int           // Width of the whole table fullWidth must fit in
+	frozen    int           // Leading columns shown beside fullWidth
+}
+
+// maxColumnWidth caps result columns other than the full width one
+const maxColumnWidth = 50
+
+// Cell is a result value shown differently from how the database returned
+// it: formatted as a number or cut to its column. Raw keeps the value for
+// copies and row actions.
+type Cell struct {
+	Raw   string
+	Shown string
+	Cut   bool // Shown ends in the truncation marker
+}
+
+// String returns the value as shown, which is what the table renders and filters on
+func (c Cell) String() string { return c.Shown }
 
-// Init initializes the table component with theme and keys
-func Init(t config.Theme, k config.KeyMap) {
-	currentTheme = t
-	currentKeys = k
+// NewBuilder returns a Builder for theme and keys
+func NewBuilder(t config.Theme, k config.KeyMap) Builder {
+	return Builder{theme: t.Readable(), keys: k}
 }
 
-// New creates a new bubble-table with Nord theme (no background)
-func New(cols []bbtable.Column) bbtable.Model {
+// WithNumberFormat returns a Builder laying out the numeric cells of query
+// results with f, or showing them raw when f is nil
+func (b Builder) WithNumberFormat(f *NumberFormat) Builder {
+	b.numbers = f
+	return b
+}
+
+// WithFullWidth returns a Builder showing result column at its natural
+// width, as far as it fits in a table maxWidth wide beside the frozen
+// leading columns, or capping every column when column is empty
+func (b Builder) WithFullWidth(column string, maxWidth, frozen int) Builder {
+	b.fullWidth, b.maxWidth, b.frozen = column, maxWidth, frozen
+	return b
+}
+
+// New creates a new bubble-table in the builder's theme (no background)
+func (b Builder) New(cols []bbtable.Column) bbtable.Model {
 	return bbtable.New(cols).
 		WithBaseStyle(lipgloss.NewStyle().
-			Foreground(lipgloss.Color(currentTheme.TextPrimary)).
-			BorderForeground(lipgloss.Color(currentTheme.BorderColor))).
+			Foreground(lipgloss.Color(b.theme.TextPrimary)).
+			BorderForeground(lipgloss.Color(b.theme.BorderColor))).
 		HeaderStyle(lipgloss.NewStyle().
-			Foreground(lipgloss.Color(currentTheme.Highlight)).
+			Foreground(lipgloss.Color(b.theme.Highlight)).
 			Bold(true)).
 		HighlightStyle(lipgloss.NewStyle().
-			Foreground(lipgloss.Color(currentTheme.Success)).
+			Foreground(lipgloss.Color(b.theme.Success)).
 			Bold(true)).
 		Focused(true).
 		BorderRounded()
@@ -39,50 +74,86 @@ func New(cols []bbtable.Column) bbtable.Model {
 
 // FromQueryResult builds a table from a QueryResult with type-specific coloring
 // maxWidth parameter is kept for API compatibility but not used - table expands to content width
-func FromQueryResult(res *db.QueryResult, maxWidth int) bbtable.Model {
+func (b Builder) FromQueryResult(res *db.QueryResult, maxWidth int) bbtable.Model {
 	if res == nil {
 		return bbtable.New(nil)
 	}
 
-	widths := calculateColumnWidths(res.Columns, res.Rows)
+	shown := res.Rows
+	var numeric []bool
+	if b.numbers != nil {
+		numeric = numericColumns(res.Columns, res.Rows)
+		shown = make([][]string, len(res.Rows))
+		for i, r := range res.Rows {
+			shown[i] = make([]string, len(r))
+			for j, val := range r {
+				shown[i][j] = val
+				if j < len(numeric) && numeric[j] {
+					shown[i][j] = b.numbers.Format(val)
+				}
+			}
+		}
+	}
+	widths := calculateColumnWidths(res.Columns, shown)
 
 	var cols []bbtable.Column
-	for _, c := range res.Columns {
+	colWidths := make([]int, len(res.Columns))
+	for i, c := range res.Columns {
 		w := widths[c]
-		if w > 50 {
-			w = 50 // Cap max width per column for very long content
+		switch {
+		case c == b.fullWidth:
+			room := b.maxWidth - 2 // Outer borders
+			for _, fw := range colWidths[:min(b.frozen, i)] {
+				room -= fw + 1 // Frozen column and its separator
+			}
+			w = min(w, room)
+		case w > maxColumnWidth:
+			w = maxColumnWidth // Cap max width per column for very long content
 		}
 		if w < 6 {
 			w = 6 // Minimum width for readability
 		}
+		colWidths[i] = w
 		// Make columns filterable and sortable
 		cols = append(cols, bbtable.NewColumn(c, c, w).
 			WithFiltered(true))
 	}
 
+	marker := lipgloss.NewStyle().Foreground(lipgloss.Color(b.theme.Warning)).Render("…")
 	var rows []bbtable.Row
-	for _, r := range res.Rows {
+	for ri, r := range res.Rows {
 		rowData := bbtable.RowData{}
 		for i, val := range r {
-			rowData[res.Columns[i]] = bbtable.NewStyledCell(val, GetValueStyle(val))
+			if i >= len(res.Columns) {
+				break
+			}
+			style := b.ValueStyle(val)
+			if numeric != nil && numeric[i] && isNumber(val) {
+				style = style.Align(lipgloss.Right)
+			}
+			text, cut := cutCell(shown[ri][i], colWidths[i], marker)
+			if text == val {
+				rowData[res.Columns[i]] = bbtable.NewStyledCell(val, style)
+			} else {
+				rowData[res.Columns[i]] = bbtable.NewStyledCell(Cell{Raw: val, Shown: text, Cut: cut}, style)
+			}
 		}
 		rows = append(rows, bbtable.NewRow(rowData))
 	}
 
-	// Custom key map for better navigation
 	// Custom key map for better navigation
 	keys := bbtable.DefaultKeyMap()
-	if len(currentKeys.NextPage) > 0 { // Check if initialized
-		keys.RowDown.SetKeys(currentKeys.MoveDown...)
-		keys.RowUp.SetKeys(currentKeys.MoveUp...)
-
-		keys.PageDown.SetKeys(currentKeys.NextPage...)
-		keys.PageUp.SetKeys(currentKeys.PrevPage...)
-		keys.ScrollRight.SetKeys(currentKeys.ScrollRight...)
-		keys.ScrollLeft.SetKeys(currentKeys.ScrollLeft...)
-		keys.Filter.SetKeys(currentKeys.Filter...)
+	if len(b.keys.NextPage) > 0 { // Unset in a zero Builder
+		keys.RowDown.SetKeys(b.keys.MoveDown...)
+		keys.RowUp.SetKeys(b.keys.MoveUp...)
+
+		keys.PageDown.SetKeys(b.keys.NextPage...)
+		keys.PageUp.SetKeys(b.keys.PrevPage...)
+		keys.ScrollRight.SetKeys(b.keys.ScrollRight...)
+		keys.ScrollLeft.SetKeys(b.keys.ScrollLeft...)
+		keys.Filter.SetKeys(b.keys.Filter...)
 	} else {
-		// Fallback defaults if Init not called (shouldn't happen)
+		// Fallback defaults for a zero Builder
 		keys.RowDown.SetKeys("j", "down")
 		keys.RowUp.SetKeys("k", "up")
 		keys.PageDown.SetKeys("n", "pgdown")
@@ -94,7 +165,7 @@ func FromQueryResult(res *db.QueryResult, maxWidth int) bbtable.Model {
 	// Disable row selection toggle so we can use enter/space for row actions
 	keys.RowSelectToggle.SetKeys()
 
-	return New(cols).
+	return b.New(cols).
 		WithRows(rows).
 		WithPageSize(20).
 		WithMinimumHeight(20). // Fixed height to prevent shrinking on last page
@@ -104,7 +175,7 @@ func FromQueryResult(res *db.QueryResult, maxWidth int) bbtable.Model {
 }
 
 // FromSchemaColumns builds a table for database columns metadata
-func FromSchemaColumns(cols []db.Column) bbtable.Model {
+func (b Builder) FromSchemaColumns(cols []db.Column) bbtable.Model {
 	headers := []string{"Name", "Type", "Null", "Key", "Default"}
 	var rowsData [][]string
 	for _, c := range cols {
@@ -127,16 +198,16 @@ func FromSchemaColumns(cols []db.Column) bbtable.Model {
 			"Name":    rd[0],
 			"Type":    rd[1],
 			"Null":    rd[2],
-			"Key":     bbtable.NewStyledCell(rd[3], lipgloss.NewStyle().Foreground(lipgloss.Color(currentTheme.Warning))),
+			"Key":     bbtable.NewStyledCell(rd[3], lipgloss.NewStyle().Foreground(lipgloss.Color(b.theme.Warning))),
 			"Default": rd[4],
 		}))
 	}
 
-	return New(tableCols).WithRows(rows)
+	return b.New(tableCols).WithRows(rows)
 }
 
 // FromConstraints builds a table for database constraints metadata
-func FromConstraints(constraints []db.Constraint) bbtable.Model {
+func (b Builder) FromConstraints(constraints []db.Constraint) bbtable.Model {
 	headers := []string{"Name", "Type", "Definition"}
 	var rowsData [][]string
 	for _, c := range constraints {
@@ -162,11 +233,11 @@ func FromConstraints(constraints []db.Constraint) bbtable.Model {
 		}))
 	}
 
-	return New(cols).WithRows(rows)
+	return b.New(cols).WithRows(rows)
 }
 
 // FromPreview builds a table from a preview string (columns | columns\nrow | row)
-func FromPreview(preview string) bbtable.Model {
+func (b Builder) FromPreview(preview string) bbtable.Model {
 	lines := strings.Split(preview, "\n")
 	if len(lines) < 2 {
 		return bbtable.New(nil)
@@ -207,7 +278,7 @@ func FromPreview(preview string) bbtable.Model {
 		if len(rds) == 1 && rds[0] == "..." {
 			rowData := bbtable.RowData{}
 			for _, name := range colNames {
-				rowData[name] = lipgloss.NewStyle().Foreground(lipgloss.Color(currentTheme.TextFaint)).Render("...")
+				rowData[name] = lipgloss.NewStyle().Foreground(lipgloss.Color(b.theme.TextFaint)).Render("...")
 			}
 			rows = append(rows, bbtable.NewRow(rowData))
 			continue
@@ -216,26 +287,38 @@ func FromPreview(preview string) bbtable.Model {
 		rowData := bbtable.RowData{}
 		for j, val := range rds {
 			if j < len(colNames) {
-				rowData[colNames[j]] = bbtable.NewStyledCell(val, GetValueStyle(val))
+				rowData[colNames[j]] = bbtable.NewStyledCell(val, b.ValueStyle(val))
 			}
 		}
 		rows = append(rows, bbtable.NewRow(rowData))
 	}
 
-	return New(cols).WithRows(rows).WithNoPagination()
+	return b.New(cols).WithRows(rows).WithNoPagination()
+}
+
+// cutCell cuts text to its first line and to width, ending it with marker
+// when anything was cut
+func cutCell(text string, width int, marker string) (string, bool) {
+	line, _, multiline := strings.Cut(text, "\n")
+	if !multiline && ansi.StringWidth(line) <= width {
+		return text, false
+	}
+	return ansi.Truncate(line, width-1, "") + marker, true
 }
 
+// calculateColumnWidths sizes each column to its widest header or value in
+// terminal cells, so CJK characters and emoji count twice
 func calculateColumnWidths(headers []string, rows [][]string) map[string]int {
 	widths := make(map[string]int)
 	for _, h := range headers {
-		widths[h] = len(h)
+		widths[h] = ansi.StringWidth(h)
 	}
 
 	for _, row := range rows {
 		for i, val := range row {
 			if i < len(headers) {
-				if len(val) > widths[headers[i]] {
-					widths[headers[i]] = len(val)
+				if w := ansi.StringWidth(val); w > widths[headers[i]] {
+					widths[headers[i]] = w
 				}
 			}
 		}
@@ -249,17 +332,17 @@ func calculateColumnWidths(headers []string, rows [][]string) map[string]int {
 	return widths
 }
 
-// GetValueStyle returns a lipgloss style based on value content
-func GetValueStyle(val string) lipgloss.Style {
+// ValueStyle returns a lipgloss style based on value content
+func (b Builder) ValueStyle(val string) lipgloss.Style {
 	if val == "" || strings.ToUpper(val) == "NULL" || val == "<nil>" {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color(currentTheme.TextFaint)).Italic(true)
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(b.theme.TextFaint)).Italic(true)
 	}
 	if _, err := fmt.Sscanf(val, "%f", new(float64)); err == nil {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color(currentTheme.Accent))
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(b.theme.Accent))
 	}
 	lower := strings.ToLower(val)
 	if lower == "true" || lower == "false" {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color(currentTheme.Warning))
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(b.theme.Warning))
 	}
-	return lipgloss.NewStyle().Foreground(lipgloss.Color(currentTheme.TextSecondary))
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(b.theme.TextSecondary))
 }
@@ -0,0 +1,44 @@
+// internal/ui/controllers.go
+// Key controllers: the ordered layers a key press passes through, each owning one context of the UI.
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// keyController handles a key press for one context and reports whether it
+// took the key. Keys it does not take fall through to the next controller.
+type keyController func(m Model, msg tea.KeyMsg) (Model, tea.Cmd, bool)
+
+// keyControllers returns the controllers in the order they see keys.
+// A new mode or popup family adds its controller here.
+func keyControllers() []keyController {
+	return []keyController{
+		Model.profileController,
+		Model.quitController,
+		Model.themeController,
+		Model.handlePopupKeys,
+		Model.globalController,
+		Model.schemaController,
+		Model.editorController,
+	}
+}
+
+// handleKey dispatches a key press, or a completed chord, to the first controller that takes it
+func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	m.statusMsg = "" // clear status on any key
+	for _, c := range keyControllers() {
+		if next, cmd, handled := c(m, msg); handled {
+			return next, cmd
+		}
+	}
+	return m, nil
+}
+
+// editorController hands the key to the current editor mode; it takes every key
+func (m Model) editorController(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if m.mode == InsertMode {
+		m, cmds := m.handleInsertMode(msg, nil)
+		return m, tea.Batch(cmds...), true
+	}
+	model, cmd := m.handleVisualMode(msg)
+	return model.(Model), cmd, true
+}
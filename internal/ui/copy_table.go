@@ -0,0 +1,308 @@
+// internal/ui/copy_table.go
+// Copy table wizard: streams a table's rows into a table of another profile, creating it from mapped DDL if asked.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/ui/components/schemabrowser"
+)
+
+// copyPageSize is how many rows one page read from the source holds
+const copyPageSize = 500
+
+// copyable reports whether rows of the driver type can be copied; the
+// wizard maps types between the SQL dialects only
+func copyable(dt db.DriverType) bool {
+	return dt == db.Postgres || dt == db.MySQL || dt == db.SQLite
+}
+
+// openCopyTablePopup opens the copy wizard for a source table.
+func (m *Model) openCopyTablePopup(tableName string) {
+	if m.showCopyTable {
+		return
+	}
+	m.showCopyTable = true
+	m.autocompleting = false
+	m.copySource = tableName
+	m.copyStep = 0
+	m.copyProfileIdx = 0
+	m.copyCreate = true
+	m.ensureInput(lazyCopyTable, &m.copyInput, newCopyTableInput)
+	m.popupStack.Push("copy table", func(m *Model) {
+		m.showCopyTable = false
+		m.copyInput.Blur()
+		m.copySource = ""
+	})
+}
+
+// handleCopyTableKeys handles keys of the wizard: pick the target profile,
+// then name the target table and start the copy
+func (m Model) handleCopyTableKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if msg.String() == "esc" {
+		m.closeTopPopup()
+		return m, nil, true
+	}
+
+	if m.copyStep == 0 {
+		switch msg.String() {
+		case "up", "k":
+			if m.copyProfileIdx > 0 {
+				m.copyProfileIdx--
+			}
+		case "down", "j":
+			if m.copyProfileIdx < len(m.config.Profiles)-1 {
+				m.copyProfileIdx++
+			}
+		case "enter":
+			if m.copyProfileIdx < len(m.config.Profiles) {
+				m.copyStep = 1
+				m.copyInput.SetValue(m.copySource)
+				m.copyInput.CursorEnd()
+				m.copyInput.Focus()
+			}
+		}
+		return m, nil, true
+	}
+
+	switch msg.String() {
+	case "tab":
+		m.copyCreate = !m.copyCreate
+		return m, nil, true
+	case "shift+tab":
+		m.copyStep = 0
+		m.copyInput.Blur()
+		return m, nil, true
+	case "enter":
+		target := strings.TrimSpace(m.copyInput.Value())
+		if target == "" {
+			return m, nil, true
+		}
+		profile := m.config.Profiles[m.copyProfileIdx]
+		source, create := m.copySource, m.copyCreate
+		m.closeTopPopup()
+		if m.loading {
+			m.errorMsg = "Wait for the running query before copying"
+			return m, nil, true
+		}
+		m.loading = true
+		return m, m.copyTableCmd(source, profile, target, create), true
+	}
+	var cmd tea.Cmd
+	m.copyInput, cmd = m.copyInput.Update(msg)
+	return m, cmd, true
+}
+
+// copyTableCmd copies the rows of source into target of profile page by
+// page, converting values to the target's column types
+func (m Model) copyTableCmd(source string, profile config.Profile, target string, create bool) tea.Cmd {
+	columns := m.columns[source]
+	sameProfile := m.profile != nil && m.profile.Name == profile.Name
+	return runTransfer("Copy", func(ctx context.Context, report transferReporter) tea.Msg {
+		done := CopyTableCompleteMsg{Table: target, Profile: profile.Name, Created: create && sameProfile}
+		if m.driver == nil {
+			done.Err = fmt.Errorf("no database connection")
+			return done
+		}
+		if err := m.writable(&profile, "INSERT INTO "+target); err != nil {
+			done.Err = err
+			return done
+		}
+		from := m.driver.Type()
+		if !copyable(from) {
+			done.Err = fmt.Errorf("copying from %s is not supported", from)
+			return done
+		}
+		if len(columns) == 0 {
+			done.Err = fmt.Errorf("no columns loaded for %s", source)
+			return done
+		}
+
+		dest := m.driver
+		exec := m.execute
+		if !sameProfile {
+			var err error
+			if dest, err = m.openProfile(profile); err != nil {
+				done.Err = err
+				return done
+			}
+			defer dest.Close()
+			// Audited and checked under the destination profile
+			exec = func(ctx context.Context, stmt string) (*db.QueryResult, error) {
+				return m.executeOn(ctx, dest, &profile, stmt)
+			}
+		}
+		to := dest.Type()
+		if !copyable(to) {
+			done.Err = fmt.Errorf("copying to %s is not supported", to)
+			return done
+		}
+
+		// Target columns matched to the source columns by name
+		targetCols := make([]db.Column, len(columns))
+		if create {
+			if _, err := exec(ctx, db.CreateTableStatement(from, to, target, columns)); err != nil {
+				done.Err = fmt.Errorf("creating %s: %w", target, err)
+				return done
+			}
+			for i, c := range columns {
+				c.Type = db.MapColumnType(from, to, c.Type, c.Key == "PRI")
+				targetCols[i] = c
+			}
+		} else {
+			existing, err := dest.GetColumns(ctx, target)
+			if err != nil {
+				done.Err = err
+				return done
+			}
+			if len(existing) == 0 {
+				done.Err = fmt.Errorf("table %s not found in %s", target, profile.Name)
+				return done
+			}
+			var kept []db.Column
+			for _, c := range columns {
+				if tc, ok := findColumn(existing, c.Name); ok {
+					kept = append(kept, c)
+					targetCols[len(kept)-1] = tc
+				}
+			}
+			if len(kept) == 0 {
+				done.Err = fmt.Errorf("%s has no columns in common with %s", target, source)
+				return done
+			}
+			columns, targetCols = kept, targetCols[:len(kept)]
+		}
+
+		total := 0
+		if result, err := m.execute(ctx, "SELECT COUNT(*) FROM "+source); err == nil && len(result.Rows) > 0 {
+			total, _ = strconv.Atoi(result.Rows[0][0])
+		}
+
+		selectCols := make([]string, len(columns))
+		var order []string
+		for i, c := range columns {
+			selectCols[i] = quoteIdent(from, c.Name)
+			if c.Key == "PRI" {
+				order = append(order, selectCols[i])
+			}
+		}
+		query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectCols, ", "), source)
+		if len(order) > 0 {
+			query += " ORDER BY " + strings.Join(order, ", ")
+		}
+		names := make([]string, len(targetCols))
+		for i, c := range targetCols {
+			names[i] = c.Name
+		}
+
+		for {
+			if err := ctx.Err(); err != nil {
+				done.Err = err
+				return done
+			}
+			report(done.Rows, max(total, done.Rows), 0)
+			page, err := m.execute(ctx, fmt.Sprintf("%s LIMIT %d OFFSET %d", query, copyPageSize, done.Rows))
+			if err != nil {
+				done.Err = err
+				return done
+			}
+			if len(page.Rows) == 0 {
+				return done
+			}
+			rows := make([][]string, len(page.Rows))
+			for i, r := range page.Rows {
+				rows[i] = make([]string, len(targetCols))
+				for j, c := range targetCols {
+					rows[i][j] = sqlValue(to, db.ConvertValue(to, c.Type, r[j]), c)
+				}
+			}
+			if _, err := exec(ctx, batchInsert(to, target, names, rows)); err != nil {
+				done.Err = err
+				return done
+			}
+			done.Rows += len(rows)
+			if len(page.Rows) < copyPageSize {
+				return done
+			}
+		}
+	})
+}
+
+// handleCopyTableComplete reports a copy and reloads the schema when the
+// copy created a table in the connected database
+func (m Model) handleCopyTableComplete(msg CopyTableCompleteMsg) (tea.Model, tea.Cmd) {
+	m = m.endTransfer()
+	if msg.Err != nil {
+		m.errorMsg = transferError("Copy", msg.Err)
+		if msg.Rows > 0 {
+			m.errorMsg += fmt.Sprintf(" after %d rows", msg.Rows)
+		}
+		return m, nil
+	}
+	m.statusMsg = fmt.Sprintf("Copied %d rows to %s in %s", msg.Rows, msg.Table, msg.Profile)
+	if msg.Created && m.driver != nil {
+		m.loadingTables = true
+		return m, schemabrowser.LoadSchemaCmd(m.driver)
+	}
+	return m, nil
+}
+
+func (m Model) renderCopyTablePopup(main string) string {
+	var content strings.Builder
+	popupWidth := 64
+	if popupWidth > m.width-10 {
+		popupWidth = m.width - 10
+	}
+
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).
+		Render("Copy " + m.copySource))
+	content.WriteString("\n\n")
+	faint := lipgloss.NewStyle().Faint(true)
+
+	if m.copyStep == 0 {
+		content.WriteString("Target profile:\n")
+		for i, p := range m.config.Profiles {
+			style := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
+			prefix := "  "
+			if i == m.copyProfileIdx {
+				style = lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Bold(true)
+				prefix = "> "
+			}
+			line := prefix + style.Render(limitString(p.Name, 30)) + faint.Render("  "+p.Type)
+			if m.profile != nil && p.Name == m.profile.Name {
+				line += faint.Render(" (current)")
+			}
+			content.WriteString(line + "\n")
+		}
+		content.WriteString("\n")
+		content.WriteString(m.renderHintLine("Enter: next • ↑/↓: select • Esc: cancel", m.popupInnerWidth(popupWidth)))
+	} else {
+		profile := m.config.Profiles[m.copyProfileIdx]
+		content.WriteString(fmt.Sprintf("Target profile: %s (%s)\n\n", profile.Name, profile.Type))
+		content.WriteString(m.copyInput.View())
+		content.WriteString("\n\n")
+		check := "[ ]"
+		if m.copyCreate {
+			check = "[x]"
+		}
+		content.WriteString(check + " Create the table, mapping column types\n\n")
+		content.WriteString(m.renderHintLine("Enter: copy • Tab: toggle create • Shift+Tab: back • Esc: cancel", m.popupInnerWidth(popupWidth)))
+	}
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
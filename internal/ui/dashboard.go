@@ -0,0 +1,166 @@
+// internal/ui/dashboard.go
+// Server dashboard: connections, cache hit ratio, longest query, replication lag and database sizes.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// dashboardRefresh is how often the open dashboard polls the server
+const dashboardRefresh = 5 * time.Second
+
+// openDashboard opens the server dashboard.
+func (m *Model) openDashboard() {
+	if m.showDashboard {
+		return
+	}
+	m.showDashboard = true
+	m.autocompleting = false
+	m.popupStack.Push("dashboard", func(m *Model) {
+		m.showDashboard = false
+	})
+}
+
+// pollMetricsCmd reads the server metrics; only the latest poll keeps refreshing
+func (m *Model) pollMetricsCmd(reporter db.MetricsReporter) tea.Cmd {
+	m.dashboardSeq++
+	seq := m.dashboardSeq
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		metrics, err := reporter.Metrics(ctx)
+		return MetricsMsg{Seq: seq, Metrics: metrics, Err: err}
+	}
+}
+
+// handleDashboardKeys handles keys while the dashboard is open
+func (m Model) handleDashboardKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	reporter, ok := m.driver.(db.MetricsReporter)
+	if !ok {
+		m.closeTopPopup()
+		return m, nil, true
+	}
+	switch {
+	case msg.String() == "r":
+		return m, m.pollMetricsCmd(reporter), true
+	case matchKey(msg, m.config.Keys.Dashboard):
+		m.closeTopPopup()
+	}
+	return m, nil, true
+}
+
+// handleMetrics shows a poll's result and schedules the next one. A failed
+// poll keeps the last metrics on screen.
+func (m Model) handleMetrics(msg MetricsMsg) (Model, tea.Cmd) {
+	if !m.showDashboard || msg.Seq != m.dashboardSeq {
+		return m, nil
+	}
+	if msg.Err != nil {
+		m.metricsErr = msg.Err.Error()
+	} else {
+		m.metrics, m.metricsErr, m.metricsAt = msg.Metrics, "", time.Now()
+	}
+	return m, tea.Tick(dashboardRefresh, func(time.Time) tea.Msg {
+		return MetricsTickMsg{Seq: msg.Seq}
+	})
+}
+
+// handleMetricsTick polls again if the dashboard is still open
+func (m Model) handleMetricsTick(msg MetricsTickMsg) (Model, tea.Cmd) {
+	reporter, ok := m.driver.(db.MetricsReporter)
+	if !ok || !m.showDashboard || msg.Seq != m.dashboardSeq {
+		return m, nil
+	}
+	return m, m.pollMetricsCmd(reporter)
+}
+
+func (m Model) renderDashboard(main string) string {
+	var content strings.Builder
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Server Dashboard")
+	faint := lipgloss.NewStyle().Faint(true)
+	content.WriteString(title)
+	if !m.metricsAt.IsZero() {
+		content.WriteString(faint.Render("  updated " + m.metricsAt.Format("15:04:05")))
+	}
+	content.WriteString("\n\n")
+
+	popupWidth := min(80, m.width-10)
+	label := lipgloss.NewStyle().Foreground(m.theme.TextSecondary()).Width(16)
+	value := lipgloss.NewStyle().Foreground(m.theme.TextPrimary()).Bold(true)
+	warn := lipgloss.NewStyle().Foreground(m.theme.WarningColor()).Bold(true)
+	row := func(name, text string, style lipgloss.Style) {
+		content.WriteString(label.Render(name) + style.Render(text) + "\n")
+	}
+
+	if metrics := m.metrics; metrics == nil {
+		if m.metricsErr == "" {
+			content.WriteString(faint.Render("Loading...") + "\n")
+		}
+	} else {
+		conns := fmt.Sprintf("%d (%d active)", metrics.Connections, metrics.Active)
+		connStyle := value
+		if metrics.MaxConnections > 0 {
+			conns = fmt.Sprintf("%d / %d (%d active)", metrics.Connections, metrics.MaxConnections, metrics.Active)
+			if metrics.Connections*10 >= metrics.MaxConnections*8 {
+				connStyle = warn
+			}
+		}
+		row("Connections", conns, connStyle)
+
+		if metrics.CacheHitRatio < 0 {
+			row("Cache hit ratio", "n/a", faint)
+		} else if metrics.CacheHitRatio < 0.9 {
+			row("Cache hit ratio", fmt.Sprintf("%.1f%%", metrics.CacheHitRatio*100), warn)
+		} else {
+			row("Cache hit ratio", fmt.Sprintf("%.1f%%", metrics.CacheHitRatio*100), value)
+		}
+
+		if metrics.LongestQuery == "" {
+			row("Longest query", "none running", faint)
+		} else {
+			query := truncateEnd(strings.Join(strings.Fields(metrics.LongestQuery), " "), max(popupWidth-36, 10))
+			row("Longest query", metrics.LongestQueryDuration.Round(time.Second).String(), value)
+			content.WriteString(label.Render("") + faint.Render(query) + "\n")
+		}
+
+		if !metrics.Replicating {
+			row("Replication lag", "not replicating", faint)
+		} else if metrics.ReplicationLag >= 10*time.Second {
+			row("Replication lag", metrics.ReplicationLag.Round(time.Second).String(), warn)
+		} else {
+			row("Replication lag", metrics.ReplicationLag.Round(time.Millisecond).String(), value)
+		}
+
+		if len(metrics.DatabaseSizes) > 0 {
+			content.WriteString("\n" + lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Database sizes") + "\n")
+			for _, size := range metrics.DatabaseSizes {
+				row("  "+limitString(size.Name, 13), formatByteCount(size.Bytes), value)
+			}
+		}
+	}
+
+	if m.metricsErr != "" {
+		content.WriteString("\n" + lipgloss.NewStyle().Foreground(m.theme.ErrorColor()).Render(limitString(m.metricsErr, popupWidth-6)) + "\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(m.renderHintLine(fmt.Sprintf("Refreshes every %s • r: refresh • Esc: close", dashboardRefresh), m.popupInnerWidth(popupWidth)))
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
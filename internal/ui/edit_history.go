@@ -0,0 +1,94 @@
+// internal/ui/edit_history.go
+// Editor undo/redo: snapshots taken when typing pauses, persisted per profile.
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editIdleDelay is the typing pause after which the editor text is snapshotted
+const editIdleDelay = 750 * time.Millisecond
+
+// scheduleEditSnapshot restarts the idle timer after the editor text changed
+func (m Model) scheduleEditSnapshot() (Model, tea.Cmd) {
+	m.editIdleID++
+	id := m.editIdleID
+	return m, tea.Tick(editIdleDelay, func(time.Time) tea.Msg {
+		return EditIdleMsg{ID: id}
+	})
+}
+
+// handleEditIdle snapshots the editor once typing has paused
+func (m Model) handleEditIdle(msg EditIdleMsg) (Model, tea.Cmd) {
+	if msg.ID != m.editIdleID || !m.edits.Record(m.editor.Value()) {
+		return m, nil
+	}
+	return m, m.saveEditsCmd()
+}
+
+// setEditorValue replaces the editor text as its own undo step, keeping any
+// unsnapshotted typing undoable
+func (m *Model) setEditorValue(text string) {
+	// Tab-stops in the replaced text no longer apply
+	m.endSnippet()
+	m.edits.Record(m.editor.Value())
+	m.editor.SetValue(text)
+	m.edits.Record(text)
+}
+
+// loadEditsCmd loads the profile's saved edit history
+func (m Model) loadEditsCmd() tea.Cmd {
+	if m.historyStore == nil || m.profile == nil {
+		return nil
+	}
+	store, name, depth := m.historyStore, m.profile.Name, m.config.UndoDepth
+	return func() tea.Msg {
+		h, err := store.LoadEdits(name, depth)
+		return EditsLoadedMsg{History: h, Err: err}
+	}
+}
+
+// handleEditsLoaded restores the saved history and, into an empty editor, the scratchpad
+func (m Model) handleEditsLoaded(msg EditsLoadedMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = "Failed to load edit history: " + msg.Err.Error()
+		return m, nil
+	}
+	if msg.History == nil {
+		m.edits.Limit = m.config.UndoDepth
+		return m, nil
+	}
+	m.edits = msg.History
+	if m.editor.Value() == "" {
+		m.editor.SetValue(m.edits.Current())
+	} else {
+		m.edits.Record(m.editor.Value())
+	}
+	return m, nil
+}
+
+// saveEditsCmd persists a copy of the edit history off the update loop
+func (m Model) saveEditsCmd() tea.Cmd {
+	if m.historyStore == nil || m.profile == nil {
+		return nil
+	}
+	h := *m.edits
+	h.Snapshots = append([]string(nil), m.edits.Snapshots...)
+	store, name := m.historyStore, m.profile.Name
+	return func() tea.Msg {
+		// A failed save only loses undo steps; it is retried on the next snapshot
+		_ = store.SaveEdits(name, &h)
+		return nil
+	}
+}
+
+// flushEdits snapshots and saves the editor synchronously, for quitting
+func (m Model) flushEdits() {
+	if m.historyStore == nil || m.profile == nil {
+		return
+	}
+	m.edits.Record(m.editor.Value())
+	_ = m.historyStore.SaveEdits(m.profile.Name, m.edits)
+}
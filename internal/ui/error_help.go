@@ -0,0 +1,175 @@
+// internal/ui/error_help.go
+// Explain a failed query: the bundled documentation hint for its error code and, with the assistant enabled, a suggested fix.
+package ui
+
+import (
+	"context"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/assistant"
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/history"
+)
+
+// openErrorHelp opens the explanation of a failed history entry
+func (m *Model) openErrorHelp(entry history.HistoryEntry) {
+	if m.showErrorHelp {
+		return
+	}
+	m.showErrorHelp = true
+	m.autocompleting = false
+	m.errorHelpEntry = entry
+	m.errorHelpBusy = false
+	m.errorHelpFix = ""
+	m.errorHelpErr = ""
+	m.popupStack.Push("error help", func(m *Model) {
+		m.showErrorHelp = false
+		m.errorHelpBusy = false
+	})
+}
+
+// errorHelpHint returns the documentation hint of the entry's error
+func (m Model) errorHelpHint() (db.ErrorHint, bool) {
+	if m.driver == nil {
+		return db.ErrorHint{}, false
+	}
+	return db.ExplainError(m.driver.Type(), m.errorHelpEntry.ErrorMessage)
+}
+
+// errorHelpSQL returns the corrected SQL of the assistant's suggestion, or ""
+// when it gave none
+func (m Model) errorHelpSQL() string {
+	if !strings.Contains(m.errorHelpFix, "```") {
+		return ""
+	}
+	return assistant.ExtractSQL(m.errorHelpFix)
+}
+
+// handleErrorHelpKeys asks the assistant, edits its fix or copies the docs link
+func (m Model) handleErrorHelpKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc", "q":
+		m.closeTopPopup()
+	case "a":
+		if !m.config.AssistantEnabled(m.profile) || m.errorHelpBusy {
+			return m, nil, true
+		}
+		m.errorHelpBusy = true
+		m.errorHelpFix, m.errorHelpErr = "", ""
+		return m, m.errorFixCmd(m.errorHelpEntry), true
+	case "e":
+		sql := m.errorHelpSQL()
+		if sql == "" {
+			return m, nil, true
+		}
+		m.closeTopPopup()
+		m.setEditorValue(sql)
+		m.mode = InsertMode
+		m.editor.Focus()
+		m.statusMsg = "Suggested fix in the editor; review it before running"
+		return m, textinput.Blink, true
+	case "y":
+		if hint, ok := m.errorHelpHint(); ok {
+			return m, m.copyToClipboardCmd(hint.DocURL), true
+		}
+	}
+	return m, nil, true
+}
+
+// errorFixCmd sends the failed query, its error and the cached schema to
+// the assistant
+func (m Model) errorFixCmd(entry history.HistoryEntry) tea.Cmd {
+	client := assistant.New(m.config.Assistant)
+	schema := assistant.Schema{Tables: m.tables, Columns: m.columns}
+	if m.driver != nil {
+		schema.Dialect = string(m.driver.Type())
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), assistantTimeout)
+		defer cancel()
+		text, err := client.SuggestFix(ctx, entry.Query, entry.ErrorMessage, schema)
+		return ErrorFixMsg{EntryID: entry.ID, Text: text, Err: err}
+	}
+}
+
+// handleErrorFix shows the assistant's suggestion if the entry's explanation
+// is still open
+func (m Model) handleErrorFix(msg ErrorFixMsg) (Model, tea.Cmd) {
+	if !m.showErrorHelp || !m.errorHelpBusy || msg.EntryID != m.errorHelpEntry.ID {
+		return m, nil
+	}
+	m.errorHelpBusy = false
+	if msg.Err != nil {
+		m.errorHelpErr = msg.Err.Error()
+		return m, nil
+	}
+	m.errorHelpFix = msg.Text
+	return m, nil
+}
+
+func (m Model) renderErrorHelp(main string) string {
+	popupWidth := min(80, m.width-10)
+	inner := m.popupInnerWidth(popupWidth)
+	faint := lipgloss.NewStyle().Faint(true)
+	wrap := lipgloss.NewStyle().Width(inner)
+	var content strings.Builder
+
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Explain Error"))
+	content.WriteString("\n\n")
+	content.WriteString(faint.Render(limitString(strings.Join(strings.Fields(m.errorHelpEntry.Query), " "), inner)))
+	content.WriteString("\n")
+	content.WriteString(wrap.Foreground(m.theme.ErrorColor()).Render(m.errorHelpEntry.ErrorMessage))
+	content.WriteString("\n\n")
+
+	hints := []string{"Esc: close"}
+	if hint, ok := m.errorHelpHint(); ok {
+		label := hint.Name
+		if hint.Code != "" {
+			label = hint.Code + " " + hint.Name
+		}
+		content.WriteString(lipgloss.NewStyle().Bold(true).Render(label) + "\n")
+		content.WriteString(wrap.Render(hint.Hint) + "\n")
+		content.WriteString(faint.Render(limitString(hint.DocURL, inner)) + "\n")
+		hints = append([]string{"y: copy docs link"}, hints...)
+	} else {
+		content.WriteString(faint.Render("No documentation hint for this error") + "\n")
+	}
+
+	if m.config.AssistantEnabled(m.profile) {
+		content.WriteString("\n")
+		switch {
+		case m.errorHelpBusy:
+			content.WriteString(faint.Render("Asking the assistant..."))
+		case m.errorHelpErr != "":
+			content.WriteString(wrap.Foreground(m.theme.ErrorColor()).Render(m.errorHelpErr))
+		case m.errorHelpFix != "":
+			content.WriteString(lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Render("Assistant") + "\n")
+			content.WriteString(wrap.Render(m.errorHelpFix))
+		default:
+			content.WriteString(faint.Width(inner).Render("Press a to send the query, its error and the cached table and column names and types to " +
+				assistant.New(m.config.Assistant).Endpoint + " for a suggested fix"))
+		}
+		content.WriteString("\n")
+		if m.errorHelpSQL() != "" {
+			hints = append([]string{"e: edit fix"}, hints...)
+		}
+		if !m.errorHelpBusy {
+			hints = append([]string{"a: ask the assistant"}, hints...)
+		}
+	}
+	content.WriteString("\n")
+	content.WriteString(m.renderHintLine(strings.Join(hints, " • "), inner))
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
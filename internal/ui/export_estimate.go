@@ -0,0 +1,122 @@
+// internal/ui/export_estimate.go
+// Table exports show how many rows they would write and ask before dumping a large table whole.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// exportConfirmRows is the estimated size from which exporting a whole
+// table takes a second Enter
+const exportConfirmRows = 1_000_000
+
+// exportEstimate is the row count shown in the table export popup
+type exportEstimate struct {
+	Filter string // Filter clause the count is for
+	Rows   int64  // -1 when the driver is not asked to count
+	Exact  bool   // Counted rather than estimated by the planner
+	Done   bool
+	Err    error
+}
+
+// exportEstimateCmd counts the rows a table export would write, asking the
+// planner where the driver can estimate and running a COUNT otherwise
+func (m Model) exportEstimateCmd(table string, filter exportFilter) tea.Cmd {
+	driver := m.driver
+	if driver == nil || driver.Type() == db.Cassandra { // COUNT scans every partition
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		query := exportQuery(table, filter)
+		key := filter.Clause()
+		if estimator, ok := driver.(db.RowEstimator); ok {
+			rows, err := estimator.EstimateRows(ctx, query)
+			return ExportEstimateMsg{Table: table, Filter: key, Rows: rows, Err: err}
+		}
+		count := "SELECT COUNT(*) FROM " + table
+		if key != "" {
+			count = fmt.Sprintf("SELECT COUNT(*) FROM (%s) export_count", query)
+		}
+		result, err := m.execute(ctx, count)
+		if err != nil {
+			return ExportEstimateMsg{Table: table, Filter: key, Err: err}
+		}
+		if len(result.Rows) == 0 || len(result.Rows[0]) == 0 {
+			return ExportEstimateMsg{Table: table, Filter: key, Err: fmt.Errorf("no count returned")}
+		}
+		rows, err := strconv.ParseInt(result.Rows[0][0], 10, 64)
+		return ExportEstimateMsg{Table: table, Filter: key, Rows: rows, Exact: true, Err: err}
+	}
+}
+
+// estimateExport starts counting the rows of the table export with the
+// current filter
+func (m *Model) estimateExport() tea.Cmd {
+	filter := m.exportFilter()
+	cmd := m.exportEstimateCmd(m.exportTable, filter)
+	if cmd == nil {
+		m.exportEstimate = exportEstimate{Filter: filter.Clause(), Rows: -1, Done: true}
+		return nil
+	}
+	m.exportEstimate = exportEstimate{Filter: filter.Clause()}
+	return cmd
+}
+
+// handleExportEstimate shows a count in the export popup it was made for
+func (m Model) handleExportEstimate(msg ExportEstimateMsg) (Model, tea.Cmd) {
+	if !m.showExportPopup || msg.Table != m.exportTable || msg.Filter != m.exportEstimate.Filter {
+		return m, nil
+	}
+	m.exportEstimate = exportEstimate{Filter: msg.Filter, Rows: msg.Rows, Exact: msg.Exact, Done: true, Err: msg.Err}
+	return m, nil
+}
+
+// confirmLargeExport reports whether Enter should ask before exporting: the
+// whole table is estimated past exportConfirmRows and was not confirmed yet
+func (m Model) confirmLargeExport() bool {
+	e := m.exportEstimate
+	return !m.exportConfirm && m.exportFilter().Clause() == "" &&
+		e.Done && e.Err == nil && e.Rows >= exportConfirmRows
+}
+
+// Text words the count, e.g. "~4.2M rows" or "12 rows"
+func (e exportEstimate) Text() string {
+	switch {
+	case !e.Done:
+		return "Counting rows..."
+	case e.Err != nil:
+		return "Row count unavailable: " + e.Err.Error()
+	case e.Rows < 0:
+		return ""
+	}
+	text := compactCount(e.Rows) + " rows"
+	if e.Rows == 1 {
+		text = "1 row"
+	}
+	if !e.Exact {
+		text = "~" + text
+	}
+	return text
+}
+
+// compactCount shortens a count to one decimal of thousands, millions or billions
+func compactCount(n int64) string {
+	switch {
+	case n >= 1_000_000_000:
+		return strconv.FormatFloat(float64(n)/1e9, 'f', 1, 64) + "B"
+	case n >= 1_000_000:
+		return strconv.FormatFloat(float64(n)/1e6, 'f', 1, 64) + "M"
+	case n >= 10_000:
+		return strconv.FormatFloat(float64(n)/1e3, 'f', 1, 64) + "K"
+	}
+	return strconv.FormatInt(n, 10)
+}
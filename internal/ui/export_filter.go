@@ -0,0 +1,209 @@
+// internal/ui/export_filter.go
+// WHERE, ORDER BY and LIMIT inputs of the table export popup, completing column names with Tab.
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Filter inputs of the table export popup, in the order up and down visit them
+const (
+	exportWhere = iota
+	exportOrderBy
+	exportLimit
+	exportFilterFields
+)
+
+// maxColumnCompletions caps the column names listed under a filter input
+const maxColumnCompletions = 6
+
+// exportFilter narrows a table export to a slice of the table
+type exportFilter struct {
+	Where   string
+	OrderBy string
+	Limit   string
+}
+
+// Clause returns the filter as clauses to follow SELECT * FROM table. A
+// WHERE or ORDER BY typed in front of an input is not repeated.
+func (f exportFilter) Clause() string {
+	var parts []string
+	if where := trimKeyword(f.Where, "WHERE"); where != "" {
+		parts = append(parts, "WHERE "+where)
+	}
+	if order := trimKeyword(f.OrderBy, "ORDER BY"); order != "" {
+		parts = append(parts, "ORDER BY "+order)
+	}
+	if limit := trimKeyword(f.Limit, "LIMIT"); limit != "" {
+		parts = append(parts, "LIMIT "+limit)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Validate checks the parts of the filter that can be checked before the
+// server sees them
+func (f exportFilter) Validate() error {
+	if limit := trimKeyword(f.Limit, "LIMIT"); limit != "" {
+		if n, err := strconv.Atoi(limit); err != nil || n < 0 {
+			return fmt.Errorf("limit must be a row count, got %q", limit)
+		}
+	}
+	return nil
+}
+
+// trimKeyword trims space and a leading keyword from an input value
+func trimKeyword(value, keyword string) string {
+	value = strings.TrimSpace(value)
+	if len(value) > len(keyword) && strings.EqualFold(value[:len(keyword)], keyword) && value[len(keyword)] == ' ' {
+		value = strings.TrimSpace(value[len(keyword):])
+	}
+	return value
+}
+
+// exportQuery selects the rows of table the export filter keeps
+func exportQuery(table string, filter exportFilter) string {
+	query := "SELECT * FROM " + table
+	if clause := filter.Clause(); clause != "" {
+		query += " " + clause
+	}
+	return query
+}
+
+// exportFilter returns the filter typed in the table export popup
+func (m Model) exportFilter() exportFilter {
+	in := m.exportFilterInputs
+	return exportFilter{Where: in[exportWhere].Value(), OrderBy: in[exportOrderBy].Value(), Limit: in[exportLimit].Value()}
+}
+
+// focusExportField moves the focus of the table export popup to field, 0
+// being the file name and 1 onward the filter inputs
+func (m *Model) focusExportField(field int) tea.Cmd {
+	m.exportField = field
+	m.pathMatches = nil
+	m.columnMatches = nil
+	m.exportInput.Blur()
+	for i := range m.exportFilterInputs {
+		m.exportFilterInputs[i].Blur()
+	}
+	if field == 0 {
+		return m.exportInput.Focus()
+	}
+	return m.exportFilterInputs[field-1].Focus()
+}
+
+// handleExportFilterKeys moves between the file name and filter inputs of a
+// table export with up and down, recounting the rows when the filter
+// changed, and edits the focused filter input, Tab completing column names
+func (m Model) handleExportFilterKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	fields := exportFilterFields + 1
+	switch msg.String() {
+	case "up", "down":
+		step := 1
+		if msg.String() == "up" {
+			step = fields - 1
+		}
+		cmd := m.focusExportField((m.exportField + step) % fields)
+		if m.exportFilter().Clause() != m.exportEstimate.Filter {
+			cmd = tea.Batch(cmd, m.estimateExport())
+		}
+		return m, cmd, true
+	}
+	if m.exportField == 0 || msg.String() == "enter" {
+		return m, nil, false
+	}
+	input := &m.exportFilterInputs[m.exportField-1]
+	if msg.String() == "tab" {
+		if m.exportField-1 != exportLimit {
+			m.completeColumnInput(input)
+		}
+		return m, nil, true
+	}
+	m.columnMatches = nil
+	m.exportConfirm = false
+	var cmd tea.Cmd
+	*input, cmd = input.Update(msg)
+	return m, cmd, true
+}
+
+// columnWordStart returns where the word being typed at the end of an
+// expression starts
+func columnWordStart(expr string) int {
+	return strings.LastIndexAny(expr, " ,()=<>!+-*/|") + 1
+}
+
+// completeColumns returns the completions of the column name being typed at
+// the end of expr, matched without regard to case, and the longest prefix
+// they share
+func completeColumns(expr string, columns []string) (common string, matches []string) {
+	start := columnWordStart(expr)
+	word := strings.ToLower(expr[start:])
+	for _, c := range columns {
+		if strings.HasPrefix(strings.ToLower(c), word) {
+			matches = append(matches, expr[:start]+c)
+		}
+	}
+	if len(matches) == 0 {
+		return expr, nil
+	}
+	common = matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	if len(common) < len(expr) { // The matches differ from what was typed only in case
+		common = expr
+	}
+	return common, matches
+}
+
+// completeColumnInput handles Tab in a filter input: the first press
+// completes the shared prefix of the table's columns, further presses cycle
+// through the matches
+func (m *Model) completeColumnInput(input *textinput.Model) {
+	if len(m.columnMatches) > 1 {
+		m.columnMatchIdx = (m.columnMatchIdx + 1) % len(m.columnMatches)
+		input.SetValue(m.columnMatches[m.columnMatchIdx])
+		input.CursorEnd()
+		return
+	}
+	var names []string
+	for _, c := range m.columns[m.exportTable] {
+		names = append(names, c.Name)
+	}
+	common, matches := completeColumns(input.Value(), names)
+	input.SetValue(common)
+	input.CursorEnd()
+	m.columnMatches, m.columnMatchIdx = nil, -1
+	if len(matches) > 1 {
+		m.columnMatches = matches
+	}
+}
+
+// renderColumnMatches lists the column names Tab cycles through on one line
+func (m Model) renderColumnMatches(width int) string {
+	if len(m.columnMatches) == 0 {
+		return ""
+	}
+	var names []string
+	for i, match := range m.columnMatches {
+		if i == maxColumnCompletions {
+			names = append(names, fmt.Sprintf("+%d", len(m.columnMatches)-i))
+			break
+		}
+		name := match[columnWordStart(match):]
+		if i == m.columnMatchIdx {
+			name = lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Bold(true).Render(name)
+		} else {
+			name = lipgloss.NewStyle().Faint(true).Render(name)
+		}
+		names = append(names, name)
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(strings.Join(names, "  ")) + "\n"
+}
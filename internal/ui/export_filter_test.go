@@ -0,0 +1,31 @@
+// internal/ui/export_filter_test.go
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExportFilterClause(t *testing.T) {
+	f := exportFilter{Where: "where status = 'a'", OrderBy: " created_at DESC ", Limit: "100"}
+	if got, want := f.Clause(), "WHERE status = 'a' ORDER BY created_at DESC LIMIT 100"; got != want {
+		t.Errorf("Clause() = %q, want %q", got, want)
+	}
+	if got := (exportFilter{Where: "wherever > 1"}).Clause(); got != "WHERE wherever > 1" {
+		t.Errorf("column starting with a keyword: %q", got)
+	}
+	if err := (exportFilter{Limit: "ten"}).Validate(); err == nil {
+		t.Error("non-numeric limit accepted")
+	}
+}
+
+func TestCompleteColumns(t *testing.T) {
+	cols := []string{"id", "created_at", "created_by", "Name"}
+	common, matches := completeColumns("id > 3 AND cr", cols)
+	if common != "id > 3 AND created_" || !slices.Equal(matches, []string{"id > 3 AND created_at", "id > 3 AND created_by"}) {
+		t.Errorf("got %q, %q", common, matches)
+	}
+	if common, _ := completeColumns("(na", cols); common != "(Name" {
+		t.Errorf("case-insensitive match = %q, want (Name", common)
+	}
+}
@@ -0,0 +1,144 @@
+// internal/ui/export_formats.go
+// Export formats: CSV, .xlsx or .parquet by file extension, gzip-compressed when the path ends in .gz.
+package ui
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/parquet"
+	"github.com/nhath/ezdb/internal/xlsx"
+)
+
+// rowWriter writes the rows of an export; Close must succeed for the file to be complete
+type rowWriter interface {
+	Write(row []string) error
+	Close() error
+}
+
+type csvRows struct{ w *csv.Writer }
+
+func (c csvRows) Write(row []string) error { return c.w.Write(row) }
+
+func (c csvRows) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type xlsxRows struct{ w *xlsx.Writer }
+
+func (x xlsxRows) Write(row []string) error { return x.w.WriteRow(row) }
+func (x xlsxRows) Close() error             { return x.w.Close() }
+
+type parquetRows struct{ w *parquet.Writer }
+
+func (p parquetRows) Write(row []string) error { return p.w.WriteRow(row) }
+func (p parquetRows) Close() error             { return p.w.Close() }
+
+// parquetColumns types columns by their SQL type in types, leaving columns
+// without one to be inferred from their values
+func parquetColumns(columns []string, types map[string]string) []parquet.Column {
+	cols := make([]parquet.Column, len(columns))
+	for i, name := range columns {
+		cols[i] = parquet.Column{Name: name}
+		colType, ok := types[name]
+		if !ok {
+			continue
+		}
+		kind, precision, scale := db.KindOf(colType)
+		switch kind {
+		case db.KindBool:
+			cols[i].Type = parquet.Boolean
+		case db.KindInt, db.KindBigInt:
+			cols[i].Type = parquet.Int64
+		case db.KindDecimal:
+			cols[i].Type, cols[i].Precision, cols[i].Scale = parquet.Decimal, precision, scale
+		case db.KindDouble:
+			cols[i].Type = parquet.Double
+		case db.KindDate:
+			cols[i].Type = parquet.Date
+		case db.KindTimestamp:
+			cols[i].Type = parquet.Timestamp
+		case db.KindTimestampTZ:
+			cols[i].Type = parquet.TimestampTZ
+		default:
+			cols[i].Type = parquet.String
+		}
+	}
+	return cols
+}
+
+// gzipRows closes the format writer before the compressed stream around it
+type gzipRows struct {
+	rowWriter
+	gz *gzip.Writer
+}
+
+func (g gzipRows) Close() error {
+	if err := g.rowWriter.Close(); err != nil {
+		return err
+	}
+	return g.gz.Close()
+}
+
+// exportFormat returns the extension deciding the format of path, e.g.
+// ".xlsx" for "out.xlsx.gz", and whether path is gzip-compressed
+func exportFormat(path string) (ext string, gz bool) {
+	path = strings.ToLower(path)
+	if gz = strings.HasSuffix(path, ".gz"); gz {
+		path = strings.TrimSuffix(path, ".gz")
+	}
+	if i := strings.LastIndexAny(path, `./\`); i >= 0 && path[i] == '.' {
+		ext = path[i:]
+	}
+	return ext, gz
+}
+
+// hasExportExt reports whether path names a format the export writers know
+func hasExportExt(path string) bool {
+	ext, _ := exportFormat(path)
+	return ext == ".csv" || ext == ".xlsx" || ext == ".parquet"
+}
+
+// newRowWriter returns the writer for path's format, writing the header row
+// first. CSV uses comma; .xlsx puts the rows on a sheet named sheet; .parquet
+// types columns by their SQL type in types, by column name, or else by value.
+func newRowWriter(w io.Writer, path string, comma rune, sheet string, columns []string, types map[string]string) (rowWriter, error) {
+	ext, compressed := exportFormat(path)
+	var gz *gzip.Writer
+	if compressed {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+
+	var rw rowWriter
+	switch ext {
+	case ".parquet":
+		pw, err := parquet.NewWriter(w, parquetColumns(columns, types))
+		if err != nil {
+			return nil, err
+		}
+		rw = parquetRows{pw}
+	case ".xlsx":
+		xw := xlsx.NewWriter(w)
+		if err := xw.AddSheet(sheet, columns); err != nil {
+			return nil, err
+		}
+		rw = xlsxRows{xw}
+	default:
+		cw := csv.NewWriter(w)
+		cw.Comma = comma
+		if err := cw.Write(columns); err != nil {
+			return nil, err
+		}
+		rw = csvRows{cw}
+	}
+
+	if gz != nil {
+		return gzipRows{rw, gz}, nil
+	}
+	return rw, nil
+}
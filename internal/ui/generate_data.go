@@ -0,0 +1,380 @@
+// internal/ui/generate_data.go
+// Data generator: fills a table with synthetic rows that respect column types, NOT NULL, unique and foreign keys.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+const (
+	generateBatch   = 100    // Rows per INSERT
+	maxGenerateRows = 100000 // Largest row count the prompt accepts
+	parentSample    = 1000   // Parent keys sampled per foreign key
+)
+
+// typeLength matches the length of varchar(20) or char(8)
+var typeLength = regexp.MustCompile(`char\s*\((\d+)\)`)
+
+// integerType matches integer and serial column types
+var integerType = regexp.MustCompile(`^(tiny|small|medium|big)?(int[248]?|integer|serial[248]?)\b`)
+
+// enumValues matches the quoted values of a MySQL enum('a','b')
+var enumValues = regexp.MustCompile(`'((?:[^']|'')*)'`)
+
+// genColumn is a column the generator fills in
+type genColumn struct {
+	db.Column
+	unique bool
+	next   int64 // Next value of a unique integer column
+}
+
+// genForeignKey is a foreign key with its sampled parent keys
+type genForeignKey struct {
+	db.Constraint
+	parents [][]string
+}
+
+// dataGenerator produces the values of generated rows
+type dataGenerator struct {
+	dt      db.DriverType
+	columns []genColumn
+	fks     []genForeignKey
+	rng     *rand.Rand
+	run     string // Sets unique text apart from earlier runs
+	seq     int
+}
+
+// generatedColumns returns the columns to fill in. Columns with a default
+// and auto-increment keys are left to the database.
+func generatedColumns(dt db.DriverType, columns []db.Column, constraints []db.Constraint) []genColumn {
+	primary := 0
+	for _, c := range columns {
+		if c.Key == "PRI" {
+			primary++
+		}
+	}
+	uniques := map[string]bool{}
+	for _, c := range constraints {
+		if c.Type == "UNIQUE" && len(c.Columns) == 1 {
+			uniques[c.Columns[0]] = true
+		}
+	}
+
+	var gen []genColumn
+	for _, c := range columns {
+		if c.Default != "" && c.Default != "<nil>" {
+			continue
+		}
+		key := primary == 1 && c.Key == "PRI"
+		if key && isIntegerType(c.Type) && (dt == db.MySQL || dt == db.SQLite) {
+			continue // AUTO_INCREMENT or the rowid
+		}
+		gen = append(gen, genColumn{Column: c, unique: key || c.Key == "UNI" || uniques[c.Name]})
+	}
+	return gen
+}
+
+// foreignKeys returns the single- and multi-column foreign keys among the constraints
+func foreignKeys(constraints []db.Constraint) []genForeignKey {
+	var fks []genForeignKey
+	for _, c := range constraints {
+		if c.Type == "FOREIGN KEY" && c.RefTable != "" && len(c.Columns) > 0 && len(c.Columns) == len(c.RefColumns) {
+			fks = append(fks, genForeignKey{Constraint: c})
+		}
+	}
+	return fks
+}
+
+func isIntegerType(colType string) bool {
+	return integerType.MatchString(strings.ToLower(colType))
+}
+
+// row returns the SQL literals of one generated row
+func (g *dataGenerator) row() []string {
+	g.seq++
+	values := make([]string, len(g.columns))
+	fromParent := map[string]string{}
+	for _, fk := range g.fks {
+		if len(fk.parents) == 0 {
+			continue
+		}
+		parent := fk.parents[g.rng.Intn(len(fk.parents))]
+		for i, name := range fk.Columns {
+			fromParent[name] = parent[i]
+		}
+	}
+
+	for i := range g.columns {
+		c := &g.columns[i]
+		if v, ok := fromParent[c.Name]; ok {
+			values[i] = sqlValue(g.dt, v, c.Column)
+			continue
+		}
+		if g.isForeignKey(c.Name) || (c.Nullable && !c.unique && g.rng.Intn(10) == 0) {
+			values[i] = "NULL" // No parent to reference, or an occasional NULL
+			continue
+		}
+		values[i] = sqlValue(g.dt, g.value(c), c.Column)
+	}
+	return values
+}
+
+func (g *dataGenerator) isForeignKey(name string) bool {
+	for _, fk := range g.fks {
+		if slices.Contains(fk.Columns, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// value generates a value fitting the column's type
+func (g *dataGenerator) value(c *genColumn) string {
+	t := strings.ToLower(c.Type)
+	switch {
+	case strings.HasPrefix(t, "bool"):
+		return strconv.FormatBool(g.rng.Intn(2) == 0)
+	case strings.HasPrefix(t, "enum("):
+		options := enumValues.FindAllStringSubmatch(c.Type, -1)
+		if len(options) > 0 {
+			return strings.ReplaceAll(options[g.rng.Intn(len(options))][1], "''", "'")
+		}
+	case isIntegerType(t):
+		if c.unique {
+			c.next++
+			return strconv.FormatInt(c.next, 10)
+		}
+		switch {
+		case strings.Contains(t, "tinyint"):
+			return strconv.Itoa(g.rng.Intn(100))
+		case strings.Contains(t, "smallint"):
+			return strconv.Itoa(g.rng.Intn(10000))
+		}
+		return strconv.Itoa(1 + g.rng.Intn(100000))
+	case strings.Contains(t, "numeric") || strings.Contains(t, "decimal") || strings.Contains(t, "real") ||
+		strings.Contains(t, "float") || strings.Contains(t, "double") || strings.Contains(t, "money") || strings.Contains(t, "number"):
+		return fmt.Sprintf("%.2f", g.rng.Float64()*1000)
+	case strings.Contains(t, "uuid"):
+		b := make([]byte, 16)
+		g.rng.Read(b)
+		b[6], b[8] = b[6]&0x0f|0x40, b[8]&0x3f|0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case strings.Contains(t, "timestamp") || strings.Contains(t, "datetime"):
+		return g.moment().Format("2006-01-02 15:04:05")
+	case strings.HasPrefix(t, "date"):
+		return g.moment().Format("2006-01-02")
+	case strings.HasPrefix(t, "time"):
+		return g.moment().Format("15:04:05")
+	case strings.HasPrefix(t, "json"):
+		return fmt.Sprintf(`{"n": %d}`, g.seq)
+	}
+
+	text := fmt.Sprintf("%s %d", c.Name, g.rng.Intn(100000))
+	if c.unique {
+		text = fmt.Sprintf("%s %s-%d", c.Name, g.run, g.seq)
+	}
+	if m := typeLength.FindStringSubmatch(t); m != nil {
+		if n, _ := strconv.Atoi(m[1]); n > 0 && len(text) > n {
+			text = text[len(text)-n:] // The tail keeps unique values apart
+		}
+	}
+	return text
+}
+
+// moment returns a random time within the past year
+func (g *dataGenerator) moment() time.Time {
+	return time.Now().Add(-time.Duration(g.rng.Int63n(int64(365 * 24 * time.Hour)))).UTC()
+}
+
+// batchInsert renders one INSERT of rows of SQL literals
+func batchInsert(dt db.DriverType, table string, columns []string, rows [][]string) string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = quoteIdent(dt, c)
+	}
+	tuples := make([]string, len(rows))
+	for i, r := range rows {
+		tuples[i] = "(" + strings.Join(r, ", ") + ")"
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", table, strings.Join(names, ", "), strings.Join(tuples, ", "))
+}
+
+// openGeneratePopup opens the row count prompt for a table.
+func (m *Model) openGeneratePopup(tableName string) {
+	if m.showGeneratePopup {
+		return
+	}
+	m.showGeneratePopup = true
+	m.autocompleting = false
+	m.ensureInput(lazyGenerate, &m.generateInput, newGenerateInput)
+	m.generateInput.SetValue("")
+	m.generateInput.Focus()
+	m.generateTable = tableName
+	m.popupStack.Push("generate", func(m *Model) {
+		m.showGeneratePopup = false
+		m.generateInput.Blur()
+		m.generateTable = ""
+	})
+}
+
+// handleGenerateKeys handles keys while the row count prompt is open
+func (m Model) handleGenerateKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc":
+		m.closeTopPopup()
+		return m, nil, true
+	case "enter":
+		value := strings.TrimSpace(m.generateInput.Value())
+		if value == "" {
+			value = m.generateInput.Placeholder
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxGenerateRows {
+			m.errorMsg = fmt.Sprintf("Enter a row count from 1 to %d", maxGenerateRows)
+			return m, nil, true
+		}
+		table := m.generateTable
+		m.closeTopPopup()
+		if m.loading {
+			m.errorMsg = "Wait for the running query before generating data"
+			return m, nil, true
+		}
+		m.loading = true
+		return m, m.generateDataCmd(table, n), true
+	}
+	var cmd tea.Cmd
+	m.generateInput, cmd = m.generateInput.Update(msg)
+	return m, cmd, true
+}
+
+// generateDataCmd inserts n generated rows into table in batches, sampling
+// the parent keys of its foreign keys first
+func (m Model) generateDataCmd(table string, n int) tea.Cmd {
+	columns, constraints := m.columns[table], m.constraints[table]
+	return runTransfer("Generate", func(ctx context.Context, report transferReporter) tea.Msg {
+		if m.driver == nil {
+			return GenerateDataCompleteMsg{Table: table, Err: fmt.Errorf("no database connection")}
+		}
+		dt := m.driver.Type()
+		if dt == db.Cassandra {
+			return GenerateDataCompleteMsg{Table: table, Err: fmt.Errorf("data generation is not supported for Cassandra")}
+		}
+		if len(columns) == 0 {
+			return GenerateDataCompleteMsg{Table: table, Err: fmt.Errorf("no columns loaded for %s", table)}
+		}
+
+		g := &dataGenerator{
+			dt:      dt,
+			columns: generatedColumns(dt, columns, constraints),
+			fks:     foreignKeys(constraints),
+			rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+			run:     strconv.FormatInt(time.Now().Unix()%1000000, 36),
+		}
+		if len(g.columns) == 0 {
+			return GenerateDataCompleteMsg{Table: table, Err: fmt.Errorf("every column of %s has a default", table)}
+		}
+
+		if err := m.sampleParents(ctx, g); err != nil {
+			return GenerateDataCompleteMsg{Table: table, Err: err}
+		}
+		// Unique integers continue after the largest existing value
+		for i := range g.columns {
+			c := &g.columns[i]
+			if !c.unique || !isIntegerType(c.Type) {
+				continue
+			}
+			result, err := m.execute(ctx, fmt.Sprintf("SELECT MAX(%s) FROM %s", quoteIdent(dt, c.Name), table))
+			if err != nil {
+				return GenerateDataCompleteMsg{Table: table, Err: err}
+			}
+			if len(result.Rows) > 0 && len(result.Rows[0]) > 0 {
+				c.next, _ = strconv.ParseInt(result.Rows[0][0], 10, 64)
+			}
+		}
+
+		names := make([]string, len(g.columns))
+		for i, c := range g.columns {
+			names[i] = c.Name
+		}
+		inserted := 0
+		for inserted < n {
+			if err := ctx.Err(); err != nil {
+				return GenerateDataCompleteMsg{Table: table, Rows: inserted, Err: err}
+			}
+			report(inserted, n, 0)
+			rows := make([][]string, min(generateBatch, n-inserted))
+			for i := range rows {
+				rows[i] = g.row()
+			}
+			if _, err := m.execute(ctx, batchInsert(dt, table, names, rows)); err != nil {
+				return GenerateDataCompleteMsg{Table: table, Rows: inserted, Err: err}
+			}
+			inserted += len(rows)
+		}
+		return GenerateDataCompleteMsg{Table: table, Rows: inserted}
+	})
+}
+
+// sampleParents reads up to parentSample keys of each foreign key's parent
+// table. A NOT NULL foreign key needs at least one.
+func (m Model) sampleParents(ctx context.Context, g *dataGenerator) error {
+	for i := range g.fks {
+		fk := &g.fks[i]
+		refs := make([]string, len(fk.RefColumns))
+		conds := make([]string, len(fk.RefColumns))
+		for j, col := range fk.RefColumns {
+			refs[j] = quoteIdent(g.dt, col)
+			conds[j] = refs[j] + " IS NOT NULL"
+		}
+		query := fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s LIMIT %d",
+			strings.Join(refs, ", "), fk.RefTable, strings.Join(conds, " AND "), parentSample)
+		result, err := m.execute(ctx, query)
+		if err != nil {
+			return fmt.Errorf("sampling %s: %w", fk.RefTable, err)
+		}
+		fk.parents = result.Rows
+		if len(fk.parents) > 0 {
+			continue
+		}
+		for _, c := range g.columns {
+			if !c.Nullable && slices.Contains(fk.Columns, c.Name) {
+				return fmt.Errorf("%s has no rows for %s to reference", fk.RefTable, c.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (m Model) renderGeneratePopup(main string) string {
+	var content strings.Builder
+	popupWidth := 60
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render(
+		fmt.Sprintf("Generate rows for: %s", m.generateTable))
+	content.WriteString(title)
+	content.WriteString("\n\n")
+	content.WriteString(m.generateInput.View())
+	content.WriteString("\n\n")
+	content.WriteString(m.renderHintLine("Enter: generate • Esc: cancel", m.popupInnerWidth(popupWidth)))
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(10).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
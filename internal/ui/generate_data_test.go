@@ -0,0 +1,57 @@
+// internal/ui/generate_data_test.go
+package ui
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+func TestGenerateRows(t *testing.T) {
+	cols := []db.Column{
+		{Name: "id", Type: "int(11)", Key: "PRI"},
+		{Name: "email", Type: "varchar(12)", Key: "UNI"},
+		{Name: "status", Type: "enum('new','it''s')"},
+		{Name: "team_id", Type: "int", Key: "MUL"},
+		{Name: "created_at", Type: "datetime", Default: "CURRENT_TIMESTAMP"},
+	}
+	constraints := []db.Constraint{{Type: "FOREIGN KEY", Columns: []string{"team_id"}, RefTable: "teams", RefColumns: []string{"id"}}}
+
+	g := &dataGenerator{
+		dt:      db.MySQL,
+		columns: generatedColumns(db.MySQL, cols, constraints),
+		fks:     foreignKeys(constraints),
+		rng:     rand.New(rand.NewSource(1)),
+		run:     "abc",
+	}
+	var names []string
+	for _, c := range g.columns {
+		names = append(names, c.Name)
+	}
+	if got := strings.Join(names, ","); got != "email,status,team_id" {
+		t.Fatalf("generated columns = %s, want email,status,team_id", got)
+	}
+
+	g.fks[0].parents = [][]string{{"7"}}
+	seen := map[string]bool{}
+	for range 50 {
+		row := g.row()
+		if len(row[0]) > 14 || seen[row[0]] {
+			t.Fatalf("email %s is not a unique value of at most 12 characters", row[0])
+		}
+		seen[row[0]] = true
+		if row[1] != "'new'" && row[1] != `'it\'s'` {
+			t.Errorf("status = %s, want an enum value", row[1])
+		}
+		if row[2] != "7" {
+			t.Errorf("team_id = %s, want the parent key 7", row[2])
+		}
+	}
+
+	want := "INSERT INTO users (`email`, `status`, `team_id`) VALUES ('a', 'new', 7), ('b', 'new', NULL)"
+	if got := batchInsert(db.MySQL, "users", []string{"email", "status", "team_id"}, [][]string{{"'a'", "'new'", "7"}, {"'b'", "'new'", "NULL"}}); got != want {
+		t.Errorf("insert =\n%s\nwant\n%s", got, want)
+	}
+}
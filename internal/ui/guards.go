@@ -0,0 +1,281 @@
+// internal/ui/guards.go
+// Query guards: per-profile lint rules that warn about or block dangerous statements before they run.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// guardViolation is a statement breaking a guard rule
+type guardViolation struct {
+	policy string // config.GuardWarn or config.GuardBlock
+	reason string
+}
+
+// clauseEnds end the table list of a FROM or JOIN
+var clauseEnds = map[string]bool{
+	"WHERE": true, "GROUP": true, "ORDER": true, "HAVING": true, "LIMIT": true, "OFFSET": true,
+	"UNION": true, "INTERSECT": true, "EXCEPT": true, "RETURNING": true, "WINDOW": true, "FOR": true,
+	"SET": true, ";": true,
+}
+
+// checkGuards returns the guard rules each statement of query breaks.
+// The large write rule needs EXPLAIN and is checked by estimateRowsCmd.
+func checkGuards(query string, guards config.QueryGuards) []guardViolation {
+	var violations []guardViolation
+	add := func(policy, reason string) {
+		if policy == config.GuardWarn || policy == config.GuardBlock {
+			violations = append(violations, guardViolation{policy: policy, reason: reason})
+		}
+	}
+	for _, stmt := range splitStatements(query) {
+		tokens := guardTokens(stmt)
+		if len(tokens) == 0 {
+			continue
+		}
+		switch main := skipCTE(tokens); main[0] {
+		case "UPDATE", "DELETE":
+			if !containsToken(main, "WHERE") {
+				add(guards.UnfilteredWrite, main[0]+" without WHERE affects every row")
+			}
+		case "TRUNCATE":
+			add(guards.UnfilteredWrite, "TRUNCATE removes every row")
+		case "DROP":
+			if len(tokens) > 1 && tokens[1] != "TEMPORARY" && tokens[1] != "TEMP" {
+				add(guards.Drop, "DROP "+tokens[1]+" cannot be undone")
+			}
+		}
+		if hasCrossJoin(tokens) {
+			add(guards.CrossJoin, "Cross join without a join condition")
+		}
+	}
+	return violations
+}
+
+// hasCrossJoin reports a CROSS JOIN, a JOIN without ON or USING, or a
+// comma-separated FROM list without WHERE
+func hasCrossJoin(tokens []string) bool {
+	for i, tok := range tokens {
+		switch tok {
+		case "JOIN":
+			if i > 0 && tokens[i-1] == "CROSS" {
+				return true
+			}
+			if i > 0 && tokens[i-1] == "NATURAL" {
+				continue
+			}
+			if !joinHasCondition(tokens[i+1:]) {
+				return true
+			}
+		case "FROM":
+			if commaJoin(tokens[i+1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// joinHasCondition looks for ON or USING after a JOIN at its nesting level
+func joinHasCondition(rest []string) bool {
+	depth := 0
+	for _, tok := range rest {
+		switch {
+		case tok == "(":
+			depth++
+		case tok == ")":
+			if depth == 0 {
+				return false
+			}
+			depth--
+		case depth > 0:
+		case tok == "ON" || tok == "USING":
+			return true
+		case tok == "JOIN" || clauseEnds[tok]:
+			return false
+		}
+	}
+	return false
+}
+
+// commaJoin reports a FROM list of several tables with no WHERE at its nesting level
+func commaJoin(rest []string) bool {
+	depth, comma := 0, false
+	for _, tok := range rest {
+		switch {
+		case tok == "(":
+			depth++
+		case tok == ")":
+			if depth == 0 {
+				return comma
+			}
+			depth--
+		case depth > 0:
+		case tok == ",":
+			comma = true
+		case tok == "WHERE":
+			return false
+		case tok == "JOIN" || clauseEnds[tok]:
+			return comma
+		}
+	}
+	return comma
+}
+
+// guardTokens splits a statement into upper-cased words, parentheses and
+// commas, skipping literals and comments
+func guardTokens(stmt string) []string {
+	var tokens []string
+	for i := 0; i < len(stmt); i++ {
+		c := stmt[i]
+		switch {
+		case strings.HasPrefix(stmt[i:], "--"):
+			if nl := strings.IndexByte(stmt[i:], '\n'); nl >= 0 {
+				i += nl
+			} else {
+				i = len(stmt)
+			}
+		case strings.HasPrefix(stmt[i:], "/*"):
+			if end := strings.Index(stmt[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(stmt)
+			}
+		case c == '\'' || c == '"' || c == '`':
+			for i++; i < len(stmt) && stmt[i] != c; i++ {
+				if stmt[i] == '\\' {
+					i++
+				}
+			}
+			tokens = append(tokens, "literal")
+		case c == '(' || c == ')' || c == ',' || c == ';':
+			tokens = append(tokens, string(c))
+		case c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9':
+			j := i
+			for j < len(stmt) && (stmt[j] == '_' || stmt[j] == '.' || stmt[j] >= 'A' && stmt[j] <= 'Z' || stmt[j] >= 'a' && stmt[j] <= 'z' || stmt[j] >= '0' && stmt[j] <= '9') {
+				j++
+			}
+			tokens = append(tokens, strings.ToUpper(stmt[i:j]))
+			i = j - 1
+		}
+	}
+	return tokens
+}
+
+// skipCTE returns the tokens of the statement after a leading WITH list,
+// or tokens unchanged when there is none
+func skipCTE(tokens []string) []string {
+	if tokens[0] != "WITH" {
+		return tokens
+	}
+	depth := 0
+	for i, tok := range tokens {
+		switch tok {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case "SELECT", "INSERT", "UPDATE", "DELETE", "MERGE", "VALUES", "TABLE":
+			// The main statement follows the closing parenthesis of the last CTE
+			if depth == 0 && i > 0 && tokens[i-1] == ")" {
+				return tokens[i:]
+			}
+		}
+	}
+	return tokens
+}
+
+func containsToken(tokens []string, word string) bool {
+	for _, tok := range tokens {
+		if tok == word {
+			return true
+		}
+	}
+	return false
+}
+
+// estimatedWrites returns the UPDATE and DELETE statements of query, whose
+// affected rows the large write rule estimates
+func estimatedWrites(query string) []string {
+	var writes []string
+	for _, stmt := range splitStatements(query) {
+		if tokens := guardTokens(stmt); len(tokens) > 0 {
+			if main := skipCTE(tokens); main[0] == "UPDATE" || main[0] == "DELETE" {
+				writes = append(writes, stmt)
+			}
+		}
+	}
+	return writes
+}
+
+// estimateRowsCmd explains each write and reports the largest row estimate
+func estimateRowsCmd(estimator db.RowEstimator, query string, writes []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		var most int64
+		for _, stmt := range writes {
+			rows, err := estimator.EstimateRows(ctx, stmt)
+			if err != nil {
+				return RowEstimateMsg{Query: query, Err: err}
+			}
+			most = max(most, rows)
+		}
+		return RowEstimateMsg{Query: query, Rows: most}
+	}
+}
+
+// blockQuery refuses to run query, putting it back in an emptied editor
+func (m *Model) blockQuery(query, reason string) {
+	m.errorMsg = "Blocked by query guard: " + reason
+	if m.editor.Value() == "" {
+		m.setEditorValue(query)
+	}
+}
+
+// handleRowEstimate applies the large write rule to the pending query once
+// EXPLAIN has estimated its rows
+func (m Model) handleRowEstimate(msg RowEstimateMsg) (Model, tea.Cmd) {
+	if !m.confirming || !m.estimatingRows || msg.Query != m.pendingQuery {
+		return m, nil
+	}
+	m.estimatingRows = false
+	guards := m.profile.Guards
+	switch {
+	case msg.Err != nil:
+		m.pendingWarnings = append(m.pendingWarnings, "Could not estimate affected rows: "+msg.Err.Error())
+	case msg.Rows > guards.MaxRows:
+		reason := fmt.Sprintf("Affects about %d rows (limit %d)", msg.Rows, guards.MaxRows)
+		if guards.LargeWrite == config.GuardBlock {
+			m.cancelConfirm()
+			m.blockQuery(msg.Query, reason)
+			return m, nil
+		}
+		m.pendingWarnings = append(m.pendingWarnings, reason)
+	case len(m.pendingWarnings) == 0 && !m.pendingStrict:
+		// Nothing left to confirm
+		query := m.pendingQuery
+		m.cancelConfirm()
+		return m, m.runQuery(query)
+	}
+	return m, nil
+}
+
+// cancelConfirm closes the confirmation prompt without running the query
+func (m *Model) cancelConfirm() {
+	m.confirming = false
+	m.pendingQuery = ""
+	m.pendingCost = ""
+	m.pendingWarnings = nil
+	m.pendingStrict = false
+	m.pendingScript = ""
+	m.estimatingRows = false
+}
@@ -0,0 +1,62 @@
+// internal/ui/guards_test.go
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nhath/ezdb/internal/config"
+)
+
+func TestCheckGuards(t *testing.T) {
+	guards := config.QueryGuards{
+		UnfilteredWrite: config.GuardBlock,
+		Drop:            config.GuardWarn,
+		CrossJoin:       config.GuardWarn,
+	}
+	tests := []struct {
+		query  string
+		policy string // "" when no rule is broken
+	}{
+		{"UPDATE users SET name = 'x' WHERE id = 1", ""},
+		{"UPDATE users SET name = 'where'", config.GuardBlock},
+		{"DELETE FROM users -- WHERE id = 1", config.GuardBlock},
+		{"TRUNCATE users", config.GuardBlock},
+		{"DROP TABLE users", config.GuardWarn},
+		{"DROP TEMPORARY TABLE scratch", ""},
+		{"SELECT * FROM users u JOIN orders o ON o.user_id = u.id", ""},
+		{"SELECT * FROM users u JOIN orders o USING (user_id)", ""},
+		{"SELECT * FROM users CROSS JOIN orders", config.GuardWarn},
+		{"SELECT * FROM users JOIN orders WHERE 1 = 1", config.GuardWarn},
+		{"SELECT * FROM users, orders", config.GuardWarn},
+		{"SELECT * FROM users, orders WHERE orders.user_id = users.id", ""},
+		{"SELECT EXTRACT(YEAR FROM created), id FROM users", ""},
+		{"SELECT * FROM (SELECT a, b FROM t WHERE a > 1) x", ""},
+		{"SELECT 1; DELETE FROM users", config.GuardBlock},
+		{"WITH old AS (SELECT id FROM users WHERE created < now()) DELETE FROM users", config.GuardBlock},
+		{"WITH RECURSIVE t(n) AS (SELECT 1 WHERE true) UPDATE users SET name = 'x'", config.GuardBlock},
+		{"WITH old AS (SELECT id FROM users) DELETE FROM users WHERE id IN (SELECT id FROM old)", ""},
+		{"WITH x AS (SELECT 1), y AS (SELECT 2) SELECT * FROM x JOIN y ON true", ""},
+	}
+	for _, tt := range tests {
+		got := checkGuards(tt.query, guards)
+		switch {
+		case tt.policy == "" && len(got) > 0:
+			t.Errorf("checkGuards(%q) = %+v, want none", tt.query, got)
+		case tt.policy != "" && (len(got) != 1 || got[0].policy != tt.policy):
+			t.Errorf("checkGuards(%q) = %+v, want one %s", tt.query, got, tt.policy)
+		}
+	}
+
+	if got := checkGuards("DELETE FROM users", config.QueryGuards{}); len(got) != 0 {
+		t.Errorf("guards off = %+v, want none", got)
+	}
+}
+
+func TestEstimatedWrites(t *testing.T) {
+	query := "SELECT 1; WITH old AS (SELECT id FROM users) DELETE FROM users WHERE id IN (SELECT id FROM old); UPDATE users SET a = 1"
+	got := estimatedWrites(query)
+	if len(got) != 2 || !strings.HasPrefix(got[0], "WITH old") || !strings.HasPrefix(got[1], "UPDATE") {
+		t.Errorf("estimatedWrites = %q, want the CTE delete and the update", got)
+	}
+}
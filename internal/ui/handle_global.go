@@ -0,0 +1,195 @@
+// internal/ui/handle_global.go
+// Global shortcuts: keys that open views or toggle panes from visual mode, whatever has focus.
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/ui/components/schemabrowser"
+)
+
+// globalController handles quit and the shortcuts that work outside the
+// editor's own keys. Keys it does not take go on to the schema browser and
+// the editor modes.
+func (m Model) globalController(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	// Quit, confirmed when it would interrupt work
+	if matchKey(msg, m.config.Keys.Quit) {
+		m, cmd := m.requestQuit()
+		return m, cmd, true
+	}
+
+	// Tab toggles schema browser (visual mode only, outside schema browser).
+	// A docked sidebar stays open and tab moves focus between panes; esc closes it.
+	if matchKey(msg, m.config.Keys.ToggleSchema) && m.mode == VisualMode {
+		if m.sidebarShown() {
+			m.sidebarBlurred = !m.sidebarBlurred
+			return m, nil, true
+		}
+		m.sidebarBlurred = false
+		m.schemaBrowser = m.schemaBrowser.Toggle()
+		m = m.updateHistoryViewport()
+		if m.schemaBrowser.IsVisible() && m.driver != nil {
+			return m, schemabrowser.LoadSchemaCmd(m.driver), true
+		}
+		return m, nil, true
+	}
+
+	// Help shortcut (when no popup open)
+	if matchKey(msg, m.config.Keys.Help) && !m.hasOpenPopup() {
+		m.openHelpPopup()
+		return m, nil, true
+	}
+
+	// P – reconnect / show profile selector
+	if matchKey(msg, m.config.Keys.ShowProfiles) && m.mode == VisualMode {
+		if m.driver != nil {
+			m.driver.Close()
+			m.driver = nil
+		}
+		m.appState = StateSelectingProfile
+		m.reloadProfiles()
+		return m, nil, true
+	}
+
+	// A – manage attached SQLite databases
+	if matchKey(msg, m.config.Keys.Attach) && m.mode == VisualMode && !m.schemaFocused() {
+		attacher, ok := m.driver.(db.Attacher)
+		if !ok {
+			m.errorMsg = "ATTACH is only available for SQLite"
+			return m, nil, true
+		}
+		m.openAttachPopup()
+		return m, tea.Batch(textinput.Blink, listAttachmentsCmd(attacher)), true
+	}
+
+	// S – switch the search_path schema or MySQL database
+	if matchKey(msg, m.config.Keys.SwitchSchema) && m.mode == VisualMode && !m.schemaFocused() {
+		switcher, ok := m.driver.(db.SchemaSwitcher)
+		if !ok {
+			m.errorMsg = "Switching schema is only available for PostgreSQL and MySQL"
+			return m, nil, true
+		}
+		m.openSchemaSwitch()
+		return m, tea.Batch(textinput.Blink, listSchemasCmd(switcher)), true
+	}
+
+	// Ctrl+A – list the server's sessions
+	if matchKey(msg, m.config.Keys.Activity) && m.mode == VisualMode && !m.schemaFocused() {
+		monitor, ok := m.driver.(db.ActivityMonitor)
+		if !ok {
+			m.errorMsg = "Session activity is only available for PostgreSQL and MySQL"
+			return m, nil, true
+		}
+		m.openActivityPopup()
+		return m, m.listSessionsCmd(monitor), true
+	}
+
+	// D – server dashboard
+	if matchKey(msg, m.config.Keys.Dashboard) && m.mode == VisualMode && !m.schemaFocused() {
+		reporter, ok := m.driver.(db.MetricsReporter)
+		if !ok {
+			m.errorMsg = "The server dashboard is only available for PostgreSQL and MySQL"
+			return m, nil, true
+		}
+		m.openDashboard()
+		m.metrics, m.metricsErr, m.metricsAt = nil, "", time.Time{}
+		return m, m.pollMetricsCmd(reporter), true
+	}
+
+	// H – schema snapshots
+	if matchKey(msg, m.config.Keys.Snapshots) && m.mode == VisualMode && !m.schemaFocused() {
+		if m.historyStore == nil || m.profile == nil {
+			m.errorMsg = "Schema snapshots need the history store"
+			return m, nil, true
+		}
+		m.openSnapshotsPopup()
+		return m, listSnapshotsCmd(m.historyStore, m.snapshotProfile(), ""), true
+	}
+
+	// I – query analytics
+	if matchKey(msg, m.config.Keys.Analytics) && m.mode == VisualMode && !m.schemaFocused() {
+		if m.historyStore == nil || m.profile == nil {
+			m.errorMsg = "Query analytics need the history store"
+			return m, nil, true
+		}
+		m.openAnalyticsPopup()
+		return m, loadAnalyticsCmd(m.historyStore, m.profile.Name), true
+	}
+
+	// T – session transcript of picked history entries
+	if matchKey(msg, m.config.Keys.Transcript) && m.mode == VisualMode && !m.schemaFocused() {
+		m.openTranscriptPopup()
+		return m, nil, true
+	}
+
+	// B – build a SELECT from forms
+	if matchKey(msg, m.config.Keys.QueryBuilder) && m.mode == VisualMode && !m.schemaFocused() {
+		if len(m.tables) == 0 {
+			m.errorMsg = "No tables loaded for the query builder"
+			return m, nil, true
+		}
+		return m, m.openQueryBuilder(), true
+	}
+
+	// R – re-run the selected history entry on another profile
+	if matchKey(msg, m.config.Keys.RunOn) && m.mode == VisualMode && !m.schemaFocused() {
+		if m.selected < 0 || m.selected >= len(m.history) || m.history[m.selected].Status == "info" {
+			return m, nil, true
+		}
+		m.openRunOnPopup(m.history[m.selected])
+		return m, nil, true
+	}
+
+	// E – explain why the selected history entry failed
+	if matchKey(msg, m.config.Keys.ExplainError) && m.mode == VisualMode && !m.schemaFocused() {
+		if m.selected < 0 || m.selected >= len(m.history) || m.history[m.selected].Status != "error" {
+			m.statusMsg = "Select a failed query to explain"
+			return m, nil, true
+		}
+		m.openErrorHelp(m.history[m.selected])
+		return m, nil, true
+	}
+
+	// Ctrl+G – ask the assistant to write SQL
+	if matchKey(msg, m.config.Keys.Assistant) && !m.schemaFocused() {
+		return m, m.openAssistantPopup(), true
+	}
+
+	// Open a SQL file into the editor or save the editor to one
+	if matchKey(msg, m.config.Keys.OpenFile) && !m.schemaFocused() {
+		return m, m.openFilePopup("/open "), true
+	}
+	if matchKey(msg, m.config.Keys.SaveFile) && !m.schemaFocused() {
+		return m, m.openFilePopup("/save " + m.currentFile), true
+	}
+
+	// N – notification center
+	if matchKey(msg, m.config.Keys.Notifications) && m.mode == VisualMode && !m.schemaFocused() {
+		m.openNotificationsPopup()
+		return m, nil, true
+	}
+
+	// Expand the results dock into the full results popup
+	if matchKey(msg, m.config.Keys.ExpandDock) && m.dockShown() && !m.hasOpenPopup() {
+		m.expandDock()
+		return m, nil, true
+	}
+
+	// </> resize the docked sidebar
+	if m.sidebarShown() && m.mode == VisualMode {
+		if matchKey(msg, m.config.Keys.SidebarGrow) {
+			m, cmd := m.resizeSidebar(sidebarRatioStep)
+			return m, cmd, true
+		}
+		if matchKey(msg, m.config.Keys.SidebarShrink) {
+			m, cmd := m.resizeSidebar(-sidebarRatioStep)
+			return m, cmd, true
+		}
+	}
+
+	return m, nil, false
+}
@@ -5,6 +5,7 @@ package ui
 import (
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 
@@ -18,9 +19,7 @@ import (
 func (m Model) handleInsertMode(msg tea.KeyMsg, cmds []tea.Cmd) (Model, []tea.Cmd) {
 	var cmd tea.Cmd
 
-	hasPopup := m.hasOpenPopup() || m.showPopup || m.showHelpPopup || m.showTemplatePopup ||
-		m.showImportPopup || m.showExportPopup || m.showRowActionPopup || m.showActionPopup ||
-		m.themeSelector.Visible()
+	hasPopup := m.hasOpenPopup()
 
 	// Autocomplete navigation / apply
 	if m.autocompleting && !hasPopup {
@@ -45,6 +44,16 @@ func (m Model) handleInsertMode(msg tea.KeyMsg, cmds []tea.Cmd) (Model, []tea.Cm
 		}
 	}
 
+	// Tab/Shift+Tab – jump between snippet tab-stops
+	if len(m.tabStops) > 0 && !hasPopup {
+		switch msg.String() {
+		case "tab":
+			return m, append(cmds, m.nextTabStop(1))
+		case "shift+tab":
+			return m, append(cmds, m.nextTabStop(-1))
+		}
+	}
+
 	// Ctrl+Space – open autocomplete
 	if matchKey(msg, m.config.Keys.Autocomplete) && !hasPopup {
 		m.autocompleting = true
@@ -56,16 +65,12 @@ func (m Model) handleInsertMode(msg tea.KeyMsg, cmds []tea.Cmd) (Model, []tea.Cm
 	if matchKey(msg, m.config.Keys.Execute) {
 		query := strings.TrimSpace(m.editor.Value())
 		if query != "" {
-			m.editor.SetValue("")
+			cmds = append(cmds, m.endSnippet())
+			m.setEditorValue("")
 			m.editor.Reset()
+			cmds = append(cmds, m.saveEditsCmd())
 
-			if m.strictMode && isModifyingQuery(query) {
-				m.confirming = true
-				m.pendingQuery = query
-				return m, cmds
-			}
-			m.loading = true
-			cmds = append(cmds, m.executeQueryCmd(query))
+			cmds = append(cmds, m.confirmOrRun(query))
 		}
 		return m, cmds
 	}
@@ -74,22 +79,31 @@ func (m Model) handleInsertMode(msg tea.KeyMsg, cmds []tea.Cmd) (Model, []tea.Cm
 	if matchKey(msg, m.config.Keys.Explain) {
 		query := strings.TrimSpace(m.editor.Value())
 		if query != "" && m.driver != nil {
+			if m.driver.Type() == db.Cassandra {
+				m.errorMsg = "EXPLAIN is not supported by CQL"
+				return m, cmds
+			}
+			// BigQuery has no EXPLAIN; a dry run reports the bytes scanned instead
+			if estimator, ok := m.driver.(db.CostEstimator); ok {
+				cmds = append(cmds, estimateCostCmd(estimator, query))
+				return m, cmds
+			}
 			explainQuery := "EXPLAIN " + query
-			if m.driver.Type() == db.SQLite {
+			if explainer, ok := m.driver.(db.Explainer); ok {
+				explainQuery = explainer.ExplainQuery(query)
+			} else if m.driver.Type() == db.SQLite {
 				explainQuery = "EXPLAIN QUERY PLAN " + query
 			}
-			m.loading = true
-			cmds = append(cmds, m.executeQueryCmd(explainQuery))
+			// EXPLAIN ANALYZE runs the statement, so it is confirmed like one
+			cmds = append(cmds, m.confirmOrRun(explainQuery))
 		}
 		return m, cmds
 	}
 
 	// Undo
 	if matchKey(msg, m.config.Keys.Undo) {
-		if len(m.undoStack) > 0 {
-			m.redoStack = append(m.redoStack, m.editor.Value())
-			prev := m.undoStack[len(m.undoStack)-1]
-			m.undoStack = m.undoStack[:len(m.undoStack)-1]
+		cmds = append(cmds, m.endSnippet())
+		if prev, ok := m.edits.Undo(m.editor.Value()); ok {
 			m.editor.SetValue(prev)
 		}
 		return m, cmds
@@ -97,10 +111,8 @@ func (m Model) handleInsertMode(msg tea.KeyMsg, cmds []tea.Cmd) (Model, []tea.Cm
 
 	// Redo
 	if matchKey(msg, m.config.Keys.Redo) {
-		if len(m.redoStack) > 0 {
-			m.undoStack = append(m.undoStack, m.editor.Value())
-			next := m.redoStack[len(m.redoStack)-1]
-			m.redoStack = m.redoStack[:len(m.redoStack)-1]
+		cmds = append(cmds, m.endSnippet())
+		if next, ok := m.edits.Redo(m.editor.Value()); ok {
 			m.editor.SetValue(next)
 		}
 		return m, cmds
@@ -108,6 +120,7 @@ func (m Model) handleInsertMode(msg tea.KeyMsg, cmds []tea.Cmd) (Model, []tea.Cm
 
 	// Esc – back to visual mode
 	if matchKey(msg, m.config.Keys.Exit) || msg.String() == "esc" {
+		cmds = append(cmds, m.endSnippet())
 		m.mode = VisualMode
 		m.editor.Blur()
 		if len(m.history) > 0 {
@@ -117,9 +130,24 @@ func (m Model) handleInsertMode(msg tea.KeyMsg, cmds []tea.Cmd) (Model, []tea.Cm
 		return m, cmds
 	}
 
+	// Typing over a selected placeholder replaces it
+	if m.tabStopSelected {
+		m.tabStopSelected = false
+		if replacesSelection(msg) {
+			m.clearTabStop()
+			if msg.Type == tea.KeyBackspace || msg.Type == tea.KeyDelete {
+				return m, cmds
+			}
+		}
+	}
+
 	// Pass key to the textarea editor
+	beforeLen, beforeCursor := utf8.RuneCountInString(m.editor.Value()), m.editorOffset()
 	m.editor, cmd = m.editor.Update(msg)
 	cmds = append(cmds, cmd)
+	if len(m.tabStops) > 0 {
+		cmds = append(cmds, m.trackTabStops(beforeLen, beforeCursor))
+	}
 
 	// --- Post-keystroke autocomplete logic ---
 	val := m.editor.Value()
@@ -143,6 +171,16 @@ func (m Model) handleInsertMode(msg tea.KeyMsg, cmds []tea.Cmd) (Model, []tea.Cm
 	return m, cmds
 }
 
+// handleDebounce refreshes suggestions and lints the editor once typing has paused
+func (m Model) handleDebounce(msg DebounceMsg) (Model, tea.Cmd) {
+	if msg.ID != m.debounceID {
+		return m, nil
+	}
+	m = m.updateSuggestions()
+	m.autocompleting = len(m.suggestions) > 0
+	return m.lintEditor()
+}
+
 // updateSuggestions refreshes autocomplete suggestions based on cursor position.
 func (m Model) updateSuggestions() Model {
 	text := m.editor.Value()
@@ -166,16 +204,26 @@ func (m Model) updateSuggestions() Model {
 
 	// Parse SQL context and fetch suggestions
 	ctx := autocomplete.ParseSQLContext(text, cursorPos)
-	suggestions := autocomplete.GetSuggestions(ctx, m.tables, m.columns, word)
+	if m.driver != nil {
+		ctx.Driver = m.driver.Type()
+	}
+	if reporter, ok := m.driver.(db.FlavorReporter); ok {
+		ctx.Dialect = reporter.Flavor()
+	}
+	tables, columns := autocomplete.InSchema(m.currentSchema, m.tables, m.columns)
+	suggestions := autocomplete.GetSuggestions(ctx, tables, columns, m.constraints, m.functions, word)
+	suggestions = autocomplete.WithHistory(suggestions, m.historyIndex, text[:cursorPos], word)
 
 	// Convert to display slices
 	m.suggestions = make([]string, len(suggestions))
 	m.suggestionDetails = make([]string, len(suggestions))
 	m.suggestionTypes = make([]autocomplete.SuggestionType, len(suggestions))
+	m.suggestionSnips = make([]string, len(suggestions))
 	for i, s := range suggestions {
 		m.suggestions[i] = s.Text
 		m.suggestionDetails[i] = s.Detail
 		m.suggestionTypes[i] = s.Type
+		m.suggestionSnips[i] = s.Snippet
 	}
 
 	m.suggestionIdx = 0
@@ -194,6 +242,21 @@ func (m Model) applySuggestion() Model {
 	if row >= len(lines) {
 		return m
 	}
+
+	// A past query replaces everything typed up to the end of the current line
+	if m.suggestionIdx < len(m.suggestionTypes) && m.suggestionTypes[m.suggestionIdx] == autocomplete.SuggestSnippet {
+		rest := ""
+		if row+1 < len(lines) {
+			rest = "\n" + strings.Join(lines[row+1:], "\n")
+		}
+		m.setEditorValue(selected + rest)
+		return m
+	}
+	if m.suggestionIdx < len(m.suggestionSnips) && m.suggestionSnips[m.suggestionIdx] != "" {
+		selected = m.suggestionSnips[m.suggestionIdx]
+	}
+	selected, stops := expandSnippet(selected)
+
 	line := lines[row]
 	col := len(line)
 	_, start, end := autocomplete.GetWordAtCursor(line, col)
@@ -206,6 +269,14 @@ func (m Model) applySuggestion() Model {
 	}
 	lines[row] = prefix + selected + suffix
 	m.editor.SetValue(strings.Join(lines, "\n"))
+	if len(stops) > 0 {
+		base := utf8.RuneCountInString(prefix)
+		for _, l := range lines[:row] {
+			base += utf8.RuneCountInString(l) + 1
+		}
+		m.startSnippet(stops, base)
+		return m
+	}
 
 	// Move cursor to end of inserted text
 	newCol := start + len(selected)
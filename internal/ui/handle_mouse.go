@@ -0,0 +1,105 @@
+// internal/ui/handle_mouse.go
+// Mouse support: click to select/focus, wheel to scroll. Disabled with disable_mouse.
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// wheelKey translates a wheel event into the arrow key it stands for
+func wheelKey(msg tea.MouseMsg) tea.KeyMsg {
+	if msg.Button == tea.MouseButtonWheelUp {
+		return tea.KeyMsg{Type: tea.KeyUp}
+	}
+	return tea.KeyMsg{Type: tea.KeyDown}
+}
+
+// handleMouse routes mouse events to the schema browser, the topmost popup or the main screen
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	wheel := msg.Button == tea.MouseButtonWheelUp || msg.Button == tea.MouseButtonWheelDown
+	click := msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress
+	if m.appState != StateReady || (!wheel && !click) {
+		return m, nil
+	}
+
+	// Docked sidebar: clicks on either pane move focus there
+	if m.sidebarShown() {
+		if msg.X < m.sidebarWidth() {
+			m.sidebarBlurred = false
+			var cmd tea.Cmd
+			m.schemaBrowser, cmd = m.schemaBrowser.Update(msg)
+			return m, cmd
+		}
+		m.sidebarBlurred = true
+		msg.X -= m.sidebarWidth()
+	} else if m.schemaBrowser.IsVisible() && !m.hasOpenPopup() {
+		var cmd tea.Cmd
+		m.schemaBrowser, cmd = m.schemaBrowser.Update(msg)
+		return m, cmd
+	}
+
+	if m.hasOpenPopup() || m.confirming || m.quitConfirm {
+		if wheel {
+			return m.Update(wheelKey(msg))
+		}
+		if m.popupStack.TopName() == "results" {
+			m = m.clickResultsRow(msg.Y)
+		}
+		return m, nil
+	}
+
+	if wheel {
+		if msg.Button == tea.MouseButtonWheelUp {
+			m.historyList = m.historyList.ScrollUp(3)
+		} else {
+			m.historyList = m.historyList.ScrollDown(3)
+		}
+		return m, nil
+	}
+
+	// Click in the history viewport selects an entry
+	if msg.Y < m.historyList.Height() {
+		if i := m.historyList.ItemAt(msg.Y); i >= 0 {
+			if m.mode == InsertMode {
+				m.mode = VisualMode
+				m.editor.Blur()
+				m.autocompleting = false
+			}
+			m.selected = i
+			m = m.updateHistoryViewport()
+		}
+		return m, nil
+	}
+
+	// Click below the history (the editor) focuses the editor
+	if m.mode != InsertMode {
+		m.mode = InsertMode
+		m.editor.Focus()
+		m = m.updateHistoryViewport()
+		return m, textinput.Blink
+	}
+	return m, nil
+}
+
+// clickResultsRow highlights the result row under screen line y
+func (m Model) clickResultsRow(y int) Model {
+	if m.popupEntry == nil || m.popupResult == nil || len(m.popupResult.Columns) == 0 {
+		return m
+	}
+	box := m.resultsPopupBox()
+	top := (m.height-lipgloss.Height(box))/2 +
+		m.theme.PopupStyle.GetBorderTopSize() + m.theme.PopupStyle.GetPaddingTop() +
+		lipgloss.Height(m.resultsPopupHeader())
+	// Table border, header row and header separator
+	top += 3
+
+	start, end := m.popupTable.VisibleIndices()
+	row := start + y - top
+	if y < top || row > end {
+		return m
+	}
+	m.popupTable = m.popupTable.WithHighlightedRow(row)
+	return m
+}
@@ -4,10 +4,8 @@ package ui
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -20,73 +18,141 @@ import (
 // Returns (model, cmd, handled). If handled is false the caller must
 // continue dispatching.
 func (m Model) handlePopupKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
-	// Universal popup close handler
-	isExitKey := matchKey(msg, m.config.Keys.Exit) || msg.String() == "esc" || msg.String() == "q"
-	hasPopup := m.hasOpenPopup() || m.showPopup || m.showHelpPopup || m.showTemplatePopup ||
-		m.showImportPopup || m.showExportPopup || m.showRowActionPopup || m.showActionPopup ||
-		m.themeSelector.Visible()
-
-	if hasPopup && isExitKey {
-		f, _ := os.OpenFile("debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-		fmt.Fprintf(f, "Exit key pressed. Stack len: %d. Top: %s\n", m.popupStack.Len(), m.popupStack.TopName())
-		f.Close()
-		if m.closeTopPopup() {
-			return m, nil, true
-		}
-		// Fallback: close any open popup directly
-		if m.showRowActionPopup {
-			m.showRowActionPopup = false
-			return m, nil, true
-		}
-		if m.showExportPopup {
-			m.showExportPopup = false
-			m.exportInput.Blur()
-			return m, nil, true
-		}
-		if m.showActionPopup {
-			m.showActionPopup = false
-			return m, nil, true
-		}
-		if m.showPopup {
-			m.showPopup = false
-			m.tableFilterInput.Blur()
-			m.tableFilterInput.SetValue("")
-			return m, nil, true
-		}
-		if m.showTemplatePopup {
-			m.showTemplatePopup = false
-			m.templateTable = ""
-			m.templateIdx = 0
-			return m, nil, true
-		}
-		if m.showImportPopup {
-			m.showImportPopup = false
-			m.importInput.Blur()
-			m.importTable = ""
-			return m, nil, true
-		}
-		if m.showHelpPopup {
-			m.showHelpPopup = false
-			return m, nil, true
-		}
-		if m.themeSelector.Visible() {
-			m.themeSelector = m.themeSelector.Hide()
+	// Help search input captures keys while focused
+	if m.showHelpPopup && m.helpFilterActive {
+		switch msg.Type {
+		case tea.KeyEnter, tea.KeyEsc:
+			m.helpFilterActive = false
+			m.helpFilterInput.Blur()
 			return m, nil, true
 		}
+		var cmd tea.Cmd
+		m.helpFilterInput, cmd = m.helpFilterInput.Update(msg)
+		return m, cmd, true
+	}
+
+	// Attach manager input captures keys (including q) while open
+	if m.showAttachPopup {
+		return m.handleAttachKeys(msg)
+	}
+
+	// Schema switcher filter captures keys (including q) while open
+	if m.showSchemaSwitch {
+		return m.handleSchemaSwitchKeys(msg)
+	}
+
+	// Keybindings editor captures keys (including q) while open
+	if m.showKeybindPopup {
+		return m.handleKeybindKeys(msg)
+	}
+
+	// Template manager captures keys (including q) while open
+	if m.showTemplateEditor {
+		return m.handleTemplateEditorKeys(msg)
+	}
+
+	// Generated row count prompt captures keys (including q) while open
+	if m.showGeneratePopup {
+		return m.handleGenerateKeys(msg)
+	}
+
+	// Truncate / drop confirmation captures keys (including q) while open
+	if m.showTableAction {
+		return m.handleTableActionKeys(msg)
+	}
+
+	// Copy table wizard captures keys (including q) while open
+	if m.showCopyTable {
+		return m.handleCopyTableKeys(msg)
+	}
+
+	// Assistant prompt captures keys (including q) while open
+	if m.showAssistant {
+		return m.handleAssistantKeys(msg)
+	}
+
+	// Query builder captures keys (including q) while open
+	if m.showQueryBuilder {
+		return m.handleQueryBuilderKeys(msg)
+	}
+
+	// Schema snapshots capture keys (including q) while open
+	if m.showSnapshots {
+		return m.handleSnapshotsKeys(msg)
+	}
+
+	// File prompt captures keys (including q) while open
+	if m.showFilePopup {
+		return m.handleFileKeys(msg)
+	}
+
+	// Data browser filter input captures keys while focused
+	if m.showBrowsePopup && m.browseFilterActive {
+		return m.handleBrowseFilterKeys(msg)
+	}
+
+	// Universal popup close handler
+	isExitKey := matchKey(msg, m.config.Keys.Exit) || msg.String() == "esc" || msg.String() == "q"
+	if isExitKey && m.closeTopPopup() {
+		return m, nil, true
+	}
+
+	// Notification center
+	if m.showNotificationsPopup {
+		return m.handleNotificationsKeys(msg)
+	}
+
+	// Run a history entry on another profile
+	if m.showRunOn {
+		return m.handleRunOnKeys(msg)
+	}
+
+	// Explanation of a failed query
+	if m.showErrorHelp {
+		return m.handleErrorHelpKeys(msg)
+	}
+
+	// Session transcript
+	if m.showTranscript {
+		return m.handleTranscriptKeys(msg)
+	}
+
+	// Query analytics
+	if m.showAnalytics {
+		return m.handleAnalyticsKeys(msg)
+	}
+
+	// Server dashboard
+	if m.showDashboard {
+		return m.handleDashboardKeys(msg)
+	}
+
+	// Activity view
+	if m.showActivityPopup {
+		return m.handleActivityKeys(msg)
+	}
+
+	// Table data browser
+	if m.showBrowsePopup && !m.showHelpPopup {
+		return m.handleBrowseKeys(msg)
 	}
 
 	// Confirming prompt (y/n for destructive queries)
 	if m.confirming {
 		switch msg.String() {
 		case "y", "Y":
-			m.confirming = false
-			m.loading = true
-			query := m.pendingQuery
-			m.pendingQuery = ""
-			return m, m.executeQueryCmd(query), true
+			if m.estimatingRows {
+				// The large write rule may still block the query
+				return m, nil, true
+			}
+			query, script := m.pendingQuery, m.pendingScript
+			m.cancelConfirm()
+			if script != "" {
+				return m, m.runSQLFileCmd(script, query), true
+			}
+			return m, m.runQuery(query), true
 		case "n", "N", "esc":
-			m.confirming = false
-			m.pendingQuery = ""
+			m.cancelConfirm()
 			return m, nil, true
 		}
 		return m, nil, true
@@ -101,7 +167,7 @@ func (m Model) handlePopupKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
 		var cmd tea.Cmd
 		m.themeSelector, cmd = m.themeSelector.Update(msg)
 		if !m.themeSelector.Visible() && m.popupStack.TopName() == "theme" {
-			m.popupStack.Pop()
+			m.closeTopPopup()
 		}
 		return m, cmd, true
 	}
@@ -112,6 +178,15 @@ func (m Model) handlePopupKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
 			m.closeTopPopup()
 			return m, nil, true
 		}
+		if matchKey(msg, m.config.Keys.Filter) {
+			m.helpFilterActive = true
+			m.ensureInput(lazyHelpFilter, &m.helpFilterInput, newHelpFilterInput)
+			return m, m.helpFilterInput.Focus(), true
+		}
+		if m.pressed(msg, "HelpEditKeys") {
+			m.openKeybindPopup()
+			return m, nil, true
+		}
 		return m, nil, true
 	}
 
@@ -124,33 +199,66 @@ func (m Model) handlePopupKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
 			}
 			return m, nil, true
 		case "down", "j":
-			if m.templateIdx < len(m.config.QueryTemplates)-1 {
+			if m.templateIdx < len(m.queryTemplates())-1 {
 				m.templateIdx++
 			}
 			return m, nil, true
 		case "enter":
-			m.popupStack.Pop()
 			model, cmd := m.executeTemplate()
 			return model, cmd, true
 		case "i":
-			m.popupStack.Pop()
 			m = m.insertTemplate()
 			return m, nil, true
 		}
+		if m.pressed(msg, "TemplateManage") {
+			m.openTemplateEditor()
+		}
 		return m, nil, true
 	}
 
 	// Import popup
 	if m.showImportPopup {
+		if msg.String() == "tab" {
+			m.completePathInput(&m.importInput)
+			return m, nil, true
+		}
+		switch msg.String() {
+		case "ctrl+t":
+			m.importOpts.DryRun = !m.importOpts.DryRun
+			return m, nil, true
+		case "ctrl+g":
+			next := 0
+			for i, n := range importBatchSizes {
+				if n == m.importOpts.BatchSize {
+					next = (i + 1) % len(importBatchSizes)
+				}
+			}
+			m.importOpts.BatchSize = importBatchSizes[next]
+			return m, nil, true
+		case "ctrl+x":
+			m.importOpts.Transaction = !m.importOpts.Transaction
+			return m, nil, true
+		case "ctrl+r":
+			if m.importOpts.ResumeRow > 0 {
+				m.importOpts.ResumeRow = 0
+			} else if r := m.importResume; r != nil && r.Table == m.importTable {
+				m.importOpts.ResumeRow = r.Row
+			}
+			return m, nil, true
+		}
+		m.pathMatches = nil
 		if msg.String() == "enter" {
 			filename := m.importInput.Value()
 			if filename != "" {
-				m.popupStack.Pop()
-				m.showImportPopup = false
-				m.importInput.Blur()
-				m.importTable = ""
+				tableName := m.importTable
+				m.closeTopPopup()
 				m.loading = true
-				return m, m.importTableCmd(m.importTable, filename), true
+				opts := m.importOpts
+				if r := m.importResume; opts.ResumeRow > 0 && (r == nil || r.File != filename) {
+					// The resume point belongs to another file
+					opts.ResumeRow = 0
+				}
+				return m, m.importTableCmd(tableName, filename, opts), true
 			}
 			return m, nil, true
 		}
@@ -161,20 +269,64 @@ func (m Model) handlePopupKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
 
 	// Export popup
 	if m.showExportPopup {
+		if m.exportTable != "" {
+			if model, cmd, handled := m.handleExportFilterKeys(msg); handled {
+				return model, cmd, true
+			}
+		}
+		if msg.String() == "tab" {
+			m.completePathInput(&m.exportInput)
+			return m, nil, true
+		}
+		m.pathMatches = nil
 		if msg.String() == "enter" {
-			filename := m.exportInput.Value()
+			if m.exportTable != "" {
+				if err := m.exportFilter().Validate(); err != nil {
+					m.errorMsg = err.Error()
+					return m, nil, true
+				}
+				if m.confirmLargeExport() {
+					m.exportConfirm = true
+					return m, nil, true
+				}
+			}
+			filename := strings.TrimSpace(m.exportInput.Value())
 			if filename == "" {
 				filename = "export.csv"
 			}
-			m.popupStack.Pop()
-			m.showExportPopup = false
-			m.exportInput.Blur()
+			if (filename == exportClipboard || filename == exportStdout) && m.exportTable != "" {
+				m.errorMsg = "Tables export to files only; clipboard: and - take query results"
+				return m, nil, true
+			}
+			m.closeTopPopup()
+			switch filename {
+			case exportClipboard:
+				text, err := resultCSV(m.popupResult)
+				if err != nil {
+					m.errorMsg = err.Error()
+					return m, nil, true
+				}
+				return m, m.copyToClipboardCmd(text), true
+			case exportStdout:
+				text, err := resultCSV(m.popupResult)
+				if err != nil {
+					m.errorMsg = err.Error()
+					return m, nil, true
+				}
+				m.stdoutExport += text
+				m.statusMsg = fmt.Sprintf("%d rows will be printed to stdout on exit", m.popupResult.RowCount)
+				return m, nil, true
+			}
 			if m.exportTable != "" {
 				m.loading = true
-				return m, m.exportTableCmd(m.exportTable, filename), true
+				return m, m.exportTableCmd(m.exportTable, m.exportFilter(), filename), true
+			}
+			if isBundlePath(filename) {
+				return m, m.exportBundleCmd(filename), true
 			}
 			return m, m.exportTableToPath(filename), true
 		}
+		m.exportConfirm = false
 		var cmd tea.Cmd
 		m.exportInput, cmd = m.exportInput.Update(msg)
 		return m, cmd, true
@@ -184,6 +336,9 @@ func (m Model) handlePopupKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
 	if m.showPopup {
 		// Filter input active
 		if m.tableFilterActive {
+			if m.tableFilterServer {
+				return m.handleServerFilterKeys(msg)
+			}
 			if msg.Type == tea.KeyEnter || msg.Type == tea.KeyEsc {
 				m.tableFilterActive = false
 				m.tableFilterInput.Blur()
@@ -195,29 +350,50 @@ func (m Model) handlePopupKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
 			return m, cmd, true
 		}
 
+		if m.showRowDetail {
+			return m.handleRowDetailKeys(msg)
+		}
+
 		// Row action sub-popup
 		if m.showRowActionPopup {
 			switch msg.String() {
 			case "1":
-				m.popupStack.Pop()
+				m.closeTopPopup()
 				model, cmd := m.selectRowAsQuery()
 				return model, cmd, true
 			case "2":
-				m.popupStack.Pop()
-				model, cmd := m.viewFullRow()
-				return model, cmd, true
+				m.closeTopPopup()
+				m.openRowDetail()
+				return m, nil, true
 			case "3":
-				m.popupStack.Pop()
-				m.showRowActionPopup = false
+				m.closeTopPopup()
 				return m, m.copyRowAsJSON(), true
 			case "4":
-				m.popupStack.Pop()
-				m.showRowActionPopup = false
+				m.closeTopPopup()
 				return m, m.copyRowAsCSV(), true
+			case "5", "6":
+				m.closeTopPopup()
+				model, cmd := m.copyRowAsStatement(msg.String() == "6")
+				return model, cmd, true
+			case "7", "8":
+				model, cmd := m.quickFilter(msg.String() == "8")
+				return model, cmd, true
+			case "left", "h":
+				if m.rowActionColumn > 0 {
+					m.rowActionColumn--
+				}
+			case "right", "l":
+				if m.popupResult != nil && m.rowActionColumn < len(m.popupResult.Columns)-1 {
+					m.rowActionColumn++
+				}
 			}
 			return m, nil, true
 		}
 
+		if m.showColumnActions {
+			return m.handleColumnActionKeys(msg)
+		}
+
 		// Action menu sub-popup
 		if m.showActionPopup {
 			return m, nil, true
@@ -229,20 +405,39 @@ func (m Model) handlePopupKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
 			return m, nil, true
 		} else if matchKey(msg, m.config.Keys.Filter) {
 			m.tableFilterActive = true
+			m.ensureInput(lazyTableFilter, &m.tableFilterInput, newTableFilterInput)
+			m.tableFilterInput.Prompt = "/ "
 			m.tableFilterInput.Focus()
 			return m, textinput.Blink, true
+		} else if matchKey(msg, m.config.Keys.ServerFilter) {
+			m.openServerFilter()
+			return m, textinput.Blink, true
 		} else if matchKey(msg, m.config.Keys.RowAction) {
 			m.openRowActionPopup()
 			return m, nil, true
 		} else if matchKey(msg, m.config.Keys.Export) {
 			m.openExportPopup("export.csv")
 			return m, textinput.Blink, true
+		} else if matchKey(msg, m.config.Keys.OpenPager) {
+			return m, m.openPager(m.popupResult), true
+		} else if matchKey(msg, m.config.Keys.FormatNumbers) {
+			m.toggleNumberFormat()
+			return m, nil, true
+		} else if matchKey(msg, m.config.Keys.FullWidth) {
+			m.toggleFullWidth()
+			return m, nil, true
+		} else if matchKey(msg, m.config.Keys.ColumnActions) {
+			m.openColumnActions()
+			return m, nil, true
 		} else if matchKey(msg, m.config.Keys.Help) {
 			m.openHelpPopup()
 			return m, nil, true
 		}
 
 		// Pass remaining keys to the popup table for navigation
+		if next, paged := m.pageResultWindow(msg); paged {
+			return next, nil, true
+		}
 		var cmd tea.Cmd
 		m.popupTable, cmd = m.popupTable.Update(msg)
 		return m, cmd, true
@@ -260,15 +455,19 @@ func (m *Model) openHelpPopup() {
 	}
 	m.showHelpPopup = true
 	m.autocompleting = false
-	f, _ := os.OpenFile("debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-	fmt.Fprintf(f, "Pushing help. Stack len before: %d\n", m.popupStack.Len())
-	f.Close()
-	m.popupStack.Push("help", func(m *Model) bool {
-		m.showHelpPopup = false
-		return true
+	m.popupStack.Push("help", func(m *Model) {
+		m.closeHelpPopup()
 	})
 }
 
+// closeHelpPopup hides the help popup and clears its search.
+func (m *Model) closeHelpPopup() {
+	m.showHelpPopup = false
+	m.helpFilterActive = false
+	m.helpFilterInput.Blur()
+	m.helpFilterInput.SetValue("")
+}
+
 // openTemplatePopup opens the template popup for a given table.
 func (m *Model) openTemplatePopup(tableName string) {
 	if m.showTemplatePopup {
@@ -278,11 +477,10 @@ func (m *Model) openTemplatePopup(tableName string) {
 	m.autocompleting = false
 	m.templateTable = tableName
 	m.templateIdx = 0
-	m.popupStack.Push("template", func(m *Model) bool {
+	m.popupStack.Push("template", func(m *Model) {
 		m.showTemplatePopup = false
 		m.templateTable = ""
 		m.templateIdx = 0
-		return true
 	})
 }
 
@@ -295,18 +493,28 @@ func (m *Model) openResultsPopup(entry *history.HistoryEntry, result *db.QueryRe
 	m.popupResult = result
 	m.showPopup = true
 	m.autocompleting = false
-	f, _ := os.OpenFile("debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-	fmt.Fprintf(f, "Pushing results. Stack len before: %d\n", m.popupStack.Len())
-	f.Close()
-	m.popupStack.Push("results", func(m *Model) bool {
+	m.popupStack.Push("results", func(m *Model) {
 		m.showPopup = false
+		m.tableFilterActive = false
+		m.tableFilterServer = false
 		m.tableFilterInput.Blur()
 		m.tableFilterInput.SetValue("")
 		m.popupTable = m.popupTable.WithFilterInputValue("")
-		return true
 	})
 }
 
+// showResultsPopup builds the results table for result and opens the results popup on it
+func (m *Model) showResultsPopup(entry *history.HistoryEntry, result *db.QueryResult) {
+	m.fullWidthColumn = ""
+	if result != nil {
+		m.popupFrozen = m.frozenColumns(entryTable(entry), result.Columns)
+	}
+	if !m.setPopupWindow(result, 0) {
+		return
+	}
+	m.openResultsPopup(entry, result)
+}
+
 // openRowActionPopup opens the row-action sub-popup.
 func (m *Model) openRowActionPopup() {
 	if m.showRowActionPopup {
@@ -314,9 +522,11 @@ func (m *Model) openRowActionPopup() {
 	}
 	m.showRowActionPopup = true
 	m.autocompleting = false
-	m.popupStack.Push("rowAction", func(m *Model) bool {
+	if m.popupResult != nil {
+		m.rowActionColumn = m.cellColumn()
+	}
+	m.popupStack.Push("rowAction", func(m *Model) {
 		m.showRowActionPopup = false
-		return true
 	})
 }
 
@@ -327,15 +537,36 @@ func (m *Model) openExportPopup(defaultName string) {
 	}
 	m.showExportPopup = true
 	m.autocompleting = false
+	m.ensureInput(lazyExport, &m.exportInput, newExportInput)
 	m.exportInput.SetValue(defaultName)
 	m.exportInput.Focus()
-	m.popupStack.Push("export", func(m *Model) bool {
+	if m.builtInputs&lazyExportFilter == 0 {
+		m.exportFilterInputs = newExportFilterInputs()
+		m.builtInputs |= lazyExportFilter
+	}
+	for i := range m.exportFilterInputs {
+		m.exportFilterInputs[i].SetValue("")
+		m.exportFilterInputs[i].Blur()
+	}
+	m.exportField = 0
+	m.columnMatches = nil
+	m.exportConfirm = false
+	m.pathMatches = nil
+	m.popupStack.Push("export", func(m *Model) {
 		m.showExportPopup = false
+		m.focusExportField(0)
 		m.exportInput.Blur()
-		return true
 	})
 }
 
+// openTableExportPopup opens the export popup for a whole table and starts
+// counting its rows
+func (m *Model) openTableExportPopup(tableName string) tea.Cmd {
+	m.exportTable = tableName
+	m.openExportPopup(tableName + ".csv")
+	return m.estimateExport()
+}
+
 // openImportPopup opens the import filename input popup for a table.
 func (m *Model) openImportPopup(tableName string) {
 	if m.showImportPopup {
@@ -343,14 +574,21 @@ func (m *Model) openImportPopup(tableName string) {
 	}
 	m.showImportPopup = true
 	m.autocompleting = false
+	m.ensureInput(lazyImport, &m.importInput, newImportInput)
 	m.importInput.SetValue("")
 	m.importInput.Focus()
+	m.pathMatches = nil
 	m.importTable = tableName
-	m.popupStack.Push("import", func(m *Model) bool {
+	m.importOpts = importOptions{BatchSize: importBatchSizes[1]}
+	if r := m.importResume; r != nil && r.Table == tableName {
+		m.importInput.SetValue(r.File)
+		m.importInput.CursorEnd()
+		m.importOpts.ResumeRow = r.Row
+	}
+	m.popupStack.Push("import", func(m *Model) {
 		m.showImportPopup = false
 		m.importInput.Blur()
 		m.importTable = ""
-		return true
 	})
 }
 
@@ -361,9 +599,8 @@ func (m *Model) openActionPopup() {
 	}
 	m.showActionPopup = true
 	m.autocompleting = false
-	m.popupStack.Push("action", func(m *Model) bool {
+	m.popupStack.Push("action", func(m *Model) {
 		m.showActionPopup = false
-		return true
 	})
 }
 
@@ -372,14 +609,26 @@ func (m *Model) openThemeSelector() {
 	if m.themeSelector.Visible() {
 		return
 	}
+	if m.themeSelector.config == nil {
+		m.themeSelector = NewThemeSelector(m.config)
+	}
 	m.themeSelector = m.themeSelector.Show()
 	m.autocompleting = false
-	m.popupStack.Push("theme", func(m *Model) bool {
+	m.popupStack.Push("theme", func(m *Model) {
 		m.themeSelector = m.themeSelector.Hide()
-		return true
 	})
 }
 
+// themeController opens the theme selector outside insert mode, the schema
+// browser and the popups that take letters
+func (m Model) themeController(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if m.mode != InsertMode && !m.schemaFocused() && !m.themeSelector.Visible() && !m.showKeybindPopup && matchKey(msg, m.config.Keys.ToggleTheme) {
+		m.openThemeSelector()
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
 // closeTopPopup closes the topmost popup via the stack.
 func (m *Model) closeTopPopup() bool {
 	if m.popupStack == nil {
@@ -388,6 +637,17 @@ func (m *Model) closeTopPopup() bool {
 	return m.popupStack.CloseTop(m)
 }
 
+// closePopupsThrough closes the popup named name and every popup above it, if it is open
+func (m *Model) closePopupsThrough(name string) {
+	if m.popupStack == nil || !m.popupStack.Contains(name) {
+		return
+	}
+	for m.popupStack.TopName() != name {
+		m.closeTopPopup()
+	}
+	m.closeTopPopup()
+}
+
 // hasOpenPopup reports whether any popup is currently open.
 func (m *Model) hasOpenPopup() bool {
 	if m.popupStack == nil {
@@ -405,67 +665,73 @@ func (m *Model) updatePopupTable() {
 	if availableHeight < 3 {
 		availableHeight = 3
 	}
-	popupWidth := m.width - 10
-	if popupWidth < 60 {
-		popupWidth = 60
-	}
-	maxTableWidth := popupWidth - 10
-
 	m.popupTable = m.popupTable.
 		WithPageSize(availableHeight).
-		WithMaxTotalWidth(maxTableWidth).
-		WithHorizontalFreezeColumnCount(1)
+		WithMaxTotalWidth(m.popupTableWidth()).
+		WithHorizontalFreezeColumnCount(m.popupFrozen)
 }
 
-// selectRowAsQuery takes the highlighted row in the popup table,
-// attempts to find its primary key, and constructs a SELECT query to fetch that specific row.
-func (m Model) selectRowAsQuery() (Model, tea.Cmd) {
-	if m.popupTable.HighlightedRow().Data == nil {
-		return m, nil
-	}
+// popupTableWidth is the widest the results popup table is drawn
+func (m Model) popupTableWidth() int {
+	return max(m.width-10, 60) - 10
+}
 
-	query := m.popupEntry.Query
-	re := regexp.MustCompile(`(?i)from\s+["'\[]?([a-zA-Z0-9._]+)["'\]]?`)
-	matches := re.FindStringSubmatch(query)
-	if len(matches) < 2 {
-		m.errorMsg = "Could not determine table name from query"
-		return m, nil
-	}
-	tableName := matches[1]
+// queryTableRe matches the first table a query reads FROM
+var queryTableRe = regexp.MustCompile(`(?i)from\s+["'\[]?([a-zA-Z0-9._]+)["'\]]?`)
 
-	var cols []db.Column
-	var ok bool
+// queryTable returns the first table query reads from, or ""
+func queryTable(query string) string {
+	if matches := queryTableRe.FindStringSubmatch(query); len(matches) == 2 {
+		return matches[1]
+	}
+	return ""
+}
 
-	if cols, ok = m.columns[tableName]; !ok {
-		for realName, c := range m.columns {
-			if strings.EqualFold(realName, tableName) {
-				tableName = realName
-				cols = c
-				ok = true
-				break
-			}
+// tableColumns looks up the loaded columns of tableName, matching it without
+// regard to case or schema, and returns the name it is loaded under
+func (m Model) tableColumns(tableName string) (string, []db.Column, bool) {
+	if cols, ok := m.columns[tableName]; ok {
+		return tableName, cols, true
+	}
+	for realName, cols := range m.columns {
+		if strings.EqualFold(realName, tableName) {
+			return realName, cols, true
 		}
-		if !ok {
-			suffix := "." + strings.ToLower(tableName)
-			for realName, c := range m.columns {
-				if strings.HasSuffix(strings.ToLower(realName), suffix) {
-					tableName = realName
-					cols = c
-					ok = true
-					break
-				}
-			}
+	}
+	suffix := "." + strings.ToLower(tableName)
+	for realName, cols := range m.columns {
+		if strings.HasSuffix(strings.ToLower(realName), suffix) {
+			return realName, cols, true
 		}
 	}
+	return tableName, nil, false
+}
+
+// resultTable finds the table the results popup query reads from and its
+// column metadata from the loaded schema
+func (m Model) resultTable() (string, []db.Column, error) {
+	tableName := queryTable(m.popupEntry.Query)
+	if tableName == "" {
+		return "", nil, fmt.Errorf("Could not determine table name from query")
+	}
 
+	tableName, cols, ok := m.tableColumns(tableName)
 	if !ok {
-		f, _ := os.OpenFile("debug_metadata.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-		if f != nil {
-			fmt.Fprintf(f, "Timestamp: %s\nTable: %s\nLoaded Tables Count: %d\nAll tables: %v\n\n",
-				time.Now(), tableName, len(m.tables), m.tables)
-			f.Close()
-		}
-		m.errorMsg = fmt.Sprintf("Metadata missing for %s (Tabs: %d). See debug_metadata.log", tableName, len(m.tables))
+		return tableName, nil, fmt.Errorf("No column metadata for %s: it is not among the %d tables loaded from the schema", tableName, len(m.tables))
+	}
+	return tableName, cols, nil
+}
+
+// selectRowAsQuery takes the highlighted row in the popup table,
+// attempts to find its primary key, and constructs a SELECT query to fetch that specific row.
+func (m Model) selectRowAsQuery() (Model, tea.Cmd) {
+	if m.popupTable.HighlightedRow().Data == nil {
+		return m, nil
+	}
+
+	tableName, cols, err := m.resultTable()
+	if err != nil {
+		m.errorMsg = err.Error()
 		return m, nil
 	}
 
@@ -510,33 +776,8 @@ func (m Model) selectRowAsQuery() (Model, tea.Cmd) {
 	}
 
 	newQuery := fmt.Sprintf("SELECT * FROM %s WHERE %s;", tableName, strings.Join(whereParts, " AND "))
-	m.editor.SetValue(newQuery)
-	m.showPopup = false
-	m.showRowActionPopup = false
-	m.showActionPopup = false
-	m.mode = InsertMode
-	return m, nil
-}
-
-// viewFullRow displays all columns and values for the highlighted row.
-func (m Model) viewFullRow() (Model, tea.Cmd) {
-	highlightedRow := m.popupTable.HighlightedRow()
-	if highlightedRow.Data == nil || m.popupResult == nil {
-		return m, nil
-	}
-
-	var content strings.Builder
-	content.WriteString("-- Row Details --\n")
-	for _, col := range m.popupResult.Columns {
-		if val, ok := highlightedRow.Data[col]; ok {
-			val = unwrapCellValue(val)
-			content.WriteString(fmt.Sprintf("%s: %v\n", col, val))
-		}
-	}
-
-	m.editor.SetValue(content.String())
-	m.showPopup = false
-	m.showRowActionPopup = false
+	m.setEditorValue(newQuery)
+	m.closePopupsThrough("results")
 	m.mode = InsertMode
 	return m, nil
 }
@@ -4,6 +4,7 @@ package ui
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
@@ -31,20 +32,7 @@ func (m Model) handleProfileSelected(msg profileselector.SelectedMsg) (Model, te
 
 // handleProfileSaved processes a saved profile (new or updated).
 func (m Model) handleProfileSaved(msg profileselector.ProfileSavedMsg) (Model, tea.Cmd) {
-	p := config.Profile{
-		Name:        msg.Profile.Name,
-		Type:        msg.Profile.Type,
-		Host:        msg.Profile.Host,
-		Port:        msg.Profile.Port,
-		User:        msg.Profile.User,
-		Database:    msg.Profile.Database,
-		Password:    msg.Profile.Password,
-		SSHHost:     msg.Profile.SSHHost,
-		SSHPort:     msg.Profile.SSHPort,
-		SSHUser:     msg.Profile.SSHUser,
-		SSHKeyPath:  msg.Profile.SSHKeyPath,
-		SSHPassword: msg.Profile.SSHPassword,
-	}
+	p := m.formProfile(msg.Profile)
 
 	if msg.IsNew {
 		if err := m.config.AddProfile(p); err != nil {
@@ -53,6 +41,11 @@ func (m Model) handleProfileSaved(msg profileselector.ProfileSavedMsg) (Model, t
 			m.statusMsg = fmt.Sprintf("%s Added profile: %s", icons.IconSuccess, p.Name)
 			m.reloadProfiles()
 			m.profileSelector = m.profileSelector.ResetState()
+			if msg.Connect {
+				var cmd tea.Cmd
+				m.profileSelector, cmd = m.profileSelector.Select(len(m.config.Profiles) - 1).Connect()
+				return m, cmd
+			}
 		}
 	} else {
 		if err := m.config.UpdateProfile(msg.Profile.Name, p); err != nil {
@@ -75,17 +68,7 @@ func (m Model) handleProfileManagement(msg profileselector.ManagementMsg) (Model
 				m.profileSelector = m.profileSelector.SetStatusMessage(fmt.Sprintf("Error deleting profile: %v", err))
 			} else {
 				m.profileSelector = m.profileSelector.SetStatusMessage(fmt.Sprintf("%s Deleted profile: %s", icons.IconSuccess, msg.Profile.Name))
-				profiles := make([]profileselector.Profile, len(m.config.Profiles))
-				for i, p := range m.config.Profiles {
-					profiles[i] = profileselector.Profile{
-						Name:     p.Name,
-						Type:     p.Type,
-						Host:     p.Host,
-						Database: p.Database,
-						Password: p.Password,
-					}
-				}
-				m.profileSelector = m.profileSelector.SetProfiles(profiles)
+				m.profileSelector = m.profileSelector.SetProfiles(selectorProfiles(m.config.Profiles))
 			}
 		}
 	}
@@ -97,33 +80,144 @@ func (m Model) handleProfileConnected(msg ProfileConnectedMsg) (Model, tea.Cmd)
 	if msg.Err != nil {
 		m.connectError = msg.Err.Error()
 		m.appState = StateSelectingProfile
+		m.restoring = nil
 		return m, nil
 	}
 	m.driver = msg.Driver
+	m.inTransaction = false
+	m.dockEntry, m.dockResult = nil, nil
+	m.functions = nil
+	m.sessionLocation = nil
+	m.schemas, m.currentSchema = nil, ""
 	m.appState = StateReady
 	m.connectError = ""
+	if m.profile != nil {
+		if m.config.Protected(m.profile) {
+			m.strictMode = true
+		}
+		m.profile.LastUsed = time.Now()
+		m.config.Save()
+		m.reloadProfiles()
+	}
+	m = m.applyRestore()
 	m.loadingTables = true
 	return m, tea.Batch(
 		tea.ClearScreen,
 		textarea.Blink,
 		m.loadHistoryCmd(),
+		m.loadEditsCmd(),
+		m.loadHistoryIndexCmd(),
+		m.sessionTimezoneCmd(),
 		schemabrowser.LoadSchemaCmd(m.driver),
 	)
 }
 
 // reloadProfiles updates the profile selector with the current config profiles.
 func (m *Model) reloadProfiles() {
-	profiles := make([]profileselector.Profile, len(m.config.Profiles))
-	for i, cp := range m.config.Profiles {
-		profiles[i] = profileselector.Profile{
-			Name:     cp.Name,
-			Type:     cp.Type,
-			Host:     cp.Host,
-			Port:     cp.Port,
-			User:     cp.User,
-			Database: cp.Database,
-			Password: cp.Password,
+	m.profileSelector = m.profileSelector.SetProfiles(selectorProfiles(m.config.Profiles))
+}
+
+// toSelectorProfile maps a config profile to the fields the profile
+// selector lists and edits
+func toSelectorProfile(p config.Profile) profileselector.Profile {
+	return profileselector.Profile{
+		Name:        p.Name,
+		Type:        p.Type,
+		Host:        p.Host,
+		Port:        p.Port,
+		User:        p.User,
+		Database:    p.Database,
+		Password:    p.Password,
+		SSHHost:     p.SSHHost,
+		SSHPort:     p.SSHPort,
+		SSHUser:     p.SSHUser,
+		SSHKeyPath:  p.SSHKeyPath,
+		SSHPassword: p.SSHPassword,
+
+		CredentialsFile: p.CredentialsFile,
+		Color:           p.Color,
+		SessionInit:     p.SessionInit,
+		Group:           p.Group,
+		Label:           p.Label,
+		LastUsed:        p.LastUsed,
+		Params:          p.Params,
+
+		ExternalPassword: p.PasswordCmd != "" || p.VaultPath != "",
+	}
+}
+
+// selectorProfiles maps every config profile for the profile selector
+func selectorProfiles(profiles []config.Profile) []profileselector.Profile {
+	out := make([]profileselector.Profile, len(profiles))
+	for i, p := range profiles {
+		out[i] = toSelectorProfile(p)
+	}
+	return out
+}
+
+// mergeSelectorProfile applies the fields edited in the profile form to
+// base, keeping every setting the form does not edit
+func mergeSelectorProfile(base config.Profile, sp profileselector.Profile) config.Profile {
+	p := base
+	// Save encrypts the plain passwords again; drop the stale ciphertext of
+	// any the form changed so a cleared password stays cleared
+	if sp.Password != base.Password {
+		p.EncryptedPassword = ""
+	}
+	if sp.SSHPassword != base.SSHPassword {
+		p.EncryptedSSHPassword = ""
+	}
+	p.Name, p.Type = sp.Name, sp.Type
+	p.Host, p.Port, p.User, p.Database, p.Password = sp.Host, sp.Port, sp.User, sp.Database, sp.Password
+	p.SSHHost, p.SSHPort, p.SSHUser, p.SSHKeyPath, p.SSHPassword = sp.SSHHost, sp.SSHPort, sp.SSHUser, sp.SSHKeyPath, sp.SSHPassword
+	p.CredentialsFile, p.Color, p.SessionInit = sp.CredentialsFile, sp.Color, sp.SessionInit
+	p.Group, p.Label, p.Params = sp.Group, sp.Label, sp.Params
+	return p
+}
+
+// formProfile returns the profile in the form merged into the saved profile
+// of the same name, or on its own for a new one
+func (m Model) formProfile(sp profileselector.Profile) config.Profile {
+	var base config.Profile
+	if old, err := m.config.GetProfile(sp.Name); err == nil {
+		base = *old
+	}
+	return mergeSelectorProfile(base, sp)
+}
+
+// handleTestConnection tries a connection with the profile in the form, without saving it
+func (m Model) handleTestConnection(msg profileselector.TestConnectionMsg) (Model, tea.Cmd) {
+	return m, testConnectionCmd(m.formProfile(msg.Profile))
+}
+
+// handleConnectionTested reports a connection test in the profile form
+func (m Model) handleConnectionTested(msg ConnectionTestedMsg) (Model, tea.Cmd) {
+	status := fmt.Sprintf("%s Connected to %s", icons.IconSuccess, msg.Name)
+	if msg.Err != nil {
+		status = fmt.Sprintf("Error connecting to %s: %v", msg.Name, msg.Err)
+	}
+	m.profileSelector = m.profileSelector.SetStatusMessage(status).Tested(msg.Err)
+	return m, nil
+}
+
+// profileController owns every key while the profile selector is shown
+func (m Model) profileController(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if m.appState != StateSelectingProfile {
+		return m, nil, false
+	}
+	if matchKey(msg, m.config.Keys.Help) {
+		m.openHelpPopup()
+		return m, nil, true
+	}
+	if m.sessionOffer != nil {
+		// Any other key dismisses the offer and reaches the selector
+		var cmd tea.Cmd
+		var handled bool
+		if m, cmd, handled = m.handleSessionOffer(msg); handled {
+			return m, cmd, true
 		}
 	}
-	m.profileSelector = m.profileSelector.SetProfiles(profiles)
+	var cmd tea.Cmd
+	m.profileSelector, cmd = m.profileSelector.Update(msg)
+	return m, cmd, true
 }
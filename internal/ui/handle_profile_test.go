@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/nhath/ezdb/internal/config"
+)
+
+func TestMergeSelectorProfileKeepsUneditedFields(t *testing.T) {
+	old := config.Profile{
+		Name: "prod", Type: "postgres", Host: "db", Password: "secret",
+		EncryptedPassword: "enc", EncryptedSSHPassword: "ssh-enc",
+		ReadOnly: true, DisableAssistant: true, PasswordCmd: "pass prod",
+		ApplicationName: "ezdb-prod", SSHHost: "bastion", SSHPassword: "tunnel",
+		Params: "sslmode=require",
+	}
+	sp := toSelectorProfile(old)
+	sp.Host = "db2"
+	sp.Password = ""
+
+	p := mergeSelectorProfile(old, sp)
+	if p.Host != "db2" {
+		t.Errorf("Host = %q, want the edited db2", p.Host)
+	}
+	if !p.ReadOnly || !p.DisableAssistant || p.PasswordCmd != "pass prod" || p.ApplicationName != "ezdb-prod" {
+		t.Errorf("settings the form does not edit were dropped: %+v", p)
+	}
+	if p.SSHHost != "bastion" || p.Params != "sslmode=require" {
+		t.Errorf("SSH or params lost in the round trip: %+v", p)
+	}
+	if p.Password != "" || p.EncryptedPassword != "" {
+		t.Errorf("cleared password kept: %q / %q", p.Password, p.EncryptedPassword)
+	}
+	if p.EncryptedSSHPassword != "ssh-enc" {
+		t.Errorf("unchanged SSH password ciphertext = %q, want ssh-enc", p.EncryptedSSHPassword)
+	}
+}
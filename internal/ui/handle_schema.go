@@ -0,0 +1,94 @@
+// internal/ui/handle_schema.go
+// Schema browser: its keys while focused, the loaded schema, and the table actions it asks for.
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/ui/components/schemabrowser"
+)
+
+// schemaController gives the schema browser every key while it has focus
+func (m Model) schemaController(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if !m.schemaFocused() {
+		return m, nil, false
+	}
+	var cmd tea.Cmd
+	m.schemaBrowser, cmd = m.schemaBrowser.Update(msg)
+	if m.sidebarDocked() {
+		m = m.updateHistoryViewport()
+	}
+	return m, cmd, true
+}
+
+// handleSchemaLoaded stores a loaded schema for the browser and autocomplete
+func (m Model) handleSchemaLoaded(msg schemabrowser.SchemaLoadedMsg) (Model, tea.Cmd) {
+	if msg.Err == nil {
+		m.schemaBrowser = m.schemaBrowser.SetSchema(msg.Tables, msg.Columns, msg.Constraints)
+		if m.restoring != nil && m.restoring.Table != "" {
+			m.schemaBrowser = m.schemaBrowser.SelectTable(m.restoring.Table)
+			m.restoring.Table = ""
+		}
+		m.tables = msg.Tables
+		m.columns = msg.Columns
+		m.constraints = msg.Constraints
+		m.statusMsg = fmt.Sprintf("Loaded %d tables", len(msg.Tables))
+	} else {
+		m.errorMsg = fmt.Sprintf("Schema load failed: %v", msg.Err)
+	}
+	m.loadingTables = false
+	if m.autocompleting {
+		m = m.updateSuggestions()
+	}
+	// The docked loading spinner may have just given its space back
+	m = m.updateHistoryViewport()
+	if msg.Err == nil {
+		// Refreshed with the schema so newly created functions show up,
+		// along with the schema the status bar names
+		cmd := m.loadFunctionsCmd()
+		if switcher, ok := m.driver.(db.SchemaSwitcher); ok {
+			cmd = tea.Batch(cmd, listSchemasCmd(switcher))
+		}
+		return m, cmd
+	}
+	return m, nil
+}
+
+// handleFunctionsLoaded stores the database's functions for autocomplete
+func (m Model) handleFunctionsLoaded(msg FunctionsLoadedMsg) (Model, tea.Cmd) {
+	// Built-in functions are still suggested, so a failed listing is not reported
+	if msg.Err == nil {
+		m.functions = msg.Functions
+	}
+	return m, nil
+}
+
+// handleSchemaAction opens the popup for an action picked on a table in the browser
+func (m Model) handleSchemaAction(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case schemabrowser.TableSelectedMsg:
+		m.openTemplatePopup(msg.TableName)
+	case schemabrowser.ExportTableMsg:
+		return m, m.openTableExportPopup(msg.TableName)
+	case schemabrowser.ImportTableMsg:
+		m.openImportPopup(msg.TableName)
+	case schemabrowser.GenerateDataMsg:
+		m.openGeneratePopup(msg.TableName)
+	case schemabrowser.TruncateTableMsg:
+		m.openTableActionPopup("TRUNCATE", msg.TableName)
+	case schemabrowser.DropTableMsg:
+		m.openTableActionPopup("DROP", msg.TableName)
+	case schemabrowser.CopyTableMsg:
+		if len(m.config.Profiles) > 0 {
+			m.openCopyTablePopup(msg.TableName)
+		}
+	case schemabrowser.BrowseTableMsg:
+		return m.openBrowsePopup(msg.TableName)
+	case schemabrowser.RunFileMsg:
+		return m, m.openFilePopup("/run ")
+	}
+	return m, nil
+}
@@ -3,14 +3,11 @@
 package ui
 
 import (
-	"strings"
-
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/evertras/bubble-table/table"
 
 	"github.com/nhath/ezdb/internal/ui/components/schemabrowser"
-	eztable "github.com/nhath/ezdb/internal/ui/components/table"
 )
 
 // handleVisualMode handles keys in visual mode.
@@ -53,33 +50,30 @@ func (m Model) handleVisualMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.expandedTable = table.Model{}
 			} else {
 				m.expandedID = entry.ID
-				if strings.Contains(entry.Preview, " | ") {
-					m.expandedTable = eztable.FromPreview(entry.Preview).
-						WithMaxTotalWidth(m.width - 14).
-						WithHorizontalFreezeColumnCount(1)
-				}
+				m.expandPreview(entry.Preview)
+				m = m.ensureSelectionVisible()
+				m = m.updateHistoryViewport()
+				return m, m.loadStoredResultCmd(entry)
 			}
 			m = m.ensureSelectionVisible()
 		}
 	} else if matchKey(msg, m.config.Keys.Rerun) {
 		if m.selected >= 0 && m.selected < len(m.history) {
 			entry := m.history[m.selected]
-			if m.strictMode && isModifyingQuery(entry.Query) {
-				m.confirming = true
-				m.pendingQuery = entry.Query
-				return m, nil
-			}
-			m.loading = true
-			return m, m.executeQueryCmd(entry.Query)
+			return m, m.confirmOrRun(entry.Query)
 		}
 	} else if matchKey(msg, m.config.Keys.ToggleStrict) {
+		if m.strictMode && m.profile != nil && m.config.Protected(m.profile) {
+			m.errorMsg = "Strict mode stays on for production profiles"
+			return m, nil
+		}
 		m.strictMode = !m.strictMode
 		m.errorMsg = ""
 		return m, nil
 	} else if matchKey(msg, m.config.Keys.Edit) {
 		if m.selected >= 0 && m.selected < len(m.history) {
 			entry := m.history[m.selected]
-			m.editor.SetValue(entry.Query)
+			m.setEditorValue(entry.Query)
 			m.mode = InsertMode
 			m.editor.Focus()
 			return m, textinput.Blink
@@ -102,6 +96,7 @@ func (m Model) handleVisualMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	} else if matchKey(msg, m.config.Keys.Filter) {
 		m.searching = true
 		m.searchQuery = ""
+		m.ensureInput(lazySearch, &m.searchInput, newSearchInput)
 		m.searchInput.SetValue("")
 		m.searchInput.Focus()
 		return m, textinput.Blink
@@ -110,6 +105,7 @@ func (m Model) handleVisualMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.schemaBrowser.IsVisible() && m.driver != nil {
 			sb, sbCmd := m.schemaBrowser.StartLoading()
 			m.schemaBrowser = sb
+			m = m.updateHistoryViewport()
 			return m, tea.Batch(schemabrowser.LoadSchemaCmd(m.driver), sbCmd)
 		}
 		return m, nil
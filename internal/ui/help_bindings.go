@@ -0,0 +1,107 @@
+// internal/ui/help_bindings.go
+// Builds help popup content from the action registry and the live KeyMap so it always reflects user overrides.
+package ui
+
+import (
+	"strings"
+
+	"github.com/nhath/ezdb/internal/config"
+)
+
+// helpGroupOrder is the order sections appear in the help popup
+var helpGroupOrder = []string{"Navigation", "Query", "Edit", "Actions", "Panels", "General"}
+
+// helpBinding is a single help row: a registered action and its keys
+type helpBinding struct {
+	action
+	Keys []string
+}
+
+// tag returns the ctx tag value used in config.KeyMap for this context
+func (c HelpContext) tag() string {
+	switch c {
+	case HelpContextInsert:
+		return "insert"
+	case HelpContextPopup:
+		return "popup"
+	case HelpContextSchema:
+		return "schema"
+	default:
+		return "visual"
+	}
+}
+
+// Title returns the display name of the context
+func (c HelpContext) Title() string {
+	switch c {
+	case HelpContextInsert:
+		return "Insert Mode"
+	case HelpContextPopup:
+		return "Results View"
+	case HelpContextSchema:
+		return "Schema Browser"
+	default:
+		return "Visual Mode"
+	}
+}
+
+// allHelpBindings lists the registered actions that apply to some context
+// and have a key bound, in registry order
+func allHelpBindings(keys config.KeyMap) []helpBinding {
+	var bindings []helpBinding
+	for _, a := range actionRegistry {
+		bound := a.keys(&keys)
+		if len(a.Ctx) == 0 || len(bound) == 0 {
+			continue
+		}
+		bindings = append(bindings, helpBinding{a, bound})
+	}
+	return bindings
+}
+
+// matches reports whether the binding matches a case-insensitive search query
+func (b helpBinding) matches(query string) bool {
+	query = strings.ToLower(query)
+	if strings.Contains(strings.ToLower(b.Desc), query) || strings.Contains(strings.ToLower(b.Group), query) {
+		return true
+	}
+	for _, k := range b.Keys {
+		if strings.Contains(strings.ToLower(k), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// helpSections groups bindings by section in helpGroupOrder.
+// With an empty filter only bindings for ctx are returned; otherwise all contexts are searched.
+func helpSections(keys config.KeyMap, ctx HelpContext, filter string) ([]string, map[string][]helpBinding) {
+	filter = strings.TrimSpace(filter)
+	sections := make(map[string][]helpBinding)
+	for _, b := range allHelpBindings(keys) {
+		if filter == "" && !b.appliesTo(ctx) {
+			continue
+		}
+		if filter != "" && !b.matches(filter) {
+			continue
+		}
+		sections[b.Group] = append(sections[b.Group], b)
+	}
+
+	var order []string
+	seen := make(map[string]bool)
+	for _, g := range helpGroupOrder {
+		if len(sections[g]) > 0 {
+			order = append(order, g)
+			seen[g] = true
+		}
+	}
+	// Groups not in helpGroupOrder go last
+	for _, b := range allHelpBindings(keys) {
+		if len(sections[b.Group]) > 0 && !seen[b.Group] {
+			order = append(order, b.Group)
+			seen[b.Group] = true
+		}
+	}
+	return order, sections
+}
@@ -0,0 +1,80 @@
+// internal/ui/hint_bar.go
+// Hint lines that fit their width: labels shorten to one word, then the least important hints drop out.
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// hint is one key of a hint line
+type hint struct {
+	Key      string
+	Label    string
+	Priority int // Hints with higher values drop out first; ties drop from the right
+}
+
+// fitHints renders hints joined by sep within width. A line too wide first
+// shortens every label to its first word, then drops hints by priority,
+// and as a last resort is cut off.
+func fitHints(hints []hint, width int, sep string, format func(hint) string) string {
+	render := func(hints []hint) string {
+		parts := make([]string, len(hints))
+		for i, h := range hints {
+			parts[i] = format(h)
+		}
+		return strings.Join(parts, sep)
+	}
+	line := render(hints)
+	if width <= 0 || lipgloss.Width(line) <= width {
+		return line
+	}
+
+	short := make([]hint, len(hints))
+	for i, h := range hints {
+		h.Label, _, _ = strings.Cut(h.Label, " ")
+		short[i] = h
+	}
+	for line = render(short); lipgloss.Width(line) > width && len(short) > 1; line = render(short) {
+		drop := len(short) - 1
+		for i := drop - 1; i >= 0; i-- {
+			if short[i].Priority > short[drop].Priority {
+				drop = i
+			}
+		}
+		short = append(short[:drop:drop], short[drop+1:]...)
+	}
+	return ansi.Truncate(line, width, "…")
+}
+
+// parseHints reads a hint line written as "Key: label" pairs joined by " • ".
+// The first and last hints, the main action and the way out, are kept
+// longest; the ones between drop out from the right.
+func parseHints(line string) []hint {
+	parts := strings.Split(line, " • ")
+	hints := make([]hint, len(parts))
+	for i, p := range parts {
+		key, label, _ := strings.Cut(p, ": ")
+		hints[i] = hint{Key: key, Label: label, Priority: i}
+	}
+	hints[len(hints)-1].Priority = 0
+	return hints
+}
+
+// renderHintLine renders a popup's "Key: label • …" hint line faintly within width
+func (m Model) renderHintLine(line string, width int) string {
+	fitted := fitHints(parseHints(line), width, " • ", func(h hint) string {
+		if h.Label == "" {
+			return h.Key
+		}
+		return h.Key + ": " + h.Label
+	})
+	return lipgloss.NewStyle().Faint(true).Render(fitted)
+}
+
+// popupInnerWidth is the text width inside a popup of the given width
+func (m Model) popupInnerWidth(popupWidth int) int {
+	return popupWidth - m.theme.PopupStyle.GetHorizontalPadding()
+}
@@ -0,0 +1,45 @@
+// internal/ui/hint_bar_test.go
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestFitHints(t *testing.T) {
+	hints := parseHints("Enter: diff with live schema • n: new snapshot • d: delete • Esc: close")
+	format := func(h hint) string { return h.Key + ": " + h.Label }
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{80, "Enter: diff with live schema • n: new snapshot • d: delete • Esc: close"},
+		{50, "Enter: diff • n: new • d: delete • Esc: close"},
+		{36, "Enter: diff • n: new • Esc: close"},
+		{20, "Enter: diff"},
+		{8, "Enter: …"},
+	}
+	for _, tt := range tests {
+		got := fitHints(hints, tt.width, " • ", format)
+		if got != tt.want {
+			t.Errorf("width %d: got %q, want %q", tt.width, got, tt.want)
+		}
+		if lipgloss.Width(got) > tt.width {
+			t.Errorf("width %d: %q is %d wide", tt.width, got, lipgloss.Width(got))
+		}
+	}
+}
+
+func TestBottomHintsFitNarrowTerminal(t *testing.T) {
+	t.Parallel()
+	s, _ := scriptModel(t)
+	s.Send(tea.WindowSizeMsg{Width: 50, Height: 30})
+	for _, line := range strings.Split(s.Screen(), "\n") {
+		if w := lipgloss.Width(line); w > 50 {
+			t.Errorf("line %d wide at width 50: %q", w, line)
+		}
+	}
+}
@@ -16,11 +16,11 @@ const (
 	IconInfo    = "" // nf-fa-info
 
 	// Navigation Icons
-	IconSelect    = "▶" // nf-fa-chevron_right
-	IconExpanded  = "▼" // nf-fa-chevron_down
-	IconCollapsed = "▶" // nf-fa-chevron_right
-	IconArrowUp   = "↑" // nf-cod-arrow_up
-	IconArrowDown = "↓" // nf-cod-arrow_down
+	IconSelect      = "▶" // nf-fa-chevron_right
+	IconExpanded    = "▼" // nf-fa-chevron_down
+	IconCollapsed   = "▶" // nf-fa-chevron_right
+	IconArrowUp     = "↑" // nf-cod-arrow_up
+	IconArrowDown   = "↓" // nf-cod-arrow_down
 	IconPointer     = "❯" // nf-cod-triangle_right
 	IconPointerFill = "►" // nf-fa-hand_o_right
 	IconVertNav     = "󰁼" // nf-md-arrow_up_down
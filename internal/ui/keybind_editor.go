@@ -0,0 +1,194 @@
+// internal/ui/keybind_editor.go
+// Keybindings editor: rebind the registry's KeyMap actions from the TUI and save them to config.
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/config"
+	eztable "github.com/nhath/ezdb/internal/ui/components/table"
+)
+
+// keybindCaptureMode is what the next key press does in the editor
+type keybindCaptureMode int
+
+const (
+	keybindCaptureOff keybindCaptureMode = iota
+	keybindCaptureReplace
+	keybindCaptureAdd
+)
+
+// keybindActions lists the rebindable actions of the registry, in KeyMap order
+func keybindActions() []action {
+	var list []action
+	for _, a := range actionRegistry {
+		if a.rebindable() {
+			list = append(list, a)
+		}
+	}
+	return list
+}
+
+// keybindConflict returns the description of another action that already uses
+// key in a context shared with a, or "" when the key is free
+func keybindConflict(keys *config.KeyMap, a action, key string) string {
+	for _, other := range actionRegistry {
+		if other.Name == a.Name || !a.sharesContext(other) {
+			continue
+		}
+		for _, k := range other.keys(keys) {
+			if k == key {
+				return other.Desc
+			}
+		}
+	}
+	return ""
+}
+
+// openKeybindPopup replaces the help popup with the keybindings editor.
+func (m *Model) openKeybindPopup() {
+	if m.showKeybindPopup {
+		return
+	}
+	if m.popupStack.TopName() == "help" {
+		m.closeTopPopup()
+	}
+	m.showKeybindPopup = true
+	m.autocompleting = false
+	m.keybindIdx = 0
+	m.keybindCapture = keybindCaptureOff
+	m.keybindMsg = ""
+	m.popupStack.Push("keybind", func(m *Model) {
+		m.showKeybindPopup = false
+		m.keybindCapture = keybindCaptureOff
+	})
+}
+
+// handleKeybindKeys handles keys while the keybindings editor is open
+func (m Model) handleKeybindKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	actions := keybindActions()
+	selected := actions[m.keybindIdx]
+
+	if m.keybindCapture != keybindCaptureOff {
+		key := msg.String()
+		mode := m.keybindCapture
+		m.keybindCapture = keybindCaptureOff
+		if msg.Type == tea.KeyEsc {
+			m.keybindMsg = ""
+			return m, nil, true
+		}
+		if other := keybindConflict(&m.config.Keys, selected, key); other != "" {
+			m.keybindMsg = fmt.Sprintf("%s is already bound to %q", key, other)
+			return m, nil, true
+		}
+		bound := []string{key}
+		if mode == keybindCaptureAdd {
+			current := selected.keys(&m.config.Keys)
+			for _, k := range current {
+				if k == key {
+					return m, nil, true
+				}
+			}
+			bound = append(append([]string{}, current...), key)
+		}
+		return m.saveKeybind(selected, bound), nil, true
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.closeTopPopup()
+	case "up", "k":
+		if m.keybindIdx > 0 {
+			m.keybindIdx--
+		}
+	case "down", "j":
+		if m.keybindIdx < len(actions)-1 {
+			m.keybindIdx++
+		}
+	case "enter":
+		m.keybindCapture = keybindCaptureReplace
+		m.keybindMsg = ""
+	case "a":
+		m.keybindCapture = keybindCaptureAdd
+		m.keybindMsg = ""
+	case "d":
+		defaults := config.DefaultConfig().Keys
+		return m.saveKeybind(selected, selected.keys(&defaults)), nil, true
+	}
+	return m, nil, true
+}
+
+// saveKeybind binds keys to a, persists the config and refreshes key-aware components
+func (m Model) saveKeybind(a action, bound []string) Model {
+	setKeys(&m.config.Keys, a.Name, bound)
+	m.tableBuilder = eztable.NewBuilder(m.config.Theme, m.config.Keys)
+	m.schemaBrowser = m.schemaBrowser.SetTableBuilder(m.tableBuilder)
+	if err := m.config.Save(); err != nil {
+		m.keybindMsg = "Failed to save config: " + err.Error()
+		return m
+	}
+	m.keybindMsg = fmt.Sprintf("%s → %s", a.Desc, strings.Join(bound, "/"))
+	return m
+}
+
+func (m Model) renderKeybindPopup(main string) string {
+	var content strings.Builder
+	popupWidth := 64
+	if popupWidth > m.width-10 {
+		popupWidth = m.width - 10
+	}
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Keybindings")
+	content.WriteString(title)
+	content.WriteString("\n\n")
+
+	actions := keybindActions()
+	// Keep the selection in view when the list is taller than the popup
+	visible := max(m.height-14, 5)
+	start := 0
+	if m.keybindIdx >= visible {
+		start = m.keybindIdx - visible + 1
+	}
+	end := min(start+visible, len(actions))
+
+	keyStyle := lipgloss.NewStyle().Foreground(m.theme.TextPrimary()).Background(m.theme.CardBg()).Padding(0, 1).Bold(true)
+	for i := start; i < end; i++ {
+		a := actions[i]
+		style := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
+		prefix := "  "
+		if i == m.keybindIdx {
+			style = lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Bold(true)
+			prefix = "> "
+		}
+		keys := strings.Join(a.keys(&m.config.Keys), "/")
+		if i == m.keybindIdx && m.keybindCapture != keybindCaptureOff {
+			keys = "press a key…"
+		}
+		if keys == "" {
+			keys = "unbound"
+		}
+		content.WriteString(prefix + style.Render(fmt.Sprintf("%-28s", a.Desc)) + " " + keyStyle.Render(keys) + "\n")
+	}
+
+	if m.keybindMsg != "" {
+		content.WriteString("\n")
+		content.WriteString(lipgloss.NewStyle().Foreground(m.theme.WarningColor()).Render(m.keybindMsg))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(m.renderHintLine("Enter: rebind • a: add key • d: default • Esc: close", m.popupInnerWidth(popupWidth)))
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
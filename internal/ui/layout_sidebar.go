@@ -0,0 +1,115 @@
+// internal/ui/layout_sidebar.go
+// Docked schema sidebar: schema on the left, history/editor on the right.
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	defaultSidebarRatio = 0.3
+	minSidebarRatio     = 0.15
+	maxSidebarRatio     = 0.6
+	sidebarRatioStep    = 0.05
+
+	// sidebarSaveDelay batches repeated resize keys into one config write
+	sidebarSaveDelay = 500 * time.Millisecond
+)
+
+// sidebarDocked reports whether the schema browser uses the docked layout
+func (m Model) sidebarDocked() bool {
+	return m.config.SchemaLayout == "sidebar"
+}
+
+// sidebarShown reports whether the docked sidebar currently takes screen space.
+// It is also shown while the schema loads so the spinner stays visible.
+func (m Model) sidebarShown() bool {
+	return m.sidebarDocked() && (m.schemaBrowser.IsVisible() || m.schemaBrowser.IsLoading())
+}
+
+// schemaFocused reports whether the schema browser receives keys.
+// A docked sidebar can stay visible while the main pane has focus.
+func (m Model) schemaFocused() bool {
+	return m.schemaBrowser.IsVisible() && !(m.sidebarDocked() && m.sidebarBlurred)
+}
+
+// sidebarRatio returns the configured split, clamped to a usable range
+func (m Model) sidebarRatio() float64 {
+	ratio := m.config.SidebarRatio
+	if ratio == 0 {
+		ratio = defaultSidebarRatio
+	}
+	return min(max(ratio, minSidebarRatio), maxSidebarRatio)
+}
+
+// sidebarWidth returns the docked sidebar width in cells
+func (m Model) sidebarWidth() int {
+	return max(int(float64(m.width)*m.sidebarRatio()), 20)
+}
+
+// paneWidth returns the width of the history/editor pane
+func (m Model) paneWidth() int {
+	if m.sidebarShown() {
+		return max(m.width-m.sidebarWidth(), 0)
+	}
+	return m.width
+}
+
+// layoutSchemaBrowser sizes the schema browser for the current layout
+func (m Model) layoutSchemaBrowser() Model {
+	if m.sidebarDocked() {
+		m.schemaBrowser = m.schemaBrowser.SetSize(m.sidebarWidth(), m.height).SetDocked(true)
+	} else {
+		m.schemaBrowser = m.schemaBrowser.SetSize(m.width, m.height).SetDocked(false)
+	}
+	return m
+}
+
+// resizeSidebar changes the split by delta and schedules saving it
+func (m Model) resizeSidebar(delta float64) (Model, tea.Cmd) {
+	m.config.SidebarRatio = min(max(m.sidebarRatio()+delta, minSidebarRatio), maxSidebarRatio)
+	m = m.layoutSchemaBrowser().updateHistoryViewport()
+
+	m.sidebarSaveID++
+	id := m.sidebarSaveID
+	return m, tea.Tick(sidebarSaveDelay, func(time.Time) tea.Msg {
+		return SidebarSaveMsg{ID: id}
+	})
+}
+
+// saveSidebarCmd persists the config off the update loop
+func (m Model) saveSidebarCmd() tea.Cmd {
+	cfg := m.config
+	return func() tea.Msg {
+		return SidebarSavedMsg{Err: cfg.Save()}
+	}
+}
+
+// handleSidebarSave saves the split once resizing has settled
+func (m Model) handleSidebarSave(msg SidebarSaveMsg) (Model, tea.Cmd) {
+	if msg.ID == m.sidebarSaveID {
+		return m, m.saveSidebarCmd()
+	}
+	return m, nil
+}
+
+// handleSidebarSaved reports a split that could not be saved
+func (m Model) handleSidebarSaved(msg SidebarSavedMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = "Failed to save sidebar size: " + msg.Err.Error()
+	}
+	return m, nil
+}
+
+// renderDocked renders the sidebar next to the main pane.
+// The history viewport and editor are already sized for the pane by updateHistoryViewport.
+func (m Model) renderDocked() string {
+	side := m.schemaBrowser.View()
+
+	main := m
+	main.width = m.paneWidth()
+	return lipgloss.JoinHorizontal(lipgloss.Top, side, main.renderMain())
+}
@@ -0,0 +1,192 @@
+// internal/ui/lazy_inputs.go
+// Popup inputs are built on first use so startup only pays for the profile selector.
+package ui
+
+import "github.com/charmbracelet/bubbles/textinput"
+
+// lazyInput identifies a lazily built input in Model.builtInputs
+type lazyInput uint16
+
+const (
+	lazyTableFilter lazyInput = 1 << iota
+	lazyHelpFilter
+	lazyExport
+	lazyExportFilter // All of exportFilterInputs
+	lazySearch
+	lazyImport
+	lazyAttach
+	lazyBrowseFilter
+	lazyFile
+	lazySchema
+	lazyGenerate
+	lazyTableAction
+	lazyCopyTable
+	lazySnapshot
+	lazyTemplate // All of templateInputs
+	lazyAssistant
+)
+
+// ensureInput builds an input the first time it is needed
+func (m *Model) ensureInput(id lazyInput, in *textinput.Model, build func() textinput.Model) {
+	if m.builtInputs&id == 0 {
+		*in = build()
+		m.builtInputs |= id
+	}
+}
+
+func newTableFilterInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/ "
+	ti.Placeholder = "Filter table..."
+	ti.CharLimit = 100
+	ti.Width = 30
+	return ti
+}
+
+func newHelpFilterInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/ "
+	ti.Placeholder = "Search shortcuts..."
+	ti.CharLimit = 50
+	ti.Width = 30
+	return ti
+}
+
+func newExportInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Export to: "
+	ti.Placeholder = "export.csv"
+	ti.CharLimit = 256
+	ti.Width = 40
+	return ti
+}
+
+func newExportFilterInputs() [exportFilterFields]textinput.Model {
+	var inputs [exportFilterFields]textinput.Model
+	for i, f := range []struct{ prompt, placeholder string }{
+		{"Where:     ", "status = 'active'"},
+		{"Order by:  ", "created_at DESC"},
+		{"Limit:     ", "all rows"},
+	} {
+		ti := textinput.New()
+		ti.Prompt = f.prompt
+		ti.Placeholder = f.placeholder
+		ti.CharLimit = 512
+		ti.Width = 40
+		inputs[i] = ti
+	}
+	return inputs
+}
+
+func newSearchInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/ "
+	ti.Placeholder = "Search history..."
+	ti.CharLimit = 100
+	ti.Width = 30
+	return ti
+}
+
+func newImportInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Import from: "
+	ti.Placeholder = "path/to/file.csv"
+	ti.CharLimit = 256
+	ti.Width = 40
+	return ti
+}
+
+func newGenerateInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Rows: "
+	ti.Placeholder = "100"
+	ti.CharLimit = 6
+	ti.Width = 20
+	return ti
+}
+
+func newTableActionInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "> "
+	ti.CharLimit = 256
+	ti.Width = 40
+	return ti
+}
+
+func newCopyTableInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Target table: "
+	ti.CharLimit = 256
+	ti.Width = 40
+	return ti
+}
+
+func newSnapshotInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Name: "
+	ti.CharLimit = 100
+	ti.Width = 40
+	return ti
+}
+
+func newAttachInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Attach: "
+	ti.Placeholder = "path/to/other.db [AS name]"
+	ti.CharLimit = 256
+	ti.Width = 50
+	return ti
+}
+
+func newBrowseFilterInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Where: "
+	ti.Placeholder = "status = active, age >= 30, name ~ smith"
+	ti.CharLimit = 256
+	ti.Width = 50
+	return ti
+}
+
+func newFileInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "> "
+	ti.Placeholder = "/open migrations/001_init.sql"
+	ti.CharLimit = 256
+	ti.Width = 50
+	return ti
+}
+
+func newSchemaInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/ "
+	ti.Placeholder = "Filter schemas..."
+	ti.CharLimit = 100
+	ti.Width = 40
+	return ti
+}
+
+func newTemplateInputs() [templateFields]textinput.Model {
+	var inputs [templateFields]textinput.Model
+	for i, f := range []struct{ prompt, placeholder string }{
+		{"Name:  ", "SELECT 10"},
+		{"Query: ", "SELECT * FROM <table> LIMIT 10"},
+		{"", "same as Query"},
+	} {
+		ti := textinput.New()
+		ti.Prompt = f.prompt
+		ti.Placeholder = f.placeholder
+		ti.CharLimit = 2000
+		ti.Width = 56
+		inputs[i] = ti
+	}
+	return inputs
+}
+
+func newAssistantInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Ask: "
+	ti.Placeholder = "orders of the last week with their customer's name"
+	ti.CharLimit = 1000
+	ti.Width = 60
+	return ti
+}
@@ -0,0 +1,175 @@
+// internal/ui/lint.go
+// Pre-execution lint: syntax problems in the editor are underlined and named in the status bar.
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/ui/autocomplete"
+)
+
+// dryRunTimeout bounds a driver dry run of the editor's SQL
+const dryRunTimeout = 3 * time.Second
+
+// lintEditor checks the editor's SQL once typing pauses. When it parses
+// locally, drivers that can prepare a statement without running it check a
+// single statement on the server too.
+func (m Model) lintEditor() (Model, tea.Cmd) {
+	text := m.editor.Value()
+	m.lintErr, m.lintQuery = nil, text
+	if strings.TrimSpace(text) == "" || m.driver == nil {
+		return m, nil
+	}
+	if m.lintErr = autocomplete.Lint(text, m.driver.Type()); m.lintErr != nil {
+		return m, nil
+	}
+
+	validator, ok := m.driver.(db.Validator)
+	if !ok || m.config.DisableDryRun || m.loading {
+		return m, nil
+	}
+	statements := splitStatements(text)
+	if len(statements) != 1 {
+		return m, nil
+	}
+	return m, validateCmd(validator, text, statements[0], strings.Index(text, statements[0]))
+}
+
+// validateCmd dry-runs stmt, found at byte offset base in the editor text
+func validateCmd(validator db.Validator, text, stmt string, base int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), dryRunTimeout)
+		defer cancel()
+		var syntaxErr *db.SyntaxError
+		// Other failures (timeouts, lost connections) are not the query's fault
+		if !errors.As(validator.Validate(ctx, stmt), &syntaxErr) {
+			return LintResultMsg{Query: text}
+		}
+		if syntaxErr.Offset >= 0 && base >= 0 {
+			syntaxErr.Offset += base
+		}
+		return LintResultMsg{Query: text, Err: syntaxErr}
+	}
+}
+
+// handleLintResult records a dry run's verdict unless the editor has changed since
+func (m Model) handleLintResult(msg LintResultMsg) (Model, tea.Cmd) {
+	if msg.Query == m.editor.Value() {
+		m.lintErr, m.lintQuery = msg.Err, msg.Query
+	}
+	return m, nil
+}
+
+// activeLintError returns the lint error unless the text has changed since
+func (m Model) activeLintError() *db.SyntaxError {
+	if m.lintErr == nil || m.lintQuery != m.editor.Value() {
+		return nil
+	}
+	return m.lintErr
+}
+
+// lintMessage words the lint error for the status bar: "line 2: unexpected )"
+func (m Model) lintMessage() string {
+	lintErr := m.activeLintError()
+	if lintErr == nil {
+		return ""
+	}
+	msg := lintErr.Message
+	if lintErr.Offset >= 0 && strings.Contains(m.lintQuery, "\n") {
+		line := strings.Count(m.lintQuery[:min(lintErr.Offset, len(m.lintQuery))], "\n") + 1
+		msg = fmt.Sprintf("line %d: %s", line, msg)
+	}
+	if utf8.RuneCountInString(msg) > 60 {
+		msg = string([]rune(msg)[:57]) + "..."
+	}
+	return msg
+}
+
+// markLintError underlines the text at the lint error in the editor view.
+// Rendering a copy of the editor with its cursor moved there shows which
+// line and cell of the view that is.
+func (m Model) markLintError(view string) string {
+	lintErr := m.activeLintError()
+	if lintErr == nil || lintErr.Offset < 0 {
+		return view
+	}
+	text := m.editor.Value()
+	offset := min(lintErr.Offset, len(text))
+
+	probe := m
+	probe.setEditorCursor(utf8.RuneCountInString(text[:offset]))
+	probe.editor.Cursor.SetMode(cursor.CursorStatic)
+	probeLines := strings.Split(probe.editor.View(), "\n")
+	lines := strings.Split(view, "\n")
+	for i, line := range probeLines {
+		at := strings.Index(line, "\x1b[7")
+		if at < 0 || i >= len(lines) {
+			continue
+		}
+		lines[i] = underlineCells(lines[i], lipgloss.Width(line[:at]), lintTokenLength(text[offset:]), m.theme.ErrorColor())
+		break
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lintTokenLength is how many runes of the text at a lint error to underline:
+// the word there, or a single character
+func lintTokenLength(s string) int {
+	n := 0
+	for _, r := range s {
+		if !(r == '_' || r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= utf8.RuneSelf) {
+			break
+		}
+		n++
+	}
+	return max(n, 1)
+}
+
+// underlineCells underlines n runes starting at visible cell col of a
+// rendered line, in color where the terminal supports it
+func underlineCells(line string, col, n int, color lipgloss.Color) string {
+	on := "\x1b[4m"
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(string(color), "#%02x%02x%02x", &r, &g, &b); err == nil {
+		on += fmt.Sprintf("\x1b[58;2;%d;%d;%dm", r, g, b)
+	}
+	const off = "\x1b[24;59m"
+
+	var sb strings.Builder
+	cells := 0
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			j := i + 1
+			for j < len(line) && !(line[j] >= 'A' && line[j] <= 'Z' || line[j] >= 'a' && line[j] <= 'z') {
+				j++
+			}
+			j = min(j+1, len(line))
+			sb.WriteString(line[i:j])
+			i = j
+			continue
+		}
+		ch, size := utf8.DecodeRuneInString(line[i:])
+		if cells >= col && n > 0 && ch != ' ' {
+			sb.WriteString(on + string(ch) + off)
+			n--
+		} else {
+			if cells >= col {
+				n = 0 // The token ends at a wrap or the end of the text
+			}
+			sb.WriteRune(ch)
+		}
+		cells += lipgloss.Width(string(ch))
+		i += size
+	}
+	return sb.String()
+}
@@ -3,20 +3,29 @@
 package ui
 
 import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
-	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/evertras/bubble-table/table"
+	"github.com/nhath/ezdb/internal/audit"
 	"github.com/nhath/ezdb/internal/config"
 	"github.com/nhath/ezdb/internal/db"
 	"github.com/nhath/ezdb/internal/history"
+	"github.com/nhath/ezdb/internal/session"
 	"github.com/nhath/ezdb/internal/ui/autocomplete"
+	"github.com/nhath/ezdb/internal/ui/components/historylist"
 	"github.com/nhath/ezdb/internal/ui/components/profileselector"
 	"github.com/nhath/ezdb/internal/ui/components/schemabrowser"
 	eztable "github.com/nhath/ezdb/internal/ui/components/table"
+	"github.com/nhath/ezdb/internal/ui/highlight"
+	"github.com/nhath/ezdb/internal/ui/styles"
 )
 
 // Model is the root Bubble Tea model
@@ -30,14 +39,23 @@ type Model struct {
 	profile       *config.Profile
 	driver        db.Driver
 	historyStore  *history.Store
+	auditLog      audit.Log // nil unless auditing is configured
+	osUser        string    // Recorded in the audit log
 	config        *config.Config
+	theme         *styles.Theme   // Colors and styles of the active theme
+	tableBuilder  eztable.Builder // Builds result tables in the theme and keys
 
 	// Profile selector
 	profileSelector profileselector.Model
 
+	// Session restore
+	sessionPath  string         // Where the session is saved on quit, "" to not save it
+	sessionOffer *session.State // Previous session offered on the profile selector
+	restoring    *session.State // Accepted session, applied as the profile loads
+
 	// Components
 	editor        textarea.Model
-	viewport      viewport.Model
+	historyList   historylist.Model
 	history       []history.HistoryEntry
 	expandedID    int64 // ID of the currently expanded history item
 	expandedTable table.Model
@@ -45,6 +63,7 @@ type Model struct {
 
 	// Results
 	results      *db.QueryResult
+	lastRun      *runSummary // Shown in the status bar until the next query
 	resultsTable table.Model
 	page         int // current results page
 
@@ -52,36 +71,177 @@ type Model struct {
 	popupStack         *PopupStack // Stack of popup closers for layered closing
 	showPopup          bool
 	showActionPopup    bool
-	showRowActionPopup bool // NEW: for showing detailed row actions
+	showRowActionPopup bool       // NEW: for showing detailed row actions
+	rowActionColumn    int        // Result column the row action quick filters compare
+	showRowDetail      bool       // Row detail panel beside the results
+	rowDetail          []rowField // Columns of the row in the row detail panel
+	rowDetailIdx       int        // Selected field in the row detail panel
+	rowDetailRow       int        // 1-based result row shown in the row detail panel
+	showColumnActions  bool       // Column aggregates menu over the results
+	columnActionCol    int        // Result column the column aggregates run on
 	showExportPopup    bool
 	showHelpPopup      bool   // Show keyboard shortcuts
 	showTemplatePopup  bool   // Show query template picker
 	templateTable      string // Table name for template
 	templateIdx        int    // Selected template index
+	showTemplateEditor bool   // Show the query template manager
+	templateEditIdx    int    // Selected configured template, or len when adding one
+	templateForm       bool   // Editing the selected template
+	templateInputs     [templateFields]textinput.Model
+	templateField      int    // Focused input of the template form
+	templateMsg        string // Last save result or form error
 	exportInput        textinput.Model
 	exportTable        string // Table name being exported
-	showImportPopup    bool   // Show import dialog
+	exportFilterInputs [exportFilterFields]textinput.Model
+	exportField        int            // Focused input of a table export, 0 the file name
+	columnMatches      []string       // Column completions of an export filter input
+	columnMatchIdx     int            // Completion Tab last cycled to, -1 before cycling
+	exportEstimate     exportEstimate // Rows the table export would write
+	exportConfirm      bool           // Enter asked once before exporting a large table whole
+	stdoutExport       string         // Results exported to "-", printed on exit
+	pathMatches        []string       // Completions of the export or import file name
+	pathMatchIdx       int            // Completion Tab last cycled to, -1 before cycling
+	showImportPopup    bool           // Show import dialog
 	importInput        textinput.Model
 	importTable        string // Table name for import
+	importOpts         importOptions
+	importResume       *importResume // Where the last failed import stopped
+	showGeneratePopup  bool          // Show the generated row count prompt
+	generateInput      textinput.Model
+	generateTable      string // Table to fill with generated rows
+	showTableAction    bool   // Show the truncate / drop confirmation
+	tableActionInput   textinput.Model
+	tableAction        string // "TRUNCATE" or "DROP"
+	tableActionTable   string // Table the action applies to
+	showCopyTable      bool   // Show the copy table wizard
+	showQueryBuilder   bool   // Show the query builder
+	showAssistant      bool   // Show the assistant prompt
+	assistantBusy      bool   // A request to the assistant is out
+	assistantInput     textinput.Model
+	copySource         string // Table being copied
+	copyStep           int    // 0 picks the target profile, 1 names the target table
+	copyProfileIdx     int
+	copyInput          textinput.Model
+	copyCreate         bool // Create the target table before copying
+	showAttachPopup    bool // Show SQLite ATTACH manager
+	attachInput        textinput.Model
+	attachments        []db.Attachment
+	attachIdx          int  // Selected attachment
+	showSchemaSwitch   bool // Show schema / database switcher
+	schemaInput        textinput.Model
+	schemas            []string
+	schemaIdx          int                // Selected schema among the filtered ones
+	currentSchema      string             // search_path schema or MySQL database in use
+	showKeybindPopup   bool               // Show keybindings editor
+	keybindIdx         int                // Selected action
+	keybindCapture     keybindCaptureMode // Waiting for a key to bind
+	keybindMsg         string             // Last rebind result or conflict
+	showFilePopup      bool               // Show /open and /save prompt
+	fileInput          textinput.Model
+	fileIdx            int    // Selected recent file, -1 for none
+	currentFile        string // SQL file last opened or saved
+	showBrowsePopup    bool   // Show table data browser
+	browser            *tableBrowser
+	builder            *queryBuilder // Query builder state while it is open
+	browseFilterActive bool
+	browseFilterInput  textinput.Model
 	popupEntry         *history.HistoryEntry
 	popupResult        *db.QueryResult
 	popupTable         table.Model
+	popupOffset        int             // Result row popupTable starts at; spilled results are shown a window at a time
+	spilled            *db.QueryResult // Shown result with rows on disk, closed once another replaces it
+
+	// Results dock (results_layout = "dock")
+	dockEntry  *history.HistoryEntry
+	dockResult *db.QueryResult
+	dockTable  table.Model
 
 	// Autocomplete
 	autocompleting    bool
 	suggestions       []string
 	suggestionDetails []string                      // Column types, function signatures
 	suggestionTypes   []autocomplete.SuggestionType // Type indicators for suggestions
+	suggestionSnips   []string                      // Text to insert instead, when set
 	suggestionIdx     int
+	historyIndex      *autocomplete.HistoryIndex // Past queries and identifiers, nil until loaded
 	tables            []string
-	columns           map[string][]db.Column // table -> columns
+	columns           map[string][]db.Column     // table -> columns
+	constraints       map[string][]db.Constraint // table -> constraints, for join suggestions
+	functions         []db.Function              // User-defined functions
 	loadingTables     bool
+	formatNumbers     bool           // Lay out numeric result cells, toggled in the results popup
+	fullWidthColumn   string         // Results popup column shown uncapped, "" for none
+	popupFrozen       int            // Leading results popup columns kept in view while scrolling
+	sessionLocation   *time.Location // Database session timezone, when history times follow it
 
 	// Status
-	loading      bool
-	errorMsg     string
-	statusMsg    string // Success/info notifications (shown in status bar, not history)
-	connectError string
+	loading       bool
+	cancelQuery   context.CancelFunc   // Cancels the running query, nil when idle
+	transfer      *TransferProgressMsg // Running export/import progress, nil when idle
+	inTransaction bool                 // Set after BEGIN until COMMIT/ROLLBACK
+	quitConfirm   bool                 // Asking whether to quit over running work
+	errorMsg      string
+	statusMsg     string // Success/info notifications (shown in status bar, not history)
+
+	// Notification center: log of past messages and the toast queue
+	notifications          []notification
+	toasts                 []notification
+	notifyID               int
+	showNotificationsPopup bool
+	notificationIdx        int
+	connectError           string
+
+	// Activity view: the server's sessions, refreshed while open
+	showActivityPopup bool
+	sessions          []db.Session
+	activityIdx       int
+	activitySeq       int    // Latest listing; older ones do not schedule a refresh
+	activityConfirm   string // "cancel" or "terminate" awaiting y/n
+	activityTarget    string // Session the pending action stops
+
+	// Server dashboard, refreshed while open
+	showDashboard bool
+	metrics       *db.ServerMetrics
+	metricsErr    string
+	metricsAt     time.Time
+	dashboardSeq  int // Latest poll; older ones do not schedule a refresh
+
+	// Schema snapshots of the profile and the diff against one
+	showSnapshots    bool
+	snapshots        []history.SchemaSnapshot
+	snapshotIdx      int
+	snapshotAll      bool // List the snapshots of every profile
+	snapshotNaming   bool // The name prompt of a new snapshot is open
+	snapshotInput    textinput.Model
+	snapshotConfirm  bool // Deleting the selected snapshot awaits y/n
+	snapshotDiff     []db.SchemaChange
+	snapshotDiffName string // Snapshot the diff is against; "" shows the list
+	snapshotScroll   int
+
+	// Query analytics from the history store
+	showAnalytics bool
+	analyticsTab  int // Index into analyticsTabs
+	analytics     *queryAnalytics
+
+	// Session transcript picker over the loaded history
+	showTranscript   bool
+	transcriptIdx    int
+	transcriptPicked map[int64]bool // History entry IDs in the transcript
+	transcriptOpts   transcriptOptions
+
+	// Re-running a history entry on another profile
+	showRunOn     bool
+	runOnEntry    history.HistoryEntry
+	runOnIdx      int                  // Index into runOnTargets()
+	runOnWarnings []string             // Guard warnings of the target awaiting y/n
+	runOnDrivers  map[string]db.Driver // Connections opened to other profiles, by name
+
+	// Explanation of a failed history entry
+	showErrorHelp  bool
+	errorHelpEntry history.HistoryEntry
+	errorHelpBusy  bool   // The assistant is writing a fix
+	errorHelpFix   string // The assistant's suggestion, "" until asked
+	errorHelpErr   string // Why asking the assistant failed
 
 	// Search mode
 	searching   bool
@@ -93,25 +253,91 @@ type Model struct {
 
 	// Table filtering
 	tableFilterActive bool
+	tableFilterServer bool // Filter compiles to a WHERE clause and re-runs the query
 	tableFilterInput  textinput.Model
 
+	// Help popup search
+	helpFilterActive bool
+	helpFilterInput  textinput.Model
+
+	// Popup inputs already built by ensureInput
+	builtInputs lazyInput
+
 	// Debounce
 	debounceID int
 
+	// Partial key chord and its timeout generation
+	pendingKeys []tea.KeyMsg
+	chordID     int
+
+	// Pending sidebar size save (debounced like debounceID)
+	sidebarSaveID int
+
 	// Schema browser sidebar
-	schemaBrowser schemabrowser.Model
+	schemaBrowser  schemabrowser.Model
+	sidebarBlurred bool // Docked sidebar visible but the main pane has focus
 
 	// Theme selector
 	themeSelector ThemeSelector
 
-	// Undo/Redo history
-	undoStack []string
-	redoStack []string
+	// Snippet tab-stops, active after inserting a snippet until tabbing past the last
+	tabStops        []tabStop
+	tabStopIdx      int
+	tabStopSelected bool // The active placeholder is replaced by typing
+
+	// Problem found in the editor's SQL before running it, while the text is still lintQuery
+	lintErr   *db.SyntaxError
+	lintQuery string
+
+	// Undo/Redo history, snapshotted when typing pauses
+	edits      *history.EditHistory
+	editIdleID int
 
 	// Strict mode
 	strictMode   bool
 	confirming   bool
 	pendingQuery string
+	pendingCost  string // Dry-run estimate shown in the confirm prompt
+	// Query guard warnings shown in the confirm prompt
+	pendingWarnings []string
+	pendingStrict   bool   // Strict mode asks, not only the guards
+	pendingScript   string // File /run runs once confirmed; pendingQuery holds its content
+	estimatingRows  bool   // EXPLAIN is estimating the rows of a large write
+
+	// Startup profiling
+	firstRender *firstRenderHook
+}
+
+// firstRenderHook runs once when the first full frame is rendered
+type firstRenderHook struct {
+	once sync.Once
+	fn   func()
+}
+
+// WithFirstRenderHook registers fn to run after the first sized frame is rendered
+func (m Model) WithFirstRenderHook(fn func()) Model {
+	m.firstRender = &firstRenderHook{fn: fn}
+	return m
+}
+
+// WithAuditLog records every statement the UI executes in log
+func (m Model) WithAuditLog(log audit.Log) Model {
+	m.auditLog = log
+	m.osUser = audit.CurrentUser()
+	return m
+}
+
+// WithSession saves the session to path on quit and offers to restore prev,
+// when its profile still exists
+func (m Model) WithSession(path string, prev *session.State) Model {
+	m.sessionPath = path
+	if prev == nil {
+		return m
+	}
+	if _, err := m.config.GetProfile(prev.Profile); err == nil {
+		m.sessionOffer = prev
+	}
+	return m
 }
 
 // NewModel creates a new UI model
@@ -130,55 +356,7 @@ func NewModel(cfg *config.Config, profile *config.Profile, driver db.Driver, sto
 	ti.FocusedStyle.Placeholder = lipgloss.NewStyle().Foreground(lipgloss.Color(cfg.Theme.Error))
 	ti.BlurredStyle.Placeholder = lipgloss.NewStyle().Foreground(lipgloss.Color(cfg.Theme.Error))
 
-	// Initialize Table Filter Input
-	tfi := textinput.New()
-	tfi.Prompt = "/ "
-	tfi.Placeholder = "Filter table..."
-	tfi.CharLimit = 100
-	tfi.Width = 30
-
-	// Initialize Export Input
-	ei := textinput.New()
-	ei.Prompt = "Export to: "
-	ei.Placeholder = "export.csv"
-	ei.CharLimit = 256
-	ei.Width = 40
-
-	// Initialize Search Input
-	si := textinput.New()
-	si.Prompt = "/ "
-	si.Placeholder = "Search history..."
-	si.CharLimit = 100
-	si.Width = 30
-
-	// Initialize Import Input
-	ii := textinput.New()
-	ii.Prompt = "Import from: "
-	ii.Placeholder = "path/to/file.csv"
-	ii.CharLimit = 256
-	ii.Width = 40
-
-	vp := viewport.New(80, 10)
-
-	// Convert config profiles to selector profiles
-	selectorProfiles := make([]profileselector.Profile, len(cfg.Profiles))
-	for i, p := range cfg.Profiles {
-		selectorProfiles[i] = profileselector.Profile{
-			Name:        p.Name,
-			Type:        p.Type,
-			Host:        p.Host,
-			Port:        p.Port,
-			User:        p.User,
-			Database:    p.Database,
-			Password:    p.Password,
-			SSHHost:     p.SSHHost,
-			SSHPort:     p.SSHPort,
-			SSHUser:     p.SSHUser,
-			SSHKeyPath:  p.SSHKeyPath,
-			SSHPassword: p.SSHPassword,
-		}
-	}
-	ps := profileselector.New(selectorProfiles, cfg.Theme)
+	ps := profileselector.New(selectorProfiles(cfg.Profiles), cfg.Theme)
 
 	// Determine initial state
 	initialState := StateSelectingProfile
@@ -187,17 +365,20 @@ func NewModel(cfg *config.Config, profile *config.Profile, driver db.Driver, sto
 		initialState = StateReady
 	}
 
-	// Initialize eztable global config
-	eztable.Init(cfg.Theme, cfg.Keys)
+	tables := eztable.NewBuilder(cfg.Theme, cfg.Keys)
 
 	return Model{
 		appState:        initialState,
 		mode:            VisualMode,
 		profile:         profile,
+		strictMode:      profile != nil && cfg.Protected(profile),
 		config:          cfg,
 		driver:          driver,
 		historyStore:    store,
+		edits:           history.NewEditHistory("", cfg.UndoDepth),
 		popupStack:      NewPopupStack(),
+		theme:           styles.New(cfg.Theme),
+		tableBuilder:    tables,
 		profileSelector: ps,
 		schemaBrowser: schemabrowser.New().SetStyles(schemabrowser.Styles{
 			Container:     lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color(cfg.Theme.Highlight)).Padding(1, 2),
@@ -212,31 +393,36 @@ func NewModel(cfg *config.Config, profile *config.Profile, driver db.Driver, sto
 			Spinner:       lipgloss.NewStyle().Foreground(lipgloss.Color(cfg.Theme.Highlight)),
 			TabActive:     lipgloss.NewStyle().Foreground(lipgloss.Color(cfg.Theme.Success)).Bold(true).Border(lipgloss.NormalBorder(), false, false, true, false).BorderForeground(lipgloss.Color(cfg.Theme.Success)).Padding(0, 1),
 			TabInactive:   lipgloss.NewStyle().Foreground(lipgloss.Color(cfg.Theme.TextFaint)).Padding(0, 1),
-		}),
-		themeSelector:    NewThemeSelector(cfg),
-		editor:           ti,
-		viewport:         vp,
-		history:          []history.HistoryEntry{},
-		expandedID:       0,
-		selected:         0,
-		page:             0,
-		columns:          make(map[string][]db.Column),
-		tableFilterInput: tfi,
-		exportInput:      ei,
-		importInput:      ii,
-		searchInput:      si,
+		}).SetTableBuilder(tables),
+		editor:      ti,
+		historyList: historylist.New().SetHighlightFunc(highlight.SQL),
+		history:     []history.HistoryEntry{},
+		expandedID:  0,
+		selected:    0,
+		page:        0,
+		columns:     make(map[string][]db.Column),
+
+		formatNumbers: cfg.NumberFormat.Enabled,
 	}
 }
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
+	watchThemes := watchThemesCmd(config.UserThemesStamp())
+	if strings.Contains(m.statusFormat(), "{clock}") {
+		watchThemes = tea.Batch(watchThemes, clockTickCmd())
+	}
 	if m.appState == StateReady {
 		return tea.Batch(
 			textarea.Blink,
 			m.loadHistoryCmd(),
+			m.loadEditsCmd(),
+			m.loadHistoryIndexCmd(),
+			m.sessionTimezoneCmd(),
 			schemabrowser.LoadSchemaCmd(m.driver),
+			watchThemes,
 		)
 	}
 	// In profile selection state, just wait for input
-	return nil
+	return watchThemes
 }
@@ -3,61 +3,192 @@
 package ui
 
 import (
-	"fmt"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/evertras/bubble-table/table"
+
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/db"
 )
 
-// isModifyingQuery returns true if the SQL statement is a write operation
+// modifyingCommands are the leading keywords of statements that write to the
+// database or change its schema, grants or settings
+var modifyingCommands = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "DROP": true, "ALTER": true,
+	"TRUNCATE": true, "CREATE": true, "REPLACE": true, "MERGE": true, "UPSERT": true,
+	"GRANT": true, "REVOKE": true, "COPY": true, "CALL": true, "DO": true,
+	"EXEC": true, "EXECUTE": true, "RENAME": true, "COMMENT": true, "LOAD": true,
+	"IMPORT": true, "REINDEX": true, "VACUUM": true, "CLUSTER": true, "LOCK": true,
+	"REFRESH": true, "ATTACH": true, "DETACH": true,
+}
+
+// isModifyingQuery returns true if any statement of the query is a write
+// operation. Comments are skipped, so neither a leading comment nor a
+// trailing statement hides a write.
 func isModifyingQuery(query string) bool {
-	q := strings.TrimSpace(strings.ToUpper(query))
-	modifyingOps := []string{
-		"INSERT", "UPDATE", "DELETE", "DROP", "ALTER", "TRUNCATE", "CREATE", "REPLACE",
-	}
-	for _, op := range modifyingOps {
-		if strings.HasPrefix(q, op) {
+	for _, stmt := range splitStatements(query) {
+		if isModifyingStatement(guardTokens(stmt)) {
 			return true
 		}
 	}
 	return false
 }
 
-// matchKey returns true if the key message matches any of the provided key strings
+// isModifyingStatement classifies a single statement by its guard tokens
+func isModifyingStatement(tokens []string) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	switch first := tokens[0]; {
+	case modifyingCommands[first]:
+		return true
+	case first == "WITH" || first == "EXPLAIN":
+		// Data-modifying CTEs and EXPLAIN ANALYZE run their writes
+		for _, op := range []string{"INSERT", "UPDATE", "DELETE", "MERGE"} {
+			if containsToken(tokens, op) {
+				return first == "WITH" || containsToken(tokens, "ANALYZE")
+			}
+		}
+	case first == "SELECT":
+		return containsToken(tokens, "INTO")
+	case first == "SET" || first == "BEGIN" || first == "START":
+		// Leaving the read-only session mode
+		for i, tok := range tokens {
+			switch tok {
+			case "DEFAULT_TRANSACTION_READ_ONLY", "TRANSACTION_READ_ONLY", "TX_READ_ONLY", "QUERY_ONLY":
+				return true
+			case "READ":
+				if i+1 < len(tokens) && tokens[i+1] == "WRITE" {
+					return true
+				}
+			}
+		}
+	case first == "PRAGMA":
+		return containsToken(tokens, "QUERY_ONLY")
+	}
+	return false
+}
+
+// transactionState returns whether a transaction is open after running stmt
+func transactionState(stmt string, inTx bool) bool {
+	fields := strings.Fields(strings.ToUpper(stmt))
+	if len(fields) == 0 {
+		return inTx
+	}
+	switch fields[0] {
+	case "BEGIN":
+		return true
+	case "START":
+		return inTx || (len(fields) > 1 && fields[1] == "TRANSACTION")
+	case "COMMIT", "END", "ABORT":
+		return false
+	case "ROLLBACK":
+		// ROLLBACK TO SAVEPOINT keeps the transaction open
+		return inTx && len(fields) > 1 && fields[1] == "TO"
+	}
+	return inTx
+}
+
+// profileColor returns the connected profile's color; production profiles
+// without one are shown in the theme's error color
+func (m Model) profileColor() (lipgloss.Color, bool) {
+	switch {
+	case m.profile == nil:
+		return "", false
+	case m.profile.Color != "":
+		return lipgloss.Color(m.profile.Color), true
+	case m.profile.IsProduction():
+		return m.theme.ErrorColor(), true
+	}
+	return "", false
+}
+
+// connectionAccent returns the connected profile's color, or the theme accent
+func (m Model) connectionAccent() lipgloss.Color {
+	if color, ok := m.profileColor(); ok {
+		return color
+	}
+	return m.theme.AccentColor()
+}
+
+// inputStyle is the editor style, bordered in the profile color when one is set
+func (m Model) inputStyle() lipgloss.Style {
+	if color, ok := m.profileColor(); ok {
+		return m.theme.InputStyle.BorderForeground(color)
+	}
+	return m.theme.InputStyle
+}
+
+// matchKey returns true if the key message matches any of the provided key strings.
+// Chords match the synthetic message dispatched once all their keys were pressed.
 func matchKey(msg tea.KeyMsg, keys []string) bool {
 	keyStr := msg.String()
 	for _, k := range keys {
-		if k == keyStr {
+		if k == keyStr || normalizeChord(k) == keyStr {
 			return true
 		}
 	}
 	return false
 }
 
-// unwrapCellValue extracts the raw value from a bubble-table StyledCell if necessary.
-// Since StyledCell fields might be unexported or hard to access, we use a robust string check.
+// unwrapCellValue extracts the raw value from a bubble-table StyledCell if
+// necessary, the value as the database returned it for formatted numbers
 func unwrapCellValue(val interface{}) interface{} {
 	if _, ok := val.(table.StyledCell); ok {
-		// Formatted struct looks like {Value Style ...}
-		// e.g. {3 [38;2;...}
-		s := fmt.Sprintf("%v", val)
-		s = strings.TrimPrefix(s, "{")
-		if idx := strings.Index(s, " "); idx != -1 {
-			return s[:idx]
-		}
-		// Fallback: return the whole string if parsing fails, but cleaner
-		return strings.TrimSuffix(s, "}")
+		return cellText(val)
 	}
 	return val
 }
 
-// limitString truncates s to maxLen by replacing the middle with "..."
+// limitString truncates s to maxLen terminal cells by replacing the middle
+// with "...", never splitting a wide character or emoji
 func limitString(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	width := ansi.StringWidth(s)
+	if width <= maxLen {
 		return s
 	}
 	// replace middle with ...
 	half := (maxLen - 3) / 2
-	return s[:half] + "..." + s[len(s)-half:]
+	return ansi.Truncate(s, half, "") + "..." + ansi.TruncateLeft(s, width-half, "")
+}
+
+// truncateEnd cuts s to maxLen terminal cells, ending it with "..." when cut
+func truncateEnd(s string, maxLen int) string {
+	return ansi.Truncate(s, maxLen, "...")
+}
+
+// padRight pads s with spaces to width terminal cells, which fmt's %-*s
+// miscounts for wide characters
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-ansi.StringWidth(s), 0))
+}
+
+// flavorTemplates are quick queries offered only on a given server flavor
+var flavorTemplates = map[string][]config.QueryTemplate{
+	db.FlavorCockroachDB: {
+		{Name: "SHOW RANGES", Query: "SHOW RANGES FROM TABLE <table>"},
+		{Name: "SELECT 100 AS OF SYSTEM TIME", Query: "SELECT * FROM <table> AS OF SYSTEM TIME '-10s' LIMIT 100"},
+		{Name: "FOLLOWER READ", Query: "SELECT * FROM <table> AS OF SYSTEM TIME follower_read_timestamp() LIMIT 100"},
+		{Name: "SHOW STATISTICS", Query: "SHOW STATISTICS FOR TABLE <table>"},
+	},
+}
+
+// queryTemplates returns the configured quick queries in the connected
+// driver's dialect, plus those for the connected flavor
+func (m Model) queryTemplates() []config.QueryTemplate {
+	var dialect string
+	if m.driver != nil {
+		dialect = string(m.driver.Type())
+	}
+	templates := make([]config.QueryTemplate, 0, len(m.config.QueryTemplates))
+	for _, t := range m.config.QueryTemplates {
+		templates = append(templates, t.For(dialect))
+	}
+	if reporter, ok := m.driver.(db.FlavorReporter); ok {
+		templates = append(templates, flavorTemplates[reporter.Flavor()]...)
+	}
+	return templates
 }
@@ -0,0 +1,69 @@
+// internal/ui/model_helpers_test.go
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestWideTextHelpers(t *testing.T) {
+	tests := []struct {
+		name, in string
+		max      int
+		want     string
+	}{
+		{"ascii fits", "orders", 10, "orders"},
+		{"ascii", "customer_orders_archive", 11, "cust...hive"},
+		{"cjk", "顧客注文履歴テーブル", 11, "顧客...ブル"},
+		{"emoji", "🚀🚀🚀🚀🚀🚀🚀🚀", 11, "🚀🚀...🚀🚀"},
+	}
+	for _, tt := range tests {
+		got := limitString(tt.in, tt.max)
+		if got != tt.want {
+			t.Errorf("%s: limitString = %q, want %q", tt.name, got, tt.want)
+		}
+		if w := lipgloss.Width(got); w > tt.max {
+			t.Errorf("%s: limitString is %d cells wide, max %d", tt.name, w, tt.max)
+		}
+	}
+
+	if got := truncateEnd("SELECT '日本語テキスト'", 15); got != "SELECT '日本..." {
+		t.Errorf("truncateEnd = %q", got)
+	}
+	for _, s := range []string{"users", "ユーザー", "📦 box"} {
+		if w := lipgloss.Width(padRight(s, 12)); w != 12 {
+			t.Errorf("padRight(%q) is %d cells wide, want 12", s, w)
+		}
+	}
+}
+
+func TestIsModifyingQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"SELECT * FROM users", false},
+		{"-- note\nDELETE FROM users", true},
+		{"/* x */ DROP TABLE users", true},
+		{"WITH d AS (DELETE FROM users RETURNING id) SELECT * FROM d", true},
+		{"WITH d AS (SELECT id FROM users) SELECT * FROM d", false},
+		{"SELECT 1; DROP TABLE t", true},
+		{"SELECT 'DROP TABLE t'", false},
+		{"SELECT id INTO archive FROM users", true},
+		{"EXPLAIN DELETE FROM users", false},
+		{"EXPLAIN ANALYZE DELETE FROM users", true},
+		{"GRANT SELECT ON users TO bob", true},
+		{"MERGE INTO t USING s ON t.id = s.id WHEN MATCHED THEN DELETE", true},
+		{"COPY users FROM '/tmp/users.csv'", true},
+		{"CALL refresh()", true},
+		{"SET SESSION TRANSACTION READ WRITE", true},
+		{"SET default_transaction_read_only = off", true},
+		{"SET search_path TO app", false},
+	}
+	for _, tt := range tests {
+		if got := isModifyingQuery(tt.query); got != tt.want {
+			t.Errorf("isModifyingQuery(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -3,9 +3,12 @@
 package ui
 
 import (
+	"time"
+
 	"github.com/nhath/ezdb/internal/config"
 	"github.com/nhath/ezdb/internal/db"
 	"github.com/nhath/ezdb/internal/history"
+	"github.com/nhath/ezdb/internal/ui/autocomplete"
 )
 
 // DebounceMsg triggers the actual autocomplete lookup after delay
@@ -21,12 +24,44 @@ type QueryResultMsg struct {
 	Err        error
 }
 
+// CostEstimateMsg sent when a dry-run cost estimate completes
+type CostEstimateMsg struct {
+	Query   string
+	Summary string
+	Err     error
+}
+
+// LintResultMsg sent when a driver dry run of the editor's SQL completes
+type LintResultMsg struct {
+	Query string
+	Err   *db.SyntaxError
+}
+
+// RowEstimateMsg sent when EXPLAIN has estimated the rows a pending write touches
+type RowEstimateMsg struct {
+	Query string
+	Rows  int64
+	Err   error
+}
+
 // HistoryLoadedMsg sent when history loads from SQLite
 type HistoryLoadedMsg struct {
 	Entries []history.HistoryEntry
 	Err     error
 }
 
+// HistoryIndexLoadedMsg sent when past queries are indexed for autocomplete
+type HistoryIndexLoadedMsg struct {
+	Index *autocomplete.HistoryIndex
+	Err   error
+}
+
+// FunctionsLoadedMsg sent when the database's user-defined functions are listed
+type FunctionsLoadedMsg struct {
+	Functions []db.Function
+	Err       error
+}
+
 // RerunResultMsg sent when re-running a query from history
 type RerunResultMsg struct {
 	Entry  *history.HistoryEntry
@@ -45,12 +80,27 @@ type ProfileConnectedMsg struct {
 	Err    error
 }
 
+// ConnectionTestedMsg is sent when a connection test from the profile form completes
+type ConnectionTestedMsg struct {
+	Name string
+	Err  error
+}
+
 // ClipboardCopiedMsg is sent when clipboard copy completes
 type ClipboardCopiedMsg struct {
 	Text string
 	Err  error
 }
 
+// ExportEstimateMsg sent when the rows a table export would write are counted
+type ExportEstimateMsg struct {
+	Table  string
+	Filter string
+	Rows   int64
+	Exact  bool
+	Err    error
+}
+
 // ExportTableCompleteMsg is sent when table export completes
 type ExportTableCompleteMsg struct {
 	Filename string
@@ -60,7 +110,79 @@ type ExportTableCompleteMsg struct {
 
 // ImportTableCompleteMsg is sent when table import completes
 type ImportTableCompleteMsg struct {
-	Rows int
+	Table     string
+	File      string
+	DryRun    bool // Rows were only checked; Rows counts those that would be inserted
+	Rows      int
+	Errors    []string // Rows skipped, by CSV line
+	ResumeRow int      // Data row a retry should start from after a failure
+	Err       error
+}
+
+// GenerateDataCompleteMsg is sent when generating rows for a table completes
+type GenerateDataCompleteMsg struct {
+	Table string
+	Rows  int
+	Err   error
+}
+
+// TableActionMsg is sent when a TRUNCATE or DROP from the schema browser completes
+type TableActionMsg struct {
+	Action string // "TRUNCATE" or "DROP"
+	Table  string
+	Err    error
+}
+
+// CopyTableCompleteMsg is sent when copying a table to a profile completes
+type CopyTableCompleteMsg struct {
+	Table   string
+	Profile string
+	Rows    int
+	Created bool // A table was created in the connected database
+	Err     error
+}
+
+// SnapshotsMsg carries a profile's schema snapshots after listing, saving or deleting
+type SnapshotsMsg struct {
+	Snapshots []history.SchemaSnapshot
+	Status    string
+	Err       error
+}
+
+// SnapshotDiffMsg carries the changes of the live schema since a snapshot
+type SnapshotDiffMsg struct {
+	Name    string
+	Changes []db.SchemaChange
+	Err     error
+}
+
+// AnalyticsMsg carries the query analytics of a profile's history
+type AnalyticsMsg struct {
+	Analytics queryAnalytics
+	Err       error
+}
+
+// StoredResultMsg carries the full result kept with a history entry, nil
+// when none was stored
+type StoredResultMsg struct {
+	Entry  history.HistoryEntry
+	Result *history.StoredResult
+	Err    error
+}
+
+// RunOnResultMsg carries the outcome of a history entry re-run on another
+// profile; Driver is set when a new connection was opened for it
+type RunOnResultMsg struct {
+	Profile string
+	Driver  db.Driver
+	Result  *db.QueryResult
+	Entry   *history.HistoryEntry
+	Err     error
+}
+
+// TranscriptSavedMsg is sent when a session transcript was written to a file
+type TranscriptSavedMsg struct {
+	Path string
 	Err  error
 }
 
@@ -74,4 +196,151 @@ type ExportCompleteMsg struct {
 type ThemeSelectedMsg struct {
 	ThemeName string
 	Theme     config.Theme
+	Auto      bool // Follow the terminal background from now on
+}
+
+// AttachmentsMsg sent after listing, attaching or detaching SQLite databases
+type AttachmentsMsg struct {
+	Attachments []db.Attachment
+	Status      string
+	Changed     bool // Schema changed and must be reloaded
+	Err         error
+}
+
+// SchemasMsg sent after listing schemas or switching the session to one
+type SchemasMsg struct {
+	Schemas []string
+	Current string
+	Status  string
+	Changed bool // Schema changed and must be reloaded
+	Err     error
+}
+
+// AssistantMsg sent when the assistant has written SQL for a request
+type AssistantMsg struct {
+	Request string
+	SQL     string
+	Err     error
+}
+
+// ErrorFixMsg sent when the assistant has suggested a fix for a failed
+// history entry
+type ErrorFixMsg struct {
+	EntryID int64
+	Text    string
+	Err     error
+}
+
+// SessionTimezoneMsg sent after asking the database for its session timezone
+type SessionTimezoneMsg struct {
+	Location *time.Location
+	Err      error
+}
+
+// ActivityMsg sent after listing the server's sessions
+type ActivityMsg struct {
+	Seq      int
+	Sessions []db.Session
+	Err      error
+}
+
+// ActivityTickMsg refreshes the activity view while it is open
+type ActivityTickMsg struct {
+	Seq int
+}
+
+// SessionStoppedMsg sent after cancelling or terminating a session
+type SessionStoppedMsg struct {
+	Status string
+	Err    error
+}
+
+// MetricsMsg sent after polling the server for the dashboard
+type MetricsMsg struct {
+	Seq     int
+	Metrics *db.ServerMetrics
+	Err     error
+}
+
+// MetricsTickMsg refreshes the dashboard while it is open
+type MetricsTickMsg struct {
+	Seq int
+}
+
+// SidebarSaveMsg fires after a resize pause; only the latest ID is saved
+type SidebarSaveMsg struct {
+	ID int
+}
+
+// SidebarSavedMsg reports the result of persisting the sidebar size
+type SidebarSavedMsg struct {
+	Err error
+}
+
+// ScriptRunMsg reports a finished .sql file run; Entries holds one history
+// entry per statement that ran, the last one failed when Err is set
+type ScriptRunMsg struct {
+	Path     string
+	Total    int
+	Entries  []*history.HistoryEntry
+	Duration time.Duration
+	Err      error
+}
+
+// ScriptLoadedMsg carries a .sql file read for /run, whose statements are
+// checked before any of them runs
+type ScriptLoadedMsg struct {
+	Path    string
+	Content string
+	Err     error
+}
+
+// SQLFileMsg reports a SQL file opened into the editor or saved from it
+type SQLFileMsg struct {
+	Path    string
+	Content string
+	Opened  bool
+	Err     error
+}
+
+// BrowsePageMsg carries a page fetched by the table data browser
+type BrowsePageMsg struct {
+	Seq    int
+	Result *db.QueryResult
+	Err    error
+}
+
+// EditIdleMsg fires after a typing pause; only the latest ID takes a snapshot
+type EditIdleMsg struct {
+	ID int
+}
+
+// EditsLoadedMsg carries the profile's saved edit history, nil if none was saved
+type EditsLoadedMsg struct {
+	History *history.EditHistory
+	Err     error
+}
+
+// ToastExpiredMsg removes a toast from the status bar once its time is up
+type ToastExpiredMsg struct {
+	ID int
+}
+
+// ChordTimeoutMsg fires when a partial chord got no further key in time
+type ChordTimeoutMsg struct {
+	ID int
+}
+
+// ClockTickMsg redraws the status bar clock every minute
+type ClockTickMsg struct{}
+
+// themeWatchTickMsg re-arms the theme file poll when nothing changed
+type themeWatchTickMsg struct {
+	Stamp string
+}
+
+// UserThemesChangedMsg is sent after theme files changed and were reloaded
+type UserThemesChangedMsg struct {
+	Stamp string
+	Err   error
 }
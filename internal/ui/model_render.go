@@ -1,10 +1,10 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/nhath/ezdb/internal/ui/styles"
 	overlay "github.com/rmhubbert/bubbletea-overlay"
 )
 
@@ -13,6 +13,9 @@ func (m Model) View() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
+	if m.firstRender != nil {
+		defer m.firstRender.once.Do(m.firstRender.fn)
+	}
 
 	// Show profile selector if not connected
 	if m.appState == StateSelectingProfile || m.appState == StateConnecting {
@@ -20,18 +23,32 @@ func (m Model) View() string {
 		if m.appState == StateConnecting {
 			// Show connecting status
 			connectingStyle := lipgloss.NewStyle().
-				Foreground(styles.AccentColor()).
+				Foreground(m.theme.AccentColor()).
 				Bold(true)
 			status := connectingStyle.Render("Connecting to " + m.profile.Name + "...")
 			view = lipgloss.JoinVertical(lipgloss.Center, view, status)
 		}
+		if m.sessionOffer != nil && m.appState == StateSelectingProfile {
+			offerStyle := lipgloss.NewStyle().Foreground(m.theme.AccentColor())
+			offer := fmt.Sprintf("Restore last session on %s (saved %s)? y / n",
+				m.sessionOffer.Profile, m.sessionOffer.SavedAt.Format("2006-01-02 15:04"))
+			view = lipgloss.JoinVertical(lipgloss.Center, view, offerStyle.Render(offer))
+		}
 		if m.connectError != "" {
-			errorStyle := lipgloss.NewStyle().Foreground(styles.ErrorColor())
+			errorStyle := lipgloss.NewStyle().Foreground(m.theme.ErrorColor())
 			view = lipgloss.JoinVertical(lipgloss.Center, view, errorStyle.Render("Error: "+m.connectError))
 		}
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, view)
 	}
 
+	if m.sidebarShown() {
+		return m.renderDocked()
+	}
+	return m.renderMain()
+}
+
+// renderMain renders history, editor, status bar and overlays at m.width
+func (m Model) renderMain() string {
 	// 1. Calculate dynamic editor height based on content
 	// Count lines in editor content
 	editorContent := m.editor.Value()
@@ -56,13 +73,17 @@ func (m Model) View() string {
 
 	// 2. Render Components
 	inputWidth := m.width - 4
-	inputView := styles.InputStyle.Width(inputWidth).Render(m.highlightView(m.editor.View()))
+	inputView := m.inputStyle().Width(inputWidth).Render(m.highlightView(m.editor.View()))
 
 	statusBar := m.renderStatusBar()
 	helpText := m.renderHelp()
 
 	// 2. Calculate Content Height
+	dockView := m.renderDock()
 	chromeHeight := lipgloss.Height(statusBar) + lipgloss.Height(helpText) + lipgloss.Height(inputView)
+	if dockView != "" { // lipgloss counts "" as one line
+		chromeHeight += lipgloss.Height(dockView)
+	}
 	availableHeight := m.height - chromeHeight
 	if availableHeight < 0 {
 		availableHeight = 0
@@ -74,47 +95,138 @@ func (m Model) View() string {
 	}
 
 	// 3. Render History Content (Viewport)
-	m.viewport.Height = historyHeight
-	historyView := m.viewport.View()
+	historyView := m.historyList.SetSize(m.width, historyHeight).View()
 
 	// 4. Final Layout
-	main := lipgloss.JoinVertical(lipgloss.Left,
-		historyView,
-		inputView,
-		statusBar,
-		helpText,
-	)
+	panes := []string{historyView, inputView}
+	if dockView != "" {
+		panes = append(panes, dockView)
+	}
+	main := lipgloss.JoinVertical(lipgloss.Left, append(panes, statusBar, helpText)...)
 
 	// Overlay popups if active
 	if m.showPopup || m.confirming {
 		main = m.renderPopupOverlay(main)
 	}
 
+	// Table data browser overlay
+	if m.showBrowsePopup {
+		main = m.renderBrowsePopup(main)
+	}
+
 	// Template popup overlay
 	if m.showTemplatePopup {
 		main = m.renderTemplatePopup(main)
 	}
 
+	// Template manager (opened from the template popup)
+	if m.showTemplateEditor {
+		main = m.renderTemplateEditor(main)
+	}
+
 	// Import popup overlay
 	if m.showImportPopup {
 		main = m.renderImportPopup(main)
 	}
 
+	// Generated row count prompt overlay
+	if m.showGeneratePopup {
+		main = m.renderGeneratePopup(main)
+	}
+
+	// Truncate / drop confirmation overlay
+	if m.showTableAction {
+		main = m.renderTableActionPopup(main)
+	}
+
+	// Copy table wizard overlay
+	if m.showCopyTable {
+		main = m.renderCopyTablePopup(main)
+	}
+
+	// Assistant prompt overlay
+	if m.showAssistant {
+		main = m.renderAssistantPopup(main)
+	}
+
+	// Query builder overlay
+	if m.showQueryBuilder {
+		main = m.renderQueryBuilder(main)
+	}
+
+	// Schema snapshots overlay
+	if m.showSnapshots {
+		main = m.renderSnapshotsPopup(main)
+	}
+
+	// Query analytics overlay
+	if m.showAnalytics {
+		main = m.renderAnalyticsPopup(main)
+	}
+
+	// Session transcript overlay
+	if m.showTranscript {
+		main = m.renderTranscriptPopup(main)
+	}
+
+	// Run on another profile overlay
+	if m.showRunOn {
+		main = m.renderRunOnPopup(main)
+	}
+
+	// Failed query explanation overlay
+	if m.showErrorHelp {
+		main = m.renderErrorHelp(main)
+	}
+
 	// Export popup overlay
 	if m.showExportPopup {
 		main = m.renderExportPopup(main)
 	}
 
+	// Attach manager overlay
+	if m.showAttachPopup {
+		main = m.renderAttachPopup(main)
+	}
+
+	// Schema switcher overlay
+	if m.showSchemaSwitch {
+		main = m.renderSchemaSwitch(main)
+	}
+
+	// SQL file prompt overlay
+	if m.showFilePopup {
+		main = m.renderFilePopup(main)
+	}
+
+	// Notification center overlay
+	if m.showNotificationsPopup {
+		main = m.renderNotificationsPopup(main)
+	}
+
+	// Server dashboard overlay
+	if m.showDashboard {
+		main = m.renderDashboard(main)
+	}
+
+	// Activity view overlay
+	if m.showActivityPopup {
+		main = m.renderActivityPopup(main)
+	}
+
 	// Theme Selector Overlay
 	if m.themeSelector.Visible() {
 		themeView := m.themeSelector.View(m.width, m.height)
 		main = overlay.Composite(themeView, main, overlay.Center, overlay.Center, 0, 0)
 	}
 
+	// Quit prompt overlay, above everything
+	if m.quitConfirm {
+		main = m.renderQuitConfirm(main)
+	}
+
 	// 5. Suggestions Overlay
-	hasPopup := m.hasOpenPopup() || m.showPopup || m.showHelpPopup || m.showTemplatePopup ||
-		m.showImportPopup || m.showExportPopup || m.showRowActionPopup || m.showActionPopup ||
-		m.themeSelector.Visible()
+	hasPopup := m.hasOpenPopup() || m.quitConfirm
 
 	if m.autocompleting && m.mode == InsertMode && !hasPopup {
 		suggestions := m.renderSuggestions()
@@ -178,7 +290,7 @@ func (m Model) View() string {
 		}
 	}
 
-	if m.schemaBrowser.IsVisible() || m.loadingTables { // Show if visible OR loading (for spinner)
+	if (m.schemaBrowser.IsVisible() || m.loadingTables) && !m.sidebarDocked() { // Show if visible OR loading (for spinner)
 		m.schemaBrowser = m.schemaBrowser.SetSize(m.width, m.height)
 		browser := m.schemaBrowser.View()
 		if browser != "" {
@@ -192,5 +304,10 @@ func (m Model) View() string {
 		main = m.renderHelpPopup(main)
 	}
 
+	// Keybindings editor (opened from help)
+	if m.showKeybindPopup {
+		main = m.renderKeybindPopup(main)
+	}
+
 	return main
 }
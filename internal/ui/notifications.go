@@ -0,0 +1,237 @@
+// internal/ui/notifications.go
+// Notification center: status/error messages become timed toasts and are kept in a browsable log.
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/ui/icons"
+	"github.com/nhath/ezdb/internal/ui/styles"
+)
+
+// NotifyLevel is the severity of a notification
+type NotifyLevel int
+
+const (
+	NotifyInfo NotifyLevel = iota
+	NotifySuccess
+	NotifyWarning
+	NotifyError
+)
+
+const (
+	// maxNotifications is how many messages the notification center keeps
+	maxNotifications = 50
+	// maxToasts caps the toast queue; older toasts are dropped first
+	maxToasts = 5
+	// toastDuration is how long a toast stays in the status bar; errors stay twice as long
+	toastDuration = 4 * time.Second
+)
+
+// notification is one logged message
+type notification struct {
+	ID    int
+	Time  time.Time
+	Level NotifyLevel
+	Text  string
+}
+
+// icon returns the status icon for the level
+func (l NotifyLevel) icon() string {
+	switch l {
+	case NotifySuccess:
+		return icons.IconSuccess
+	case NotifyWarning:
+		return icons.IconWarning
+	case NotifyError:
+		return icons.IconError
+	default:
+		return icons.IconInfo
+	}
+}
+
+// color returns the color for the level in theme
+func (l NotifyLevel) color(theme *styles.Theme) lipgloss.Color {
+	switch l {
+	case NotifySuccess:
+		return theme.SuccessColor()
+	case NotifyWarning:
+		return theme.WarningColor()
+	case NotifyError:
+		return theme.ErrorColor()
+	default:
+		return theme.AccentColor()
+	}
+}
+
+// duration returns how long a toast of this level is shown
+func (l NotifyLevel) duration() time.Duration {
+	if l == NotifyError {
+		return 2 * toastDuration
+	}
+	return toastDuration
+}
+
+// notify logs a message and queues it as a toast. The returned command
+// expires the toast when it is the first in the queue.
+func (m Model) notify(level NotifyLevel, text string) (Model, tea.Cmd) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return m, nil
+	}
+	m.notifyID++
+	n := notification{ID: m.notifyID, Time: time.Now(), Level: level, Text: text}
+
+	m.notifications = append(m.notifications, n)
+	if len(m.notifications) > maxNotifications {
+		m.notifications = m.notifications[len(m.notifications)-maxNotifications:]
+	}
+
+	m.toasts = append(m.toasts, n)
+	if len(m.toasts) > maxToasts {
+		m.toasts = m.toasts[len(m.toasts)-maxToasts:]
+		return m, expireToastCmd(m.toasts[0])
+	}
+	if len(m.toasts) == 1 {
+		return m, expireToastCmd(n)
+	}
+	return m, nil
+}
+
+// recordNotifications notifies about status and error messages set while handling a message
+func (m Model) recordNotifications(prevStatus, prevError string) (Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	if m.errorMsg != "" && m.errorMsg != prevError {
+		var cmd tea.Cmd
+		m, cmd = m.notify(NotifyError, m.errorMsg)
+		cmds = append(cmds, cmd)
+	}
+	if m.statusMsg != "" && m.statusMsg != prevStatus {
+		var cmd tea.Cmd
+		m, cmd = m.notify(NotifySuccess, m.statusMsg)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func expireToastCmd(n notification) tea.Cmd {
+	return tea.Tick(n.Level.duration(), func(time.Time) tea.Msg {
+		return ToastExpiredMsg{ID: n.ID}
+	})
+}
+
+// handleToastExpired drops the expired toast and starts the next one's timer
+func (m Model) handleToastExpired(msg ToastExpiredMsg) (Model, tea.Cmd) {
+	if len(m.toasts) == 0 || m.toasts[0].ID != msg.ID {
+		return m, nil
+	}
+	m.toasts = m.toasts[1:]
+	if len(m.toasts) > 0 {
+		return m, expireToastCmd(m.toasts[0])
+	}
+	return m, nil
+}
+
+// renderToast renders the current toast for the status bar, or "" when none is queued
+func (m Model) renderToast() string {
+	if len(m.toasts) == 0 {
+		return ""
+	}
+	t := m.toasts[0]
+	text := t.Text
+	if t.Level == NotifyError {
+		text = truncateEnd(text, 40)
+	}
+	if len(m.toasts) > 1 {
+		text += fmt.Sprintf(" (+%d)", len(m.toasts)-1)
+	}
+	bg := t.Level.color(m.theme)
+	return lipgloss.NewStyle().Background(bg).Foreground(m.theme.OnColor(bg)).Padding(0, 1).Render(t.Level.icon() + " " + text)
+}
+
+// openNotificationsPopup opens the notification center.
+func (m *Model) openNotificationsPopup() {
+	if m.showNotificationsPopup {
+		return
+	}
+	m.showNotificationsPopup = true
+	m.autocompleting = false
+	m.notificationIdx = 0
+	m.popupStack.Push("notifications", func(m *Model) {
+		m.showNotificationsPopup = false
+	})
+}
+
+// handleNotificationsKeys handles keys while the notification center is open
+func (m Model) handleNotificationsKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "k":
+		if m.notificationIdx > 0 {
+			m.notificationIdx--
+		}
+	case "down", "j":
+		if m.notificationIdx < len(m.notifications)-1 {
+			m.notificationIdx++
+		}
+	case "c":
+		m.notifications = nil
+		m.notificationIdx = 0
+	default:
+		if matchKey(msg, m.config.Keys.Notifications) {
+			m.closeTopPopup()
+		}
+	}
+	return m, nil, true
+}
+
+func (m Model) renderNotificationsPopup(main string) string {
+	var content strings.Builder
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Notifications")
+	content.WriteString(title)
+	content.WriteString("\n\n")
+
+	if len(m.notifications) == 0 {
+		content.WriteString(lipgloss.NewStyle().Faint(true).Render("  (none)"))
+		content.WriteString("\n")
+	}
+
+	popupWidth := min(90, m.width-10)
+	visible := max(m.height-12, 5)
+	start := 0
+	if m.notificationIdx >= visible {
+		start = m.notificationIdx - visible + 1
+	}
+	end := min(start+visible, len(m.notifications))
+	timeStyle := lipgloss.NewStyle().Faint(true)
+	// Newest first
+	for i := start; i < end; i++ {
+		n := m.notifications[len(m.notifications)-1-i]
+		prefix := "  "
+		textStyle := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
+		if i == m.notificationIdx {
+			prefix = "> "
+			textStyle = lipgloss.NewStyle().Foreground(m.theme.TextPrimary()).Bold(true)
+		}
+		icon := lipgloss.NewStyle().Foreground(n.Level.color(m.theme)).Render(n.Level.icon())
+		content.WriteString(prefix + timeStyle.Render(n.Time.Format("15:04:05")) + " " + icon + " " +
+			textStyle.Render(limitString(n.Text, max(popupWidth-20, 10))) + "\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(m.renderHintLine("↑/↓: scroll • c: clear • Esc: close", m.popupInnerWidth(popupWidth)))
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
@@ -0,0 +1,75 @@
+// internal/ui/number_format.go
+// Number formatting of result cells: thousands separators and fixed decimals in the locale's style, display only.
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/nhath/ezdb/internal/config"
+	eztable "github.com/nhath/ezdb/internal/ui/components/table"
+)
+
+// numberFormat returns the layout of numeric cells for the configured locale
+func numberFormat(cfg config.NumberFormat) *eztable.NumberFormat {
+	thousands, decimal := localeSeparators(numberLocale(cfg.Locale))
+	return &eztable.NumberFormat{Thousands: thousands, Decimal: decimal, Decimals: cfg.Decimals}
+}
+
+// numberLocale returns locale, or the one of the environment when empty,
+// as a BCP 47 tag: "de_DE.UTF-8" becomes "de-DE"
+func numberLocale(locale string) string {
+	for _, env := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if locale != "" {
+			break
+		}
+		locale = os.Getenv(env)
+	}
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	return strings.ReplaceAll(locale, "_", "-")
+}
+
+// localeSeparators returns the thousands and decimal separators of locale,
+// read off a sample number the locale's printer lays out. Unknown locales,
+// and C or POSIX, use "," and ".".
+func localeSeparators(locale string) (thousands, decimal string) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return ",", "."
+	}
+	sample, ok := strings.CutPrefix(message.NewPrinter(tag).Sprintf("%.1f", 1234567.5), "1")
+	thousands, rest, ok2 := strings.Cut(sample, "234")
+	_, decimal, ok3 := strings.Cut(rest, "567")
+	decimal, ok4 := strings.CutSuffix(decimal, "5")
+	if !ok || !ok2 || !ok3 || !ok4 {
+		return ",", "."
+	}
+	return thousands, decimal
+}
+
+// resultTables returns the table builder for query results, laying out
+// their numbers while formatting is on
+func (m Model) resultTables() eztable.Builder {
+	if !m.formatNumbers {
+		return m.tableBuilder
+	}
+	return m.tableBuilder.WithNumberFormat(numberFormat(m.config.NumberFormat))
+}
+
+// toggleNumberFormat turns number formatting of result cells on or off and
+// rebuilds the shown results
+func (m *Model) toggleNumberFormat() {
+	m.formatNumbers = !m.formatNumbers
+	if m.dockResult != nil {
+		m.setDockResult(m.dockEntry, m.dockResult)
+	}
+	m.rebuildPopupTable()
+	m.statusMsg = "Number formatting off"
+	if m.formatNumbers {
+		m.statusMsg = "Number formatting on"
+	}
+}
@@ -0,0 +1,44 @@
+// internal/ui/number_format_test.go
+package ui
+
+import (
+	"testing"
+
+	eztable "github.com/nhath/ezdb/internal/ui/components/table"
+)
+
+func TestNumberFormat(t *testing.T) {
+	f := eztable.NumberFormat{Thousands: ",", Decimal: ".", Decimals: 2}
+	tests := []struct{ in, want string }{
+		{"1234567", "1,234,567"},
+		{"-1234.5", "-1,234.50"},
+		{"999.999", "1,000.00"},
+		{"12345678901234567890.125", "12,345,678,901,234,567,890.13"},
+		{"0.5", "0.50"},
+		{"0042", "0042"}, // A code, not an amount
+		{"1e10", "1e10"},
+		{"NULL", "NULL"},
+	}
+	for _, tt := range tests {
+		if got := f.Format(tt.in); got != tt.want {
+			t.Errorf("Format(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLocaleSeparators(t *testing.T) {
+	tests := []struct{ locale, thousands, decimal string }{
+		{"en-US", ",", "."},
+		{"de-DE", ".", ","},
+		{"fr-FR", "\u00a0", ","},
+		{"C", ",", "."},
+	}
+	for _, tt := range tests {
+		if th, dec := localeSeparators(tt.locale); th != tt.thousands || dec != tt.decimal {
+			t.Errorf("%s: separators %q %q, want %q %q", tt.locale, th, dec, tt.thousands, tt.decimal)
+		}
+	}
+	if got := numberLocale("de_DE.UTF-8"); got != "de-DE" {
+		t.Errorf("numberLocale = %q, want de-DE", got)
+	}
+}
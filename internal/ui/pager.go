@@ -0,0 +1,179 @@
+// internal/ui/pager.go
+// External pager: results are written psql-style aligned (or delimited) and opened in pspg, less or the configured pager.
+package ui
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// pagerCommand returns the pager configured for the profile, falling back
+// to the global one; "" means results are not paged automatically
+func (m Model) pagerCommand() string {
+	if m.profile != nil && m.profile.Pager != "" {
+		return m.profile.Pager
+	}
+	return m.config.Pager
+}
+
+// pagerDelimiter returns the field separator of delimited pager output
+func (m Model) pagerDelimiter() rune {
+	if d := []rune(m.config.PagerDelimiter); len(d) == 1 {
+		return d[0]
+	}
+	return ','
+}
+
+// writeAligned writes a result as psql-style aligned text, which pspg and
+// less -S display with columns lined up
+func writeAligned(w io.Writer, result *db.QueryResult) error {
+	clean := strings.NewReplacer("\r\n", `\n`, "\n", `\n`, "\t", " ")
+	cells := func(row []string) []string {
+		out := make([]string, len(result.Columns))
+		for i := range out {
+			if i < len(row) {
+				out[i] = clean.Replace(row[i])
+			}
+		}
+		return out
+	}
+
+	// Measure every row first, so a spilled result is read twice rather than held
+	widths := make([]int, len(result.Columns))
+	for i, c := range result.Columns {
+		widths[i] = lipgloss.Width(c)
+	}
+	err := result.EachRow(func(row []string) error {
+		for i, c := range cells(row) {
+			widths[i] = max(widths[i], lipgloss.Width(c))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	pad := func(s string, width int) string {
+		return s + strings.Repeat(" ", width-lipgloss.Width(s))
+	}
+
+	b := bufio.NewWriter(w)
+	line := func(cells []string) error {
+		for i, c := range cells {
+			if i > 0 {
+				b.WriteString("|")
+			}
+			b.WriteString(" " + pad(c, widths[i]) + " ")
+		}
+		_, err := b.WriteString("\n")
+		return err
+	}
+	line(result.Columns)
+	for i, width := range widths {
+		if i > 0 {
+			b.WriteString("+")
+		}
+		b.WriteString(strings.Repeat("-", width+2))
+	}
+	b.WriteString("\n")
+	count := 0
+	err = result.EachRow(func(row []string) error {
+		count++
+		return line(cells(row))
+	})
+	if err != nil {
+		return err
+	}
+	if count == 1 {
+		b.WriteString("(1 row)\n")
+	} else {
+		fmt.Fprintf(b, "(%d rows)\n", count)
+	}
+	return b.Flush()
+}
+
+// pagerArgs adds the flags the known pagers need to read the file
+// correctly, unless the configured command already sets them
+func pagerArgs(command []string, delimited bool, delimiter rune) []string {
+	args := slices.Clone(command[1:])
+	has := func(flag string) bool { return slices.Contains(args, flag) }
+	switch filepath.Base(command[0]) {
+	case "pspg":
+		if delimited && !has("--csv") {
+			args = append(args, "--csv", "--csv-header", "on", "--csv-separator", string(delimiter))
+		}
+	case "less":
+		if !has("-S") && !has("--chop-long-lines") {
+			args = append(args, "-S")
+		}
+	}
+	return args
+}
+
+// openPager opens a result in the pager of the profile, or in $PAGER or
+// less when none is configured
+func (m Model) openPager(result *db.QueryResult) tea.Cmd {
+	if result == nil || len(result.Columns) == 0 {
+		return nil
+	}
+	command := strings.Fields(m.pagerCommand())
+	if len(command) == 0 {
+		command = strings.Fields(os.Getenv("PAGER"))
+	}
+	if len(command) == 0 {
+		command = []string{"less"}
+	}
+	delimited := m.config.PagerFormat == "csv"
+
+	ext := ".txt"
+	if delimited {
+		ext = ".csv"
+	}
+	f, err := os.CreateTemp("", "ezdb-*"+ext)
+	if err != nil {
+		return func() tea.Msg { return PagerFinishedMsg{Err: err} }
+	}
+	defer f.Close()
+
+	if delimited {
+		w := csv.NewWriter(f)
+		w.Comma = m.pagerDelimiter()
+		w.Write(result.Columns)
+		if err = result.EachRow(w.Write); err == nil {
+			w.Flush()
+			err = w.Error()
+		}
+	} else {
+		err = writeAligned(f, result)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return func() tea.Msg { return PagerFinishedMsg{Err: err} }
+	}
+
+	args := append(pagerArgs(command, delimited, m.pagerDelimiter()), f.Name())
+	c := exec.Command(command[0], args...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		os.Remove(f.Name()) // Cleanup
+		return PagerFinishedMsg{Err: err}
+	})
+}
+
+// handlePagerFinished reports a pager that failed to run
+func (m Model) handlePagerFinished(msg PagerFinishedMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = fmt.Sprintf("Pager error: %v", msg.Err)
+	}
+	return m, nil
+}
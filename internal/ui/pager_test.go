@@ -0,0 +1,51 @@
+// internal/ui/pager_test.go
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+func TestWriteAligned(t *testing.T) {
+	var b strings.Builder
+	result := &db.QueryResult{
+		Columns: []string{"id", "name"},
+		Rows:    [][]string{{"1", "alice"}, {"10", "line\nbreak"}},
+	}
+	if err := writeAligned(&b, result); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		" id | name        \n" +
+		"----+-------------\n" +
+		" 1  | alice       \n" +
+		" 10 | line\\nbreak \n" +
+		"(2 rows)\n"
+	if b.String() != want {
+		t.Errorf("writeAligned =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestPagerArgs(t *testing.T) {
+	tests := []struct {
+		command   string
+		delimited bool
+		want      []string
+	}{
+		{"pspg", false, []string{}},
+		{"/usr/bin/pspg -s 4", true, []string{"-s", "4", "--csv", "--csv-header", "on", "--csv-separator", ";"}},
+		{"pspg --csv", true, []string{"--csv"}},
+		{"less", false, []string{"-S"}},
+		{"less -S -R", true, []string{"-S", "-R"}},
+		{"bat", false, []string{}},
+	}
+	for _, tt := range tests {
+		got := pagerArgs(strings.Fields(tt.command), tt.delimited, ';')
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("pagerArgs(%q, %v) = %q, want %q", tt.command, tt.delimited, got, tt.want)
+		}
+	}
+}
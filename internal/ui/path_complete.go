@@ -0,0 +1,107 @@
+// internal/ui/path_complete.go
+// Path completion for file name inputs: Tab completes the common prefix, then cycles through the matches.
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxPathCompletions caps the matches listed under a file name input
+const maxPathCompletions = 8
+
+// completePath returns the completions of a typed path, directories with a
+// trailing slash, and the longest prefix they share. Hidden entries are only
+// offered once the typed name starts with a dot.
+func completePath(input string) (common string, matches []string) {
+	dir, prefix := filepath.Split(input)
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	} else if readDir == "~/" || strings.HasPrefix(readDir, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return input, nil
+		}
+		readDir = filepath.Join(home, readDir[2:])
+	}
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return input, nil
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".")) {
+			continue
+		}
+		if e.IsDir() {
+			name += string(filepath.Separator)
+		}
+		matches = append(matches, dir+name)
+	}
+	if len(matches) == 0 {
+		return input, nil
+	}
+	sort.Strings(matches)
+	common = matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	return common, matches
+}
+
+// completePathInput handles Tab in a file name input: the first press
+// completes the shared prefix, further presses cycle through the matches
+func (m *Model) completePathInput(input *textinput.Model) {
+	if len(m.pathMatches) > 1 {
+		m.pathMatchIdx = (m.pathMatchIdx + 1) % len(m.pathMatches)
+		input.SetValue(m.pathMatches[m.pathMatchIdx])
+		input.CursorEnd()
+		return
+	}
+	common, matches := completePath(input.Value())
+	input.SetValue(common)
+	input.CursorEnd()
+	m.pathMatches, m.pathMatchIdx = nil, -1
+	if len(matches) > 1 {
+		m.pathMatches = matches
+	}
+}
+
+// renderPathMatches lists the completions of a file name input, if any
+func (m Model) renderPathMatches(width int) string {
+	if len(m.pathMatches) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	faint := lipgloss.NewStyle().Faint(true)
+	start := 0
+	if m.pathMatchIdx >= maxPathCompletions {
+		start = m.pathMatchIdx - maxPathCompletions + 1
+	}
+	for i := start; i < len(m.pathMatches) && i < start+maxPathCompletions; i++ {
+		name := filepath.Base(m.pathMatches[i])
+		if strings.HasSuffix(m.pathMatches[i], string(filepath.Separator)) {
+			name += string(filepath.Separator)
+		}
+		if i == m.pathMatchIdx {
+			b.WriteString(lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Bold(true).Render("> " + limitString(name, width-2)))
+		} else {
+			b.WriteString(faint.Render("  " + limitString(name, width-2)))
+		}
+		b.WriteString("\n")
+	}
+	if len(m.pathMatches) > maxPathCompletions {
+		b.WriteString(faint.Render(fmt.Sprintf("  %d matches, Tab cycles", len(m.pathMatches))))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+// internal/ui/path_complete_test.go
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCompletePath(t *testing.T) {
+	dir := t.TempDir() + string(filepath.Separator)
+	for _, name := range []string{"report-2024.csv", "report-2025.csv", ".hidden.csv"} {
+		if err := os.WriteFile(dir+name, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(dir+"exports", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	common, matches := completePath(dir + "rep")
+	if common != dir+"report-202" || !slices.Equal(matches, []string{dir + "report-2024.csv", dir + "report-2025.csv"}) {
+		t.Errorf("completePath(rep) = %q, %q", common, matches)
+	}
+	if common, _ := completePath(dir + "ex"); common != dir+"exports"+string(filepath.Separator) {
+		t.Errorf("completePath(ex) = %q, want the directory with a separator", common)
+	}
+	if _, matches := completePath(dir); slices.Contains(matches, dir+".hidden.csv") {
+		t.Error("completePath offered a hidden file without a leading dot")
+	}
+	if common, matches := completePath(dir + ".h"); common != dir+".hidden.csv" || len(matches) != 1 {
+		t.Errorf("completePath(.h) = %q, %q", common, matches)
+	}
+	if common, matches := completePath(dir + "nothing"); common != dir+"nothing" || matches != nil {
+		t.Errorf("completePath(nothing) = %q, %q; want the input unchanged", common, matches)
+	}
+}
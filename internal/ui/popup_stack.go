@@ -1,13 +1,13 @@
 package ui
 
-// PopupCloser is a function that closes a popup and returns true if it was open
-type PopupCloser func(*Model) bool
+// PopupCloser hides a popup and resets its state
+type PopupCloser func(*Model)
 
 // PopupStack manages a stack of popup closers for proper layered popup handling
 // When Esc/q is pressed, the topmost popup is closed first
 type PopupStack struct {
 	closers []PopupCloser
-	names   []string // For debugging
+	names   []string
 }
 
 // NewPopupStack creates a new popup stack
@@ -42,7 +42,8 @@ func (s *PopupStack) CloseTop(m *Model) bool {
 	if closer == nil {
 		return false
 	}
-	return closer(m)
+	closer(m)
+	return true
 }
 
 // IsEmpty returns true if no popups are open
@@ -55,14 +56,17 @@ func (s *PopupStack) Len() int {
 	return len(s.closers)
 }
 
-// Clear closes all popups and clears the stack
-// func (s *PopupStack) Clear() {
-// 	for !s.IsEmpty() {
-// 		s.CloseTop()
-// 	}
-// }
+// Contains reports whether a popup named name is open
+func (s *PopupStack) Contains(name string) bool {
+	for _, n := range s.names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
 
-// TopName returns the name of the topmost popup for debugging
+// TopName returns the name of the topmost popup
 func (s *PopupStack) TopName() string {
 	if len(s.names) == 0 {
 		return ""
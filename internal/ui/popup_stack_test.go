@@ -0,0 +1,57 @@
+// internal/ui/popup_stack_test.go
+package ui
+
+import "testing"
+
+// stackedModel has the results, row action and export popups open, in that order
+func stackedModel() Model {
+	m := Model{popupStack: NewPopupStack()}
+	m.openResultsPopup(nil, nil)
+	m.openRowActionPopup()
+	m.openExportPopup("export.csv")
+	return m
+}
+
+func TestPopupStackOrder(t *testing.T) {
+	m := stackedModel()
+	if m.popupStack.Len() != 3 || m.popupStack.TopName() != "export" {
+		t.Fatalf("stack = %d popups, top %q; want 3, export", m.popupStack.Len(), m.popupStack.TopName())
+	}
+
+	steps := []struct {
+		top                     string
+		results, rowAction, exp bool
+	}{
+		{"rowAction", true, true, false},
+		{"results", true, false, false},
+		{"", false, false, false},
+	}
+	for _, want := range steps {
+		if !m.closeTopPopup() {
+			t.Fatalf("closeTopPopup closed nothing, want top %q next", want.top)
+		}
+		if got := m.popupStack.TopName(); got != want.top {
+			t.Errorf("top = %q, want %q", got, want.top)
+		}
+		if m.showPopup != want.results || m.showRowActionPopup != want.rowAction || m.showExportPopup != want.exp {
+			t.Errorf("with top %q shown = results %v, row action %v, export %v", want.top, m.showPopup, m.showRowActionPopup, m.showExportPopup)
+		}
+	}
+	if m.closeTopPopup() || m.hasOpenPopup() {
+		t.Error("closeTopPopup on an empty stack reported a popup")
+	}
+}
+
+func TestClosePopupsThrough(t *testing.T) {
+	m := stackedModel()
+	m.closePopupsThrough("help") // Not open
+	if m.popupStack.Len() != 3 {
+		t.Fatalf("closing an unopened popup changed the stack to %d popups", m.popupStack.Len())
+	}
+
+	m.closePopupsThrough("rowAction")
+	if m.popupStack.TopName() != "results" || m.showExportPopup || m.showRowActionPopup || !m.showPopup {
+		t.Errorf("after closing through rowAction: top %q, export %v, row action %v, results %v",
+			m.popupStack.TopName(), m.showExportPopup, m.showRowActionPopup, m.showPopup)
+	}
+}
@@ -0,0 +1,238 @@
+// internal/ui/progress.go
+// Progress reporting for long exports and imports, rendered as a status bar progress bar.
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// progressInterval throttles how often a transfer reports progress
+const progressInterval = 100 * time.Millisecond
+
+// progressBarWidth is the number of cells of the status bar progress bar
+const progressBarWidth = 20
+
+// TransferProgressMsg reports how far an export, import or script run has got.
+// The first one is sent when the transfer starts; updates carries the
+// following progress messages and finally the transfer's completion message.
+type TransferProgressMsg struct {
+	Op      string // "Export", "Import" or "Run"
+	Unit    string // What Rows counts, e.g. "rows"
+	Rows    int
+	Total   int   // Total rows, 0 while unknown
+	Bytes   int64 // Bytes written, 0 when not tracked
+	Started time.Time
+
+	cancel  context.CancelFunc
+	updates <-chan tea.Msg
+}
+
+// transferReporter is called by transfer work with rows done, total rows and bytes written
+type transferReporter func(rows, total int, bytes int64)
+
+// runTransfer runs work off the update loop and streams its progress in rows.
+// work returns the completion message, which ends the transfer.
+func runTransfer(op string, work func(ctx context.Context, report transferReporter) tea.Msg) tea.Cmd {
+	return runTransferOf(op, "rows", work)
+}
+
+// runTransferOf is runTransfer for progress counted in unit
+func runTransferOf(op, unit string, work func(ctx context.Context, report transferReporter) tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		updates := make(chan tea.Msg, 1)
+		start := TransferProgressMsg{Op: op, Unit: unit, Started: time.Now(), cancel: cancel, updates: updates}
+
+		go func() {
+			var last time.Time
+			report := func(rows, total int, bytes int64) {
+				if time.Since(last) < progressInterval {
+					return
+				}
+				last = time.Now()
+				p := start
+				p.Rows, p.Total, p.Bytes = rows, total, bytes
+				// Drop the update if the UI has not read the previous one yet
+				select {
+				case updates <- p:
+				default:
+				}
+			}
+			done := work(ctx, report)
+			cancel()
+			updates <- done
+			close(updates)
+		}()
+		return start
+	}
+}
+
+// waitForTransfer delivers the next progress or completion message of a transfer
+func waitForTransfer(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// handleTransferProgress records progress and keeps listening; the quit prompt can cancel the transfer
+func (m Model) handleTransferProgress(msg TransferProgressMsg) (Model, tea.Cmd) {
+	m.transfer = &msg
+	m.loading = true
+	m.cancelQuery = msg.cancel
+	return m, waitForTransfer(msg.updates)
+}
+
+// endTransfer clears transfer state once its completion message arrives
+func (m Model) endTransfer() Model {
+	m.transfer = nil
+	m.loading = false
+	m.cancelQuery = nil
+	return m
+}
+
+// transferError words a transfer failure, reporting cancellation plainly
+func transferError(op string, err error) string {
+	if errors.Is(err, context.Canceled) {
+		return op + " cancelled"
+	}
+	return fmt.Sprintf("%s failed: %v", op, err)
+}
+
+// handleExportTableComplete reports a finished table export
+func (m Model) handleExportTableComplete(msg ExportTableCompleteMsg) (Model, tea.Cmd) {
+	m = m.endTransfer()
+	if msg.Err != nil {
+		m.errorMsg = transferError("Export", msg.Err)
+	} else {
+		m.statusMsg = fmt.Sprintf("Exported %d rows to %s", msg.Rows, msg.Filename)
+	}
+	m.exportTable = ""
+	return m, nil
+}
+
+// handleImportTableComplete reports a finished import or validation, keeping
+// where a failed import stopped so it can resume, and lists rejected rows in history
+func (m Model) handleImportTableComplete(msg ImportTableCompleteMsg) (Model, tea.Cmd) {
+	m = m.endTransfer()
+	if msg.Err != nil {
+		op := "Import"
+		if msg.DryRun {
+			op = "Validate"
+		}
+		m.errorMsg = transferError(op, msg.Err)
+		if msg.ResumeRow > 0 {
+			m.importResume = &importResume{Table: msg.Table, File: msg.File, Row: msg.ResumeRow}
+			m.errorMsg += fmt.Sprintf(" after %d rows; import again to resume from row %d", msg.Rows, msg.ResumeRow)
+		}
+	} else if msg.DryRun && len(msg.Errors) > 0 {
+		m.errorMsg = fmt.Sprintf("Validated: %d rows would be imported, %d would fail (listed in history)", msg.Rows, len(msg.Errors))
+	} else if msg.DryRun {
+		m.statusMsg = fmt.Sprintf("Validated: all %d rows would be imported", msg.Rows)
+	} else if len(msg.Errors) > 0 {
+		m.errorMsg = fmt.Sprintf("Imported %d rows, skipped %d (listed in history)", msg.Rows, len(msg.Errors))
+	} else {
+		m.statusMsg = fmt.Sprintf("Imported %d rows", msg.Rows)
+	}
+	if msg.Err == nil && !msg.DryRun && m.importResume != nil && m.importResume.Table == msg.Table {
+		m.importResume = nil
+	}
+	if len(msg.Errors) > 0 {
+		report := msg.Errors[:min(len(msg.Errors), importErrorsShown)]
+		if more := len(msg.Errors) - len(report); more > 0 {
+			report = append(report, fmt.Sprintf("... and %d more", more))
+		}
+		summary := fmt.Sprintf("Import into %s skipped %d rows:", msg.Table, len(msg.Errors))
+		if msg.DryRun {
+			summary = fmt.Sprintf("Validating an import into %s, %d rows would fail:", msg.Table, len(msg.Errors))
+		}
+		m = m.addSystemMessage(summary + "\n" + strings.Join(report, "\n"))
+	}
+	m.importTable = ""
+	return m, nil
+}
+
+// handleGenerateDataComplete reports finished test data generation
+func (m Model) handleGenerateDataComplete(msg GenerateDataCompleteMsg) (Model, tea.Cmd) {
+	m = m.endTransfer()
+	if msg.Err != nil {
+		m.errorMsg = transferError("Generate", msg.Err)
+	} else {
+		m.statusMsg = fmt.Sprintf("Generated %d rows in %s", msg.Rows, msg.Table)
+	}
+	return m, nil
+}
+
+// handleExportComplete reports a finished export of query results
+func (m Model) handleExportComplete(msg ExportCompleteMsg) (Model, tea.Cmd) {
+	m = m.endTransfer()
+	if msg.Err != nil {
+		m.errorMsg = transferError("Export", msg.Err)
+	} else {
+		m.statusMsg = fmt.Sprintf("Exported to: %s", msg.Path)
+	}
+	return m, nil
+}
+
+// countingWriter counts bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// renderTransferProgress renders "Export ▕██░░▏ 45% 450/1000 rows · 1.2 MiB · ETA 3s"
+func (m Model) renderTransferProgress() string {
+	p := m.transfer
+	var b strings.Builder
+	b.WriteString(p.Op + " ")
+
+	if p.Total > 0 {
+		ratio := min(float64(p.Rows)/float64(p.Total), 1)
+		filled := int(ratio * progressBarWidth)
+		bar := lipgloss.NewStyle().Foreground(m.theme.AccentColor()).Render(strings.Repeat("█", filled)) +
+			lipgloss.NewStyle().Foreground(m.theme.TextFaint()).Render(strings.Repeat("░", progressBarWidth-filled))
+		fmt.Fprintf(&b, "▕%s▏ %d%% %d/%d %s", bar, int(ratio*100), p.Rows, p.Total, p.Unit)
+	} else {
+		fmt.Fprintf(&b, "%d %s", p.Rows, p.Unit)
+	}
+	if p.Bytes > 0 {
+		b.WriteString(" · " + formatByteCount(p.Bytes))
+	}
+	if p.Total > 0 && p.Rows > 0 && p.Rows < p.Total {
+		elapsed := time.Since(p.Started)
+		eta := time.Duration(float64(elapsed) / float64(p.Rows) * float64(p.Total-p.Rows))
+		b.WriteString(" · ETA " + eta.Round(time.Second).String())
+	}
+	return lipgloss.NewStyle().Foreground(m.theme.TextPrimary()).Padding(0, 1).Render(b.String())
+}
+
+// formatByteCount renders a byte count with a binary unit
+func formatByteCount(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
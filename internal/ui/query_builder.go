@@ -0,0 +1,507 @@
+// internal/ui/query_builder.go
+// Query builder: pick a table, its columns, filters and a sort order from forms, and get the SELECT written into the editor.
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/ui/highlight"
+)
+
+// Steps of the query builder, in the order Tab visits them
+const (
+	builderTable = iota
+	builderColumns
+	builderFilters
+	builderSort
+)
+
+var builderSteps = []string{"Table", "Columns", "Filters", "Sort"}
+
+// builderListRows is how many tables or columns the builder lists at once
+const builderListRows = 10
+
+// builderOp is a comparison offered for filter conditions
+type builderOp struct {
+	Label string
+	SQL   string
+}
+
+// builderOps are the comparisons of filter conditions, cycled with left and right
+var builderOps = []builderOp{
+	{"=", "="}, {"≠", "<>"}, {"<", "<"}, {"≤", "<="}, {">", ">"}, {"≥", ">="},
+	{"contains", "LIKE"}, {"starts with", "LIKE"}, {"is null", "IS NULL"}, {"is not null", "IS NOT NULL"},
+}
+
+// builderCond is one filter condition of the query builder
+type builderCond struct {
+	Column db.Column
+	Op     int // Index into builderOps
+	Value  string
+}
+
+// needsValue reports whether the comparison of op takes a value
+func needsValue(op int) bool {
+	return !strings.HasPrefix(builderOps[op].SQL, "IS ")
+}
+
+// SQL renders the condition for the driver's dialect
+func (c builderCond) SQL(dt db.DriverType) string {
+	op := builderOps[c.Op]
+	ident := quoteIdent(dt, c.Column.Name)
+	switch op.Label {
+	case "contains":
+		return ident + " LIKE " + quoteLiteral(dt, "%"+c.Value+"%", "")
+	case "starts with":
+		return ident + " LIKE " + quoteLiteral(dt, c.Value+"%", "")
+	}
+	if !needsValue(c.Op) {
+		return ident + " " + op.SQL
+	}
+	return ident + " " + op.SQL + " " + quoteLiteral(dt, c.Value, c.Column.Type)
+}
+
+// String describes the condition the way the builder shows it
+func (c builderCond) String() string {
+	if !needsValue(c.Op) {
+		return c.Column.Name + " " + builderOps[c.Op].Label
+	}
+	return c.Column.Name + " " + builderOps[c.Op].Label + " " + c.Value
+}
+
+// queryBuilder is the state of the query builder
+type queryBuilder struct {
+	Step     int
+	Cursor   int // Highlighted table or column, or the focused field of the filter and sort forms
+	Table    string
+	Columns  []db.Column
+	Picked   []bool // Columns to select; none picked selects every column
+	Conds    []builderCond
+	CondCol  int // Column of the condition being added
+	CondOp   int
+	SortCol  int // Index into Columns, -1 for the table's own order
+	SortDesc bool
+	Search   textinput.Model // Narrows the table list
+	Value    textinput.Model // Value of the condition being added
+	Limit    textinput.Model
+}
+
+// Query returns the SELECT the builder describes
+func (b *queryBuilder) Query(dt db.DriverType) string {
+	var cols []string
+	for i, c := range b.Columns {
+		if b.Picked[i] {
+			cols = append(cols, quoteIdent(dt, c.Name))
+		}
+	}
+	if len(cols) == 0 {
+		cols = []string{"*"}
+	}
+	var q strings.Builder
+	fmt.Fprintf(&q, "SELECT %s FROM %s", strings.Join(cols, ", "), b.Table)
+	for i, c := range b.Conds {
+		if i == 0 {
+			q.WriteString(" WHERE ")
+		} else {
+			q.WriteString(" AND ")
+		}
+		q.WriteString(c.SQL(dt))
+	}
+	if b.SortCol >= 0 && b.SortCol < len(b.Columns) {
+		dir := "ASC"
+		if b.SortDesc {
+			dir = "DESC"
+		}
+		fmt.Fprintf(&q, " ORDER BY %s %s", quoteIdent(dt, b.Columns[b.SortCol].Name), dir)
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(b.Limit.Value())); err == nil && n > 0 {
+		fmt.Fprintf(&q, " LIMIT %d", n)
+	}
+	return q.String()
+}
+
+// tables returns the tables whose name contains the search text
+func (b *queryBuilder) tables(all []string) []string {
+	search := strings.ToLower(strings.TrimSpace(b.Search.Value()))
+	var tables []string
+	for _, t := range all {
+		if strings.Contains(strings.ToLower(t), search) {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+// focus gives the keyboard to the input of the current step and field
+func (b *queryBuilder) focus() tea.Cmd {
+	b.Search.Blur()
+	b.Value.Blur()
+	b.Limit.Blur()
+	switch {
+	case b.Step == builderTable:
+		return b.Search.Focus()
+	case b.Step == builderFilters && b.Cursor == 2:
+		return b.Value.Focus()
+	case b.Step == builderSort && b.Cursor == 2:
+		return b.Limit.Focus()
+	}
+	return nil
+}
+
+// openQueryBuilder opens the query builder on the table list
+func (m *Model) openQueryBuilder() tea.Cmd {
+	if m.showQueryBuilder {
+		return nil
+	}
+	b := &queryBuilder{SortCol: -1, Search: textinput.New(), Value: textinput.New(), Limit: textinput.New()}
+	b.Search.Prompt = "/ "
+	b.Search.Placeholder = "Filter tables..."
+	b.Search.Width = 40
+	b.Value.Prompt = ""
+	b.Value.Placeholder = "value"
+	b.Value.Width = 30
+	b.Limit.Prompt = ""
+	b.Limit.Placeholder = "all rows"
+	b.Limit.CharLimit = 9
+	b.Limit.SetValue("100")
+	m.builder = b
+	m.showQueryBuilder = true
+	m.autocompleting = false
+	m.popupStack.Push("query builder", func(m *Model) {
+		m.showQueryBuilder = false
+		m.builder = nil
+	})
+	return b.focus()
+}
+
+// handleQueryBuilderKeys handles keys of the query builder. Tab and
+// Shift+Tab move between the steps once a table is picked; Ctrl+E writes
+// the query into the editor and Enter on the last step also runs it.
+func (m Model) handleQueryBuilderKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	b := m.builder
+	switch msg.String() {
+	case "esc":
+		m.closeTopPopup()
+		return m, nil, true
+	case "tab", "shift+tab":
+		if b.Table == "" {
+			return m, nil, true
+		}
+		step := b.Step + 1
+		if msg.String() == "shift+tab" {
+			step = b.Step - 1
+		}
+		b.Step, b.Cursor = min(max(step, builderTable), builderSort), 0
+		return m, b.focus(), true
+	case "ctrl+e":
+		if b.Table != "" {
+			model, cmd := m.finishQueryBuilder(false)
+			return model, cmd, true
+		}
+		return m, nil, true
+	}
+
+	switch b.Step {
+	case builderTable:
+		return m.handleBuilderTableKeys(msg)
+	case builderColumns:
+		return m.handleBuilderColumnKeys(msg)
+	case builderFilters:
+		return m.handleBuilderFilterKeys(msg)
+	}
+	return m.handleBuilderSortKeys(msg)
+}
+
+// handleBuilderTableKeys narrows the table list as the search is typed and
+// picks the highlighted table with Enter
+func (m Model) handleBuilderTableKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	b := m.builder
+	tables := b.tables(m.tables)
+	switch msg.String() {
+	case "up":
+		b.Cursor = max(b.Cursor-1, 0)
+		return m, nil, true
+	case "down":
+		b.Cursor = max(min(b.Cursor+1, len(tables)-1), 0)
+		return m, nil, true
+	case "enter":
+		if b.Cursor >= len(tables) {
+			return m, nil, true
+		}
+		name := tables[b.Cursor]
+		columns := m.columns[name]
+		if len(columns) == 0 {
+			m.errorMsg = fmt.Sprintf("No columns loaded for %s", name)
+			return m, nil, true
+		}
+		if name != b.Table {
+			b.Table, b.Columns, b.Picked = name, columns, make([]bool, len(columns))
+			b.Conds, b.CondCol, b.SortCol = nil, 0, -1
+		}
+		b.Step, b.Cursor = builderColumns, 0
+		return m, b.focus(), true
+	}
+	var cmd tea.Cmd
+	b.Search, cmd = b.Search.Update(msg)
+	b.Cursor = min(b.Cursor, max(len(b.tables(m.tables))-1, 0))
+	return m, cmd, true
+}
+
+// handleBuilderColumnKeys checks and unchecks the columns to select
+func (m Model) handleBuilderColumnKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	b := m.builder
+	switch msg.String() {
+	case "up", "k":
+		b.Cursor = max(b.Cursor-1, 0)
+	case "down", "j":
+		b.Cursor = min(b.Cursor+1, len(b.Columns)-1)
+	case " ", "space", "x":
+		b.Picked[b.Cursor] = !b.Picked[b.Cursor]
+	case "a":
+		all := true
+		for _, p := range b.Picked {
+			all = all && p
+		}
+		for i := range b.Picked {
+			b.Picked[i] = !all
+		}
+	case "enter":
+		b.Step, b.Cursor = builderFilters, 0
+		return m, b.focus(), true
+	}
+	return m, nil, true
+}
+
+// handleBuilderFilterKeys edits the condition being added: its column and
+// comparison with left and right, its value by typing. Enter adds it, or
+// moves on to sorting when there is nothing to add; Backspace on an empty
+// value removes the last condition.
+func (m Model) handleBuilderFilterKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	b := m.builder
+	switch msg.String() {
+	case "up":
+		b.Cursor = max(b.Cursor-1, 0)
+		return m, b.focus(), true
+	case "down":
+		b.Cursor = min(b.Cursor+1, 2)
+		return m, b.focus(), true
+	case "left", "right", "h", "l":
+		if b.Cursor == 2 {
+			break
+		}
+		step := 1
+		if msg.String() == "left" || msg.String() == "h" {
+			step = -1
+		}
+		if b.Cursor == 0 {
+			b.CondCol = (b.CondCol + step + len(b.Columns)) % len(b.Columns)
+		} else {
+			b.CondOp = (b.CondOp + step + len(builderOps)) % len(builderOps)
+		}
+		return m, nil, true
+	case "enter":
+		value := b.Value.Value()
+		if needsValue(b.CondOp) && strings.TrimSpace(value) == "" {
+			b.Step, b.Cursor = builderSort, 0
+			return m, b.focus(), true
+		}
+		if !needsValue(b.CondOp) {
+			value = ""
+		}
+		b.Conds = append(b.Conds, builderCond{Column: b.Columns[b.CondCol], Op: b.CondOp, Value: value})
+		b.Value.SetValue("")
+		return m, nil, true
+	case "backspace":
+		if b.Value.Value() == "" && len(b.Conds) > 0 {
+			b.Conds = b.Conds[:len(b.Conds)-1]
+			return m, nil, true
+		}
+	}
+	if b.Cursor != 2 {
+		return m, nil, true
+	}
+	var cmd tea.Cmd
+	b.Value, cmd = b.Value.Update(msg)
+	return m, cmd, true
+}
+
+// handleBuilderSortKeys picks the sort column and direction with left and
+// right and takes the row limit as typed; Enter runs the query
+func (m Model) handleBuilderSortKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	b := m.builder
+	switch msg.String() {
+	case "up":
+		b.Cursor = max(b.Cursor-1, 0)
+		return m, b.focus(), true
+	case "down":
+		b.Cursor = min(b.Cursor+1, 2)
+		return m, b.focus(), true
+	case "enter":
+		model, cmd := m.finishQueryBuilder(true)
+		return model, cmd, true
+	}
+	switch b.Cursor {
+	case 0:
+		switch msg.String() {
+		case "left", "h":
+			b.SortCol = max(b.SortCol-1, -1)
+		case "right", "l":
+			b.SortCol = min(b.SortCol+1, len(b.Columns)-1)
+		}
+	case 1:
+		switch msg.String() {
+		case "left", "right", "h", "l", " ", "space":
+			b.SortDesc = !b.SortDesc
+		}
+	default:
+		if msg.Type == tea.KeyRunes && strings.Trim(string(msg.Runes), "0123456789") != "" {
+			return m, nil, true
+		}
+		var cmd tea.Cmd
+		b.Limit, cmd = b.Limit.Update(msg)
+		return m, cmd, true
+	}
+	return m, nil, true
+}
+
+// finishQueryBuilder writes the built query into the editor and runs it,
+// or with run false leaves it there to be edited
+func (m Model) finishQueryBuilder(run bool) (Model, tea.Cmd) {
+	if m.driver == nil {
+		m.errorMsg = "Not connected"
+		return m, nil
+	}
+	query := m.builder.Query(m.driver.Type())
+	m.closeTopPopup()
+	m.setEditorValue(query)
+	if !run {
+		m.mode = InsertMode
+		m.editor.Focus()
+		return m, textinput.Blink
+	}
+	return m, m.confirmOrRun(query)
+}
+
+// renderQueryBuilder draws the query builder over main
+func (m Model) renderQueryBuilder(main string) string {
+	b := m.builder
+	popupWidth := min(72, m.width-10)
+	inner := m.popupInnerWidth(popupWidth)
+	faint := lipgloss.NewStyle().Faint(true)
+	selected := lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Bold(true)
+	normal := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
+
+	var content strings.Builder
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Query Builder"))
+	content.WriteString("  ")
+	for i, name := range builderSteps {
+		if i > 0 {
+			content.WriteString(faint.Render(" › "))
+		}
+		if i == b.Step {
+			content.WriteString(selected.Render(name))
+		} else {
+			content.WriteString(faint.Render(name))
+		}
+	}
+	content.WriteString("\n\n")
+
+	// field renders a form field, marked while it has the focus
+	field := func(i int, label, value string) string {
+		if i == b.Cursor {
+			return "> " + selected.Render(label) + value + "\n"
+		}
+		return "  " + normal.Render(label) + value + "\n"
+	}
+	// window returns the slice of n list rows around cursor that fits
+	window := func(n int) (int, int) {
+		start := max(min(b.Cursor-builderListRows/2, n-builderListRows), 0)
+		return start, min(start+builderListRows, n)
+	}
+
+	var hint string
+	switch b.Step {
+	case builderTable:
+		content.WriteString(b.Search.View() + "\n\n")
+		tables := b.tables(m.tables)
+		start, end := window(len(tables))
+		for i := start; i < end; i++ {
+			if i == b.Cursor {
+				content.WriteString("> " + selected.Render(truncateEnd(tables[i], inner-2)) + "\n")
+			} else {
+				content.WriteString("  " + normal.Render(truncateEnd(tables[i], inner-2)) + "\n")
+			}
+		}
+		if len(tables) == 0 {
+			content.WriteString(faint.Render("  No tables match") + "\n")
+		}
+		hint = "Enter: pick • ↑/↓: select • type to filter • Esc: cancel"
+	case builderColumns:
+		start, end := window(len(b.Columns))
+		for i := start; i < end; i++ {
+			c := b.Columns[i]
+			check := "[ ] "
+			if b.Picked[i] {
+				check = "[x] "
+			}
+			style := normal
+			prefix := "  "
+			if i == b.Cursor {
+				style, prefix = selected, "> "
+			}
+			content.WriteString(prefix + check + style.Render(truncateEnd(c.Name, inner-24)) + faint.Render("  "+truncateEnd(c.Type, 16)) + "\n")
+		}
+		content.WriteString(faint.Render("\nNo columns checked selects all of them") + "\n")
+		hint = "Space: check • a: all • Enter: next • Shift+Tab: back • Esc: cancel"
+	case builderFilters:
+		for _, c := range b.Conds {
+			content.WriteString("  • " + truncateEnd(c.String(), inner-4) + "\n")
+		}
+		if len(b.Conds) == 0 {
+			content.WriteString(faint.Render("  No filters, every row is kept") + "\n")
+		}
+		content.WriteString("\n")
+		content.WriteString(field(0, "Column:   ", "‹ "+b.Columns[b.CondCol].Name+" ›"))
+		content.WriteString(field(1, "Compare:  ", "‹ "+builderOps[b.CondOp].Label+" ›"))
+		if needsValue(b.CondOp) {
+			content.WriteString(field(2, "Value:    ", b.Value.View()))
+		} else {
+			content.WriteString(field(2, "Value:    ", faint.Render("not needed")))
+		}
+		hint = "Enter: add • ⌫: remove last • ←/→: change • Tab: sort • Esc: cancel"
+	case builderSort:
+		sortCol, dir := "table order", "ascending"
+		if b.SortCol >= 0 {
+			sortCol = b.Columns[b.SortCol].Name
+		}
+		if b.SortDesc {
+			dir = "descending"
+		}
+		content.WriteString(field(0, "Sort by:  ", "‹ "+sortCol+" ›"))
+		content.WriteString(field(1, "Order:    ", "‹ "+dir+" ›"))
+		content.WriteString(field(2, "Limit:    ", b.Limit.View()))
+		hint = "Enter: run • Ctrl+E: edit in editor • ←/→: change • Shift+Tab: back • Esc: cancel"
+	}
+
+	if b.Table != "" && m.driver != nil {
+		query := lipgloss.NewStyle().Width(inner).Render(highlight.SQL(b.Query(m.driver.Type())))
+		content.WriteString("\n" + faint.Render("SQL") + "\n" + query + "\n")
+	}
+	content.WriteString("\n")
+	content.WriteString(m.renderHintLine(hint, inner))
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
@@ -0,0 +1,171 @@
+// internal/ui/quick_filter.go
+// Quick filters: row actions that add "column = value" or "column <> value" for a cell to the query's WHERE clause and re-run it.
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// sqlWord is a keyword or identifier of a query and its byte offset
+type sqlWord struct {
+	word string // Upper-cased
+	pos  int
+}
+
+// selectStar matches a query selecting every column
+var selectStar = regexp.MustCompile(`(?is)^\s*SELECT\s+\*\s+FROM\b`)
+
+// topLevelWords returns the words of query outside parentheses, literals,
+// quoted identifiers and comments
+func topLevelWords(query string) []sqlWord {
+	var words []sqlWord
+	depth := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case strings.HasPrefix(query[i:], "--"):
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return words
+			}
+			i += end + 3
+		case c == '\'' || c == '"' || c == '`':
+			for i++; i < len(query); i++ {
+				if query[i] == c {
+					if i+1 < len(query) && query[i+1] == c {
+						i++
+						continue
+					}
+					break
+				}
+			}
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == '_' || (c|0x20 >= 'a' && c|0x20 <= 'z'):
+			start := i
+			for i+1 < len(query) && (query[i+1] == '_' || query[i+1] == '.' || (query[i+1]|0x20 >= 'a' && query[i+1]|0x20 <= 'z') || (query[i+1] >= '0' && query[i+1] <= '9')) {
+				i++
+			}
+			if depth == 0 {
+				words = append(words, sqlWord{word: strings.ToUpper(query[start : i+1]), pos: start})
+			}
+		}
+	}
+	return words
+}
+
+// appendPredicate adds pred to the WHERE clause of a single SELECT, or adds
+// a WHERE clause in front of its GROUP BY, ORDER BY or LIMIT. It returns
+// false for any other statement.
+func appendPredicate(query, pred string) (string, bool) {
+	query = trimTrailingComments(query)
+	words := topLevelWords(query)
+	if len(words) == 0 || words[0].word != "SELECT" {
+		return "", false
+	}
+	from, where, end := -1, -1, len(query)
+	hasOr := false
+	for i, w := range words {
+		switch w.word {
+		case "UNION", "INTERSECT", "EXCEPT":
+			return "", false
+		case "FROM":
+			if from < 0 {
+				from = i
+			}
+		case "WHERE":
+			if from >= 0 && where < 0 {
+				where = i
+			}
+		case "OR":
+			hasOr = hasOr || (where >= 0 && end == len(query))
+		case "GROUP", "HAVING", "WINDOW", "ORDER", "LIMIT", "OFFSET", "FETCH", "FOR":
+			if from >= 0 && end == len(query) {
+				end = w.pos
+			}
+		}
+	}
+	if from < 0 {
+		return "", false
+	}
+
+	head, tail := trimTrailingComments(query[:end]), query[end:]
+	if tail != "" {
+		tail = " " + tail
+	}
+	if where < 0 {
+		return head + " WHERE " + pred + tail, true
+	}
+	cond := strings.TrimSpace(head[words[where].pos+len("WHERE"):])
+	if hasOr {
+		cond = "(" + cond + ")"
+	}
+	return query[:words[where].pos] + "WHERE " + cond + " AND " + pred + tail, true
+}
+
+// quickFilterPredicate compares col with a result value; NULL values
+// become IS NULL tests
+func quickFilterPredicate(dt db.DriverType, col db.Column, value string, exclude bool) string {
+	ident := quoteIdent(dt, col.Name)
+	switch {
+	case value == "NULL" && exclude:
+		return ident + " IS NOT NULL"
+	case value == "NULL":
+		return ident + " IS NULL"
+	case exclude:
+		return ident + " <> " + quoteLiteral(dt, value, col.Type)
+	}
+	return ident + " = " + quoteLiteral(dt, value, col.Type)
+}
+
+// quickFilter re-runs the results query keeping only the rows whose picked
+// column equals, or with exclude differs from, the highlighted row's value.
+// Columns the query's table does not have, such as aliases, are filtered
+// around the query instead.
+func (m Model) quickFilter(exclude bool) (Model, tea.Cmd) {
+	row := m.popupTable.HighlightedRow().Data
+	if row == nil || m.popupEntry == nil || m.popupResult == nil || m.driver == nil ||
+		m.rowActionColumn >= len(m.popupResult.Columns) {
+		return m, nil
+	}
+	dt := m.driver.Type()
+	name := m.popupResult.Columns[m.rowActionColumn]
+	col := db.Column{Name: name}
+	query := strings.TrimSpace(trimTrailingComments(m.popupEntry.Query))
+
+	inTable := false
+	if table := entryTable(m.popupEntry); table != "" {
+		if _, schema, ok := m.tableColumns(table); ok {
+			if c, found := findColumn(schema, name); found {
+				col, inTable = c, true
+			}
+		}
+	}
+	pred := quickFilterPredicate(dt, col, cellText(row[name]), exclude)
+	filtered, ok := "", false
+	if inTable || selectStar.MatchString(query) {
+		filtered, ok = appendPredicate(query, pred)
+	}
+	if !ok {
+		if dt == db.Cassandra {
+			m.errorMsg = "Cannot add a filter to this query"
+			return m, nil
+		}
+		filtered = fmt.Sprintf("SELECT * FROM (%s) AS ezdb_filtered WHERE %s", query, pred)
+	}
+	// The filtered result opens in a fresh results popup
+	m.closePopupsThrough("results")
+	return m, m.confirmOrRun(filtered)
+}
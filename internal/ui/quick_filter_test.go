@@ -0,0 +1,29 @@
+// internal/ui/quick_filter_test.go
+package ui
+
+import "testing"
+
+func TestAppendPredicate(t *testing.T) {
+	tests := []struct {
+		query, want string
+		ok          bool
+	}{
+		{"SELECT * FROM items;", "SELECT * FROM items WHERE x = 1", true},
+		{"SELECT * FROM items ORDER BY id LIMIT 10", "SELECT * FROM items WHERE x = 1 ORDER BY id LIMIT 10", true},
+		{"SELECT * FROM items WHERE a = 'order' LIMIT 5", "SELECT * FROM items WHERE a = 'order' AND x = 1 LIMIT 5", true},
+		{"SELECT * FROM items WHERE a = 1 OR b = 2", "SELECT * FROM items WHERE (a = 1 OR b = 2) AND x = 1", true},
+		{"SELECT name, count(*) FROM items WHERE id IN (SELECT id FROM t WHERE y OR z) GROUP BY name",
+			"SELECT name, count(*) FROM items WHERE id IN (SELECT id FROM t WHERE y OR z) AND x = 1 GROUP BY name", true},
+		{"SELECT * FROM items WHERE a = 1 -- x", "SELECT * FROM items WHERE a = 1 AND x = 1", true},
+		{"SELECT * FROM items /* all */; -- done", "SELECT * FROM items WHERE x = 1", true},
+		{"SELECT * FROM items WHERE a = '--' -- x\nORDER BY id", "SELECT * FROM items WHERE a = '--' AND x = 1 ORDER BY id", true},
+		{"SELECT 1 UNION SELECT 2", "", false},
+		{"SHOW TABLES", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := appendPredicate(tt.query, "x = 1")
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("appendPredicate(%q) = %q, %v; want %q, %v", tt.query, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// internal/ui/quit.go
+// Quit confirmation: asks before quitting over a running query, import or export, or an open transaction.
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+)
+
+// quitBlocker returns what quitting now would interrupt, or "" when nothing
+func (m Model) quitBlocker() string {
+	switch {
+	case m.transfer != nil:
+		return m.transfer.Op + " in progress"
+	case m.loading:
+		return "A query is running"
+	case m.inTransaction:
+		return "A transaction is open"
+	}
+	return ""
+}
+
+// requestQuit quits, or asks first when work would be interrupted
+func (m Model) requestQuit() (Model, tea.Cmd) {
+	if m.quitBlocker() != "" {
+		m.quitConfirm = true
+		return m, nil
+	}
+	return m.quit()
+}
+
+// quit saves what should outlive the program and exits
+func (m Model) quit() (Model, tea.Cmd) {
+	if m.cancelQuery != nil {
+		m.cancelQuery()
+		m.cancelQuery = nil
+	}
+	m.flushEdits()
+	m.saveSession()
+	return m, tea.Quit
+}
+
+// quitController gives the quit prompt every key until it is answered
+func (m Model) quitController(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if !m.quitConfirm {
+		return m, nil, false
+	}
+	m, cmd := m.handleQuitConfirm(msg)
+	return m, cmd, true
+}
+
+// handleQuitConfirm answers the quit prompt: y quits anyway, c cancels the
+// running work, r rolls back the open transaction, and n or esc stays
+func (m Model) handleQuitConfirm(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		m.quitConfirm = false
+		return m.quit()
+	case "c":
+		if m.loading && m.cancelQuery != nil {
+			m.cancelQuery()
+			m.cancelQuery = nil
+			m.quitConfirm = false
+		}
+	case "r":
+		if m.inTransaction && !m.loading {
+			m.quitConfirm = false
+			return m, m.runQuery("ROLLBACK")
+		}
+	case "n", "N", "esc":
+		m.quitConfirm = false
+	}
+	return m, nil
+}
+
+// renderQuitConfirm renders the quit prompt over main
+func (m Model) renderQuitConfirm(main string) string {
+	var content strings.Builder
+	content.WriteString(m.theme.WarningStyle.Render(" QUIT EZDB? ") + "\n\n")
+	if reason := m.quitBlocker(); reason != "" {
+		content.WriteString(reason + "; quitting now abandons it.\n\n")
+	}
+
+	yes := lipgloss.NewStyle().Bold(true).Foreground(m.theme.ErrorColor())
+	other := lipgloss.NewStyle().Bold(true).Foreground(m.theme.SuccessColor())
+	options := []string{yes.Render("(y) Quit anyway")}
+	switch {
+	case m.loading && m.cancelQuery != nil:
+		options = append(options, other.Render("(c) Cancel it"))
+	case m.inTransaction && !m.loading:
+		options = append(options, other.Render("(r) Roll back"))
+	}
+	options = append(options, other.Render("(n/Esc) Stay"))
+	content.WriteString(strings.Join(options, "  "))
+
+	box := m.theme.PopupStyle.
+		Width(min(60, m.width-4)).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+	return overlay.Composite(box, main, overlay.Center, overlay.Center, 0, 0)
+}
@@ -4,65 +4,177 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/nhath/ezdb/internal/ui/styles"
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/db"
 )
 
+// keyHint is one entry of the bottom help line, naming registered actions.
+// The first key of each is shown joined by "/", labelled with the first
+// action's hint unless label overrides it; when decides if the hint is shown
+// and priority when it drops out of a narrow line (see fitHints).
+type keyHint struct {
+	actions  []string
+	label    string
+	when     func(m Model) bool
+	priority int
+}
+
+// firstKey returns the first binding or fallback if unbound
+func firstKey(bindings []string, fallback string) string {
+	if len(bindings) > 0 {
+		return bindings[0]
+	}
+	return fallback
+}
+
+// Hint conditions
+func hintAlways(Model) bool    { return true }
+func hintLoading(m Model) bool { return m.loading && m.cancelQuery != nil }
+func hintIdle(m Model) bool    { return !hintLoading(m) }
+func hintInTx(m Model) bool    { return m.inTransaction }
+func hintResults(m Model) bool { return m.showPopup }
+func hintTable(m Model) bool {
+	return m.schemaFocused() && m.schemaBrowser.CurrentTable() != ""
+}
+func hintInsert(m Model) bool {
+	return m.mode == InsertMode && !m.showPopup && !m.schemaFocused()
+}
+func hintVisual(m Model) bool {
+	return m.mode != InsertMode && !m.showPopup && !m.schemaFocused()
+}
+func hintAttach(m Model) bool {
+	_, ok := m.driver.(db.Attacher)
+	return ok && hintVisual(m)
+}
+func hintSchemaSwitch(m Model) bool {
+	_, ok := m.driver.(db.SchemaSwitcher)
+	return ok && hintVisual(m)
+}
+func hintActivity(m Model) bool {
+	_, ok := m.driver.(db.ActivityMonitor)
+	return ok && hintVisual(m)
+}
+func hintAssistant(m Model) bool {
+	return m.config.AssistantEnabled(m.profile) && (hintVisual(m) || hintInsert(m))
+}
+func hintFailedEntry(m Model) bool {
+	return hintVisual(m) && m.selected >= 0 && m.selected < len(m.history) && m.history[m.selected].Status == "error"
+}
+func hintDashboard(m Model) bool {
+	_, ok := m.driver.(db.MetricsReporter)
+	return ok && hintVisual(m)
+}
+func hintDock(m Model) bool        { return m.dockShown() && !m.showPopup }
+func hintVisualIdle(m Model) bool  { return hintVisual(m) && hintIdle(m) }
+func hintInsertIdle(m Model) bool  { return hintInsert(m) && hintIdle(m) }
+func hintResultsIdle(m Model) bool { return hintResults(m) && hintIdle(m) }
+
+// keyHints declares every hint of the bottom help line in display order
+var keyHints = []keyHint{
+	// Running query
+	{[]string{"Quit"}, "Cancel", hintLoading, 0},
+
+	// Insert mode
+	{[]string{"Execute"}, "", hintInsertIdle, 0},
+	{[]string{"Explain"}, "", hintInsertIdle, 2},
+	{[]string{"Exit"}, "", hintInsert, 0},
+	{[]string{"Autocomplete"}, "", hintInsert, 2},
+
+	// Visual mode
+	{[]string{"InsertMode"}, "", hintVisual, 0},
+	{[]string{"MoveUp", "MoveDown"}, "", hintVisual, 1},
+	{[]string{"ToggleExpand"}, "", hintVisual, 1},
+	{[]string{"Rerun"}, "", hintVisualIdle, 1},
+	{[]string{"Edit"}, "", hintVisual, 1},
+	{[]string{"ToggleSchema"}, "", hintVisual, 1},
+	{[]string{"ToggleTheme"}, "", hintVisual, 3},
+	{[]string{"Attach"}, "", hintAttach, 3},
+	{[]string{"SwitchSchema"}, "", hintSchemaSwitch, 3},
+	{[]string{"Activity"}, "", hintActivity, 3},
+	{[]string{"Dashboard"}, "", hintDashboard, 3},
+	{[]string{"Snapshots"}, "", hintVisual, 3},
+	{[]string{"Analytics"}, "", hintVisual, 3},
+	{[]string{"Transcript"}, "", hintVisual, 3},
+	{[]string{"RunOn"}, "", hintVisual, 3},
+	{[]string{"QueryBuilder"}, "", hintVisual, 3},
+	{[]string{"Assistant"}, "", hintAssistant, 3},
+	{[]string{"ExplainError"}, "", hintFailedEntry, 2},
+
+	// Docked result
+	{[]string{"ExpandDock"}, "", hintDock, 2},
+
+	// Active result set
+	{[]string{"RowAction"}, "", hintResults, 1},
+	{[]string{"Filter"}, "", hintResults, 1},
+	{[]string{"Export"}, "", hintResultsIdle, 2},
+	{[]string{"OpenPager"}, "", hintResultsIdle, 2},
+	{[]string{"FormatNumbers"}, "", hintResultsIdle, 3},
+	{[]string{"FullWidth"}, "", hintResultsIdle, 3},
+	{[]string{"ColumnActions"}, "", hintResultsIdle, 3},
+
+	// Active table in the schema browser
+	{[]string{"SchemaExport"}, "", hintTable, 1},
+	{[]string{"SchemaImport"}, "", hintTable, 2},
+	{[]string{"SchemaGenerate"}, "", hintTable, 3},
+	{[]string{"SchemaCopy"}, "", hintTable, 3},
+	{[]string{"SchemaTruncate"}, "", hintTable, 3},
+	{[]string{"SchemaDrop"}, "", hintTable, 3},
+
+	// Open transaction
+	{[]string{"Commit"}, "", hintInTx, 0},
+	{[]string{"Rollback"}, "", hintInTx, 0},
+
+	// Always available
+	{[]string{"Help"}, "", hintAlways, 0},
+	{[]string{"Quit"}, "", hintIdle, 0},
+}
+
+// render returns the key and label of the hint, or ok false when an action
+// has no key bound
+func (h keyHint) render(keys *config.KeyMap) (key, label string, ok bool) {
+	var parts []string
+	for i, name := range h.actions {
+		a, found := actionNamed(name)
+		bound := a.keys(keys)
+		if !found || len(bound) == 0 {
+			return "", "", false
+		}
+		if i == 0 {
+			label = a.Hint
+		}
+		parts = append(parts, bound[0])
+	}
+	if h.label != "" {
+		label = h.label
+	}
+	return strings.Join(parts, "/"), label, true
+}
+
 func (m Model) renderHelp() string {
 	// Style for key hints - makes keys look like keyboard buttons
 	keyStyle := lipgloss.NewStyle().
-		Foreground(styles.TextPrimary()).
-		Background(styles.CardBg()).
+		Foreground(m.theme.TextPrimary()).
+		Background(m.theme.CardBg()).
 		Padding(0, 1).
 		Bold(true)
 
-	sepStyle := lipgloss.NewStyle().Foreground(styles.TextFaint())
-	descStyle := lipgloss.NewStyle().Foreground(styles.TextSecondary())
-
-	// Helper to format a single hint
-	hint := func(key, desc string) string {
-		return keyStyle.Render(key) + descStyle.Render(" "+desc)
-	}
-
-	// Helper to get first key or fallback
-	key := func(bindings []string, fallback string) string {
-		if len(bindings) > 0 {
-			return bindings[0]
-		}
-		return fallback
-	}
+	sepStyle := lipgloss.NewStyle().Foreground(m.theme.TextFaint())
+	descStyle := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
 
 	sep := sepStyle.Render("  ")
 
-	keys := m.config.Keys
-
 	// Context-aware hints based on current state
-	var hints []string
-
-	if m.mode == InsertMode {
-		hints = append(hints,
-			hint(key(keys.Execute, "ctrl+d"), "Run"),
-			hint(key(keys.Explain, "X"), "Explain"),
-			hint(key(keys.Exit, "esc"), "Visual"),
-			hint(key(keys.Autocomplete, "ctrl+space"), "Complete"),
-		)
-	} else {
-		// Visual mode
-		hints = append(hints,
-			hint(key(keys.InsertMode, "i"), "Insert"),
-			hint(key(keys.MoveUp, "k")+"/"+key(keys.MoveDown, "j"), "Nav"),
-			hint(key(keys.ToggleExpand, "enter"), "Expand"),
-			hint(key(keys.Rerun, "r"), "Rerun"),
-			hint(key(keys.Edit, "e"), "Edit"),
-			hint(key(keys.ToggleSchema, "tab"), "Schema"),
-			hint(key(keys.ToggleTheme, "t"), "Theme"),
-		)
+	var hints []hint
+	for _, h := range keyHints {
+		if !h.when(m) {
+			continue
+		}
+		if key, label, ok := h.render(&m.config.Keys); ok {
+			hints = append(hints, hint{Key: key, Label: label, Priority: h.priority})
+		}
 	}
 
-	// Always show help and quit
-	hints = append(hints,
-		hint(key(keys.Help, "?"), "Help"),
-		hint(key(keys.Quit, "ctrl+c"), "Quit"),
-	)
-
-	return strings.Join(hints, sep)
+	return fitHints(hints, m.width, sep, func(h hint) string {
+		return keyStyle.Render(h.Key) + descStyle.Render(" "+h.Label)
+	})
 }
@@ -1,16 +1,25 @@
 package ui
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/nhath/ezdb/internal/ui/highlight"
-	"github.com/nhath/ezdb/internal/ui/icons"
-	"github.com/nhath/ezdb/internal/ui/styles"
+	"github.com/nhath/ezdb/internal/ui/components/historylist"
 )
 
+// updateHistoryViewport sizes the editor and history viewport for the main pane,
+// which is narrower than the screen when the sidebar is docked
 func (m Model) updateHistoryViewport() Model {
+	fullWidth := m.width
+	m.width = m.paneWidth()
+	m.editor.SetWidth(m.width - 4)
+	m = m.layoutHistoryPane()
+	m.width = fullWidth
+	return m
+}
+
+// layoutHistoryPane sizes the history viewport for a pane m.width wide
+func (m Model) layoutHistoryPane() Model {
 	// Calculate dynamic editor height
 	editorContent := m.editor.Value()
 	lineCount := strings.Count(editorContent, "\n") + 1
@@ -34,7 +43,7 @@ func (m Model) updateHistoryViewport() Model {
 	helpText := m.renderHelp()
 	// Input area
 	inputWidth := m.width - 4
-	inputView := styles.InputStyle.Width(inputWidth).Render(m.highlightView(m.editor.View()))
+	inputView := m.inputStyle().Width(inputWidth).Render(m.highlightView(m.editor.View()))
 	// Suggestions (only in insert mode)
 	chromeHeight := lipgloss.Height(statusBar) + lipgloss.Height(helpText)
 	availableHeight := m.height - chromeHeight
@@ -42,224 +51,50 @@ func (m Model) updateHistoryViewport() Model {
 		availableHeight = 0
 	}
 
-	historyHeight := availableHeight - lipgloss.Height(inputView)
+	historyHeight := availableHeight - lipgloss.Height(inputView) - m.dockHeight()
 	if historyHeight < 0 {
 		historyHeight = 0
 	}
 
-	m.viewport.Width = m.width
-	m.viewport.Height = historyHeight
-	m.viewport.SetContent(m.renderHistoryContent(historyHeight))
-	return m
+	m.historyList = m.historyList.SetSize(m.width, historyHeight)
+	return m.refreshHistoryList()
 }
 
-// renderHistoryContent generates the string for the viewport
-func (m Model) renderHistoryContent(minHeight int) string {
-	if len(m.history) == 0 {
-		return ""
-	}
-
-	var sections []string
-	for i := range m.history {
-		sections = append(sections, strings.TrimRight(m.renderHistoryItem(i), "\n"))
-	}
-	// Join with newline separator for margin between cards
-	content := strings.Join(sections, "\n\n")
-
-	// Add a bit more padding at the top of the entire list for the first item
-	content = lipgloss.NewStyle().MarginTop(1).Render(content)
-
-	h := lipgloss.Height(content)
-	if h < minHeight && h > 0 {
-		return strings.Repeat("\n", minHeight-h) + content
-	}
-
-	return content
-}
-
-// renderHistoryItem renders a single history entry
-func (m Model) renderHistoryItem(i int) string {
-	if i < 0 || i >= len(m.history) {
-		return ""
-	}
-	entry := m.history[i]
-	isSelected := m.mode == VisualMode && i == m.selected
-	isExpanded := m.expandedID == entry.ID
-
-	// No wrapper style needed - header handles its own styling
-	_ = isSelected // Used below for header styling
-
-	// Content construction
-	var content strings.Builder
-
-	// Build header section (query + metadata) with subtle background
-	var headerContent strings.Builder
-
-	// Query Line with syntax highlighting
-	if entry.Status != "info" {
-		indicator := " " + icons.IconCollapsed + " "
-		if isExpanded {
-			indicator = " " + icons.IconExpanded + " "
-		}
-		headerContent.WriteString(indicator)
-	}
-
-	queryText := entry.QueryPreview(m.width - 14) // Adjusted for margins
-	if isExpanded {
-		queryText = entry.Query
-	}
-
-	// SQL syntax highlighting (background stripped, foreground only)
-	if entry.Status == "info" {
-		headerContent.WriteString(queryText)
-	} else {
-		headerContent.WriteString(highlight.SQL(queryText))
-	}
-
-	// [EXPANDED] indicator
-	if isExpanded {
-		headerContent.WriteString(" [EXPANDED]")
-	}
-	headerContent.WriteString("\n")
-
-	// Meta Line - plain text for consistent background
-	statusIcon := icons.IconSuccess
-	if entry.Status == "error" {
-		statusIcon = icons.IconError
-	} else if entry.Status == "info" {
-		statusIcon = icons.IconInfo
-	}
-
-	var metaInfo string
-	if entry.Status == "info" {
-		metaInfo = fmt.Sprintf("  %s %s", statusIcon, entry.ExecutedAt.Format("15:04:05"))
+// refreshHistoryList hands the history, selection and expanded entry to the
+// history list and lays it out again
+func (m Model) refreshHistoryList() Model {
+	m.historyList = m.historyList.
+		SetStyles(historylist.Styles{
+			Header:   lipgloss.NewStyle().Background(m.theme.CardBg()).Foreground(m.theme.TextPrimary()),
+			Accent:   m.connectionAccent(),
+			Error:    m.theme.ErrorStyle,
+			ErrorDim: m.theme.ErrorGrayStyle,
+			Preview:  lipgloss.NewStyle().Foreground(m.theme.TextFaint()),
+		}).
+		SetItems(ConvertToItems(m.history, m.formatTimestamp)).
+		SetSelected(m.selected).
+		SetFocused(m.mode == VisualMode)
+	if m.expandedID != 0 {
+		m.historyList = m.historyList.SetExpanded(m.expandedID, m.expandedTable.View())
 	} else {
-		metaInfo = fmt.Sprintf("  %s %dms | %d rows | %s", statusIcon, entry.DurationMs, entry.RowCount, entry.ExecutedAt.Format("15:04:05"))
+		m.historyList = m.historyList.SetExpanded(0, "")
 	}
-	headerContent.WriteString(metaInfo)
-
-	// Apply full-width background to entire header section
-	// Using cardBg for better contrast
-	headerBg := styles.CardBg()
-
-	headerStyle := lipgloss.NewStyle().
-		Background(headerBg).
-		Foreground(styles.TextPrimary()). // Nord4 text
-		Width(m.width).                   // Full viewport width
-		Padding(1, 1)
-
-	// Add left accent border for selected items
-	if isSelected {
-		headerStyle = headerStyle.
-			BorderLeft(true).
-			BorderStyle(lipgloss.ThickBorder()).
-			BorderForeground(styles.AccentColor()). // Global accent
-			PaddingLeft(1)
-	}
-
-	content.WriteString(headerStyle.Render(headerContent.String()))
-	content.WriteString("\n")
-
-	// Details
-	if entry.ErrorMessage != "" {
-		if isSelected {
-			content.WriteString(styles.ErrorStyle.Render("  " + entry.ErrorMessage))
-			content.WriteString("\n")
-		} else {
-			content.WriteString(styles.ErrorGrayStyle.Render("  " + entry.ErrorMessage))
-			content.WriteString("\n")
-		}
-	}
-
-	if isExpanded && entry.RowCount > 0 {
-		// Render the expanded table component
-		tableContentView := m.expandedTable.View()
-		previewStyle := lipgloss.NewStyle().
-			Padding(1, 4)
-
-		if isSelected {
-			previewStyle = previewStyle.
-				BorderLeft(true).
-				BorderStyle(lipgloss.ThickBorder()).
-				BorderForeground(styles.AccentColor()).
-				PaddingLeft(3) // Adjusted for BorderLeft (4-1=3)
-		}
-
-		content.WriteString(previewStyle.Render(tableContentView))
-		content.WriteString("\n")
-	} else if isExpanded && entry.Preview != "" {
-		// Fallback for non-tabular preview (e.g., affected rows message)
-		previewLines := strings.Split(entry.Preview, "\n")
-		var previewBody strings.Builder
-		for _, line := range previewLines {
-			previewBody.WriteString(line + "\n")
-		}
-
-		previewStyle := lipgloss.NewStyle().
-			Foreground(styles.TextFaint()).
-			Padding(1, 4)
-
-		if isSelected {
-			previewStyle = previewStyle.
-				BorderLeft(true).
-				BorderStyle(lipgloss.ThickBorder()).
-				BorderForeground(styles.AccentColor()).
-				PaddingLeft(3)
-		}
+	m.historyList = m.historyList.Refresh()
+	return m
+}
 
-		content.WriteString(previewStyle.Render(previewBody.String()))
-		content.WriteString("\n")
+// expandPreview builds the inline table of an expanded entry when its preview holds rows
+func (m *Model) expandPreview(preview string) {
+	if strings.Contains(preview, " | ") {
+		m.expandedTable = m.tableBuilder.FromPreview(preview).
+			WithMaxTotalWidth(m.width - 14).
+			WithHorizontalFreezeColumnCount(1)
 	}
-
-	// Add spacing between history items for visual separation
-	// Add margin between cards
-	content.WriteString("\n\n")
-
-	return content.String()
 }
 
 // ensureSelectionVisible updates the viewport to keep the selected item in view
 func (m Model) ensureSelectionVisible() Model {
-	if len(m.history) == 0 {
-		return m
-	}
-
-	var sections []string
-	for i := range m.history {
-		sections = append(sections, strings.TrimRight(m.renderHistoryItem(i), "\n"))
-	}
-
-	// Calculate base heights including margins
-	top := 1 // Account for the MarginTop(1) added in renderHistoryContent
-	for i := 0; i < m.selected; i++ {
-		// lipgloss.Height(sections[i]) includes the item's Margin(1, 1).
-		// Margin(1, 1) means 1 top, 1 bottom. Total 2 lines of vertical margin.
-		top += lipgloss.Height(sections[i]) + 1 // +1 for JoinVertical newline
-	}
-
-	itemHeight := lipgloss.Height(sections[m.selected])
-	bottom := top + itemHeight
-
-	// Calculate total content height
-	content := lipgloss.NewStyle().MarginTop(1).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
-	totalHeight := lipgloss.Height(content)
-
-	vHeight := m.viewport.Height
-	if totalHeight < vHeight {
-		padding := vHeight - totalHeight
-		top += padding
-		bottom += padding
-	}
-
-	// Viewport window
-	vTop := m.viewport.YOffset
-	vBottom := vTop + vHeight // Visible bottom
-
-	if top < vTop {
-		m.viewport.SetYOffset(top)
-	} else if bottom > vBottom {
-		m.viewport.SetYOffset(bottom - vHeight)
-	}
-
+	m = m.refreshHistoryList()
+	m.historyList = m.historyList.EnsureVisible()
 	return m
 }
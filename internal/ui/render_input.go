@@ -3,7 +3,8 @@ package ui
 import "github.com/nhath/ezdb/internal/ui/highlight"
 
 // highlightView applies syntax highlighting to the textarea view.
-// Uses highlight.SQLPreserveANSI to preserve existing ANSI codes (cursor, etc.)
+// Uses highlight.SQLPreserveANSI to preserve existing ANSI codes (cursor, etc.),
+// then marks a selected snippet placeholder and the lint error.
 func (m Model) highlightView(view string) string {
-	return highlight.SQLPreserveANSI(view)
+	return m.markLintError(m.highlightTabStop(highlight.SQLPreserveANSI(view)))
 }
@@ -2,11 +2,13 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/nhath/ezdb/internal/ui/styles"
 	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/db"
 )
 
 // --- Results popup orchestration ---
@@ -16,7 +18,7 @@ func (m Model) renderPopupOverlay(main string) string {
 		return m.renderConfirmPopup(main)
 	}
 
-	// Layer the popups: results -> action menu -> row action
+	// Layer the popups: results -> action menu -> row action -> row detail -> column actions
 	resultsView := main
 	if m.popupEntry != nil && m.popupResult != nil {
 		resultsView = m.renderResultsPopup(main)
@@ -30,6 +32,14 @@ func (m Model) renderPopupOverlay(main string) string {
 		resultsView = m.renderRowActionPopup(resultsView)
 	}
 
+	if m.showRowDetail {
+		resultsView = m.renderRowDetail(resultsView)
+	}
+
+	if m.showColumnActions {
+		resultsView = m.renderColumnActions(resultsView)
+	}
+
 	if m.showExportPopup {
 		resultsView = m.renderExportPopup(resultsView)
 	}
@@ -38,16 +48,32 @@ func (m Model) renderPopupOverlay(main string) string {
 }
 
 func (m Model) renderResultsPopup(main string) string {
+	// Use bubbletea-overlay to composite popup over main content
+	return overlay.Composite(m.resultsPopupBox(), main, overlay.Center, overlay.Center, 0, 0)
+}
+
+// resultsPopupHeader renders the query and timing lines above the table
+func (m Model) resultsPopupHeader() string {
+	q := truncateEnd(m.popupEntry.Query, 100)
+	rows := fmt.Sprintf("%d", m.popupResult.RowCount)
+	if m.popupResult.Spill != nil {
+		end := min(m.popupOffset+resultWindowRows, m.popupResult.RowCount)
+		rows += fmt.Sprintf(" (showing %d-%d, paged from disk)", m.popupOffset+1, end)
+	}
+	duration := fmt.Sprintf("%dms", m.popupEntry.DurationMs)
+	if phases := m.popupResult.Timing.String(); phases != "" {
+		duration += " (" + phases + ")"
+	}
+	return fmt.Sprintf("Query: %s\nExecution Time: %s | Rows: %s\n\n",
+		q, duration, rows)
+}
+
+// resultsPopupBox renders the bordered results popup
+func (m Model) resultsPopupBox() string {
 	var content strings.Builder
 
 	// Header
-	q := m.popupEntry.Query
-	if len(q) > 100 {
-		q = q[:97] + "..."
-	}
-	content.WriteString(fmt.Sprintf("Query: %s\n", q))
-	content.WriteString(fmt.Sprintf("Execution Time: %dms | Rows: %d\n\n",
-		m.popupEntry.DurationMs, m.popupResult.RowCount))
+	content.WriteString(m.resultsPopupHeader())
 
 	// Table
 	if len(m.popupResult.Columns) > 0 {
@@ -63,54 +89,54 @@ func (m Model) renderResultsPopup(main string) string {
 	} else {
 		content.WriteString("\n\n")
 
-		// Helper to get key string
-		k := func(keys []string, def string) string {
-			if len(keys) > 0 {
-				return keys[0]
-			}
-			return def
-		}
-
-		shortcutsStr := fmt.Sprintf("%s/%s:page • %s/%s:scroll • %s:filter • %s:actions • %s:export • %s:close • %s:help",
-			k(m.config.Keys.NextPage, "n"), k(m.config.Keys.PrevPage, "b"),
-			k(m.config.Keys.ScrollLeft, "h"), k(m.config.Keys.ScrollRight, "l"),
-			k(m.config.Keys.Filter, "/"),
-			k(m.config.Keys.RowAction, "enter"),
-			k(m.config.Keys.Export, "ctrl+e"),
-			k(m.config.Keys.Exit, "q"),
-			k(m.config.Keys.Help, "?"))
-
-		shortcuts := lipgloss.NewStyle().Faint(true).Render(shortcutsStr)
-		content.WriteString(shortcuts)
+		content.WriteString(m.renderResultsHints(m.popupInnerWidth(resultsPopupWidth(m.width))))
 	}
 
 	// Popup sizing
-	popupWidth := m.width - 10
-	if popupWidth < 60 {
-		popupWidth = 60
-	}
-	if popupWidth > m.width {
-		popupWidth = m.width - 4
-	}
+	popupWidth := resultsPopupWidth(m.width)
 	popupHeight := m.height - 6
 	if popupHeight < 15 {
 		popupHeight = 15
 	}
 
 	// Table handles its own horizontal scrolling via h/l keys
-	popupBox := styles.PopupStyle.
+	return m.theme.PopupStyle.
 		Width(popupWidth).
 		Height(popupHeight).
-		Background(styles.PopupBg()).
+		Background(m.theme.PopupBg()).
 		Render(content.String())
+}
 
-	// Use bubbletea-overlay to composite popup over main content
-	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+// resultsPopupWidth is the width of the results popup in a terminal of width
+func resultsPopupWidth(width int) int {
+	popupWidth := max(width-10, 60)
+	if popupWidth > width {
+		popupWidth = width - 4
+	}
+	return popupWidth
+}
+
+// renderResultsHints renders the results popup's shortcuts within width
+func (m Model) renderResultsHints(width int) string {
+	keys := m.config.Keys
+	hints := []hint{
+		{firstKey(keys.NextPage, "n") + "/" + firstKey(keys.PrevPage, "b"), "page", 0},
+		{firstKey(keys.ScrollLeft, "h") + "/" + firstKey(keys.ScrollRight, "l"), "scroll", 1},
+		{firstKey(keys.Filter, "/"), "filter", 0},
+		{firstKey(keys.ServerFilter, "W"), "where", 2},
+		{firstKey(keys.RowAction, "enter"), "actions", 0},
+		{firstKey(keys.Export, "ctrl+e"), "export", 1},
+		{firstKey(keys.FullWidth, "w"), "width", 3},
+		{firstKey(keys.Exit, "q"), "close", 0},
+		{firstKey(keys.Help, "?"), "help", 0},
+	}
+	line := fitHints(hints, width, " • ", func(h hint) string { return h.Key + ":" + h.Label })
+	return lipgloss.NewStyle().Faint(true).Render(line)
 }
 
 func (m Model) renderActionPopup(main string) string {
 	var content strings.Builder
-	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(styles.AccentColor()).Render("Row Actions"))
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Row Actions"))
 	content.WriteString("\n\n")
 	content.WriteString("• Edit Row\n")
 	content.WriteString("• Delete Row\n")
@@ -118,11 +144,11 @@ func (m Model) renderActionPopup(main string) string {
 	content.WriteString("• Copy Row CSV\n")
 	content.WriteString("\n(Press q or Esc to close)")
 
-	popupBox := styles.PopupStyle.
+	popupBox := m.theme.PopupStyle.
 		Width(40).
-		Background(styles.PopupBg()).
+		Background(m.theme.PopupBg()).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(styles.BorderColor()).
+		BorderForeground(m.theme.BorderColor()).
 		Padding(1).
 		Render(content.String())
 
@@ -133,17 +159,10 @@ func (m Model) renderRowActionPopup(main string) string {
 	var content strings.Builder
 	header := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(styles.AccentColor()).
+		Foreground(m.theme.AccentColor()).
 		Render("Row Actions")
 	content.WriteString(header + "\n\n")
 
-	// Show available actions
-	content.WriteString("1 - Select this row\n")
-	content.WriteString("2 - View Full Row\n")
-	content.WriteString("3 - Copy as JSON\n")
-	content.WriteString("4 - Copy as CSV\n")
-	content.WriteString("\nPress 1-4, q to close")
-
 	// Calculate max content width
 	// Total rendered width = content width + 2 (borders) + 2 (padding) = content + 4
 	// So: content width = terminal width - 4 - safety margin
@@ -155,12 +174,27 @@ func (m Model) renderRowActionPopup(main string) string {
 		maxContentWidth = 20 // Minimum viable width
 	}
 
+	// Show available actions
+	content.WriteString("1 - Select this row\n")
+	content.WriteString("2 - View Full Row\n")
+	content.WriteString("3 - Copy as JSON\n")
+	content.WriteString("4 - Copy as CSV\n")
+	content.WriteString("5 - Copy as INSERT\n")
+	content.WriteString("6 - Copy as UPDATE\n")
+	if row := m.popupTable.HighlightedRow().Data; row != nil && m.popupResult != nil && m.rowActionColumn < len(m.popupResult.Columns) {
+		col := m.popupResult.Columns[m.rowActionColumn]
+		val := strings.Join(strings.Fields(cellText(row[col])), " ")
+		content.WriteString(truncateEnd("7 - Filter: "+col+" = "+val, maxContentWidth-2) + "\n")
+		content.WriteString(truncateEnd("8 - Exclude: "+col+" ≠ "+val, maxContentWidth-2) + "\n")
+	}
+	content.WriteString("\nPress 1-8, ←/→ column, q to close")
+
 	popupBox := lipgloss.NewStyle().
 		Width(maxContentWidth).
-		Background(styles.PopupBg()).
-		Foreground(styles.TextPrimary()).
+		Background(m.theme.PopupBg()).
+		Foreground(m.theme.TextPrimary()).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(styles.HighlightColor()).
+		BorderForeground(m.theme.HighlightColor()).
 		Padding(1).
 		Render(content.String())
 
@@ -170,30 +204,53 @@ func (m Model) renderRowActionPopup(main string) string {
 func (m Model) renderConfirmPopup(main string) string {
 	var content strings.Builder
 
-	header := styles.WarningStyle.Render(" CONFIRM DESTRUCTIVE ACTION ")
+	header := m.theme.WarningStyle.Render(" CONFIRM DESTRUCTIVE ACTION ")
+	if !isModifyingQuery(m.pendingQuery) && m.pendingStrict {
+		header = m.theme.WarningStyle.Render(" CONFIRM QUERY COST ")
+	} else if !isModifyingQuery(m.pendingQuery) {
+		header = m.theme.WarningStyle.Render(" CONFIRM QUERY ")
+	}
 	content.WriteString(header + "\n\n")
-	content.WriteString("Strict Mode is active. Do you really want to execute this query?\n\n")
+	subject := "query"
+	if m.pendingScript != "" {
+		subject = "file " + filepath.Base(m.pendingScript)
+	}
+	if len(m.pendingWarnings) > 0 || m.estimatingRows {
+		warningStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.WarningColor())
+		content.WriteString("Query guards flagged this " + subject + ":\n")
+		for _, w := range m.pendingWarnings {
+			content.WriteString(warningStyle.Render("  • "+w) + "\n")
+		}
+		if m.estimatingRows {
+			content.WriteString(lipgloss.NewStyle().Foreground(m.theme.TextFaint()).Render("  Estimating affected rows...") + "\n")
+		}
+		content.WriteString("\nDo you really want to execute this " + subject + "?\n\n")
+	} else {
+		content.WriteString("Strict Mode is active. Do you really want to execute this " + subject + "?\n\n")
+	}
 
 	// Query Preview
-	q := m.pendingQuery
-	if len(q) > 400 {
-		q = q[:397] + "..."
-	}
+	q := truncateEnd(m.pendingQuery, 400)
 	content.WriteString(lipgloss.NewStyle().
 		Border(lipgloss.NormalBorder(), true).
-		BorderForeground(styles.TextFaint()).
+		BorderForeground(m.theme.TextFaint()).
 		Padding(1).
-		Foreground(styles.TextPrimary()).
+		Foreground(m.theme.TextPrimary()).
 		Render(q))
 
+	if m.pendingCost != "" {
+		content.WriteString("\n\n")
+		content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.WarningColor()).Render(m.pendingCost))
+	}
+
 	content.WriteString("\n\n")
-	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(styles.SuccessColor()).Render("(y) Yes, execute") + "  " +
-		lipgloss.NewStyle().Bold(true).Foreground(styles.ErrorColor()).Render("(n/Esc) No, cancel"))
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.SuccessColor()).Render("(y) Yes, execute") + "  " +
+		lipgloss.NewStyle().Bold(true).Foreground(m.theme.ErrorColor()).Render("(n/Esc) No, cancel"))
 
 	// Box styling with background
-	popupBox := styles.PopupStyle.
+	popupBox := m.theme.PopupStyle.
 		Width(min(80, m.width-4)).
-		Background(styles.PopupBg()). // Dark background for popup
+		Background(m.theme.PopupBg()). // Dark background for popup
 		Render(content.String())
 
 	// Use bubbletea-overlay to composite popup over main content
@@ -203,25 +260,52 @@ func (m Model) renderConfirmPopup(main string) string {
 func (m Model) renderExportPopup(main string) string {
 	var content strings.Builder
 
+	title := "Export Results"
+	if m.exportTable != "" {
+		title = "Export " + m.exportTable
+	}
+
 	header := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(styles.AccentColor()).
-		Render("Export Results")
+		Foreground(m.theme.AccentColor()).
+		Render(title)
 	content.WriteString(header + "\n\n")
 
 	content.WriteString("Enter filename (or path):\n\n")
 	content.WriteString(m.exportInput.View())
-	content.WriteString("\n\n")
+	content.WriteString("\n")
+	content.WriteString(m.renderPathMatches(46))
+	content.WriteString("\n")
+	faint := lipgloss.NewStyle().Faint(true)
+	hint := "Enter: Export • Tab: Complete path • Esc: Cancel"
+	if m.exportTable == "" {
+		content.WriteString(faint.Render("Use .zip or dir/ for a query+results bundle, clipboard: to copy as CSV or - to print CSV on exit"))
+		content.WriteString("\n")
+	} else {
+		for _, in := range m.exportFilterInputs {
+			content.WriteString(in.View())
+			content.WriteString("\n")
+		}
+		content.WriteString(m.renderColumnMatches(46))
+		if m.exportConfirm {
+			warn := lipgloss.NewStyle().Foreground(m.theme.WarningColor()).Bold(true)
+			content.WriteString(warn.Render(m.exportEstimate.Text() + ", continue?"))
+			hint = "Enter: Export all • ↓: Add a filter • Esc: Cancel"
+		} else {
+			content.WriteString(faint.Render(m.exportEstimate.Text()))
+			hint = "Enter: Export • Tab: Complete • ↑↓: Filter • Esc: Cancel"
+		}
+		content.WriteString("\n\n")
+	}
 
-	hint := lipgloss.NewStyle().Faint(true).Render("Enter: Export | Esc: Cancel")
-	content.WriteString(hint)
+	content.WriteString(m.renderHintLine(hint, 48))
 
 	popupBox := lipgloss.NewStyle().
 		Width(50).
-		Background(styles.PopupBg()).
-		Foreground(styles.TextPrimary()).
+		Background(m.theme.PopupBg()).
+		Foreground(m.theme.TextPrimary()).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(styles.SuccessColor()).
+		BorderForeground(m.theme.SuccessColor()).
 		Padding(1).
 		Render(content.String())
 
@@ -231,10 +315,10 @@ func (m Model) renderExportPopup(main string) string {
 // --- Help popup ---
 
 func (m Model) getHelpContext() HelpContext {
-	if m.schemaBrowser.IsVisible() {
+	if m.schemaFocused() {
 		return HelpContextSchema
 	}
-	if m.showPopup {
+	if m.showPopup || m.showBrowsePopup {
 		return HelpContextPopup
 	}
 	if m.mode == InsertMode {
@@ -252,22 +336,22 @@ func (m Model) renderHelpPopup(main string) string {
 	// Styles
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(styles.AccentColor()).
+		Foreground(m.theme.AccentColor()).
 		MarginBottom(1)
 
 	sectionStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(styles.HighlightColor()).
+		Foreground(m.theme.HighlightColor()).
 		MarginTop(1)
 
 	keyStyle := lipgloss.NewStyle().
-		Foreground(styles.TextPrimary()).
-		Background(styles.CardBg()).
+		Foreground(m.theme.TextPrimary()).
+		Background(m.theme.CardBg()).
 		Padding(0, 1).
 		Bold(true)
 
 	descStyle := lipgloss.NewStyle().
-		Foreground(styles.TextSecondary())
+		Foreground(m.theme.TextSecondary())
 
 	rowStyle := lipgloss.NewStyle().
 		MarginLeft(1)
@@ -281,22 +365,6 @@ func (m Model) renderHelpPopup(main string) string {
 		return rowStyle.Render(keyStyle.Render(key) + " " + descStyle.Render(desc))
 	}
 
-	// Context title
-	var contextName string
-	switch ctx {
-	case HelpContextInsert:
-		contextName = "Insert Mode"
-	case HelpContextPopup:
-		contextName = "Results View"
-	case HelpContextSchema:
-		contextName = "Schema Browser"
-	default:
-		contextName = "Visual Mode"
-	}
-
-	content.WriteString(titleStyle.Render("Shortcuts - " + contextName))
-	content.WriteString("\n")
-
 	// Helper to get first key or fallback
 	key := func(bindings []string, fallback string) string {
 		if len(bindings) > 0 {
@@ -305,133 +373,40 @@ func (m Model) renderHelpPopup(main string) string {
 		return fallback
 	}
 
-	// Helper to join first keys with separator
-	keyPair := func(a, b []string) string {
-		return key(a, "?") + "/" + key(b, "?")
-	}
-
-	// Context-specific shortcuts
-	switch ctx {
-	case HelpContextInsert:
-		content.WriteString(sectionStyle.Render("Query"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Execute, "ctrl+d"), "Execute query"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Explain, "X"), "Explain query"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Autocomplete, "ctrl+space"), "Autocomplete"))
-		content.WriteString("\n")
-
-		content.WriteString(sectionStyle.Render("Edit"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Undo, "ctrl+z"), "Undo"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Redo, "ctrl+y"), "Redo"))
-		content.WriteString("\n")
-
-		content.WriteString(sectionStyle.Render("Navigation"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Exit, "esc"), "Exit to Visual mode"))
-		content.WriteString("\n")
-
-	case HelpContextPopup:
-		content.WriteString(sectionStyle.Render("Navigation"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(keyPair(keys.MoveUp, keys.MoveDown), "Navigate rows"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(keyPair(keys.ScrollLeft, keys.ScrollRight), "Scroll columns"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(keyPair(keys.NextPage, keys.PrevPage), "Page up/down"))
-		content.WriteString("\n")
-
-		content.WriteString(sectionStyle.Render("Actions"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.RowAction, "enter"), "Row actions"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Filter, "/"), "Filter results"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Export, "ctrl+e"), "Export to file"))
-		content.WriteString("\n")
-
-		content.WriteString(sectionStyle.Render("Exit"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Exit, "esc"), "Close popup"))
-		content.WriteString("\n")
-
-	case HelpContextSchema:
-		content.WriteString(sectionStyle.Render("Navigation"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(keyPair(keys.MoveUp, keys.MoveDown), "Navigate tables"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(keyPair(keys.ScrollRight, keys.ScrollLeft), "Switch tabs"))
-		content.WriteString("\n")
-
-		content.WriteString(sectionStyle.Render("Actions"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.ToggleExpand, "enter"), "View columns"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.ToggleTheme, "t"), "Query templates"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Export, "e"), "Export table"))
-		content.WriteString("\n")
-
-		content.WriteString(sectionStyle.Render("Exit"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.ToggleSchema, "tab"), "Close browser"))
-		content.WriteString("\n")
-
-	default: // Visual mode
-		content.WriteString(sectionStyle.Render("Navigation"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(keyPair(keys.MoveUp, keys.MoveDown), "Navigate history"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(keyPair(keys.GoTop, keys.GoBottom), "Jump to top/bottom"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.ToggleExpand, "enter"), "Expand/collapse"))
-		content.WriteString("\n")
+	content.WriteString(titleStyle.Render("Shortcuts - " + ctx.Title()))
+	content.WriteString("\n")
 
-		content.WriteString(sectionStyle.Render("Actions"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.InsertMode, "i"), "Enter Insert mode"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Rerun, "r"), "Rerun query"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Edit, "e"), "Edit query"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Copy, "y"), "Copy query"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.Delete, "x"), "Delete entry"))
+	filter := m.helpFilterInput.Value()
+	if m.helpFilterActive || filter != "" {
+		content.WriteString(m.helpFilterInput.View())
 		content.WriteString("\n")
+	}
 
-		content.WriteString(sectionStyle.Render("Panels"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.ToggleSchema, "tab"), "Schema browser"))
+	// Sections are generated from the action registry and the live keymap
+	order, sections := helpSections(keys, ctx, filter)
+	if len(order) == 0 {
+		content.WriteString(descStyle.Render("No matching shortcuts"))
 		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.ToggleTheme, "t"), "Theme selector"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.ShowProfiles, "P"), "Switch profile"))
-		content.WriteString("\n")
-		content.WriteString(renderRow(key(keys.ToggleStrict, "m"), "Toggle strict mode"))
+	}
+	for _, group := range order {
+		content.WriteString(sectionStyle.Render(group))
 		content.WriteString("\n")
+		for _, b := range sections[group] {
+			content.WriteString(renderRow(strings.Join(b.Keys, "/"), b.Desc))
+			content.WriteString("\n")
+		}
 	}
 
-	// Always show quit
-	content.WriteString(sectionStyle.Render("General"))
-	content.WriteString("\n")
-	content.WriteString(renderRow(key(keys.Help, "?"), "Toggle this help"))
-	content.WriteString("\n")
-	content.WriteString(renderRow(key(keys.Quit, "ctrl+c"), "Quit"))
-	content.WriteString("\n")
-
-	content.WriteString(footerStyle.Render("Press " + key(keys.Help, "?") + " or " + key(keys.Exit, "esc") + " to close"))
+	editKeys, _ := actionNamed("HelpEditKeys")
+	content.WriteString(footerStyle.Render(key(keys.Filter, "/") + " search • " + key(editKeys.keys(&keys), "e") + " edit keys • " + key(keys.Help, "?") + " or " + key(keys.Exit, "esc") + " to close"))
 
 	// Style popup
-	popupWidth := 42
-	popupBox := styles.PopupStyle.
+	popupWidth := 48
+	popupBox := m.theme.PopupStyle.
 		Width(popupWidth).
 		MaxHeight(m.height-4).
 		Padding(1, 2).
-		Background(styles.PopupBg()).
+		Background(m.theme.PopupBg()).
 		Render(content.String())
 
 	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
@@ -443,39 +418,37 @@ func (m Model) renderTemplatePopup(main string) string {
 	var content strings.Builder
 
 	// Title
-	title := lipgloss.NewStyle().Bold(true).Foreground(styles.AccentColor()).Render(
+	title := lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render(
 		fmt.Sprintf("Quick Queries for: %s", m.templateTable))
 	content.WriteString(title)
 	content.WriteString("\n\n")
 
 	// List templates
-	for i, t := range m.config.QueryTemplates {
-		style := lipgloss.NewStyle().Foreground(styles.TextSecondary())
+	for i, t := range m.queryTemplates() {
+		style := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
 		prefix := "  "
 		if i == m.templateIdx {
-			style = lipgloss.NewStyle().Foreground(styles.SuccessColor()).Bold(true)
+			style = lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Bold(true)
 			prefix = " "
 		}
 		// Show template with replaced table name for preview
-		preview := strings.ReplaceAll(t.Query, "<table>", m.templateTable)
-		if len(preview) > 50 {
-			preview = preview[:47] + "..."
-		}
+		preview := truncateEnd(strings.ReplaceAll(t.Query, "<table>", m.templateTable), 50)
 		content.WriteString(fmt.Sprintf("%s%s\n", prefix, style.Render(t.Name)))
 		content.WriteString(fmt.Sprintf("    %s\n\n", lipgloss.NewStyle().Faint(true).Render(preview)))
 	}
 
-	content.WriteString(lipgloss.NewStyle().Faint(true).Render("Enter: execute • i: insert into editor • Esc: cancel"))
-
 	// Style popup
 	popupWidth := 60
 	if popupWidth > m.width-10 {
 		popupWidth = m.width - 10
 	}
-	popupBox := styles.PopupStyle.
+	manage, _ := actionNamed("TemplateManage")
+	content.WriteString(m.renderHintLine("Enter: execute • i: insert into editor • "+manage.keys(&m.config.Keys)[0]+": manage • Esc: cancel", m.popupInnerWidth(popupWidth)))
+
+	popupBox := m.theme.PopupStyle.
 		Width(popupWidth).
 		MaxHeight(m.height - 4).
-		Background(styles.PopupBg()).
+		Background(m.theme.PopupBg()).
 		Render(content.String())
 
 	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
@@ -486,19 +459,39 @@ func (m Model) renderTemplatePopup(main string) string {
 func (m Model) renderImportPopup(main string) string {
 	var content strings.Builder
 
-	title := lipgloss.NewStyle().Bold(true).Foreground(styles.AccentColor()).Render(
+	title := lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render(
 		fmt.Sprintf("Import into: %s", m.importTable))
 	content.WriteString(title)
 	content.WriteString("\n\n")
 	content.WriteString(m.importInput.View())
-	content.WriteString("\n\n")
-	content.WriteString(lipgloss.NewStyle().Faint(true).Render("Enter: import • Esc: cancel"))
-
+	content.WriteString("\n")
+	content.WriteString(m.renderPathMatches(56))
+	content.WriteString("\n")
+	faint := lipgloss.NewStyle().Faint(true)
+	check := func(on bool) string {
+		if on {
+			return "[x] "
+		}
+		return "[ ] "
+	}
+	opts := m.importOpts
+	content.WriteString(check(opts.DryRun) + "Validate only, inserting nothing " + faint.Render("Ctrl+T") + "\n")
+	if _, ok := m.driver.(db.BulkLoader); ok {
+		content.WriteString(faint.Render("    Bulk loaded in one statement; if the server refuses:") + "\n")
+	}
+	content.WriteString(fmt.Sprintf("    Batch size: %d rows ", opts.BatchSize) + faint.Render("Ctrl+G") + "\n")
+	content.WriteString(check(opts.Transaction) + "One transaction, rolled back on any error " + faint.Render("Ctrl+X") + "\n")
+	if r := m.importResume; r != nil && r.Table == m.importTable {
+		content.WriteString(check(opts.ResumeRow > 0) + fmt.Sprintf("Resume from row %d of %s ", r.Row, filepath.Base(r.File)) + faint.Render("Ctrl+R") + "\n")
+	}
+	content.WriteString("\n")
 	popupWidth := 60
-	popupBox := styles.PopupStyle.
+	content.WriteString(m.renderHintLine("Enter: import • Tab: complete path • Esc: cancel", m.popupInnerWidth(popupWidth)))
+
+	popupBox := m.theme.PopupStyle.
 		Width(popupWidth).
-		MaxHeight(10).
-		Background(styles.PopupBg()).
+		MaxHeight(20).
+		Background(m.theme.PopupBg()).
 		Render(content.String())
 
 	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
@@ -5,71 +5,265 @@ import (
 	"strings"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nhath/ezdb/internal/db"
 	"github.com/nhath/ezdb/internal/ui/icons"
-	"github.com/nhath/ezdb/internal/ui/styles"
 )
 
+// defaultStatusFormat is the status bar layout when status_format is not set
+const defaultStatusFormat = "{mode}{label}{profile}{db}{schema}{flavor}{strict}{readonly}{rows}{chord}{activity}{lint}{message}"
+
+// statusSegments render the {name} placeholders of the status format; an
+// empty string leaves the segment out
+var statusSegments = map[string]func(Model) string{
+	"mode":     Model.statusMode,
+	"label":    Model.statusLabel,
+	"profile":  Model.statusProfile,
+	"db":       Model.statusDB,
+	"schema":   Model.statusSchema,
+	"flavor":   Model.statusFlavor,
+	"strict":   Model.statusStrict,
+	"readonly": Model.statusReadOnly,
+	"tx":       Model.statusTx,
+	"rows":     Model.statusRows,
+	"clock":    Model.statusClock,
+	"chord":    Model.statusChord,
+	"activity": Model.statusActivity,
+	"lint":     Model.statusLint,
+	"message":  Model.statusMessage,
+}
+
+// statusToken is literal text or a segment name of the status format
+type statusToken struct {
+	text    string
+	segment string
+}
+
+// parseStatusFormat splits format into literal text and {segment}
+// placeholders; unknown placeholders stay literal so typos show up
+func parseStatusFormat(format string) []statusToken {
+	var tokens []statusToken
+	for format != "" {
+		open := strings.Index(format, "{")
+		if open < 0 {
+			tokens = append(tokens, statusToken{text: format})
+			break
+		}
+		end := strings.Index(format[open:], "}")
+		if end < 0 {
+			tokens = append(tokens, statusToken{text: format})
+			break
+		}
+		name := format[open+1 : open+end]
+		if _, ok := statusSegments[name]; !ok {
+			tokens = append(tokens, statusToken{text: format[:open+end+1]})
+		} else {
+			if open > 0 {
+				tokens = append(tokens, statusToken{text: format[:open]})
+			}
+			tokens = append(tokens, statusToken{segment: name})
+		}
+		format = format[open+end+1:]
+	}
+	return tokens
+}
+
+// statusFormat returns the configured status bar layout
+func (m Model) statusFormat() string {
+	if m.config.StatusFormat != "" {
+		return m.config.StatusFormat
+	}
+	return defaultStatusFormat
+}
+
 func (m Model) renderStatusBar() string {
 	var parts []string
+	for _, t := range parseStatusFormat(m.statusFormat()) {
+		if t.segment == "" {
+			parts = append(parts, t.text)
+		} else if s := statusSegments[t.segment](m); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	content := lipgloss.JoinHorizontal(lipgloss.Left, parts...)
+	return m.theme.StatusBarStyle.Width(m.width).Render(content)
+}
 
-	// 1. Mode
-	modeStr := strings.ToUpper(string(m.mode))
-	modeStyle := styles.ModeStyle
+func (m Model) statusMode() string {
+	modeStyle := m.theme.ModeStyle
 	if m.mode == InsertMode {
-		modeStyle = styles.InsertModeStyle
+		modeStyle = m.theme.InsertModeStyle
 	}
-	parts = append(parts, modeStyle.Render(modeStr))
+	return modeStyle.Render(strings.ToUpper(string(m.mode)))
+}
 
-	// 2. Connection Info
-	if m.profile != nil {
-		icon := icons.GetDatabaseIcon(m.profile.Type)
-		profileInfo := styles.ConnectionStyle.Render(fmt.Sprintf(" %s %s ", icon, m.profile.Name))
+// statusLabel is the environment label, e.g. PROD
+func (m Model) statusLabel() string {
+	if m.profile == nil || m.profile.Label == "" {
+		return ""
+	}
+	accent := m.connectionAccent()
+	labelStyle := lipgloss.NewStyle().Background(accent).Foreground(m.theme.OnColor(accent)).Padding(0, 1).Bold(true)
+	return labelStyle.Render(strings.ToUpper(strings.TrimSpace(m.profile.Label)))
+}
 
-		dbInfo := fmt.Sprintf(" %s@%s:%d/%s ", m.profile.User, limitString(m.profile.Host, 20), m.profile.Port, m.profile.Database)
-		if m.profile.Type == "sqlite" {
-			dbInfo = fmt.Sprintf(" sqlite:%s ", m.profile.Database)
-		}
+func (m Model) statusProfile() string {
+	if m.profile == nil {
+		return m.theme.ConnectionStyle.Render(" NO PROFILE ")
+	}
+	connStyle := m.theme.ConnectionStyle
+	if _, ok := m.profileColor(); ok {
+		connStyle = connStyle.Background(m.connectionAccent()).Foreground(m.theme.OnColor(m.connectionAccent())).Bold(true)
+	}
+	return connStyle.Render(fmt.Sprintf(" %s %s ", icons.GetDatabaseIcon(m.profile.Type), m.profile.Name))
+}
 
-		parts = append(parts, profileInfo+lipgloss.NewStyle().Background(styles.CardBg()).Foreground(styles.TextPrimary()).Render(dbInfo))
-	} else {
-		parts = append(parts, styles.ConnectionStyle.Render(" NO PROFILE "))
+func (m Model) statusDB() string {
+	if m.profile == nil {
+		return ""
+	}
+	dbInfo := fmt.Sprintf(" %s@%s:%d/%s ", m.profile.User, limitString(m.profile.Host, 20), m.profile.Port, m.profile.Database)
+	if m.profile.Type == "sqlite" {
+		dbInfo = fmt.Sprintf(" sqlite:%s ", m.profile.Database)
+	} else if m.profile.Type == "bigquery" {
+		dbInfo = fmt.Sprintf(" bigquery:%s/%s ", m.profile.Host, m.profile.Database)
 	}
+	return lipgloss.NewStyle().Background(m.theme.CardBg()).Foreground(m.theme.TextPrimary()).Render(dbInfo)
+}
 
-	// 3. Strict Mode
-	if m.strictMode {
-		parts = append(parts, lipgloss.NewStyle().Background(styles.WarningColor()).Foreground(styles.BgPrimary()).Padding(0, 1).Bold(true).Render(icons.IconLock+" STRICT "))
+// statusSchema is the schema on the search_path or MySQL database, switched with S
+func (m Model) statusSchema() string {
+	if m.profile == nil || m.currentSchema == "" || m.currentSchema == m.profile.Database {
+		return ""
 	}
+	schemaStyle := lipgloss.NewStyle().Background(m.theme.CardBg()).Foreground(m.theme.HighlightColor()).Padding(0, 1)
+	return schemaStyle.Render(icons.IconSchema + " " + m.currentSchema)
+}
 
-	// 4. Loading indicator
-	if m.loading {
-		spinner := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-		frame := spinner[int(time.Now().UnixMilli()/100)%len(spinner)]
-		loadingStyle := lipgloss.NewStyle().Foreground(styles.AccentColor()).Padding(0, 1)
-		parts = append(parts, loadingStyle.Render(frame+" Running..."))
-	} else if m.loadingTables {
-		spinner := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-		frame := spinner[int(time.Now().UnixMilli()/100)%len(spinner)]
-		loadingStyle := lipgloss.NewStyle().Foreground(styles.HighlightColor()).Padding(0, 1)
-		parts = append(parts, loadingStyle.Render(frame+" Loading schema..."))
+// statusFlavor is the detected server flavor (MySQL, MariaDB, TiDB)
+func (m Model) statusFlavor() string {
+	reporter, ok := m.driver.(db.FlavorReporter)
+	if m.profile == nil || !ok || reporter.Flavor() == "" {
+		return ""
 	}
+	flavorStyle := lipgloss.NewStyle().Background(m.theme.CardBg()).Foreground(m.theme.AccentColor()).Padding(0, 1)
+	return flavorStyle.Render(reporter.Flavor())
+}
 
-	// 5. Status message (success/info)
-	if m.statusMsg != "" {
-		statusStyle := lipgloss.NewStyle().Background(styles.SuccessColor()).Foreground(styles.BgPrimary()).Padding(0, 1)
-		parts = append(parts, statusStyle.Render(icons.IconSuccess+" "+m.statusMsg))
+func (m Model) statusStrict() string {
+	if !m.strictMode {
+		return ""
 	}
+	return lipgloss.NewStyle().Background(m.theme.WarningColor()).Foreground(m.theme.OnColor(m.theme.WarningColor())).Padding(0, 1).Bold(true).Render(icons.IconLock + " STRICT ")
+}
 
-	// 6. Error indicator
-	if m.errorMsg != "" {
-		errorStyle := lipgloss.NewStyle().Background(styles.ErrorColor()).Foreground(styles.TextPrimary()).Padding(0, 1)
-		truncated := m.errorMsg
-		if len(truncated) > 40 {
-			truncated = truncated[:37] + "..."
-		}
-		parts = append(parts, errorStyle.Render(icons.IconError+" "+truncated))
+func (m Model) statusReadOnly() string {
+	if m.profile == nil || !m.config.ReadOnly(m.profile) {
+		return ""
 	}
+	return lipgloss.NewStyle().Background(m.theme.CardBg()).Foreground(m.theme.WarningColor()).Padding(0, 1).Bold(true).Render("READ-ONLY")
+}
 
-	content := lipgloss.JoinHorizontal(lipgloss.Left, parts...)
-	return styles.StatusBarStyle.Width(m.width).Render(content)
+// statusTx shows an open transaction
+func (m Model) statusTx() string {
+	if !m.inTransaction {
+		return ""
+	}
+	return lipgloss.NewStyle().Background(m.theme.HighlightColor()).Foreground(m.theme.OnColor(m.theme.HighlightColor())).Padding(0, 1).Bold(true).Render("TX")
+}
+
+// runSummary is the outcome of the last query, kept in the status bar until the next one
+type runSummary struct {
+	rows     int64
+	affected bool // Rows written rather than returned
+	failed   bool
+	duration time.Duration
+}
+
+// String describes the run, e.g. "142 rows in 38ms"
+func (s runSummary) String() string {
+	ms := s.duration.Milliseconds()
+	if s.failed {
+		return fmt.Sprintf("failed after %dms", ms)
+	}
+	noun := "rows"
+	if s.rows == 1 {
+		noun = "row"
+	}
+	if s.affected {
+		noun += " affected"
+	}
+	return fmt.Sprintf("%d %s in %dms", s.rows, noun, ms)
+}
+
+// statusRows summarizes the last query: rows returned or affected and its time
+func (m Model) statusRows() string {
+	if m.lastRun == nil {
+		return ""
+	}
+	style := lipgloss.NewStyle().Foreground(m.theme.TextSecondary()).Padding(0, 1)
+	if m.lastRun.failed {
+		style = style.Foreground(m.theme.ErrorColor())
+	}
+	return style.Render(m.lastRun.String())
+}
+
+func (m Model) statusClock() string {
+	return lipgloss.NewStyle().Foreground(m.theme.TextSecondary()).Padding(0, 1).Render(time.Now().Format("15:04"))
+}
+
+// statusChord is a partial chord waiting for its next key
+func (m Model) statusChord() string {
+	if len(m.pendingKeys) == 0 {
+		return ""
+	}
+	chordStyle := lipgloss.NewStyle().Foreground(m.theme.HighlightColor()).Padding(0, 1).Bold(true)
+	return chordStyle.Render(strings.Join(keyTokens(m.pendingKeys), " ") + " …")
+}
+
+// statusActivity is the transfer progress or loading indicator
+func (m Model) statusActivity() string {
+	spinner := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	frame := spinner[int(time.Now().UnixMilli()/100)%len(spinner)]
+	switch {
+	case m.transfer != nil:
+		return m.renderTransferProgress()
+	case m.loading:
+		return lipgloss.NewStyle().Foreground(m.theme.AccentColor()).Padding(0, 1).Render(frame + " Running...")
+	case m.loadingTables:
+		return lipgloss.NewStyle().Foreground(m.theme.HighlightColor()).Padding(0, 1).Render(frame + " Loading schema...")
+	}
+	return ""
+}
+
+// statusLint is a problem found in the editor's SQL before running it
+func (m Model) statusLint() string {
+	msg := m.lintMessage()
+	if msg == "" {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(m.theme.ErrorColor()).Padding(0, 1).Render(icons.IconError + " " + msg)
+}
+
+// statusMessage is the toast of queued notifications, or else the error
+// kept until it is cleared
+func (m Model) statusMessage() string {
+	if toast := m.renderToast(); toast != "" {
+		return toast
+	}
+	if m.errorMsg == "" {
+		return ""
+	}
+	errorStyle := lipgloss.NewStyle().Background(m.theme.ErrorColor()).Foreground(m.theme.TextPrimary()).Padding(0, 1)
+	truncated := truncateEnd(m.errorMsg, 40)
+	return errorStyle.Render(icons.IconError + " " + truncated)
+}
+
+// clockTickCmd redraws the status bar clock at the start of the next minute
+func clockTickCmd() tea.Cmd {
+	now := time.Now()
+	return tea.Tick(now.Truncate(time.Minute).Add(time.Minute).Sub(now), func(time.Time) tea.Msg {
+		return ClockTickMsg{}
+	})
 }
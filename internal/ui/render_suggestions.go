@@ -5,7 +5,6 @@ import (
 
 	"github.com/nhath/ezdb/internal/ui/autocomplete"
 	"github.com/nhath/ezdb/internal/ui/icons"
-	"github.com/nhath/ezdb/internal/ui/styles"
 )
 
 // renderSuggestions renders the suggestion dropdown with type indicators
@@ -15,7 +14,7 @@ func (m Model) renderSuggestions() string {
 	}
 
 	if m.loadingTables {
-		return styles.SuggestionBoxStyle.Render("Loading schema...")
+		return m.theme.SuggestionBoxStyle.Render("Loading schema...")
 	}
 
 	var views []string
@@ -37,10 +36,10 @@ func (m Model) renderSuggestions() string {
 
 	for i := start; i < end; i++ {
 		s := m.suggestions[i]
-		style := styles.SuggestionItemStyle
+		style := m.theme.SuggestionItemStyle
 		prefix := "  "
 		if i == m.suggestionIdx {
-			style = styles.SuggestionSelectedStyle
+			style = m.theme.SuggestionSelectedStyle
 			prefix = " " + icons.IconSelect + " "
 		}
 
@@ -56,6 +55,10 @@ func (m Model) renderSuggestions() string {
 				typeIndicator = " " + icons.IconTypeC
 			case autocomplete.SuggestFunction:
 				typeIndicator = " " + icons.IconTypeF
+			case autocomplete.SuggestJoin:
+				typeIndicator = " " + icons.IconFKey
+			case autocomplete.SuggestSnippet, autocomplete.SuggestRecent:
+				typeIndicator = " " + icons.IconRefresh
 			default:
 				typeIndicator = " " + icons.IconBullet
 			}
@@ -70,5 +73,5 @@ func (m Model) renderSuggestions() string {
 		views = append(views, style.Render(prefix+s+typeIndicator+detail))
 	}
 
-	return styles.SuggestionBoxStyle.Render(strings.Join(views, "\n"))
+	return m.theme.SuggestionBoxStyle.Render(strings.Join(views, "\n"))
 }
@@ -0,0 +1,85 @@
+// internal/ui/result_spill.go
+// Results that outgrew result_memory_mb: the results popup pages through their spilled rows a window at a time.
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// resultWindowRows is how many rows of a spilled result the popup table holds at a time
+const resultWindowRows = 1000
+
+// keepResult makes result the shown one, closing the spill of the result it replaces
+func (m *Model) keepResult(result *db.QueryResult) {
+	if m.spilled != nil && m.spilled != result {
+		m.spilled.Close()
+	}
+	m.spilled = nil
+	if result != nil && result.Spill != nil {
+		m.spilled = result
+	}
+}
+
+// resultWindow returns the rows of result the popup table holds when it
+// starts at row offset; a result without a spill is held whole
+func resultWindow(result *db.QueryResult, offset int) (*db.QueryResult, error) {
+	if result == nil || result.Spill == nil {
+		return result, nil
+	}
+	rows, err := result.RowsAt(offset, resultWindowRows)
+	if err != nil {
+		return nil, err
+	}
+	window := *result
+	window.Rows = rows
+	window.Spill = nil
+	return &window, nil
+}
+
+// setPopupWindow builds the popup table from the rows of result starting at
+// offset, keeping the current table when they cannot be read
+func (m *Model) setPopupWindow(result *db.QueryResult, offset int) bool {
+	window, err := resultWindow(result, offset)
+	if err != nil {
+		m.errorMsg = err.Error()
+		return false
+	}
+	m.popupOffset = offset
+	m.popupTable = m.resultTables().WithFullWidth(m.fullWidthColumn, m.popupTableWidth(), m.popupFrozen).FromQueryResult(window, 0).Focused(true)
+	m.updatePopupTable()
+	return true
+}
+
+// pageResultWindow moves the popup to the next or previous window of a
+// spilled result when a key would page or step past the rows it holds.
+// Filtering works within the window, so a filtered table is left alone.
+func (m Model) pageResultWindow(msg tea.KeyMsg) (Model, bool) {
+	result := m.popupResult
+	if result == nil || result.Spill == nil || m.tableFilterInput.Value() != "" {
+		return m, false
+	}
+	keys := m.config.Keys
+	t := m.popupTable
+	end := min(m.popupOffset+resultWindowRows, result.RowCount)
+
+	switch {
+	case end < result.RowCount &&
+		(matchKey(msg, keys.NextPage) && t.CurrentPage() == t.MaxPages() ||
+			matchKey(msg, keys.MoveDown) && t.GetHighlightedRowIndex() == t.TotalRows()-1):
+		m.setPopupWindow(result, end)
+		return m, true
+	case m.popupOffset > 0 && matchKey(msg, keys.PrevPage) && t.CurrentPage() == 1:
+		if m.setPopupWindow(result, max(m.popupOffset-resultWindowRows, 0)) {
+			m.popupTable = m.popupTable.PageLast()
+		}
+		return m, true
+	case m.popupOffset > 0 && matchKey(msg, keys.MoveUp) && t.GetHighlightedRowIndex() == 0:
+		if m.setPopupWindow(result, max(m.popupOffset-resultWindowRows, 0)) {
+			m.popupTable = m.popupTable.WithHighlightedRow(m.popupTable.TotalRows() - 1)
+		}
+		return m, true
+	}
+	return m, false
+}
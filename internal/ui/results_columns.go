@@ -0,0 +1,106 @@
+// internal/ui/results_columns.go
+// Results popup columns: showing a column cut short at its full width, and rebuilding the table when a display option changes.
+package ui
+
+import (
+	"fmt"
+
+	"github.com/evertras/bubble-table/table"
+
+	"github.com/nhath/ezdb/internal/history"
+	eztable "github.com/nhath/ezdb/internal/ui/components/table"
+)
+
+// toggleFullWidth shows the column picked by fullWidthTarget at its natural
+// width, or caps the widened column again
+func (m *Model) toggleFullWidth() {
+	if m.popupResult == nil || len(m.popupResult.Columns) == 0 {
+		return
+	}
+	if m.fullWidthColumn != "" {
+		m.statusMsg = m.fullWidthColumn + " capped again"
+		m.fullWidthColumn = ""
+	} else {
+		m.fullWidthColumn = m.fullWidthTarget()
+		m.statusMsg = fmt.Sprintf("%s at full width; scroll with %s/%s", m.fullWidthColumn,
+			firstKey(m.config.Keys.ScrollLeft, "h"), firstKey(m.config.Keys.ScrollRight, "l"))
+	}
+	m.rebuildPopupTable()
+}
+
+// fullWidthTarget picks the column to widen: the first one cut short in the
+// highlighted row from the horizontal scroll position on, the frozen columns
+// last, or else the column at the scroll position
+func (m Model) fullWidthTarget() string {
+	columns := m.popupResult.Columns
+	start := m.cellColumn()
+	row := m.popupTable.HighlightedRow().Data
+	cut := func(i int) bool {
+		styled, _ := row[columns[i]].(table.StyledCell)
+		cell, ok := styled.Data.(eztable.Cell)
+		return ok && cell.Cut
+	}
+	for i := start; i < len(columns); i++ {
+		if cut(i) {
+			return columns[i]
+		}
+	}
+	for i := range min(m.popupFrozen, start) {
+		if cut(i) {
+			return columns[i]
+		}
+	}
+	return columns[start]
+}
+
+// cellColumn returns the index of the result column at the horizontal
+// scroll position, the first one in view after the frozen columns
+func (m Model) cellColumn() int {
+	return max(min(m.popupTable.GetHorizontalScrollColumnOffset()+m.popupFrozen, len(m.popupResult.Columns)-1), 0)
+}
+
+// frozenColumns returns how many leading columns of a result read from
+// tableName stay in view while scrolling sideways: frozen_columns when set,
+// else the primary key columns the result starts with, at least one
+func (m Model) frozenColumns(tableName string, columns []string) int {
+	n := m.config.FrozenColumns
+	if n == 0 {
+		n = 1
+		if _, schema, ok := m.tableColumns(tableName); ok && tableName != "" {
+			keys := 0
+			for _, name := range columns {
+				if col, ok := findColumn(schema, name); !ok || col.Key != "PRI" {
+					break
+				}
+				keys++
+			}
+			n = max(keys, 1)
+		}
+	}
+	return min(max(n, 0), len(columns))
+}
+
+// entryTable returns the first table the query of entry reads from, or ""
+func entryTable(entry *history.HistoryEntry) string {
+	if entry == nil {
+		return ""
+	}
+	return queryTable(entry.Query)
+}
+
+// rebuildPopupTable builds the results table again after a display option
+// changed, keeping the highlighted row, the filter and the scroll position
+func (m *Model) rebuildPopupTable() {
+	if m.popupResult == nil {
+		return
+	}
+	row := m.popupTable.GetHighlightedRowIndex()
+	scrolled := m.popupTable.GetHorizontalScrollColumnOffset()
+	if !m.setPopupWindow(m.popupResult, m.popupOffset) {
+		return
+	}
+	m.popupTable = m.popupTable.WithFilterInputValue(m.tableFilterInput.Value()).WithHighlightedRow(row)
+	for range scrolled {
+		m.popupTable = m.popupTable.ScrollRight()
+	}
+}
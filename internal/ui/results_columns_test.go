@@ -0,0 +1,36 @@
+// internal/ui/results_columns_test.go
+package ui
+
+import (
+	"testing"
+
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/db"
+)
+
+func TestFrozenColumns(t *testing.T) {
+	m := NewModel(config.DefaultConfig(), nil, nil, nil)
+	m.columns["public.order_items"] = []db.Column{{Name: "order_id", Key: "PRI"}, {Name: "line", Key: "PRI"}, {Name: "sku"}}
+
+	tests := []struct {
+		name    string
+		setting int
+		table   string
+		columns []string
+		want    int
+	}{
+		{"leading primary key", 0, "order_items", []string{"order_id", "line", "sku"}, 2},
+		{"key not leading", 0, "order_items", []string{"sku", "order_id"}, 1},
+		{"unknown table", 0, "invoices", []string{"id", "total"}, 1},
+		{"no table", 0, "", []string{"x"}, 1},
+		{"configured", 3, "invoices", []string{"a", "b", "c", "d"}, 3},
+		{"more than the columns", 5, "invoices", []string{"a", "b"}, 2},
+		{"none", -1, "order_items", []string{"order_id", "line"}, 0},
+	}
+	for _, tt := range tests {
+		m.config.FrozenColumns = tt.setting
+		if got := m.frozenColumns(tt.table, tt.columns); got != tt.want {
+			t.Errorf("%s: frozenColumns = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
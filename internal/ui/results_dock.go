@@ -0,0 +1,87 @@
+// internal/ui/results_dock.go
+// Results dock: SELECT results render in a pane below the editor instead of a popup.
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/history"
+)
+
+// resultsDocked reports whether SELECT results go to the dock
+func (m Model) resultsDocked() bool {
+	return m.config.ResultsLayout == "dock"
+}
+
+// dockShown reports whether the dock has a result to show
+func (m Model) dockShown() bool {
+	return m.resultsDocked() && m.dockResult != nil && m.appState == StateReady
+}
+
+// dockTableChrome is the table's border, header and pagination footer lines
+const dockTableChrome = 6
+
+// dockRows is the number of result rows shown in the dock
+func (m Model) dockRows() int {
+	return max(m.height/5, 3)
+}
+
+// setDockResult shows a result in the dock
+func (m *Model) setDockResult(entry *history.HistoryEntry, result *db.QueryResult) {
+	m.dockEntry = entry
+	m.dockResult = result
+	frozen := 1
+	if result != nil {
+		frozen = m.frozenColumns(entryTable(entry), result.Columns)
+	}
+	m.dockTable = m.resultTables().FromQueryResult(result, 0).
+		WithPageSize(m.dockRows()).
+		WithMinimumHeight(0).
+		WithHorizontalFreezeColumnCount(frozen).
+		Focused(false)
+}
+
+// expandDock opens the docked result in the full-screen results popup
+func (m *Model) expandDock() {
+	m.showResultsPopup(m.dockEntry, m.dockResult)
+}
+
+// dockHeight returns the dock height, 0 when hidden: a title line plus a fixed-height body
+func (m Model) dockHeight() int {
+	if !m.dockShown() {
+		return 0
+	}
+	return 1 + m.dockRows() + dockTableChrome
+}
+
+// renderDock renders the title line and a page of the last result
+func (m Model) renderDock() string {
+	if !m.dockShown() {
+		return ""
+	}
+
+	title := fmt.Sprintf(" Results: %d rows", m.dockResult.RowCount)
+	if m.dockEntry != nil {
+		title += fmt.Sprintf(" • %dms", m.dockEntry.DurationMs)
+	}
+	hint := fmt.Sprintf("%s: expand ", firstKey(m.config.Keys.ExpandDock, "ctrl+o"))
+	gap := max(m.width-lipgloss.Width(title)-lipgloss.Width(hint), 1)
+	header := lipgloss.NewStyle().
+		Background(m.theme.CardBg()).
+		Foreground(m.theme.AccentColor()).
+		Bold(true).
+		Render(title + strings.Repeat(" ", gap) + hint)
+
+	body := "(No results)"
+	if len(m.dockResult.Columns) > 0 {
+		body = m.dockTable.WithMaxTotalWidth(m.width - 2).View()
+	}
+	// Pad or clip to the height reported by dockHeight
+	bodyHeight := m.dockHeight() - 1
+	body = lipgloss.NewStyle().Height(bodyHeight).MaxHeight(bodyHeight).Render(body)
+	return lipgloss.JoinVertical(lipgloss.Left, header, body)
+}
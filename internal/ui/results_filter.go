@@ -0,0 +1,102 @@
+// internal/ui/results_filter.go
+// Database-side results filter: the typed filter becomes a WHERE clause around the query, which is re-run.
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// serverFilterQuery wraps query so only rows matching expr are returned.
+// expr is either "column op value" terms as in the data browser, or plain
+// text matched case-insensitively against every column.
+func serverFilterQuery(dt db.DriverType, query string, columns []string, expr string) (string, error) {
+	if dt == db.Cassandra {
+		return "", fmt.Errorf("filtering in the database is not supported by CQL")
+	}
+	// A trailing line comment would swallow the closing parenthesis
+	query = strings.TrimSpace(trimTrailingComments(query))
+
+	cols := make([]db.Column, len(columns))
+	for i, name := range columns {
+		cols[i] = db.Column{Name: name}
+	}
+
+	var where string
+	if isFilterTerms(expr) {
+		var err error
+		if where, err = parseBrowseFilter(dt, expr, cols); err != nil {
+			return "", err
+		}
+	} else {
+		textType := "TEXT"
+		switch dt {
+		case db.MySQL:
+			textType = "CHAR"
+		case db.BigQuery:
+			textType = "STRING"
+		}
+		// A substring position rather than LIKE, so % _ and \ in the text match themselves
+		position := "INSTR"
+		if dt == db.Postgres || dt == db.BigQuery {
+			position = "STRPOS"
+		}
+		needle := quoteLiteral(dt, strings.ToLower(strings.TrimSpace(expr)), "text")
+		conds := make([]string, len(columns))
+		for i, name := range columns {
+			conds[i] = fmt.Sprintf("%s(LOWER(CAST(%s AS %s)), %s) > 0", position, quoteIdent(dt, name), textType, needle)
+		}
+		where = strings.Join(conds, " OR ")
+	}
+	return fmt.Sprintf("SELECT * FROM (%s) AS ezdb_filtered WHERE %s", query, where), nil
+}
+
+// isFilterTerms reports whether every comma-separated part of expr is a "column op value" term
+func isFilterTerms(expr string) bool {
+	for _, part := range strings.Split(expr, ",") {
+		if !browseFilterTerm.MatchString(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// openServerFilter starts a database-side filter, seeded with any client-side filter text
+func (m *Model) openServerFilter() {
+	m.ensureInput(lazyTableFilter, &m.tableFilterInput, newTableFilterInput)
+	m.tableFilterActive = true
+	m.tableFilterServer = true
+	m.tableFilterInput.Prompt = "WHERE "
+	m.tableFilterInput.CursorEnd()
+	m.tableFilterInput.Focus()
+}
+
+// handleServerFilterKeys edits the database filter; enter re-runs the query with it
+func (m Model) handleServerFilterKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.tableFilterActive = false
+		m.tableFilterServer = false
+		m.tableFilterInput.Blur()
+		return m, nil, true
+	case tea.KeyEnter:
+		expr := strings.TrimSpace(m.tableFilterInput.Value())
+		if expr == "" || m.popupEntry == nil || m.popupResult == nil || m.driver == nil {
+			return m, nil, true
+		}
+		query, err := serverFilterQuery(m.driver.Type(), m.popupEntry.Query, m.popupResult.Columns, expr)
+		if err != nil {
+			m.errorMsg = err.Error()
+			return m, nil, true
+		}
+		m.closeTopPopup()
+		return m, m.confirmOrRun(query), true
+	}
+	var cmd tea.Cmd
+	m.tableFilterInput, cmd = m.tableFilterInput.Update(msg)
+	return m, cmd, true
+}
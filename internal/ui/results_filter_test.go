@@ -0,0 +1,61 @@
+// internal/ui/results_filter_test.go
+package ui
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+func TestServerFilterQuery(t *testing.T) {
+	cols := []string{"id", "name"}
+	got, err := serverFilterQuery(db.Postgres, "SELECT id, name FROM users;", cols, "id > 3")
+	if want := `SELECT * FROM (SELECT id, name FROM users) AS ezdb_filtered WHERE "id" > '3'`; err != nil || got != want {
+		t.Errorf("terms: got %s, %v; want %s", got, err, want)
+	}
+	got, _ = serverFilterQuery(db.MySQL, "SELECT * FROM users", cols, "Bob")
+	if want := "SELECT * FROM (SELECT * FROM users) AS ezdb_filtered WHERE " +
+		"INSTR(LOWER(CAST(`id` AS CHAR)), 'bob') > 0 OR INSTR(LOWER(CAST(`name` AS CHAR)), 'bob') > 0"; got != want {
+		t.Errorf("text: got %s, want %s", got, want)
+	}
+	got, _ = serverFilterQuery(db.Postgres, "SELECT * FROM users; -- all of them", cols, "id = 1")
+	if want := `SELECT * FROM (SELECT * FROM users) AS ezdb_filtered WHERE "id" = '1'`; got != want {
+		t.Errorf("trailing comment: got %s, want %s", got, want)
+	}
+	got, _ = serverFilterQuery(db.Postgres, "SELECT * FROM users", []string{"name"}, "50%_off")
+	if want := `SELECT * FROM (SELECT * FROM users) AS ezdb_filtered WHERE STRPOS(LOWER(CAST("name" AS TEXT)), '50%_off') > 0`; got != want {
+		t.Errorf("wildcards: got %s, want %s", got, want)
+	}
+}
+
+func TestServerFilterQueryLiteralText(t *testing.T) {
+	driver := &db.SQLiteDriver{}
+	if err := driver.Connect(db.ConnectParams{Database: filepath.Join(t.TempDir(), "test.db")}); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer driver.Close()
+	ctx := context.Background()
+	for _, stmt := range []string{
+		"CREATE TABLE deals (name TEXT)",
+		`INSERT INTO deals VALUES ('50% off'), ('500 off'), ('a_b'), ('axb'), ('c\d')`,
+	} {
+		if _, err := driver.Execute(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	for text, want := range map[string]string{"50%": "50% off", "a_b": "a_b", `c\d`: `c\d`} {
+		query, err := serverFilterQuery(db.SQLite, "SELECT name FROM deals", []string{"name"}, text)
+		if err != nil {
+			t.Fatalf("%s: %v", text, err)
+		}
+		result, err := driver.Execute(ctx, query)
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		if len(result.Rows) != 1 || result.Rows[0][0] != want {
+			t.Errorf("filter %q matched %v, want only %q", text, result.Rows, want)
+		}
+	}
+}
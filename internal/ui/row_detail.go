@@ -0,0 +1,139 @@
+// internal/ui/row_detail.go
+// Row detail panel: every column of the highlighted result row beside the results, each value copyable, leaving the editor alone.
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+)
+
+// rowField is one column of the row shown in the row detail panel
+type rowField struct {
+	Name  string
+	Value string
+}
+
+// rowDetailValueLines caps the lines the selected value wraps to under the field list
+const rowDetailValueLines = 8
+
+// openRowDetail shows the highlighted result row in the row detail panel
+func (m *Model) openRowDetail() {
+	row := m.popupTable.HighlightedRow().Data
+	if row == nil || m.popupResult == nil || m.showRowDetail {
+		return
+	}
+	m.rowDetail = make([]rowField, 0, len(m.popupResult.Columns))
+	for _, col := range m.popupResult.Columns {
+		if val, ok := row[col]; ok {
+			m.rowDetail = append(m.rowDetail, rowField{Name: col, Value: cellText(val)})
+		}
+	}
+	m.rowDetailRow = m.popupOffset + m.popupTable.GetHighlightedRowIndex() + 1
+	m.rowDetailIdx = 0
+	m.showRowDetail = true
+	m.popupStack.Push("rowDetail", func(m *Model) {
+		m.showRowDetail = false
+		m.rowDetail = nil
+	})
+}
+
+// handleRowDetailKeys moves between the fields of the row detail panel and
+// copies their values
+func (m Model) handleRowDetailKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc", "q":
+		m.closeTopPopup()
+	case "up", "k":
+		if m.rowDetailIdx > 0 {
+			m.rowDetailIdx--
+		}
+	case "down", "j":
+		if m.rowDetailIdx < len(m.rowDetail)-1 {
+			m.rowDetailIdx++
+		}
+	case "g", "home":
+		m.rowDetailIdx = 0
+	case "G", "end":
+		m.rowDetailIdx = max(len(m.rowDetail)-1, 0)
+	case "y", "enter":
+		if m.rowDetailIdx < len(m.rowDetail) {
+			return m, m.copyToClipboardCmd(m.rowDetail[m.rowDetailIdx].Value), true
+		}
+	case "Y":
+		lines := make([]string, len(m.rowDetail))
+		for i, f := range m.rowDetail {
+			lines[i] = f.Name + ": " + f.Value
+		}
+		return m, m.copyToClipboardCmd(strings.Join(lines, "\n")), true
+	}
+	return m, nil, true
+}
+
+// renderRowDetail draws the row detail panel over the right side of main
+func (m Model) renderRowDetail(main string) string {
+	panelWidth := min(max(m.width/2, 40), m.width-4)
+	inner := m.popupInnerWidth(panelWidth)
+	faint := lipgloss.NewStyle().Faint(true)
+
+	var content strings.Builder
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).
+		Render(fmt.Sprintf("Row %d of %d", m.rowDetailRow, m.popupResult.RowCount)))
+	content.WriteString("\n\n")
+
+	nameWidth := 0
+	for _, f := range m.rowDetail {
+		nameWidth = max(nameWidth, lipgloss.Width(f.Name))
+	}
+	nameWidth = min(nameWidth, 24)
+	valueWidth := max(inner-nameWidth-4, 10)
+
+	// Keep the selection in view when the row has more columns than fit
+	visible := max(m.height-rowDetailValueLines-14, 3)
+	start := 0
+	if m.rowDetailIdx >= visible {
+		start = m.rowDetailIdx - visible + 1
+	}
+	for i := start; i < min(start+visible, len(m.rowDetail)); i++ {
+		f := m.rowDetail[i]
+		nameStyle := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
+		prefix := "  "
+		if i == m.rowDetailIdx {
+			nameStyle = lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Bold(true)
+			prefix = "> "
+		}
+		value := truncateEnd(strings.Join(strings.Fields(f.Value), " "), valueWidth)
+		valueStyle := lipgloss.NewStyle().Foreground(m.theme.TextPrimary())
+		if f.Value == "NULL" || f.Value == "" {
+			valueStyle = faint.Italic(true)
+		}
+		content.WriteString(prefix + nameStyle.Render(padRight(limitString(f.Name, nameWidth), nameWidth)) + "  " + valueStyle.Render(value) + "\n")
+	}
+	if len(m.rowDetail) > visible {
+		content.WriteString(faint.Render(fmt.Sprintf("  %d of %d columns", m.rowDetailIdx+1, len(m.rowDetail))) + "\n")
+	}
+
+	// The selected value whole, wrapped to the panel
+	if m.rowDetailIdx < len(m.rowDetail) {
+		f := m.rowDetail[m.rowDetailIdx]
+		content.WriteString("\n" + faint.Render(f.Name) + "\n")
+		wrapped := strings.Split(lipgloss.NewStyle().Width(inner).Render(f.Value), "\n")
+		if len(wrapped) > rowDetailValueLines {
+			wrapped = append(wrapped[:rowDetailValueLines-1], faint.Render(fmt.Sprintf("… %d more lines, y copies it whole", len(wrapped)-rowDetailValueLines+1)))
+		}
+		content.WriteString(strings.Join(wrapped, "\n") + "\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(m.renderHintLine("y: copy value • Y: copy row • ↑/↓: field • Esc: close", inner))
+
+	panel := m.theme.PopupStyle.
+		Width(panelWidth).
+		MaxHeight(m.height - 2).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+	return overlay.Composite(panel, main, overlay.Right, overlay.Center, -1, 0)
+}
@@ -0,0 +1,110 @@
+// internal/ui/row_statements.go
+// Row actions that rebuild the highlighted result row as an INSERT or UPDATE statement.
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/evertras/bubble-table/table"
+
+	"github.com/nhath/ezdb/internal/db"
+	eztable "github.com/nhath/ezdb/internal/ui/components/table"
+)
+
+// cellText returns the raw text of a result table cell
+func cellText(val interface{}) string {
+	if cell, ok := val.(table.StyledCell); ok {
+		val = cell.Data
+	}
+	if c, ok := val.(eztable.Cell); ok {
+		return c.Raw
+	}
+	return fmt.Sprint(val)
+}
+
+// sqlValue renders a result value as a literal for col; "NULL" cells stay NULL
+func sqlValue(dt db.DriverType, val string, col db.Column) string {
+	if val == "NULL" {
+		return "NULL"
+	}
+	return quoteLiteral(dt, val, col.Type)
+}
+
+// insertStatement builds an INSERT of one row; columns may lack type information
+func insertStatement(dt db.DriverType, tableName string, columns []db.Column, values []string) string {
+	names := make([]string, len(columns))
+	literals := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = quoteIdent(dt, col.Name)
+		literals[i] = sqlValue(dt, values[i], col)
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);",
+		tableName, strings.Join(names, ", "), strings.Join(literals, ", "))
+}
+
+// updateStatement builds an UPDATE of one row that sets every non-key
+// column and matches the row by its primary key
+func updateStatement(dt db.DriverType, tableName string, columns []db.Column, values []string) (string, error) {
+	var sets, where []string
+	for i, col := range columns {
+		ident := quoteIdent(dt, col.Name)
+		if col.Key == "PRI" {
+			if values[i] == "NULL" {
+				return "", fmt.Errorf("primary key %s is NULL", col.Name)
+			}
+			where = append(where, ident+" = "+sqlValue(dt, values[i], col))
+		} else {
+			sets = append(sets, ident+" = "+sqlValue(dt, values[i], col))
+		}
+	}
+	if len(where) == 0 {
+		return "", fmt.Errorf("no primary key column in the results of %s", tableName)
+	}
+	if len(sets) == 0 {
+		return "", fmt.Errorf("only primary key columns in the results of %s", tableName)
+	}
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s;",
+		tableName, strings.Join(sets, ", "), strings.Join(where, " AND ")), nil
+}
+
+// highlightedRowColumns pairs the highlighted row's values with the table's
+// column metadata, falling back to bare names for columns not in the schema
+func (m Model) highlightedRowColumns(schema []db.Column) ([]db.Column, []string) {
+	data := m.popupTable.HighlightedRow().Data
+	columns := make([]db.Column, len(m.popupResult.Columns))
+	values := make([]string, len(m.popupResult.Columns))
+	for i, name := range m.popupResult.Columns {
+		columns[i] = db.Column{Name: name}
+		if col, ok := findColumn(schema, name); ok {
+			columns[i] = col
+		}
+		values[i] = cellText(data[name])
+	}
+	return columns, values
+}
+
+// copyRowAsStatement copies the highlighted row as an INSERT, or as an UPDATE when update is set
+func (m Model) copyRowAsStatement(update bool) (Model, tea.Cmd) {
+	if m.popupResult == nil || m.popupTable.HighlightedRow().Data == nil || m.driver == nil {
+		return m, nil
+	}
+
+	tableName, schema, err := m.resultTable()
+	// An INSERT can do without column metadata; an UPDATE needs the primary key
+	if tableName == "" || (err != nil && update) {
+		m.errorMsg = err.Error()
+		return m, nil
+	}
+	columns, values := m.highlightedRowColumns(schema)
+
+	stmt := insertStatement(m.driver.Type(), tableName, columns, values)
+	if update {
+		if stmt, err = updateStatement(m.driver.Type(), tableName, columns, values); err != nil {
+			m.errorMsg = err.Error()
+			return m, nil
+		}
+	}
+	return m, m.copyToClipboardCmd(stmt)
+}
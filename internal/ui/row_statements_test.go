@@ -0,0 +1,33 @@
+// internal/ui/row_statements_test.go
+package ui
+
+import (
+	"testing"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+func TestRowStatements(t *testing.T) {
+	columns := []db.Column{
+		{Name: "id", Type: "integer", Key: "PRI"},
+		{Name: "name", Type: "text"},
+		{Name: "active", Type: "boolean"},
+		{Name: "note", Type: "text"},
+	}
+	values := []string{"7", "O'Hara", "true", "NULL"}
+
+	want := `INSERT INTO users ("id", "name", "active", "note") VALUES (7, 'O''Hara', true, NULL);`
+	if got := insertStatement(db.Postgres, "users", columns, values); got != want {
+		t.Errorf("insert =\n%s\nwant\n%s", got, want)
+	}
+
+	want = "UPDATE users SET `name` = 'O\\'Hara', `active` = true, `note` = NULL WHERE `id` = 7;"
+	got, err := updateStatement(db.MySQL, "users", columns, values)
+	if err != nil || got != want {
+		t.Errorf("update =\n%s, %v\nwant\n%s", got, err, want)
+	}
+
+	if _, err := updateStatement(db.Postgres, "users", columns[1:], values[1:]); err == nil {
+		t.Error("update without a primary key succeeded")
+	}
+}
@@ -0,0 +1,131 @@
+// internal/ui/run_file.go
+// Script runner for .sql files: runs statements in order, recording each in history.
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/ui/components/schemabrowser"
+)
+
+// loadScriptCmd reads a file for /run
+func loadScriptCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		content, err := os.ReadFile(path)
+		return ScriptLoadedMsg{Path: path, Content: string(content), Err: err}
+	}
+}
+
+// handleScriptLoaded checks a loaded file against the profile's query guards
+// before running it. A blocked statement stops the whole file; warnings, and
+// in strict mode any write, are confirmed once for the file.
+func (m Model) handleScriptLoaded(msg ScriptLoadedMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	}
+	if m.driver == nil {
+		m.errorMsg = "No database connection"
+		return m, nil
+	}
+	var guards config.QueryGuards
+	if m.profile != nil {
+		guards = m.profile.Guards
+	}
+	var warnings []string
+	for _, v := range checkGuards(msg.Content, guards) {
+		if v.policy == config.GuardBlock {
+			m.errorMsg = fmt.Sprintf("Blocked by query guard: %s: %s", filepath.Base(msg.Path), v.reason)
+			return m, nil
+		}
+		warnings = append(warnings, v.reason)
+	}
+	strict := m.strictMode && isModifyingQuery(msg.Content)
+	if !strict && len(warnings) == 0 {
+		return m, m.runSQLFileCmd(msg.Path, msg.Content)
+	}
+	m.confirming = true
+	m.pendingQuery = msg.Content
+	m.pendingScript = msg.Path
+	m.pendingCost = ""
+	m.pendingWarnings = warnings
+	m.pendingStrict = strict
+	return m, nil
+}
+
+// runSQLFileCmd runs a file's content statement by statement, stopping at the first failure
+func (m Model) runSQLFileCmd(path, content string) tea.Cmd {
+	return runTransferOf("Run", "statements", func(ctx context.Context, report transferReporter) tea.Msg {
+		if m.driver == nil {
+			return ScriptRunMsg{Path: path, Err: fmt.Errorf("no database connection")}
+		}
+
+		statements := splitStatements(content)
+		done := ScriptRunMsg{Path: path, Total: len(statements)}
+		started := time.Now()
+		for i, stmt := range statements {
+			if err := ctx.Err(); err != nil {
+				done.Err = err
+				break
+			}
+			start := time.Now()
+			result, err := m.execute(ctx, stmt)
+			done.Entries = append(done.Entries, m.recordHistory(stmt, start, result, err))
+			if err != nil {
+				done.Err = err
+				break
+			}
+			report(i+1, len(statements), 0)
+		}
+		done.Duration = time.Since(started)
+		return done
+	})
+}
+
+// handleScriptRun adds the run's statements to history and summarizes it.
+// The schema is reloaded since scripts usually change it.
+func (m Model) handleScriptRun(msg ScriptRunMsg) (Model, tea.Cmd) {
+	m = m.endTransfer()
+	for _, entry := range msg.Entries {
+		m.inTransaction = transactionState(entry.Query, m.inTransaction)
+		m.history = append(m.history, *entry)
+	}
+	if len(msg.Entries) > 0 {
+		m.selected = len(m.history) - 1
+		m.expandedID = 0
+	}
+
+	name := filepath.Base(msg.Path)
+	ok := len(msg.Entries)
+	if ok > 0 && msg.Entries[ok-1].Status == "error" {
+		ok--
+	}
+	switch {
+	case msg.Err == nil:
+		m.errorMsg = ""
+		m.statusMsg = fmt.Sprintf("Ran %d statements from %s in %s", msg.Total, name, msg.Duration.Round(time.Millisecond))
+	case errors.Is(msg.Err, context.Canceled):
+		m.errorMsg = fmt.Sprintf("%s: run cancelled after %d of %d statements", name, ok, msg.Total)
+	case ok < len(msg.Entries):
+		m.errorMsg = fmt.Sprintf("%s: statement %d of %d failed (%d ran): %v", name, ok+1, msg.Total, ok, msg.Err)
+	default:
+		m.errorMsg = fmt.Sprintf("%s: %v", name, msg.Err)
+	}
+
+	m = m.updateHistoryViewport()
+	m.historyList = m.historyList.GotoBottom()
+	m = m.ensureSelectionVisible()
+	if ok == 0 || m.driver == nil {
+		return m, nil
+	}
+	m.loadingTables = true
+	return m, schemabrowser.LoadSchemaCmd(m.driver)
+}
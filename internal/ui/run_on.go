@@ -0,0 +1,206 @@
+// internal/ui/run_on.go
+// Run on another profile: re-run a history entry against a second connection, e.g. prod vs staging.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/history"
+)
+
+// runOnTargets returns the profiles a history entry can be re-run on
+func (m Model) runOnTargets() []config.Profile {
+	var targets []config.Profile
+	for _, p := range m.config.Profiles {
+		if m.profile == nil || p.Name != m.profile.Name {
+			targets = append(targets, p)
+		}
+	}
+	return targets
+}
+
+// openRunOnPopup opens the profile picker for re-running entry.
+func (m *Model) openRunOnPopup(entry history.HistoryEntry) {
+	if m.showRunOn {
+		return
+	}
+	m.showRunOn = true
+	m.autocompleting = false
+	m.runOnEntry = entry
+	m.runOnIdx = 0
+	m.runOnWarnings = nil
+	m.popupStack.Push("run on", func(m *Model) {
+		m.showRunOn = false
+		m.runOnWarnings = nil
+	})
+}
+
+// handleRunOnKeys picks the target profile, then asks about guard warnings
+// of that profile before running
+func (m Model) handleRunOnKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	targets := m.runOnTargets()
+	if m.runOnWarnings != nil {
+		if msg.String() == "y" || msg.String() == "Y" {
+			target := targets[m.runOnIdx]
+			m.closeTopPopup()
+			return m, m.startRunOn(target), true
+		}
+		m.runOnWarnings = nil
+		return m, nil, true
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.closeTopPopup()
+	case "up", "k":
+		if m.runOnIdx > 0 {
+			m.runOnIdx--
+		}
+	case "down", "j":
+		if m.runOnIdx < len(targets)-1 {
+			m.runOnIdx++
+		}
+	case "enter":
+		if m.runOnIdx >= len(targets) {
+			return m, nil, true
+		}
+		target := targets[m.runOnIdx]
+		query := m.runOnEntry.Query
+		var warnings []string
+		for _, v := range checkGuards(query, target.Guards) {
+			if v.policy == config.GuardBlock {
+				m.closeTopPopup()
+				m.errorMsg = fmt.Sprintf("Blocked by query guard of %s: %s", target.Name, v.reason)
+				return m, nil, true
+			}
+			warnings = append(warnings, v.reason)
+		}
+		if m.strictMode && isModifyingQuery(query) {
+			warnings = append(warnings, "Strict mode: this query modifies data")
+		}
+		if len(warnings) > 0 {
+			m.runOnWarnings = warnings
+			return m, nil, true
+		}
+		m.closeTopPopup()
+		return m, m.startRunOn(target), true
+	}
+	return m, nil, true
+}
+
+// startRunOn marks the model as loading and runs the picked entry on target
+func (m *Model) startRunOn(target config.Profile) tea.Cmd {
+	if m.loading {
+		m.errorMsg = "Wait for the running query first"
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	m.loading = true
+	m.cancelQuery = cancel
+	m.statusMsg = "Running on " + target.Name + "..."
+	return m.runOnCmd(m.queryContext(ctx), cancel, m.runOnEntry.Query, target, m.runOnDrivers[target.Name])
+}
+
+// runOnCmd executes query on target, connecting first unless driver is an
+// open connection to it, and records it in this profile's history
+func (m Model) runOnCmd(ctx context.Context, cancel context.CancelFunc, query string, target config.Profile, driver db.Driver) tea.Cmd {
+	return func() tea.Msg {
+		defer cancel()
+		done := RunOnResultMsg{Profile: target.Name}
+		if driver == nil {
+			var err error
+			if driver, err = m.openProfile(target); err != nil {
+				done.Err = fmt.Errorf("connecting to %s: %w", target.Name, err)
+				return done
+			}
+			done.Driver = driver
+		}
+		start := time.Now()
+		result, err := m.executeOn(ctx, driver, &target, query)
+		done.Result = result
+		done.Entry = m.recordHistoryOn(target.Name, query, start, result, err)
+		done.Err = err
+		return done
+	}
+}
+
+// handleRunOnResult keeps a new connection for later runs and shows the
+// result like one of the connected profile
+func (m Model) handleRunOnResult(msg RunOnResultMsg) (Model, tea.Cmd) {
+	if msg.Driver != nil {
+		if m.runOnDrivers == nil {
+			m.runOnDrivers = map[string]db.Driver{}
+		}
+		m.runOnDrivers[msg.Profile] = msg.Driver
+	}
+	m.statusMsg = ""
+	if msg.Entry == nil {
+		m.loading = false
+		m.cancelQuery = nil
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	}
+	m, cmd := m.handleQueryResult(QueryResultMsg{Result: msg.Result, Entry: msg.Entry, Err: msg.Err})
+	if msg.Err == nil {
+		m.statusMsg = "Ran on " + msg.Profile
+	}
+	return m, cmd
+}
+
+func (m Model) renderRunOnPopup(main string) string {
+	var content strings.Builder
+	faint := lipgloss.NewStyle().Faint(true)
+	popupWidth := min(70, m.width-10)
+
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Run on..."))
+	content.WriteString("\n\n")
+	content.WriteString(faint.Render(limitString(strings.Join(strings.Fields(m.runOnEntry.Query), " "), popupWidth-4)))
+	content.WriteString("\n\n")
+
+	targets := m.runOnTargets()
+	if len(targets) == 0 {
+		content.WriteString(faint.Render("  (no other profiles)"))
+		content.WriteString("\n")
+	}
+	for i, p := range targets {
+		style := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
+		prefix := "  "
+		if i == m.runOnIdx {
+			style = lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Bold(true)
+			prefix = "> "
+		}
+		line := prefix + style.Render(limitString(p.Name, 30)) + faint.Render("  "+p.Type)
+		if m.runOnDrivers[p.Name] != nil {
+			line += faint.Render(" (connected)")
+		}
+		content.WriteString(line + "\n")
+	}
+	content.WriteString("\n")
+
+	if m.runOnWarnings != nil {
+		warn := lipgloss.NewStyle().Foreground(m.theme.WarningColor()).Bold(true)
+		for _, w := range m.runOnWarnings {
+			content.WriteString(warn.Render("! "+w) + "\n")
+		}
+		content.WriteString(warn.Render(fmt.Sprintf("Run on %s anyway? (y/n)", targets[m.runOnIdx].Name)))
+	} else {
+		content.WriteString(m.renderHintLine("Enter: run • ↑/↓: select • Esc: cancel", m.popupInnerWidth(popupWidth)))
+	}
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
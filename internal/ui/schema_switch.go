@@ -0,0 +1,189 @@
+// internal/ui/schema_switch.go
+// Schema switcher: change the PostgreSQL search_path schema or the MySQL database without editing the profile.
+package ui
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/ui/components/schemabrowser"
+)
+
+// openSchemaSwitch opens the schema switcher popup.
+func (m *Model) openSchemaSwitch() {
+	if m.showSchemaSwitch {
+		return
+	}
+	m.showSchemaSwitch = true
+	m.autocompleting = false
+	m.ensureInput(lazySchema, &m.schemaInput, newSchemaInput)
+	m.schemaInput.SetValue("")
+	m.schemaInput.Focus()
+	m.schemaIdx = max(slices.Index(m.schemas, m.currentSchema), 0)
+	m.popupStack.Push("schema", func(m *Model) {
+		m.showSchemaSwitch = false
+		m.schemaInput.Blur()
+	})
+}
+
+// handleSchemaSwitchKeys handles keys while the schema switcher is open
+func (m Model) handleSchemaSwitchKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switcher, ok := m.driver.(db.SchemaSwitcher)
+	if !ok {
+		m.closeTopPopup()
+		return m, nil, true
+	}
+
+	filtered := m.filteredSchemas()
+	switch msg.String() {
+	case "esc":
+		m.closeTopPopup()
+		return m, nil, true
+	case "up":
+		if m.schemaIdx > 0 {
+			m.schemaIdx--
+		}
+		return m, nil, true
+	case "down":
+		if m.schemaIdx < len(filtered)-1 {
+			m.schemaIdx++
+		}
+		return m, nil, true
+	case "enter":
+		if m.schemaIdx >= len(filtered) {
+			return m, nil, true
+		}
+		m.closeTopPopup()
+		if m.loading {
+			m.errorMsg = "Wait for the running query before switching schema"
+			return m, nil, true
+		}
+		if filtered[m.schemaIdx] == m.currentSchema {
+			return m, nil, true
+		}
+		return m, switchSchemaCmd(switcher, filtered[m.schemaIdx]), true
+	}
+
+	var cmd tea.Cmd
+	m.schemaInput, cmd = m.schemaInput.Update(msg)
+	m.schemaIdx = min(m.schemaIdx, max(len(m.filteredSchemas())-1, 0))
+	return m, cmd, true
+}
+
+// filteredSchemas returns the schemas matching the switcher's filter
+func (m Model) filteredSchemas() []string {
+	filter := strings.ToLower(strings.TrimSpace(m.schemaInput.Value()))
+	if filter == "" {
+		return m.schemas
+	}
+	var matches []string
+	for _, s := range m.schemas {
+		if strings.Contains(strings.ToLower(s), filter) {
+			matches = append(matches, s)
+		}
+	}
+	return matches
+}
+
+// handleSchemas applies a schema listing or switch and reloads the schema if it changed
+func (m Model) handleSchemas(msg SchemasMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	}
+	m.schemas = msg.Schemas
+	m.currentSchema = msg.Current
+	if msg.Status != "" {
+		m.statusMsg = msg.Status
+	}
+	if msg.Changed && m.driver != nil {
+		m.loadingTables = true
+		return m, schemabrowser.LoadSchemaCmd(m.driver)
+	}
+	return m, nil
+}
+
+func listSchemasCmd(switcher db.SchemaSwitcher) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return listSchemas(ctx, switcher, "", false)
+	}
+}
+
+func switchSchemaCmd(switcher db.SchemaSwitcher, schema string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := switcher.SwitchSchema(ctx, schema); err != nil {
+			return SchemasMsg{Err: err}
+		}
+		return listSchemas(ctx, switcher, "Switched to "+schema, true)
+	}
+}
+
+func listSchemas(ctx context.Context, switcher db.SchemaSwitcher, status string, changed bool) SchemasMsg {
+	schemas, err := switcher.Schemas(ctx)
+	if err != nil {
+		return SchemasMsg{Err: err}
+	}
+	current, err := switcher.CurrentSchema(ctx)
+	return SchemasMsg{Schemas: schemas, Current: current, Status: status, Changed: changed, Err: err}
+}
+
+func (m Model) renderSchemaSwitch(main string) string {
+	var content strings.Builder
+	popupWidth := 56
+	if popupWidth > m.width-10 {
+		popupWidth = m.width - 10
+	}
+
+	title := "Switch Schema"
+	if m.driver != nil && m.driver.Type() == db.MySQL {
+		title = "Switch Database"
+	}
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render(title))
+	content.WriteString("\n\n")
+	content.WriteString(m.schemaInput.View())
+	content.WriteString("\n\n")
+
+	filtered := m.filteredSchemas()
+	if len(filtered) == 0 {
+		content.WriteString(lipgloss.NewStyle().Faint(true).Render("  (none)"))
+		content.WriteString("\n")
+	}
+	// Keep the selection in a window of at most 12 rows
+	const visible = 12
+	start := max(0, min(m.schemaIdx-visible/2, len(filtered)-visible))
+	for i := start; i < len(filtered) && i < start+visible; i++ {
+		style := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
+		prefix := "  "
+		if i == m.schemaIdx {
+			style = lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Bold(true)
+			prefix = "> "
+		}
+		line := prefix + style.Render(limitString(filtered[i], 40))
+		if filtered[i] == m.currentSchema {
+			line += lipgloss.NewStyle().Faint(true).Render("  (current)")
+		}
+		content.WriteString(line + "\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(m.renderHintLine("Enter: switch • ↑/↓: select • Esc: close", m.popupInnerWidth(popupWidth)))
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
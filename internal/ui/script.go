@@ -0,0 +1,175 @@
+// internal/ui/script.go
+// Headless scripting: drives the model's Update loop with keys and messages and reads back the rendered screen, for end-to-end tests.
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxScriptSteps bounds the messages one Send may process, so commands
+// that keep rescheduling themselves cannot loop forever
+const maxScriptSteps = 200
+
+// Script runs a Model without a terminal. Each step feeds a message through
+// Update and then runs the commands it returns, feeding their messages back
+// in, until nothing is left to do. Commands that take longer than Wait,
+// such as cursor blinks and polling ticks, are dropped.
+type Script struct {
+	Wait time.Duration
+
+	model Model
+	quit  bool
+}
+
+// NewScript starts m at the given terminal size, running its Init commands
+func NewScript(m Model, width, height int) *Script {
+	s := &Script{Wait: 100 * time.Millisecond, model: m}
+	s.drain(s.run(m.Init()))
+	return s.Send(tea.WindowSizeMsg{Width: width, Height: height})
+}
+
+// Send feeds msg to the model and runs what follows from it
+func (s *Script) Send(msg tea.Msg) *Script {
+	s.drain([]tea.Msg{msg})
+	return s
+}
+
+// Keys presses keys in order, named as in the keybindings, e.g. "i",
+// "ctrl+d", "esc", "shift+tab" or "alt+enter"
+func (s *Script) Keys(keys ...string) *Script {
+	for _, k := range keys {
+		s.Send(scriptKey(k))
+	}
+	return s
+}
+
+// Type enters text as one key event, the way fast typing arrives
+func (s *Script) Type(text string) *Script {
+	return s.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+}
+
+// Model returns the model as of the last step
+func (s *Script) Model() Model {
+	return s.model
+}
+
+// View returns the rendered screen, styles included
+func (s *Script) View() string {
+	return s.model.View()
+}
+
+// Screen returns the rendered screen as plain text
+func (s *Script) Screen() string {
+	return ansi.Strip(s.model.View())
+}
+
+// Quit reports whether the model asked the program to exit
+func (s *Script) Quit() bool {
+	return s.quit
+}
+
+// drain processes queued messages and the messages their commands produce
+func (s *Script) drain(queue []tea.Msg) {
+	for step := 0; len(queue) > 0 && step < maxScriptSteps && !s.quit; step++ {
+		msg := queue[0]
+		queue = queue[1:]
+		if _, ok := msg.(tea.QuitMsg); ok {
+			s.quit = true
+			return
+		}
+		// tea.Batch and tea.Sequence arrive as lists of commands
+		if cmds, ok := commandList(msg); ok {
+			queue = append(queue, s.run(cmds...)...)
+			continue
+		}
+		next, cmd := s.model.Update(msg)
+		s.model = next.(Model)
+		queue = append(queue, s.run(cmd)...)
+	}
+}
+
+// run runs cmds concurrently and returns the messages of those finishing
+// within Wait, in command order
+func (s *Script) run(cmds ...tea.Cmd) []tea.Msg {
+	results := make([]chan tea.Msg, len(cmds))
+	for i, cmd := range cmds {
+		if cmd == nil {
+			continue
+		}
+		results[i] = make(chan tea.Msg, 1) // Buffered so abandoned commands can finish
+		go func(cmd tea.Cmd, out chan<- tea.Msg) { out <- cmd() }(cmd, results[i])
+	}
+
+	deadline := time.After(s.Wait)
+	expired := false
+	var msgs []tea.Msg
+	for _, out := range results {
+		if out == nil {
+			continue
+		}
+		var msg tea.Msg
+		if !expired {
+			select {
+			case msg = <-out:
+			case <-deadline:
+				expired = true
+			}
+		}
+		if expired {
+			// Past the deadline, only take what has already finished
+			select {
+			case msg = <-out:
+			default:
+			}
+		}
+		if msg != nil {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// commandList returns the commands of a batch or sequence message
+func commandList(msg tea.Msg) ([]tea.Cmd, bool) {
+	v := reflect.ValueOf(msg)
+	cmdType := reflect.TypeOf((tea.Cmd)(nil))
+	if !v.IsValid() || v.Kind() != reflect.Slice || v.Type().Elem() != cmdType {
+		return nil, false
+	}
+	cmds := make([]tea.Cmd, v.Len())
+	for i := range cmds {
+		cmds[i], _ = v.Index(i).Interface().(tea.Cmd)
+	}
+	return cmds, true
+}
+
+// keyTypes maps key names such as "enter" or "ctrl+c" to their key type
+var keyTypes = func() map[string]tea.KeyType {
+	types := map[string]tea.KeyType{"space": tea.KeySpace}
+	for k := tea.KeyType(-128); k < 128; k++ {
+		if name := k.String(); name != "" && k != tea.KeyRunes {
+			types[name] = k
+		}
+	}
+	return types
+}()
+
+// scriptKey returns the key event for a key name
+func scriptKey(name string) tea.KeyMsg {
+	var msg tea.KeyMsg
+	if rest, ok := strings.CutPrefix(name, "alt+"); ok && rest != "" {
+		msg.Alt = true
+		name = rest
+	}
+	if k, ok := keyTypes[name]; ok {
+		msg.Type = k
+	} else {
+		msg.Type, msg.Runes = tea.KeyRunes, []rune(name)
+	}
+	return msg
+}
@@ -0,0 +1,694 @@
+// internal/ui/script_test.go
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrg/xdg"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/evertras/bubble-table/table"
+
+	"github.com/nhath/ezdb/internal/audit"
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/history"
+	"github.com/nhath/ezdb/internal/ui/components/schemabrowser"
+)
+
+// scriptModel returns a script of a model connected to a SQLite database
+// holding one row in items
+func scriptModel(t *testing.T) (*Script, db.Driver) {
+	t.Helper()
+	dir := t.TempDir()
+	driver := &db.SQLiteDriver{}
+	if err := driver.Connect(db.ConnectParams{Database: filepath.Join(dir, "test.db")}); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { driver.Close() })
+	ctx := context.Background()
+	for _, stmt := range []string{"CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)", "INSERT INTO items VALUES (1, 'widget')"} {
+		if _, err := driver.Execute(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	store, err := history.OpenStore(filepath.Join(dir, "history.db"))
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	profile := &config.Profile{Name: "test", Type: "sqlite", Database: filepath.Join(dir, "test.db")}
+	m := NewModel(config.DefaultConfig(), profile, driver, store)
+	return NewScript(m, 120, 40), driver
+}
+
+func TestScriptStrictModeConfirm(t *testing.T) {
+	t.Parallel()
+	s, driver := scriptModel(t)
+
+	s.Keys("m", "i").Type("DELETE FROM items").Keys("ctrl+d")
+	if !strings.Contains(s.Screen(), "CONFIRM DESTRUCTIVE ACTION") {
+		t.Fatalf("strict mode ran the delete without asking:\n%s", s.Screen())
+	}
+	s.Keys("n")
+	if strings.Contains(s.Screen(), "CONFIRM DESTRUCTIVE ACTION") {
+		t.Error("confirmation still shown after n")
+	}
+	res, err := driver.Execute(context.Background(), "SELECT COUNT(*) FROM items")
+	if err != nil || res.Rows[0][0] != "1" {
+		t.Errorf("items after declining = %v, %v, want 1 row", res, err)
+	}
+}
+
+func TestScriptPopupStack(t *testing.T) {
+	t.Parallel()
+	s, _ := scriptModel(t)
+
+	s.Keys("i").Type("SELECT name FROM items").Keys("ctrl+d")
+	if !strings.Contains(s.Screen(), "widget") || s.Model().popupStack.TopName() != "results" {
+		t.Fatalf("results popup not open, top %q:\n%s", s.Model().popupStack.TopName(), s.Screen())
+	}
+	s.Keys("?")
+	if top := s.Model().popupStack.TopName(); top != "help" {
+		t.Fatalf("top popup = %q, want help", top)
+	}
+	s.Keys("esc")
+	if top := s.Model().popupStack.TopName(); top != "results" {
+		t.Errorf("after esc top popup = %q, want results", top)
+	}
+	s.Keys("esc")
+	if s.Model().popupStack.Len() != 0 {
+		t.Errorf("popups left open: %q", s.Model().popupStack.TopName())
+	}
+
+	s.Keys("ctrl+c")
+	if !s.Quit() {
+		t.Error("ctrl+c did not quit an idle session")
+	}
+}
+
+func TestScriptRowDetailPanel(t *testing.T) {
+	t.Parallel()
+	s, _ := scriptModel(t)
+
+	s.Keys("i").Type("SELECT id, name FROM items").Keys("ctrl+d", "enter")
+	if top := s.Model().popupStack.TopName(); top != "rowAction" {
+		t.Fatalf("top popup = %q, want rowAction", top)
+	}
+	// View the full row: the panel opens over the results, the editor is left alone
+	editor := s.Model().editor.Value()
+	s.Keys("2")
+	if top := s.Model().popupStack.TopName(); top != "rowDetail" {
+		t.Fatalf("top popup = %q, want rowDetail", top)
+	}
+	if text := s.Model().editor.Value(); text != editor {
+		t.Errorf("editor = %q, want %q untouched", text, editor)
+	}
+	s.Keys("j")
+	if f := s.Model().rowDetail[s.Model().rowDetailIdx]; f.Name != "name" || f.Value != "widget" {
+		t.Errorf("selected field = %+v, want name: widget", f)
+	}
+	if screen := s.Screen(); !strings.Contains(screen, "widget") || !strings.Contains(screen, "Row 1 of") {
+		t.Errorf("screen lacks the row detail panel:\n%s", screen)
+	}
+	s.Keys("esc")
+	if top := s.Model().popupStack.TopName(); top != "results" {
+		t.Errorf("top popup = %q after esc, want results", top)
+	}
+}
+
+func TestScriptQuickFilter(t *testing.T) {
+	t.Parallel()
+	s, driver := scriptModel(t)
+	if _, err := driver.Execute(context.Background(), "INSERT INTO items VALUES (2, 'gadget')"); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Keys("i").Type("SELECT id, name FROM items ORDER BY id").Keys("ctrl+d", "enter")
+	if !strings.Contains(s.Screen(), "8 - Exclude: name ≠ widget") {
+		t.Fatalf("exclude action not offered for the name column:\n%s", s.Screen())
+	}
+	s.Keys("8")
+	m := s.Model()
+	if m.popupEntry == nil || !strings.Contains(m.popupEntry.Query, "<> 'widget'") {
+		t.Fatalf("query not filtered: %+v", m.popupEntry)
+	}
+	if rows := m.popupResult.Rows; len(rows) != 1 || rows[0][1] != "gadget" {
+		t.Errorf("rows = %v, want only gadget", rows)
+	}
+
+	// Filters stack on the re-run query, here on the id column
+	s.Keys("enter", "left", "7")
+	if m := s.Model(); len(m.popupResult.Rows) != 1 || !strings.Contains(m.popupEntry.Query, "= 2") {
+		t.Errorf("second filter: query %q, rows %v", m.popupEntry.Query, m.popupResult.Rows)
+	}
+}
+
+func TestScriptColumnAggregates(t *testing.T) {
+	t.Parallel()
+	s, driver := scriptModel(t)
+	for _, stmt := range []string{"INSERT INTO items VALUES (2, 'widget')", "INSERT INTO items VALUES (3, NULL)"} {
+		if _, err := driver.Execute(context.Background(), stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s.Keys("i").Type("SELECT id, name FROM items WHERE id > 1").Keys("ctrl+d", "A")
+	if !strings.Contains(s.Screen(), "Column: name") {
+		t.Fatalf("column aggregates not opened on name:\n%s", s.Screen())
+	}
+	// Aggregates cover the whole source table, not just the rows shown
+	s.Keys("2")
+	m := s.Model()
+	if m.popupEntry == nil || !strings.Contains(m.popupEntry.Query, "GROUP BY") {
+		t.Fatalf("group by not run: %+v", m.popupEntry)
+	}
+	if rows := m.popupResult.Rows; len(rows) != 2 || rows[0][0] != "widget" || rows[0][1] != "2" {
+		t.Errorf("rows = %v, want widget counted twice first", rows)
+	}
+
+	// From the counts, row_count is in view; step back to name
+	s.Keys("A", "left", "4")
+	if rows := s.Model().popupResult.Rows; len(rows) != 1 || rows[0][0] != "1" || rows[0][1] != "3" {
+		t.Errorf("NULL count of name = %v, want 1 of 3", rows)
+	}
+}
+
+func TestScriptQueryBuilder(t *testing.T) {
+	t.Parallel()
+	s, driver := scriptModel(t)
+	if _, err := driver.Execute(context.Background(), "INSERT INTO items VALUES (2, 'gadget')"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Table, then the name column, then name contains "gad", then run
+	s.Keys("B").Type("ite").Keys("enter", "down", " ", "enter", "right", "down", "right", "right", "right", "right", "right", "right", "down").
+		Type("gad").Keys("enter")
+	if conds := s.Model().builder.Conds; len(conds) != 1 || conds[0].String() != "name contains gad" {
+		t.Fatalf("conditions = %v", conds)
+	}
+	s.Keys("enter", "enter")
+
+	want := `SELECT "name" FROM items WHERE "name" LIKE '%gad%' LIMIT 100`
+	m := s.Model()
+	if m.editor.Value() != want {
+		t.Errorf("editor = %q, want %q", m.editor.Value(), want)
+	}
+	if m.popupResult == nil || len(m.popupResult.Rows) != 1 || m.popupResult.Rows[0][0] != "gadget" {
+		t.Errorf("query not run: %+v", m.popupResult)
+	}
+}
+
+func TestScriptAssistant(t *testing.T) {
+	t.Parallel()
+	var system string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct{ Content string } `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		system = req.Messages[0].Content
+		w.Write([]byte(`{"message":{"content":"SELECT name FROM items WHERE id = 1"}}`))
+	}))
+	defer srv.Close()
+	s, _ := scriptModel(t)
+
+	// Off until the config opts in
+	s.Keys("ctrl+g")
+	if s.Model().showAssistant || !strings.Contains(s.Model().errorMsg, "assistant is off") {
+		t.Fatalf("assistant opened without opting in, error %q", s.Model().errorMsg)
+	}
+
+	s.Model().config.Assistant = config.Assistant{Enabled: true, Provider: config.AssistantOllama, Endpoint: srv.URL}
+	s.Keys("ctrl+g").Type("name of item 1").Keys("enter")
+	m := s.Model()
+	if got := m.editor.Value(); got != "SELECT name FROM items WHERE id = 1" {
+		t.Errorf("editor = %q", got)
+	}
+	if m.mode != InsertMode || len(m.history) != 0 {
+		t.Errorf("generated SQL was run or not left for review: mode %v, %d history entries", m.mode, len(m.history))
+	}
+	if !strings.Contains(system, "items(id INTEGER PK, name TEXT)") || strings.Contains(system, "widget") {
+		t.Errorf("schema sent = %q", system)
+	}
+}
+
+func TestScriptExplainError(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"message":{"content":"The column is name.\n` + "```sql\\nSELECT name FROM items\\n```" + `"}}`))
+	}))
+	defer srv.Close()
+	s, _ := scriptModel(t)
+
+	s.Keys("i").Type("SELECT nme FROM items").Keys("ctrl+d", "esc", "E")
+	if !s.Model().showErrorHelp {
+		t.Fatalf("no explanation for %+v", s.Model().history)
+	}
+	if screen := s.Screen(); !strings.Contains(screen, "SQLITE_ERROR") || !strings.Contains(screen, "sqlite.org/rescode") {
+		t.Errorf("no documentation hint:\n%s", screen)
+	}
+	s.Keys("esc")
+
+	s.Model().config.Assistant = config.Assistant{Enabled: true, Provider: config.AssistantOllama, Endpoint: srv.URL}
+	s.Keys("E", "a")
+	if !strings.Contains(s.Screen(), "The column is name.") {
+		t.Errorf("no suggestion:\n%s", s.Screen())
+	}
+	s.Keys("e")
+	if m := s.Model(); m.showErrorHelp || m.editor.Value() != "SELECT name FROM items" || m.mode != InsertMode {
+		t.Errorf("fix not in the editor: %q, mode %v", m.editor.Value(), m.mode)
+	}
+}
+
+func TestScriptErrorPosition(t *testing.T) {
+	t.Parallel()
+	s, _ := scriptModel(t)
+
+	s.Keys("i").Type("SELECT id FORM items").Keys("ctrl+d", "esc")
+	e := s.Model().history[len(s.Model().history)-1]
+	// SQLite reads FORM as an alias and fails at items
+	if e.Status != "error" || e.ErrorPosition != 16 {
+		t.Fatalf("entry = %+v, want the error at items", e)
+	}
+	// The failed entry opens expanded
+	screen := s.Screen()
+	if !strings.Contains(screen, "1 | SELECT id FORM items") || !strings.Contains(screen, strings.Repeat(" ", len("1 | SELECT id FORM "))+"^") {
+		t.Errorf("expanded error does not mark the position:\n%s", screen)
+	}
+	s.Keys("enter")
+	if screen := s.Screen(); !strings.Contains(screen, `query failed: near "items": syntax error`) || strings.Contains(screen, "^") {
+		t.Errorf("collapsed error should be one line:\n%s", screen)
+	}
+}
+
+func TestScriptSpilledResultPaging(t *testing.T) {
+	t.Parallel()
+	s, driver := scriptModel(t)
+	ctx := context.Background()
+	fill := "INSERT INTO items WITH RECURSIVE n(i) AS (SELECT 2 UNION ALL SELECT i + 1 FROM n WHERE i < 2500) SELECT i, 'item ' || i FROM n"
+	if _, err := driver.Execute(ctx, fill); err != nil {
+		t.Fatalf("fill: %v", err)
+	}
+	query := "SELECT id, name FROM items ORDER BY id"
+	res, err := driver.Execute(db.WithResultMemory(ctx, 8192), query)
+	if err != nil || res.Spill == nil {
+		t.Fatalf("select did not spill: %v", err)
+	}
+
+	s.Send(RerunResultMsg{Result: res, Entry: &history.HistoryEntry{ID: 1, Query: query}})
+	// Paging off the end of the first window loads the next one from disk
+	for i := 0; i < 200 && s.Model().popupOffset == 0; i++ {
+		s.Keys("n")
+	}
+	if off := s.Model().popupOffset; off != resultWindowRows {
+		t.Fatalf("popup offset = %d after paging, want %d", off, resultWindowRows)
+	}
+	if !strings.Contains(s.Screen(), "showing 1001-2000") {
+		t.Errorf("header does not show the window:\n%s", s.Screen())
+	}
+	if row := s.Model().popupTable.HighlightedRow().Data; row["id"].(table.StyledCell).Data != "1001" {
+		t.Errorf("highlighted row = %v, want id 1001", row)
+	}
+
+	// Stepping up from the window's first row goes back to the previous one
+	s.Keys("k")
+	if row := s.Model().popupTable.HighlightedRow().Data; s.Model().popupOffset != 0 || row["id"].(table.StyledCell).Data != "1000" {
+		t.Errorf("offset %d, highlighted row %v, want id 1000", s.Model().popupOffset, row)
+	}
+
+	// Replacing the result deletes the spill
+	s.Send(RerunResultMsg{Result: &db.QueryResult{Columns: []string{"x"}, IsSelect: true}, Entry: &history.HistoryEntry{ID: 2, Query: "SELECT 1"}})
+	if _, err := res.RowsAt(2400, 1); err == nil {
+		t.Error("spilled rows still readable after the result was replaced")
+	}
+}
+
+func TestScriptTableExportFilter(t *testing.T) {
+	t.Parallel()
+	s, driver := scriptModel(t)
+	if _, err := driver.Execute(context.Background(), "INSERT INTO items VALUES (2, 'gadget')"); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	out := filepath.Join(t.TempDir(), "items.csv")
+
+	s.Send(schemabrowser.ExportTableMsg{TableName: "items"})
+	if !strings.Contains(s.Screen(), "2 rows") {
+		t.Fatalf("export popup does not count the table:\n%s", s.Screen())
+	}
+	// Exporting a large table whole asks first
+	s.Send(ExportEstimateMsg{Table: "items", Rows: 4_200_000}).Keys("enter")
+	if !strings.Contains(s.Screen(), "~4.2M rows, continue?") || !s.Model().showExportPopup {
+		t.Fatalf("large export did not ask to continue:\n%s", s.Screen())
+	}
+	// Tab completes column names; the filter is recounted on leaving its inputs
+	s.Keys("ctrl+u").Type(out).Keys("down").Type("I").Keys("tab")
+	if where := s.Model().exportFilterInputs[exportWhere].Value(); where != "id" {
+		t.Errorf("where after Tab = %q, want the id column", where)
+	}
+	s.Type(" >= 2").Keys("down").Type("name DESC").Keys("down").Type("1").Keys("down")
+	if !strings.Contains(s.Screen(), "1 row") {
+		t.Errorf("filtered count not shown:\n%s", s.Screen())
+	}
+	s.Keys("enter")
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("export: %v (%s)", err, s.Model().errorMsg)
+	}
+	if got := string(data); got != "id,name\n2,gadget\n" {
+		t.Errorf("exported %q, want only the filtered row", got)
+	}
+}
+
+func TestScriptDescribeTemplateDialect(t *testing.T) {
+	t.Parallel()
+	s, driver := scriptModel(t)
+	for _, tmpl := range s.Model().queryTemplates() {
+		if tmpl.Name != "DESCRIBE" {
+			continue
+		}
+		res, err := driver.Execute(context.Background(), strings.ReplaceAll(tmpl.Query, "<table>", "items"))
+		if err != nil || res.RowCount != 2 {
+			t.Fatalf("DESCRIBE on sqlite ran %q: %v, want the 2 columns of items", tmpl.Query, err)
+		}
+		return
+	}
+	t.Fatal("no DESCRIBE template")
+}
+
+func TestScriptTemplateManager(t *testing.T) {
+	t.Cleanup(xdg.Reload) // Runs after XDG_CONFIG_HOME is restored
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	xdg.Reload()
+	s, _ := scriptModel(t)
+	count := len(s.Model().config.QueryTemplates)
+
+	s.Send(schemabrowser.TableSelectedMsg{TableName: "items"}).Keys("m", "a")
+	s.Type("NAMES").Keys("tab").Type("SELECT name FROM <table>").Keys("tab").Type("SELECT name FROM <table> ORDER BY name").Keys("enter")
+	templates := s.Model().config.QueryTemplates
+	if len(templates) != count+1 || templates[count].Name != "NAMES" || templates[count].Dialects["sqlite"] == "" {
+		t.Fatalf("templates after adding = %+v", templates)
+	}
+
+	// Move the new template up, delete the one it passed, and use it from the picker
+	s.Keys("K", "down", "d", "esc")
+	templates = s.Model().config.QueryTemplates
+	if len(templates) != count || templates[count-1].Name != "NAMES" {
+		t.Fatalf("templates after moving and deleting = %+v", templates)
+	}
+	if !strings.Contains(s.Screen(), "SELECT name FROM items ORDER BY name") {
+		t.Errorf("picker does not list the sqlite query:\n%s", s.Screen())
+	}
+	path, _ := config.ConfigPath()
+	if data, err := os.ReadFile(path); err != nil || !strings.Contains(string(data), "NAMES") {
+		t.Errorf("config not saved: %v", err)
+	}
+}
+
+func TestScriptNumberFormatToggle(t *testing.T) {
+	t.Parallel()
+	s, _ := scriptModel(t)
+	s.Model().config.NumberFormat = config.NumberFormat{Decimals: 2, Locale: "de-DE"}
+
+	result := &db.QueryResult{Columns: []string{"item", "price"}, IsSelect: true, RowCount: 2,
+		Rows: [][]string{{"widget", "1234567.891"}, {"gadget", "-42"}}}
+	s.Send(RerunResultMsg{Result: result, Entry: &history.HistoryEntry{ID: 1, Query: "SELECT item, price FROM prices"}})
+	if !strings.Contains(s.Screen(), "1234567.891") {
+		t.Fatalf("raw value not shown before toggling:\n%s", s.Screen())
+	}
+	s.Keys("#")
+	if screen := s.Screen(); !strings.Contains(screen, "1.234.567,89") || !strings.Contains(screen, "-42") {
+		t.Fatalf("formatted values not shown:\n%s", screen)
+	}
+	// Row actions keep the value as the database returned it
+	s.Keys("enter", "2", "j")
+	if f := s.Model().rowDetail[s.Model().rowDetailIdx]; f.Value != "1234567.891" {
+		t.Errorf("row detail %s = %q, want the raw price", f.Name, f.Value)
+	}
+}
+
+func TestScriptFullWidthColumn(t *testing.T) {
+	t.Parallel()
+	s, _ := scriptModel(t)
+
+	note := strings.Repeat("x", 80) + "END"
+	result := &db.QueryResult{Columns: []string{"id", "note"}, IsSelect: true, RowCount: 1, Rows: [][]string{{"1", note}}}
+	s.Send(RerunResultMsg{Result: result, Entry: &history.HistoryEntry{ID: 1, Query: "SELECT id, note FROM notes"}})
+	if screen := s.Screen(); strings.Contains(screen, "END") || !strings.Contains(screen, "x…") {
+		t.Fatalf("long note not cut with a marker:\n%s", screen)
+	}
+	s.Keys("w")
+	if col := s.Model().fullWidthColumn; col != "note" {
+		t.Fatalf("full width column = %q, want note", col)
+	}
+	if screen := s.Screen(); !strings.Contains(screen, "xEND") {
+		t.Errorf("note not shown whole at full width:\n%s", screen)
+	}
+	s.Keys("w")
+	if screen := s.Screen(); strings.Contains(screen, "END") {
+		t.Errorf("note not capped again:\n%s", screen)
+	}
+}
+
+// transferResult runs a transfer command and returns its completion message
+func transferResult(cmd tea.Cmd) tea.Msg {
+	var done tea.Msg
+	for msg := range cmd().(TransferProgressMsg).updates {
+		if _, ok := msg.(TransferProgressMsg); !ok {
+			done = msg
+		}
+	}
+	return done
+}
+
+func TestImportReadOnlyTransaction(t *testing.T) {
+	t.Parallel()
+	s, driver := scriptModel(t)
+	m := s.Model()
+	m.profile.ReadOnly = true
+
+	csvPath := filepath.Join(t.TempDir(), "items.csv")
+	if err := os.WriteFile(csvPath, []byte("id,name\n2,gadget\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	done := transferResult(m.importTableCmd("items", csvPath, importOptions{BatchSize: 10, Transaction: true})).(ImportTableCompleteMsg)
+	if !errors.Is(done.Err, errReadOnly) {
+		t.Errorf("import error = %v, want %v", done.Err, errReadOnly)
+	}
+	res, err := driver.Execute(context.Background(), "SELECT COUNT(*) FROM items")
+	if err != nil || res.Rows[0][0] != "1" {
+		t.Errorf("items after a read-only import = %v, %v, want 1 row", res, err)
+	}
+}
+
+func TestScriptRunFileGuards(t *testing.T) {
+	t.Parallel()
+	s, driver := scriptModel(t)
+	path := filepath.Join(t.TempDir(), "cleanup.sql")
+	if err := os.WriteFile(path, []byte("SELECT 1;\n-- clear it\nDELETE FROM items;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	count := func() string {
+		res, err := driver.Execute(context.Background(), "SELECT COUNT(*) FROM items")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Rows[0][0]
+	}
+	run := func() { s.Keys("ctrl+r", "ctrl+u").Type("/run " + path).Keys("enter") }
+
+	s.Model().profile.Guards.UnfilteredWrite = config.GuardBlock
+	run()
+	if m := s.Model(); !strings.Contains(m.errorMsg, "Blocked by query guard: cleanup.sql") || count() != "1" {
+		t.Fatalf("blocked file ran: %q, %s rows left", m.errorMsg, count())
+	}
+
+	s.Model().profile.Guards.UnfilteredWrite = config.GuardWarn
+	run()
+	if !strings.Contains(s.Screen(), "Query guards flagged this file cleanup.sql") {
+		t.Fatalf("flagged file ran without asking:\n%s", s.Screen())
+	}
+	if s.Keys("n"); count() != "1" {
+		t.Fatal("declined file ran")
+	}
+	run()
+	if s.Keys("y"); count() != "0" {
+		t.Errorf("confirmed file did not run: %s rows left, %q", count(), s.Model().errorMsg)
+	}
+}
+
+func TestScriptRunFileStrictMode(t *testing.T) {
+	t.Parallel()
+	s, driver := scriptModel(t)
+	dir := t.TempDir()
+	reads, writes := filepath.Join(dir, "report.sql"), filepath.Join(dir, "rename.sql")
+	for path, content := range map[string]string{
+		reads:  "SELECT * FROM items;\n",
+		writes: "SELECT 1;\nUPDATE items SET name = 'gadget' WHERE id = 1;\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	name := func() string {
+		res, err := driver.Execute(context.Background(), "SELECT name FROM items WHERE id = 1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Rows[0][0]
+	}
+	run := func(path string) { s.Keys("ctrl+r", "ctrl+u").Type("/run " + path).Keys("enter") }
+
+	s.Keys("m")
+	run(reads)
+	if h := s.Model().history; strings.Contains(s.Screen(), "CONFIRM") || len(h) != 1 || h[0].Query != "SELECT * FROM items" {
+		t.Fatalf("strict mode asked about a read-only file:\n%s", s.Screen())
+	}
+	run(writes)
+	if !strings.Contains(s.Screen(), "CONFIRM DESTRUCTIVE ACTION") || !strings.Contains(s.Screen(), "execute this file rename.sql") {
+		t.Fatalf("strict mode ran a file with writes without asking:\n%s", s.Screen())
+	}
+	if s.Keys("n"); name() != "widget" {
+		t.Fatal("declined file ran")
+	}
+	run(writes)
+	if s.Keys("y"); name() != "gadget" {
+		t.Errorf("confirmed file did not run: %q", s.Model().errorMsg)
+	}
+}
+
+func TestCopyTableReadOnlyProfile(t *testing.T) {
+	t.Parallel()
+	s, _ := scriptModel(t)
+	dest := filepath.Join(t.TempDir(), "dest.db")
+	profile := config.Profile{Name: "archive", Type: "sqlite", Database: dest, ReadOnly: true}
+
+	done := transferResult(s.Model().copyTableCmd("items", profile, "items", true)).(CopyTableCompleteMsg)
+	if !errors.Is(done.Err, errReadOnly) {
+		t.Errorf("copy into a read-only profile = %v, want %v", done.Err, errReadOnly)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("read-only destination was opened or written: %v", err)
+	}
+}
+
+func TestScriptTemplateGuards(t *testing.T) {
+	t.Parallel()
+	s, driver := scriptModel(t)
+	m := s.Model()
+	m.config.QueryTemplates = []config.QueryTemplate{{Name: "Clear", Query: "DELETE FROM <table>"}}
+	m.profile.Guards.UnfilteredWrite = config.GuardWarn
+
+	s.Send(schemabrowser.TableSelectedMsg{TableName: "items"}).Keys("enter")
+	if !strings.Contains(s.Screen(), "DELETE without WHERE affects every row") {
+		t.Fatalf("guarded template ran without asking:\n%s", s.Screen())
+	}
+	s.Keys("n")
+	res, err := driver.Execute(context.Background(), "SELECT COUNT(*) FROM items")
+	if err != nil || res.Rows[0][0] != "1" {
+		t.Errorf("items after declining the template = %v, %v, want 1 row", res, err)
+	}
+}
+
+func TestFailedStatementClosesEarlierSpill(t *testing.T) {
+	if _, err := os.ReadDir("/proc/self/fd"); err != nil {
+		t.Skip("needs /proc to list open files")
+	}
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	s, _ := scriptModel(t)
+	// openSpills counts the open spill files, which are unlinked but still held
+	openSpills := func() int {
+		n := 0
+		fds, _ := os.ReadDir("/proc/self/fd")
+		for _, fd := range fds {
+			if target, err := os.Readlink("/proc/self/fd/" + fd.Name()); err == nil && strings.HasPrefix(target, filepath.Join(dir, "ezdb-result-")) {
+				n++
+			}
+		}
+		return n
+	}
+
+	ctx, cancel := context.WithCancel(db.WithResultMemory(context.Background(), 1024))
+	query := "WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 1000) SELECT i, 'row ' || i FROM n; SELECT nope"
+	msg := s.Model().executeQueryCmd(ctx, cancel, query)().(QueryResultMsg)
+	if msg.Err == nil || len(msg.AllEntries) != 1 {
+		t.Fatalf("result = %+v, want the second statement to fail", msg)
+	}
+	if n := openSpills(); n != 0 {
+		t.Errorf("%d spill files still open after the failed statement", n)
+	}
+}
+
+// stubMonitor records the sessions it was asked to stop
+type stubMonitor struct{ stopped []string }
+
+func (s *stubMonitor) Sessions(context.Context) ([]db.Session, error) { return nil, nil }
+func (s *stubMonitor) CancelSession(_ context.Context, id string) error {
+	s.stopped = append(s.stopped, "cancel "+id)
+	return nil
+}
+func (s *stubMonitor) TerminateSession(_ context.Context, id string) error {
+	s.stopped = append(s.stopped, "terminate "+id)
+	return nil
+}
+
+func TestStopSessionReadOnlyAndAudit(t *testing.T) {
+	t.Parallel()
+	s, _ := scriptModel(t)
+	log, err := audit.Open(audit.TargetFile, filepath.Join(t.TempDir(), "audit.log"))
+	if err != nil {
+		t.Fatalf("audit: %v", err)
+	}
+	t.Cleanup(func() { log.Close() })
+	m := s.Model().WithAuditLog(log)
+	monitor := &stubMonitor{}
+
+	msg := m.stopSessionCmd(monitor, "terminate", "42")()
+	if stopped := msg.(SessionStoppedMsg); stopped.Err != nil {
+		t.Fatalf("terminate: %v", stopped.Err)
+	}
+	records, err := log.Records("test")
+	if err != nil || len(records) != 1 || records[0].Statement != "terminate session 42" {
+		t.Fatalf("audit records = %+v (%v), want the terminate", records, err)
+	}
+
+	m.profile.ReadOnly = true
+	msg = m.stopSessionCmd(monitor, "cancel", "42")()
+	if stopped := msg.(SessionStoppedMsg); !errors.Is(stopped.Err, errReadOnly) {
+		t.Fatalf("cancel on a read-only profile: err = %v, want errReadOnly", stopped.Err)
+	}
+	if len(monitor.stopped) != 1 {
+		t.Errorf("monitor stopped %v, want only the first terminate", monitor.stopped)
+	}
+}
+
+func TestScriptChords(t *testing.T) {
+	t.Parallel()
+	s, _ := scriptModel(t)
+	s.Model().config.Keys.ToggleStrict = []string{"g s"}
+
+	s.Keys("g", "s")
+	if !s.Model().strictMode {
+		t.Fatal("g s in visual mode did not toggle strict mode")
+	}
+
+	s.Keys("tab", "g", "s")
+	if m := s.Model(); !m.strictMode || m.errorMsg != "g s is not bound here" {
+		t.Errorf("g s in the schema browser: strict = %v, error = %q; want it reported as unbound", m.strictMode, m.errorMsg)
+	}
+
+	m := s.Model()
+	m.popupStack.Push("export", func(*Model) {})
+	if m.chordsEnabled(scriptKey("g")) {
+		t.Error("chords enabled under the export form")
+	}
+}
@@ -0,0 +1,80 @@
+// internal/ui/session.go
+// Session restore: saves the profile, editor, history selection and schema browser on quit, and offers them back on the next start.
+package ui
+
+import (
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nhath/ezdb/internal/session"
+)
+
+// handleSessionOffer answers the restore offer on the profile selector:
+// y restores the session, n or esc dismisses it, and any other key
+// dismisses it and is handled as usual
+func (m Model) handleSessionOffer(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	offer := m.sessionOffer
+	m.sessionOffer = nil
+	switch msg.String() {
+	case "y", "Y":
+		return m.restoreSession(offer)
+	case "n", "N", "esc":
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// restoreSession connects to the session's profile; the rest of the
+// session is applied as the connection and its schema load
+func (m Model) restoreSession(s *session.State) (Model, tea.Cmd, bool) {
+	for i, p := range m.config.Profiles {
+		if p.Name == s.Profile {
+			m.restoring = s
+			m.profileSelector = m.profileSelector.Select(i)
+			var cmd tea.Cmd
+			m.profileSelector, cmd = m.profileSelector.Connect()
+			return m, cmd, true
+		}
+	}
+	return m, nil, true
+}
+
+// applyRestore puts back the editor and schema browser of the session being
+// restored once its profile has connected
+func (m Model) applyRestore() Model {
+	if m.restoring == nil || m.profile == nil || m.restoring.Profile != m.profile.Name {
+		m.restoring = nil
+		return m
+	}
+	if m.restoring.Editor != "" {
+		m.editor.SetValue(m.restoring.Editor)
+	}
+	if m.restoring.SchemaOpen && !m.schemaBrowser.IsVisible() {
+		m.schemaBrowser = m.schemaBrowser.Toggle()
+	}
+	// Reconnecting later must not put them back again
+	m.restoring.Editor, m.restoring.SchemaOpen = "", false
+	return m
+}
+
+// saveSession records the session for the next start; failures only cost
+// the restore offer, so they are logged
+func (m Model) saveSession() {
+	if m.sessionPath == "" || m.appState != StateReady || m.profile == nil {
+		return
+	}
+	s := session.State{
+		Profile:    m.profile.Name,
+		Editor:     m.editor.Value(),
+		SchemaOpen: m.schemaBrowser.IsVisible(),
+		Table:      m.schemaBrowser.CurrentTable(),
+		SavedAt:    time.Now(),
+	}
+	if m.selected >= 0 && m.selected < len(m.history) {
+		s.HistoryID = m.history[m.selected].ID
+	}
+	if err := session.Save(m.sessionPath, s); err != nil {
+		log.Printf("session: %v", err)
+	}
+}
@@ -0,0 +1,296 @@
+// internal/ui/snapshots.go
+// Schema snapshots: save the live schema under a name and diff the live schema against a saved one.
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/history"
+)
+
+// snapshotTimeout bounds reading the live schema for a snapshot or diff
+const snapshotTimeout = 2 * time.Minute
+
+// openSnapshotsPopup opens the snapshot list of the current profile.
+func (m *Model) openSnapshotsPopup() {
+	if m.showSnapshots {
+		return
+	}
+	m.showSnapshots = true
+	m.autocompleting = false
+	m.snapshotIdx = 0
+	m.snapshotAll = false
+	m.snapshotNaming = false
+	m.snapshotConfirm = false
+	m.snapshotDiff, m.snapshotDiffName = nil, ""
+	m.ensureInput(lazySnapshot, &m.snapshotInput, newSnapshotInput)
+	m.popupStack.Push("snapshots", func(m *Model) {
+		m.showSnapshots = false
+		m.snapshotInput.Blur()
+	})
+}
+
+// snapshotProfile is the profile whose snapshots are listed, "" for all
+func (m Model) snapshotProfile() string {
+	if m.snapshotAll || m.profile == nil {
+		return ""
+	}
+	return m.profile.Name
+}
+
+// handleSnapshotsKeys handles keys of the snapshot list, the name prompt
+// and the diff view
+func (m Model) handleSnapshotsKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	store := m.historyStore
+	switch {
+	case m.snapshotNaming:
+		switch msg.String() {
+		case "esc":
+			m.snapshotNaming = false
+			m.snapshotInput.Blur()
+		case "enter":
+			name := strings.TrimSpace(m.snapshotInput.Value())
+			if name == "" || m.driver == nil || m.profile == nil {
+				return m, nil, true
+			}
+			m.snapshotNaming = false
+			m.snapshotInput.Blur()
+			m.statusMsg = "Saving snapshot " + name + "..."
+			return m, saveSnapshotCmd(store, m.driver, m.profile.Name, name, m.snapshotProfile()), true
+		default:
+			var cmd tea.Cmd
+			m.snapshotInput, cmd = m.snapshotInput.Update(msg)
+			return m, cmd, true
+		}
+		return m, nil, true
+
+	case m.snapshotDiffName != "":
+		switch msg.String() {
+		case "esc", "q", "backspace", "left", "h":
+			m.snapshotDiff, m.snapshotDiffName = nil, ""
+		case "up", "k":
+			m.snapshotScroll = max(m.snapshotScroll-1, 0)
+		case "down", "j":
+			m.snapshotScroll = min(m.snapshotScroll+1, max(len(m.snapshotDiff)-1, 0))
+		}
+		return m, nil, true
+
+	case m.snapshotConfirm:
+		m.snapshotConfirm = false
+		if (msg.String() == "y" || msg.String() == "Y") && m.snapshotIdx < len(m.snapshots) {
+			return m, deleteSnapshotCmd(store, m.snapshots[m.snapshotIdx], m.snapshotProfile()), true
+		}
+		return m, nil, true
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.closeTopPopup()
+	case "up", "k":
+		if m.snapshotIdx > 0 {
+			m.snapshotIdx--
+		}
+	case "down", "j":
+		if m.snapshotIdx < len(m.snapshots)-1 {
+			m.snapshotIdx++
+		}
+	case "n":
+		m.snapshotNaming = true
+		m.snapshotInput.SetValue(time.Now().Format("2006-01-02 15:04"))
+		m.snapshotInput.CursorEnd()
+		m.snapshotInput.Focus()
+	case "a":
+		m.snapshotAll = !m.snapshotAll
+		m.snapshotIdx = 0
+		return m, listSnapshotsCmd(store, m.snapshotProfile(), ""), true
+	case "d":
+		if m.snapshotIdx < len(m.snapshots) {
+			m.snapshotConfirm = true
+		}
+	case "enter":
+		if m.snapshotIdx < len(m.snapshots) && m.driver != nil {
+			m.statusMsg = "Comparing with " + m.snapshots[m.snapshotIdx].Name + "..."
+			return m, diffSnapshotCmd(store, m.driver, m.snapshots[m.snapshotIdx]), true
+		}
+	}
+	return m, nil, true
+}
+
+func listSnapshotsCmd(store *history.Store, profile, status string) tea.Cmd {
+	return func() tea.Msg {
+		snapshots, err := store.Snapshots(profile)
+		return SnapshotsMsg{Snapshots: snapshots, Status: status, Err: err}
+	}
+}
+
+// saveSnapshotCmd reads the live schema and stores it as a snapshot
+func saveSnapshotCmd(store *history.Store, driver db.Driver, profile, name, listed string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+		defer cancel()
+		schema, err := db.LoadSchema(ctx, driver)
+		if err != nil {
+			return SnapshotsMsg{Err: err}
+		}
+		data, err := json.Marshal(schema)
+		if err != nil {
+			return SnapshotsMsg{Err: err}
+		}
+		if _, err := store.SaveSnapshot(profile, name, string(data)); err != nil {
+			return SnapshotsMsg{Err: err}
+		}
+		snapshots, err := store.Snapshots(listed)
+		status := fmt.Sprintf("Saved snapshot %s (%d tables)", name, len(schema.Columns))
+		return SnapshotsMsg{Snapshots: snapshots, Status: status, Err: err}
+	}
+}
+
+func deleteSnapshotCmd(store *history.Store, snap history.SchemaSnapshot, listed string) tea.Cmd {
+	return func() tea.Msg {
+		if err := store.DeleteSnapshot(snap.ID); err != nil {
+			return SnapshotsMsg{Err: err}
+		}
+		snapshots, err := store.Snapshots(listed)
+		return SnapshotsMsg{Snapshots: snapshots, Status: "Deleted snapshot " + snap.Name, Err: err}
+	}
+}
+
+// diffSnapshotCmd compares the live schema with a stored snapshot
+func diffSnapshotCmd(store *history.Store, driver db.Driver, snap history.SchemaSnapshot) tea.Cmd {
+	return func() tea.Msg {
+		data, err := store.SnapshotSchema(snap.ID)
+		if err != nil {
+			return SnapshotDiffMsg{Err: err}
+		}
+		var old db.Schema
+		if err := json.Unmarshal([]byte(data), &old); err != nil {
+			return SnapshotDiffMsg{Err: fmt.Errorf("reading snapshot %s: %w", snap.Name, err)}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+		defer cancel()
+		live, err := db.LoadSchema(ctx, driver)
+		if err != nil {
+			return SnapshotDiffMsg{Err: err}
+		}
+		return SnapshotDiffMsg{Name: snap.Name, Changes: db.DiffSchemas(old, live)}
+	}
+}
+
+// handleSnapshots shows a snapshot listing
+func (m Model) handleSnapshots(msg SnapshotsMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	}
+	m.snapshots = msg.Snapshots
+	m.snapshotIdx = min(m.snapshotIdx, max(len(m.snapshots)-1, 0))
+	if msg.Status != "" {
+		m.statusMsg = msg.Status
+	}
+	return m, nil
+}
+
+// handleSnapshotDiff shows the changes since a snapshot
+func (m Model) handleSnapshotDiff(msg SnapshotDiffMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	}
+	if !m.showSnapshots {
+		return m, nil
+	}
+	m.snapshotDiff, m.snapshotDiffName, m.snapshotScroll = msg.Changes, msg.Name, 0
+	m.statusMsg = fmt.Sprintf("%d changes since %s", len(msg.Changes), msg.Name)
+	return m, nil
+}
+
+func (m Model) renderSnapshotsPopup(main string) string {
+	var content strings.Builder
+	faint := lipgloss.NewStyle().Faint(true)
+	popupWidth := min(100, m.width-10)
+	visible := max(m.height-14, 5)
+
+	if m.snapshotDiffName != "" {
+		content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).
+			Render("Changes since " + m.snapshotDiffName))
+		content.WriteString("\n\n")
+		if len(m.snapshotDiff) == 0 {
+			content.WriteString(faint.Render("  The live schema matches the snapshot"))
+			content.WriteString("\n")
+		}
+		end := min(m.snapshotScroll+visible, len(m.snapshotDiff))
+		for _, c := range m.snapshotDiff[m.snapshotScroll:end] {
+			color := m.theme.WarningColor()
+			switch c.Op {
+			case "+":
+				color = m.theme.SuccessColor()
+			case "-":
+				color = m.theme.ErrorColor()
+			}
+			content.WriteString(lipgloss.NewStyle().Foreground(color).Render(limitString(c.String(), popupWidth-4)))
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+		content.WriteString(m.renderHintLine("↑/↓: scroll • Esc: back to snapshots", m.popupInnerWidth(popupWidth)))
+	} else {
+		title := "Schema Snapshots"
+		if !m.snapshotAll && m.profile != nil {
+			title += " of " + m.profile.Name
+		}
+		content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render(title))
+		content.WriteString("\n\n")
+		if len(m.snapshots) == 0 {
+			content.WriteString(faint.Render("  (none, press n to save one)"))
+			content.WriteString("\n")
+		}
+		start := 0
+		if m.snapshotIdx >= visible {
+			start = m.snapshotIdx - visible + 1
+		}
+		for i := start; i < len(m.snapshots) && i < start+visible; i++ {
+			s := m.snapshots[i]
+			style := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
+			prefix := "  "
+			if i == m.snapshotIdx {
+				style = lipgloss.NewStyle().Foreground(m.theme.TextPrimary()).Bold(true)
+				prefix = "> "
+			}
+			line := fmt.Sprintf("%s %s", padRight(limitString(s.Name, 30), 30), s.CreatedAt.In(m.timestampLocation()).Format("2006-01-02 15:04"))
+			if m.snapshotAll {
+				line += "  " + limitString(s.ProfileName, 20)
+			}
+			content.WriteString(prefix + style.Render(line) + "\n")
+		}
+		content.WriteString("\n")
+		switch {
+		case m.snapshotNaming:
+			content.WriteString(m.snapshotInput.View())
+		case m.snapshotConfirm:
+			content.WriteString(lipgloss.NewStyle().Foreground(m.theme.WarningColor()).Bold(true).
+				Render(fmt.Sprintf("Delete snapshot %s? (y/n)", m.snapshots[m.snapshotIdx].Name)))
+		default:
+			all := "a: all profiles"
+			if m.snapshotAll {
+				all = "a: this profile"
+			}
+			content.WriteString(m.renderHintLine("Enter: diff with live schema • n: new • d: delete • "+all+" • Esc: close", m.popupInnerWidth(popupWidth)))
+		}
+	}
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
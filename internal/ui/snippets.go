@@ -0,0 +1,240 @@
+// internal/ui/snippets.go
+// Snippet tab-stops: ${1:placeholder} fields in templates and suggestions that tab and shift+tab jump between.
+package ui
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tabStopPattern matches ${N} and ${N:placeholder}. Bare $N is left alone
+// since it is PostgreSQL's parameter syntax.
+var tabStopPattern = regexp.MustCompile(`\$\{(\d+)(?::([^}]*))?\}`)
+
+// tabStop is a placeholder's range in the editor text, in runes
+type tabStop struct {
+	start, end int
+}
+
+// expandSnippet replaces the tab-stops in s with their placeholder text and
+// returns their ranges in jump order: by number, with ${0} last
+func expandSnippet(s string) (string, []tabStop) {
+	matches := tabStopPattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+
+	type numbered struct {
+		n    int
+		stop tabStop
+	}
+	var (
+		b     strings.Builder
+		stops []numbered
+		last  int
+		runes int
+	)
+	for _, match := range matches {
+		b.WriteString(s[last:match[0]])
+		runes += utf8.RuneCountInString(s[last:match[0]])
+		n, _ := strconv.Atoi(s[match[2]:match[3]])
+		placeholder := ""
+		if match[4] >= 0 {
+			placeholder = s[match[4]:match[5]]
+		}
+		b.WriteString(placeholder)
+		stops = append(stops, numbered{n: n, stop: tabStop{start: runes, end: runes + utf8.RuneCountInString(placeholder)}})
+		runes += utf8.RuneCountInString(placeholder)
+		last = match[1]
+	}
+	b.WriteString(s[last:])
+
+	sort.SliceStable(stops, func(i, j int) bool {
+		if stops[i].n == 0 || stops[j].n == 0 {
+			return stops[j].n == 0 && stops[i].n != 0
+		}
+		return stops[i].n < stops[j].n
+	})
+	ordered := make([]tabStop, len(stops))
+	for i, s := range stops {
+		ordered[i] = s.stop
+	}
+	return b.String(), ordered
+}
+
+// startSnippet activates the tab-stops of a snippet inserted at rune offset
+// base and selects the first one
+func (m *Model) startSnippet(stops []tabStop, base int) {
+	m.tabStops = make([]tabStop, len(stops))
+	for i, s := range stops {
+		m.tabStops[i] = tabStop{start: base + s.start, end: base + s.end}
+	}
+	m.selectTabStop(0)
+}
+
+// selectTabStop moves the cursor to a tab-stop. Its placeholder stays
+// selected, to be replaced by typing, until the cursor moves.
+func (m *Model) selectTabStop(i int) {
+	m.tabStopIdx = i
+	stop := m.tabStops[i]
+	m.setEditorCursor(stop.start)
+	m.tabStopSelected = stop.end > stop.start
+	// A steady cursor keeps the selection highlight from blinking with it
+	m.editor.Cursor.SetMode(cursor.CursorStatic)
+}
+
+// nextTabStop jumps dir tab-stops forward or back; moving past the last one
+// ends the snippet
+func (m *Model) nextTabStop(dir int) tea.Cmd {
+	i := m.tabStopIdx + dir
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(m.tabStops) {
+		m.setEditorCursor(m.tabStops[m.tabStopIdx].end)
+		return m.endSnippet()
+	}
+	m.selectTabStop(i)
+	return nil
+}
+
+// endSnippet leaves tab-stop navigation
+func (m *Model) endSnippet() tea.Cmd {
+	if m.tabStops == nil {
+		return nil
+	}
+	m.tabStops = nil
+	m.tabStopSelected = false
+	return m.editor.Cursor.SetMode(cursor.CursorBlink)
+}
+
+// replacesSelection reports whether a key types over a selected placeholder
+func replacesSelection(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace, tea.KeyBackspace, tea.KeyDelete:
+		return true
+	}
+	return false
+}
+
+// clearTabStop deletes the active placeholder's text
+func (m *Model) clearTabStop() {
+	stop := m.tabStops[m.tabStopIdx]
+	text := []rune(m.editor.Value())
+	m.editor.SetValue(string(text[:stop.start]) + string(text[stop.end:]))
+	m.setEditorCursor(stop.start)
+	m.shiftTabStops(stop.end, stop.start-stop.end)
+}
+
+// shiftTabStops resizes the active tab-stop by delta runes and moves those
+// starting at or after its old end, at, along with it
+func (m *Model) shiftTabStops(at, delta int) {
+	for i := range m.tabStops {
+		s := &m.tabStops[i]
+		switch {
+		case i == m.tabStopIdx:
+			s.end = max(s.start, s.end+delta)
+		case s.start >= at:
+			s.start += delta
+			s.end += delta
+		}
+	}
+}
+
+// trackTabStops follows an edit made by the textarea: typing inside the
+// active tab-stop grows or shrinks it, an edit anywhere else ends the snippet
+func (m *Model) trackTabStops(beforeLen, beforeCursor int) tea.Cmd {
+	delta := utf8.RuneCountInString(m.editor.Value()) - beforeLen
+	after := m.editorOffset()
+	if delta == 0 {
+		return nil
+	}
+
+	stop := m.tabStops[m.tabStopIdx]
+	at := min(beforeCursor, after)
+	if at < stop.start || at > stop.end || (delta < 0 && at-delta > stop.end) {
+		return m.endSnippet()
+	}
+	m.shiftTabStops(stop.end, delta)
+	return nil
+}
+
+// editorOffset returns the cursor position in the editor text, in runes
+func (m Model) editorOffset() int {
+	lines := strings.Split(m.editor.Value(), "\n")
+	offset := 0
+	for _, line := range lines[:min(m.editor.Line(), len(lines))] {
+		offset += utf8.RuneCountInString(line) + 1
+	}
+	info := m.editor.LineInfo()
+	return offset + info.StartColumn + info.ColumnOffset
+}
+
+// setEditorCursor moves the cursor to a rune offset in the editor text
+func (m *Model) setEditorCursor(offset int) {
+	lines := strings.Split(m.editor.Value(), "\n")
+	row, col := 0, offset
+	for row < len(lines)-1 && col > utf8.RuneCountInString(lines[row]) {
+		col -= utf8.RuneCountInString(lines[row]) + 1
+		row++
+	}
+
+	// The textarea only moves between rows one soft-wrapped line at a time
+	for i := 0; m.editor.Line() < row && i < len(m.editor.Value()); i++ {
+		m.editor.CursorDown()
+	}
+	for i := 0; m.editor.Line() > row && i < len(m.editor.Value()); i++ {
+		m.editor.CursorUp()
+	}
+	m.editor.SetCursor(col)
+}
+
+// highlightTabStop underlines the selected placeholder in the editor view.
+// The placeholder starts at the cursor, which renders reversed.
+func (m Model) highlightTabStop(view string) string {
+	if !m.tabStopSelected {
+		return view
+	}
+	stop := m.tabStops[m.tabStopIdx]
+	at := strings.Index(view, "\x1b[7")
+	if at < 0 {
+		return view
+	}
+
+	var b strings.Builder
+	b.WriteString(view[:at])
+	remaining := stop.end - stop.start // The cursor itself covers the first rune
+	first := true
+	for i := at; i < len(view); {
+		if view[i] == '\x1b' {
+			j := i + 1
+			for j < len(view) && !(view[j] >= 'A' && view[j] <= 'Z' || view[j] >= 'a' && view[j] <= 'z') {
+				j++
+			}
+			j = min(j+1, len(view))
+			b.WriteString(view[i:j])
+			i = j
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(view[i:])
+		if r == '\n' || remaining == 0 {
+			b.WriteString(view[i:])
+			break
+		}
+		if first {
+			b.WriteRune(r)
+			first = false
+		} else {
+			b.WriteString("\x1b[4m" + string(r) + "\x1b[24m")
+		}
+		remaining--
+		i += size
+	}
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandSnippet(t *testing.T) {
+	text, stops := expandSnippet("INSERT INTO t (${2:cols}) VALUES (${1:vals})${0} -- $1 stays")
+	if text != "INSERT INTO t (cols) VALUES (vals) -- $1 stays" {
+		t.Fatalf("text = %q", text)
+	}
+	want := []tabStop{{29, 33}, {15, 19}, {34, 34}}
+	if !reflect.DeepEqual(stops, want) {
+		t.Fatalf("stops = %v, want %v", stops, want)
+	}
+
+	if text, stops := expandSnippet("SELECT 1"); text != "SELECT 1" || stops != nil {
+		t.Fatalf("plain text = %q, %v", text, stops)
+	}
+}
+
+func TestShiftTabStops(t *testing.T) {
+	m := Model{tabStops: []tabStop{{0, 4}, {10, 14}}}
+	// Typing two runes more into the first placeholder
+	m.shiftTabStops(4, 2)
+	want := []tabStop{{0, 6}, {12, 16}}
+	if !reflect.DeepEqual(m.tabStops, want) {
+		t.Fatalf("stops = %v, want %v", m.tabStops, want)
+	}
+}
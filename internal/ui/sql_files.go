@@ -0,0 +1,222 @@
+// internal/ui/sql_files.go
+// SQL files: /open and /save load a file into the editor and write the buffer back; /run executes one.
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+)
+
+// maxRecentFiles is how many opened or saved files are remembered
+const maxRecentFiles = 10
+
+// openFilePopup opens the file command prompt seeded with a command, e.g. "/open "
+func (m *Model) openFilePopup(command string) tea.Cmd {
+	if m.showFilePopup {
+		return nil
+	}
+	m.showFilePopup = true
+	m.autocompleting = false
+	m.fileIdx = -1
+	m.ensureInput(lazyFile, &m.fileInput, newFileInput)
+	m.fileInput.SetValue(command)
+	m.fileInput.CursorEnd()
+	m.popupStack.Push("file", func(m *Model) {
+		m.showFilePopup = false
+		m.fileInput.Blur()
+	})
+	return tea.Batch(m.fileInput.Focus(), textinput.Blink)
+}
+
+// parseFileCommand splits "/open path", "/save path" or "/run path"; the path may be empty
+func parseFileCommand(input string) (verb, path string, err error) {
+	input = strings.TrimSpace(input)
+	verb, path, _ = strings.Cut(input, " ")
+	path = strings.TrimSpace(path)
+	switch verb {
+	case "/open", "/save", "/run":
+		return verb, path, nil
+	}
+	return "", "", fmt.Errorf("unknown command %q, expected /open, /save or /run <file>", verb)
+}
+
+// expandPath resolves ~ and relative paths to an absolute path
+func expandPath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	return filepath.Abs(path)
+}
+
+// handleFileKeys handles keys while the file prompt is open. ↑/↓ pick a
+// recent file into the prompt, keeping the command word.
+func (m Model) handleFileKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	recent := m.config.RecentFiles
+	switch msg.String() {
+	case "esc":
+		m.closeTopPopup()
+		return m, nil, true
+	case "up", "down":
+		if len(recent) == 0 {
+			return m, nil, true
+		}
+		if msg.String() == "up" {
+			m.fileIdx = max(m.fileIdx-1, 0)
+		} else {
+			m.fileIdx = min(m.fileIdx+1, len(recent)-1)
+		}
+		verb, _, _ := strings.Cut(strings.TrimSpace(m.fileInput.Value()), " ")
+		if verb != "/save" && verb != "/run" {
+			verb = "/open"
+		}
+		m.fileInput.SetValue(verb + " " + recent[m.fileIdx])
+		m.fileInput.CursorEnd()
+		return m, nil, true
+	case "enter":
+		verb, path, err := parseFileCommand(m.fileInput.Value())
+		if err == nil && path == "" {
+			path = m.currentFile
+		}
+		if err == nil && path == "" {
+			err = fmt.Errorf("%s needs a file name", verb)
+		}
+		if err == nil {
+			path, err = expandPath(path)
+		}
+		if err != nil {
+			m.errorMsg = err.Error()
+			return m, nil, true
+		}
+		m.closeTopPopup()
+		switch verb {
+		case "/open":
+			return m, openSQLFileCmd(path), true
+		case "/run":
+			if m.driver == nil {
+				m.errorMsg = "No database connection"
+				return m, nil, true
+			}
+			return m, loadScriptCmd(path), true
+		}
+		return m, saveSQLFileCmd(path, m.editor.Value()), true
+	}
+
+	var cmd tea.Cmd
+	m.fileInput, cmd = m.fileInput.Update(msg)
+	return m, cmd, true
+}
+
+func openSQLFileCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		content, err := os.ReadFile(path)
+		return SQLFileMsg{Path: path, Content: string(content), Opened: true, Err: err}
+	}
+}
+
+func saveSQLFileCmd(path, content string) tea.Cmd {
+	return func() tea.Msg {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		err := os.WriteFile(path, []byte(content), 0o644)
+		return SQLFileMsg{Path: path, Err: err}
+	}
+}
+
+// handleSQLFile loads an opened file into the editor or reports a save, and remembers the file
+func (m Model) handleSQLFile(msg SQLFileMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	}
+	m.currentFile = msg.Path
+	if msg.Opened {
+		content := strings.TrimRight(msg.Content, "\n")
+		if len(content) > m.editor.CharLimit {
+			m.editor.CharLimit = len(content)
+		}
+		m.setEditorValue(content)
+		m.mode = InsertMode
+		m.editor.Focus()
+		m.statusMsg = fmt.Sprintf("Opened %s (%d lines)", filepath.Base(msg.Path), strings.Count(content, "\n")+1)
+	} else {
+		m.statusMsg = "Saved " + filepath.Base(msg.Path)
+	}
+
+	m.config.RecentFiles = addRecentFile(m.config.RecentFiles, msg.Path)
+	return m, m.saveRecentFilesCmd()
+}
+
+// addRecentFile moves path to the front of the recent files list
+func addRecentFile(recent []string, path string) []string {
+	list := []string{path}
+	for _, p := range recent {
+		if p != path && len(list) < maxRecentFiles {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// saveRecentFilesCmd persists the config off the update loop
+func (m Model) saveRecentFilesCmd() tea.Cmd {
+	cfg := m.config
+	return func() tea.Msg {
+		if err := cfg.Save(); err != nil {
+			return SQLFileMsg{Err: fmt.Errorf("failed to save recent files: %w", err)}
+		}
+		return nil
+	}
+}
+
+func (m Model) renderFilePopup(main string) string {
+	var content strings.Builder
+	popupWidth := 64
+	if popupWidth > m.width-10 {
+		popupWidth = m.width - 10
+	}
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("SQL File")
+	content.WriteString(title)
+	content.WriteString("\n\n")
+	content.WriteString(m.fileInput.View())
+	content.WriteString("\n\n")
+
+	content.WriteString(lipgloss.NewStyle().Foreground(m.theme.TextSecondary()).Render("Recent files"))
+	content.WriteString("\n")
+	if len(m.config.RecentFiles) == 0 {
+		content.WriteString(lipgloss.NewStyle().Faint(true).Render("  (none)"))
+		content.WriteString("\n")
+	}
+	for i, p := range m.config.RecentFiles {
+		style := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
+		prefix := "  "
+		if i == m.fileIdx {
+			style = lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Bold(true)
+			prefix = "> "
+		}
+		content.WriteString(prefix + style.Render(limitString(p, 56)) + "\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(m.renderHintLine("/open <file> • /save [file] • /run <file> • ↑/↓: recent • Enter: go • Esc: close", m.popupInnerWidth(popupWidth)))
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
@@ -0,0 +1,27 @@
+// internal/ui/sql_files_test.go
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFileCommand(t *testing.T) {
+	verb, path, err := parseFileCommand("  /open  db/001 init.sql ")
+	if err != nil || verb != "/open" || path != "db/001 init.sql" {
+		t.Errorf("parseFileCommand = %q, %q, %v", verb, path, err)
+	}
+	if _, path, err := parseFileCommand("/save"); err != nil || path != "" {
+		t.Errorf("/save without a path = %q, %v", path, err)
+	}
+	if _, _, err := parseFileCommand("/exec x.sql"); err == nil {
+		t.Error("unknown command accepted")
+	}
+}
+
+func TestAddRecentFile(t *testing.T) {
+	got := addRecentFile([]string{"/a.sql", "/b.sql", "/c.sql"}, "/b.sql")
+	if want := []string{"/b.sql", "/a.sql", "/c.sql"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("addRecentFile = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+// internal/ui/styles/background.go
+// Terminal background detection for automatic light/dark themes.
+package styles
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	darkBackground = true
+	detected       bool
+)
+
+// DetectBackground checks the terminal background once, before the TUI takes over:
+// COLORFGBG when set, otherwise an OSC 11 query. Returns true for dark backgrounds.
+func DetectBackground() bool {
+	if dark, ok := parseColorFGBG(os.Getenv("COLORFGBG")); ok {
+		darkBackground = dark
+	} else {
+		darkBackground = lipgloss.HasDarkBackground()
+	}
+	detected = true
+	return darkBackground
+}
+
+// DarkBackground returns the detected background. Without a startup query it
+// falls back to COLORFGBG and then assumes dark, since the TUI now owns stdin.
+func DarkBackground() bool {
+	if detected {
+		return darkBackground
+	}
+	if dark, ok := parseColorFGBG(os.Getenv("COLORFGBG")); ok {
+		return dark
+	}
+	return true
+}
+
+// parseColorFGBG reads the background from "fg;bg" or "fg;other;bg".
+// ANSI colors 7 and 9-15 are light; 0-6 and 8 are dark.
+func parseColorFGBG(v string) (dark, ok bool) {
+	if v == "" {
+		return false, false
+	}
+	parts := strings.Split(v, ";")
+	bg, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil || bg < 0 || bg > 15 {
+		return false, false
+	}
+	return bg != 7 && bg < 9, true
+}
@@ -5,8 +5,10 @@ import (
 	"github.com/nhath/ezdb/internal/config"
 )
 
-var (
-	// Colors (exported via getter functions below)
+// Theme holds the colors and styles of one theme. Build it with New and pass
+// it to whatever renders; nothing in the package changes after New returns.
+type Theme struct {
+	// Colors, read with the getters below
 	textPrimary   lipgloss.Color
 	textSecondary lipgloss.Color
 	textFaint     lipgloss.Color
@@ -44,125 +46,135 @@ var (
 	SystemMessageStyle      lipgloss.Style
 	WarningStyle            lipgloss.Style
 	PopupStyle              lipgloss.Style
-)
+}
+
+// Color getters for use in components
+func (t *Theme) TextPrimary() lipgloss.Color    { return t.textPrimary }
+func (t *Theme) TextSecondary() lipgloss.Color  { return t.textSecondary }
+func (t *Theme) TextFaint() lipgloss.Color      { return t.textFaint }
+func (t *Theme) AccentColor() lipgloss.Color    { return t.accentColor }
+func (t *Theme) SuccessColor() lipgloss.Color   { return t.successColor }
+func (t *Theme) ErrorColor() lipgloss.Color     { return t.errorColor }
+func (t *Theme) HighlightColor() lipgloss.Color { return t.highlightColor }
+func (t *Theme) WarningColor() lipgloss.Color   { return t.warningColor }
+func (t *Theme) BgPrimary() lipgloss.Color      { return t.bgPrimary }
+func (t *Theme) BgSecondary() lipgloss.Color    { return t.bgSecondary }
+func (t *Theme) CardBg() lipgloss.Color         { return t.cardBg }
+func (t *Theme) PopupBg() lipgloss.Color        { return t.popupBg }
+func (t *Theme) BorderColor() lipgloss.Color    { return t.borderColor }
+func (t *Theme) SelectedBg() lipgloss.Color     { return t.selectedBg }
+
+// OnColor returns a foreground that stays readable on the given badge background
+func (t *Theme) OnColor(bg lipgloss.Color) lipgloss.Color {
+	return lipgloss.Color(config.ReadableOn(string(bg), string(t.bgPrimary), string(t.textPrimary)))
+}
+
+// New builds the colors and styles of theme
+func New(theme config.Theme) *Theme {
+	t := &Theme{}
+	theme = theme.Readable()
 
-// Color getter functions for use in components
-func TextPrimary() lipgloss.Color    { return textPrimary }
-func TextSecondary() lipgloss.Color  { return textSecondary }
-func TextFaint() lipgloss.Color      { return textFaint }
-func AccentColor() lipgloss.Color    { return accentColor }
-func SuccessColor() lipgloss.Color   { return successColor }
-func ErrorColor() lipgloss.Color     { return errorColor }
-func HighlightColor() lipgloss.Color { return highlightColor }
-func WarningColor() lipgloss.Color   { return warningColor }
-func BgPrimary() lipgloss.Color      { return bgPrimary }
-func BgSecondary() lipgloss.Color    { return bgSecondary }
-func CardBg() lipgloss.Color         { return cardBg }
-func PopupBg() lipgloss.Color        { return popupBg }
-func BorderColor() lipgloss.Color    { return borderColor }
-func SelectedBg() lipgloss.Color     { return selectedBg }
-
-// Init initializes the global styles based on the provided configuration theme
-func Init(theme config.Theme) {
 	// Initialize Colors
-	textPrimary = lipgloss.Color(theme.TextPrimary)
-	textSecondary = lipgloss.Color(theme.TextSecondary)
-	textFaint = lipgloss.Color(theme.TextFaint)
-
-	accentColor = lipgloss.Color(theme.Accent)
-	successColor = lipgloss.Color(theme.Success)
-	errorColor = lipgloss.Color(theme.Error)
-	highlightColor = lipgloss.Color(theme.Highlight)
-	warningColor = lipgloss.Color(theme.Warning)
-
-	bgPrimary = lipgloss.Color(theme.BgPrimary)
-	bgSecondary = lipgloss.Color(theme.BgSecondary)
-	cardBg = lipgloss.Color(theme.CardBg)
-	popupBg = lipgloss.Color(theme.PopupBg)
-	borderColor = lipgloss.Color(theme.BorderColor)
-	selectedBg = lipgloss.Color(theme.SelectedBg)
+	t.textPrimary = lipgloss.Color(theme.TextPrimary)
+	t.textSecondary = lipgloss.Color(theme.TextSecondary)
+	t.textFaint = lipgloss.Color(theme.TextFaint)
+
+	t.accentColor = lipgloss.Color(theme.Accent)
+	t.successColor = lipgloss.Color(theme.Success)
+	t.errorColor = lipgloss.Color(theme.Error)
+	t.highlightColor = lipgloss.Color(theme.Highlight)
+	t.warningColor = lipgloss.Color(theme.Warning)
+
+	t.bgPrimary = lipgloss.Color(theme.BgPrimary)
+	t.bgSecondary = lipgloss.Color(theme.BgSecondary)
+	t.cardBg = lipgloss.Color(theme.CardBg)
+	t.popupBg = lipgloss.Color(theme.PopupBg)
+	t.borderColor = lipgloss.Color(theme.BorderColor)
+	t.selectedBg = lipgloss.Color(theme.SelectedBg)
 
 	// Initialize Styles
-	StatusBarStyle = lipgloss.NewStyle().
-		Foreground(textPrimary).
-		Background(bgSecondary)
+	t.StatusBarStyle = lipgloss.NewStyle().
+		Foreground(t.textPrimary).
+		Background(t.bgSecondary)
 
-	ModeStyle = lipgloss.NewStyle().
+	t.ModeStyle = lipgloss.NewStyle().
 		Bold(true).
 		Padding(0, 1).
-		Background(successColor).
-		Foreground(bgPrimary)
+		Background(t.successColor).
+		Foreground(t.OnColor(t.successColor))
 
-	InsertModeStyle = lipgloss.NewStyle().
+	t.InsertModeStyle = lipgloss.NewStyle().
 		Bold(true).
 		Padding(0, 1).
-		Background(accentColor).
-		Foreground(bgPrimary)
+		Background(t.accentColor).
+		Foreground(t.OnColor(t.accentColor))
 
-	ConnectionStyle = lipgloss.NewStyle().
+	t.ConnectionStyle = lipgloss.NewStyle().
 		Padding(0, 1).
-		Background(cardBg).
-		Foreground(textPrimary)
+		Background(t.cardBg).
+		Foreground(t.textPrimary)
 
-	QueryStyle = lipgloss.NewStyle().
+	t.QueryStyle = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(textPrimary)
+		Foreground(t.textPrimary)
 
-	MetaStyle = lipgloss.NewStyle().
-		Foreground(textFaint).
+	t.MetaStyle = lipgloss.NewStyle().
+		Foreground(t.textFaint).
 		Italic(true)
 
-	SelectionStyle = lipgloss.NewStyle()
-	ItemStyle = lipgloss.NewStyle()
+	t.SelectionStyle = lipgloss.NewStyle()
+	t.ItemStyle = lipgloss.NewStyle()
 
-	InputStyle = lipgloss.NewStyle().
+	t.InputStyle = lipgloss.NewStyle().
 		Border(lipgloss.NormalBorder(), true, false, false, false).
-		BorderForeground(textFaint).
+		BorderForeground(t.textFaint).
 		Padding(0, 0).
 		MarginTop(0)
 
-	PromptStyle = lipgloss.NewStyle().
+	t.PromptStyle = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(accentColor).
+		Foreground(t.accentColor).
 		MarginRight(1)
 
-	SuggestionBoxStyle = lipgloss.NewStyle().
+	t.SuggestionBoxStyle = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(textFaint).
-		// Background(bgPrimary).
+		BorderForeground(t.textFaint).
+		// Background(t.bgPrimary).
 		Padding(0, 1)
 
-	SuggestionItemStyle = lipgloss.NewStyle().
-		Foreground(textPrimary)
+	t.SuggestionItemStyle = lipgloss.NewStyle().
+		Foreground(t.textPrimary)
 
-	SuggestionSelectedStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#000000")).
-		Background(highlightColor).
+	t.SuggestionSelectedStyle = lipgloss.NewStyle().
+		Foreground(t.OnColor(t.highlightColor)).
+		Background(t.highlightColor).
 		Bold(true)
 
-	SuccessStyle = lipgloss.NewStyle().
-		Foreground(successColor)
+	t.SuccessStyle = lipgloss.NewStyle().
+		Foreground(t.successColor)
 
-	ErrorStyle = lipgloss.NewStyle().
-		Foreground(errorColor).
+	t.ErrorStyle = lipgloss.NewStyle().
+		Foreground(t.errorColor).
 		Bold(true)
 
-	ErrorGrayStyle = lipgloss.NewStyle().
-		Foreground(textFaint).
+	t.ErrorGrayStyle = lipgloss.NewStyle().
+		Foreground(t.textFaint).
 		Bold(true)
 
-	SystemMessageStyle = lipgloss.NewStyle().
-		Foreground(highlightColor).
+	t.SystemMessageStyle = lipgloss.NewStyle().
+		Foreground(t.highlightColor).
 		Bold(true)
 
-	WarningStyle = lipgloss.NewStyle().
-		Foreground(bgPrimary).
-		Background(warningColor).
+	t.WarningStyle = lipgloss.NewStyle().
+		Foreground(t.OnColor(t.warningColor)).
+		Background(t.warningColor).
 		Bold(true).
 		Padding(0, 1)
 
-	PopupStyle = lipgloss.NewStyle().
+	t.PopupStyle = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(highlightColor).
+		BorderForeground(t.highlightColor).
 		Padding(1, 2)
+
+	return t
 }
@@ -0,0 +1,159 @@
+// internal/ui/table_actions.go
+// Truncate and drop from the schema browser, confirmed by typing the table name.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/db"
+	"github.com/nhath/ezdb/internal/ui/components/schemabrowser"
+)
+
+// tableActionTimeout bounds a TRUNCATE or DROP from the schema browser
+const tableActionTimeout = time.Minute
+
+// tableActionStatement returns the statement of action ("TRUNCATE" or
+// "DROP") on table. SQLite has no TRUNCATE and empties the table with DELETE.
+func tableActionStatement(dt db.DriverType, action, table string) string {
+	if action == "TRUNCATE" && dt == db.SQLite {
+		return "DELETE FROM " + table
+	}
+	return action + " TABLE " + table
+}
+
+// openTableActionPopup asks to confirm action on a table by typing its name.
+func (m *Model) openTableActionPopup(action, tableName string) {
+	if m.showTableAction {
+		return
+	}
+	m.showTableAction = true
+	m.autocompleting = false
+	m.ensureInput(lazyTableAction, &m.tableActionInput, newTableActionInput)
+	m.tableActionInput.SetValue("")
+	m.tableActionInput.Placeholder = tableName
+	m.tableActionInput.Focus()
+	m.tableAction, m.tableActionTable = action, tableName
+	m.popupStack.Push("table action", func(m *Model) {
+		m.showTableAction = false
+		m.tableActionInput.Blur()
+		m.tableAction, m.tableActionTable = "", ""
+	})
+}
+
+// handleTableActionKeys handles keys while the confirmation is open. Enter
+// runs the statement only once the typed name matches the table.
+func (m Model) handleTableActionKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc":
+		m.closeTopPopup()
+		return m, nil, true
+	case "enter":
+		if m.tableActionInput.Value() != m.tableActionTable {
+			m.errorMsg = fmt.Sprintf("Type %s to confirm", m.tableActionTable)
+			return m, nil, true
+		}
+		action, table := m.tableAction, m.tableActionTable
+		m.closeTopPopup()
+		if m.driver == nil {
+			return m, nil, true
+		}
+		if m.loading {
+			m.errorMsg = "Wait for the running query before changing tables"
+			return m, nil, true
+		}
+		stmt := tableActionStatement(m.driver.Type(), action, table)
+		if m.profile != nil {
+			for _, v := range checkGuards(stmt, m.profile.Guards) {
+				if v.policy == config.GuardBlock {
+					m.errorMsg = "Blocked by query guard: " + v.reason
+					return m, nil, true
+				}
+			}
+		}
+		m.loading = true
+		return m, m.tableActionCmd(action, table, stmt), true
+	}
+	var cmd tea.Cmd
+	m.tableActionInput, cmd = m.tableActionInput.Update(msg)
+	return m, cmd, true
+}
+
+func (m Model) tableActionCmd(action, table, stmt string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), tableActionTimeout)
+		defer cancel()
+		_, err := m.execute(ctx, stmt)
+		return TableActionMsg{Action: action, Table: table, Err: err}
+	}
+}
+
+// handleTableAction reports a truncate or drop and reloads the schema
+func (m Model) handleTableAction(msg TableActionMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.Err != nil {
+		m.errorMsg = fmt.Sprintf("%s %s failed: %v", msg.Action, msg.Table, msg.Err)
+		return m, nil
+	}
+	if msg.Action == "DROP" {
+		m.statusMsg = "Dropped " + msg.Table
+	} else {
+		m.statusMsg = "Truncated " + msg.Table
+	}
+	if m.driver == nil {
+		return m, nil
+	}
+	m.loadingTables = true
+	return m, schemabrowser.LoadSchemaCmd(m.driver)
+}
+
+func (m Model) renderTableActionPopup(main string) string {
+	var content strings.Builder
+
+	warn := lipgloss.NewStyle().Bold(true).Foreground(m.theme.ErrorColor())
+	title := "Truncate " + m.tableActionTable
+	detail := "Every row of the table will be deleted."
+	if m.tableAction == "DROP" {
+		title = "Drop " + m.tableActionTable
+		detail = "The table and all its data will be removed."
+	}
+	content.WriteString(warn.Render(title))
+	content.WriteString("\n\n")
+	content.WriteString(detail + " This cannot be undone.\n")
+	if m.driver != nil {
+		stmt := tableActionStatement(m.driver.Type(), m.tableAction, m.tableActionTable)
+		content.WriteString(lipgloss.NewStyle().Faint(true).Render(stmt))
+		content.WriteString("\n")
+	}
+	content.WriteString("\nType ")
+	content.WriteString(lipgloss.NewStyle().Bold(true).Render(m.tableActionTable))
+	content.WriteString(" to confirm:\n")
+	content.WriteString(m.tableActionInput.View())
+	content.WriteString("\n\n")
+
+	confirmed := m.tableActionInput.Value() == m.tableActionTable
+	hint := lipgloss.NewStyle().Faint(true)
+	if confirmed {
+		hint = warn
+	}
+	content.WriteString(hint.Render("Enter: " + strings.ToLower(m.tableAction) + " • Esc: cancel"))
+
+	popupWidth := 60
+	if popupWidth > m.width-10 {
+		popupWidth = m.width - 10
+	}
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
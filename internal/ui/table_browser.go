@@ -0,0 +1,381 @@
+// internal/ui/table_browser.go
+// Table data browser: pages through a table with sorting and filters built into the SQL.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/evertras/bubble-table/table"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// tableBrowser is the state of the table data browser
+type tableBrowser struct {
+	Table    string
+	Page     int
+	PageSize int
+	SortCol  string // "" for the table's natural order
+	SortDesc bool
+	Filter   string // Filter expression as typed, see parseBrowseFilter
+	Query    string // SQL of the page shown
+	Result   *db.QueryResult
+	HasMore  bool
+	Loading  bool
+	seq      int // Fetch generation; stale results are dropped
+	table    table.Model
+}
+
+// browseFilterTerm matches one "column op value" filter term
+var browseFilterTerm = regexp.MustCompile(`^\s*([^\s=!<>~]+)\s*(!=|<>|<=|>=|=|<|>|~)\s*(.*?)\s*$`)
+
+// quoteIdent quotes a column name for the driver's dialect
+func quoteIdent(dt db.DriverType, name string) string {
+	if dt == db.MySQL || dt == db.BigQuery {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteLiteral renders a filter value as a SQL literal. Numbers and booleans
+// compared with numeric or boolean columns stay bare; when the column type is
+// unknown, only the strictly typed dialects get bare numbers.
+func quoteLiteral(dt db.DriverType, value, colType string) string {
+	t := strings.ToLower(colType)
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		numeric := strings.Contains(t, "int") || strings.Contains(t, "float") || strings.Contains(t, "double") ||
+			strings.Contains(t, "numeric") || strings.Contains(t, "decimal") || strings.Contains(t, "real") ||
+			strings.Contains(t, "number") || strings.Contains(t, "serial")
+		if numeric || (t == "" && (dt == db.BigQuery || dt == db.Cassandra)) {
+			return value
+		}
+	}
+	if lv := strings.ToLower(value); (lv == "true" || lv == "false") && strings.HasPrefix(t, "bool") {
+		return lv
+	}
+	if dt == db.MySQL || dt == db.BigQuery {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+		return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// parseBrowseFilter turns "col op value, ..." into a WHERE condition. Operators
+// are = != <> < <= > >= and ~ (contains); "= null" and "!= null" test for NULL.
+// columns maps known column names to their types and rejects unknown names.
+func parseBrowseFilter(dt db.DriverType, expr string, columns []db.Column) (string, error) {
+	var conds []string
+	for _, part := range strings.Split(expr, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		match := browseFilterTerm.FindStringSubmatch(part)
+		if match == nil {
+			return "", fmt.Errorf("cannot parse filter %q, expected: column op value", strings.TrimSpace(part))
+		}
+		name, op, value := match[1], match[2], match[3]
+		if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		col, ok := findColumn(columns, name)
+		if !ok {
+			return "", fmt.Errorf("unknown column %q", name)
+		}
+		ident := quoteIdent(dt, col.Name)
+
+		switch {
+		case strings.EqualFold(value, "null") && op == "=":
+			conds = append(conds, ident+" IS NULL")
+		case strings.EqualFold(value, "null") && (op == "!=" || op == "<>"):
+			conds = append(conds, ident+" IS NOT NULL")
+		case op == "~":
+			if dt == db.Cassandra {
+				return "", fmt.Errorf("contains (~) is not supported by CQL")
+			}
+			conds = append(conds, ident+" LIKE "+quoteLiteral(dt, "%"+value+"%", ""))
+		default:
+			conds = append(conds, ident+" "+op+" "+quoteLiteral(dt, value, col.Type))
+		}
+	}
+	return strings.Join(conds, " AND "), nil
+}
+
+// findColumn looks a column up by name, ignoring case
+func findColumn(columns []db.Column, name string) (db.Column, bool) {
+	for _, c := range columns {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+	return db.Column{}, false
+}
+
+// buildBrowseQuery builds the SELECT for one page. It fetches one extra row
+// to tell whether another page follows.
+func buildBrowseQuery(dt db.DriverType, b *tableBrowser, where string) string {
+	var q strings.Builder
+	fmt.Fprintf(&q, "SELECT * FROM %s", b.Table)
+	if where != "" {
+		q.WriteString(" WHERE " + where)
+	}
+	if b.SortCol != "" {
+		dir := "ASC"
+		if b.SortDesc {
+			dir = "DESC"
+		}
+		fmt.Fprintf(&q, " ORDER BY %s %s", quoteIdent(dt, b.SortCol), dir)
+	}
+	fmt.Fprintf(&q, " LIMIT %d", b.PageSize+1)
+	if b.Page > 0 {
+		fmt.Fprintf(&q, " OFFSET %d", b.Page*b.PageSize)
+	}
+	if dt == db.Cassandra && where != "" {
+		q.WriteString(" ALLOW FILTERING")
+	}
+	return q.String()
+}
+
+// browseColumns returns the browsed table's columns from the loaded schema,
+// falling back to the names of the last fetched page
+func (m Model) browseColumns() []db.Column {
+	if cols := m.columns[m.browser.Table]; len(cols) > 0 {
+		return cols
+	}
+	var cols []db.Column
+	if m.browser.Result != nil {
+		for _, name := range m.browser.Result.Columns {
+			cols = append(cols, db.Column{Name: name})
+		}
+	}
+	return cols
+}
+
+// openBrowsePopup opens the data browser on a table and fetches its first page
+func (m Model) openBrowsePopup(tableName string) (Model, tea.Cmd) {
+	if m.showBrowsePopup || m.driver == nil {
+		return m, nil
+	}
+	m.browser = &tableBrowser{Table: tableName, PageSize: max(m.height-18, 5)}
+	m.showBrowsePopup = true
+	m.autocompleting = false
+	m.popupStack.Push("browse", func(m *Model) {
+		m.showBrowsePopup = false
+		m.browseFilterActive = false
+		m.browser = nil
+	})
+	return m.fetchBrowsePage()
+}
+
+// fetchBrowsePage runs the query for the browser's current page
+func (m Model) fetchBrowsePage() (Model, tea.Cmd) {
+	b := *m.browser
+	dt := m.driver.Type()
+	if dt == db.Cassandra && (b.Page > 0 || b.SortCol != "") {
+		m.errorMsg = "Paging and sorting are not supported by CQL"
+		return m, nil
+	}
+	where, err := parseBrowseFilter(dt, b.Filter, m.browseColumns())
+	if err != nil {
+		m.errorMsg = err.Error()
+		return m, nil
+	}
+
+	b.seq++
+	b.Loading = true
+	b.Query = buildBrowseQuery(dt, &b, where)
+	m.browser = &b
+
+	execute, query, seq := m.execute, b.Query, b.seq
+	return m, func() tea.Msg {
+		result, err := execute(context.Background(), query)
+		return BrowsePageMsg{Seq: seq, Result: result, Err: err}
+	}
+}
+
+// handleBrowsePage shows a fetched page, trimming the look-ahead row
+func (m Model) handleBrowsePage(msg BrowsePageMsg) (Model, tea.Cmd) {
+	if m.browser == nil || msg.Seq != m.browser.seq {
+		return m, nil
+	}
+	b := *m.browser
+	b.Loading = false
+	if msg.Err != nil {
+		m.browser = &b
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	}
+
+	result := *msg.Result
+	b.HasMore = len(result.Rows) > b.PageSize
+	if b.HasMore {
+		result.Rows = result.Rows[:b.PageSize]
+		result.RowCount = b.PageSize
+	}
+	b.Result = &result
+	b.table = m.resultTables().FromQueryResult(&result, 0).
+		WithPageSize(b.PageSize).
+		WithFooterVisibility(false).
+		WithMaxTotalWidth(max(m.width-20, 50)).
+		WithHorizontalFreezeColumnCount(m.frozenColumns(b.Table, result.Columns))
+	m.browser = &b
+	return m, nil
+}
+
+// handleBrowseFilterKeys edits the filter expression; enter applies it from the first page
+func (m Model) handleBrowseFilterKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.browseFilterActive = false
+		m.browseFilterInput.Blur()
+		b := *m.browser
+		b.Filter = strings.TrimSpace(m.browseFilterInput.Value())
+		b.Page = 0
+		m.browser = &b
+		model, cmd := m.fetchBrowsePage()
+		return model, cmd, true
+	case tea.KeyEsc:
+		m.browseFilterActive = false
+		m.browseFilterInput.Blur()
+		return m, nil, true
+	}
+	var cmd tea.Cmd
+	m.browseFilterInput, cmd = m.browseFilterInput.Update(msg)
+	return m, cmd, true
+}
+
+// handleBrowseKeys handles keys while the data browser is open
+func (m Model) handleBrowseKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	b := *m.browser
+	switch {
+	case matchKey(msg, m.config.Keys.NextPage):
+		if !b.HasMore || b.Loading {
+			return m, nil, true
+		}
+		b.Page++
+	case matchKey(msg, m.config.Keys.PrevPage):
+		if b.Page == 0 || b.Loading {
+			return m, nil, true
+		}
+		b.Page--
+	case matchKey(msg, m.config.Keys.Filter):
+		m.browseFilterActive = true
+		m.ensureInput(lazyBrowseFilter, &m.browseFilterInput, newBrowseFilterInput)
+		m.browseFilterInput.SetValue(b.Filter)
+		m.browseFilterInput.CursorEnd()
+		return m, tea.Batch(m.browseFilterInput.Focus(), textinput.Blink), true
+	case msg.String() == "s":
+		// Cycle the sort column: none, then each column in turn
+		b.SortCol, b.SortDesc = nextSortColumn(m.browseColumns(), b.SortCol), false
+		b.Page = 0
+	case msg.String() == "r":
+		if b.SortCol == "" {
+			return m, nil, true
+		}
+		b.SortDesc = !b.SortDesc
+		b.Page = 0
+	case msg.String() == "c":
+		b.SortCol, b.SortDesc, b.Filter, b.Page = "", false, "", 0
+	case msg.String() == "i":
+		// Hand the page's query over to the editor
+		query := b.Query
+		m.closeTopPopup()
+		m.setEditorValue(query)
+		m.mode = InsertMode
+		m.editor.Focus()
+		return m, textinput.Blink, true
+	case matchKey(msg, m.config.Keys.Help):
+		m.openHelpPopup()
+		return m, nil, true
+	default:
+		var cmd tea.Cmd
+		b.table, cmd = b.table.Update(msg)
+		m.browser = &b
+		return m, cmd, true
+	}
+	m.browser = &b
+	model, cmd := m.fetchBrowsePage()
+	return model, cmd, true
+}
+
+// nextSortColumn returns the column after current, or "" after the last one
+func nextSortColumn(columns []db.Column, current string) string {
+	if current == "" {
+		if len(columns) == 0 {
+			return ""
+		}
+		return columns[0].Name
+	}
+	for i, c := range columns {
+		if c.Name == current && i+1 < len(columns) {
+			return columns[i+1].Name
+		}
+	}
+	return ""
+}
+
+func (m Model) renderBrowsePopup(main string) string {
+	b := m.browser
+	var content strings.Builder
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Browse: " + b.Table)
+	content.WriteString(title)
+
+	info := fmt.Sprintf("  page %d", b.Page+1)
+	if b.Result != nil && len(b.Result.Rows) > 0 {
+		first := b.Page*b.PageSize + 1
+		info += fmt.Sprintf(" • rows %d–%d", first, first+len(b.Result.Rows)-1)
+	}
+	if b.SortCol != "" {
+		arrow := "↑"
+		if b.SortDesc {
+			arrow = "↓"
+		}
+		info += fmt.Sprintf(" • sort: %s %s", b.SortCol, arrow)
+	}
+	if b.Filter != "" {
+		info += " • filter: " + b.Filter
+	}
+	if b.Loading {
+		info += " • loading..."
+	}
+	content.WriteString(lipgloss.NewStyle().Foreground(m.theme.TextSecondary()).Render(info))
+	content.WriteString("\n")
+	content.WriteString(lipgloss.NewStyle().Faint(true).Render(limitString(b.Query, max(m.width-20, 20))))
+	content.WriteString("\n\n")
+
+	switch {
+	case b.Result == nil:
+		content.WriteString("Loading...")
+	case len(b.Result.Rows) == 0:
+		content.WriteString("(No rows)")
+	default:
+		content.WriteString(b.table.View())
+	}
+	content.WriteString("\n\n")
+
+	if m.browseFilterActive {
+		content.WriteString(m.browseFilterInput.View())
+	} else {
+		content.WriteString(lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf(
+			"%s/%s:page • %s:filter • s:sort • r:reverse • c:clear • i:edit query • %s:close",
+			firstKey(m.config.Keys.NextPage, "n"), firstKey(m.config.Keys.PrevPage, "b"),
+			firstKey(m.config.Keys.Filter, "/"), firstKey(m.config.Keys.Exit, "q"))))
+	}
+
+	popupBox := m.theme.PopupStyle.
+		Width(max(m.width-10, 60)).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
@@ -0,0 +1,35 @@
+// internal/ui/table_browser_test.go
+package ui
+
+import (
+	"testing"
+
+	"github.com/nhath/ezdb/internal/db"
+)
+
+func TestBrowseQuery(t *testing.T) {
+	cols := []db.Column{{Name: "id", Type: "integer"}, {Name: "name", Type: "text"}, {Name: "deleted_at", Type: "timestamp"}}
+	where, err := parseBrowseFilter(db.Postgres, "ID >= 10, name ~ o'brien, deleted_at = null", cols)
+	if err != nil {
+		t.Fatalf("parseBrowseFilter: %v", err)
+	}
+	b := &tableBrowser{Table: "users", Page: 2, PageSize: 20, SortCol: "name", SortDesc: true}
+	want := `SELECT * FROM users WHERE "id" >= 10 AND "name" LIKE '%o''brien%' AND "deleted_at" IS NULL ORDER BY "name" DESC LIMIT 21 OFFSET 40`
+	if got := buildBrowseQuery(db.Postgres, b, where); got != want {
+		t.Errorf("query =\n%s\nwant\n%s", got, want)
+	}
+
+	where, _ = parseBrowseFilter(db.MySQL, `name = "it's"`, cols)
+	if want := "`name` = 'it\\'s'"; where != want {
+		t.Errorf("MySQL where = %s, want %s", where, want)
+	}
+}
+
+func TestBrowseFilterErrors(t *testing.T) {
+	cols := []db.Column{{Name: "id"}}
+	for _, expr := range []string{"missing = 1", "id", "id = 1; DROP TABLE x, nope > 2"} {
+		if _, err := parseBrowseFilter(db.SQLite, expr, cols); err == nil {
+			t.Errorf("parseBrowseFilter(%q) succeeded", expr)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+// internal/ui/template_editor.go
+// Query template manager: add, edit, delete and reorder the configured templates from the template popup and save them to config.
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/config"
+)
+
+// Inputs of the template form, in the order tab visits them
+const (
+	templateName = iota
+	templateQuery
+	templateDialect // Query for the connected driver only
+	templateFields
+)
+
+// templateDialect returns the driver type whose dialect query the form edits
+func (m Model) templateDialect() string {
+	if m.driver == nil {
+		return ""
+	}
+	return string(m.driver.Type())
+}
+
+// openTemplateEditor opens the template manager over the template popup.
+func (m *Model) openTemplateEditor() {
+	if m.showTemplateEditor {
+		return
+	}
+	m.showTemplateEditor = true
+	m.templateEditIdx = min(m.templateIdx, max(len(m.config.QueryTemplates)-1, 0))
+	m.templateForm = false
+	m.templateMsg = ""
+	m.popupStack.Push("templateEditor", func(m *Model) {
+		m.showTemplateEditor = false
+		m.templateForm = false
+		m.templateIdx = min(m.templateIdx, max(len(m.queryTemplates())-1, 0))
+	})
+}
+
+// openTemplateForm starts editing the template at idx, or a new one when idx
+// is past the end
+func (m *Model) openTemplateForm(idx int) tea.Cmd {
+	m.ensureTemplateInputs()
+	var t config.QueryTemplate
+	if idx < len(m.config.QueryTemplates) {
+		t = m.config.QueryTemplates[idx]
+	}
+	dialect := m.templateDialect()
+	m.templateInputs[templateName].SetValue(t.Name)
+	m.templateInputs[templateQuery].SetValue(t.Query)
+	m.templateInputs[templateDialect].SetValue(t.Dialects[dialect])
+	m.templateInputs[templateDialect].Prompt = fmt.Sprintf("%-7s", dialect+":")
+	m.templateEditIdx = idx
+	m.templateForm = true
+	m.templateMsg = ""
+	return m.focusTemplateField(templateName)
+}
+
+// ensureTemplateInputs builds the form inputs the first time it opens
+func (m *Model) ensureTemplateInputs() {
+	if m.builtInputs&lazyTemplate == 0 {
+		m.templateInputs = newTemplateInputs()
+		m.builtInputs |= lazyTemplate
+	}
+}
+
+// focusTemplateField moves the focus of the template form to field
+func (m *Model) focusTemplateField(field int) tea.Cmd {
+	m.templateField = field
+	for i := range m.templateInputs {
+		m.templateInputs[i].Blur()
+	}
+	return m.templateInputs[field].Focus()
+}
+
+// handleTemplateEditorKeys handles keys while the template manager is open
+func (m Model) handleTemplateEditorKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if m.templateForm {
+		return m.handleTemplateFormKeys(msg)
+	}
+	templates := m.config.QueryTemplates
+	switch msg.String() {
+	case "esc", "q":
+		m.closeTopPopup()
+	case "up", "k":
+		if m.templateEditIdx > 0 {
+			m.templateEditIdx--
+		}
+	case "down", "j":
+		if m.templateEditIdx < len(templates)-1 {
+			m.templateEditIdx++
+		}
+	case "a":
+		return m, m.openTemplateForm(len(templates)), true
+	case "enter", "e":
+		if m.templateEditIdx < len(templates) {
+			return m, m.openTemplateForm(m.templateEditIdx), true
+		}
+	case "d":
+		if m.templateEditIdx < len(templates) {
+			name := templates[m.templateEditIdx].Name
+			updated := append(append([]config.QueryTemplate{}, templates[:m.templateEditIdx]...), templates[m.templateEditIdx+1:]...)
+			m = m.saveTemplates(updated, "Deleted "+name)
+			m.templateEditIdx = min(m.templateEditIdx, max(len(updated)-1, 0))
+		}
+	case "K", "J":
+		to := m.templateEditIdx - 1
+		if msg.String() == "J" {
+			to = m.templateEditIdx + 1
+		}
+		if to >= 0 && to < len(templates) {
+			updated := append([]config.QueryTemplate{}, templates...)
+			updated[to], updated[m.templateEditIdx] = updated[m.templateEditIdx], updated[to]
+			m = m.saveTemplates(updated, "")
+			m.templateEditIdx = to
+		}
+	}
+	return m, nil, true
+}
+
+// handleTemplateFormKeys edits the name and queries of a template, saving it on Enter
+func (m Model) handleTemplateFormKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc":
+		m.templateForm = false
+		m.templateMsg = ""
+		return m, nil, true
+	case "tab", "down":
+		return m, m.focusTemplateField((m.templateField + 1) % templateFields), true
+	case "shift+tab", "up":
+		return m, m.focusTemplateField((m.templateField + templateFields - 1) % templateFields), true
+	case "enter":
+		return m.saveTemplateForm(), nil, true
+	}
+	var cmd tea.Cmd
+	m.templateInputs[m.templateField], cmd = m.templateInputs[m.templateField].Update(msg)
+	return m, cmd, true
+}
+
+// saveTemplateForm checks the form and saves it over the edited template, or
+// as a new last one
+func (m Model) saveTemplateForm() Model {
+	in := m.templateInputs
+	name := strings.TrimSpace(in[templateName].Value())
+	query := strings.TrimSpace(in[templateQuery].Value())
+	if name == "" || query == "" {
+		m.templateMsg = "A template needs a name and a query"
+		return m
+	}
+	templates := append([]config.QueryTemplate{}, m.config.QueryTemplates...)
+	for i, t := range templates {
+		if i != m.templateEditIdx && strings.EqualFold(t.Name, name) {
+			m.templateMsg = fmt.Sprintf("A template named %q already exists", t.Name)
+			return m
+		}
+	}
+
+	var t config.QueryTemplate
+	if m.templateEditIdx < len(templates) {
+		t = templates[m.templateEditIdx]
+	} else {
+		templates = append(templates, t)
+	}
+	t.Name, t.Query = name, query
+	// Other drivers' dialect queries are kept as they are
+	dialects := map[string]string{}
+	for d, q := range t.Dialects {
+		dialects[d] = q
+	}
+	if dialect := m.templateDialect(); dialect != "" {
+		if q := strings.TrimSpace(in[templateDialect].Value()); q != "" {
+			dialects[dialect] = q
+		} else {
+			delete(dialects, dialect)
+		}
+	}
+	t.Dialects = nil
+	if len(dialects) > 0 {
+		t.Dialects = dialects
+	}
+	templates[m.templateEditIdx] = t
+
+	m = m.saveTemplates(templates, "Saved "+name)
+	m.templateForm = false
+	return m
+}
+
+// saveTemplates replaces the configured templates and persists the config
+func (m Model) saveTemplates(templates []config.QueryTemplate, done string) Model {
+	m.config.QueryTemplates = templates
+	if err := m.config.Save(); err != nil {
+		m.templateMsg = "Failed to save config: " + err.Error()
+		return m
+	}
+	m.templateMsg = done
+	return m
+}
+
+func (m Model) renderTemplateEditor(main string) string {
+	var content strings.Builder
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Query Templates")
+	content.WriteString(title)
+	content.WriteString("\n\n")
+
+	faint := lipgloss.NewStyle().Faint(true)
+	popupWidth := min(72, m.width-10)
+	if m.templateForm {
+		for _, in := range m.templateInputs {
+			content.WriteString(in.View())
+			content.WriteString("\n")
+		}
+		content.WriteString(faint.Render(fmt.Sprintf("<table> is the selected table; leave %s: empty to use Query", m.templateDialect())))
+		content.WriteString("\n")
+	} else {
+		templates := m.config.QueryTemplates
+		// Keep the selection in view when the list is taller than the popup
+		visible := max(m.height-14, 5)
+		start := 0
+		if m.templateEditIdx >= visible {
+			start = m.templateEditIdx - visible + 1
+		}
+		dialect := m.templateDialect()
+		for i := start; i < min(start+visible, len(templates)); i++ {
+			t := templates[i]
+			style := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
+			prefix := "  "
+			if i == m.templateEditIdx {
+				style = lipgloss.NewStyle().Foreground(m.theme.SuccessColor()).Bold(true)
+				prefix = "> "
+			}
+			name := padRight(limitString(t.Name, 20), 20)
+			content.WriteString(prefix + style.Render(name) + " " + faint.Render(limitString(t.For(dialect).Query, popupWidth-28)) + "\n")
+		}
+		if len(templates) == 0 {
+			content.WriteString(faint.Render("No templates; a adds one") + "\n")
+		}
+	}
+
+	if m.templateMsg != "" {
+		content.WriteString("\n")
+		content.WriteString(lipgloss.NewStyle().Foreground(m.theme.WarningColor()).Render(m.templateMsg))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	hint := "a: add • Enter/e: edit • d: delete • K/J: move • Esc: close"
+	if m.templateForm {
+		hint = "Tab: next field • Enter: save • Esc: cancel"
+	}
+	content.WriteString(m.renderHintLine(hint, m.popupInnerWidth(popupWidth)))
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
@@ -12,37 +12,37 @@ type TableSelectedMsg struct {
 }
 
 func (m Model) executeTemplate() (Model, tea.Cmd) {
-	if m.templateIdx < 0 || m.templateIdx >= len(m.config.QueryTemplates) {
+	templates := m.queryTemplates()
+	if m.templateIdx < 0 || m.templateIdx >= len(templates) {
 		return m, nil
 	}
 
-	template := m.config.QueryTemplates[m.templateIdx]
-	query := strings.ReplaceAll(template.Query, "<table>", m.templateTable)
+	template := templates[m.templateIdx]
+	query, _ := expandSnippet(strings.ReplaceAll(template.Query, "<table>", m.templateTable))
 
-	m.showTemplatePopup = false
-	m.templateTable = ""
-	m.templateIdx = 0
+	m.closeTopPopup()
 
-	// Execute the query
-	m.loading = true
-	return m, m.executeQueryCmd(query)
+	// Execute the query, placeholders as written, through the guards and strict mode
+	return m, m.confirmOrRun(query)
 }
 
 func (m Model) insertTemplate() Model {
-	if m.templateIdx < 0 || m.templateIdx >= len(m.config.QueryTemplates) {
+	templates := m.queryTemplates()
+	if m.templateIdx < 0 || m.templateIdx >= len(templates) {
 		return m
 	}
 
-	template := m.config.QueryTemplates[m.templateIdx]
-	query := strings.ReplaceAll(template.Query, "<table>", m.templateTable)
+	template := templates[m.templateIdx]
+	query, stops := expandSnippet(strings.ReplaceAll(template.Query, "<table>", m.templateTable))
 
-	m.showTemplatePopup = false
-	m.templateTable = ""
-	m.templateIdx = 0
+	m.closeTopPopup()
 
 	// Insert query into editor
-	m.editor.SetValue(query)
+	m.setEditorValue(query)
 	m.mode = InsertMode
 	m.editor.Focus()
+	if len(stops) > 0 {
+		m.startSnippet(stops, 0)
+	}
 	return m
 }
@@ -1,14 +1,19 @@
 package ui
 
 import (
+	"fmt"
 	"sort"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/nhath/ezdb/internal/config"
 	"github.com/nhath/ezdb/internal/ui/components/popup"
+	"github.com/nhath/ezdb/internal/ui/styles"
 )
 
+// autoThemeEntry is the first list entry; it follows the terminal background
+const autoThemeEntry = "Auto"
+
 type ThemeSelector struct {
 	visible  bool
 	themes   []string
@@ -24,6 +29,7 @@ func NewThemeSelector(cfg *config.Config) ThemeSelector {
 		names = append(names, name)
 	}
 	sort.Strings(names)
+	names = append([]string{autoThemeEntry}, names...)
 
 	return ThemeSelector{
 		visible:  false,
@@ -42,6 +48,10 @@ func (m ThemeSelector) UpdateTheme(theme config.Theme) ThemeSelector {
 func (m ThemeSelector) Show() ThemeSelector {
 	m.visible = true
 	// Find current theme index
+	m.selected = 0
+	if m.config.ThemeMode == "auto" {
+		return m
+	}
 	for i, name := range m.themes {
 		if name == m.config.ThemeName {
 			m.selected = i
@@ -79,9 +89,13 @@ func (m ThemeSelector) Update(msg tea.Msg) (ThemeSelector, tea.Cmd) {
 		case "enter":
 			m.visible = false
 			themeName := m.themes[m.selected]
+			auto := themeName == autoThemeEntry
+			if auto {
+				themeName = m.config.AutoThemeName(styles.DarkBackground())
+			}
 			theme := config.GetThemes()[themeName]
 			return m, func() tea.Msg {
-				return ThemeSelectedMsg{ThemeName: themeName, Theme: theme}
+				return ThemeSelectedMsg{ThemeName: themeName, Theme: theme, Auto: auto}
 			}
 		case "esc", "q":
 			m.visible = false
@@ -109,9 +123,29 @@ func (m ThemeSelector) View(w, h int) string {
 				Bold(true)
 			prefix = "> "
 		}
-		content += style.Render(prefix+name) + "\n"
+		label := name
+		if name == autoThemeEntry {
+			label = m.autoLabel()
+		} else if config.IsUserTheme(name) {
+			label += " (file)"
+		}
+		content += style.Render(prefix+label) + "\n"
 	}
 
 	m.popup = m.popup.Show("Select Theme", content, "Enter: Select • Esc: Cancel • ?: Help")
 	return m.popup.View()
 }
+
+// autoLabel describes the Auto entry with the theme it currently resolves to
+func (m ThemeSelector) autoLabel() string {
+	bg := "light"
+	dark := styles.DarkBackground()
+	if dark {
+		bg = "dark"
+	}
+	label := fmt.Sprintf("%s (%s terminal: %s)", autoThemeEntry, bg, m.config.AutoThemeName(dark))
+	if m.config.ThemeMode == "auto" {
+		label += " *"
+	}
+	return label
+}
@@ -0,0 +1,52 @@
+// internal/ui/theme_watch.go
+// Polls user theme files so edits apply without restarting.
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/config"
+)
+
+// themePollInterval is how often theme files are checked for changes
+const themePollInterval = 2 * time.Second
+
+// watchThemesCmd waits one interval, then reloads user themes if their files changed
+func watchThemesCmd(stamp string) tea.Cmd {
+	return tea.Tick(themePollInterval, func(time.Time) tea.Msg {
+		current := config.UserThemesStamp()
+		if current == stamp {
+			return themeWatchTickMsg{Stamp: stamp}
+		}
+		return UserThemesChangedMsg{Stamp: current, Err: config.ReloadUserThemes()}
+	})
+}
+
+// handleUserThemesChanged refreshes the selector and re-applies the active theme if it is a user theme
+func (m Model) handleUserThemesChanged(msg UserThemesChangedMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = msg.Err.Error()
+	}
+	if m.themeSelector.config != nil {
+		visible := m.themeSelector.Visible()
+		m.themeSelector = NewThemeSelector(m.config)
+		if visible {
+			m.themeSelector = m.themeSelector.Show()
+		}
+	}
+
+	cmds := []tea.Cmd{watchThemesCmd(msg.Stamp)}
+	if theme, ok := config.GetThemes()[m.config.ThemeName]; ok && config.IsUserTheme(m.config.ThemeName) && theme != m.config.Theme {
+		m.config.Theme = theme
+		m = m.applyTheme()
+		if err := m.config.Save(); err != nil {
+			m.errorMsg = "Failed to save theme: " + err.Error()
+		}
+		m.statusMsg = fmt.Sprintf("Reloaded theme %s", m.config.ThemeName)
+		cmds = append(cmds, tea.ClearScreen)
+	}
+	return m, tea.Batch(cmds...)
+}
@@ -0,0 +1,107 @@
+// internal/ui/timestamps.go
+// History times in the configured layouts and timezone, or the database session's, with relative dates for the past week.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/nhath/ezdb/internal/config"
+	"github.com/nhath/ezdb/internal/db"
+)
+
+// sessionTimezoneCmd asks the database for its session timezone when
+// history times follow it
+func (m Model) sessionTimezoneCmd() tea.Cmd {
+	if m.driver == nil || !strings.EqualFold(m.config.Timestamps.Timezone, config.SessionTimezone) {
+		return nil
+	}
+	driver := m.driver
+	var query string
+	switch driver.Type() {
+	case db.Postgres:
+		query = "SHOW TIMEZONE"
+	case db.MySQL:
+		query = "SELECT IF(@@session.time_zone = 'SYSTEM', @@system_time_zone, @@session.time_zone)"
+	default: // SQLite, Cassandra and BigQuery keep times in UTC
+		return func() tea.Msg { return SessionTimezoneMsg{Location: time.UTC} }
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		result, err := driver.Execute(ctx, query)
+		if err != nil {
+			return SessionTimezoneMsg{Err: err}
+		}
+		if len(result.Rows) == 0 || len(result.Rows[0]) == 0 {
+			return SessionTimezoneMsg{Err: fmt.Errorf("no timezone returned")}
+		}
+		loc, err := parseTimezone(result.Rows[0][0])
+		return SessionTimezoneMsg{Location: loc, Err: err}
+	}
+}
+
+// parseTimezone reads a timezone the database reports: an IANA name or a
+// "+05:30" style offset
+func parseTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc, nil
+	}
+	if offset, err := time.Parse("-07:00", name); err == nil {
+		_, secs := offset.Zone()
+		return time.FixedZone("UTC"+name, secs), nil
+	}
+	return nil, fmt.Errorf("unknown timezone %q", name)
+}
+
+// handleSessionTimezone shows history times in the session timezone once known
+func (m Model) handleSessionTimezone(msg SessionTimezoneMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = "Session timezone: " + msg.Err.Error() + ", showing local times"
+		return m, nil
+	}
+	m.sessionLocation = msg.Location
+	return m.refreshHistoryList(), nil
+}
+
+// timestampLocation is the timezone history times are shown in
+func (m Model) timestampLocation() *time.Location {
+	if m.sessionLocation != nil {
+		return m.sessionLocation
+	}
+	loc, err := m.config.Timestamps.Location()
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// formatTimestamp lays out a history time in the configured timezone
+func (m Model) formatTimestamp(t time.Time) string {
+	return formatTimestamp(t, time.Now(), m.config.Timestamps, m.timestampLocation())
+}
+
+// formatTimestamp lays out t relative to now: times from today in the today
+// layout, yesterday and the past week as "yesterday 14:03" and "Mon 14:03"
+// unless ts is Absolute, and older times in the date layout
+func formatTimestamp(t, now time.Time, ts config.Timestamps, loc *time.Location) string {
+	t, now = t.In(loc), now.In(loc)
+	today, older := ts.Layouts()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	switch {
+	case !t.Before(midnight):
+		return t.Format(today)
+	case ts.Absolute:
+		return t.Format(older)
+	case !t.Before(midnight.AddDate(0, 0, -1)):
+		return "yesterday " + t.Format("15:04")
+	case !t.Before(midnight.AddDate(0, 0, -6)):
+		return t.Format("Mon 15:04")
+	}
+	return t.Format(older)
+}
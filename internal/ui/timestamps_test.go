@@ -0,0 +1,55 @@
+// internal/ui/timestamps_test.go
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nhath/ezdb/internal/config"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no timezone database:", err)
+	}
+	now := time.Date(2024, 3, 14, 9, 30, 0, 0, time.UTC) // A Thursday, 10:30 in Berlin
+	tests := []struct {
+		name string
+		at   time.Time
+		ts   config.Timestamps
+		loc  *time.Location
+		want string
+	}{
+		{"today", now.Add(-time.Hour), config.Timestamps{}, time.UTC, "08:30:00"},
+		{"today in berlin", now.Add(-time.Hour), config.Timestamps{}, berlin, "09:30:00"},
+		{"yesterday", now.Add(-20 * time.Hour), config.Timestamps{}, time.UTC, "yesterday 13:30"},
+		{"midnight moves with the timezone", now.Add(-10 * time.Hour), config.Timestamps{}, berlin, "00:30:00"},
+		{"this week", now.AddDate(0, 0, -4), config.Timestamps{}, time.UTC, "Sun 09:30"},
+		{"older", now.AddDate(0, 0, -10), config.Timestamps{}, time.UTC, "2024-03-04 09:30"},
+		{"absolute", now.Add(-20 * time.Hour), config.Timestamps{Absolute: true}, time.UTC, "2024-03-13 13:30"},
+		{"custom layouts", now.AddDate(0, -1, 0), config.Timestamps{Format: "3:04pm", DateFormat: "Jan 2"}, time.UTC, "Feb 14"},
+		{"custom today", now, config.Timestamps{Format: "3:04pm"}, time.UTC, "9:30am"},
+	}
+	for _, tt := range tests {
+		if got := formatTimestamp(tt.at, now, tt.ts, tt.loc); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseTimezone(t *testing.T) {
+	loc, err := parseTimezone("+05:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, offset := time.Date(2024, 1, 1, 0, 0, 0, 0, loc).Zone(); offset != 5*3600+30*60 {
+		t.Errorf("+05:30 has offset %d", offset)
+	}
+	if loc, err := parseTimezone("UTC"); err != nil || loc != time.UTC {
+		t.Errorf("UTC parsed as %v, %v", loc, err)
+	}
+	if _, err := parseTimezone("Nowhere/Special"); err == nil {
+		t.Error("unknown timezone parsed")
+	}
+}
@@ -0,0 +1,335 @@
+// internal/ui/transcript.go
+// Session transcripts: render picked history entries as Markdown or HTML for incident docs.
+package ui
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+
+	"github.com/nhath/ezdb/internal/history"
+)
+
+// redacted replaces values hidden from a transcript
+const redacted = "***"
+
+var (
+	// stringLiteral matches a single-quoted SQL string, doubled quotes included
+	stringLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+	// numberLiteral matches a number that is not part of an identifier
+	numberLiteral = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+)
+
+// transcriptOptions controls what a transcript shows
+type transcriptOptions struct {
+	HTML           bool
+	RedactLiterals bool           // Hide string and number literals in queries and errors
+	RedactResults  bool           // Hide the values of result previews, keeping column names
+	Location       *time.Location // Timezone of the times shown, nil to keep them as stored
+}
+
+// redactLiterals hides the string and number literals of a statement or message
+func redactLiterals(s string) string {
+	s = stringLiteral.ReplaceAllString(s, "'"+redacted+"'")
+	return numberLiteral.ReplaceAllStringFunc(s, func(n string) string { return redacted })
+}
+
+// previewTable splits a tabular preview into its columns and rows; ok is
+// false for previews that are a plain message
+func previewTable(preview string) (columns []string, rows [][]string, ok bool) {
+	lines := strings.Split(preview, "\n")
+	if len(lines) < 2 || !strings.Contains(lines[0], " | ") {
+		return nil, nil, false
+	}
+	split := func(line string) []string {
+		cells := strings.Split(line, " | ")
+		for i, c := range cells {
+			cells[i] = strings.TrimSpace(c)
+		}
+		return cells
+	}
+	columns = split(lines[0])
+	for _, line := range lines[1:] {
+		if line = strings.TrimSpace(line); line != "" && line != "..." {
+			rows = append(rows, split(line))
+		}
+	}
+	return columns, rows, true
+}
+
+// renderTranscript renders entries, oldest first, as a Markdown or HTML document
+func renderTranscript(entries []history.HistoryEntry, profile string, opts transcriptOptions) string {
+	entries = append([]history.HistoryEntry(nil), entries...)
+	if opts.Location != nil {
+		for i := range entries {
+			entries[i].ExecutedAt = entries[i].ExecutedAt.In(opts.Location)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].ExecutedAt.Before(entries[j].ExecutedAt) })
+
+	var b strings.Builder
+	title := "ezdb session transcript"
+	summary := fmt.Sprintf("Profile %s, %d queries", profile, len(entries))
+	if len(entries) > 0 {
+		summary += fmt.Sprintf(", %s to %s", entries[0].ExecutedAt.Format("2006-01-02 15:04:05"),
+			entries[len(entries)-1].ExecutedAt.Format("2006-01-02 15:04:05"))
+	}
+	if opts.HTML {
+		fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>%s</title></head>\n<body>\n", title)
+		fmt.Fprintf(&b, "<h1>%s</h1>\n<p>%s</p>\n", title, html.EscapeString(summary))
+	} else {
+		fmt.Fprintf(&b, "# %s\n\n%s\n", title, summary)
+	}
+
+	for i, e := range entries {
+		query, errMsg := e.Query, e.ErrorMessage
+		if opts.RedactLiterals {
+			query, errMsg = redactLiterals(query), redactLiterals(errMsg)
+		}
+		heading := fmt.Sprintf("%d. %s, %dms", i+1, e.ExecutedAt.Format("15:04:05"), e.DurationMs)
+		if e.Status == "error" {
+			heading += ", failed"
+		} else {
+			heading += fmt.Sprintf(", %d rows", e.RowCount)
+		}
+		preview := e.Preview
+		columns, rows, tabular := previewTable(preview)
+		if opts.RedactResults {
+			for _, row := range rows {
+				for j := range row {
+					row[j] = redacted
+				}
+			}
+			// A one-column result has no separator to tell its header from
+			// its values, so the whole preview is hidden
+			if !tabular && preview != "" {
+				preview = redacted
+			}
+		}
+
+		if opts.HTML {
+			fmt.Fprintf(&b, "<h2>%s</h2>\n<pre><code>%s</code></pre>\n", html.EscapeString(heading), html.EscapeString(query))
+			if errMsg != "" {
+				fmt.Fprintf(&b, "<p><strong>Error:</strong> %s</p>\n", html.EscapeString(errMsg))
+			}
+			if tabular {
+				b.WriteString("<table>\n<tr>")
+				for _, c := range columns {
+					fmt.Fprintf(&b, "<th>%s</th>", html.EscapeString(c))
+				}
+				b.WriteString("</tr>\n")
+				for _, row := range rows {
+					b.WriteString("<tr>")
+					for _, c := range row {
+						fmt.Fprintf(&b, "<td>%s</td>", html.EscapeString(c))
+					}
+					b.WriteString("</tr>\n")
+				}
+				b.WriteString("</table>\n")
+			} else if preview != "" && e.Status != "error" {
+				fmt.Fprintf(&b, "<p>%s</p>\n", html.EscapeString(preview))
+			}
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n## %s\n\n```sql\n%s\n```\n", heading, strings.TrimSpace(query))
+		if errMsg != "" {
+			fmt.Fprintf(&b, "\n> Error: %s\n", strings.ReplaceAll(errMsg, "\n", "\n> "))
+		}
+		if tabular {
+			cell := func(s string) string { return strings.ReplaceAll(s, "|", `\|`) }
+			b.WriteString("\n|")
+			for _, c := range columns {
+				b.WriteString(" " + cell(c) + " |")
+			}
+			b.WriteString("\n|" + strings.Repeat(" --- |", len(columns)) + "\n")
+			for _, row := range rows {
+				b.WriteString("|")
+				for _, c := range row {
+					b.WriteString(" " + cell(c) + " |")
+				}
+				b.WriteString("\n")
+			}
+		} else if preview != "" && e.Status != "error" {
+			fmt.Fprintf(&b, "\n%s\n", preview)
+		}
+	}
+
+	if opts.HTML {
+		b.WriteString("</body>\n</html>\n")
+	}
+	return b.String()
+}
+
+// openTranscriptPopup opens the transcript picker with the selected history entry picked.
+func (m *Model) openTranscriptPopup() {
+	if m.showTranscript {
+		return
+	}
+	m.showTranscript = true
+	m.autocompleting = false
+	m.transcriptIdx = min(max(m.selected, 0), max(len(m.history)-1, 0))
+	m.transcriptPicked = map[int64]bool{}
+	if m.transcriptIdx < len(m.history) {
+		m.transcriptPicked[m.history[m.transcriptIdx].ID] = true
+	}
+	m.popupStack.Push("transcript", func(m *Model) {
+		m.showTranscript = false
+	})
+}
+
+// transcriptEntries returns the picked history entries
+func (m Model) transcriptEntries() []history.HistoryEntry {
+	var entries []history.HistoryEntry
+	for _, e := range m.history {
+		if m.transcriptPicked[e.ID] && e.Status != "info" {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// handleTranscriptKeys picks entries and options and copies or saves the transcript
+func (m Model) handleTranscriptKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc", "q":
+		m.closeTopPopup()
+	case "up", "k":
+		if m.transcriptIdx > 0 {
+			m.transcriptIdx--
+		}
+	case "down", "j":
+		if m.transcriptIdx < len(m.history)-1 {
+			m.transcriptIdx++
+		}
+	case " ", "space":
+		if m.transcriptIdx < len(m.history) {
+			id := m.history[m.transcriptIdx].ID
+			m.transcriptPicked[id] = !m.transcriptPicked[id]
+		}
+	case "a":
+		// Pick everything unless everything is already picked
+		all := false
+		for _, e := range m.history {
+			all = all || !m.transcriptPicked[e.ID]
+		}
+		for _, e := range m.history {
+			m.transcriptPicked[e.ID] = all
+		}
+	case "f":
+		m.transcriptOpts.HTML = !m.transcriptOpts.HTML
+	case "l":
+		m.transcriptOpts.RedactLiterals = !m.transcriptOpts.RedactLiterals
+	case "v":
+		m.transcriptOpts.RedactResults = !m.transcriptOpts.RedactResults
+	case "y", "enter", "w":
+		entries := m.transcriptEntries()
+		if len(entries) == 0 {
+			m.errorMsg = "Pick at least one query with space"
+			return m, nil, true
+		}
+		profile := ""
+		if m.profile != nil {
+			profile = m.profile.Name
+		}
+		opts := m.transcriptOpts
+		opts.Location = m.timestampLocation()
+		doc := renderTranscript(entries, profile, opts)
+		m.closeTopPopup()
+		if msg.String() == "w" {
+			return m, saveTranscriptCmd(doc, m.transcriptOpts.HTML), true
+		}
+		return m, m.copyToClipboardCmd(doc), true
+	}
+	return m, nil, true
+}
+
+// saveTranscriptCmd writes a transcript to a timestamped file in the working directory
+func saveTranscriptCmd(doc string, asHTML bool) tea.Cmd {
+	return func() tea.Msg {
+		ext := ".md"
+		if asHTML {
+			ext = ".html"
+		}
+		path, err := filepath.Abs("ezdb-transcript-" + time.Now().Format("20060102-150405") + ext)
+		if err == nil {
+			err = os.WriteFile(path, []byte(doc), 0o644)
+		}
+		return TranscriptSavedMsg{Path: path, Err: err}
+	}
+}
+
+// handleTranscriptSaved reports where a transcript was saved
+func (m Model) handleTranscriptSaved(msg TranscriptSavedMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.errorMsg = fmt.Sprintf("Saving transcript failed: %v", msg.Err)
+	} else {
+		m.statusMsg = "Saved transcript to " + msg.Path
+	}
+	return m, nil
+}
+
+func (m Model) renderTranscriptPopup(main string) string {
+	var content strings.Builder
+	faint := lipgloss.NewStyle().Faint(true)
+	popupWidth := min(100, m.width-10)
+	visible := max(m.height-16, 5)
+
+	content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.theme.AccentColor()).Render("Session Transcript"))
+	content.WriteString("\n\n")
+	if len(m.history) == 0 {
+		content.WriteString(faint.Render("  (no history yet)"))
+		content.WriteString("\n")
+	}
+	start := 0
+	if m.transcriptIdx >= visible {
+		start = m.transcriptIdx - visible + 1
+	}
+	for i := start; i < len(m.history) && i < start+visible; i++ {
+		e := m.history[i]
+		style := lipgloss.NewStyle().Foreground(m.theme.TextSecondary())
+		prefix := "  "
+		if i == m.transcriptIdx {
+			style = lipgloss.NewStyle().Foreground(m.theme.TextPrimary()).Bold(true)
+			prefix = "> "
+		}
+		box := "[ ] "
+		if m.transcriptPicked[e.ID] {
+			box = "[x] "
+		}
+		query := limitString(strings.Join(strings.Fields(e.Query), " "), popupWidth-24)
+		content.WriteString(prefix + style.Render(box+m.formatTimestamp(e.ExecutedAt)+"  "+query) + "\n")
+	}
+
+	content.WriteString("\n")
+	format := "Markdown"
+	if m.transcriptOpts.HTML {
+		format = "HTML"
+	}
+	onOff := func(on bool) string {
+		if on {
+			return "on"
+		}
+		return "off"
+	}
+	content.WriteString(fmt.Sprintf("%d picked • format: %s • redact literals: %s • redact results: %s\n",
+		len(m.transcriptEntries()), format, onOff(m.transcriptOpts.RedactLiterals), onOff(m.transcriptOpts.RedactResults)))
+	content.WriteString(m.renderHintLine("Space: pick • a: all • f: format • l/v: redact literals/results • y: copy • w: save • Esc: close", m.popupInnerWidth(popupWidth)))
+
+	popupBox := m.theme.PopupStyle.
+		Width(popupWidth).
+		MaxHeight(m.height - 4).
+		Background(m.theme.PopupBg()).
+		Render(content.String())
+
+	return overlay.Composite(popupBox, main, overlay.Center, overlay.Center, 0, 0)
+}
@@ -0,0 +1,64 @@
+// internal/ui/transcript_test.go
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nhath/ezdb/internal/history"
+)
+
+func TestRedactLiterals(t *testing.T) {
+	got := redactLiterals("SELECT * FROM t2 WHERE email = 'a''b@c.io' AND age > 42.5")
+	want := "SELECT * FROM t2 WHERE email = '***' AND age > ***"
+	if got != want {
+		t.Errorf("redactLiterals = %q, want %q", got, want)
+	}
+}
+
+func TestRenderTranscript(t *testing.T) {
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	entries := []history.HistoryEntry{
+		{Query: "SELECT nope", ExecutedAt: at.Add(time.Minute), Status: "error", ErrorMessage: "column \"nope\" does not exist"},
+		{Query: "SELECT id, email FROM users WHERE id = 7", ExecutedAt: at, DurationMs: 3, RowCount: 1,
+			Status: "success", Preview: "id | email\n7 | a|b@c.io"},
+	}
+
+	md := renderTranscript(entries, "prod", transcriptOptions{RedactLiterals: true})
+	for _, want := range []string{
+		"Profile prod, 2 queries, 2024-03-01 10:00:00 to 2024-03-01 10:01:00",
+		"## 1. 10:00:00, 3ms, 1 rows\n\n```sql\nSELECT id, email FROM users WHERE id = ***\n```",
+		"| id | email |\n| --- | --- |\n| 7 | a\\|b@c.io |",
+		"## 2. 10:01:00, 0ms, failed",
+		"> Error: column \"nope\" does not exist",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown transcript lacks %q:\n%s", want, md)
+		}
+	}
+
+	page := renderTranscript(entries, "prod", transcriptOptions{HTML: true, RedactResults: true})
+	for _, want := range []string{
+		"<th>id</th><th>email</th>",
+		"<td>***</td><td>***</td>",
+		"<p><strong>Error:</strong> column &#34;nope&#34; does not exist</p>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("HTML transcript lacks %q:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, "b@c.io") {
+		t.Error("HTML transcript shows a redacted result value")
+	}
+}
+
+func TestRenderTranscriptRedactsSingleColumn(t *testing.T) {
+	entries := []history.HistoryEntry{{Query: "SELECT email FROM users", RowCount: 1,
+		Status: "success", Preview: "email\nalice@example.com\n"}}
+	for _, opts := range []transcriptOptions{{RedactResults: true}, {HTML: true, RedactResults: true}} {
+		if doc := renderTranscript(entries, "prod", opts); strings.Contains(doc, "alice@example.com") || !strings.Contains(doc, redacted) {
+			t.Errorf("transcript (HTML %v) does not redact a one-column result:\n%s", opts.HTML, doc)
+		}
+	}
+}
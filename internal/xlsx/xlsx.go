@@ -0,0 +1,198 @@
+// internal/xlsx/xlsx.go
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MaxRows is the most rows a worksheet holds, header included
+const MaxRows = 1048576
+
+// ErrTooManyRows is returned when a sheet outgrows MaxRows
+var ErrTooManyRows = errors.New("xlsx: a sheet holds at most 1048576 rows")
+
+// number matches values written as numeric cells; longer integers and
+// leading zeros would lose digits in Excel, so they stay text
+var number = regexp.MustCompile(`^-?(0|[1-9][0-9]{0,14})(\.[0-9]+)?$`)
+
+// sheetName drops the characters Excel forbids in sheet names
+var sheetName = strings.NewReplacer("[", "", "]", "", ":", "", "*", "", "?", "", "/", "", "\\", "")
+
+// Writer streams worksheets into an .xlsx workbook. Rows go to the sheet
+// last added; Close writes the workbook parts.
+type Writer struct {
+	zw     *zip.Writer
+	sheet  *bufio.Writer
+	names  []string
+	rows   int
+	closed bool
+}
+
+// NewWriter returns a Writer of a workbook written to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{zw: zip.NewWriter(w)}
+}
+
+// AddSheet starts a worksheet whose first row is a bold header
+func (w *Writer) AddSheet(name string, header []string) error {
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+	w.names = append(w.names, w.uniqueName(name))
+	f, err := w.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.names)))
+	if err != nil {
+		return err
+	}
+	w.sheet = bufio.NewWriter(f)
+	w.rows = 0
+	w.sheet.WriteString(xml.Header)
+	w.sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return w.writeRow(header, true)
+}
+
+// WriteRow appends a row to the current sheet; "NULL" cells stay empty
+func (w *Writer) WriteRow(row []string) error {
+	if w.sheet == nil {
+		return errors.New("xlsx: WriteRow before AddSheet")
+	}
+	return w.writeRow(row, false)
+}
+
+func (w *Writer) writeRow(row []string, header bool) error {
+	if w.rows == MaxRows {
+		return ErrTooManyRows
+	}
+	w.rows++
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.rows)
+	for i, v := range row {
+		ref := column(i) + strconv.Itoa(w.rows)
+		switch {
+		case header:
+			fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr" s="1"><is><t xml:space="preserve">`, ref)
+			xml.EscapeText(w.sheet, []byte(v))
+			w.sheet.WriteString(`</t></is></c>`)
+		case v == "NULL" || v == "":
+		case number.MatchString(v):
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, ref, v)
+		default:
+			fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			xml.EscapeText(w.sheet, []byte(v))
+			w.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+// endSheet closes the current worksheet, if any
+func (w *Writer) endSheet() error {
+	if w.sheet == nil {
+		return nil
+	}
+	w.sheet.WriteString(`</sheetData></worksheet>`)
+	err := w.sheet.Flush()
+	w.sheet = nil
+	return err
+}
+
+// uniqueName cleans a sheet name, keeping it within Excel's 31 characters
+// and distinct from the sheets before it
+func (w *Writer) uniqueName(name string) string {
+	name = strings.TrimSpace(sheetName.Replace(name))
+	if name == "" {
+		name = "Sheet"
+	}
+	base := []rune(name)
+	for n := 1; ; n++ {
+		candidate := string(base[:min(len(base), 31)])
+		if n > 1 {
+			suffix := fmt.Sprintf(" (%d)", n)
+			candidate = string(base[:min(len(base), 31-len(suffix))]) + suffix
+		}
+		taken := false
+		for _, existing := range w.names {
+			taken = taken || strings.EqualFold(existing, candidate)
+		}
+		if !taken {
+			return candidate
+		}
+	}
+}
+
+// Close finishes the last sheet and writes the workbook around the sheets
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if len(w.names) == 0 {
+		if err := w.AddSheet("Sheet1", nil); err != nil {
+			return err
+		}
+	}
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+
+	var types, sheets, rels strings.Builder
+	for i, name := range w.names {
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		sheets.WriteString(`<sheet name="`)
+		xml.EscapeText(&sheets, []byte(name))
+		fmt.Fprintf(&sheets, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(w.names)+1)
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			types.String() + `</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` +
+			sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			rels.String() + `</Relationships>`},
+		{"xl/styles.xml", `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+			`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+			`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+			`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+			`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+			`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+			`</styleSheet>`},
+	}
+	for _, p := range parts {
+		f, err := w.zw.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, xml.Header+p.body); err != nil {
+			return err
+		}
+	}
+	return w.zw.Close()
+}
+
+// column returns the letters of the zero-based column i, e.g. 27 is AB
+func column(i int) string {
+	var s []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		s = append([]byte{byte('A' + (i-1)%26)}, s...)
+	}
+	return string(s)
+}
@@ -0,0 +1,73 @@
+// internal/xlsx/xlsx_test.go
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriterWorkbook(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.AddSheet("users", []string{"id", "name"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range [][]string{{"1", "Ann & Bo"}, {"007", "NULL"}} {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.AddSheet("users", []string{"n"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(b)
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A2"><v>1</v></c>`,
+		`<c r="B2" t="inlineStr"><is><t xml:space="preserve">Ann &amp; Bo</t></is></c>`,
+		`<c r="A3" t="inlineStr"><is><t xml:space="preserve">007</t></is></c>`,
+		`<c r="B1" t="inlineStr" s="1">`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet1 is missing %s:\n%s", want, sheet)
+		}
+	}
+	if strings.Contains(sheet, `r="B3"`) {
+		t.Errorf("NULL should be an empty cell:\n%s", sheet)
+	}
+	if _, ok := parts["xl/worksheets/sheet2.xml"]; !ok {
+		t.Fatal("second sheet missing")
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="users (2)"`) {
+		t.Errorf("duplicate sheet name not made unique:\n%s", parts["xl/workbook.xml"])
+	}
+}
+
+func TestColumn(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := column(i); got != want {
+			t.Errorf("column(%d) = %q, want %q", i, got, want)
+		}
+	}
+}